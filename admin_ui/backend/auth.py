@@ -1,6 +1,6 @@
 import os
 import json
-from datetime import datetime, timedelta
+from datetime import datetime, timedelta, timezone
 from typing import Optional
 from fastapi import APIRouter, Depends, HTTPException, status
 from fastapi.security import OAuth2PasswordBearer, OAuth2PasswordRequestForm
@@ -59,6 +59,28 @@ def get_password_hash(password):
 def verify_password(plain_password, hashed_password):
     return pwd_context.verify(plain_password, hashed_password)
 
+def verify_login_password(user_dict, plain_password, now=None):
+    """Check a login password against the user's hash, or against the hash that
+    `agent users passwd --grace` keeps in previous_password_hash until
+    previous_password_expires (RFC 3339, UTC). Without --grace no previous hash
+    is written, so only the current password logs in."""
+    current = user_dict.get("hashed_password")
+    if current and verify_password(plain_password, current):
+        return True
+    previous = user_dict.get("previous_password_hash")
+    expires = user_dict.get("previous_password_expires")
+    if not previous or not expires:
+        return False
+    try:
+        expires_at = datetime.fromisoformat(expires.replace("Z", "+00:00"))
+    except ValueError:
+        return False
+    if expires_at.tzinfo is None:
+        expires_at = expires_at.replace(tzinfo=timezone.utc)
+    if expires_at <= (now or datetime.now(timezone.utc)):
+        return False
+    return verify_password(plain_password, previous)
+
 def load_users():
     if not os.path.exists(USERS_PATH):
         # Create default admin user with must_change_password flag
@@ -125,7 +147,9 @@ async def get_current_user(token: str = Depends(oauth2_scheme)):
 @router.post("/login", response_model=Token)
 async def login_for_access_token(form_data: OAuth2PasswordRequestForm = Depends()):
     user = get_user(form_data.username)
-    if not user or not verify_password(form_data.password, user.hashed_password):
+    users = load_users()
+    user_dict = users.get(user.username, {}) if user else {}
+    if not user or not verify_login_password(user_dict, form_data.password):
         raise HTTPException(
             status_code=status.HTTP_401_UNAUTHORIZED,
             detail="Incorrect username or password",
@@ -133,8 +157,6 @@ async def login_for_access_token(form_data: OAuth2PasswordRequestForm = Depends(
         )
     
     # Check if user needs to change password
-    users = load_users()
-    user_dict = users.get(user.username, {})
     must_change = user_dict.get("must_change_password", False)
     
     access_token_expires = timedelta(minutes=ACCESS_TOKEN_EXPIRE_MINUTES)
@@ -160,6 +182,8 @@ async def change_password(
     # Update password and clear must_change_password flag
     users[current_user.username]["hashed_password"] = get_password_hash(request.new_password)
     users[current_user.username]["must_change_password"] = False
+    users[current_user.username].pop("previous_password_hash", None)
+    users[current_user.username].pop("previous_password_expires", None)
     save_users(users)
     
     return {"status": "success", "message": "Password updated successfully"}
@@ -0,0 +1,42 @@
+import sys
+from datetime import datetime, timezone
+from pathlib import Path
+
+
+BACKEND_ROOT = Path(__file__).resolve().parents[1]
+sys.path.insert(0, str(BACKEND_ROOT))
+
+import auth  # noqa: E402
+
+
+NOW = datetime(2026, 10, 1, 12, 0, tzinfo=timezone.utc)
+
+
+def _user(expires):
+    # The layout `agent users passwd --grace 24h` writes.
+    return {
+        "username": "admin",
+        "hashed_password": auth.get_password_hash("new-password"),
+        "previous_password_hash": auth.get_password_hash("old-password"),
+        "previous_password_expires": expires,
+    }
+
+
+def test_current_password_accepted() -> None:
+    assert auth.verify_login_password(_user("2026-10-02T12:00:00Z"), "new-password", now=NOW)
+
+
+def test_previous_password_accepted_until_expiry() -> None:
+    assert auth.verify_login_password(_user("2026-10-02T12:00:00Z"), "old-password", now=NOW)
+
+
+def test_previous_password_rejected_after_expiry() -> None:
+    assert not auth.verify_login_password(_user("2026-10-01T11:59:59Z"), "old-password", now=NOW)
+
+
+def test_previous_password_rejected_with_bad_expiry() -> None:
+    assert not auth.verify_login_password(_user("tomorrow"), "old-password", now=NOW)
+
+
+def test_wrong_password_rejected() -> None:
+    assert not auth.verify_login_password(_user("2026-10-02T12:00:00Z"), "guess", now=NOW)
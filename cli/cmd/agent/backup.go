@@ -0,0 +1,583 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/backup"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/backup/remote"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/environment"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/health"
+	"github.com/spf13/cobra"
+)
+
+var (
+	backupKeep              int
+	backupPushDir           string
+	backupListJSON          bool
+	backupCreateIncremental bool
+	backupCreateFull        bool
+)
+
+var backupCmd = &cobra.Command{
+	Use:    "backup",
+	Short:  "Manage operator config backups",
+	Hidden: true, // advanced tool; `agent update` and `agent check --fix` create backups automatically
+	Long: `Manage the operator config backups created by agent update (.agent/update-backups/).
+
+Subcommands:
+  create    Snapshot the operator config now
+  schedule  Run create hourly, daily or weekly (systemd user timer or cron)
+  list      Show update and check --fix backup sets
+  prune     Delete old update-backup directories, keeping the newest N
+  verify    Check manifests and validate every file without restoring anything
+  push      Upload a backup to S3-compatible storage
+  pull      Download a backup from S3-compatible storage
+
+Remote storage is configured in .env:
+  AWS_ENDPOINT, AWS_BUCKET, AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY (AWS_REGION optional)`,
+}
+
+var backupCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Snapshot the operator config into a new update-backup set",
+	Long: `Copy .env, config/ai-agent.yaml, config/ai-agent.local.yaml, config/users.json and
+config/contexts/ into a new set under .agent/update-backups/ (with a checksum manifest,
+encrypted when AGENT_BACKUP_ENCRYPT_KEY is set), then prune old sets as agent update does
+(AGENT_BACKUP_KEEP, default 10). This is what agent backup schedule runs.
+
+With --incremental (or AGENT_BACKUP_INCREMENTAL=true in .env) only the files whose SHA-256
+differs from the most recent set are copied, and delta-manifest.json lists the added,
+modified and unchanged files. Restores, agent rollback, agent backup verify and agent config
+diff rebuild such a set from the nearest full set and the deltas after it; agent backup push
+uploads it rebuilt. Pruning keeps the sets a kept delta is built on. A full set is taken
+instead when there is no earlier set, after 10 deltas in a row, or when backups are encrypted.
+--full forces a full set.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if backupCreateIncremental && backupCreateFull {
+			return errors.New("--incremental and --full are mutually exclusive")
+		}
+		repoRoot, err := resolveRepoRootForFix()
+		if err != nil {
+			return err
+		}
+		if err := os.Chdir(repoRoot); err != nil {
+			return fmt.Errorf("failed to switch to repo root: %w", err)
+		}
+		ctx := &updateContext{repoRoot: repoRoot}
+
+		incremental := backupCreateIncremental
+		if !backupCreateFull && !incremental {
+			switch strings.ToLower(backupEnvValue(repoRoot, backup.IncrementalEnv)) {
+			case "1", "true", "yes", "on":
+				incremental = true
+			}
+		}
+		if incremental {
+			baseline, reason := incrementalBaseline(repoRoot)
+			if baseline != "" {
+				copied, err := createIncrementalUpdateBackup(ctx, baseline)
+				if err != nil {
+					return err
+				}
+				fmt.Printf("Created %s (incremental on %s, %d changed file(s) copied)\n", ctx.backupDir, filepath.Base(baseline), len(copied))
+				pruneUpdateBackupsAfterUpdate(ctx)
+				return nil
+			}
+			fmt.Printf("Taking a full backup: %s\n", reason)
+		}
+
+		if err := createUpdateBackups(ctx); err != nil {
+			return err
+		}
+		fmt.Printf("Created %s\n", ctx.backupDir)
+		pruneUpdateBackupsAfterUpdate(ctx)
+		return nil
+	},
+}
+
+// incrementalBaseline returns the set an incremental backup should be taken against, or ""
+// and the reason a full backup is needed instead.
+func incrementalBaseline(repoRoot string) (string, string) {
+	if backupEnvValue(repoRoot, backup.EncryptKeyEnv) != "" {
+		return "", "incremental backups are not supported with " + backup.EncryptKeyEnv
+	}
+	latest, err := backup.LatestBackupDir(updateBackupRoot(repoRoot))
+	if err != nil {
+		return "", err.Error()
+	}
+	if latest == "" {
+		return "", "no earlier backup to compare against"
+	}
+	if backup.IsEncrypted(latest) {
+		return "", "the latest backup is encrypted"
+	}
+	n, err := backup.ChainLength(latest)
+	if err != nil {
+		return "", err.Error()
+	}
+	if n >= backup.MaxDeltaChain {
+		return "", fmt.Sprintf("%d incremental backups since the last full one", n)
+	}
+	return latest, ""
+}
+
+// createIncrementalUpdateBackup writes a new update-backup set holding only the operator
+// files that changed since baseline. The repo root must be the working directory.
+func createIncrementalUpdateBackup(ctx *updateContext, baseline string) ([]string, error) {
+	backupDir, err := newUpdateBackupDir(ctx.repoRoot)
+	if err != nil {
+		return nil, err
+	}
+	if filepath.Clean(backupDir) == filepath.Clean(baseline) {
+		return nil, fmt.Errorf("backup %s already exists; try again in a second", backupDir)
+	}
+	ctx.backupDir = backupDir
+	copied, err := backup.IncrementalBackup(".", baseline, backupDir, updateBackupPaths()...)
+	if err != nil {
+		_ = os.RemoveAll(backupDir)
+		return nil, err
+	}
+	if err := backup.WriteManifest(backupDir); err != nil {
+		return nil, fmt.Errorf("failed to write backup manifest: %w", err)
+	}
+	return copied, nil
+}
+
+var backupListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List backup sets with timestamps, sizes and file counts",
+	Long: `List the backup sets in .agent/update-backups/ (created by agent update) and
+.agent/check-fix-backups/ (pre-fix snapshots created by agent check --fix), newest first.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		repoRoot, err := resolveRepoRootForFix()
+		if err != nil {
+			return err
+		}
+		var all []backup.BackupSetInfo
+		roots := []string{updateBackupRoot(repoRoot), checkFixBackupRoot(repoRoot)}
+		for _, root := range roots {
+			sets, err := backup.ListBackupSets(root)
+			if err != nil {
+				return err
+			}
+			all = append(all, sets...)
+		}
+
+		if backupListJSON {
+			if all == nil {
+				all = []backup.BackupSetInfo{}
+			}
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(all)
+		}
+
+		for i, root := range roots {
+			if i > 0 {
+				fmt.Println("")
+			}
+			kind := filepath.Base(root)
+			fmt.Printf("%s (%s)\n", kind, root)
+			tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(tw, "NAME\tCREATED (UTC)\tFILES\tSIZE\tMANIFEST\tBASE")
+			n := 0
+			for _, s := range all {
+				if s.Kind != kind {
+					continue
+				}
+				n++
+				manifest := "no"
+				if s.HasManifest {
+					manifest = "yes"
+				}
+				base := "-" // full set
+				if s.Base != "" {
+					base = s.Base
+				}
+				fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%s\t%s\n",
+					filepath.Base(s.Path), s.CreatedAt.UTC().Format("2006-01-02 15:04:05"),
+					s.FileCount, backup.HumanBytes(s.TotalBytes), manifest, base)
+			}
+			if n == 0 {
+				fmt.Println("  (none)")
+				continue
+			}
+			_ = tw.Flush()
+		}
+		return nil
+	},
+}
+
+var backupPruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Delete old update-backup directories",
+	Long: `Delete old update-backup directories, keeping the newest N. Sets are ordered by the
+timestamp in their name (YYYYMMDD_HHMMSS); a directory not named after one is ordered by its
+modification time.
+
+When --keep is not given, AGENT_BACKUP_KEEP from the environment or .env is used
+(default 10).`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		repoRoot, err := resolveRepoRootForFix()
+		if err != nil {
+			return err
+		}
+		keep := backupKeep
+		if !cmd.Flags().Changed("keep") {
+			keep = backupKeepFromEnv(repoRoot)
+		}
+		root := updateBackupRoot(repoRoot)
+		removed, err := backup.PruneUpdateBackups(root, keep)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Pruned %d backup(s) from %s (keep=%d)\n", removed, root, keep)
+		objects, err := pruneContentStore(repoRoot)
+		if err != nil {
+			return err
+		}
+		if objects > 0 {
+			fmt.Printf("Removed %d unreferenced object(s) from %s\n", objects, backup.ContentStoreDir)
+		}
+		return nil
+	},
+}
+
+var backupPushCmd = &cobra.Command{
+	Use:   "push",
+	Short: "Upload a backup to S3-compatible storage",
+	Long: `Compress a backup directory and upload it to S3-compatible storage.
+
+Defaults to the most recent directory in .agent/update-backups/. The object key is
+<directory-name>.tar.gz, under envs/<name>/ with --env NAME. The set must have a manifest (` + backup.ManifestName + `), which
+agent backup pull verifies.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		repoRoot, err := resolveRepoRootForFix()
+		if err != nil {
+			return err
+		}
+		dir := strings.TrimSpace(backupPushDir)
+		if dir == "" {
+			dir, err = backup.LatestBackupDir(updateBackupRoot(repoRoot))
+			if err != nil {
+				return err
+			}
+			if dir == "" {
+				return errors.New("no update backup directories found (use --dir to choose one)")
+			}
+		}
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			return fmt.Errorf("backup directory not found: %s", dir)
+		}
+		store, err := newRemoteStoreFromEnv(repoRoot)
+		if err != nil {
+			return err
+		}
+		key := filepath.Base(filepath.Clean(dir)) + ".tar.gz"
+		// Incremental sets are uploaded in full, so each object restores on its own.
+		src, cleanup, err := materializeBackupDir(dir)
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+		// agent backup pull rejects sets without a manifest, so don't upload one.
+		if _, err := os.Stat(filepath.Join(src, backup.ManifestName)); err != nil {
+			return fmt.Errorf("%s has no %s; add one with agent backup verify --fix-manifest before pushing", dir, backup.ManifestName)
+		}
+		if err := store.Push(src, key); err != nil {
+			return err
+		}
+		fmt.Printf("Pushed %s -> %s\n", dir, key)
+		return nil
+	},
+}
+
+var backupPullCmd = &cobra.Command{
+	Use:   "pull [key]",
+	Short: "Download a backup from S3-compatible storage",
+	Long: `Download a backup from S3-compatible storage into .agent/update-backups/ and verify
+its manifest. A download without ` + backup.ManifestName + `, or one that does not match it, is
+deleted. Without a key, lists the backups available remotely for the active --env.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		repoRoot, err := resolveRepoRootForFix()
+		if err != nil {
+			return err
+		}
+		store, err := newRemoteStoreFromEnv(repoRoot)
+		if err != nil {
+			return err
+		}
+		if len(args) == 0 {
+			keys, err := listRemoteBackups(store)
+			if err != nil {
+				return err
+			}
+			if len(keys) == 0 {
+				fmt.Println("No remote backups found.")
+				return nil
+			}
+			for _, k := range keys {
+				fmt.Println(k)
+			}
+			return nil
+		}
+
+		key := strings.TrimSpace(args[0])
+		name := sanitizeBackupID(strings.TrimSuffix(filepath.Base(key), ".tar.gz"))
+		if name == "" {
+			return fmt.Errorf("invalid backup key %q", key)
+		}
+		dst := filepath.Join(updateBackupRoot(repoRoot), name)
+		if _, err := os.Stat(dst); err == nil {
+			return fmt.Errorf("backup directory already exists: %s", dst)
+		}
+		if err := store.Pull(key, dst); err != nil {
+			_ = os.RemoveAll(dst)
+			return err
+		}
+		// Unlike local sets from before manifests existed, a download is only trusted with one.
+		if err := backup.VerifyManifest(dst); err != nil {
+			_ = os.RemoveAll(dst)
+			if errors.Is(err, fs.ErrNotExist) {
+				return fmt.Errorf("downloaded backup has no %s; refusing to keep it", backup.ManifestName)
+			}
+			return fmt.Errorf("downloaded backup failed verification: %w", err)
+		}
+		fmt.Printf("Pulled %s -> %s\n", key, dst)
+		return nil
+	},
+}
+
+func init() {
+	backupPushCmd.Flags().StringVar(&backupPushDir, "dir", "", "backup directory to upload (default: latest update backup)")
+	backupListCmd.Flags().BoolVar(&backupListJSON, "json", false, "output as JSON")
+	backupPruneCmd.Flags().IntVar(&backupKeep, "keep", backup.DefaultKeep, "number of newest backups to keep")
+	addBackupCreateFlags(backupCreateCmd)
+
+	backupCmd.AddCommand(backupCreateCmd)
+	backupCmd.AddCommand(backupListCmd)
+	backupCmd.AddCommand(backupPruneCmd)
+	backupCmd.AddCommand(backupPushCmd)
+	backupCmd.AddCommand(backupPullCmd)
+	rootCmd.AddCommand(backupCmd)
+}
+
+// addBackupCreateFlags adds the flags of agent backup create to c (it is also agent config backup).
+func addBackupCreateFlags(c *cobra.Command) {
+	c.Flags().BoolVar(&backupCreateIncremental, "incremental", false, "copy only the files changed since the most recent backup set")
+	c.Flags().BoolVar(&backupCreateFull, "full", false, "take a full backup even when "+backup.IncrementalEnv+" is set")
+}
+
+func updateBackupRoot(repoRoot string) string {
+	return filepath.Join(envBackupBase(repoRoot), "update-backups")
+}
+
+func checkFixBackupRoot(repoRoot string) string {
+	return filepath.Join(envBackupBase(repoRoot), "check-fix-backups")
+}
+
+// envBackupBase is .agent for the default environment and .agent/envs/<name> otherwise, so
+// a restore under one --env never picks up another environment's backups.
+func envBackupBase(repoRoot string) string {
+	if agentEnv == environment.Default {
+		return filepath.Join(repoRoot, ".agent")
+	}
+	return filepath.Join(repoRoot, ".agent", "envs", agentEnv)
+}
+
+// pruneContentStore drops content-store objects no longer linked from any backup set. The
+// store is shared by every environment, so the sets of all of them are scanned, not only the
+// current --env's.
+func pruneContentStore(repoRoot string) (int, error) {
+	dir := filepath.Join(repoRoot, filepath.FromSlash(backup.ContentStoreDir))
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return 0, nil
+	}
+	roots, err := allBackupRoots(repoRoot)
+	if err != nil {
+		return 0, err
+	}
+	return backup.PruneContentStore(&backup.ContentStore{Dir: dir}, roots...)
+}
+
+// allBackupRoots lists the update-backup and check-fix-backup roots of the default
+// environment and of every environment with a directory under .agent/envs.
+func allBackupRoots(repoRoot string) ([]string, error) {
+	bases := []string{filepath.Join(repoRoot, ".agent")}
+	entries, err := os.ReadDir(filepath.Join(repoRoot, ".agent", "envs"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to list environment backups: %w", err)
+	}
+	for _, e := range entries {
+		if e.IsDir() {
+			bases = append(bases, filepath.Join(repoRoot, ".agent", "envs", e.Name()))
+		}
+	}
+	roots := make([]string, 0, 2*len(bases))
+	for _, base := range bases {
+		roots = append(roots, filepath.Join(base, "update-backups"), filepath.Join(base, "check-fix-backups"))
+	}
+	return roots, nil
+}
+
+// backupKeepFromEnv reads AGENT_BACKUP_KEEP from the process environment, falling back to .env.
+// Invalid or negative values fall back to backup.DefaultKeep.
+func backupKeepFromEnv(repoRoot string) int {
+	envMap, _ := health.LoadEnvFile(filepath.Join(repoRoot, envRel(".env")))
+	raw := strings.Trim(strings.TrimSpace(health.GetEnv("AGENT_BACKUP_KEEP", envMap)), "\"'")
+	if raw == "" {
+		return backup.DefaultKeep
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 0 {
+		return backup.DefaultKeep
+	}
+	return n
+}
+
+// backupEnvValue reads key from the process environment, falling back to repoRoot/.env.
+func backupEnvValue(repoRoot, key string) string {
+	envMap, _ := health.LoadEnvFile(filepath.Join(repoRoot, envRel(".env")))
+	return strings.Trim(strings.TrimSpace(health.GetEnv(key, envMap)), "\"'")
+}
+
+// backupIdentityFile returns AGENT_BACKUP_IDENTITY_FILE resolved against repoRoot, or "".
+func backupIdentityFile(repoRoot string) string {
+	identity := backupEnvValue(repoRoot, backup.IdentityFileEnv)
+	if identity != "" && !filepath.IsAbs(identity) {
+		identity = filepath.Join(repoRoot, identity)
+	}
+	return identity
+}
+
+// encryptNewBackup encrypts a freshly written backup set to AGENT_BACKUP_ENCRYPT_KEY, if set,
+// and signs it with AGENT_BACKUP_IDENTITY_FILE, which must be the identity of that key.
+func encryptNewBackup(repoRoot, dir string) error {
+	key := backupEnvValue(repoRoot, backup.EncryptKeyEnv)
+	if key == "" {
+		return nil
+	}
+	identity := backupIdentityFile(repoRoot)
+	if identity == "" {
+		return fmt.Errorf("cannot encrypt backup %s (%s is set): set %s to its age identity file to sign the set", dir, backup.EncryptKeyEnv, backup.IdentityFileEnv)
+	}
+	if err := backup.CheckIdentity(identity, key); err != nil {
+		return fmt.Errorf("cannot encrypt backup %s (%s is set): %w", dir, backup.EncryptKeyEnv, err)
+	}
+	if err := backup.EncryptBackupDir(dir, key); err != nil {
+		return fmt.Errorf("failed to encrypt backup %s (%s is set): %w", dir, backup.EncryptKeyEnv, err)
+	}
+	if err := backup.SignBackupManifest(dir, identity); err != nil {
+		return fmt.Errorf("failed to sign backup %s: %w", dir, err)
+	}
+	return nil
+}
+
+// openBackupDir returns dir itself, or for an encrypted set a decrypted copy in a private temp
+// directory (using AGENT_BACKUP_IDENTITY_FILE) so plaintext never lands in the backup tree.
+// An incremental set is rebuilt from its chain (see materializeBackupDir). The returned cleanup
+// removes the copy.
+func openBackupDir(repoRoot, dir string) (string, func(), error) {
+	if backup.IsIncremental(dir) {
+		return materializeBackupDir(dir)
+	}
+	if !backup.IsEncrypted(dir) {
+		return dir, func() {}, nil
+	}
+	identity := backupIdentityFile(repoRoot)
+	if identity == "" {
+		return "", nil, fmt.Errorf("backup is encrypted; set %s to the age identity file", backup.IdentityFileEnv)
+	}
+	tmp, err := os.MkdirTemp("", "agent-backup-")
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup := func() { _ = os.RemoveAll(tmp) }
+	if err := copyDir(dir, tmp); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+	if err := backup.DecryptBackupDir(tmp, identity); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+	return tmp, cleanup, nil
+}
+
+// materializeBackupDir returns dir itself for a full set, or for an incremental set (agent
+// backup create --incremental) its full contents rebuilt from the nearest full set and the
+// deltas after it, in a temp directory named like dir. The returned cleanup removes the copy.
+func materializeBackupDir(dir string) (string, func(), error) {
+	if !backup.IsIncremental(dir) {
+		return dir, func() {}, nil
+	}
+	tmp, err := os.MkdirTemp("", "agent-backup-")
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup := func() { _ = os.RemoveAll(tmp) }
+	out := filepath.Join(tmp, filepath.Base(filepath.Clean(dir)))
+	if err := backup.MaterializeBackup(dir, out); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+	return out, cleanup, nil
+}
+
+// newRemoteStoreFromEnv builds the S3 store from the process environment, falling back to .env.
+func newRemoteStoreFromEnv(repoRoot string) (remote.RemoteStore, error) {
+	envMap, _ := health.LoadEnvFile(filepath.Join(repoRoot, envRel(".env")))
+	get := func(key string) string {
+		return strings.Trim(strings.TrimSpace(health.GetEnv(key, envMap)), "\"'")
+	}
+	return remote.NewS3Store(remote.S3Config{
+		Endpoint:        get("AWS_ENDPOINT"),
+		Bucket:          get("AWS_BUCKET"),
+		AccessKeyID:     get("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: get("AWS_SECRET_ACCESS_KEY"),
+		Region:          get("AWS_REGION"),
+		Prefix:          remoteEnvPrefix(),
+	})
+}
+
+// listRemoteBackups lists the active --env's remote sets; for the default environment the
+// keys under remoteEnvsDir, which belong to named environments, are left out.
+func listRemoteBackups(store remote.RemoteStore) ([]string, error) {
+	keys, err := store.List()
+	if err != nil || agentEnv != environment.Default {
+		return keys, err
+	}
+	own := keys[:0]
+	for _, k := range keys {
+		if !strings.HasPrefix(k, remoteEnvsDir) {
+			own = append(own, k)
+		}
+	}
+	return own, nil
+}
+
+// remoteEnvPrefix is the object key prefix of the active --env's sets: none for the default
+// environment and envs/<name>/ otherwise, mirroring envBackupBase, so a pull under one --env
+// never lists or fetches another environment's sets.
+func remoteEnvPrefix() string {
+	if agentEnv == environment.Default {
+		return ""
+	}
+	return remoteEnvsDir + agentEnv + "/"
+}
+
+// remoteEnvsDir holds the named environments' sets in remote storage.
+const remoteEnvsDir = "envs/"
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/backup"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/exitcodes"
+	"github.com/spf13/cobra"
+)
+
+var (
+	backupRestoreSource    string
+	backupRestoreTargetDir string
+	backupRestoreToLive    bool
+)
+
+var backupRestoreCmd = &cobra.Command{
+	Use:   "restore",
+	Short: "Restore a backup set into a directory for inspection",
+	Long: `Restore a backup set into --target-dir without touching the live config, to inspect
+what it contains. --source is a backup directory or a timestamp prefix matched against
+.agent/update-backups/ and .agent/check-fix-backups/ (see agent backup list).
+
+The set goes through the same restore path as agent check --fix and agent rollback: the
+manifest is verified, encrypted sets are decrypted with AGENT_BACKUP_IDENTITY_FILE and
+incremental sets are rebuilt from their chain, and only files that pass validation are
+written, under their repo-relative paths (.env, config/...). A per-file validation report
+(as in agent backup verify) and the restored paths are printed.
+
+--target-dir must not be the repo root. --to-live restores into the repo root instead (or
+allows --target-dir to be it): the current state is snapshotted to
+.agent/check-fix-backups/ first, and no service is restarted (agent rollback does both).
+
+Exits 2 if the set has invalid files or nothing could be restored.`,
+	Example: `  agent backup restore --source 20240615_143000 --target-dir /tmp/inspect
+  agent backup restore --source .agent/update-backups/20240615_143000 --to-live`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if backupRestoreSource == "" {
+			return errors.New("--source is required")
+		}
+		if backupRestoreTargetDir == "" && !backupRestoreToLive {
+			return errors.New("--target-dir is required (or --to-live to restore over the live config)")
+		}
+		repoRoot, err := resolveRepoRootForFix()
+		if err != nil {
+			return err
+		}
+		// Both paths are resolved against the caller's working directory, before the chdir.
+		dir, err := resolveRollbackTarget(repoRoot, backupRestoreSource)
+		if err != nil {
+			return err
+		}
+		target := repoRoot
+		if backupRestoreTargetDir != "" {
+			if target, err = filepath.Abs(backupRestoreTargetDir); err != nil {
+				return fmt.Errorf("invalid --target-dir: %w", err)
+			}
+		}
+		live := sameDir(target, repoRoot)
+		if live && !backupRestoreToLive {
+			return fmt.Errorf("--target-dir %s is the live repo root; pass --to-live to restore over the live config", backupRestoreTargetDir)
+		}
+		if err := os.Chdir(repoRoot); err != nil {
+			return fmt.Errorf("failed to switch to repo root: %w", err)
+		}
+
+		ok := verifyBackupSet(repoRoot, backup.BackupSetInfo{Kind: filepath.Base(filepath.Dir(dir)), Path: dir})
+		fmt.Println()
+
+		restoreBase := true
+		if live {
+			restoreBase = shouldRestoreBaseConfig()
+			summary := &fixSummary{repoRoot: repoRoot}
+			if err := snapshotBeforeFix(repoRoot, summary); err != nil {
+				return err
+			}
+			fmt.Printf("Snapshot of the current config: %s\n", summary.prefixBackup)
+		} else if err := os.MkdirAll(target, 0o755); err != nil {
+			return fmt.Errorf("failed to create %s: %w", target, err)
+		}
+		result := restoreFromSingleBackupDir(dir, restoreBase, true, target)
+		for _, w := range result.warnings {
+			fmt.Printf("Warning: %s\n", w)
+		}
+		if len(result.restoredPaths) == 0 {
+			fmt.Printf("Nothing restored from %s (no valid .env with a valid ai-agent.yaml or ai-agent.local.yaml)\n", dir)
+			os.Exit(exitcodes.ExitFail)
+		}
+		fmt.Printf("Restored %d path(s) into %s:\n", len(result.restoredPaths), target)
+		for _, rel := range result.restoredPaths {
+			fmt.Printf("  %s\n", rel)
+		}
+		if !ok {
+			os.Exit(exitcodes.ExitFail)
+		}
+		return nil
+	},
+}
+
+func init() {
+	backupRestoreCmd.Flags().StringVar(&backupRestoreSource, "source", "", "backup directory or timestamp prefix to restore")
+	backupRestoreCmd.Flags().StringVar(&backupRestoreTargetDir, "target-dir", "", "directory to restore into (must not be the repo root)")
+	backupRestoreCmd.Flags().BoolVar(&backupRestoreToLive, "to-live", false, "restore over the live config in the repo root, after snapshotting it")
+	backupCmd.AddCommand(backupRestoreCmd)
+}
+
+// sameDir reports whether a and b name the same directory, following symlinks when they
+// exist.
+func sameDir(a, b string) bool {
+	if ra, err := filepath.EvalSymlinks(a); err == nil {
+		a = ra
+	}
+	if rb, err := filepath.EvalSymlinks(b); err == nil {
+		b = rb
+	}
+	return filepath.Clean(a) == filepath.Clean(b)
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/backup"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/environment"
+	cmdexec "github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/exec"
+	"github.com/spf13/cobra"
+)
+
+var (
+	backupScheduleInterval string
+	backupScheduleRemove   bool
+	backupScheduleMethod   string
+)
+
+var backupScheduleCmd = &cobra.Command{
+	Use:   "schedule",
+	Short: "Run agent backup create automatically (systemd user timer or cron)",
+	Long: `Install a job that runs agent backup create hourly, daily or weekly for this repo root.
+
+With systemd (a user manager answering systemctl --user), agent-backup.service and
+agent-backup.timer are written to ~/.config/systemd/user/ and the timer is enabled. Otherwise
+a line tagged "# agent-backup (managed by agent backup schedule)" is added to the user's
+crontab. Use --method to choose explicitly. Running schedule again replaces the job.
+
+User timers only run while the user is logged in unless lingering is enabled
+(loginctl enable-linger). With --env NAME the job is agent-backup-NAME and backs up that
+environment. --remove uninstalls the job.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := backup.ScheduleName(agentEnv)
+		method := strings.ToLower(strings.TrimSpace(backupScheduleMethod))
+		switch method {
+		case "auto", "systemd", "cron":
+		default:
+			return fmt.Errorf("invalid --method %q (must be auto, systemd or cron)", backupScheduleMethod)
+		}
+		if backupScheduleRemove {
+			return removeBackupSchedule(name, method)
+		}
+
+		iv, err := backup.ParseInterval(backupScheduleInterval)
+		if err != nil {
+			return err
+		}
+		repoRoot, err := resolveRepoRootForFix()
+		if err != nil {
+			return err
+		}
+		exe, err := os.Executable()
+		if err != nil {
+			return fmt.Errorf("cannot locate the agent binary: %w", err)
+		}
+		argv := []string{exe, "--repo-root", repoRoot}
+		if agentEnv != environment.Default {
+			argv = append(argv, "--env", agentEnv)
+		}
+		command, err := backup.QuoteCommand(append(argv, "backup", "create")...)
+		if err != nil {
+			return err
+		}
+
+		if method == "auto" {
+			method = "cron"
+			if systemdUserAvailable() {
+				method = "systemd"
+			} else if _, err := exec.LookPath("crontab"); err != nil {
+				return errors.New("neither a systemd user manager nor crontab is available")
+			}
+		}
+		if method == "systemd" {
+			return installSystemdSchedule(name, command, iv)
+		}
+		return installCronSchedule(name, command, iv)
+	},
+}
+
+func init() {
+	backupScheduleCmd.Flags().StringVar(&backupScheduleInterval, "interval", string(backup.Daily), "how often to back up: hourly, daily or weekly")
+	backupScheduleCmd.Flags().BoolVar(&backupScheduleRemove, "remove", false, "uninstall the scheduled backup")
+	backupScheduleCmd.Flags().StringVar(&backupScheduleMethod, "method", "auto", "scheduler to use: auto, systemd or cron")
+
+	backupCmd.AddCommand(backupScheduleCmd)
+}
+
+// systemdUnitDir is where systemd looks for user units (~/.config/systemd/user).
+func systemdUnitDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "systemd", "user"), nil
+}
+
+// systemdUserAvailable reports whether a systemd user manager is running for this user.
+func systemdUserAvailable() bool {
+	_, err := cmdexec.RunCmdResult(context.Background(), "systemctl", "--user", "show-environment")
+	return err == nil
+}
+
+func installSystemdSchedule(name string, command []string, iv backup.Interval) error {
+	dir, err := systemdUnitDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	service, timer := backup.SystemdUnits(name, command, iv)
+	for _, unit := range []struct{ file, data string }{{name + ".service", service}, {name + ".timer", timer}} {
+		path := filepath.Join(dir, unit.file)
+		if err := os.WriteFile(path, []byte(unit.data), 0o644); err != nil {
+			return err
+		}
+		fmt.Printf("Wrote %s\n", path)
+	}
+	if _, err := runCmd("systemctl", "--user", "daemon-reload"); err != nil {
+		return fmt.Errorf("systemctl --user daemon-reload: %w", err)
+	}
+	if _, err := runCmd("systemctl", "--user", "enable", "--now", name+".timer"); err != nil {
+		return fmt.Errorf("systemctl --user enable --now %s.timer: %w", name, err)
+	}
+	fmt.Printf("Enabled %s.timer (%s)\n", name, iv)
+
+	next, _ := runCmd("systemctl", "--user", "show", name+".timer", "--property=NextElapseUSecRealtime", "--value")
+	if next == "" || next == "n/a" {
+		next = "unknown (see systemctl --user list-timers)"
+	}
+	fmt.Printf("Next run: %s\n", next)
+	if user := os.Getenv("USER"); user != "" {
+		if linger, err := runCmd("loginctl", "show-user", user, "--property=Linger", "--value"); err == nil && linger == "no" {
+			fmt.Printf("Note: user timers stop when %s logs out; run 'loginctl enable-linger %s' to keep backups running.\n", user, user)
+		}
+	}
+	return nil
+}
+
+// readCrontab returns the user's crontab ("" when there is none yet).
+func readCrontab() (string, error) {
+	res, err := cmdexec.RunCmdResult(context.Background(), "crontab", "-l")
+	if err != nil {
+		if strings.Contains(res.Stderr, "no crontab") {
+			return "", nil
+		}
+		return "", fmt.Errorf("crontab -l: %w", err)
+	}
+	return res.Stdout, nil
+}
+
+func writeCrontab(content string) error {
+	ctx := cmdexec.WithStreams(context.Background(), cmdexec.Streams{Stdin: strings.NewReader(content)})
+	if _, err := cmdexec.RunCmdResult(ctx, "crontab", "-"); err != nil {
+		return fmt.Errorf("crontab -: %w", err)
+	}
+	return nil
+}
+
+func installCronSchedule(name string, command []string, iv backup.Interval) error {
+	current, err := readCrontab()
+	if err != nil {
+		return err
+	}
+	if err := writeCrontab(backup.CrontabWithSchedule(current, name, command, iv)); err != nil {
+		return err
+	}
+	fmt.Printf("Added @%s %s to your crontab\n", iv, strings.Join(command, " "))
+	fmt.Printf("Next run: %s\n", backup.NextCronRun(iv, time.Now()).Format("Mon 2006-01-02 15:04 MST"))
+	return nil
+}
+
+// removeBackupSchedule uninstalls the job for name from systemd and/or cron, per method.
+func removeBackupSchedule(name, method string) error {
+	removed := false
+	if method != "cron" {
+		dir, err := systemdUnitDir()
+		if err != nil {
+			return err
+		}
+		timer := filepath.Join(dir, name+".timer")
+		if _, err := os.Stat(timer); err == nil {
+			// Best-effort: the manager may not be running (e.g. outside a login session).
+			_, _ = runCmd("systemctl", "--user", "disable", "--now", name+".timer")
+			for _, file := range []string{timer, filepath.Join(dir, name+".service")} {
+				if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+					return err
+				}
+			}
+			_, _ = runCmd("systemctl", "--user", "daemon-reload")
+			fmt.Printf("Removed %s.timer and %s.service\n", name, name)
+			removed = true
+		}
+	}
+	if method != "systemd" {
+		if _, err := exec.LookPath("crontab"); err == nil {
+			current, err := readCrontab()
+			if err != nil {
+				return err
+			}
+			if rest, found := backup.CrontabWithoutSchedule(current, name); found {
+				if err := writeCrontab(rest); err != nil {
+					return err
+				}
+				fmt.Printf("Removed the %s line from your crontab\n", name)
+				removed = true
+			}
+		}
+	}
+	if !removed {
+		fmt.Println("No backup schedule installed.")
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/backup"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/environment"
+)
+
+func TestPruneContentStoreKeepsOtherEnvironmentsObjects(t *testing.T) {
+	root := t.TempDir()
+	orig := agentEnv
+	t.Cleanup(func() { agentEnv = orig })
+
+	store, err := backup.NewContentStore(filepath.Join(root, filepath.FromSlash(backup.ContentStoreDir)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := filepath.Join(t.TempDir(), ".env")
+	writeTestFile(t, src, "ASTERISK_HOST=pbx\n")
+	set := filepath.Join(root, ".agent", "update-backups", "20260101_000000", ".env")
+	if err := backup.BackupWithDedup(src, set, store); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(root, ".agent", "envs", "staging", "update-backups"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	agentEnv = "staging"
+	removed, err := pruneContentStore(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removed != 0 {
+		t.Fatalf("prune under --env staging removed %d object(s) the default environment uses", removed)
+	}
+
+	if err := os.RemoveAll(filepath.Join(root, ".agent", "update-backups")); err != nil {
+		t.Fatal(err)
+	}
+	if removed, err := pruneContentStore(root); err != nil || removed != 1 {
+		t.Fatalf("removed=%d err=%v, want the unreferenced object removed", removed, err)
+	}
+}
+
+type fakeRemoteStore struct{ keys []string }
+
+func (f fakeRemoteStore) Push(localDir, key string) error { return nil }
+func (f fakeRemoteStore) Pull(key, localDir string) error { return nil }
+func (f fakeRemoteStore) List() ([]string, error)         { return append([]string(nil), f.keys...), nil }
+
+func TestRemoteBackupsAreKeptApartPerEnvironment(t *testing.T) {
+	orig := agentEnv
+	t.Cleanup(func() { agentEnv = orig })
+	store := fakeRemoteStore{keys: []string{"20260101_000000.tar.gz", "envs/staging/20260102_000000.tar.gz"}}
+
+	agentEnv = environment.Default
+	if p := remoteEnvPrefix(); p != "" {
+		t.Fatalf("default prefix = %q", p)
+	}
+	keys, err := listRemoteBackups(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(keys, []string{"20260101_000000.tar.gz"}) {
+		t.Fatalf("default environment lists %v", keys)
+	}
+
+	agentEnv = "staging"
+	if p := remoteEnvPrefix(); p != "envs/staging/" {
+		t.Fatalf("staging prefix = %q", p)
+	}
+}
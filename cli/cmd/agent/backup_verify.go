@@ -0,0 +1,176 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/backup"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/exitcodes"
+	"github.com/spf13/cobra"
+)
+
+var (
+	backupVerifyAll         bool
+	backupVerifyLatest      int
+	backupVerifyFixManifest bool
+)
+
+var backupVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Check that backup sets are intact and restorable",
+	Long: `Verify backup sets in .agent/update-backups/ and .agent/check-fix-backups/ without
+restoring anything. For each set the manifest checksums are verified, then every file is
+checked for git conflict markers and validated the way agent check --fix validates it
+before a restore (.env core ARI keys, YAML mappings, config/users.json records).
+
+Encrypted sets are decrypted to a private temp directory for validation, which needs
+AGENT_BACKUP_IDENTITY_FILE.
+
+All sets are verified by default (--all); --latest N limits this to the newest N. With
+--fix-manifest, a manifest is written for sets that have none (created before manifests
+were introduced) when all of their files are valid.
+
+Exits 2 if any set has a checksum mismatch or an invalid file.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if backupVerifyAll && cmd.Flags().Changed("latest") {
+			return errors.New("--all and --latest cannot be combined")
+		}
+		if backupVerifyLatest < 0 {
+			return errors.New("--latest must not be negative")
+		}
+		repoRoot, err := resolveRepoRootForFix()
+		if err != nil {
+			return err
+		}
+
+		var sets []backup.BackupSetInfo
+		for _, root := range []string{updateBackupRoot(repoRoot), checkFixBackupRoot(repoRoot)} {
+			found, err := backup.ListBackupSets(root)
+			if err != nil {
+				return err
+			}
+			sets = append(sets, found...)
+		}
+		sort.SliceStable(sets, func(i, j int) bool { return sets[i].CreatedAt.After(sets[j].CreatedAt) })
+		if backupVerifyLatest > 0 && len(sets) > backupVerifyLatest {
+			sets = sets[:backupVerifyLatest]
+		}
+		if len(sets) == 0 {
+			fmt.Println("No backup sets found.")
+			return nil
+		}
+
+		invalid := 0
+		for i, s := range sets {
+			if i > 0 {
+				fmt.Println()
+			}
+			if !verifyBackupSet(repoRoot, s) {
+				invalid++
+			}
+		}
+		fmt.Println()
+		fmt.Printf("Verified %d backup set(s): %d valid, %d invalid\n", len(sets), len(sets)-invalid, invalid)
+		if invalid > 0 {
+			os.Exit(exitcodes.ExitFail)
+		}
+		return nil
+	},
+}
+
+func init() {
+	backupVerifyCmd.Flags().BoolVar(&backupVerifyAll, "all", false, "verify every backup set (default)")
+	backupVerifyCmd.Flags().IntVar(&backupVerifyLatest, "latest", 0, "only verify the newest N backup sets")
+	backupVerifyCmd.Flags().BoolVar(&backupVerifyFixManifest, "fix-manifest", false, "write a manifest for valid sets that are missing one")
+	backupCmd.AddCommand(backupVerifyCmd)
+}
+
+// verifyBackupSet prints the manifest status and a per-file table for one set and reports
+// whether the set is usable.
+func verifyBackupSet(repoRoot string, s backup.BackupSetInfo) bool {
+	fmt.Printf("%s/%s\n", s.Kind, filepath.Base(s.Path))
+	ok := true
+	status, err := backup.CheckManifest(s.Path)
+	switch status {
+	case backup.ManifestOK:
+		fmt.Println("  Manifest: ok")
+	case backup.ManifestMissing:
+		fmt.Println("  Manifest: missing (created before manifests were introduced)")
+	default:
+		fmt.Printf("  Manifest: FAIL (%v)\n", err)
+		ok = false
+	}
+
+	dir, cleanup, err := openBackupDir(repoRoot, s.Path)
+	if err != nil {
+		fmt.Printf("  Files: not validated (%v)\n", err)
+		return false
+	}
+	defer cleanup()
+	results, err := backup.ValidateFiles(dir, backupFileValidators())
+	if err != nil {
+		fmt.Printf("  Files: not validated (%v)\n", err)
+		return false
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "  FILE\tCHECKS\tRESULT")
+	for _, r := range results {
+		result := "ok"
+		if !r.OK() {
+			result = "FAIL: " + r.Error
+			ok = false
+		}
+		fmt.Fprintf(tw, "  %s\t%s\t%s\n", r.Path, strings.Join(r.Validators, ","), result)
+	}
+	_ = tw.Flush()
+
+	if status == backup.ManifestMissing && backupVerifyFixManifest {
+		if !ok {
+			fmt.Println("  Manifest not written: the set has invalid files")
+		} else if err := writeManifestKeepMtime(s.Path); err != nil {
+			fmt.Printf("  Manifest not written: %v\n", err)
+		} else {
+			fmt.Printf("  Wrote %s\n", backup.ManifestName)
+		}
+	}
+	return ok
+}
+
+// writeManifestKeepMtime writes the manifest for an existing set and restores the set
+// directory's modification time, which orders sets that are not named after a timestamp.
+func writeManifestKeepMtime(dir string) error {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return err
+	}
+	if err := backup.WriteManifest(dir); err != nil {
+		return err
+	}
+	return os.Chtimes(dir, info.ModTime(), info.ModTime())
+}
+
+// backupFileValidators are the checks agent check --fix applies before restoring a file.
+func backupFileValidators() []backup.FileValidator {
+	return []backup.FileValidator{
+		{Name: "conflict-markers", Validate: func(p string) error {
+			if hasConflictMarkers(p) {
+				return errors.New("contains git conflict markers")
+			}
+			return nil
+		}},
+		{Name: "env", Match: func(rel string) bool { return rel == envRel(".env") }, Validate: validateEnvBackup},
+		{Name: "yaml", Match: func(rel string) bool {
+			ext := path.Ext(rel)
+			return ext == ".yaml" || ext == ".yml"
+		}, Validate: validateYAMLMappingBackup},
+		{Name: "users-json", Match: func(rel string) bool { return rel == envRel("config/users.json") }, Validate: validateUsersJSON},
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/backup"
+)
+
+func TestVerifyFixManifestKeepsLatestBackup(t *testing.T) {
+	root, newest := fixRepo(t)
+	orig := backupVerifyFixManifest
+	t.Cleanup(func() { backupVerifyFixManifest = orig })
+	backupVerifyFixManifest = true
+
+	old := filepath.Join(updateBackupRoot(root), "20250101_000000")
+	writeTestFile(t, filepath.Join(old, ".env"), "ASTERISK_HOST=pbx\nASTERISK_ARI_USERNAME=ari\n")
+	oldTime := time.Now().Add(-48 * time.Hour)
+	for _, dir := range []string{old, newest} {
+		if err := os.Chtimes(dir, oldTime, oldTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	sets, err := backup.ListBackupSets(updateBackupRoot(root))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var oldSet backup.BackupSetInfo
+	for _, s := range sets {
+		if s.Path == old {
+			oldSet = s
+		}
+	}
+	if !verifyBackupSet(root, oldSet) {
+		t.Fatal("expected the old set to verify")
+	}
+	if status, err := backup.CheckManifest(old); status != backup.ManifestOK {
+		t.Fatalf("manifest not written: %v", err)
+	}
+
+	latest, err := backup.LatestBackupDir(updateBackupRoot(root))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if latest != newest {
+		t.Fatalf("LatestBackupDir=%s after --fix-manifest, want %s", latest, newest)
+	}
+	info, err := os.Stat(old)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !info.ModTime().Equal(oldTime) {
+		t.Fatalf("old set mtime changed to %v", info.ModTime())
+	}
+}
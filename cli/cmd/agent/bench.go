@@ -0,0 +1,123 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/bench"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/check"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/exitcodes"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/health"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/secrets"
+	"github.com/spf13/cobra"
+)
+
+var (
+	benchConcurrency int
+	benchRequests    int
+	benchEndpoint    string
+	benchTimeout     = bench.DefaultRequestTimeout
+)
+
+var benchCmd = &cobra.Command{
+	Use:    "bench",
+	Short:  "Measure ARI round-trip latency and throughput under synthetic load",
+	Hidden: true, // advanced tool for sizing a deployment
+	Long: `Send --requests GET requests for /ari/api-docs/resources.json to Asterisk's ARI,
+--concurrency at a time, with the credentials from .env, and print the request rate, error
+rate, p50/p95/p99 latency and a latency histogram.
+
+The ARI URL is built from ASTERISK_ARI_SCHEME, ASTERISK_HOST and ASTERISK_ARI_PORT in .env
+(ASTERISK_ARI_SSL_VERIFY=false skips certificate checks); --endpoint overrides it, e.g.
+--endpoint https://pbx.example.com:8089. Run it from the host the agent runs on to get numbers
+that match what ai_engine sees. Ctrl-C stops early and prints what was measured.
+
+Exit codes: 0 no errors, 1 some requests failed, 2 every request failed.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts, err := benchOptionsFromEnv()
+		if err != nil {
+			return err
+		}
+		opts.Concurrency = benchConcurrency
+		opts.Requests = benchRequests
+		opts.RequestTimeout = benchTimeout
+		if benchEndpoint != "" {
+			opts.Endpoint = benchEndpoint
+		}
+		if opts.Endpoint == "" {
+			return errors.New("ASTERISK_HOST not set in .env (or pass --endpoint)")
+		}
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		fmt.Printf("Benchmarking %s%s (%d requests, concurrency %d)...\n\n", strings.TrimRight(opts.Endpoint, "/"), bench.ARIPath, opts.Requests, min(opts.Concurrency, opts.Requests))
+		res, err := bench.RunBenchmark(ctx, opts)
+		if err != nil && res.Requests == 0 {
+			return err
+		}
+		if err != nil {
+			fmt.Println("Interrupted; partial results:")
+		}
+		if err := bench.WriteSummary(os.Stdout, res); err != nil {
+			return err
+		}
+
+		switch {
+		case res.Requests > 0 && res.Errors == res.Requests:
+			os.Exit(exitcodes.ExitFail)
+		case res.Errors > 0:
+			os.Exit(exitcodes.ExitWarn)
+		}
+		return nil
+	},
+}
+
+func init() {
+	benchCmd.Flags().IntVar(&benchConcurrency, "concurrency", bench.DefaultConcurrency, "requests in flight at once")
+	benchCmd.Flags().IntVar(&benchRequests, "requests", bench.DefaultRequests, "total number of requests")
+	benchCmd.Flags().StringVar(&benchEndpoint, "endpoint", "", "ARI base URL (default: from ASTERISK_ARI_SCHEME/ASTERISK_HOST/ASTERISK_ARI_PORT in .env)")
+	benchCmd.Flags().DurationVar(&benchTimeout, "timeout", bench.DefaultRequestTimeout, "timeout for each request")
+	rootCmd.AddCommand(benchCmd)
+}
+
+// benchOptionsFromEnv reads the ARI URL and credentials from the active .env. Endpoint is
+// empty when ASTERISK_HOST is not set.
+func benchOptionsFromEnv() (bench.BenchmarkOptions, error) {
+	var opts bench.BenchmarkOptions
+	repoRoot, err := resolveRepoRootForFix()
+	if err != nil {
+		return opts, err
+	}
+	envMap, _, _ := secrets.LoadEnv(filepath.Join(repoRoot, envRel(".env"))) // .env.age is decrypted in memory
+	get := func(key string) string { return check.EnvValue(health.GetEnv(key, envMap)) }
+
+	opts.Username = get("ASTERISK_ARI_USERNAME")
+	opts.Password = get("ASTERISK_ARI_PASSWORD")
+	switch strings.ToLower(get("ASTERISK_ARI_SSL_VERIFY")) {
+	case "0", "false", "no":
+		opts.InsecureSkipVerify = true
+	}
+	host := get("ASTERISK_HOST")
+	if host == "" {
+		return opts, nil
+	}
+	port := get("ASTERISK_ARI_PORT")
+	if port == "" {
+		if k, ok := check.DefaultEnvSchema().Lookup("ASTERISK_ARI_PORT"); ok {
+			port = k.Default
+		}
+	}
+	scheme := strings.ToLower(get("ASTERISK_ARI_SCHEME"))
+	if scheme == "" {
+		scheme = "http"
+	}
+	opts.Endpoint = scheme + "://" + net.JoinHostPort(host, port)
+	return opts, nil
+}
@@ -1,17 +1,58 @@
 package main
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"log/slog"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/check"
+	dockercheck "github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/check/docker"
+	schemacheck "github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/check/schema"
+	tlscheck "github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/check/tls"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/env"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/exitcodes"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/logging"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/metrics"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/notify"
 	"github.com/spf13/cobra"
 )
 
 var (
-	checkJSON bool
-	checkFix  bool
+	checkJSON               bool
+	checkFormat             string
+	checkFix                bool
+	checkFixInteractive     bool
+	checkFixDryRun          bool
+	checkFixSummaryOutput   string
+	checkHTMLOutput         string
+	checkSlowThreshold      time.Duration
+	checkTimeout            time.Duration
+	checkSince              bool
+	checkConcurrency        int
+	checkWaitTimeout        time.Duration
+	checkWatch              time.Duration
+	checkItems              []string
+	checkSchemaFile         string
+	checkSummaryOnly        bool
+	checkBaseline           bool
+	checkClearBaseline      bool
+	checkNotifyWebhook      string
+	checkPushGateway        string
+	checkPushJob            string
+	checkPushInstance       string
+	checkFixMaxRetries      int
+	checkFixAllowPrerelease bool
+	checkOutputOrder        string
+	checkCompareTo          string
+	// checkOutputOptions is --output-order, parsed by validateCheckFlags.
+	checkOutputOptions check.OutputOptions
 )
 
 var checkCmd = &cobra.Command{
@@ -20,37 +61,152 @@ var checkCmd = &cobra.Command{
 	Long: `Run the standard diagnostics report for Asterisk AI Voice Agent.
 
 This is the recommended first step when troubleshooting. It prints a shareable report
-to stdout. Use --json for JSON-only output.
+to stdout. Use --format=json (or --json) for JSON-only output, --format=sarif for a
+SARIF 2.1.0 log that GitHub code scanning (github/codeql-action/upload-sarif) can ingest, or
+--format=html for a self-contained HTML dashboard.
+
+--html-output FILE also writes the HTML dashboard to FILE, next to the normal output. The page
+reloads itself every 10s, so with --watch a browser showing FILE follows each run.
 
 Probes:
+  - config/contexts/*.yaml syntax (host-side)
   - Docker + Compose
+  - docker-compose.yml syntax (docker compose config) and core image pullability
+    (docker compose pull --dry-run, Compose v2.20+; registry errors are warnings)
   - ai_engine container status, network mode, mounts
   - In-container checks via: docker exec ai_engine python -
-  - ARI reachability and app registration (container-side only)
+  - ARI connectivity from the host (ASTERISK_HOST:ASTERISK_ARI_PORT, round-trip time)
+  - ARI reachability and app registration (container-side)
   - Transport compatibility + advertise host alignment
   - Best-effort internet/DNS reachability (no external containers)
+  - Stale images: ai_engine, admin_ui and local_ai_server still running an older image than
+    the one last pulled (warning; run docker compose up -d <service>)
+  - Zombie containers: exited but not removed containers of the Compose project
+    (COMPOSE_PROJECT_NAME), with their exit codes (warning; run agent cleanup --zombies)
+  - Env example: keys .env.example sets that .env lacks, and placeholder values
+    (warning; see agent env diff)
+  - Asterisk TLS certificate on the ARI endpoint when ASTERISK_ARI_SCHEME=https or
+    ASTERISK_TLS=true: expired or hostname mismatch fails, expiry within
+    ASTERISK_TLS_WARN_DAYS (default 14) warns
+  - config/ai-agent.yaml against the JSON Schema built into agent (--schema-file FILE to use
+    another schema version; $ref to local files resolves, http(s) refs are not fetched);
+    each violation is listed by its JSON Pointer path
+
+Each completed run is saved to .agent/last-report.json. With --since, only the checks whose
+status changed since that run are printed (NEW: or RECOVERED:); if nothing changed and all
+checks pass, a single "No status changes" line is printed.
+
+With -w/--watch, diagnostics re-run every 5s (or --watch=10s) and the report is redrawn until
+Ctrl-C. Checks that got worse since the previous run are flagged REGRESSION:, checks that got
+better RECOVERED:. Watch runs are not saved to .agent/last-report.json.
+
+With --item NAME (repeatable), only the named checks run, plus the checks they depend on
+(e.g. --item ari depends on config, env and the ai_engine container). NAME is an item name
+as printed in the report or its lower-case dashed form (ari-connectivity, internet-dns).
+Partial reports are not saved to .agent/last-report.json.
+
+With --baseline, the report is also saved to .agent/baseline-report.json as the known-good
+state. Later runs list the checks that passed in the baseline and now fail as
+"REGRESSION since <date>:" ahead of the report. --clear-baseline deletes the baseline.
+
+With --notify-webhook URL, a message is posted to URL when the checks go from no failures to
+at least one (alert) or back (recovery), compared with .agent/last-report.json or, with
+--watch, the previous run. Slack incoming webhooks get a Slack message; other URLs are probed
+once with an empty POST and get {"text", "timestamp", "event", "summary", "changed_items", ...}
+unless the reply shows a Slack-compatible receiver.
+
+With --push-gateway URL, each run's results are POSTed to a Prometheus Pushgateway at
+URL/metrics/job/<--push-job>/instance/<--push-instance> (default agent_check and the host
+name): agent_check_item_status{name,status} (1 for the item's status, 0 for the others),
+agent_check_duration_seconds{name}, agent_check_failing, agent_check_warning and
+agent_check_last_run_timestamp_seconds. A failed push is logged and does not change the exit
+code.
+
+With AGENT_TELEMETRY=1 in .env, complete runs also send anonymized counts and built-in check
+statuses to AGENT_TELEMETRY_ENDPOINT (off by default; see agent telemetry --show-payload).
+
+--output-order sets the order of the text report: fail-first (default) or warn-first (problems
+on top), pass-first (problems last, just above the summary) or name-asc. Items with the same
+status keep their run order.
+
+With --compare-to FILE, a report saved as JSON (agent check --format json, or
+.agent/last-report.json from another commit) is the base: instead of the report, only the
+checks whose state changed are printed, as "↑ new failure", "↑ new warning" or "↓ resolved"
+(--format json prints the change lists as JSON). The exit code then reflects the changes:
+0 - no new failures or warnings, 1 - new warnings only, 2 - new failures.
+
+With --summary-only, the report is replaced by one line for status boards:
+"✓ all 12 checks passed", "⚠ 2 warnings" or "✗ 3 failures, 1 warning".
 
 Exit codes:
   0 - PASS (no warnings)
   1 - WARN (non-critical issues)
-  2 - FAIL (critical issues)`,
+  2 - FAIL (critical issues)
+  3 - --fix: restoring backups or restarting services failed
+  4 - --fix: no restorable backup found
+  5 - pre-flight error (invalid flags, repo root not found, pre-fix snapshot failed)`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		format, err := resolveCheckFormat()
+		if err == nil {
+			err = validateCheckFlags(format)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(exitcodes.ExitPreFlight)
+		}
 		if checkFix {
-			if checkJSON {
-				return errors.New("--fix cannot be combined with --json")
+			exitCode, err := runCheckWithFix(logging.FromContext(cmd.Context()), checkFixMaxRetries)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			}
-			exitCode, err := runCheckWithFix()
-			if exitCode != 0 {
+			if exitCode != exitcodes.ExitOK {
 				os.Exit(exitCode)
 			}
-			if err != nil {
-				return err
-			}
 			return nil
 		}
 
-		runner := check.NewRunner(verbose, version, buildTime)
-		report, err := runner.Run()
+		log := logging.FromContext(cmd.Context())
+		if checkClearBaseline {
+			return clearBaseline()
+		}
+		var compareBase *check.Report
+		if checkCompareTo != "" {
+			compareBase, err = check.LoadReport(checkCompareTo)
+			if err == nil && compareBase == nil {
+				err = fmt.Errorf("--compare-to: %s not found", checkCompareTo)
+			}
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(exitcodes.ExitPreFlight)
+			}
+		}
+		runner := newCheckRunner()
+		runner.Concurrency = checkConcurrency
+		runner.Logger = log
+		runner.FilterItems = checkItems
+		if checkWatch > 0 {
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			isTTY := false
+			if fi, err := os.Stdout.Stat(); err == nil {
+				isTTY = (fi.Mode() & os.ModeCharDevice) != 0
+			}
+			w := &check.Watcher{Runner: runner, Timeout: checkTimeout, SlowThreshold: checkSlowThreshold, Clear: isTTY, HTMLOutput: checkHTMLOutput, Output: checkOutputOptions}
+			if checkNotifyWebhook != "" || checkPushGateway != "" {
+				w.OnReport = func(prev, rep *check.Report) {
+					if checkNotifyWebhook != "" {
+						notifyTransition(log, prev, rep)
+					}
+					pushCheckMetrics(log, rep)
+				}
+			}
+			return w.Run(ctx, checkWatch, os.Stdout)
+		}
+		report, err := runner.RunWithTimeout(context.Background(), checkTimeout)
+		if errors.Is(err, check.ErrUnknownItem) {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(exitcodes.ExitPreFlight)
+		}
 
 		if report == nil {
 			report = &check.Report{
@@ -73,19 +229,52 @@ Exit codes:
 			}
 		}
 
-		if checkJSON {
+		report.SlowThreshold = checkSlowThreshold
+		if !errors.Is(err, check.ErrTimedOut) && len(checkItems) == 0 {
+			trackLastReport(log, report)
+			sendCheckTelemetry(log, report)
+		}
+		if len(checkItems) == 0 {
+			compareBaseline(log, report, !errors.Is(err, check.ErrTimedOut))
+		}
+		pushCheckMetrics(log, report)
+		if compareBase != nil {
+			diff := check.DiffReports(compareBase, report)
+			if format == "json" {
+				_ = diff.OutputJSON(os.Stdout)
+			} else {
+				diff.OutputText(os.Stdout, checkCompareTo)
+			}
+			if exitCode := diff.ExitCode(); exitCode != exitcodes.ExitOK {
+				os.Exit(exitCode)
+			}
+			return nil
+		}
+		switch {
+		case checkSummaryOnly:
+			fmt.Println(report.Summarize())
+		case format == "json":
 			_ = report.OutputJSON(os.Stdout)
-		} else {
-			report.OutputText(os.Stdout)
+		case format == "sarif":
+			_ = report.OutputSARIF(os.Stdout)
+		case format == "html":
+			_ = report.OutputHTML(os.Stdout)
+		default:
+			report.OutputText(os.Stdout, checkOutputOptions)
+		}
+		if checkHTMLOutput != "" {
+			if err := report.WriteHTMLFile(checkHTMLOutput); err != nil {
+				log.Warn("could not write HTML report", "path", checkHTMLOutput, "error", err)
+			}
 		}
 
-		exitCode := 0
+		exitCode := exitcodes.ExitOK
 		if err != nil || report.FailCount > 0 {
-			exitCode = 2
+			exitCode = exitcodes.ExitFail
 		} else if report.WarnCount > 0 {
-			exitCode = 1
+			exitCode = exitcodes.ExitWarn
 		}
-		if exitCode != 0 {
+		if exitCode != exitcodes.ExitOK {
 			os.Exit(exitCode)
 		}
 		return nil
@@ -94,6 +283,208 @@ Exit codes:
 
 func init() {
 	checkCmd.Flags().BoolVar(&checkJSON, "json", false, "output as JSON (JSON only)")
+	checkCmd.Flags().StringVar(&checkFormat, "format", "text", "output format: text|json|sarif|html")
+	checkCmd.Flags().StringVar(&checkOutputOrder, "output-order", string(check.OrderFailFirst), "order of the text report: fail-first|warn-first|pass-first|name-asc")
+	checkCmd.Flags().StringVar(&checkHTMLOutput, "html-output", "", "also write the report as a self-contained HTML page to this file")
 	checkCmd.Flags().BoolVar(&checkFix, "fix", false, "attempt automatic recovery from recent backups and re-run diagnostics")
+	checkCmd.Flags().BoolVar(&checkFixDryRun, "dry-run", false, "with --fix, report what would be restored without writing files or restarting services")
+	checkCmd.Flags().BoolVar(&checkFixInteractive, "interactive", false, "with --fix, show a diff and confirm each file before it is restored")
+	checkCmd.Flags().IntVar(&checkFixMaxRetries, "max-retries", 1, "with --fix, restore cycles to try: after a failed re-check, restore the next-oldest backup set and check again")
+	checkCmd.Flags().BoolVar(&checkFixAllowPrerelease, "allow-prerelease-restore", false, "with --fix, also restore from backup sets marked as taken before an agent update --prerelease")
+	checkCmd.Flags().StringVar(&checkFixSummaryOutput, "summary-output", "", "with --fix, write the recovery summary and both reports as JSON to this file (replaced on each run)")
+	checkCmd.Flags().DurationVar(&checkSlowThreshold, "slow-threshold", check.DefaultSlowThreshold, "annotate checks slower than this and list them under \"Slow checks\"")
+	checkCmd.Flags().DurationVar(&checkWaitTimeout, "wait-timeout", check.DefaultWaitTimeout, "with --fix, keep re-running diagnostics after the restart until nothing fails or this much time has passed")
+	checkCmd.Flags().IntVar(&checkConcurrency, "concurrency", 1, "number of independent checks to run in parallel (report order is unchanged)")
+	checkCmd.Flags().BoolVar(&checkSince, "since", false, "only report checks whose status changed since the last run")
+	checkCmd.Flags().DurationVarP(&checkWatch, "watch", "w", 0, "re-run diagnostics on this interval and redraw the report until Ctrl-C (--watch alone: 5s)")
+	checkCmd.Flags().Lookup("watch").NoOptDefVal = check.DefaultWatchInterval.String()
+	checkCmd.Flags().DurationVar(&checkTimeout, "check-timeout", check.DefaultTimeout, "abort diagnostics that run longer than this and report the hung check as failed (0 disables)")
+	checkCmd.Flags().BoolVar(&checkSummaryOnly, "summary-only", false, "print only a one-line summary such as \"✓ all 12 checks passed\" (same exit codes)")
+	checkCmd.Flags().BoolVar(&checkBaseline, "baseline", false, "save this run as the known-good baseline ("+check.BaselineReportPath+")")
+	checkCmd.Flags().BoolVar(&checkClearBaseline, "clear-baseline", false, "delete the saved baseline and exit")
+	checkCmd.Flags().StringVar(&checkNotifyWebhook, "notify-webhook", "", "post to this Slack or generic JSON webhook when checks start failing or recover")
+	checkCmd.Flags().StringVar(&checkPushGateway, "push-gateway", "", "push the results to this Prometheus Pushgateway URL after each run")
+	checkCmd.Flags().StringVar(&checkPushJob, "push-job", metrics.DefaultJob, "with --push-gateway, the job label to push under")
+	checkCmd.Flags().StringVar(&checkPushInstance, "push-instance", "", "with --push-gateway, the instance label (default: this host's name)")
+	checkCmd.Flags().StringArrayVar(&checkItems, "item", nil, "only run this check and the checks it depends on (repeatable, e.g. --item ari-connectivity)")
+	checkCmd.Flags().StringVar(&checkCompareTo, "compare-to", "", "print only the checks whose state changed since this saved JSON report; exit 2 on new failures, 1 on new warnings")
+	checkCmd.Flags().StringVar(&checkSchemaFile, "schema-file", "", "validate config/ai-agent.yaml against this JSON Schema instead of the built-in one (local $ref only)")
 	rootCmd.AddCommand(checkCmd)
+
+	// Compiled-in checks that live outside the check package; they run after the built-ins.
+	dockercheck.Register(dockercheck.DefaultServices)
+	dockercheck.RegisterZombies()
+	dockercheck.RegisterDependencies()
+	env.RegisterDiffCheck()
+	tlscheck.Register()
+	schemacheck.Register(func() string { return checkSchemaFile })
+}
+
+// trackLastReport compares report with the previous run (for --since and --notify-webhook) and
+// saves it for the next one. Failures are non-fatal: --since then degrades to comparing against
+// an empty history, and no notification is sent.
+func trackLastReport(log *slog.Logger, report *check.Report) {
+	repoRoot, err := resolveRepoRootForFix()
+	if err != nil {
+		return
+	}
+	path := filepath.Join(repoRoot, filepath.FromSlash(check.LastReportPath))
+	if checkSince || checkNotifyWebhook != "" {
+		prev, err := check.LoadReport(path)
+		if err != nil {
+			log.Warn("ignoring previous report", "path", path, "error", err)
+		}
+		if checkNotifyWebhook != "" {
+			notifyTransition(log, prev, report)
+		}
+		if checkSince {
+			report.CompareWith(prev)
+			report.OnlyChanges = true
+		}
+	}
+	if err := check.SaveReport(path, report); err != nil {
+		log.Debug("could not save last report", "path", path, "error", err)
+	}
+}
+
+// notifyTransition posts the pass/fail transition from prev to report to --notify-webhook.
+func notifyTransition(log *slog.Logger, prev, report *check.Report) {
+	if err := notify.NotifyOnTransition(checkNotifyWebhook, prev, report); err != nil {
+		log.Warn("could not send check notification", "error", err)
+	}
+}
+
+// pushCheckMetrics sends report to --push-gateway, if set. Failures are only logged: the
+// gateway being down should not turn a passing check into a failing job.
+func pushCheckMetrics(log *slog.Logger, report *check.Report) {
+	if checkPushGateway == "" {
+		return
+	}
+	instance := checkPushInstance
+	if instance == "" {
+		instance, _ = os.Hostname()
+	}
+	if err := metrics.PushMetrics(report, checkPushGateway, checkPushJob, instance); err != nil {
+		log.Warn("could not push check metrics", "gateway", checkPushGateway, "error", err)
+	}
+}
+
+// compareBaseline flags the checks that passed in the saved baseline and now fail, then, with
+// --baseline, saves report as the new baseline (only when the run completed).
+func compareBaseline(log *slog.Logger, report *check.Report, complete bool) {
+	repoRoot, err := resolveRepoRootForFix()
+	if err != nil {
+		return
+	}
+	path := filepath.Join(repoRoot, filepath.FromSlash(check.BaselineReportPath))
+	base, err := check.LoadReport(path)
+	if err != nil {
+		log.Warn("ignoring baseline report", "path", path, "error", err)
+	}
+	report.CompareWithBaseline(base)
+	if !checkBaseline {
+		return
+	}
+	if !complete {
+		fmt.Fprintln(os.Stderr, "Baseline not saved: the run did not complete")
+		return
+	}
+	if err := check.SaveReport(path, report); err != nil {
+		fmt.Fprintf(os.Stderr, "Baseline not saved: %v\n", err)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Saved baseline to %s\n", path)
+}
+
+// clearBaseline implements --clear-baseline.
+func clearBaseline() error {
+	repoRoot, err := resolveRepoRootForFix()
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(repoRoot, filepath.FromSlash(check.BaselineReportPath))
+	if err := os.Remove(path); err != nil {
+		if os.IsNotExist(err) {
+			fmt.Println("No baseline saved.")
+			return nil
+		}
+		return err
+	}
+	fmt.Printf("Removed %s\n", path)
+	return nil
+}
+
+// resolveCheckFormat reconciles --format with the legacy --json flag.
+func resolveCheckFormat() (string, error) {
+	format := strings.ToLower(strings.TrimSpace(checkFormat))
+	switch format {
+	case "", "text":
+		format = "text"
+	case "json", "sarif", "html":
+	default:
+		return "", fmt.Errorf("invalid --format %q (must be text, json, sarif or html)", checkFormat)
+	}
+	if checkJSON {
+		format = "json"
+	}
+	return format, nil
+}
+
+// validateCheckFlags rejects flag combinations before any probe runs; callers exit with
+// exitcodes.ExitPreFlight.
+func validateCheckFlags(format string) error {
+	switch {
+	case checkFixInteractive && !checkFix:
+		return errors.New("--interactive requires --fix")
+	case checkFixDryRun && !checkFix:
+		return errors.New("--dry-run requires --fix")
+	case checkFixSummaryOutput != "" && !checkFix:
+		return errors.New("--summary-output requires --fix")
+	case checkFixAllowPrerelease && !checkFix:
+		return errors.New("--allow-prerelease-restore requires --fix")
+	case checkFixMaxRetries < 1:
+		return errors.New("--max-retries must be at least 1")
+	case checkFixMaxRetries > 1 && !checkFix:
+		return errors.New("--max-retries requires --fix")
+	case checkFixMaxRetries > 1 && checkFixInteractive:
+		return errors.New("--max-retries cannot be combined with --interactive")
+	case checkHTMLOutput != "" && checkFix:
+		return errors.New("--html-output cannot be combined with --fix")
+	case checkSince && checkFix:
+		return errors.New("--since cannot be combined with --fix")
+	case checkFix && format != "text":
+		return fmt.Errorf("--fix cannot be combined with %s output", strings.ToUpper(format))
+	case checkWatch < 0:
+		return errors.New("--watch interval must be positive")
+	case checkWatch > 0 && (checkFix || checkSince || format != "text"):
+		return errors.New("--watch cannot be combined with --fix, --since or --format=" + format)
+	case len(checkItems) > 0 && (checkFix || checkSince):
+		return errors.New("--item cannot be combined with --fix or --since")
+	case checkNotifyWebhook != "" && (checkFix || len(checkItems) > 0):
+		return errors.New("--notify-webhook cannot be combined with --fix or --item")
+	case checkPushGateway != "" && checkFix:
+		return errors.New("--push-gateway cannot be combined with --fix")
+	case checkPushGateway == "" && (checkPushInstance != "" || checkPushJob != metrics.DefaultJob):
+		return errors.New("--push-job and --push-instance require --push-gateway")
+	case checkPushGateway != "" && checkPushJob == "":
+		return errors.New("--push-job must not be empty")
+	case checkSummaryOnly && (checkFix || checkSince || checkWatch > 0 || format != "text"):
+		return errors.New("--summary-only cannot be combined with --fix, --since, --watch or JSON/SARIF/HTML output")
+	case checkBaseline && checkClearBaseline:
+		return errors.New("--baseline cannot be combined with --clear-baseline")
+	case (checkBaseline || checkClearBaseline) && (checkFix || checkSince || checkWatch > 0 || len(checkItems) > 0):
+		return errors.New("--baseline and --clear-baseline cannot be combined with --fix, --since, --watch or --item")
+	case checkCompareTo != "" && (checkFix || checkSince || checkWatch > 0 || checkSummaryOnly):
+		return errors.New("--compare-to cannot be combined with --fix, --since, --watch or --summary-only")
+	case checkCompareTo != "" && format != "text" && format != "json":
+		return fmt.Errorf("--compare-to cannot be combined with %s output", strings.ToUpper(format))
+	case checkConcurrency < 1:
+		return errors.New("--concurrency must be at least 1")
+	}
+	order, err := check.ParseOrderMode(checkOutputOrder)
+	if err != nil {
+		return err
+	}
+	checkOutputOptions.ItemOrder = order
+	return nil
 }
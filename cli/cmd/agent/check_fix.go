@@ -1,16 +1,26 @@
 package main
 
 import (
+	"bufio"
+	"context"
 	"errors"
 	"fmt"
+	"io/fs"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 	"time"
 
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/backup"
 	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/check"
 	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/configmerge"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/exitcodes"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/maputil"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/reporoot"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/status"
 )
 
 type fixSummary struct {
@@ -18,7 +28,54 @@ type fixSummary struct {
 	prefixBackup string
 	sourceBackup string
 	restored     []string
+	skipped      []string
 	warnings     []string
+	dryRun       bool
+	// attempts has one entry per restore cycle that reached the post-fix diagnostics.
+	attempts []check.AttemptRecord
+}
+
+// recordAttempt adds the cycle that restored paths from source and ended with after/afterErr.
+func (s *fixSummary) recordAttempt(source string, paths []string, after *check.Report, afterErr error) {
+	rec := check.AttemptRecord{
+		Attempt:       len(s.attempts) + 1,
+		SourceBackup:  source,
+		RestoredPaths: append([]string{}, paths...),
+		Passed:        !fixFailed(after, afterErr),
+	}
+	if after != nil {
+		rec.AfterFailCount = after.FailCount
+	}
+	if afterErr != nil {
+		rec.Error = afterErr.Error()
+	}
+	s.attempts = append(s.attempts, rec)
+}
+
+// fixFailed reports whether post-fix diagnostics still fail.
+func fixFailed(after *check.Report, afterErr error) bool {
+	return after == nil || afterErr != nil || after.FailCount > 0
+}
+
+// fixWaitPoll is the initial pause between post-restart diagnostics runs.
+const fixWaitPoll = 2 * time.Second
+
+var (
+	errNoBackup      = errors.New("no usable backup files found")
+	errFixPreflight  = errors.New("pre-flight failed")
+	errDryRunRestore = errors.New("dry run")
+)
+
+// fixExitCode maps a runBackupRecovery error to its exitcodes class.
+func fixExitCode(err error) int {
+	switch {
+	case errors.Is(err, errFixPreflight):
+		return exitcodes.ExitPreFlight
+	case errors.Is(err, errNoBackup):
+		return exitcodes.ExitNoBackup
+	default:
+		return exitcodes.ExitRecoveryError
+	}
 }
 
 type backupRestoreResult struct {
@@ -28,10 +85,27 @@ type backupRestoreResult struct {
 	warnings      []string
 }
 
-func runCheckWithFix() (int, error) {
+// runCheckWithFix runs diagnostics, restores from backups and re-checks. With maxRetries > 1,
+// each failed re-check force-restores the next-oldest update-backup set, restarts the core
+// services and re-checks again, up to maxRetries cycles in all.
+func runCheckWithFix(log *slog.Logger, maxRetries int) (exitCode int, err error) {
+	var (
+		summary       *fixSummary
+		before, after *check.Report
+	)
+	if checkFixSummaryOutput != "" {
+		// Resolved now: recovery switches to the repo root.
+		path, absErr := filepath.Abs(checkFixSummaryOutput)
+		if absErr != nil {
+			return exitcodes.ExitPreFlight, fmt.Errorf("invalid --summary-output: %w", absErr)
+		}
+		defer func() { writeFixSummaryFile(log, path, summary, before, after, exitCode, err) }()
+	}
+
 	// 1) Baseline diagnostics first (always show operators what failed before fix).
-	runner := check.NewRunner(verbose, version, buildTime)
-	before, beforeErr := runner.Run()
+	runner := newCheckRunner()
+	runner.Logger = log
+	before, beforeErr := runner.RunWithTimeout(context.Background(), checkTimeout)
 	if before == nil {
 		before = &check.Report{
 			Version:   version,
@@ -52,12 +126,13 @@ func runCheckWithFix() (int, error) {
 			},
 		}
 	}
-	before.OutputText(os.Stdout)
+	before.SlowThreshold = checkSlowThreshold
+	before.OutputText(os.Stdout, checkOutputOptions)
 
 	noIssues := beforeErr == nil && before.FailCount == 0 && before.WarnCount == 0
 	if noIssues {
 		fmt.Println("No issues detected. No recovery actions needed.")
-		return 0, nil
+		return exitcodes.ExitOK, nil
 	}
 
 	fmt.Println("Attempting automatic recovery from recent backups...")
@@ -66,57 +141,121 @@ func runCheckWithFix() (int, error) {
 		printFixSummary(summary)
 	}
 	if fixErr != nil {
-		return 2, fixErr
+		return fixExitCode(fixErr), fixErr
+	}
+	if checkFixDryRun {
+		fmt.Println("Dry run complete: recovery would restore the files above and restart core services.")
+		return exitcodes.ExitOK, nil
 	}
-
-	// Give services a moment to transition after compose restart/up.
-	time.Sleep(2 * time.Second)
 
 	fmt.Println("")
-	fmt.Println("Re-running diagnostics after fix...")
-	after, afterErr := runner.Run()
+	fmt.Printf("Re-running diagnostics after fix (waiting up to %s for services)...\n", checkWaitTimeout)
+	after, afterErr := check.WaitForServicesHealthy(runner, checkTimeout, checkWaitTimeout, fixWaitPoll)
 	if after == nil {
-		return 2, errors.New("post-fix diagnostics failed: report unavailable")
+		return exitcodes.ExitFail, fmt.Errorf("post-fix diagnostics failed: %w", afterErr)
+	}
+	after.SlowThreshold = checkSlowThreshold
+	after.OutputText(os.Stdout, checkOutputOptions)
+	summary.recordAttempt(summary.sourceBackup, summary.restored, after, afterErr)
+
+	for attempt := 2; attempt <= maxRetries && fixFailed(after, afterErr); attempt++ {
+		fmt.Println("")
+		fmt.Printf("Attempt %d/%d: diagnostics still fail; restoring the next-oldest backup set...\n", attempt, maxRetries)
+		source, paths, retryErr := restoreOlderBackup(summary)
+		if source == "" {
+			fmt.Printf("Giving up: %v\n", retryErr)
+			break
+		}
+		fmt.Printf("  Restored from %s: %s\n", source, strings.Join(paths, ", "))
+		if retryErr != nil {
+			summary.recordAttempt(source, paths, nil, retryErr)
+			recordFixHistory(log, summary, before, after)
+			return fixExitCode(retryErr), retryErr
+		}
+		fmt.Printf("Re-running diagnostics (waiting up to %s for services)...\n", checkWaitTimeout)
+		next, nextErr := check.WaitForServicesHealthy(runner, checkTimeout, checkWaitTimeout, fixWaitPoll)
+		if next == nil {
+			summary.recordAttempt(source, paths, nil, nextErr)
+			recordFixHistory(log, summary, before, after)
+			return exitcodes.ExitFail, fmt.Errorf("post-fix diagnostics failed: %w", nextErr)
+		}
+		after, afterErr = next, nextErr
+		after.SlowThreshold = checkSlowThreshold
+		after.OutputText(os.Stdout, checkOutputOptions)
+		summary.recordAttempt(source, paths, after, afterErr)
+	}
+
+	recordFixHistory(log, summary, before, after)
+	if afterErr != nil {
+		fmt.Printf("Note: %v\n", afterErr)
 	}
-	after.OutputText(os.Stdout)
 
 	if afterErr != nil || after.FailCount > 0 {
-		return 2, nil
+		return exitcodes.ExitFail, nil
 	}
 	if after.WarnCount > 0 {
-		return 1, nil
+		return exitcodes.ExitWarn, nil
+	}
+	return exitcodes.ExitOK, nil
+}
+
+// restoreOlderBackup force-restores the newest update-backup set older than
+// summary.sourceBackup that yields a valid core config (every set when the source was not an
+// update-backup set), then restarts the core services. It returns the set used and the paths
+// restored; source is "" when no older set was usable.
+func restoreOlderBackup(summary *fixSummary) (source string, paths []string, err error) {
+	dirs, err := sortedUpdateBackupDirs()
+	if err != nil {
+		return "", nil, err
+	}
+	start := 0
+	for i, dir := range dirs {
+		if dir == summary.sourceBackup {
+			start = i + 1
+		}
 	}
-	return 0, nil
+	restoreBase := shouldRestoreBaseConfig()
+	for _, dir := range dirs[start:] {
+		if warn, skip := skipPrereleaseBackup(dir); skip {
+			summary.warnings = append(summary.warnings, warn)
+			continue
+		}
+		result := restoreFromSingleBackupDir(dir, restoreBase, true, ".")
+		summary.warnings = append(summary.warnings, result.warnings...)
+		if result.restored == 0 || !result.coreRestored {
+			continue
+		}
+		summary.sourceBackup = dir
+		for _, p := range result.restoredPaths {
+			if !slices.Contains(summary.restored, p) {
+				summary.restored = append(summary.restored, p)
+			}
+		}
+		return dir, result.restoredPaths, restartForFix(summary)
+	}
+	return "", nil, errors.New("no older usable update backup set")
 }
 
 func runBackupRecovery() (*fixSummary, error) {
 	repoRoot, err := resolveRepoRootForFix()
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%w: %w", errFixPreflight, err)
 	}
 	if err := os.Chdir(repoRoot); err != nil {
-		return nil, fmt.Errorf("failed to switch to repo root: %w", err)
+		return nil, fmt.Errorf("%w: failed to switch to repo root: %w", errFixPreflight, err)
 	}
 
-	summary := &fixSummary{repoRoot: repoRoot}
-
-	// Safety: snapshot current operator state before touching anything.
-	ts := time.Now().UTC().Format("20060102_150405")
-	prefixBackup := filepath.Join(repoRoot, ".agent", "check-fix-backups", ts)
-	if err := os.MkdirAll(prefixBackup, 0o755); err != nil {
-		return summary, fmt.Errorf("failed to create pre-fix backup directory: %w", err)
+	summary := &fixSummary{repoRoot: repoRoot, dryRun: checkFixDryRun}
+	if checkFixDryRun {
+		dryRunRestored, dryRunFailed = map[string]string{}, nil
+		defer func() { dryRunRestored, dryRunFailed = nil, nil }()
+	} else if err := snapshotBeforeFix(repoRoot, summary); err != nil {
+		return summary, fmt.Errorf("%w: %w", errFixPreflight, err)
 	}
-	summary.prefixBackup = prefixBackup
-	for _, rel := range []string{
-		".env",
-		filepath.Join("config", "ai-agent.yaml"),
-		filepath.Join("config", "ai-agent.local.yaml"),
-		filepath.Join("config", "users.json"),
-		filepath.Join("config", "contexts"),
-	} {
-		if err := backupPathIfExists(rel, prefixBackup); err != nil {
-			return summary, fmt.Errorf("failed to snapshot current state (%s): %w", rel, err)
-		}
+
+	if checkFixInteractive {
+		fixPrompter = &restorePrompter{in: bufio.NewReader(os.Stdin), out: os.Stdout}
+		defer func() { fixPrompter = nil }()
 	}
 
 	restored, source, restoredPaths, warns, err := restoreFromUpdateBackups()
@@ -124,7 +263,7 @@ func runBackupRecovery() (*fixSummary, error) {
 	if err == nil && restored > 0 {
 		summary.sourceBackup = source
 		summary.restored = append(summary.restored, restoredPaths...)
-	} else {
+	} else if fixPrompter == nil || !fixPrompter.aborted {
 		// Fallback to Admin UI style per-file *.bak snapshots when update backups are unavailable.
 		restored, source, restoredPaths, warns, err = restoreFromFileBackups()
 		summary.warnings = append(summary.warnings, warns...)
@@ -133,88 +272,204 @@ func runBackupRecovery() (*fixSummary, error) {
 			summary.restored = append(summary.restored, restoredPaths...)
 		}
 	}
+	if fixPrompter != nil {
+		summary.skipped = append(summary.skipped, fixPrompter.skipped...)
+		if fixPrompter.aborted {
+			return summary, errRecoveryAborted
+		}
+	}
 	if err != nil {
 		return summary, err
 	}
 
 	if len(summary.restored) == 0 {
-		return summary, errors.New("no restorable backup files found")
+		return summary, errNoBackup
 	}
 
-	if err := restartCoreServices(); err != nil {
+	if checkFixDryRun {
+		// The real run would restore the rest and carry on, but report the failures the same
+		// way a failed recovery is.
+		if len(dryRunFailed) > 0 {
+			return summary, fmt.Errorf("%w: %d restore(s) would fail: %s", errDryRunRestore, len(dryRunFailed), strings.Join(dryRunFailed, ", "))
+		}
+		return summary, nil
+	}
+	if err := restartForFix(summary); err != nil {
 		return summary, err
 	}
 	return summary, nil
 }
 
-func resolveRepoRootForFix() (string, error) {
-	root, err := gitShowTopLevel()
-	if err == nil && strings.TrimSpace(root) != "" {
-		return root, nil
+// restartForFix restarts the core services and waits for their healthchecks, so the
+// diagnostics that follow see the restarted services. Services that are still unhealthy are
+// only a warning here: those diagnostics decide whether the fix worked.
+func restartForFix(summary *fixSummary) error {
+	if err := restartCoreServices(); err != nil {
+		return err
 	}
-	wd, wdErr := os.Getwd()
-	if wdErr != nil {
-		return "", fmt.Errorf("unable to resolve repository root: %w", wdErr)
+	ctx, cancel := context.WithTimeout(context.Background(), status.MaxHealthWait)
+	defer cancel()
+	if err := status.WaitForDockerServiceHealthy(ctx, coreServices, 2*time.Second); err != nil {
+		if !errors.Is(err, status.ErrNotHealthy) {
+			return err
+		}
+		summary.warnings = append(summary.warnings, err.Error())
 	}
-	return wd, nil
+	return nil
 }
 
-func restoreFromUpdateBackups() (int, string, []string, []string, error) {
-	backupRoot := filepath.Join(".agent", "update-backups")
-	entries, err := os.ReadDir(backupRoot)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return 0, "", nil, nil, errors.New("no update backup directories found")
-		}
-		return 0, "", nil, nil, fmt.Errorf("failed to read update backup root: %w", err)
+// operatorConfigPaths are the operator-owned files (relative to the repo root) covered by
+// pre-fix snapshots and config exports, for the active --env. Contexts are shared by all
+// environments.
+func operatorConfigPaths() []string {
+	return []string{
+		envRel(".env"),
+		envRel(filepath.Join("config", "ai-agent.yaml")),
+		envRel(filepath.Join("config", "ai-agent.local.yaml")),
+		envRel(filepath.Join("config", "users.json")),
+		filepath.Join("config", "contexts"),
 	}
+}
 
-	type dirInfo struct {
-		path string
-		mt   time.Time
+// snapshotBeforeFix copies the current operator state into .agent/check-fix-backups/<ts> so a
+// recovery can always be undone.
+func snapshotBeforeFix(repoRoot string, summary *fixSummary) error {
+	ts := time.Now().UTC().Format("20060102_150405")
+	prefixBackup := filepath.Join(checkFixBackupRoot(repoRoot), ts)
+	if err := os.MkdirAll(prefixBackup, 0o755); err != nil {
+		return fmt.Errorf("failed to create pre-fix backup directory: %w", err)
 	}
-	dirs := make([]dirInfo, 0, len(entries))
-	for _, e := range entries {
-		if !e.IsDir() {
-			continue
+	summary.prefixBackup = prefixBackup
+	for _, rel := range operatorConfigPaths() {
+		if err := backupPathIfExists(rel, prefixBackup); err != nil {
+			return fmt.Errorf("failed to snapshot current state (%s): %w", rel, err)
 		}
-		full := filepath.Join(backupRoot, e.Name())
-		info, statErr := os.Stat(full)
-		if statErr != nil {
-			continue
+	}
+	if err := backup.WriteManifest(prefixBackup); err != nil {
+		return fmt.Errorf("failed to write pre-fix snapshot manifest: %w", err)
+	}
+	if err := encryptNewBackup(repoRoot, prefixBackup); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// resolveRepoRootForFix returns --repo-root when given, else the git top-level if it has the
+// reporoot markers (a Compose file and config/ai-agent.yaml), else the nearest directory up
+// from the working directory with them. It never falls back to the working directory itself:
+// commands write backups and config under the root, which must not land in an unrelated
+// directory (such as a dotfiles repo the checkout happens to sit in).
+func resolveRepoRootForFix() (string, error) {
+	if override := strings.TrimSpace(repoRootOverride); override != "" {
+		abs, err := filepath.Abs(override)
+		if err != nil {
+			return "", fmt.Errorf("invalid --repo-root: %w", err)
 		}
-		dirs = append(dirs, dirInfo{path: full, mt: info.ModTime()})
+		if info, err := os.Stat(abs); err != nil || !info.IsDir() {
+			return "", fmt.Errorf("--repo-root %s is not a directory", override)
+		}
+		return abs, nil
 	}
-	if len(dirs) == 0 {
-		return 0, "", nil, nil, errors.New("no update backup directories found")
+	root, gitErr := gitShowTopLevel()
+	if gitErr == nil && strings.TrimSpace(root) != "" {
+		if reporoot.IsRepoRoot(root) {
+			return root, nil
+		}
+		gitErr = fmt.Errorf("git top-level %s has no %s and %s", root, reporoot.ComposeFiles[0], reporoot.ConfigFile)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("unable to resolve repository root: %w", err)
+	}
+	root, err = reporoot.Find(wd)
+	if err != nil {
+		return "", fmt.Errorf("unable to resolve repository root (git: %v; %w); run agent inside the Asterisk-AI-Voice-Agent checkout or pass --repo-root", gitErr, err)
+	}
+	return root, nil
+}
+
+func restoreFromUpdateBackups() (int, string, []string, []string, error) {
+	dirs, err := sortedUpdateBackupDirs()
+	if err != nil {
+		return 0, "", nil, nil, err
 	}
-	sort.Slice(dirs, func(i, j int) bool { return dirs[i].mt.After(dirs[j].mt) })
 
 	var warnings []string
 	restoreBase := shouldRestoreBaseConfig()
-	for _, candidate := range dirs {
-		result := restoreFromSingleBackupDir(candidate.path, restoreBase)
+	for _, dir := range dirs {
+		if warn, skip := skipPrereleaseBackup(dir); skip {
+			warnings = append(warnings, warn)
+			continue
+		}
+		result := restoreFromSingleBackupDir(dir, restoreBase, false, ".")
 		warnings = append(warnings, result.warnings...)
 		if result.restored == 0 {
 			continue
 		}
 		if result.coreRestored {
-			return result.restored, candidate.path, result.restoredPaths, warnings, nil
+			return result.restored, dir, result.restoredPaths, warnings, nil
 		}
 	}
 	return 0, "", nil, warnings, errors.New("no usable update backup directory found")
 }
 
-func restoreFromSingleBackupDir(backupDir string, restoreBase bool) backupRestoreResult {
+// skipPrereleaseBackup reports whether --fix must pass over dir because it was taken before an
+// update to pre-release images (agent update --prerelease), and the warning to show. Such sets
+// are only used with --allow-prerelease-restore.
+func skipPrereleaseBackup(dir string) (string, bool) {
+	meta, err := backup.ReadMetadata(dir)
+	if err != nil {
+		return fmt.Sprintf("Skipped %s: %v", dir, err), true
+	}
+	if !meta.Prerelease || checkFixAllowPrerelease {
+		return "", false
+	}
+	return fmt.Sprintf("Skipped %s: taken before a pre-release update (pass --allow-prerelease-restore to use it)", dir), true
+}
+
+// sortedUpdateBackupDirs lists the update-backup sets, newest first. The cwd is the repo root.
+func sortedUpdateBackupDirs() ([]string, error) {
+	dirs, err := backup.BackupDirs(updateBackupRoot("."))
+	if err != nil {
+		return nil, err
+	}
+	if len(dirs) == 0 {
+		return nil, errors.New("no update backup directories found")
+	}
+	return dirs, nil
+}
+
+// restoreFromSingleBackupDir restores broken operator files from backupDir into destRoot ("."
+// for the live repo root, the working directory). With force (used by `agent rollback`), every
+// valid file in the backup is restored even if the destination copy is valid. restoredPaths
+// stay relative to destRoot.
+func restoreFromSingleBackupDir(backupDir string, restoreBase bool, force bool, destRoot string) backupRestoreResult {
 	result := backupRestoreResult{}
+	dst := func(rel string) string { return filepath.Join(destRoot, rel) }
 
-	needEnv := !fileValid(".env", validateEnvBackup)
-	needLocal := !fileValid(filepath.Join("config", "ai-agent.local.yaml"), validateYAMLMappingBackup)
-	needBase := restoreBase && !fileValid(filepath.Join("config", "ai-agent.yaml"), validateYAMLMappingBackup)
+	// Never restore from a backup whose contents no longer match its manifest.
+	if err := verifyBackupManifest(backupDir); err != nil {
+		result.warnings = append(result.warnings, fmt.Sprintf("Skipped %s: %v", backupDir, err))
+		return result
+	}
+	// Encrypted sets are restored from a decrypted temp copy; messages keep naming backupDir.
+	srcDir, cleanup, err := openBackupDir(".", backupDir)
+	if err != nil {
+		result.warnings = append(result.warnings, fmt.Sprintf("Skipped %s: %v", backupDir, err))
+		return result
+	}
+	defer cleanup()
 
-	backupEnvOK := backupFileValid(backupDir, ".env", validateEnvBackup)
-	backupLocalOK := backupFileValid(backupDir, filepath.Join("config", "ai-agent.local.yaml"), validateYAMLMappingBackup)
-	backupBaseOK := backupFileValid(backupDir, filepath.Join("config", "ai-agent.yaml"), validateYAMLMappingBackup)
+	backupEnvOK := backupFileValid(srcDir, envRel(".env"), validateEnvBackup)
+	backupLocalOK := backupFileValid(srcDir, envRel(filepath.Join("config", "ai-agent.local.yaml")), validateYAMLMappingBackup)
+	backupBaseOK := backupFileValid(srcDir, envRel(filepath.Join("config", "ai-agent.yaml")), validateYAMLMappingBackup)
+	backupUsersOK := backupFileValid(srcDir, envRel(filepath.Join("config", "users.json")), validateUsersJSON)
+
+	needEnv := !fileValid(dst(envRel(".env")), validateEnvBackup) || (force && backupEnvOK)
+	needLocal := !fileValid(dst(envRel(filepath.Join("config", "ai-agent.local.yaml"))), validateYAMLMappingBackup) || (force && backupLocalOK)
+	needBase := restoreBase && (!fileValid(dst(envRel(filepath.Join("config", "ai-agent.yaml"))), validateYAMLMappingBackup) || (force && backupBaseOK))
+	needUsers := !fileValid(dst(envRel(filepath.Join("config", "users.json"))), validateUsersJSON) || (force && backupUsersOK)
 
 	envOkAfter := !needEnv || backupEnvOK
 	localOkAfter := !needLocal || backupLocalOK
@@ -229,7 +484,7 @@ func restoreFromSingleBackupDir(backupDir string, restoreBase bool) backupRestor
 		if !allow {
 			return
 		}
-		src := filepath.Join(backupDir, rel)
+		src := filepath.Join(srcDir, rel)
 		if _, err := os.Stat(src); err != nil {
 			return
 		}
@@ -239,7 +494,10 @@ func restoreFromSingleBackupDir(backupDir string, restoreBase bool) backupRestor
 				return
 			}
 		}
-		if err := copyFile(src, rel); err != nil {
+		if !confirmRestore(src, dst(rel)) {
+			return
+		}
+		if err := restoreCopyFile(src, dst(rel)); err != nil {
 			result.warnings = append(result.warnings, fmt.Sprintf("Failed to restore %s from %s: %v", rel, backupDir, err))
 			return
 		}
@@ -247,34 +505,44 @@ func restoreFromSingleBackupDir(backupDir string, restoreBase bool) backupRestor
 		result.restoredPaths = append(result.restoredPaths, rel)
 	}
 
-	restoreFile(".env", validateEnvBackup, needEnv)
-	restoreFile(filepath.Join("config", "ai-agent.local.yaml"), validateYAMLMappingBackup, needLocal)
-	restoreFile(filepath.Join("config", "ai-agent.yaml"), validateYAMLMappingBackup, needBase)
-	restoreFile(filepath.Join("config", "users.json"), nil, !fileExists(filepath.Join("config", "users.json")))
+	restoreFile(envRel(".env"), validateEnvBackup, needEnv)
+	restoreFile(envRel(filepath.Join("config", "ai-agent.local.yaml")), validateYAMLMappingBackup, needLocal)
+	restoreFile(envRel(filepath.Join("config", "ai-agent.yaml")), validateYAMLMappingBackup, needBase)
+	restoreFile(envRel(filepath.Join("config", "users.json")), validateUsersJSON, needUsers)
 
-	srcCtx := filepath.Join(backupDir, "config", "contexts")
+	srcCtx := filepath.Join(srcDir, "config", "contexts")
 	if info, err := os.Stat(srcCtx); err == nil && info.IsDir() {
-		dstCtx := filepath.Join("config", "contexts")
+		dstCtx := dst(filepath.Join("config", "contexts"))
 		restoreContextsAtomic(srcCtx, dstCtx, &result)
 	}
 
-	result.coreRestored = fileValid(".env", validateEnvBackup) &&
-		(fileValid(filepath.Join("config", "ai-agent.local.yaml"), validateYAMLMappingBackup) ||
-			fileValid(filepath.Join("config", "ai-agent.yaml"), validateYAMLMappingBackup))
+	result.coreRestored = fileValid(dst(envRel(".env")), validateEnvBackup) &&
+		(fileValid(dst(envRel(filepath.Join("config", "ai-agent.local.yaml"))), validateYAMLMappingBackup) ||
+			fileValid(dst(envRel(filepath.Join("config", "ai-agent.yaml"))), validateYAMLMappingBackup))
 	return result
 }
 
+// verifyBackupManifest checks backupDir against its manifest.sha256. Backups created before
+// manifests were introduced have no manifest and are accepted as-is.
+func verifyBackupManifest(dir string) error {
+	err := backup.VerifyManifest(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
 func restoreFromFileBackups() (int, string, []string, []string, error) {
 	var warnings []string
 	restored := 0
 	var restoredPaths []string
 	sources := map[string]bool{}
 
-	needEnv := !fileValid(".env", validateEnvBackup)
-	needLocal := !fileValid(filepath.Join("config", "ai-agent.local.yaml"), validateYAMLMappingBackup)
+	needEnv := !fileValid(envRel(".env"), validateEnvBackup)
+	needLocal := !fileValid(envRel(filepath.Join("config", "ai-agent.local.yaml")), validateYAMLMappingBackup)
 	restoreBase := shouldRestoreBaseConfig()
-	needBase := restoreBase && !fileValid(filepath.Join("config", "ai-agent.yaml"), validateYAMLMappingBackup)
-	needUsers := !fileExists(filepath.Join("config", "users.json"))
+	needBase := restoreBase && !fileValid(envRel(filepath.Join("config", "ai-agent.yaml")), validateYAMLMappingBackup)
+	needUsers := !fileValid(envRel(filepath.Join("config", "users.json")), validateUsersJSON)
 
 	findLatestValidated := func(rel string, pattern string, validate func(string) error) string {
 		src, err := latestBackupMatch(pattern)
@@ -296,33 +564,36 @@ func restoreFromFileBackups() (int, string, []string, []string, error) {
 
 	envSrc := ""
 	if needEnv {
-		envSrc = findLatestValidated(".env", ".env.bak.*", validateEnvBackup)
+		envSrc = findLatestValidated(envRel(".env"), envRel(".env")+".bak.*", validateEnvBackup)
 	}
 	localSrc := ""
 	if needLocal {
-		localSrc = findLatestValidated(filepath.Join("config", "ai-agent.local.yaml"), filepath.Join("config", "ai-agent.local.yaml.bak.*"), validateYAMLMappingBackup)
+		localSrc = findLatestValidated(envRel(filepath.Join("config", "ai-agent.local.yaml")), envRel(filepath.Join("config", "ai-agent.local.yaml"))+".bak.*", validateYAMLMappingBackup)
 	}
 	baseSrc := ""
 	if needBase {
-		baseSrc = findLatestValidated(filepath.Join("config", "ai-agent.yaml"), filepath.Join("config", "ai-agent.yaml.bak.*"), validateYAMLMappingBackup)
+		baseSrc = findLatestValidated(envRel(filepath.Join("config", "ai-agent.yaml")), envRel(filepath.Join("config", "ai-agent.yaml"))+".bak.*", validateYAMLMappingBackup)
 	}
 	usersSrc := ""
 	if needUsers {
-		usersSrc = findLatestValidated(filepath.Join("config", "users.json"), filepath.Join("config", "users.json.bak.*"), nil)
+		usersSrc = findLatestValidated(envRel(filepath.Join("config", "users.json")), envRel(filepath.Join("config", "users.json"))+".bak.*", validateUsersJSON)
 	}
 
 	envOkAfter := !needEnv || envSrc != ""
 	localOkAfter := !needLocal || localSrc != ""
 	baseOkAfter := !needBase || baseSrc != ""
 	if !envOkAfter || !(localOkAfter || baseOkAfter) {
-		return 0, "", nil, warnings, errors.New("no usable backup files found (missing core files)")
+		return 0, "", nil, warnings, fmt.Errorf("%w (missing core files)", errNoBackup)
 	}
 
 	restoreFromSrc := func(src string, rel string) {
 		if src == "" {
 			return
 		}
-		if err := copyFile(src, rel); err != nil {
+		if !confirmRestore(src, rel) {
+			return
+		}
+		if err := restoreCopyFile(src, rel); err != nil {
 			warnings = append(warnings, fmt.Sprintf("Failed to restore %s from %s: %v", rel, src, err))
 			return
 		}
@@ -331,25 +602,93 @@ func restoreFromFileBackups() (int, string, []string, []string, error) {
 		sources[filepath.Dir(src)] = true
 	}
 
-	restoreFromSrc(envSrc, ".env")
-	restoreFromSrc(localSrc, filepath.Join("config", "ai-agent.local.yaml"))
-	restoreFromSrc(baseSrc, filepath.Join("config", "ai-agent.yaml"))
-	restoreFromSrc(usersSrc, filepath.Join("config", "users.json"))
+	restoreFromSrc(envSrc, envRel(".env"))
+	restoreFromSrc(localSrc, envRel(filepath.Join("config", "ai-agent.local.yaml")))
+	restoreFromSrc(baseSrc, envRel(filepath.Join("config", "ai-agent.yaml")))
+	restoreFromSrc(usersSrc, envRel(filepath.Join("config", "users.json")))
 
 	if restored == 0 {
-		return 0, "", nil, warnings, errors.New("no usable backup files found")
+		return 0, "", nil, warnings, errNoBackup
 	}
-	if !(fileValid(".env", validateEnvBackup) &&
-		(fileValid(filepath.Join("config", "ai-agent.local.yaml"), validateYAMLMappingBackup) ||
-			fileValid(filepath.Join("config", "ai-agent.yaml"), validateYAMLMappingBackup))) {
-		return 0, "", nil, warnings, errors.New("no usable backup files found (missing core files)")
+	if !(fileValid(envRel(".env"), validateEnvBackup) &&
+		(fileValid(envRel(filepath.Join("config", "ai-agent.local.yaml")), validateYAMLMappingBackup) ||
+			fileValid(envRel(filepath.Join("config", "ai-agent.yaml")), validateYAMLMappingBackup))) {
+		return 0, "", nil, warnings, fmt.Errorf("%w (missing core files)", errNoBackup)
 	}
-	sourceList := sortedKeys(sources)
+	sourceList := maputil.SortedKeys(sources)
 	return restored, strings.Join(sourceList, ", "), restoredPaths, warnings, nil
 }
 
+// dryRunRestored maps live paths to the backup they would have been restored from during
+// `agent check --fix --dry-run`, so post-restore validity checks see the planned state.
+// dryRunFailed lists the paths whose restore would fail.
+var (
+	dryRunRestored map[string]string
+	dryRunFailed   []string
+)
+
+// restoreCopyFile restores src over dst, or under --dry-run checks that it could and reports
+// the intent.
+func restoreCopyFile(src string, dst string) error {
+	if dryRunRestored != nil {
+		if err := probeRestore(src, dst); err != nil {
+			dryRunFailed = append(dryRunFailed, dst)
+			return err
+		}
+		fmt.Printf("[dry-run] would restore %s -> %s\n", src, dst)
+		dryRunRestored[dst] = src
+		return nil
+	}
+	return copyFile(src, dst)
+}
+
+// probeRestore checks, without writing anything, that src can be read and dst written: an
+// existing dst file must open for writing (a directory replaced by a directory is renamed
+// away), a missing one needs its nearest existing parent to be a directory.
+func probeRestore(src string, dst string) error {
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	srcInfo, err := f.Stat()
+	_ = f.Close()
+	if err != nil {
+		return err
+	}
+	if info, err := os.Stat(dst); err == nil {
+		if info.IsDir() && srcInfo.IsDir() {
+			return nil
+		}
+		f, err := os.OpenFile(dst, os.O_WRONLY, 0)
+		if err != nil {
+			return err
+		}
+		return f.Close()
+	}
+	for dir := filepath.Dir(dst); ; dir = filepath.Dir(dir) {
+		info, err := os.Stat(dir)
+		if err == nil {
+			if !info.IsDir() {
+				return fmt.Errorf("%s is not a directory", dir)
+			}
+			return nil
+		}
+		if parent := filepath.Dir(dir); parent == dir {
+			return err
+		}
+	}
+}
+
+// effectivePath returns the file that would be live at rel after a dry-run restore.
+func effectivePath(rel string) string {
+	if src, ok := dryRunRestored[rel]; ok {
+		return src
+	}
+	return rel
+}
+
 func fileExists(rel string) bool {
-	_, err := os.Stat(rel)
+	_, err := os.Stat(effectivePath(rel))
 	return err == nil
 }
 
@@ -357,10 +696,11 @@ func fileValid(rel string, validate func(string) error) bool {
 	if validate == nil {
 		return fileExists(rel)
 	}
-	if _, err := os.Stat(rel); err != nil {
+	path := effectivePath(rel)
+	if _, err := os.Stat(path); err != nil {
 		return false
 	}
-	return validate(rel) == nil
+	return validate(path) == nil
 }
 
 func backupFileValid(backupDir string, rel string, validate func(string) error) bool {
@@ -375,8 +715,23 @@ func backupFileValid(backupDir string, rel string, validate func(string) error)
 }
 
 func restoreContextsAtomic(srcCtx string, dstCtx string, result *backupRestoreResult) {
-	tmpCtx := filepath.Join("config", fmt.Sprintf(".contexts.restore.tmp.%d", time.Now().UnixNano()))
-	backupCtx := filepath.Join("config", fmt.Sprintf("contexts.pre_restore.%d", time.Now().UnixNano()))
+	if !confirmRestore(srcCtx, dstCtx) {
+		return
+	}
+	if dryRunRestored != nil {
+		if err := probeRestore(srcCtx, dstCtx); err != nil {
+			dryRunFailed = append(dryRunFailed, dstCtx)
+			result.warnings = append(result.warnings, fmt.Sprintf("Failed to restore config/contexts from %s: %v", srcCtx, err))
+			return
+		}
+		fmt.Printf("[dry-run] would restore %s -> %s\n", srcCtx, dstCtx)
+		dryRunRestored[dstCtx] = srcCtx
+		result.restored++
+		result.restoredPaths = append(result.restoredPaths, dstCtx)
+		return
+	}
+	tmpCtx := filepath.Join(filepath.Dir(dstCtx), fmt.Sprintf(".contexts.restore.tmp.%d", time.Now().UnixNano()))
+	backupCtx := filepath.Join(filepath.Dir(dstCtx), fmt.Sprintf("contexts.pre_restore.%d", time.Now().UnixNano()))
 
 	if err := copyDir(srcCtx, tmpCtx); err != nil {
 		result.warnings = append(result.warnings, fmt.Sprintf("Failed to stage config/contexts restore from %s: %v", srcCtx, err))
@@ -434,88 +789,42 @@ func latestBackupMatch(pattern string) (string, error) {
 	return infos[0].path, nil
 }
 
-func validateYAMLMappingBackup(path string) error {
-	if hasConflictMarkers(path) {
-		return errors.New("contains git conflict markers")
-	}
-	if _, err := configmerge.ReadYAMLFile(path); err != nil {
-		return fmt.Errorf("invalid YAML mapping: %w", err)
-	}
-	return nil
-}
-
-func validateEnvBackup(path string) error {
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return err
-	}
-	content := string(data)
-	if strings.TrimSpace(content) == "" {
-		return errors.New("empty env file")
-	}
-	// Keep this lightweight: for recovery we only require core ARI keys.
-	if !strings.Contains(content, "ASTERISK_HOST=") || !strings.Contains(content, "ASTERISK_ARI_USERNAME=") {
-		return errors.New("missing core ARI keys")
-	}
-	return nil
-}
-
-func hasConflictMarkers(path string) bool {
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return false
-	}
-	hasOpen := false
-	hasSep := false
-	hasClose := false
-	for _, line := range strings.Split(string(data), "\n") {
-		trimmed := strings.TrimSpace(line)
-		if strings.HasPrefix(trimmed, "<<<<<<<") {
-			hasOpen = true
-		} else if strings.HasPrefix(trimmed, "=======") {
-			hasSep = true
-		} else if strings.HasPrefix(trimmed, ">>>>>>>") {
-			hasClose = true
-		}
-
-		// Only signal a conflict when the marker pattern is plausibly present.
-		// A standalone "=======" line can occur legitimately (e.g., separators in YAML),
-		// but Git conflicts require marker combinations.
-		if hasOpen && (hasSep || hasClose) {
-			return true
-		}
-		if hasSep && hasClose {
-			return true
-		}
-	}
-	return false
-}
+// Validators are shared with `agent config validate --all` and live in internal/check.
+var (
+	validateYAMLMappingBackup = check.ValidateYAMLMapping
+	validateEnvBackup         = check.ValidateEnv
+	hasConflictMarkers        = check.HasConflictMarkers
+	validateUsersJSON         = check.ValidateUsersJSON
+)
 
 func shouldRestoreBaseConfig() bool {
-	base := filepath.Join("config", "ai-agent.yaml")
+	base := envRel(filepath.Join("config", "ai-agent.yaml"))
 	if _, err := os.Stat(base); err != nil {
 		return true
 	}
 	if hasConflictMarkers(base) {
 		return true
 	}
-	if _, err := configmerge.ReadYAMLFile(base); err != nil {
+	if _, err := configmerge.ReadYAMLFileDecrypted(base); err != nil {
 		return true
 	}
 	return false
 }
 
+// coreServices are the Compose services restartCoreServices restarts.
+var coreServices = []string{"ai_engine", "admin_ui"}
+
 func restartCoreServices() error {
 	if _, err := runCmd("docker", "compose", "version"); err != nil {
 		return fmt.Errorf("docker compose unavailable: %w", err)
 	}
 
-	if _, err := runCmd("docker", "compose", "up", "-d", "--no-build", "ai_engine", "admin_ui"); err == nil {
+	if _, err := runCmd("docker", append([]string{"compose", "up", "-d", "--no-build"}, coreServices...)...); err == nil {
 		return nil
 	}
 
 	// Fallback path: restart each service and attempt up if restart fails.
-	for _, svc := range []string{"ai_engine", "admin_ui"} {
+	for _, svc := range coreServices {
 		if _, err := runCmd("docker", "compose", "restart", svc); err != nil {
 			if _, err2 := runCmd("docker", "compose", "up", "-d", "--no-build", svc); err2 != nil {
 				return fmt.Errorf("failed to restart %s (restart error: %v; up error: %w)", svc, err, err2)
@@ -525,18 +834,80 @@ func restartCoreServices() error {
 	return nil
 }
 
+// recordFixHistory appends the applied recovery and the reports around it to
+// .agent/fix-history.jsonl for agent fix history. Failures only cost the audit entry.
+func recordFixHistory(log *slog.Logger, summary *fixSummary, before, after *check.Report) {
+	path := filepath.Join(summary.repoRoot, filepath.FromSlash(check.FixHistoryPath))
+	err := check.AppendFixRecord(path, check.FixRecord{
+		Timestamp:     time.Now().UTC(),
+		RepoRoot:      summary.repoRoot,
+		PrefixBackup:  summary.prefixBackup,
+		SourceBackup:  summary.sourceBackup,
+		RestoredPaths: summary.restored,
+		SkippedPaths:  summary.skipped,
+		Warnings:      summary.warnings,
+		Attempts:      summary.attempts,
+		Before:        before,
+		After:         after,
+	})
+	if err != nil {
+		log.Warn("could not record fix history", "path", path, "error", err)
+	}
+}
+
+// writeFixSummaryFile writes the --summary-output file for a --fix run that ended with
+// exitCode and err. summary and after are nil when the run stopped before recovery or before
+// the post-fix diagnostics.
+func writeFixSummaryFile(log *slog.Logger, path string, summary *fixSummary, before, after *check.Report, exitCode int, err error) {
+	out := check.FixSummary{
+		Timestamp:    time.Now().UTC(),
+		ExitCode:     exitCode,
+		BeforeReport: before,
+		AfterReport:  after,
+	}
+	if summary != nil {
+		out.RepoRoot = summary.repoRoot
+		out.PrefixBackup = summary.prefixBackup
+		out.SourceBackup = summary.sourceBackup
+		out.Restored = summary.restored
+		out.Skipped = summary.skipped
+		out.Warnings = summary.warnings
+		out.DryRun = summary.dryRun
+		out.Attempts = summary.attempts
+	}
+	if err != nil {
+		out.Error = err.Error()
+	}
+	if err := check.WriteFixSummary(path, out); err != nil {
+		log.Warn("could not write fix summary", "path", path, "error", err)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Wrote recovery summary to %s\n", path)
+}
+
 func printFixSummary(summary *fixSummary) {
 	fmt.Println("")
-	fmt.Println("Recovery summary")
+	if summary.dryRun {
+		fmt.Println("Recovery summary (dry-run: no files written)")
+	} else {
+		fmt.Println("Recovery summary")
+	}
 	fmt.Printf("  Repo root: %s\n", summary.repoRoot)
 	if summary.prefixBackup != "" {
 		fmt.Printf("  Pre-fix snapshot: %s\n", summary.prefixBackup)
 	}
+	verb := "Restored"
+	if summary.dryRun {
+		verb = "Would restore"
+	}
 	if summary.sourceBackup != "" {
-		fmt.Printf("  Restored from: %s\n", summary.sourceBackup)
+		fmt.Printf("  %s from: %s\n", verb, summary.sourceBackup)
 	}
 	if len(summary.restored) > 0 {
-		fmt.Printf("  Restored paths: %s\n", strings.Join(summary.restored, ", "))
+		fmt.Printf("  %s paths: %s\n", verb, strings.Join(summary.restored, ", "))
+	}
+	if len(summary.skipped) > 0 {
+		fmt.Printf("  Skipped (operator declined): %s\n", strings.Join(summary.skipped, ", "))
 	}
 	for _, w := range summary.warnings {
 		fmt.Printf("  Warning: %s\n", w)
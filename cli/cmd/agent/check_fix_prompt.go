@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/backup"
+)
+
+var errRecoveryAborted = errors.New("recovery aborted by operator")
+
+// restorePrompter asks the operator before each overwrite during `agent check --fix --interactive`.
+type restorePrompter struct {
+	in      *bufio.Reader
+	out     io.Writer
+	skipped []string
+	aborted bool
+}
+
+// fixPrompter is non-nil only while an interactive recovery is in progress.
+var fixPrompter *restorePrompter
+
+// confirmRestore reports whether src may be restored over dst. It always returns true in
+// non-interactive mode so the default recovery path is unchanged.
+func confirmRestore(src string, dst string) bool {
+	p := fixPrompter
+	if p == nil {
+		return true
+	}
+	if p.aborted {
+		return false
+	}
+
+	fmt.Fprintf(p.out, "\n--- %s (live)\n+++ %s (backup)\n", dst, src)
+	fmt.Fprint(p.out, unifiedDiff(dst, src))
+	for {
+		fmt.Fprintf(p.out, "Restore %s? [y/n/q]: ", dst)
+		line, err := p.in.ReadString('\n')
+		answer := strings.ToLower(strings.TrimSpace(line))
+		switch answer {
+		case "y", "yes":
+			return true
+		case "n", "no":
+			p.skipped = append(p.skipped, dst)
+			return false
+		case "q", "quit":
+			p.aborted = true
+			return false
+		}
+		if err != nil {
+			// stdin closed: treat as quit rather than looping forever.
+			p.aborted = true
+			return false
+		}
+	}
+}
+
+// unifiedDiff renders `diff -u` (or `diff -ruN` for directories) between the live path and the
+// backup candidate. A missing live path is diffed against an empty file.
+func unifiedDiff(live string, candidate string) string {
+	if info, err := os.Stat(candidate); err == nil && info.IsDir() {
+		if _, err := os.Stat(live); err != nil {
+			return "(live directory missing; backup would be restored as-is)\n"
+		}
+	} else if _, err := os.Stat(live); err != nil {
+		live = os.DevNull
+	}
+	out, err := backup.UnifiedDiff(live, candidate, "", "")
+	if errors.Is(err, exec.ErrNotFound) {
+		return "(diff not available; showing paths only)\n"
+	}
+	if err != nil {
+		return fmt.Sprintf("(%v)\n", err)
+	}
+	if out == "" {
+		return "(no differences)\n"
+	}
+	return out
+}
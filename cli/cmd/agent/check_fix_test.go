@@ -0,0 +1,201 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/backup"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/exitcodes"
+)
+
+// fixRepo returns a repo root whose .env and config/ai-agent.yaml are missing, with one
+// update-backup set holding valid copies and a users.json. The working directory and
+// --repo-root are restored when the test ends.
+func fixRepo(t *testing.T) (root, set string) {
+	t.Helper()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	origRoot, origDryRun := repoRootOverride, checkFixDryRun
+	t.Cleanup(func() {
+		_ = os.Chdir(wd)
+		repoRootOverride, checkFixDryRun = origRoot, origDryRun
+	})
+
+	root = t.TempDir()
+	set = filepath.Join(root, ".agent", "update-backups", "20260101_000000")
+	writeTestFile(t, filepath.Join(set, ".env"), "ASTERISK_HOST=pbx\nASTERISK_ARI_USERNAME=ari\n")
+	writeTestFile(t, filepath.Join(set, "config", "ai-agent.yaml"), "default_provider: openai_realtime\n")
+	writeTestFile(t, filepath.Join(set, "config", "users.json"), `{"admin": {"username": "admin", "hashed_password": "x"}}`)
+	if err := backup.WriteManifest(set); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(root, "config"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	repoRootOverride = root
+	return root, set
+}
+
+func writeTestFile(t *testing.T, path, data string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// treeFiles lists every path under root with its content, to compare before and after.
+func treeFiles(t *testing.T, root string) map[string]string {
+	t.Helper()
+	out := map[string]string{}
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(root, path)
+		if d.IsDir() {
+			out[rel] = "<dir>"
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		out[rel] = string(data)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+func TestFixDryRunWritesNothing(t *testing.T) {
+	root, _ := fixRepo(t)
+	checkFixDryRun = true
+	before := treeFiles(t, root)
+
+	summary, err := runBackupRecovery()
+	if err != nil {
+		t.Fatalf("runBackupRecovery: %v", err)
+	}
+	if len(summary.restored) != 3 || summary.prefixBackup != "" {
+		t.Fatalf("summary = %+v", summary)
+	}
+	after := treeFiles(t, root)
+	if len(after) != len(before) {
+		t.Fatalf("dry run changed the tree:\nbefore %v\nafter  %v", before, after)
+	}
+	for rel, data := range before {
+		if after[rel] != data {
+			t.Fatalf("dry run changed %s", rel)
+		}
+	}
+	if _, err := os.Stat(checkFixBackupRoot(root)); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("dry run took a pre-fix snapshot: %v", err)
+	}
+}
+
+func TestFixDryRunExitCodes(t *testing.T) {
+	t.Run("restore would fail", func(t *testing.T) {
+		root, _ := fixRepo(t)
+		checkFixDryRun = true
+		// A directory where users.json goes: copying the backup over it fails.
+		if err := os.MkdirAll(filepath.Join(root, "config", "users.json"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		_, err := runBackupRecovery()
+		if !errors.Is(err, errDryRunRestore) || fixExitCode(err) != exitcodes.ExitRecoveryError {
+			t.Fatalf("err = %v (exit %d)", err, fixExitCode(err))
+		}
+	})
+
+	t.Run("nothing restorable", func(t *testing.T) {
+		_, set := fixRepo(t)
+		checkFixDryRun = true
+		if err := os.RemoveAll(set); err != nil {
+			t.Fatal(err)
+		}
+		_, err := runBackupRecovery()
+		if fixExitCode(err) != exitcodes.ExitNoBackup {
+			t.Fatalf("err = %v (exit %d)", err, fixExitCode(err))
+		}
+	})
+}
+
+// TestHasConflictMarkersFormats covers the conflict markers recovery looks for before it
+// trusts a live config file.
+func TestHasConflictMarkersFormats(t *testing.T) {
+	cases := []struct {
+		name    string
+		content string
+		want    bool
+	}{
+		{"merge", "<<<<<<< HEAD\na: 1\n=======\na: 2\n>>>>>>> main\n", true},
+		{"bare markers", "<<<<<<<\na: 1\n=======\na: 2\n>>>>>>>\n", true},
+		{"diff3", "<<<<<<< HEAD\na: 1\n||||||| merged common ancestors\na: 0\n=======\na: 2\n>>>>>>> main\n", true},
+		{"zdiff3 base only", "<<<<<<< ours\na: 1\n||||||| base\na: 0\n", true},
+		{"vscode labels", "<<<<<<< HEAD (Current Change)\na: 1\n=======\na: 2\n>>>>>>> feature/x (Incoming Change)\n", true},
+		{"rebase", "<<<<<<< HEAD\na: 1\n=======\na: 2\n>>>>>>> 3f2c1ab (Tune VAD thresholds)\n", true},
+		{"stash", "<<<<<<< Updated upstream\na: 1\n=======\na: 2\n>>>>>>> Stashed changes\n", true},
+		{"crlf", "<<<<<<< HEAD\r\na: 1\r\n=======\r\na: 2\r\n>>>>>>> main\r\n", true},
+		{"separator and close only", "a: 1\n=======\na: 2\n>>>>>>> main\n", true},
+		{"separator in value", "banner: =======\ngreeting: \"<<<<<<< not a marker\"\n", false},
+		{"indented block scalar", "prompt: |\n  <<<<<<< example\n  =======\n  >>>>>>> example\n", false},
+		{"long rules", "# ========\n========\nkey: value\n>>>>>>>>\n", false},
+		{"lone open marker", "<<<<<<< HEAD\na: 1\n", false},
+	}
+	dir := t.TempDir()
+	for i, tc := range cases {
+		path := filepath.Join(dir, fmt.Sprintf("c%d.yaml", i))
+		writeTestFile(t, path, tc.content)
+		if got := hasConflictMarkers(path); got != tc.want {
+			t.Errorf("%s: HasConflictMarkers = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestResolveRepoRootSkipsGitTopLevelWithoutMarkers(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	origRoot, origSafe := repoRootOverride, gitSafeDirectory
+	t.Cleanup(func() {
+		_ = os.Chdir(wd)
+		repoRootOverride, gitSafeDirectory = origRoot, origSafe
+	})
+	repoRootOverride, gitSafeDirectory = "", ""
+
+	// A checkout that sits inside an unrelated git repository (e.g. a dotfiles home).
+	outer, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out, err := exec.Command("git", "init", "-q", outer).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, out)
+	}
+	checkout := filepath.Join(outer, "Asterisk-AI-Voice-Agent")
+	writeTestFile(t, filepath.Join(checkout, "docker-compose.yml"), "services: {}\n")
+	writeTestFile(t, filepath.Join(checkout, "config", "ai-agent.yaml"), "default_provider: openai_realtime\n")
+	if err := os.Chdir(filepath.Join(checkout, "config")); err != nil {
+		t.Fatal(err)
+	}
+
+	root, err := resolveRepoRootForFix()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root != checkout {
+		t.Fatalf("root = %s, want %s (not the git top-level %s)", root, checkout, outer)
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	dockercheck "github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/check/docker"
+	"github.com/spf13/cobra"
+)
+
+var cleanupZombies bool
+
+var cleanupCmd = &cobra.Command{
+	Use:    "cleanup",
+	Short:  "Remove leftovers of failed updates and crashes",
+	Hidden: true, // advanced tool; agent check points to it
+	Long: `Remove leftovers that agent check reports.
+
+--zombies removes (docker rm) the exited containers of the Compose project that the
+"` + dockercheck.ZombieItemName + `" check item lists: containers from earlier compositions left behind
+by failed updates or crashes. The project is COMPOSE_PROJECT_NAME from .env, or
+` + dockercheck.DefaultProject + `. Running containers are never touched.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !cleanupZombies {
+			return errors.New("nothing to clean up; pass --zombies")
+		}
+		repoRoot, err := resolveRepoRootForFix()
+		if err != nil {
+			return err
+		}
+		project := dockercheck.ProjectName(filepath.Join(repoRoot, envRel(".env")))
+		zombies, err := dockercheck.FindZombieContainers(cmd.Context(), project)
+		if err != nil {
+			return fmt.Errorf("failed to list exited containers: %w", err)
+		}
+		if len(zombies) == 0 {
+			fmt.Printf("No exited containers in project %s.\n", project)
+			return nil
+		}
+
+		failed := 0
+		for _, z := range zombies {
+			if _, err := runCmd("docker", "rm", z.ID); err != nil {
+				failed++
+				fmt.Printf("✗ %s: %v\n", z.Name, err)
+				continue
+			}
+			fmt.Printf("✓ removed %s (exit code %d)\n", z.Name, z.ExitCode)
+		}
+		if failed > 0 {
+			return fmt.Errorf("failed to remove %d of %d container(s)", failed, len(zombies))
+		}
+		fmt.Printf("Removed %d exited container(s) from project %s.\n", len(zombies), project)
+		return nil
+	},
+}
+
+func init() {
+	cleanupCmd.Flags().BoolVar(&cleanupZombies, "zombies", false, "remove exited containers of the Compose project (see the "+dockercheck.ZombieItemName+" check)")
+	rootCmd.AddCommand(cleanupCmd)
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var completionCmd = &cobra.Command{
+	Use:   "completion [bash|zsh|fish|powershell]",
+	Short: "Print a shell completion script",
+	Long: `Print a tab-completion script for agent. Without an argument the shell is taken from
+$SHELL. The script is only printed; nothing is installed or edited.
+
+  bash:  echo 'source <(agent completion bash)' >> ~/.bashrc
+  zsh:   echo 'source <(agent completion zsh)' >> ~/.zshrc   (needs compinit)
+  fish:  agent completion fish > ~/.config/fish/completions/agent.fish
+
+Besides commands and flags, completion offers --format values for agent check and backup
+set names from .agent/update-backups/ and .agent/check-fix-backups/ for agent rollback.`,
+	Args:      cobra.MaximumNArgs(1),
+	ValidArgs: []string{"bash", "zsh", "fish", "powershell"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		shell := ""
+		if len(args) == 1 {
+			shell = args[0]
+		} else {
+			shell = filepath.Base(os.Getenv("SHELL"))
+			if shell == "." || shell == "" {
+				return fmt.Errorf("cannot detect the shell from $SHELL; pass it explicitly: agent completion bash|zsh|fish")
+			}
+		}
+		out := cmd.OutOrStdout()
+		switch strings.ToLower(shell) {
+		case "bash":
+			return rootCmd.GenBashCompletionV2(out, true)
+		case "zsh":
+			return rootCmd.GenZshCompletion(out)
+		case "fish":
+			return rootCmd.GenFishCompletion(out, true)
+		case "powershell", "pwsh":
+			return rootCmd.GenPowerShellCompletionWithDesc(out)
+		default:
+			return fmt.Errorf("unsupported shell %q (supported: bash, zsh, fish, powershell)", shell)
+		}
+	},
+}
+
+func init() {
+	// Replaces cobra's default completion command, which requires the shell argument.
+	rootCmd.CompletionOptions.DisableDefaultCmd = true
+	rootCmd.AddCommand(completionCmd)
+
+	_ = rootCmd.RegisterFlagCompletionFunc("log-level", fixedCompletions("debug", "info", "warn", "error"))
+	_ = rootCmd.RegisterFlagCompletionFunc("log-format", fixedCompletions("text", "json"))
+	_ = checkCmd.RegisterFlagCompletionFunc("format", fixedCompletions("text", "json", "sarif", "html"))
+	_ = checkCmd.RegisterFlagCompletionFunc("output-order", fixedCompletions("fail-first", "warn-first", "pass-first", "name-asc"))
+	rollbackCmd.ValidArgsFunction = completeBackupSets
+}
+
+func fixedCompletions(values ...string) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return values, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completeBackupSets offers backup directory names (timestamps) for agent rollback, newest
+// first. Nothing is offered once the single argument is given.
+func completeBackupSets(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	repoRoot, err := resolveRepoRootForFix()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	seen := map[string]bool{}
+	var names []string
+	for _, root := range []string{updateBackupRoot(repoRoot), checkFixBackupRoot(repoRoot)} {
+		entries, err := os.ReadDir(root)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			if e.IsDir() && !seen[e.Name()] && strings.HasPrefix(e.Name(), toComplete) {
+				seen[e.Name()] = true
+				names = append(names, e.Name())
+			}
+		}
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(names)))
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
@@ -3,7 +3,11 @@ package main
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
 
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/check"
 	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/config"
 	"github.com/spf13/cobra"
 )
@@ -27,6 +31,10 @@ var validateCmd = &cobra.Command{
 	Short: "Validate configuration file",
 	Long: `Validate config/ai-agent.yaml for syntax and configuration errors.
 
+With --all, validates the whole operator config set instead (.env, config/ai-agent.yaml,
+config/ai-agent.local.yaml, config/users.json, config/contexts/*.yaml) using the same
+validators as agent check --fix. This mode never writes, moves, or fixes files.
+
 Exit codes:
   0 - Configuration is valid
   1 - Warnings found (non-critical)
@@ -34,22 +42,48 @@ Exit codes:
 	RunE: runValidate,
 }
 
+// configBackupCmd is agent backup create under agent config, next to the commands that read
+// the sets it writes (config diff, config audit).
+var configBackupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "Snapshot the operator config (same as agent backup create)",
+	Long:  backupCreateCmd.Long,
+	Args:  cobra.NoArgs,
+	RunE:  backupCreateCmd.RunE,
+}
+
 var (
-	configFile   string
-	configFix    bool
-	configStrict bool
+	configFile        string
+	configFix         bool
+	configStrict      bool
+	configValidateAll bool
 )
 
 func init() {
 	validateCmd.Flags().StringVar(&configFile, "file", "config/ai-agent.yaml", "Path to configuration file")
 	validateCmd.Flags().BoolVar(&configFix, "fix", false, "Attempt to auto-fix issues")
 	validateCmd.Flags().BoolVar(&configStrict, "strict", false, "Treat warnings as errors")
+	validateCmd.Flags().BoolVar(&configValidateAll, "all", false, "Validate all operator config files (read-only)")
+
+	addBackupCreateFlags(configBackupCmd)
 
 	configCmd.AddCommand(validateCmd)
+	configCmd.AddCommand(configBackupCmd)
 	rootCmd.AddCommand(configCmd)
 }
 
 func runValidate(cmd *cobra.Command, args []string) error {
+	if configValidateAll {
+		if configFix {
+			return fmt.Errorf("--all is read-only and cannot be combined with --fix")
+		}
+		return runValidateConfigSet()
+	}
+
+	if !cmd.Flags().Changed("file") {
+		configFile = filepath.ToSlash(baseConfigRel("."))
+	}
+
 	fmt.Println("")
 	fmt.Printf("Validating %s...\n", configFile)
 	fmt.Println("")
@@ -115,6 +149,49 @@ func runValidate(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func runValidateConfigSet() error {
+	root, err := resolveRepoRootForFix()
+	if err != nil {
+		return err
+	}
+	results, err := check.ValidateConfigSet(root, agentEnv)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("")
+	fmt.Printf("Validating operator config in %s...\n", root)
+	fmt.Println("")
+
+	failCount, warnCount := 0, 0
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "FILE\tSTATUS\tMESSAGE")
+	for _, r := range results {
+		switch r.Status {
+		case check.StatusFail:
+			failCount++
+		case check.StatusWarn:
+			warnCount++
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", r.File, strings.ToUpper(string(r.Status)), r.Message)
+	}
+	_ = tw.Flush()
+
+	fmt.Println("")
+	fmt.Printf("Summary: %d file(s), %d warning(s), %d error(s)\n", len(results), warnCount, failCount)
+
+	exitCode := 0
+	if failCount > 0 || (configStrict && warnCount > 0) {
+		exitCode = 2
+	} else if warnCount > 0 {
+		exitCode = 1
+	}
+	if exitCode != 0 {
+		os.Exit(exitCode)
+	}
+	return nil
+}
+
 func printValidationResult(result *config.ValidationResult) {
 	// Print passes
 	for _, check := range result.Passed {
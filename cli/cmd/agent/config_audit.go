@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/audit"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/backup"
+	"github.com/spf13/cobra"
+)
+
+var configAuditSince string
+
+var configAuditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "List config changes made since the last backup",
+	Long: `List what changed in the operator config since a backup set: .env variables, YAML
+keys (dot-separated paths in ai-agent.yaml, ai-agent.local.yaml and config/contexts), and
+Admin UI users added to or removed from config/users.json. With --env NAME the
+environment's own files are compared (.env.NAME, ai-agent.local.NAME.yaml, ...).
+
+Secret .env values (names containing KEY, SECRET, PASSWORD or TOKEN) are shown as
+` + backup.RedactedValue + `; an empty value stays empty so you can still see a secret being cleared.
+
+--since accepts a backup directory path or a directory name under .agent/update-backups/
+or .agent/check-fix-backups/. By default the most recent backup set (see agent backup list)
+is used.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		repoRoot, err := resolveRepoRootForFix()
+		if err != nil {
+			return err
+		}
+		since, err := resolveAuditSince(repoRoot)
+		if err != nil {
+			return err
+		}
+		dir, cleanup, err := openBackupDir(repoRoot, since.Path)
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+		plain := since
+		plain.Path = dir
+		entries, err := audit.AuditConfigChanges(repoRoot, agentEnv, plain)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Changes since %s (%s UTC)\n\n", since.Path, since.CreatedAt.UTC().Format("2006-01-02 15:04:05"))
+		if len(entries) == 0 {
+			fmt.Println("No changes.")
+			return nil
+		}
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "FILE\tKEY\tOLD\tNEW")
+		for _, e := range entries {
+			key := e.Key
+			if key == "" {
+				key = "-"
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", e.File, key, e.OldValue, e.NewValue)
+		}
+		return tw.Flush()
+	},
+}
+
+func init() {
+	configAuditCmd.Flags().StringVar(&configAuditSince, "since", "", "backup directory to compare against (default: most recent backup set)")
+	configCmd.AddCommand(configAuditCmd)
+}
+
+// resolveAuditSince picks the --since backup set, or the newest set of either kind.
+func resolveAuditSince(repoRoot string) (backup.BackupSetInfo, error) {
+	roots := []string{updateBackupRoot(repoRoot), checkFixBackupRoot(repoRoot)}
+	if configAuditSince != "" {
+		dir := resolveBackupDirArg(roots[0], configAuditSince)
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			dir = resolveBackupDirArg(roots[1], configAuditSince)
+		}
+		info, err := os.Stat(dir)
+		if err != nil || !info.IsDir() {
+			return backup.BackupSetInfo{}, fmt.Errorf("backup directory not found: %s", configAuditSince)
+		}
+		return backup.BackupSetInfo{Kind: filepath.Base(filepath.Dir(dir)), Path: dir, CreatedAt: info.ModTime()}, nil
+	}
+
+	var sets []backup.BackupSetInfo
+	for _, root := range roots {
+		found, err := backup.ListBackupSets(root)
+		if err != nil {
+			return backup.BackupSetInfo{}, err
+		}
+		sets = append(sets, found...)
+	}
+	if len(sets) == 0 {
+		return backup.BackupSetInfo{}, fmt.Errorf("no backup sets found in %s or %s (use --since DIR)", roots[0], roots[1])
+	}
+	sort.SliceStable(sets, func(i, j int) bool { return sets[i].CreatedAt.After(sets[j].CreatedAt) })
+	return sets[0], nil
+}
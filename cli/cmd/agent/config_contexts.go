@@ -0,0 +1,350 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/check"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/contexts"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/exitcodes"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	contextsName         string
+	contextsFile         string
+	contextsRetention    time.Duration
+	contextsAll          bool
+	contextsFromZip      string
+	contextsOverwrite    bool
+	contextsSkip         bool
+	contextsRename       bool
+	contextsImportFormat string
+	contextsExportFormat string
+	contextsOutput       string
+)
+
+var configContextsCmd = &cobra.Command{
+	Use:   "contexts",
+	Short: "Manage the context files in config/contexts",
+	Long: `Manage the per-context YAML files in config/contexts, which the engine merges into
+contexts at startup.
+
+Subcommands:
+  list      Show context files with size and last-modified time
+  add       Validate a context file and copy it in as <name>.yaml
+  remove    Move a context file to config/contexts/.deleted (purged after --retention)
+  validate  Check context files for required fields, language and prompt length
+  import    Import the context files of a zip archive (--from-zip) or an export (--format json)
+  export    Write every context as one JSON or YAML document
+
+The engine reads config/contexts at startup: restart ai_engine after add or remove. Expired
+files in .deleted are purged whenever one of these subcommands runs.`,
+}
+
+var configContextsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List context files",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := newContextStore()
+		if err != nil {
+			return err
+		}
+		list, err := store.List()
+		if err != nil {
+			return err
+		}
+		if len(list) == 0 {
+			fmt.Printf("No context files in %s\n", store.Dir)
+			return nil
+		}
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "NAME\tSIZE\tMODIFIED")
+		for _, c := range list {
+			fmt.Fprintf(tw, "%s\t%d\t%s\n", c.Name, c.Size, c.ModTime.Format("2006-01-02 15:04:05"))
+		}
+		return tw.Flush()
+	},
+}
+
+var configContextsAddCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Validate and add a context file",
+	Long: `Validate --file and copy it to config/contexts/<name>.yaml. The file must be a YAML
+mapping with a non-empty name field (the engine skips context files without one) that no
+existing context file already uses. An existing <name>.yaml is never overwritten.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := newContextStore()
+		if err != nil {
+			return err
+		}
+		c, err := store.Add(contextsName, contextsFile)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Added context %s (%s). Restart ai_engine to load it.\n", c.Name, c.Path)
+		return nil
+	},
+}
+
+var configContextsRemoveCmd = &cobra.Command{
+	Use:   "remove",
+	Short: "Remove a context file (recoverable until --retention passes)",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if contextsRetention < 0 {
+			return errors.New("--retention must not be negative")
+		}
+		store, err := newContextStore()
+		if err != nil {
+			return err
+		}
+		moved, err := store.Remove(contextsName, contextsRetention)
+		if err != nil {
+			return err
+		}
+		if moved == "" {
+			fmt.Printf("Deleted context %s. Restart ai_engine to unload it.\n", contextsName)
+			return nil
+		}
+		fmt.Printf("Moved context %s to %s (purged after %s). Restart ai_engine to unload it.\n", contextsName, moved, contextsRetention)
+		return nil
+	},
+}
+
+var configContextsValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check context files against the context schema",
+	Long: `Check context files against the context schema: name, system_prompt (or prompt), voice
+and language must be set, and language must be a supported BCP-47 tag such as en-US.
+A system_prompt over 4096 characters is a warning.
+
+Pass --name to check one file or --all to check every file in config/contexts. Exits 2 if
+any file fails.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if contextsAll == (contextsName != "") {
+			return errors.New("pass either --name or --all")
+		}
+		store, err := newContextStore()
+		if err != nil {
+			return err
+		}
+		list, err := store.List()
+		if err != nil {
+			return err
+		}
+		if !contextsAll {
+			var match []contexts.Context
+			for _, c := range list {
+				if c.Name == strings.TrimSuffix(strings.TrimSuffix(contextsName, ".yaml"), ".yml") {
+					match = append(match, c)
+				}
+			}
+			if len(match) == 0 {
+				return fmt.Errorf("%w: %s", contexts.ErrContextNotFound, contextsName)
+			}
+			list = match
+		}
+		if len(list) == 0 {
+			fmt.Printf("No context files in %s\n", store.Dir)
+			return nil
+		}
+
+		failed := 0
+		for _, c := range list {
+			issues, err := contexts.ValidateContext(c.Path)
+			if err != nil {
+				failed++
+				fmt.Printf("❌ %s: %v\n", filepath.Base(c.Path), err)
+				continue
+			}
+			bad := false
+			for _, is := range issues {
+				bad = bad || is.Status == check.StatusFail
+			}
+			switch {
+			case bad:
+				failed++
+				fmt.Printf("❌ %s\n", filepath.Base(c.Path))
+			case len(issues) > 0:
+				fmt.Printf("⚠️  %s\n", filepath.Base(c.Path))
+			default:
+				fmt.Printf("✅ %s\n", filepath.Base(c.Path))
+			}
+			for _, is := range issues {
+				fmt.Printf("   %s %s: %s\n", is.Status, is.Field, is.Message)
+			}
+		}
+		if failed > 0 {
+			fmt.Printf("\n%d of %d context file(s) failed validation\n", failed, len(list))
+			os.Exit(exitcodes.ExitFail)
+		}
+		return nil
+	},
+}
+
+var configContextsExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export every context as one JSON or YAML document",
+	Long: `Write every context file in config/contexts as one document, for the Admin UI API or
+another host:
+
+  {"contexts": [{"name": "sales", "system_prompt": "...", ...}, ...], "exportedAt": "<RFC 3339>"}
+
+--format json writes it as JSON, --format yaml (the default) as YAML with the same keys.
+Contexts are in file name order; removed files in .deleted are left out. Read it back with
+agent config contexts import --format json --file FILE.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := newContextStore()
+		if err != nil {
+			return err
+		}
+		var out []byte
+		switch contextsExportFormat {
+		case "json":
+			out, err = contexts.ExportContextsJSON(store.Dir)
+		case "yaml":
+			var exp contexts.ContextExport
+			if exp, err = contexts.ExportContexts(store.Dir); err == nil {
+				out, err = yaml.Marshal(exp)
+			}
+		default:
+			return fmt.Errorf("invalid --format %q (use yaml or json)", contextsExportFormat)
+		}
+		if err != nil {
+			return err
+		}
+		if contextsOutput == "" {
+			_, err = os.Stdout.Write(out)
+			return err
+		}
+		if err := os.WriteFile(contextsOutput, out, 0o644); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Exported contexts to %s\n", contextsOutput)
+		return nil
+	},
+}
+
+var configContextsImportCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import context files from a zip archive or an export",
+	Long: `Import every .yaml/.yml file in --from-zip into config/contexts. Folders inside the
+archive are flattened (pack/sales.yaml becomes sales.yaml) and other files are ignored.
+
+With --format json, import the contexts of an agent config contexts export document given as
+--file instead (a YAML export works too). Each is written as <name>.yaml after its name
+field, with characters other than letters, digits, '_', '-' and '.' replaced by '-'.
+
+Each file must pass agent config contexts validate, and no two context files may end up with
+the same name field. Entries with absolute or ../ paths abort the import. Nothing is
+written unless every file is valid. When a context file of the same name exists, --overwrite
+replaces it, --skip keeps it, and --rename imports the new one as <name>-1.yaml. Without one
+of these, the import stops.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := contexts.ImportOptions{}
+		set := 0
+		for flag, policy := range map[*bool]string{
+			&contextsOverwrite: contexts.CollisionOverwrite,
+			&contextsSkip:      contexts.CollisionSkip,
+			&contextsRename:    contexts.CollisionRename,
+		} {
+			if *flag {
+				opts.OnCollision = policy
+				set++
+			}
+		}
+		if set > 1 {
+			return errors.New("--overwrite, --skip and --rename are mutually exclusive")
+		}
+		var data []byte
+		switch contextsImportFormat {
+		case "zip":
+			if contextsFromZip == "" || contextsFile != "" {
+				return errors.New("--format zip takes --from-zip, not --file")
+			}
+		case "json":
+			if contextsFile == "" || contextsFromZip != "" {
+				return errors.New("--format json takes --file, not --from-zip")
+			}
+			var err error
+			if data, err = os.ReadFile(contextsFile); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("invalid --format %q (use zip or json)", contextsImportFormat)
+		}
+		store, err := newContextStore()
+		if err != nil {
+			return err
+		}
+		var names []string
+		if data != nil {
+			names, err = contexts.ImportContextsJSON(data, store.Dir, opts)
+		} else {
+			names, err = contexts.ImportContextsFromZip(contextsFromZip, store.Dir, opts)
+		}
+		if err != nil {
+			return err
+		}
+		if len(names) == 0 {
+			fmt.Println("No context files imported (all skipped).")
+			return nil
+		}
+		fmt.Printf("Imported %d context file(s) into %s: %s\n", len(names), store.Dir, strings.Join(names, ", "))
+		fmt.Println("Restart ai_engine to load them.")
+		return nil
+	},
+}
+
+func init() {
+	configContextsAddCmd.Flags().StringVar(&contextsName, "name", "", "context file name (written as <name>.yaml)")
+	configContextsAddCmd.Flags().StringVar(&contextsFile, "file", "", "YAML file to add")
+	_ = configContextsAddCmd.MarkFlagRequired("name")
+	_ = configContextsAddCmd.MarkFlagRequired("file")
+	configContextsRemoveCmd.Flags().StringVar(&contextsName, "name", "", "context file name (without .yaml)")
+	configContextsRemoveCmd.Flags().DurationVar(&contextsRetention, "retention", contexts.DefaultRetention, "how long to keep the removed file in .deleted (0 deletes it immediately)")
+	_ = configContextsRemoveCmd.MarkFlagRequired("name")
+
+	configContextsValidateCmd.Flags().StringVar(&contextsName, "name", "", "context file name to check (without .yaml)")
+	configContextsValidateCmd.Flags().BoolVar(&contextsAll, "all", false, "check every context file")
+
+	configContextsImportCmd.Flags().StringVar(&contextsFromZip, "from-zip", "", "zip archive of context files to import")
+	configContextsImportCmd.Flags().StringVar(&contextsImportFormat, "format", "zip", "import source: zip (--from-zip) or json (--file, an agent config contexts export document)")
+	configContextsImportCmd.Flags().StringVar(&contextsFile, "file", "", "export document to import with --format json")
+	configContextsImportCmd.Flags().BoolVar(&contextsOverwrite, "overwrite", false, "replace existing context files of the same name")
+	configContextsImportCmd.Flags().BoolVar(&contextsSkip, "skip", false, "keep existing context files of the same name")
+	configContextsImportCmd.Flags().BoolVar(&contextsRename, "rename", false, "import colliding files as <name>-N.yaml")
+
+	configContextsExportCmd.Flags().StringVar(&contextsExportFormat, "format", "yaml", "output format: yaml|json")
+	configContextsExportCmd.Flags().StringVarP(&contextsOutput, "output", "o", "", "write to FILE instead of stdout")
+
+	configContextsCmd.AddCommand(configContextsListCmd, configContextsAddCmd, configContextsRemoveCmd, configContextsValidateCmd, configContextsImportCmd, configContextsExportCmd)
+	configCmd.AddCommand(configContextsCmd)
+}
+
+// newContextStore opens config/contexts under the repo root and purges expired removals.
+func newContextStore() (*contexts.ContextStore, error) {
+	repoRoot, err := resolveRepoRootForFix()
+	if err != nil {
+		return nil, err
+	}
+	store := contexts.NewContextStore(filepath.Join(repoRoot, filepath.FromSlash(contexts.DefaultDir)))
+	if n, err := store.Purge(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to purge expired files in %s: %v\n", contexts.DeletedDir, err)
+	} else if n > 0 {
+		fmt.Printf("Purged %d expired context file(s) from %s\n", n, contexts.DeletedDir)
+	}
+	return store, nil
+}
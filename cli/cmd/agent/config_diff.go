@@ -0,0 +1,187 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/backup"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/diff"
+	"github.com/spf13/cobra"
+)
+
+var (
+	configDiffFrom    string
+	configDiffTo      string
+	configDiffFormat  string
+	configDiffReverse bool
+)
+
+var configDiffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Show what changed between two backup directories",
+	Long: `Compare two operator config backups and show added, removed and modified files.
+
+--from and --to accept a directory path or the name of a directory under
+.agent/update-backups/. By default the two most recent update backups are compared
+(older as --from, newer as --to); with only --to, --from is the backup just before it.
+Incremental sets are rebuilt and encrypted sets decrypted with ` + backup.IdentityFileEnv + `
+first.
+
+--format patch prints a unified diff that patch -p1 applies from the repo root, e.g.
+  agent config diff --format patch > config.patch && patch -p1 < config.patch
+Binary files are listed as comments; copy them by hand. --reverse swaps --from and --to,
+so the patch undoes the change.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		repoRoot, err := resolveRepoRootForFix()
+		if err != nil {
+			return err
+		}
+		if configDiffFormat != "text" && configDiffFormat != "patch" {
+			return fmt.Errorf("invalid --format %q (use text or patch)", configDiffFormat)
+		}
+		from, to, err := resolveConfigDiffDirs(repoRoot)
+		if err != nil {
+			return err
+		}
+		if configDiffReverse {
+			from, to = to, from
+		}
+		fromDir, cleanupFrom, err := openBackupDir(repoRoot, from)
+		if err != nil {
+			return err
+		}
+		defer cleanupFrom()
+		toDir, cleanupTo, err := openBackupDir(repoRoot, to)
+		if err != nil {
+			return err
+		}
+		defer cleanupTo()
+		diffs, err := backup.DiffBackupDirs(fromDir, toDir)
+		if err != nil {
+			return err
+		}
+		if configDiffFormat == "patch" {
+			return diff.DiffToPatch(diffs, os.Stdout)
+		}
+		printBackupDiffs(from, to, diffs)
+		return nil
+	},
+}
+
+func init() {
+	configDiffCmd.Flags().StringVar(&configDiffFrom, "from", "", "older backup directory (default: second most recent update backup)")
+	configDiffCmd.Flags().StringVar(&configDiffTo, "to", "", "newer backup directory (default: most recent update backup)")
+	configDiffCmd.Flags().StringVar(&configDiffFormat, "format", "text", "output format: text|patch (unified diff for patch -p1)")
+	configDiffCmd.Flags().BoolVar(&configDiffReverse, "reverse", false, "swap --from and --to (a patch that undoes the change)")
+	configCmd.AddCommand(configDiffCmd)
+}
+
+func resolveConfigDiffDirs(repoRoot string) (string, string, error) {
+	root := updateBackupRoot(repoRoot)
+	from := resolveBackupDirArg(root, configDiffFrom)
+	to := resolveBackupDirArg(root, configDiffTo)
+	if from != "" && to != "" {
+		return from, to, nil
+	}
+
+	dirs, err := backup.BackupDirs(root)
+	if err != nil {
+		return "", "", err
+	}
+	if to == "" {
+		if len(dirs) == 0 {
+			return "", "", fmt.Errorf("no update backups found in %s", root)
+		}
+		to = dirs[0]
+	}
+	if from == "" {
+		// dirs is newest first: take the set just before to, or the newest one when to is
+		// outside root.
+		i := 0
+		for j, d := range dirs {
+			if filepath.Clean(d) == filepath.Clean(to) {
+				i = j + 1
+				break
+			}
+		}
+		if i >= len(dirs) {
+			if configDiffTo != "" {
+				return "", "", fmt.Errorf("no update backup older than %s to compare (pass --from)", filepath.Base(to))
+			}
+			return "", "", errors.New("need at least two update backups to compare (or pass --from)")
+		}
+		from = dirs[i]
+	}
+	return from, to, nil
+}
+
+// resolveBackupDirArg accepts either a path or a directory name under root.
+func resolveBackupDirArg(root, arg string) string {
+	arg = strings.TrimSpace(arg)
+	if arg == "" {
+		return ""
+	}
+	if info, err := os.Stat(arg); err == nil && info.IsDir() {
+		return arg
+	}
+	if !strings.ContainsAny(arg, `/\`) {
+		return filepath.Join(root, arg)
+	}
+	return arg
+}
+
+func printBackupDiffs(from, to string, diffs []backup.FileDiff) {
+	green := color.New(color.FgGreen).SprintFunc()
+	red := color.New(color.FgRed).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+	gray := color.New(color.FgHiBlack).SprintFunc()
+
+	fmt.Printf("Comparing %s -> %s\n", from, to)
+	fmt.Println("")
+	if len(diffs) == 0 {
+		fmt.Println("No differences.")
+		return
+	}
+
+	added, removed, modified := 0, 0, 0
+	for _, d := range diffs {
+		switch d.Status {
+		case backup.DiffAdded:
+			added++
+			fmt.Printf("%s %s\n", green("+ added   "), d.Path)
+		case backup.DiffRemoved:
+			removed++
+			fmt.Printf("%s %s\n", red("- removed "), d.Path)
+		default:
+			modified++
+			fmt.Printf("%s %s\n", yellow("~ modified"), d.Path)
+		}
+		if d.Binary {
+			fmt.Printf("    %s\n", gray(fmt.Sprintf("(binary, sizes: %d → %d bytes)", d.SizeA, d.SizeB)))
+			continue
+		}
+		for _, line := range strings.Split(strings.TrimRight(d.Unified, "\n"), "\n") {
+			if line == "" {
+				continue
+			}
+			switch {
+			case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+				line = gray(line)
+			case strings.HasPrefix(line, "+"):
+				line = green(line)
+			case strings.HasPrefix(line, "-"):
+				line = red(line)
+			case strings.HasPrefix(line, "@@"):
+				line = gray(line)
+			}
+			fmt.Printf("    %s\n", line)
+		}
+	}
+	fmt.Println("")
+	fmt.Printf("Summary: %d added, %d removed, %d modified\n", added, removed, modified)
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestResolveConfigDiffDirsOnlyTo(t *testing.T) {
+	root, _ := fixRepo(t)
+	origFrom, origTo := configDiffFrom, configDiffTo
+	t.Cleanup(func() { configDiffFrom, configDiffTo = origFrom, origTo })
+
+	backups := updateBackupRoot(root)
+	for _, name := range []string{"20250101_000000", "20250201_000000", "20260201_000000"} {
+		writeTestFile(t, filepath.Join(backups, name, ".env"), "ASTERISK_HOST=pbx\n")
+	}
+
+	configDiffFrom, configDiffTo = "", "20250201_000000"
+	from, to, err := resolveConfigDiffDirs(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(backups, "20250101_000000"); from != want {
+		t.Fatalf("from = %s, want the set just before --to (%s)", from, want)
+	}
+	if want := filepath.Join(backups, "20250201_000000"); to != want {
+		t.Fatalf("to = %s, want %s", to, want)
+	}
+
+	configDiffTo = "20250101_000000"
+	if _, _, err := resolveConfigDiffDirs(root); err == nil {
+		t.Fatal("expected an error when --to is the oldest set")
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/secrets"
+	"github.com/spf13/cobra"
+)
+
+var (
+	configEncryptSecretsFile        string
+	configEncryptSecretsKeyFile     string
+	configEncryptSecretsAnnotations []string
+	configEncryptSecretsDecrypt     bool
+)
+
+var configEncryptSecretsCmd = &cobra.Command{
+	Use:   "encrypt-secrets",
+	Short: "Encrypt secret values inside a YAML config file in place",
+	Long: `Replace the value of every key named like a secret (password, api_key, secret, token, or
+ending in _password, _api_key, ...) in a config file with ENC[aes256gcm,<base64>], encrypted
+with AES-256-GCM under a key derived from ` + secrets.KeyFile + `. Comments and layout are
+kept; ${VAR} references, empty values and values already encrypted are left alone.
+
+The key file is created (32 random bytes, mode 0600) on first use. Keep a copy somewhere safe
+and out of version control: without it the values cannot be recovered.
+
+The agent CLI decrypts ENC[...] values wherever it reads YAML (config validate, merge, diff)
+when the key file is present. The engine and Admin UI do not, so run --decrypt (or deploy a
+decrypted copy) before starting the containers.
+
+  agent config encrypt-secrets --file config/ai-agent.local.yaml
+  agent config encrypt-secrets --annotation password --annotation webhook_url
+  agent config encrypt-secrets --decrypt`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		repoRoot, err := resolveRepoRootForFix()
+		if err != nil {
+			return err
+		}
+		path := configEncryptSecretsFile
+		if path == "" {
+			path = filepath.Join(repoRoot, baseConfigRel(repoRoot))
+		}
+		keyFile := configEncryptSecretsKeyFile
+		if keyFile == "" {
+			keyFile = filepath.Join(repoRoot, filepath.FromSlash(secrets.KeyFile))
+		}
+		before, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		if configEncryptSecretsDecrypt {
+			if err := secrets.DecryptYAMLSecrets(path, keyFile); err != nil {
+				return fmt.Errorf("failed to decrypt %s: %w", path, err)
+			}
+		} else {
+			created, err := secrets.GenerateKeyFile(keyFile)
+			if err != nil {
+				return fmt.Errorf("failed to create %s: %w", keyFile, err)
+			}
+			if created {
+				fmt.Printf("Created %s; back it up, encrypted values cannot be read without it\n", keyFile)
+			}
+			if err := secrets.EncryptYAMLSecrets(path, keyFile, configEncryptSecretsAnnotations); err != nil {
+				return fmt.Errorf("failed to encrypt %s: %w", path, err)
+			}
+		}
+
+		after, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		marker := []byte("ENC[aes256gcm,")
+		switch {
+		case bytes.Equal(before, after) && configEncryptSecretsDecrypt:
+			fmt.Printf("No encrypted values in %s\n", path)
+		case bytes.Equal(before, after):
+			fmt.Printf("No unencrypted values for %s in %s\n", strings.Join(configEncryptSecretsAnnotations, ", "), path)
+		case configEncryptSecretsDecrypt:
+			fmt.Printf("Decrypted %d value(s) in %s\n", bytes.Count(before, marker)-bytes.Count(after, marker), path)
+		default:
+			fmt.Printf("Encrypted %d value(s) in %s\n", bytes.Count(after, marker)-bytes.Count(before, marker), path)
+		}
+		return nil
+	},
+}
+
+func init() {
+	configEncryptSecretsCmd.Flags().StringVarP(&configEncryptSecretsFile, "file", "f", "", "config file to rewrite (default config/ai-agent.yaml)")
+	configEncryptSecretsCmd.Flags().StringVar(&configEncryptSecretsKeyFile, "key-file", "", "key file (default <repo root>/"+secrets.KeyFile+")")
+	configEncryptSecretsCmd.Flags().StringArrayVar(&configEncryptSecretsAnnotations, "annotation", secrets.DefaultSecretAnnotations, "key name to encrypt, also matching *_NAME (repeatable)")
+	configEncryptSecretsCmd.Flags().BoolVar(&configEncryptSecretsDecrypt, "decrypt", false, "decrypt the ENC[...] values back to plaintext instead")
+	configCmd.AddCommand(configEncryptSecretsCmd)
+}
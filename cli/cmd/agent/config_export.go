@@ -0,0 +1,315 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/backup"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/backup/remote"
+	"github.com/spf13/cobra"
+)
+
+var (
+	configExportOutput string
+	configExportRedact bool
+	configImportFile   string
+	configImportYes    bool
+)
+
+var configExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Write the operator config to a single portable archive",
+	Long: `Write .env, config/ai-agent.yaml, config/ai-agent.local.yaml, config/users.json and
+config/contexts/ to a gzip-compressed tar, for moving an installation to another host.
+
+The archive carries a version header (` + backup.ExportHeaderName + `) and a checksum manifest that
+agent config import verifies. With --redact, API keys, passwords and tokens in .env are replaced
+with ` + backup.RedactedValue + `; importing such an archive keeps the target host's existing secrets.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runConfigExport()
+	},
+}
+
+var configImportCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Restore the operator config from an export archive",
+	Long: `Restore the operator config from an archive written by agent config export.
+
+Import checks the version header and checksum manifest and refuses archives holding files other
+than the ones export writes, or files that fail the checks agent check --fix applies before a
+restore (.env syntax, YAML mappings, users.json). It then previews the changed files as a diff,
+asks for confirmation, snapshots the current state to .agent/check-fix-backups/, writes the
+files (.env with mode 0600) and restarts ai_engine/admin_ui.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runConfigImport()
+	},
+}
+
+func init() {
+	configExportCmd.Flags().StringVarP(&configExportOutput, "output", "o", "agent-config.tar.gz", "archive to write")
+	configExportCmd.Flags().BoolVar(&configExportRedact, "redact", false, "replace secret values in .env with "+backup.RedactedValue)
+	configImportCmd.Flags().StringVar(&configImportFile, "file", "", "archive written by agent config export")
+	configImportCmd.Flags().BoolVarP(&configImportYes, "yes", "y", false, "import without asking for confirmation")
+	_ = configImportCmd.MarkFlagRequired("file")
+
+	configCmd.AddCommand(configExportCmd)
+	configCmd.AddCommand(configImportCmd)
+}
+
+func runConfigExport() error {
+	output, err := filepath.Abs(configExportOutput)
+	if err != nil {
+		return err
+	}
+	repoRoot, err := resolveRepoRootForFix()
+	if err != nil {
+		return err
+	}
+	if err := os.Chdir(repoRoot); err != nil {
+		return fmt.Errorf("failed to switch to repo root: %w", err)
+	}
+
+	stage, err := os.MkdirTemp("", "agent-config-export-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(stage)
+
+	for _, rel := range operatorConfigPaths() {
+		if err := copyPathIfExists(rel, stage); err != nil {
+			return fmt.Errorf("failed to stage %s: %w", rel, err)
+		}
+	}
+	stagedEnv := filepath.Join(stage, envRel(".env"))
+	if data, err := os.ReadFile(stagedEnv); err == nil {
+		if configExportRedact {
+			data = backup.RedactEnv(data)
+		}
+		if err := os.WriteFile(stagedEnv, data, 0o600); err != nil {
+			return err
+		}
+		// WriteFile keeps the mode copied from the live file; the archive should record 0600.
+		if err := os.Chmod(stagedEnv, 0o600); err != nil {
+			return err
+		}
+	}
+
+	header := backup.ExportHeader{
+		Format:     backup.ExportFormatVersion,
+		CLIVersion: version,
+		CreatedAt:  time.Now().UTC().Truncate(time.Second),
+		Redacted:   configExportRedact,
+	}
+	if err := backup.WriteExportHeader(stage, header); err != nil {
+		return err
+	}
+	if err := backup.WriteManifest(stage); err != nil {
+		return err
+	}
+	files, err := exportedFiles(stage)
+	if err != nil {
+		return err
+	}
+
+	// The archive may contain secrets, so keep it owner-only.
+	f, err := os.OpenFile(output, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", output, err)
+	}
+	if err := remote.WriteArchive(f, stage); err != nil {
+		_ = f.Close()
+		_ = os.Remove(output)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	for _, rel := range files {
+		fmt.Printf("  %s\n", rel)
+	}
+	note := ""
+	if configExportRedact {
+		note = " (secrets redacted)"
+	}
+	fmt.Printf("Exported %d file(s) to %s%s\n", len(files), output, note)
+	return nil
+}
+
+func runConfigImport() error {
+	archive, err := filepath.Abs(configImportFile)
+	if err != nil {
+		return err
+	}
+	repoRoot, err := resolveRepoRootForFix()
+	if err != nil {
+		return err
+	}
+	if err := os.Chdir(repoRoot); err != nil {
+		return fmt.Errorf("failed to switch to repo root: %w", err)
+	}
+
+	stage, err := os.MkdirTemp("", "agent-config-import-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(stage)
+
+	f, err := os.Open(archive)
+	if err != nil {
+		return err
+	}
+	err = remote.ExtractArchive(f, stage)
+	_ = f.Close()
+	if err != nil {
+		return err
+	}
+	header, err := backup.ReadExportHeader(stage)
+	if err != nil {
+		return err
+	}
+	if err := backup.VerifyManifest(stage); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("refusing to import %s: archive has no %s", archive, backup.ManifestName)
+		}
+		return fmt.Errorf("refusing to import %s: %w", archive, err)
+	}
+	exportedBy := header.CLIVersion
+	if exportedBy == "" {
+		exportedBy = "unknown"
+	}
+	fmt.Printf("Archive verified: %s (format %d, exported by agent %s at %s)\n",
+		archive, header.Format, exportedBy, header.CreatedAt.Format(time.RFC3339))
+
+	stagedEnv := filepath.Join(stage, envRel(".env"))
+	if header.Redacted {
+		if data, err := os.ReadFile(stagedEnv); err == nil {
+			live, _ := os.ReadFile(envRel(".env"))
+			merged, missing := backup.RestoreRedactedEnv(data, live)
+			if err := os.WriteFile(stagedEnv, merged, 0o600); err != nil {
+				return err
+			}
+			if len(missing) > 0 {
+				fmt.Printf("Warning: redacted secrets with no value on this host will be left empty: %s\n", strings.Join(missing, ", "))
+			}
+		}
+	}
+
+	files, err := exportedFiles(stage)
+	if err != nil {
+		return err
+	}
+	// Only the files agent config export writes may land in the tree, and only if they would
+	// pass the checks agent check --fix applies before restoring them.
+	for _, rel := range files {
+		if !isOperatorConfigPath(rel) {
+			return fmt.Errorf("refusing to import %s: %s is not an operator config file", archive, rel)
+		}
+	}
+	results, err := backup.ValidateFiles(stage, backupFileValidators())
+	if err != nil {
+		return err
+	}
+	for _, res := range results {
+		if !res.OK() {
+			return fmt.Errorf("refusing to import %s: %s: %s", archive, res.Path, res.Error)
+		}
+	}
+
+	var changed []string
+	for _, rel := range files {
+		staged, err := os.ReadFile(filepath.Join(stage, rel))
+		if err != nil {
+			return err
+		}
+		if live, err := os.ReadFile(rel); err == nil && bytes.Equal(live, staged) {
+			continue
+		}
+		changed = append(changed, rel)
+	}
+	if len(changed) == 0 {
+		fmt.Println("Nothing to import: the live config already matches the archive.")
+		return nil
+	}
+
+	fmt.Println("")
+	fmt.Println("Changes (live -> archive):")
+	for _, rel := range changed {
+		fmt.Printf("\n=== %s\n", rel)
+		fmt.Print(unifiedDiff(rel, filepath.Join(stage, rel)))
+	}
+	fmt.Println("")
+
+	if !configImportYes && !confirmRollback(os.Stdin, fmt.Sprintf("Import %d file(s) from %s?", len(changed), archive)) {
+		fmt.Println("Import cancelled. No files were changed.")
+		return nil
+	}
+
+	summary := &fixSummary{repoRoot: repoRoot}
+	if err := snapshotBeforeFix(repoRoot, summary); err != nil {
+		return err
+	}
+	fmt.Printf("Current config saved to %s\n", summary.prefixBackup)
+	for _, rel := range changed {
+		if err := copyFile(filepath.Join(stage, rel), rel); err != nil {
+			return err
+		}
+		if rel == envRel(".env") {
+			if err := os.Chmod(rel, 0o600); err != nil {
+				return err
+			}
+		}
+		fmt.Printf("  imported %s\n", rel)
+	}
+
+	if err := restartCoreServices(); err != nil {
+		return fmt.Errorf("imported %d file(s) but restart failed: %w", len(changed), err)
+	}
+	fmt.Printf("Imported %d file(s) and restarted ai_engine/admin_ui. Run agent check to verify.\n", len(changed))
+	return nil
+}
+
+// isOperatorConfigPath reports whether rel is one of operatorConfigPaths or lies under one of
+// its directories.
+func isOperatorConfigPath(rel string) bool {
+	rel = filepath.Clean(rel)
+	for _, p := range operatorConfigPaths() {
+		if rel == p || strings.HasPrefix(rel, p+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// exportedFiles lists the config files in an export stage, excluding the header and manifest.
+func exportedFiles(stage string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(stage, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(stage, path)
+		if err != nil {
+			return err
+		}
+		if rel == backup.ManifestName || rel == backup.ExportHeaderName {
+			return nil
+		}
+		files = append(files, rel)
+		return nil
+	})
+	sort.Strings(files)
+	return files, err
+}
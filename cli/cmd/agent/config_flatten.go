@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/configmerge"
+	"github.com/spf13/cobra"
+)
+
+var (
+	configFlattenFile   string
+	configFlattenOutput string
+)
+
+var configFlattenCmd = &cobra.Command{
+	Use:   "flatten",
+	Short: "Resolve !include directives into a single YAML file",
+	Long: `Resolve every "key: !include <relpath>" in a config file (paths are relative to the file
+that contains them, and may nest) and print the result as one YAML document, or write it to
+--output. Comments and key order from all files are kept.
+
+The engine and Admin UI load ai-agent.yaml, ai-agent.local.yaml and config/contexts/ with a
+plain YAML loader that cannot resolve !include, so agent check and agent config validate fail
+on an !include in those files. Keep split sources elsewhere and deploy the flattened file:
+
+  agent config flatten --file config/src/ai-agent.yaml --output config/ai-agent.yaml`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := configFlattenFile
+		if path == "" {
+			repoRoot, err := resolveRepoRootForFix()
+			if err != nil {
+				return err
+			}
+			path = filepath.Join(repoRoot, baseConfigRel(repoRoot))
+		}
+		out, err := configmerge.FlattenYAMLFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to flatten %s: %w", path, err)
+		}
+		if configFlattenOutput == "" {
+			_, err = os.Stdout.Write(out)
+			return err
+		}
+		if err := os.WriteFile(configFlattenOutput, out, 0o644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", configFlattenOutput, err)
+		}
+		fmt.Printf("Wrote flattened config to %s\n", configFlattenOutput)
+		return nil
+	},
+}
+
+func init() {
+	configFlattenCmd.Flags().StringVarP(&configFlattenFile, "file", "f", "", "config file to flatten (default config/ai-agent.yaml)")
+	configFlattenCmd.Flags().StringVarP(&configFlattenOutput, "output", "o", "", "write the flattened config to FILE instead of stdout")
+	configCmd.AddCommand(configFlattenCmd)
+}
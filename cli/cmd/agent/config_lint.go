@@ -0,0 +1,157 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/configmerge"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/exitcodes"
+	"github.com/spf13/cobra"
+)
+
+var (
+	configLintRules string
+	configLintFix   bool
+)
+
+// lintRequiredKeys are the top-level keys a base config must define (see internal/config's
+// validator); overrides and contexts are partial and are not held to them.
+var lintRequiredKeys = []string{"default_provider", "providers"}
+
+var configLintCmd = &cobra.Command{
+	Use:   "lint [file...]",
+	Short: "Check config YAML for duplicate keys, long lines and site-specific rules",
+	Long: `Lint config YAML files. By default the files you edit are checked: the local override
+(config/ai-agent.local.yaml, or its --env variant) and config/contexts/*.yaml. Pass files to
+check others, such as config/ai-agent.yaml.
+
+Built-in rules:
+  no-duplicate-keys        a key defined twice in one mapping (error; the last one wins)
+  max-line-length          lines over 120 characters (warning)
+  no-trailing-whitespace   spaces or tabs at the end of a line (warning, fixable)
+  required-top-level-keys  default_provider and providers, for base configs only (error)
+
+Site rules are read from every *.yaml file in .agent/lint-rules/, and from --rules FILE:
+
+  rules:
+    - name: https-webhooks
+      key: tools.*.webhook_url     # dot path; * matches any one key or list index
+      require: '^https://'         # or forbid: <regex>
+      message: webhooks must use TLS
+      severity: error              # default: warning
+
+A rule reports each scalar at a matching key whose value matches forbid, or does not match
+require.
+
+--fix rewrites each file with fixable issues in place, then lints it again.
+
+Exit codes:
+  0 - no issues
+  1 - warnings only
+  2 - errors`,
+	Example: `  agent config lint
+  agent config lint config/ai-agent.yaml --rules site-rules.yaml
+  agent config lint --fix`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		repoRoot, err := resolveRepoRootForFix()
+		if err != nil {
+			return err
+		}
+		siteRules, err := configmerge.LoadLintRules(filepath.Join(repoRoot, configmerge.LintRulesDir))
+		if err != nil {
+			return fmt.Errorf("failed to load lint rules: %w", err)
+		}
+		if configLintRules != "" {
+			extra, err := configmerge.LoadLintRulesFile(configLintRules)
+			if err != nil {
+				return fmt.Errorf("failed to load lint rules: %w", err)
+			}
+			siteRules = append(siteRules, extra...)
+		}
+
+		files := args
+		if len(files) == 0 {
+			if files, err = defaultLintFiles(repoRoot); err != nil {
+				return err
+			}
+			if len(files) == 0 {
+				fmt.Println("No operator config files to lint (pass a file to lint the base config).")
+				return nil
+			}
+		}
+
+		var errCount, warnCount, fixed int
+		for _, file := range files {
+			rules := append(configmerge.DefaultLintRules(), siteRules...)
+			if isBaseConfigFile(file) {
+				rules = append(rules, configmerge.RequiredTopLevelKeys(lintRequiredKeys))
+			}
+			if configLintFix {
+				changed, err := configmerge.FixFile(file, rules)
+				if err != nil {
+					return err
+				}
+				if changed {
+					fixed++
+					fmt.Printf("Fixed %s\n", file)
+				}
+			}
+			issues, err := configmerge.LintFile(file, rules)
+			if err != nil {
+				return err
+			}
+			for _, is := range issues {
+				if is.Severity == configmerge.LintError {
+					errCount++
+				} else {
+					warnCount++
+				}
+				fmt.Println(is.String())
+			}
+		}
+
+		fmt.Println("")
+		summary := fmt.Sprintf("%d file(s) checked: %d error(s), %d warning(s)", len(files), errCount, warnCount)
+		if configLintFix {
+			summary += fmt.Sprintf(", %d file(s) fixed", fixed)
+		}
+		fmt.Println(summary)
+		switch {
+		case errCount > 0:
+			os.Exit(exitcodes.ExitFail)
+		case warnCount > 0:
+			os.Exit(exitcodes.ExitWarn)
+		}
+		return nil
+	},
+}
+
+// defaultLintFiles lists the operator-owned config files that exist under repoRoot.
+func defaultLintFiles(repoRoot string) ([]string, error) {
+	var files []string
+	local := filepath.Join(repoRoot, envRel(filepath.Join("config", "ai-agent.local.yaml")))
+	if _, err := os.Stat(local); err == nil {
+		files = append(files, local)
+	}
+	contexts, err := filepath.Glob(filepath.Join(repoRoot, "config", "contexts", "*.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	return append(files, contexts...), nil
+}
+
+// isBaseConfigFile reports whether file is config/ai-agent.yaml or an environment's
+// ai-agent.<name>.yaml, as opposed to a local override.
+func isBaseConfigFile(file string) bool {
+	name := filepath.Base(file)
+	return strings.HasPrefix(name, "ai-agent.") && strings.HasSuffix(name, ".yaml") &&
+		!strings.HasPrefix(name, "ai-agent.local.")
+}
+
+func init() {
+	configLintCmd.Flags().StringVar(&configLintRules, "rules", "", "additional rules file, on top of "+configmerge.LintRulesDir+"/*.yaml")
+	configLintCmd.Flags().BoolVar(&configLintFix, "fix", false, "correct fixable issues in place")
+	configCmd.AddCommand(configLintCmd)
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/configmerge"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	configMergeOutput   string
+	configMergeDiff     bool
+	configMergeStrategy string
+)
+
+var configMergeCmd = &cobra.Command{
+	Use:   "merge",
+	Short: "Preview the effective config (ai-agent.yaml + ai-agent.local.yaml)",
+	Long: `Deep-merge config/ai-agent.local.yaml over config/ai-agent.yaml the same way the engine
+does (mappings merge, lists and scalars are replaced, null deletes a key) and print the result
+as YAML, or write it to --output. ${VAR} references are shown unexpanded.
+
+With --diff, only the keys where the local file overrides the base are printed.
+
+--strategy previews other ways of combining the files; the engine itself always uses overlay:
+  overlay     mappings merge recursively, lists and scalars are replaced (default)
+  deep-merge  as overlay, but lists are concatenated, skipping items already in the base list
+  last-wins   each top-level key in the local file replaces the base key as a whole
+A null in the local file deletes the key under every strategy.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if configMergeDiff && configMergeOutput != "" {
+			return errors.New("--diff cannot be combined with --output")
+		}
+		strategy, err := configmerge.ParseMergeStrategy(configMergeStrategy)
+		if err != nil {
+			return err
+		}
+		if configMergeDiff && strategy != configmerge.StrategyOverlay {
+			return errors.New("--diff lists the local overrides and cannot be combined with --strategy")
+		}
+		repoRoot, err := resolveRepoRootForFix()
+		if err != nil {
+			return err
+		}
+		base, local, err := readConfigLayers(repoRoot)
+		if err != nil {
+			return err
+		}
+
+		if configMergeDiff {
+			overrides := configmerge.Overrides(base, local)
+			if len(overrides) == 0 {
+				fmt.Println("No local overrides.")
+				return nil
+			}
+			for _, o := range overrides {
+				switch o.Kind {
+				case configmerge.OverrideAdded:
+					fmt.Printf("+ %s: %s\n", o.Path, inlineYAML(o.Local))
+				case configmerge.OverrideRemoved:
+					fmt.Printf("- %s (was %s)\n", o.Path, inlineYAML(o.Base))
+				default:
+					fmt.Printf("~ %s: %s -> %s\n", o.Path, inlineYAML(o.Base), inlineYAML(o.Local))
+				}
+			}
+			return nil
+		}
+
+		merged, err := configmerge.MergeYAML(base, local, strategy)
+		if err != nil {
+			return err
+		}
+		if configMergeOutput != "" {
+			if err := configmerge.WriteYAMLFileAtomic(configMergeOutput, merged); err != nil {
+				return fmt.Errorf("failed to write %s: %w", configMergeOutput, err)
+			}
+			fmt.Printf("Wrote merged config to %s\n", configMergeOutput)
+			return nil
+		}
+		out, err := yaml.Marshal(merged)
+		if err != nil {
+			return err
+		}
+		_, err = os.Stdout.Write(out)
+		return err
+	},
+}
+
+func init() {
+	configMergeCmd.Flags().StringVarP(&configMergeOutput, "output", "o", "", "write the merged config to FILE instead of stdout")
+	configMergeCmd.Flags().BoolVar(&configMergeDiff, "diff", false, "print only keys overridden by ai-agent.local.yaml")
+	configMergeCmd.Flags().StringVar(&configMergeStrategy, "strategy", string(configmerge.StrategyOverlay), "merge strategy to preview: overlay, deep-merge or last-wins")
+	configCmd.AddCommand(configMergeCmd)
+}
+
+// readConfigLayers reads the base config and the ai-agent.local.yaml override of the active
+// --env; local is empty when there is no override file.
+func readConfigLayers(repoRoot string) (base, local map[string]any, err error) {
+	basePath := filepath.Join(repoRoot, baseConfigRel(repoRoot))
+	localPath := filepath.Join(repoRoot, envRel(filepath.Join("config", "ai-agent.local.yaml")))
+
+	base, err = configmerge.ReadYAMLFile(basePath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read base config: %w", err)
+	}
+	local = map[string]any{}
+	if _, statErr := os.Stat(localPath); statErr == nil {
+		local, err = configmerge.ReadYAMLFile(localPath)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read local override: %w", err)
+		}
+	}
+	return base, local, nil
+}
+
+// inlineYAML renders v as single-line YAML flow style for diff output.
+func inlineYAML(v any) string {
+	var node yaml.Node
+	if err := node.Encode(v); err != nil {
+		return fmt.Sprint(v)
+	}
+	setFlowStyle(&node)
+	out, err := yaml.Marshal(&node)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return strings.TrimRight(string(out), "\n")
+}
+
+func setFlowStyle(n *yaml.Node) {
+	n.Style |= yaml.FlowStyle
+	for _, c := range n.Content {
+		setFlowStyle(c)
+	}
+}
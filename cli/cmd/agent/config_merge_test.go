@@ -0,0 +1,38 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/secrets"
+)
+
+func TestConfigMergeOutputKeepsEncryptedValues(t *testing.T) {
+	root, _ := fixRepo(t)
+	origOutput := configMergeOutput
+	t.Cleanup(func() { configMergeOutput = origOutput })
+
+	keyFile := filepath.Join(root, filepath.FromSlash(secrets.KeyFile))
+	if _, err := secrets.GenerateKeyFile(keyFile); err != nil {
+		t.Fatal(err)
+	}
+	base := filepath.Join(root, "config", "ai-agent.yaml")
+	writeTestFile(t, base, "asterisk:\n  password: s3cr3t\n")
+	if err := secrets.EncryptYAMLSecrets(base, keyFile, []string{"password"}); err != nil {
+		t.Fatal(err)
+	}
+
+	configMergeOutput = filepath.Join(t.TempDir(), "merged.yaml")
+	if err := configMergeCmd.RunE(configMergeCmd, nil); err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(configMergeOutput)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(got), "s3cr3t") || !strings.Contains(string(got), "ENC[aes256gcm,") {
+		t.Fatalf("merged config should keep the ENC[...] value:\n%s", got)
+	}
+}
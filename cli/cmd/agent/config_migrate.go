@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/configmerge"
+	"github.com/spf13/cobra"
+)
+
+var (
+	configMigrateFile   string
+	configMigrateDryRun bool
+)
+
+var configMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Upgrade ai-agent.yaml to the current config schema",
+	Long: fmt.Sprintf(`Upgrade config/ai-agent.yaml to config_version %d.
+
+Reads the config_version marker, prints the migrations needed to reach the current
+schema, and (unless --dry-run is set) applies them and writes the file back, keeping its
+comments and key order. Files without config_version are treated as current, matching the engine.`, configmerge.CurrentSchemaVersion),
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := configMigrateFile
+		if !filepath.IsAbs(path) {
+			repoRoot, err := resolveRepoRootForFix()
+			if err != nil {
+				return err
+			}
+			if !cmd.Flags().Changed("file") {
+				path = baseConfigRel(repoRoot)
+			}
+			path = filepath.Join(repoRoot, path)
+		}
+
+		m, err := configmerge.ReadYAMLFileRaw(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		from, err := configmerge.SchemaVersionOf(m)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if from > configmerge.CurrentSchemaVersion {
+			return fmt.Errorf("%s: config_version %d is newer than this CLI supports (%d); update the agent CLI", path, from, configmerge.CurrentSchemaVersion)
+		}
+		pending := configmerge.PendingMigrations(from)
+		if len(pending) == 0 {
+			fmt.Printf("%s is at config_version %d (current). Nothing to migrate.\n", path, from)
+			return nil
+		}
+
+		fmt.Printf("%s: config_version %d -> %d\n", path, from, configmerge.CurrentSchemaVersion)
+		for _, v := range pending {
+			fmt.Printf("  -> %d: %s\n", v, configmerge.DescribeMigration(v))
+		}
+		if configMigrateDryRun {
+			fmt.Println("Dry run: no changes written.")
+			return nil
+		}
+
+		if _, err := configmerge.MigrateYAMLFile(path, true); err != nil {
+			return err
+		}
+		fmt.Printf("Migrated %s to config_version %d.\n", path, configmerge.CurrentSchemaVersion)
+		return nil
+	},
+}
+
+func init() {
+	configMigrateCmd.Flags().StringVar(&configMigrateFile, "file", filepath.Join("config", "ai-agent.yaml"), "config file to migrate (relative to the repo root; with --env, config/ai-agent.<name>.yaml if it exists)")
+	configMigrateCmd.Flags().BoolVar(&configMigrateDryRun, "dry-run", false, "print the migrations that would run without writing")
+	configCmd.AddCommand(configMigrateCmd)
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/config"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/environment"
+	"github.com/spf13/cobra"
+)
+
+var (
+	configResetPreserveCredentials bool
+	configResetYes                 bool
+)
+
+var configResetCmd = &cobra.Command{
+	Use:   "reset",
+	Short: "Restore the factory config from the defaults built into agent",
+	Long: `Replace the operator config with the defaults shipped with this agent release: .env from
+.env.example, config/ai-agent.yaml, and config/contexts/ holding only the shipped demo context.
+config/ai-agent.local.yaml and any other contexts are removed.
+
+The current state is first snapshotted to .agent/check-fix-backups/ (undo with agent rollback).
+With --preserve-credentials, ASTERISK_HOST, ASTERISK_ARI_USERNAME, ASTERISK_ARI_PASSWORD and
+every *_API_KEY are carried over from the old .env. config/users.json is not touched.
+
+With --env NAME the environment's own files are reset; the shared config/ai-agent.yaml and
+config/contexts/ are only reset for the default environment, and the shared ai_engine and
+admin_ui containers are not restarted.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runConfigReset()
+	},
+}
+
+func init() {
+	configResetCmd.Flags().BoolVar(&configResetPreserveCredentials, "preserve-credentials", false, "keep the Asterisk ARI connection and AI provider API keys from the current .env")
+	configResetCmd.Flags().BoolVarP(&configResetYes, "yes", "y", false, "reset without asking for confirmation")
+
+	configCmd.AddCommand(configResetCmd)
+}
+
+func runConfigReset() error {
+	repoRoot, err := resolveRepoRootForFix()
+	if err != nil {
+		return err
+	}
+	if err := os.Chdir(repoRoot); err != nil {
+		return fmt.Errorf("failed to switch to repo root: %w", err)
+	}
+
+	question := fmt.Sprintf("Reset the config in %s to factory defaults?", repoRoot)
+	if !configResetPreserveCredentials {
+		question = fmt.Sprintf("Reset the config in %s to factory defaults, including ARI credentials and API keys?", repoRoot)
+	}
+	if !configResetYes && !confirmRollback(os.Stdin, question) {
+		fmt.Println("Reset cancelled. No files were changed.")
+		return nil
+	}
+
+	err = config.ResetConfig(repoRoot, config.ResetOptions{
+		Env:                 agentEnv,
+		PreserveCredentials: configResetPreserveCredentials,
+		Snapshot: func() (string, error) {
+			summary := &fixSummary{repoRoot: repoRoot}
+			err := snapshotBeforeFix(repoRoot, summary)
+			return summary.prefixBackup, err
+		},
+		Out: os.Stdout,
+	})
+	if err != nil {
+		return err
+	}
+
+	if agentEnv != environment.Default {
+		fmt.Printf("Config of environment %s reset. Run agent --env %s check to verify.\n", agentEnv, agentEnv)
+		return nil
+	}
+	if err := restartCoreServices(); err != nil {
+		return fmt.Errorf("config reset but restart failed: %w", err)
+	}
+	fmt.Println("Config reset and ai_engine/admin_ui restarted. Run agent check to verify.")
+	return nil
+}
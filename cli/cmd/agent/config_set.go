@@ -0,0 +1,95 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/configmerge"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var configSetString bool
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Set one key in config/ai-agent.local.yaml",
+	Long: `Set one key in config/ai-agent.local.yaml using dot notation, e.g.
+
+  agent config set audiosocket.port 8091
+  agent config set providers.openai_realtime.model gpt-4o
+
+The value is parsed as a YAML scalar or flow value, so 8091 is an integer, true a boolean,
+null deletes the key from the merged config, and [a, b] a list. Use --string to store the
+value verbatim as a string. Intermediate mappings are created as needed; comments and key
+order elsewhere in the file are kept. The file is replaced atomically.
+
+Restart ai_engine to apply the change.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		repoRoot, err := resolveRepoRootForFix()
+		if err != nil {
+			return err
+		}
+		var value any = args[1]
+		if !configSetString {
+			if err := yaml.Unmarshal([]byte(args[1]), &value); err != nil {
+				return fmt.Errorf("invalid value %q (use --string to store it verbatim): %w", args[1], err)
+			}
+		}
+		store := configmerge.NewConfigStore(filepath.Join(repoRoot, envRel(filepath.Join("config", "ai-agent.local.yaml"))))
+		if err := store.Set(args[0], value); err != nil {
+			return err
+		}
+		fmt.Printf("Set %s in %s\n", args[0], store.Path)
+		return nil
+	},
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print one key from the local override or base config",
+	Long: `Print the value of a dot-notation key. config/ai-agent.local.yaml is consulted first;
+if the key is not overridden there, config/ai-agent.yaml is used and a note naming the
+source is printed to stderr. Scalars are printed bare; mappings and lists as YAML.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		repoRoot, err := resolveRepoRootForFix()
+		if err != nil {
+			return err
+		}
+		value, err := configmerge.NewConfigStore(filepath.Join(repoRoot, envRel(filepath.Join("config", "ai-agent.local.yaml")))).Get(args[0])
+		if errors.Is(err, configmerge.ErrKeyNotFound) {
+			base := baseConfigRel(repoRoot)
+			value, err = configmerge.NewConfigStore(filepath.Join(repoRoot, base)).Get(args[0])
+			if err == nil {
+				fmt.Fprintf(os.Stderr, "(from %s)\n", filepath.ToSlash(base))
+			}
+		}
+		if err != nil {
+			return err
+		}
+		switch value.(type) {
+		case map[string]any, []any:
+			b, err := yaml.Marshal(value)
+			if err != nil {
+				return err
+			}
+			fmt.Print(string(b))
+		case nil:
+			fmt.Println("null")
+		default:
+			fmt.Println(strings.TrimSpace(fmt.Sprint(value)))
+		}
+		return nil
+	},
+}
+
+func init() {
+	configSetCmd.Flags().BoolVar(&configSetString, "string", false, "store the value as a string without YAML parsing")
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configGetCmd)
+}
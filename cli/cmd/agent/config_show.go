@@ -0,0 +1,96 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/backup"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/check"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/configmerge"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/secrets"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	configShowEffective bool
+	configShowRedact    bool
+	configShowStrictEnv bool
+)
+
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Print the merged config, optionally with ${VAR} references resolved",
+	Long: `Print config/ai-agent.local.yaml merged over config/ai-agent.yaml as YAML, the way the
+engine merges them (see agent config merge).
+
+With --effective, ${VAR} references are also resolved against .env the way the engine
+expands them: ${VAR:-default} and ${VAR:=default} use the default when VAR is unset or
+empty, and a ${VAR} whose key .env does not set is left as written (with a warning, or an
+error under --strict-env). Values that are only a reference take the type they expand to.
+Variables set only in docker-compose.yml or the shell are not seen.
+
+With --redact, values under credential-like keys (KEY, SECRET, PASSWORD or TOKEN in the
+name), and values resolved from such .env keys, are printed as ***.`,
+	Example: `  agent config show --effective --redact
+  agent config show --effective --strict-env > /dev/null`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if configShowStrictEnv && !configShowEffective {
+			return errors.New("--strict-env only applies with --effective")
+		}
+		repoRoot, err := resolveRepoRootForFix()
+		if err != nil {
+			return err
+		}
+		base, local, err := readConfigLayers(repoRoot)
+		if err != nil {
+			return err
+		}
+		cfg, err := configmerge.MergeYAML(base, local, configmerge.StrategyOverlay)
+		if err != nil {
+			return err
+		}
+
+		if configShowEffective {
+			envMap, envPath, err := secrets.LoadEnv(filepath.Join(repoRoot, envRel(".env"))) // .env.age is decrypted in memory
+			if err != nil {
+				return fmt.Errorf("failed to read %s: %w", envPath, err)
+			}
+			env := make(map[string]string, len(envMap))
+			for k, v := range envMap {
+				v = check.EnvValue(v)
+				if configShowRedact && v != "" && backup.IsSecretEnvKey(k) {
+					v = configmerge.RedactMask
+				}
+				env[k] = v
+			}
+			cfg, err = configmerge.ResolveEnvRefs(cfg, env)
+			if err != nil {
+				if configShowStrictEnv || !errors.Is(err, configmerge.ErrUndefinedEnvRef) {
+					return err
+				}
+				fmt.Fprintf(os.Stderr, "Warning: %v (not set in %s; left unexpanded)\n", err, filepath.Base(envPath))
+			}
+		}
+		if configShowRedact {
+			cfg = configmerge.RedactSecrets(cfg)
+		}
+
+		out, err := yaml.Marshal(cfg)
+		if err != nil {
+			return err
+		}
+		_, err = os.Stdout.Write(out)
+		return err
+	},
+}
+
+func init() {
+	configShowCmd.Flags().BoolVar(&configShowEffective, "effective", false, "resolve ${VAR} references against .env")
+	configShowCmd.Flags().BoolVar(&configShowRedact, "redact", false, "print credential values as "+configmerge.RedactMask)
+	configShowCmd.Flags().BoolVar(&configShowStrictEnv, "strict-env", false, "with --effective, fail when a ${VAR} reference names a key .env does not set")
+	configCmd.AddCommand(configShowCmd)
+}
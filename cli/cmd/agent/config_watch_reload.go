@@ -0,0 +1,56 @@
+package main
+
+import (
+	"os"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/reload"
+	"github.com/spf13/cobra"
+)
+
+var (
+	watchReloadNoValidate bool
+	watchReloadSignal     string
+	watchReloadService    string
+)
+
+var configWatchReloadCmd = &cobra.Command{
+	Use:   "watch-reload",
+	Short: "Reload ai_engine's config after each valid change under config/",
+	Long: `Watch the YAML files under config/ and, after each burst of writes, send SIGHUP to ai_engine
+with docker compose kill. ai_engine reloads ai-agent.yaml and the contexts on SIGHUP the same
+way as POST /reload: changes apply to new calls, active calls keep the previous config.
+
+A reload is only sent when every changed file passes the validator agent check --fix uses
+(YAML that parses to a mapping, no git conflict markers); --no-validate skips this. After the
+signal, the ai_engine log is polled for the reload result for up to 5s and reported.
+
+.env changes are not reloaded: containers read .env only when they are created.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sig, err := reload.ParseSignal(watchReloadSignal)
+		if err != nil {
+			return err
+		}
+		repoRoot, err := resolveRepoRootForFix()
+		if err != nil {
+			return err
+		}
+		r := &reload.Reloader{
+			Root:       repoRoot,
+			Service:    watchReloadService,
+			Signal:     sig,
+			Env:        agentEnv,
+			NoValidate: watchReloadNoValidate,
+			Out:        os.Stdout,
+		}
+		return r.Run()
+	},
+}
+
+func init() {
+	configWatchReloadCmd.Flags().BoolVar(&watchReloadNoValidate, "no-validate", false, "reload even when a changed file fails validation")
+	configWatchReloadCmd.Flags().StringVar(&watchReloadSignal, "signal", "SIGHUP", "signal that makes the service reload its config")
+	configWatchReloadCmd.Flags().StringVar(&watchReloadService, "service", reload.DefaultService, "Compose service to signal")
+
+	configCmd.AddCommand(configWatchReloadCmd)
+}
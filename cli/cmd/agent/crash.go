@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/crash"
+	"github.com/spf13/cobra"
+)
+
+var crashCmd = &cobra.Command{
+	Use:    "crash",
+	Short:  "List and print agent crash reports",
+	Hidden: true, // only useful after a crash; the crash message points here
+	Long: `When an agent command panics, it prints a short message and writes a crash report to
+.agent/crash-<timestamp>.txt under the repo root (the system temp directory outside a
+checkout): the panic, the stack, the agent and Go versions, the command line with credential
+values masked, and the names (not values) of the environment variables set.`,
+}
+
+var crashListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List crash reports, newest first",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		repoRoot, err := resolveRepoRootForFix()
+		if err != nil {
+			return err
+		}
+		reports, err := crash.List(repoRoot)
+		if err != nil {
+			return err
+		}
+		if len(reports) == 0 {
+			fmt.Println("No crash reports.")
+			return nil
+		}
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "REPORT\tTIME\tPANIC")
+		for _, r := range reports {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", r.Name, r.Time.Format("2006-01-02 15:04:05"), r.Summary)
+		}
+		return tw.Flush()
+	},
+}
+
+var crashShowCmd = &cobra.Command{
+	Use:   "show <file>",
+	Short: "Print a crash report",
+	Long:  `Print a crash report, given as a path or as a name listed by agent crash list.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		repoRoot, err := resolveRepoRootForFix()
+		if err != nil {
+			return err
+		}
+		path, err := crash.Resolve(repoRoot, args[0])
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		_, err = os.Stdout.Write(data)
+		return err
+	},
+}
+
+func init() {
+	crashCmd.AddCommand(crashListCmd)
+	crashCmd.AddCommand(crashShowCmd)
+	rootCmd.AddCommand(crashCmd)
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/diagnose"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/logging"
+	"github.com/spf13/cobra"
+)
+
+var (
+	diagnoseOutput   string
+	diagnoseUpload   string
+	diagnoseLogLines int
+)
+
+var diagnoseCmd = &cobra.Command{
+	Use:    "diagnose",
+	Short:  "Collect an anonymized diagnostic bundle for a support ticket",
+	Hidden: true, // advanced tool; agent check output is enough for most issues
+	Long: `Collect an anonymized diagnostic bundle (tar.gz) to attach to a support ticket.
+
+The bundle contains:
+  - report.json               the agent check report
+  - compose-ps.json           docker compose ps --format json
+  - logs/<service>.log        the last --log-lines lines of each Compose service's logs
+  - config/ai-agent.yaml      with every value under a key containing PASSWORD, SECRET, KEY
+                              or TOKEN replaced by ` + diagnose.RedactedValue + ` (ai-agent.local.yaml too)
+  - bundle.json               file list and anything that could not be collected
+
+.env is never included. Log lines of the form NAME=value or NAME: value are redacted by the
+same rule. Review the bundle before sharing it.
+
+With --upload URL, the bundle is POSTed as multipart/form-data (field "bundle") and the
+ticket ID returned by the support endpoint is printed. Without --upload it is written to
+--output.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runDiagnose(cmd)
+	},
+}
+
+func init() {
+	diagnoseCmd.Flags().StringVarP(&diagnoseOutput, "output", "o", "", "bundle file to write (default agent-diagnostics-<timestamp>.tar.gz; with --upload, only written when set)")
+	diagnoseCmd.Flags().StringVar(&diagnoseUpload, "upload", "", "support endpoint URL to POST the bundle to")
+	diagnoseCmd.Flags().IntVar(&diagnoseLogLines, "log-lines", diagnose.DefaultLogLines, "log lines to collect per Compose service")
+	rootCmd.AddCommand(diagnoseCmd)
+}
+
+func runDiagnose(cmd *cobra.Command) error {
+	if diagnoseLogLines < 1 {
+		return fmt.Errorf("--log-lines must be at least 1")
+	}
+	name := fmt.Sprintf("agent-diagnostics-%s.tar.gz", time.Now().UTC().Format("20060102-150405"))
+	output := diagnoseOutput
+	if output == "" && diagnoseUpload == "" {
+		output = name
+	}
+	if output != "" {
+		abs, err := filepath.Abs(output)
+		if err != nil {
+			return err
+		}
+		output = abs
+		name = filepath.Base(abs)
+	}
+	repoRoot, err := resolveRepoRootForFix()
+	if err != nil {
+		return err
+	}
+	if err := os.Chdir(repoRoot); err != nil {
+		return fmt.Errorf("failed to switch to repo root: %w", err)
+	}
+
+	log := logging.FromContext(cmd.Context())
+	runner := newCheckRunner()
+	runner.Logger = log
+	fmt.Println("Collecting diagnostics (agent check, docker compose ps/logs, redacted config)...")
+	r, err := diagnose.BuildDiagnosticBundle(runner, diagnose.BundleOptions{RepoRoot: repoRoot, LogLines: diagnoseLogLines})
+	if err != nil {
+		return err
+	}
+	bundle, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	if output != "" {
+		// Redacted, but logs and config still describe the deployment: keep it owner-only.
+		if err := os.WriteFile(output, bundle, 0o600); err != nil {
+			return fmt.Errorf("failed to write %s: %w", output, err)
+		}
+		fmt.Printf("Wrote diagnostic bundle to %s (%d bytes)\n", output, len(bundle))
+	}
+	if diagnoseUpload == "" {
+		return nil
+	}
+
+	log.Debug("uploading diagnostic bundle", "url", diagnoseUpload, "bytes", len(bundle))
+	ticket, err := diagnose.Upload(cmd.Context(), nil, diagnoseUpload, name, bytes.NewReader(bundle))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Uploaded diagnostic bundle. Ticket ID: %s\n", ticket)
+	return nil
+}
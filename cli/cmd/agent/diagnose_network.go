@@ -0,0 +1,101 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/check"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/check/network"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/exitcodes"
+	"github.com/spf13/cobra"
+)
+
+var (
+	diagnoseNetworkExtra string
+	diagnoseNetworkJSON  bool
+)
+
+var diagnoseNetworkCmd = &cobra.Command{
+	Use:   "network",
+	Short: "Check outbound connectivity to the external AI service endpoints",
+	Long: `Send an unauthenticated GET to each AI provider endpoint (OpenAI, ElevenLabs, Google
+Speech-to-Text, Deepgram, Azure Speech) and compare the HTTP status with the one the provider
+returns for a request without credentials. Each probe times out after 5 seconds.
+
+An unreachable endpoint fails; one that answers with another status warns, since a proxy or
+TLS-inspecting firewall may be answering in its place. HTTPS_PROXY is honoured.
+
+--extra-endpoints adds endpoints from a JSON or YAML list:
+
+  - name: Internal LLM
+    url: https://llm.example.internal/health
+    expected_status: 200   # optional; omit to accept any status
+
+Exit codes: 0 all reachable, 1 warnings, 2 an endpoint is unreachable.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		specs, err := network.DefaultEndpoints()
+		if err != nil {
+			return err
+		}
+		if diagnoseNetworkExtra != "" {
+			extra, err := network.LoadEndpoints(diagnoseNetworkExtra)
+			if err != nil {
+				return fmt.Errorf("--extra-endpoints: %w", err)
+			}
+			specs = append(specs, extra...)
+		}
+
+		items := network.CheckExternalConnectivity(specs)
+		if diagnoseNetworkJSON {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(items); err != nil {
+				return err
+			}
+		} else {
+			printNetworkItems(items)
+		}
+
+		exitCode := exitcodes.ExitOK
+		for _, it := range items {
+			switch {
+			case it.Status == check.StatusFail:
+				exitCode = exitcodes.ExitFail
+			case it.Status == check.StatusWarn && exitCode == exitcodes.ExitOK:
+				exitCode = exitcodes.ExitWarn
+			}
+		}
+		if exitCode != exitcodes.ExitOK {
+			os.Exit(exitCode)
+		}
+		return nil
+	},
+}
+
+func init() {
+	diagnoseNetworkCmd.Flags().StringVar(&diagnoseNetworkExtra, "extra-endpoints", "", "JSON or YAML file with additional endpoints to probe")
+	diagnoseNetworkCmd.Flags().BoolVar(&diagnoseNetworkJSON, "json", false, "output the results as JSON")
+	diagnoseCmd.AddCommand(diagnoseNetworkCmd)
+}
+
+func printNetworkItems(items []check.Item) {
+	gray := color.New(color.FgHiBlack).SprintFunc()
+	for _, it := range items {
+		icon := "✅"
+		switch it.Status {
+		case check.StatusWarn:
+			icon = "⚠️ "
+		case check.StatusFail:
+			icon = "❌"
+		}
+		fmt.Printf("%s %s: %s\n", icon, it.Name, it.Message)
+		fmt.Printf("   %s\n", gray(strings.ReplaceAll(it.Details, "\n", "\n   ")))
+		if it.Remediation != "" {
+			fmt.Printf("   → %s\n", it.Remediation)
+		}
+	}
+}
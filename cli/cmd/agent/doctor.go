@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/check"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/exitcodes"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/logging"
+	"github.com/spf13/cobra"
+)
+
+var (
+	doctorJSON bool
+	doctorOpen bool
+)
+
+var doctorCmd = &cobra.Command{
+	Use:    "doctor",
+	Short:  "Explain failing checks with remediation steps and doc links",
+	Hidden: true,
+	Long: `Run the same diagnostics as agent check, but print only the warnings and failures,
+each expanded with its details, a remediation step and a link to the relevant docs.
+
+Use --open to open the documentation for the first failure (or warning) in a browser.
+
+Exit codes match agent check (0 pass, 1 warn, 2 fail).`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		runner := newCheckRunner()
+		runner.Logger = logging.FromContext(cmd.Context())
+		report, err := runner.RunWithTimeout(context.Background(), check.DefaultTimeout)
+		if report == nil {
+			return err
+		}
+
+		var problems []check.Item
+		for _, item := range report.Items {
+			if item.Status == check.StatusFail || item.Status == check.StatusWarn {
+				problems = append(problems, item)
+			}
+		}
+
+		if doctorJSON {
+			if problems == nil {
+				problems = []check.Item{}
+			}
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			_ = enc.Encode(problems)
+		} else {
+			printDoctorItems(problems)
+		}
+
+		if doctorOpen {
+			if url := firstDocURL(problems); url != "" {
+				if err := openURL(url); err != nil {
+					fmt.Fprintf(os.Stderr, "Could not open %s: %v\n", url, err)
+				}
+			}
+		}
+
+		if report.FailCount > 0 {
+			os.Exit(exitcodes.ExitFail)
+		}
+		if report.WarnCount > 0 {
+			os.Exit(exitcodes.ExitWarn)
+		}
+		return nil
+	},
+}
+
+func init() {
+	doctorCmd.Flags().BoolVar(&doctorJSON, "json", false, "output the failing/warning items as JSON")
+	doctorCmd.Flags().BoolVar(&doctorOpen, "open", false, "open the docs for the first failing item (xdg-open/open)")
+	rootCmd.AddCommand(doctorCmd)
+}
+
+func printDoctorItems(items []check.Item) {
+	if len(items) == 0 {
+		color.New(color.FgGreen, color.Bold).Println("No warnings or failures - system looks healthy.")
+		return
+	}
+	red := color.New(color.FgRed, color.Bold).SprintFunc()
+	yellow := color.New(color.FgYellow, color.Bold).SprintFunc()
+	gray := color.New(color.FgHiBlack).SprintFunc()
+
+	for i, item := range items {
+		if i > 0 {
+			fmt.Println("")
+		}
+		if item.Status == check.StatusFail {
+			fmt.Printf("%s %s\n", red("FAIL"), item.Name)
+		} else {
+			fmt.Printf("%s %s\n", yellow("WARN"), item.Name)
+		}
+		fmt.Printf("  %s %s\n", gray("Message:    "), item.Message)
+		if item.Details != "" {
+			fmt.Printf("  %s %s\n", gray("Details:    "), doctorIndent(item.Details))
+		}
+		if item.Remediation != "" {
+			fmt.Printf("  %s %s\n", gray("Remediation:"), doctorIndent(item.Remediation))
+		}
+		if item.DocURL != "" {
+			fmt.Printf("  %s %s\n", gray("Docs:       "), item.DocURL)
+		}
+	}
+}
+
+// doctorIndent aligns continuation lines of multi-line fields with the value column.
+func doctorIndent(s string) string {
+	return strings.ReplaceAll(s, "\n", "\n"+strings.Repeat(" ", 15))
+}
+
+// firstDocURL prefers the first failing item's link, falling back to the first warning.
+func firstDocURL(items []check.Item) string {
+	for _, status := range []check.Status{check.StatusFail, check.StatusWarn} {
+		for _, item := range items {
+			if item.Status == status && item.DocURL != "" {
+				return item.DocURL
+			}
+		}
+	}
+	return ""
+}
+
+func openURL(url string) error {
+	name := "xdg-open"
+	switch runtime.GOOS {
+	case "darwin":
+		name = "open"
+	case "windows":
+		return errors.New("--open is not supported on Windows")
+	}
+	return exec.Command(name, url).Start()
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/check"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/environment"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/secrets"
+	"github.com/spf13/cobra"
+)
+
+var (
+	envCheckFile   string
+	envCheckStrict bool
+)
+
+var envCmd = &cobra.Command{
+	Use:    "env",
+	Short:  "Inspect and encrypt the .env file",
+	Hidden: true, // advanced tool; `agent check` covers the common cases
+}
+
+var envCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Validate .env against the known-key schema",
+	Long: `Validate .env against the schema of known keys (cli/internal/check/env.schema.yaml).
+If only .env.age exists, it is decrypted in memory with AGENT_ENV_IDENTITY_FILE.
+
+Reports:
+  - missing required keys (FAIL)
+  - values that fail their type check, e.g. a non-numeric port (FAIL)
+  - keys the schema does not know about, usually typos (WARN)
+
+Exit codes:
+  0 - No issues
+  1 - Warnings only
+  2 - Errors found`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := envCheckFile
+		if path == "" {
+			repoRoot, err := resolveRepoRootForFix()
+			if err != nil {
+				return err
+			}
+			path = filepath.Join(repoRoot, envRel(".env"))
+		}
+		envMap, path, err := secrets.LoadEnv(path) // falls back to .env.age, decrypted in memory
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		schema := check.DefaultEnvSchema()
+		issues := schema.Check(envMap)
+
+		fmt.Println("")
+		fmt.Printf("Checking %s (%d keys set, %d known)...\n", path, len(envMap), len(schema.Keys))
+		fmt.Println("")
+
+		failCount, warnCount := 0, 0
+		if len(issues) > 0 {
+			tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(tw, "KEY\tSTATUS\tMESSAGE")
+			for _, is := range issues {
+				switch is.Status {
+				case check.StatusFail:
+					failCount++
+				case check.StatusWarn:
+					warnCount++
+				}
+				fmt.Fprintf(tw, "%s\t%s\t%s\n", is.Key, strings.ToUpper(string(is.Status)), is.Message)
+			}
+			_ = tw.Flush()
+			fmt.Println("")
+		}
+		fmt.Printf("Summary: %d warning(s), %d error(s)\n", warnCount, failCount)
+
+		exitCode := 0
+		if failCount > 0 || (envCheckStrict && warnCount > 0) {
+			exitCode = 2
+		} else if warnCount > 0 {
+			exitCode = 1
+		}
+		if exitCode != 0 {
+			os.Exit(exitCode)
+		}
+		return nil
+	},
+}
+
+var envListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the environments that have config files",
+	Long: `List the environment names usable with --env (or AGENT_ENV), found from suffixed files
+in the repo root: .env.<name>, config/ai-agent.<name>.yaml and config/ai-agent.local.<name>.yaml.
+"default" is the unsuffixed .env and config/ai-agent.local.yaml. The active environment is
+marked with *.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		repoRoot, err := resolveRepoRootForFix()
+		if err != nil {
+			return err
+		}
+		names, err := environment.List(repoRoot)
+		if err != nil {
+			return err
+		}
+		if len(names) == 0 {
+			fmt.Println("No environments found.")
+			return nil
+		}
+		for _, name := range names {
+			marker := " "
+			if name == agentEnv {
+				marker = "*"
+			}
+			fmt.Printf("%s %s\n", marker, name)
+		}
+		return nil
+	},
+}
+
+// envRel maps an operator config path (relative to the repo root) to the active --env
+// environment, e.g. .env -> .env.production.
+func envRel(rel string) string {
+	return environment.Path(rel, agentEnv)
+}
+
+// newCheckRunner returns a check runner for the active --env.
+func newCheckRunner() *check.Runner {
+	runner := check.NewRunner(verbose, version, buildTime)
+	runner.Env = agentEnv
+	return runner
+}
+
+// baseConfigRel is config/ai-agent.<name>.yaml when the active environment has its own base
+// config, else the shared config/ai-agent.yaml.
+func baseConfigRel(repoRoot string) string {
+	base := filepath.Join("config", "ai-agent.yaml")
+	if rel := envRel(base); rel != base {
+		if _, err := os.Stat(filepath.Join(repoRoot, rel)); err == nil {
+			return rel
+		}
+	}
+	return base
+}
+
+func init() {
+	envCheckCmd.Flags().StringVar(&envCheckFile, "file", "", "path to the env file (default: <repo root>/.env, or .env.<name> with --env)")
+	envCheckCmd.Flags().BoolVar(&envCheckStrict, "strict", false, "treat unknown keys as errors")
+
+	envCmd.AddCommand(envCheckCmd, envListCmd)
+	rootCmd.AddCommand(envCmd)
+}
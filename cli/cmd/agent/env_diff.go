@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/env"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/exitcodes"
+	"github.com/spf13/cobra"
+)
+
+var (
+	envDiffExample string
+	envDiffCurrent string
+)
+
+var envDiffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Compare .env with .env.example",
+	Long: `Compare .env with .env.example, typically after an update added new keys. Commented-out
+keys in either file do not count. If only .env.age exists, it is decrypted in memory.
+
+Reports:
+  + keys .env.example sets that .env does not, with the example value
+  - keys only .env sets (often fine: local or removed settings)
+  ~ keys .env still leaves at a placeholder such as CHANGE_ME or your_api_key_here
+Values of credential keys (passwords, tokens, API keys) are masked.
+
+Exit codes:
+  0 - .env has every key .env.example sets
+  1 - keys are missing from .env`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		example, current := envDiffExample, envDiffCurrent
+		if example == "" || current == "" {
+			repoRoot, err := resolveRepoRootForFix()
+			if err != nil {
+				return err
+			}
+			if example == "" {
+				example = filepath.Join(repoRoot, env.ExampleFile)
+			}
+			if current == "" {
+				current = filepath.Join(repoRoot, envRel(".env"))
+			}
+		}
+		d, err := env.DiffEnvFiles(current, example)
+		if err != nil {
+			return err
+		}
+
+		fmt.Println("")
+		fmt.Printf("Comparing %s with %s...\n", current, example)
+		fmt.Println("")
+		if len(d.MissingInA) > 0 {
+			fmt.Printf("Missing from %s:\n", filepath.Base(current))
+			for _, key := range d.MissingInA {
+				fmt.Printf("  + %s=%s\n", key, env.MaskValue(key, d.B[key]))
+			}
+			fmt.Println("")
+		}
+		if len(d.MissingInB) > 0 {
+			fmt.Printf("Not in %s:\n", filepath.Base(example))
+			for _, key := range d.MissingInB {
+				fmt.Printf("  - %s\n", key)
+			}
+			fmt.Println("")
+		}
+		if len(d.Mismatched) > 0 {
+			fmt.Println("Placeholder values:")
+			for _, key := range d.Mismatched {
+				fmt.Printf("  ~ %s=%s\n", key, env.MaskValue(key, d.A[key]))
+			}
+			fmt.Println("")
+		}
+		fmt.Printf("Summary: %d missing, %d extra, %d placeholder(s)\n", len(d.MissingInA), len(d.MissingInB), len(d.Mismatched))
+
+		if len(d.MissingInA) > 0 {
+			fmt.Println("Add the missing keys by hand or with: agent env generate --merge")
+			os.Exit(exitcodes.ExitWarn)
+		}
+		return nil
+	},
+}
+
+func init() {
+	envDiffCmd.Flags().StringVar(&envDiffExample, "example", "", "path to the example file (default: <repo root>/.env.example)")
+	envDiffCmd.Flags().StringVar(&envDiffCurrent, "current", "", "path to the env file (default: <repo root>/.env, or .env.<name> with --env)")
+	envCmd.AddCommand(envDiffCmd)
+}
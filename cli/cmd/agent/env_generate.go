@@ -0,0 +1,94 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/env"
+	"github.com/spf13/cobra"
+)
+
+var (
+	envGenerateOutput string
+	envGenerateSet    []string
+	envGenerateMerge  bool
+)
+
+var envGenerateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Write a populated .env from the built-in template",
+	Long: `Write a .env from the template built into the binary (the shipped .env.example with every
+known key and its comments). Values come from --set, then the template defaults; JWT_SECRET is
+generated with crypto/rand. Keys that are still empty (the ARI host and credentials, provider
+API keys) are prompted for; only the ARI keys are required. Secrets are read without echo on
+a terminal.
+
+An existing .env is never overwritten. With --merge, the keys it does not set yet are appended
+instead, so an older .env picks up keys added by newer releases.
+
+  agent env generate
+  agent env generate --set ASTERISK_HOST=10.0.0.5 --set ASTERISK_ARI_USERNAME=ai < secrets.txt
+  agent env generate --merge`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		answers := map[string]string{}
+		for _, kv := range envGenerateSet {
+			name, value, ok := strings.Cut(kv, "=")
+			if !ok || strings.TrimSpace(name) == "" {
+				return fmt.Errorf("--set %q: want KEY=VALUE", kv)
+			}
+			answers[strings.TrimSpace(name)] = value
+		}
+		path := envGenerateOutput
+		if path == "" {
+			repoRoot, err := resolveRepoRootForFix()
+			if err != nil {
+				return err
+			}
+			path = filepath.Join(repoRoot, envRel(".env"))
+		}
+		tmpl, err := env.DefaultTemplate()
+		if err != nil {
+			return err
+		}
+		if fi, err := os.Stdin.Stat(); err == nil && fi.Mode()&os.ModeCharDevice != 0 {
+			env.ReadPassword = readPasswordTTY
+		}
+
+		if envGenerateMerge {
+			added, err := env.MergeEnvFile(tmpl, answers, path)
+			if errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("%s does not exist; run without --merge to create it", path)
+			}
+			if err != nil {
+				return err
+			}
+			if len(added) == 0 {
+				fmt.Printf("%s already sets every known key\n", path)
+				return nil
+			}
+			fmt.Printf("Added %d key(s) to %s: %s\n", len(added), path, strings.Join(added, ", "))
+			return nil
+		}
+
+		if err := env.GenerateEnvFile(tmpl, answers, path); err != nil {
+			if errors.Is(err, env.ErrExists) {
+				return fmt.Errorf("%s already exists; use --merge to add missing keys", path)
+			}
+			return err
+		}
+		fmt.Printf("Wrote %s\n", path)
+		fmt.Println("Run 'agent env check' to validate it.")
+		return nil
+	},
+}
+
+func init() {
+	envGenerateCmd.Flags().StringVarP(&envGenerateOutput, "output", "o", "", "file to write (default: <repo root>/.env, or .env.<name> with --env)")
+	envGenerateCmd.Flags().StringArrayVar(&envGenerateSet, "set", nil, "KEY=VALUE answer, skipping its prompt (repeatable)")
+	envGenerateCmd.Flags().BoolVar(&envGenerateMerge, "merge", false, "append only the keys missing from an existing file")
+	envCmd.AddCommand(envGenerateCmd)
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/secrets"
+	"github.com/spf13/cobra"
+)
+
+var (
+	envEncryptRecipient string
+	envEncryptNoBackup  bool
+	envDecryptIdentity  string
+	envDecryptForce     bool
+)
+
+var envEncryptCmd = &cobra.Command{
+	Use:   "encrypt",
+	Short: "Encrypt .env to .env.age and remove the plaintext",
+	Long: `Encrypt <repo root>/.env with age to .env.age (mode 0600), then remove .env.
+
+The recipient (public key, "age1...") comes from --recipient or AGENT_ENV_RECIPIENT. The
+plaintext is kept as .env.bak.<timestamp>, the pattern agent check --fix restores from; delete
+it once agent env decrypt works with your identity file, or pass --no-backup.
+
+While only .env.age exists, agent check decrypts it in memory with the identity file named by
+AGENT_ENV_IDENTITY_FILE; plaintext is never written to disk. With --env NAME the files are
+.env.NAME and .env.NAME.age.
+
+Note: ai_engine and local_ai_server load .env through env_file in docker-compose.yml, so run
+agent env decrypt before docker compose up or a container recreate.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		recipient := envEncryptRecipient
+		if recipient == "" {
+			recipient = strings.TrimSpace(os.Getenv(secrets.RecipientEnv))
+		}
+		if recipient == "" {
+			return fmt.Errorf("no age recipient: pass --recipient or set %s", secrets.RecipientEnv)
+		}
+		repoRoot, err := resolveRepoRootForFix()
+		if err != nil {
+			return err
+		}
+		plain := filepath.Join(repoRoot, envRel(".env"))
+		enc := plain + ".age"
+		if _, err := os.Stat(plain); err != nil {
+			return fmt.Errorf("nothing to encrypt: %w", err)
+		}
+
+		if err := secrets.EncryptEnv(plain, enc, recipient); err != nil {
+			return fmt.Errorf("failed to encrypt %s: %w", plain, err)
+		}
+		fmt.Printf("Wrote %s\n", enc)
+		if envEncryptNoBackup {
+			if err := os.Remove(plain); err != nil {
+				return err
+			}
+			fmt.Printf("Removed %s\n", plain)
+			return nil
+		}
+		bak := plain + ".bak." + time.Now().UTC().Format("20060102_150405")
+		if err := os.Rename(plain, bak); err != nil {
+			return fmt.Errorf("failed to back up %s: %w", plain, err)
+		}
+		fmt.Printf("Moved %s to %s (plaintext; delete it once agent env decrypt is verified)\n", plain, bak)
+		return nil
+	},
+}
+
+var envDecryptCmd = &cobra.Command{
+	Use:   "decrypt",
+	Short: "Decrypt .env.age back to .env",
+	Long: `Decrypt <repo root>/.env.age with the age identity file from --identity or
+AGENT_ENV_IDENTITY_FILE and write .env (mode 0600). .env.age is kept.
+
+An existing .env is not overwritten unless --force is given, in which case it is kept as
+.env.bak.<timestamp>.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		identity := envDecryptIdentity
+		if identity == "" {
+			identity = strings.TrimSpace(os.Getenv(secrets.IdentityFileEnv))
+		}
+		if identity == "" {
+			return fmt.Errorf("no age identity file: pass --identity or set %s", secrets.IdentityFileEnv)
+		}
+		repoRoot, err := resolveRepoRootForFix()
+		if err != nil {
+			return err
+		}
+		plain := filepath.Join(repoRoot, envRel(".env"))
+		enc := plain + ".age"
+		if _, err := os.Stat(enc); err != nil {
+			return fmt.Errorf("nothing to decrypt: %w", err)
+		}
+		if _, err := os.Stat(plain); err == nil {
+			if !envDecryptForce {
+				return errors.New(plain + " already exists; use --force to replace it")
+			}
+			bak := plain + ".bak." + time.Now().UTC().Format("20060102_150405")
+			if err := copyFile(plain, bak); err != nil {
+				return fmt.Errorf("failed to back up %s: %w", plain, err)
+			}
+			fmt.Printf("Kept the existing %s as %s\n", plain, bak)
+		}
+
+		if err := secrets.DecryptEnv(enc, plain, identity); err != nil {
+			return fmt.Errorf("failed to decrypt %s: %w", enc, err)
+		}
+		fmt.Printf("Wrote %s\n", plain)
+		return nil
+	},
+}
+
+func init() {
+	envEncryptCmd.Flags().StringVar(&envEncryptRecipient, "recipient", "", "age recipient public key (default: $"+secrets.RecipientEnv+")")
+	envEncryptCmd.Flags().BoolVar(&envEncryptNoBackup, "no-backup", false, "remove .env without keeping a plaintext .env.bak copy")
+	envDecryptCmd.Flags().StringVar(&envDecryptIdentity, "identity", "", "age identity file (default: $"+secrets.IdentityFileEnv+")")
+	envDecryptCmd.Flags().BoolVar(&envDecryptForce, "force", false, "replace an existing .env (keeping a .bak copy)")
+
+	envCmd.AddCommand(envEncryptCmd, envDecryptCmd)
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/check"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/history"
+	"github.com/spf13/cobra"
+)
+
+var (
+	fixHistoryLast       int
+	fixHistoryJSON       bool
+	fixHistorySince      string
+	fixHistoryBefore     string
+	fixHistoryFailedOnly bool
+)
+
+var fixCmd = &cobra.Command{
+	Use:    "fix",
+	Short:  "Inspect recoveries made by agent check --fix",
+	Hidden: true, // advanced tool; agent check --fix does the recovering
+}
+
+var fixHistoryCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show the recoveries agent check --fix applied",
+	Long: `Show the recoveries agent check --fix applied, newest first, from the append-only
+audit log .agent/fix-history.jsonl. Each entry records the backup restored from, the paths
+restored, any warnings, and the failing check count before and after the fix.
+
+--since and --before take an RFC3339 time or a 2006-01-02 date (midnight UTC) and keep the
+entries at or after --since and strictly before --before. --failed-only keeps the entries
+whose fix left checks failing. The filters combine, and --last applies to what they keep.
+
+--json prints the full records, including both check reports.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if fixHistoryLast < 0 {
+			return errors.New("--last must not be negative")
+		}
+		q := history.Query{FailedOnly: fixHistoryFailedOnly}
+		var err error
+		if fixHistorySince != "" {
+			if q.Since, err = history.ParseDate(fixHistorySince); err != nil {
+				return fmt.Errorf("--since: %w", err)
+			}
+		}
+		if fixHistoryBefore != "" {
+			if q.Before, err = history.ParseDate(fixHistoryBefore); err != nil {
+				return fmt.Errorf("--before: %w", err)
+			}
+		}
+		if !q.Since.IsZero() && !q.Before.IsZero() && !q.Since.Before(q.Before) {
+			return errors.New("--since must be earlier than --before")
+		}
+		repoRoot, err := resolveRepoRootForFix()
+		if err != nil {
+			return err
+		}
+		path := filepath.Join(repoRoot, filepath.FromSlash(check.FixHistoryPath))
+		records, skipped, err := history.Load(path, q)
+		if err != nil {
+			return err
+		}
+		if skipped > 0 {
+			fmt.Fprintf(os.Stderr, "Warning: skipped %d unreadable line(s) in %s\n", skipped, path)
+		}
+		// Newest first.
+		for i, j := 0, len(records)-1; i < j; i, j = i+1, j-1 {
+			records[i], records[j] = records[j], records[i]
+		}
+		if fixHistoryLast > 0 && len(records) > fixHistoryLast {
+			records = records[:fixHistoryLast]
+		}
+
+		if fixHistoryJSON {
+			if records == nil {
+				records = []check.FixRecord{}
+			}
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(records)
+		}
+		if len(records) == 0 && q != (history.Query{}) {
+			fmt.Printf("No recoveries in %s match the filters\n", path)
+			return nil
+		}
+		if len(records) == 0 {
+			fmt.Printf("No recoveries recorded in %s\n", path)
+			return nil
+		}
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "TIME (UTC)\tSOURCE BACKUP\tRESTORED\tFAILS BEFORE\tFAILS AFTER\tWARNINGS")
+		for _, r := range records {
+			source := r.SourceBackup
+			if rel, err := filepath.Rel(repoRoot, source); err == nil && filepath.IsAbs(source) {
+				source = rel
+			}
+			if source == "" {
+				source = "-"
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%d\t%d\n", r.Timestamp.UTC().Format("2006-01-02 15:04:05"),
+				source, len(r.RestoredPaths), r.BeforeFailCount, r.AfterFailCount, len(r.Warnings))
+		}
+		return tw.Flush()
+	},
+}
+
+func init() {
+	fixHistoryCmd.Flags().IntVar(&fixHistoryLast, "last", 10, "show the newest N recoveries (0 shows all)")
+	fixHistoryCmd.Flags().BoolVar(&fixHistoryJSON, "json", false, "output the full records as JSON")
+	fixHistoryCmd.Flags().StringVar(&fixHistorySince, "since", "", "only recoveries at or after this time (RFC3339 or 2006-01-02)")
+	fixHistoryCmd.Flags().StringVar(&fixHistoryBefore, "before", "", "only recoveries before this time (RFC3339 or 2006-01-02)")
+	fixHistoryCmd.Flags().BoolVar(&fixHistoryFailedOnly, "failed-only", false, "only recoveries that left checks failing")
+	fixCmd.AddCommand(fixHistoryCmd)
+	rootCmd.AddCommand(fixCmd)
+}
@@ -2,30 +2,34 @@ package main
 
 import (
 	"fmt"
+	"os"
 
-	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/wizard"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/initwizard"
 	"github.com/spf13/cobra"
 )
 
 var (
 	initNonInteractive bool
 	initTemplate       string
+	initForce          bool
 )
 
 var initCmd = &cobra.Command{
 	Use:    "init",
-	Short:  "Interactive setup wizard",
+	Short:  "First-run wizard: create .env and starter config",
 	Hidden: true, // v5.0: prefer `agent setup`
-	Long: `Interactive setup wizard for Asterisk AI Voice Agent.
+	Long: `First-run wizard for a fresh checkout. Asks for:
+  - Asterisk host and ARI credentials (tested against ARI before they are accepted)
+  - AI provider (OpenAI Realtime, Deepgram Voice Agent, Google Gemini Live) and its API key
+  - TTS voice and STT language
 
-Guides you through configuration:
-  - Asterisk ARI credentials
-  - Audio transport (AudioSocket/ExternalMedia)
-  - AI provider selection (OpenAI, Deepgram, Anthropic, etc.)
-  - Pipeline configuration
-  - Configuration validation
+and writes, from built-in templates:
+  - .env                                  ARI settings and the provider API key (mode 0600)
+  - config/ai-agent.local.yaml            default provider, voice and language overrides
+  - config/contexts/` + initwizard.ContextName + `.yaml          a starter context
 
-This can be run multiple times to reconfigure the system.`,
+then runs agent check. If any of these files exist, init stops; use agent setup to change
+an existing install, or --force to overwrite (old files are kept as <file>.bak.<timestamp>).`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if initNonInteractive {
 			fmt.Println("⚠️  Non-interactive mode not yet implemented")
@@ -33,15 +37,21 @@ This can be run multiple times to reconfigure the system.`,
 			return nil
 		}
 
-		// Create and run wizard
-		w, err := wizard.NewWizard()
+		repoRoot, err := resolveRepoRootForFix()
 		if err != nil {
-			return fmt.Errorf("failed to initialize wizard: %w", err)
+			return err
 		}
-
-		if err := w.Run(); err != nil {
+		if err := os.Chdir(repoRoot); err != nil {
+			return fmt.Errorf("failed to switch to repo root: %w", err)
+		}
+		opts := initwizard.InitOptions{Force: initForce}
+		if fi, err := os.Stdin.Stat(); err == nil && fi.Mode()&os.ModeCharDevice != 0 {
+			opts.ReadPassword = readPasswordTTY
+		}
+		if err := initwizard.RunInit(repoRoot, opts); err != nil {
 			return err
 		}
+		fmt.Println()
 		return checkCmd.RunE(cmd, args)
 	},
 }
@@ -49,6 +59,7 @@ This can be run multiple times to reconfigure the system.`,
 func init() {
 	initCmd.Flags().BoolVar(&initNonInteractive, "non-interactive", false, "non-interactive mode (use defaults)")
 	initCmd.Flags().StringVar(&initTemplate, "template", "", "config template: local|cloud|hybrid|openai-agent|deepgram-agent")
+	initCmd.Flags().BoolVar(&initForce, "force", false, "overwrite existing .env and config files (keeping .bak copies)")
 
 	rootCmd.AddCommand(initCmd)
 }
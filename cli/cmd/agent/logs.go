@@ -0,0 +1,60 @@
+package main
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/logs"
+	"github.com/spf13/cobra"
+)
+
+var (
+	logsSince  time.Duration
+	logsFollow bool
+	logsGrep   string
+	logsLevel  string
+)
+
+var logsCmd = &cobra.Command{
+	Use:    "logs [service...]",
+	Short:  "Show Docker Compose service logs with filtering",
+	Hidden: true, // advanced tool; wraps docker compose logs
+	Long: `Show the logs of the given Compose services (all services when none are named), as
+docker compose logs does, with filtering applied by the CLI.
+
+  --grep PATTERN   keep lines matching PATTERN as a regular expression, or containing it as
+                   plain text (so "call(" or a literal IP still works)
+  --level LEVEL    keep JSON log entries (LOG_FORMAT=json, the ai_engine default) at or above
+                   LEVEL: debug, info, warning, error or critical. Lines that are not JSON
+                   have no level to compare and are always kept.
+
+Examples:
+  agent logs ai_engine --since 1h --level error
+  agent logs -f ai_engine local_ai_server --grep 1761234567.89`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		repoRoot, err := resolveRepoRootForFix()
+		if err != nil {
+			return err
+		}
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		return logs.StreamLogs(ctx, logs.LogOptions{
+			Dir:      repoRoot,
+			Services: args,
+			Since:    logsSince,
+			Follow:   logsFollow,
+			Grep:     logsGrep,
+			Level:    logsLevel,
+		}, os.Stdout)
+	},
+}
+
+func init() {
+	logsCmd.Flags().DurationVar(&logsSince, "since", 0, "only show entries newer than this (e.g. 30m, 1h)")
+	logsCmd.Flags().BoolVarP(&logsFollow, "follow", "f", false, "keep streaming new log entries")
+	logsCmd.Flags().StringVar(&logsGrep, "grep", "", "keep lines matching this regex or containing it as text")
+	logsCmd.Flags().StringVar(&logsLevel, "level", "", "minimum level for JSON log entries (debug|info|warning|error|critical)")
+	rootCmd.AddCommand(logsCmd)
+}
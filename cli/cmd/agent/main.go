@@ -5,6 +5,10 @@ import (
 	"os"
 
 	"github.com/fatih/color"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/crash"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/environment"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/exitcodes"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/logging"
 	"github.com/spf13/cobra"
 )
 
@@ -13,12 +17,23 @@ var (
 	buildTime = "unknown" // Overridden at build time via -ldflags
 	verbose   bool
 	noColor   bool
+	// verboseCommands streams the output of git/docker commands run by update, check --fix, etc.
+	verboseCommands bool
+	logLevel        string
+	logFormat       string
+	// repoRootOverride is --repo-root; see resolveRepoRootForFix.
+	repoRootOverride string
+	// envFlag is --env; agentEnv is the resolved environment (see envRel).
+	envFlag  string
+	agentEnv = environment.Default
 )
 
 func main() {
-	if err := rootCmd.Execute(); err != nil {
+	crash.Version = version
+	crash.Root = resolveRepoRootForFix
+	if err := crash.WithPanicRecovery(rootCmd.Execute); err != nil {
 		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+		os.Exit(exitcodes.ExitError)
 	}
 }
 
@@ -36,7 +51,7 @@ Available commands:
 		version),
 	SilenceUsage:  true,
 	SilenceErrors: true,
-	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
 		// Auto-disable color when stdout isn't a TTY; allow explicit opt-out as well.
 		isTTY := false
 		if fi, err := os.Stdout.Stat(); err == nil {
@@ -45,10 +60,25 @@ Available commands:
 		if noColor || !isTTY {
 			color.NoColor = true
 		}
+
+		// Diagnostic logs go to stderr; commands fetch the logger with logging.FromContext.
+		logger, err := logging.New(os.Stderr, logging.Options{Level: logLevel, Format: logFormat, Verbose: verbose})
+		if err != nil {
+			return err
+		}
+		cmd.SetContext(logging.NewContext(cmd.Context(), logger))
+
+		agentEnv, err = environment.Resolve(envFlag)
+		return err
 	},
 }
 
 func init() {
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "verbose output")
+	rootCmd.PersistentFlags().BoolVar(&verboseCommands, "verbose-commands", false, "echo external commands (git, docker) and stream their output live")
 	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "disable color output")
+	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", logging.DefaultLevel, "diagnostic log level on stderr: debug|info|warn|error (--verbose implies debug)")
+	rootCmd.PersistentFlags().StringVar(&repoRootOverride, "repo-root", "", "repository root to operate on (default: git top-level, or the nearest parent with docker-compose.yml and config/ai-agent.yaml)")
+	rootCmd.PersistentFlags().StringVar(&envFlag, "env", "", "environment whose config files to use, e.g. production for .env.production (default: $AGENT_ENV, then default)")
+	rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", "text", "diagnostic log format on stderr: text|json")
 }
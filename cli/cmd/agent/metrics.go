@@ -0,0 +1,112 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	dockercheck "github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/check/docker"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/metrics"
+	"github.com/spf13/cobra"
+)
+
+var (
+	metricsInterval time.Duration
+	metricsOutput   string
+	metricsFile     string
+	metricsLast     time.Duration
+)
+
+var metricsCmd = &cobra.Command{
+	Use:    "metrics",
+	Short:  "Record and summarize container CPU, memory and network usage",
+	Hidden: true, // advanced tool for hosts without a Prometheus stack
+}
+
+var metricsCollectCmd = &cobra.Command{
+	Use:   "collect [service...]",
+	Short: "Append docker stats samples to a JSON Lines file until stopped",
+	Long: `Poll docker stats --no-stream every --interval (default 10s) and append one JSON line per
+container to --output (default .agent/metrics.jsonl under the repo root): CPU%, memory used
+and limit, and the network bytes received and sent since the container started.
+
+The named services are sampled; without arguments ai_engine, admin_ui and local_ai_server.
+Containers that are not running are left out of that poll. Runs until Ctrl-C or SIGTERM, so
+it can be left running under nohup, tmux or a systemd unit. Summarize with agent metrics
+report.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		output, err := metricsPath(metricsOutput)
+		if err != nil {
+			return err
+		}
+		services := args
+		if len(services) == 0 {
+			services = dockercheck.DefaultServices
+		}
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		fmt.Printf("Sampling %v every %s into %s (Ctrl-C to stop)\n", services, metricsInterval, output)
+		return metrics.CollectMetrics(ctx, services, metricsInterval, output)
+	},
+}
+
+var metricsReportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Summarize recorded CPU, memory and network usage per container",
+	Long: `Read the samples agent metrics collect wrote to --file (default .agent/metrics.jsonl) and
+print one row per container: CPU% average, maximum and trend (the second half of the window
+against the first), memory at the last sample with its change and peak, and the network
+bytes received and sent. --last limits the window (default 1h; 0 for every sample).`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := metricsPath(metricsFile)
+		if err != nil {
+			return err
+		}
+		var since time.Time
+		if metricsLast > 0 {
+			since = time.Now().Add(-metricsLast)
+		}
+		samples, err := metrics.ReadSamples(path, since)
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("%s not found; record samples with agent metrics collect first", path)
+		}
+		if err != nil {
+			return err
+		}
+		if len(samples) == 0 {
+			fmt.Printf("No samples in %s for the last %s.\n", path, metricsLast)
+			return nil
+		}
+		summaries := metrics.Summarize(samples)
+		fmt.Printf("%d samples from %s to %s\n\n", len(samples),
+			samples[0].Time.Local().Format("2006-01-02 15:04:05"), samples[len(samples)-1].Time.Local().Format("2006-01-02 15:04:05"))
+		return metrics.WriteReport(os.Stdout, summaries)
+	},
+}
+
+func init() {
+	metricsCollectCmd.Flags().DurationVar(&metricsInterval, "interval", metrics.DefaultInterval, "time between docker stats polls")
+	metricsCollectCmd.Flags().StringVar(&metricsOutput, "output", metrics.DefaultOutput, "JSON Lines file samples are appended to (relative to the repo root)")
+	metricsReportCmd.Flags().StringVar(&metricsFile, "file", metrics.DefaultOutput, "JSON Lines file written by agent metrics collect (relative to the repo root)")
+	metricsReportCmd.Flags().DurationVar(&metricsLast, "last", time.Hour, "only summarize samples this recent (0 for all)")
+	metricsCmd.AddCommand(metricsCollectCmd)
+	metricsCmd.AddCommand(metricsReportCmd)
+	rootCmd.AddCommand(metricsCmd)
+}
+
+// metricsPath resolves a relative samples file against the repo root.
+func metricsPath(path string) (string, error) {
+	if filepath.IsAbs(path) {
+		return path, nil
+	}
+	repoRoot, err := resolveRepoRootForFix()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(repoRoot, path), nil
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"os"
 
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/logging"
 	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/troubleshoot"
 	"github.com/spf13/cobra"
 )
@@ -39,6 +40,7 @@ This is the recommended post-call troubleshooting command.`,
 			false, // list
 			rcaJSON,
 			verbose,
+			logging.FromContext(cmd.Context()),
 		)
 		err := runner.Run()
 		if rcaJSON && err != nil {
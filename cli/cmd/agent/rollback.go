@@ -0,0 +1,195 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/backup"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/check"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/exitcodes"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/logging"
+	"github.com/spf13/cobra"
+)
+
+var rollbackYes bool
+
+// Replaced in tests.
+var (
+	rollbackInput       io.Reader = os.Stdin
+	rollbackRestart               = restartCoreServices
+	rollbackDiagnostics           = check.WaitForServicesHealthy
+)
+
+var rollbackCmd = &cobra.Command{
+	Use:    "rollback <backup-dir|timestamp>",
+	Short:  "Restore operator config from a specific backup set",
+	Hidden: true, // advanced recovery; `agent check --fix` picks the latest backup automatically
+	Long: `Restore operator config from a specific backup set instead of the most recent one.
+
+The argument is either a backup directory path or a timestamp prefix (e.g. 20240615_143000)
+matched against .agent/update-backups/ and .agent/check-fix-backups/ (see agent backup list).
+
+Rollback verifies the backup manifest, previews the changes as a diff, asks for confirmation,
+snapshots the current state to .agent/check-fix-backups/, restores the files, restarts
+ai_engine/admin_ui and re-runs agent check.
+
+Exit codes follow agent check: 0 PASS, 1 WARN, 2 FAIL.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		exitCode, err := runRollback(logging.FromContext(cmd.Context()), args[0])
+		if exitCode != 0 {
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			}
+			os.Exit(exitCode)
+		}
+		return err
+	},
+}
+
+func init() {
+	rollbackCmd.Flags().BoolVarP(&rollbackYes, "yes", "y", false, "restore without asking for confirmation")
+	rootCmd.AddCommand(rollbackCmd)
+}
+
+func runRollback(log *slog.Logger, arg string) (int, error) {
+	repoRoot, err := resolveRepoRootForFix()
+	if err != nil {
+		return 0, err
+	}
+	if err := os.Chdir(repoRoot); err != nil {
+		return 0, fmt.Errorf("failed to switch to repo root: %w", err)
+	}
+
+	dir, err := resolveRollbackTarget(repoRoot, arg)
+	if err != nil {
+		return 0, err
+	}
+	if err := backup.VerifyManifest(dir); err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			return 0, fmt.Errorf("refusing to roll back from %s: %w", dir, err)
+		}
+		fmt.Printf("Note: %s has no manifest (created before manifests were introduced); skipping verification.\n", dir)
+	} else {
+		fmt.Printf("Manifest verified: %s\n", dir)
+	}
+	// The preview diffs against plaintext; encrypted sets are decrypted to a temp copy.
+	previewDir, cleanup, err := openBackupDir(repoRoot, dir)
+	if err != nil {
+		return 0, fmt.Errorf("refusing to roll back from %s: %w", dir, err)
+	}
+	defer cleanup()
+
+	// Plan with the dry-run overlay so the preview goes through the same restore logic.
+	restoreBase := shouldRestoreBaseConfig()
+	dryRunRestored = map[string]string{}
+	plan := restoreFromSingleBackupDir(dir, restoreBase, true, ".")
+	dryRunRestored = nil
+	for _, w := range plan.warnings {
+		fmt.Printf("Warning: %s\n", w)
+	}
+	if len(plan.restoredPaths) == 0 {
+		return 0, fmt.Errorf("nothing restorable in %s", dir)
+	}
+
+	fmt.Println("")
+	fmt.Println("Changes (live -> backup):")
+	for _, rel := range plan.restoredPaths {
+		fmt.Printf("\n=== %s\n", rel)
+		fmt.Print(unifiedDiff(rel, filepath.Join(previewDir, rel)))
+	}
+	fmt.Println("")
+
+	if !rollbackYes && !confirmRollback(rollbackInput, fmt.Sprintf("Restore %d path(s) from %s?", len(plan.restoredPaths), dir)) {
+		fmt.Println("Rollback cancelled. No files were changed.")
+		return 0, nil
+	}
+
+	summary := &fixSummary{repoRoot: repoRoot, sourceBackup: dir}
+	if err := snapshotBeforeFix(repoRoot, summary); err != nil {
+		return exitcodes.ExitFail, err
+	}
+	result := restoreFromSingleBackupDir(dir, restoreBase, true, ".")
+	summary.restored = append(summary.restored, result.restoredPaths...)
+	summary.warnings = append(summary.warnings, result.warnings...)
+	printFixSummary(summary)
+	if len(result.restoredPaths) == 0 {
+		return exitcodes.ExitFail, errors.New("rollback restored no files")
+	}
+
+	if err := rollbackRestart(); err != nil {
+		return exitcodes.ExitFail, err
+	}
+	fmt.Println("")
+	fmt.Printf("Re-running diagnostics after rollback (waiting up to %s for services)...\n", check.DefaultWaitTimeout)
+	runner := newCheckRunner()
+	runner.Logger = log
+	report, runErr := rollbackDiagnostics(runner, check.DefaultTimeout, check.DefaultWaitTimeout, fixWaitPoll)
+	if report == nil {
+		return exitcodes.ExitFail, fmt.Errorf("post-rollback diagnostics failed: %w", runErr)
+	}
+	report.OutputText(os.Stdout, check.OutputOptions{})
+	if runErr != nil || report.FailCount > 0 {
+		return exitcodes.ExitFail, nil
+	}
+	if report.WarnCount > 0 {
+		return exitcodes.ExitWarn, nil
+	}
+	return exitcodes.ExitOK, nil
+}
+
+// resolveRollbackTarget accepts a backup directory path, or a directory-name prefix that
+// must match exactly one set under the update or check-fix backup roots.
+func resolveRollbackTarget(repoRoot string, arg string) (string, error) {
+	arg = strings.TrimSpace(arg)
+	if arg == "" {
+		return "", errors.New("backup directory or timestamp is required")
+	}
+	if info, err := os.Stat(arg); err == nil && info.IsDir() {
+		return filepath.Abs(arg)
+	}
+	if strings.ContainsAny(arg, `/\`) {
+		return "", fmt.Errorf("backup directory not found: %s", arg)
+	}
+
+	var matches []string
+	for _, root := range []string{updateBackupRoot(repoRoot), checkFixBackupRoot(repoRoot)} {
+		dirs, err := backup.BackupDirs(root)
+		if err != nil {
+			return "", err
+		}
+		for _, d := range dirs {
+			if strings.HasPrefix(filepath.Base(d), arg) {
+				matches = append(matches, d)
+			}
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("no backup set matches %q (see agent backup list)", arg)
+	case 1:
+		return matches[0], nil
+	default:
+		return "", fmt.Errorf("%q matches %d backup sets; pass the full directory path:\n  %s", arg, len(matches), strings.Join(matches, "\n  "))
+	}
+}
+
+func confirmRollback(in io.Reader, question string) bool {
+	fmt.Printf("%s [y/N]: ", question)
+	line, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && line == "" {
+		return false
+	}
+	switch strings.ToLower(strings.TrimSpace(line)) {
+	case "y", "yes":
+		return true
+	default:
+		return false
+	}
+}
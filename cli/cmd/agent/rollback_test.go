@@ -0,0 +1,153 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/check"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/exitcodes"
+)
+
+const liveEnv = "ASTERISK_HOST=old\nASTERISK_ARI_USERNAME=ari\n"
+
+// rollbackRepo is fixRepo with a live .env that differs from the backup, and the rollback
+// seams stubbed: answer is the reply to the confirmation prompt, restartErr what the restart
+// returns. *restarted counts restarts.
+func rollbackRepo(t *testing.T, answer string, restartErr error) (root string, restarted *int) {
+	t.Helper()
+	root, _ = fixRepo(t)
+	writeTestFile(t, filepath.Join(root, ".env"), liveEnv)
+
+	origIn, origRestart, origDiag, origYes := rollbackInput, rollbackRestart, rollbackDiagnostics, rollbackYes
+	t.Cleanup(func() {
+		rollbackInput, rollbackRestart, rollbackDiagnostics, rollbackYes = origIn, origRestart, origDiag, origYes
+	})
+	restarted = new(int)
+	rollbackInput = strings.NewReader(answer)
+	rollbackRestart = func() error {
+		*restarted++
+		return restartErr
+	}
+	rollbackDiagnostics = func(*check.Runner, time.Duration, time.Duration, time.Duration) (*check.Report, error) {
+		return &check.Report{Items: []check.Item{{Name: "Host", Status: check.StatusWarn}}, WarnCount: 1}, nil
+	}
+	rollbackYes = false
+	return root, restarted
+}
+
+// captureStdout returns what fn printed.
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	orig := os.Stdout
+	os.Stdout = w
+	done := make(chan string)
+	go func() {
+		b, _ := io.ReadAll(r)
+		done <- string(b)
+	}()
+	defer func() { os.Stdout = orig }()
+	fn()
+	_ = w.Close()
+	return <-done
+}
+
+func readTestFile(t *testing.T, path string) string {
+	t.Helper()
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestRollbackPreviewAndCancel(t *testing.T) {
+	root, restarted := rollbackRepo(t, "n\n", nil)
+
+	var code int
+	var err error
+	out := captureStdout(t, func() { code, err = runRollback(slog.Default(), "20260101") })
+	if code != 0 || err != nil {
+		t.Fatalf("runRollback = %d, %v", code, err)
+	}
+	for _, want := range []string{"Manifest verified", "Changes (live -> backup):", "=== .env", "[y/N]", "Rollback cancelled"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("output lacks %q:\n%s", want, out)
+		}
+	}
+	if got := readTestFile(t, filepath.Join(root, ".env")); got != liveEnv {
+		t.Fatalf(".env changed on cancel: %q", got)
+	}
+	if _, err := os.Stat(filepath.Join(root, "config", "ai-agent.yaml")); !os.IsNotExist(err) {
+		t.Fatalf("config restored on cancel: %v", err)
+	}
+	if _, err := os.Stat(checkFixBackupRoot(root)); !os.IsNotExist(err) {
+		t.Fatalf("snapshot taken on cancel: %v", err)
+	}
+	if *restarted != 0 {
+		t.Fatal("services restarted on cancel")
+	}
+}
+
+func TestRollbackConfirmRestoresAndRechecks(t *testing.T) {
+	root, restarted := rollbackRepo(t, "y\n", nil)
+
+	var code int
+	var err error
+	out := captureStdout(t, func() { code, err = runRollback(slog.Default(), "20260101") })
+	if code != exitcodes.ExitWarn || err != nil {
+		t.Fatalf("runRollback = %d, %v\n%s", code, err, out)
+	}
+	if got := readTestFile(t, filepath.Join(root, ".env")); !strings.Contains(got, "ASTERISK_HOST=pbx") {
+		t.Fatalf(".env not restored: %q", got)
+	}
+	if got := readTestFile(t, filepath.Join(root, "config", "ai-agent.yaml")); got != "default_provider: openai_realtime\n" {
+		t.Fatalf("ai-agent.yaml = %q", got)
+	}
+	snapshots, _ := filepath.Glob(filepath.Join(checkFixBackupRoot(root), "*", ".env"))
+	if len(snapshots) != 1 || readTestFile(t, snapshots[0]) != liveEnv {
+		t.Fatalf("pre-rollback snapshot = %v", snapshots)
+	}
+	if *restarted != 1 {
+		t.Fatalf("restarted %d times", *restarted)
+	}
+}
+
+func TestRollbackRestartFailure(t *testing.T) {
+	root, _ := rollbackRepo(t, "", errors.New("docker compose unavailable"))
+	rollbackYes = true
+
+	var code int
+	var err error
+	captureStdout(t, func() { code, err = runRollback(slog.Default(), "20260101") })
+	if code != exitcodes.ExitFail || err == nil || !strings.Contains(err.Error(), "docker compose unavailable") {
+		t.Fatalf("runRollback = %d, %v", code, err)
+	}
+	// The files are restored before the restart and stay restored.
+	if got := readTestFile(t, filepath.Join(root, ".env")); !strings.Contains(got, "ASTERISK_HOST=pbx") {
+		t.Fatalf(".env not restored: %q", got)
+	}
+}
+
+func TestRollbackRefusesTamperedBackup(t *testing.T) {
+	root, restarted := rollbackRepo(t, "y\n", nil)
+	writeTestFile(t, filepath.Join(root, ".agent", "update-backups", "20260101_000000", ".env"), "ASTERISK_HOST=evil\nASTERISK_ARI_USERNAME=ari\n")
+
+	var err error
+	captureStdout(t, func() { _, err = runRollback(slog.Default(), "20260101") })
+	if err == nil || !strings.Contains(err.Error(), "refusing to roll back") {
+		t.Fatalf("err = %v", err)
+	}
+	if got := readTestFile(t, filepath.Join(root, ".env")); got != liveEnv || *restarted != 0 {
+		t.Fatalf(".env = %q, restarted %d times", got, *restarted)
+	}
+}
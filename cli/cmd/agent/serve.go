@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/healthserver"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/logging"
+	"github.com/spf13/cobra"
+)
+
+var (
+	serveHealthPort int
+	serveInterval   time.Duration
+)
+
+var serveCmd = &cobra.Command{
+	Use:    "serve",
+	Short:  "Serve agent check results over HTTP",
+	Hidden: true, // advanced: intended for orchestrator health probes
+	Long: `Run agent check on an interval and expose the latest result over HTTP.
+
+Endpoints:
+  GET /healthz   200 when no checks fail, 503 otherwise
+  GET /readyz    200 when no checks fail or warn, 503 otherwise
+  GET /metrics   Prometheus text format (agent_check_fail_total, agent_check_warn_total, ...)
+
+Endpoints return 503 until the first check run completes. /healthz and /readyz also return
+503 when the last run timed out or errored ("status": "error"), or when no run has finished
+for two intervals plus the 30s check timeout ("status": "stale").`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		runner := newCheckRunner()
+		runner.Logger = logging.FromContext(cmd.Context())
+		fmt.Printf("Serving health on :%d (interval %s)\n", serveHealthPort, serveInterval)
+		return healthserver.ServeHealth(serveHealthPort, runner, serveInterval)
+	},
+}
+
+func init() {
+	serveCmd.Flags().IntVar(&serveHealthPort, "health-port", 8099, "port for /healthz, /readyz and /metrics")
+	serveCmd.Flags().DurationVar(&serveInterval, "interval", 30*time.Second, "how often to re-run diagnostics")
+	rootCmd.AddCommand(serveCmd)
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/check"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/exitcodes"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/logging"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/status"
+	"github.com/spf13/cobra"
+)
+
+var (
+	statusJSON         bool
+	statusServicesOnly bool
+	statusChecksOnly   bool
+)
+
+var statusCmd = &cobra.Command{
+	Use:    "status",
+	Short:  "Show Compose service health and agent check results together",
+	Hidden: true, // advanced tool; agent check remains the primary report
+	Long: `Show the state of every Compose service (docker compose ps --all) and the agent check
+results in a single table. Services that are exited, dead or unhealthy, and failing checks,
+are highlighted in red; warnings in yellow.
+
+Use --services-only to skip the diagnostics, or --checks-only to skip docker compose ps.
+
+Exit codes match agent check (0 pass, 1 warn, 2 fail); an exited or unhealthy service
+counts as a warning.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if statusServicesOnly && statusChecksOnly {
+			return errors.New("--services-only and --checks-only cannot be combined")
+		}
+		repoRoot, err := resolveRepoRootForFix()
+		if err != nil {
+			return err
+		}
+		if err := os.Chdir(repoRoot); err != nil {
+			return fmt.Errorf("failed to switch to repo root: %w", err)
+		}
+
+		var runner *check.Runner
+		if !statusServicesOnly {
+			runner = newCheckRunner()
+			runner.Logger = logging.FromContext(cmd.Context())
+		}
+		var report *status.StatusReport
+		if statusChecksOnly {
+			checks, runErr := runner.RunWithTimeout(cmd.Context(), check.DefaultTimeout)
+			if checks != nil {
+				report = &status.StatusReport{Report: *checks, Services: []status.ServiceStatus{}}
+			}
+			err = runErr
+		} else {
+			report, err = status.RunStatusReport(repoRoot, runner)
+		}
+		if report == nil {
+			return err
+		}
+		if errors.Is(err, check.ErrTimedOut) {
+			fmt.Fprintln(os.Stderr, "Diagnostics timed out; showing partial results.")
+		}
+
+		if statusJSON {
+			if err := report.OutputJSON(os.Stdout); err != nil {
+				return err
+			}
+		} else {
+			report.OutputText(os.Stdout, !statusChecksOnly, !statusServicesOnly)
+		}
+
+		switch report.ExitCode() {
+		case 2:
+			os.Exit(exitcodes.ExitFail)
+		case 1:
+			os.Exit(exitcodes.ExitWarn)
+		}
+		return nil
+	},
+}
+
+func init() {
+	statusCmd.Flags().BoolVar(&statusJSON, "json", false, "output the services and check report as JSON")
+	statusCmd.Flags().BoolVar(&statusServicesOnly, "services-only", false, "only show Compose services (skip diagnostics)")
+	statusCmd.Flags().BoolVar(&statusChecksOnly, "checks-only", false, "only show agent check results (skip docker compose ps)")
+	rootCmd.AddCommand(statusCmd)
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/check"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/secrets"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/telemetry"
+	"github.com/spf13/cobra"
+)
+
+var telemetryShowPayload bool
+
+var telemetryCmd = &cobra.Command{
+	Use:    "telemetry",
+	Short:  "Show the opt-in usage statistics agent check can send",
+	Hidden: true, // opt-in; documented with AGENT_TELEMETRY in .env.example
+	Long: `Telemetry is off unless AGENT_TELEMETRY=1 is set in .env. When it is on, each full
+agent check run (not --watch or --item runs) POSTs a small JSON document to
+AGENT_TELEMETRY_ENDPOINT with:
+  - the pass/warn/fail/skip counts
+  - the name and status of each built-in check (no messages or details; declarative and
+    plugin checks are only counted, since operators name them)
+  - the OS, CPU architecture and agent version
+  - a random installation ID kept in .agent/install-id (delete it to get a new one)
+.env values and host names are never sent. A failed send is logged and never changes the
+check result.
+
+Without flags, prints whether telemetry is on and where it would go. --show-payload prints the
+document that would be sent for the last agent check run (.agent/last-report.json), without
+sending anything.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		repoRoot, err := resolveRepoRootForFix()
+		if err != nil {
+			return err
+		}
+		envMap, _, _ := secrets.LoadEnv(filepath.Join(repoRoot, envRel(".env"))) // .env.age is decrypted in memory
+
+		if telemetryShowPayload {
+			report, err := check.LoadReport(filepath.Join(repoRoot, filepath.FromSlash(check.LastReportPath)))
+			if err != nil {
+				return err
+			}
+			if report == nil {
+				return errors.New("no saved agent check report; run agent check first")
+			}
+			id, err := telemetry.InstallID(repoRoot)
+			if err != nil {
+				return err
+			}
+			b, err := json.MarshalIndent(telemetry.NewPayload(report, id), "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(b))
+			return nil
+		}
+
+		state := "off"
+		if telemetry.Enabled(envMap) {
+			state = "on"
+		}
+		endpoint := telemetry.Endpoint(envMap)
+		if endpoint == "" {
+			endpoint = "(not set)"
+		}
+		fmt.Printf("Telemetry: %s (%s in .env)\n", state, telemetry.Env)
+		fmt.Printf("Endpoint:  %s (%s)\n", endpoint, telemetry.EndpointEnv)
+		fmt.Printf("Install ID file: %s\n", filepath.Join(repoRoot, filepath.FromSlash(telemetry.InstallIDPath)))
+		return nil
+	},
+}
+
+func init() {
+	telemetryCmd.Flags().BoolVar(&telemetryShowPayload, "show-payload", false, "print what would be sent for the last agent check run, without sending it")
+	rootCmd.AddCommand(telemetryCmd)
+}
+
+// sendCheckTelemetry sends report when AGENT_TELEMETRY is on in .env. Failures are only
+// logged, like pushCheckMetrics.
+func sendCheckTelemetry(log *slog.Logger, report *check.Report) {
+	repoRoot, err := resolveRepoRootForFix()
+	if err != nil {
+		return
+	}
+	envMap, _, _ := secrets.LoadEnv(filepath.Join(repoRoot, envRel(".env")))
+	if !telemetry.Enabled(envMap) {
+		return
+	}
+	endpoint := telemetry.Endpoint(envMap)
+	if endpoint == "" {
+		log.Debug("telemetry enabled but no endpoint set", "env", telemetry.EndpointEnv)
+		return
+	}
+	ctx := telemetry.WithRepoRoot(context.Background(), repoRoot)
+	if err := telemetry.SendTelemetry(ctx, report, endpoint); err != nil {
+		log.Warn("could not send telemetry", "error", err)
+	}
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"os"
 
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/logging"
 	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/troubleshoot"
 	"github.com/spf13/cobra"
 )
@@ -66,6 +67,7 @@ Features:
 			troubleshootList,
 			troubleshootJSON,
 			verbose,
+			logging.FromContext(cmd.Context()),
 		)
 		err := runner.Run()
 		if troubleshootJSON && err != nil {
@@ -3,26 +3,31 @@ package main
 import (
 	"bytes"
 	"context"
-	"crypto/sha256"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"io/fs"
-	"net/http"
+	"log/slog"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"regexp"
 	"runtime"
 	"sort"
-	"strconv"
 	"strings"
 	"syscall"
 	"time"
 
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/backup"
 	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/check"
 	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/configmerge"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/environment"
+	cmdexec "github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/exec"
+	agentfs "github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/fs"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/logging"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/maputil"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/update"
 	"github.com/spf13/cobra"
 )
 
@@ -48,9 +53,16 @@ var (
 	updateBackupID       string
 	updatePlan           bool
 	updatePlanJSON       bool
+	updateHealthTimeout  time.Duration
+	updateRollback       bool
+	updatePrerelease     bool
+	updatePrereleaseTag  string
 	gitSafeDirectory     string
 )
 
+// updateHealthPoll is the initial pause between post-update checks while services settle.
+const updateHealthPoll = 5 * time.Second
+
 var semverTagRe = regexp.MustCompile(`^(v)?([0-9]+\.[0-9]+\.[0-9]+)$`)
 
 func normalizeSemverTagRef(ref string) (string, bool) {
@@ -76,7 +88,18 @@ This command:
   - Safely fast-forwards to origin/main (no forced merges by default)
   - Preserves local tracked changes using git stash (optional)
   - Rebuilds/restarts only the containers impacted by the change set
-  - Verifies success by running agent check (optional)
+  - Verifies success by running agent check (optional), retrying for up to --health-timeout
+    while services start
+  - With --rollback-on-failure, a post-update check that still fails is rolled back: the branch
+    returns to the previous commit (git reset --keep), operator config is restored from the
+    backup, and the affected containers are rebuilt/restarted
+  - With --prerelease, running services whose registry image is tagged latest are switched to
+    the --prerelease-tag image (edge or nightly) through a temporary Compose override, and the
+    update's backup is marked as pre-release so agent check --fix does not restore from it
+    unless given --allow-prerelease-restore. Locally built images (pull_policy: build, the
+    default docker-compose.yml) are not changed; when no running service can be switched the
+    update is refused before anything changes. A later plain docker compose up returns the
+    services to latest.
 
 Safety notes:
   - If you edited config/ai-agent.yaml directly, updates can conflict. This updater automatically migrates
@@ -84,7 +107,7 @@ Safety notes:
   - No hard resets are performed.
   - Fast-forward only: if your branch has diverged, the update stops with guidance.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return runUpdate()
+		return runUpdate(logging.FromContext(cmd.Context()))
 	},
 }
 
@@ -102,10 +125,15 @@ func init() {
 	updateCmd.Flags().StringVar(&updateBackupID, "backup-id", "", "use a stable backup identifier (creates .agent/update-backups/<id>)")
 	updateCmd.Flags().BoolVar(&updatePlan, "plan", false, "print the update plan (git/diff/docker actions) without applying it")
 	updateCmd.Flags().BoolVar(&updatePlanJSON, "plan-json", false, "when used with --plan, output the plan as JSON")
+	updateCmd.Flags().DurationVar(&updateHealthTimeout, "health-timeout", 60*time.Second, "keep re-running agent check after the update until it has no failures or this much time has passed (0 checks once)")
+	updateCmd.Flags().BoolVar(&updateRollback, "rollback-on-failure", false, "roll back code, config and containers if the post-update check still fails after --health-timeout")
+	updateCmd.Flags().BoolVar(&updatePrerelease, "prerelease", false, "switch running services from :latest registry images to pre-release images and mark the backup as pre-release")
+	updateCmd.Flags().StringVar(&updatePrereleaseTag, "prerelease-tag", update.TagEdge, "with --prerelease, the image tag to use: edge|nightly")
 	rootCmd.AddCommand(updateCmd)
 }
 
 type updateContext struct {
+	log       *slog.Logger
 	repoRoot  string
 	oldSHA    string
 	newSHA    string
@@ -120,6 +148,8 @@ type updateContext struct {
 	composeChanged    bool
 
 	skippedServices map[string]string // service -> "rebuild"|"restart" (filtered by flags)
+
+	rolledBack bool
 }
 
 type updatePlanReport struct {
@@ -151,7 +181,7 @@ type updatePlanReport struct {
 	Warnings         []string          `json:"warnings,omitempty"`
 }
 
-func runUpdate() (retErr error) {
+func runUpdate(log *slog.Logger) (retErr error) {
 	printUpdateStep("Preparing update")
 	if updateSelfUpdate {
 		maybeSelfUpdateAndReexec()
@@ -166,6 +196,7 @@ func runUpdate() (retErr error) {
 	}
 
 	ctx := &updateContext{
+		log:               log,
 		repoRoot:          repoRoot,
 		servicesToRebuild: map[string]bool{},
 		servicesToRestart: map[string]bool{},
@@ -187,6 +218,18 @@ func runUpdate() (retErr error) {
 	if updatePlan {
 		return runUpdatePlan(ctx)
 	}
+	if updateRollback && updateSkipCheck {
+		return errors.New("--rollback-on-failure requires the post-update check (drop --skip-check)")
+	}
+	if updatePrereleaseTag != update.TagEdge && updatePrereleaseTag != update.TagNightly {
+		return fmt.Errorf("--prerelease-tag must be %s or %s", update.TagEdge, update.TagNightly)
+	}
+	if updatePrerelease {
+		// Refuse before the backup, fast-forward and rebuild rather than after them.
+		if _, err := prereleaseServices(ctx); err != nil {
+			return err
+		}
+	}
 
 	printUpdateStep("Creating backups")
 	if err := createUpdateBackups(ctx); err != nil {
@@ -330,25 +373,108 @@ func runUpdate() (retErr error) {
 	if err := applyDockerActions(ctx); err != nil {
 		return err
 	}
+	if updatePrerelease {
+		printUpdateStep(fmt.Sprintf("Switching to %s images", updatePrereleaseTag))
+		if err := applyPrereleaseImages(ctx); err != nil {
+			return err
+		}
+	}
 
 	if updateSkipCheck {
 		printUpdateSummary(ctx, "", 0, 0)
+		markPrereleaseBackup(ctx)
+		pruneUpdateBackupsAfterUpdate(ctx)
 		return nil
 	}
 
 	printUpdateStep("Running agent check")
-	report, status, warnCount, failCount, err := runPostUpdateCheck()
+	report, status, warnCount, failCount, err := runPostUpdateCheck(ctx.log, updateHealthTimeout)
 	printPostUpdateCheck(report, warnCount, failCount)
 	printUpdateSummary(ctx, status, warnCount, failCount)
+	if (err != nil || failCount > 0) && updateRollback {
+		printUpdateStep("Rolling back")
+		if rbErr := rollbackFailedUpdate(ctx); rbErr != nil {
+			return fmt.Errorf("post-update check failed and rollback also failed: %w", rbErr)
+		}
+		ctx.rolledBack = true
+		return fmt.Errorf("post-update check failed; rolled back to %s", shortSHA(ctx.oldSHA))
+	}
 	if err != nil {
 		return err
 	}
 	if failCount > 0 {
 		return errors.New("post-update check reported failures")
 	}
+	markPrereleaseBackup(ctx)
+	pruneUpdateBackupsAfterUpdate(ctx)
 	return nil
 }
 
+// prereleaseServices returns the running services (admin_ui only with --include-ui) that
+// --prerelease would switch, or an error when there are none.
+func prereleaseServices(ctx *updateContext) ([]string, error) {
+	running := runningComposeServices()
+	if !updateIncludeUI {
+		delete(running, "admin_ui")
+	}
+	if len(running) == 0 {
+		return nil, errors.New("--prerelease: no running services to switch (start them with docker compose up -d first)")
+	}
+	services := maputil.SortedKeys(running)
+	if _, _, err := update.SelectPrereleaseImages(ctx.repoRoot, services, updatePrereleaseTag); err != nil {
+		return nil, fmt.Errorf("--prerelease: %w", err)
+	}
+	return services, nil
+}
+
+// applyPrereleaseImages switches the running services (admin_ui only with --include-ui) to
+// --prerelease-tag images.
+func applyPrereleaseImages(ctx *updateContext) error {
+	services, err := prereleaseServices(ctx)
+	if err != nil {
+		return err
+	}
+	streams := cmdexec.Streams{Stdin: os.Stdin}
+	if verbose || verboseCommands {
+		streams.Stdout, streams.Stderr = os.Stdout, os.Stderr
+	}
+	update.ProgressWriter = updateHumanWriter()
+	return update.UpdateImages(cmdexec.WithStreams(context.Background(), streams), update.UpdateOptions{
+		RepoRoot:   ctx.repoRoot,
+		Services:   services,
+		Prerelease: true,
+		Tag:        updatePrereleaseTag,
+	})
+}
+
+// markPrereleaseBackup records in the update's backup set that it was taken before a
+// --prerelease update, so agent check --fix only restores it with --allow-prerelease-restore
+// (best-effort).
+func markPrereleaseBackup(ctx *updateContext) {
+	if !updatePrerelease || ctx.backupDir == "" {
+		return
+	}
+	if err := backup.WriteMetadata(ctx.backupDir, backup.Metadata{Prerelease: true, ImageTag: updatePrereleaseTag}); err != nil {
+		printUpdateInfo("WARN: failed to mark %s as pre-release: %v", ctx.backupDir, err)
+	}
+}
+
+// pruneUpdateBackupsAfterUpdate caps .agent/update-backups after a successful update (best-effort).
+func pruneUpdateBackupsAfterUpdate(ctx *updateContext) {
+	keep := backupKeepFromEnv(ctx.repoRoot)
+	removed, err := backup.PruneUpdateBackups(updateBackupRoot(ctx.repoRoot), keep)
+	if err != nil {
+		printUpdateInfo("WARN: failed to prune old backups: %v", err)
+		return
+	}
+	if removed > 0 {
+		printUpdateInfo("Pruned %d old backup(s) (keep=%d)", removed, keep)
+		if _, err := pruneContentStore(ctx.repoRoot); err != nil {
+			printUpdateInfo("WARN: failed to prune content store: %v", err)
+		}
+	}
+}
+
 func runUpdatePlan(ctx *updateContext) error {
 	dirty, err := gitIsDirty(updateStashUntracked)
 	if err != nil {
@@ -443,8 +569,8 @@ func runUpdatePlan(ctx *updateContext) error {
 		WouldAbort:       wouldAbort,
 		RebuildMode:      strings.ToLower(strings.TrimSpace(updateRebuild)),
 		ComposeChanged:   ctx.composeChanged,
-		ServicesRebuild:  sortedKeys(ctx.servicesToRebuild),
-		ServicesRestart:  sortedKeys(ctx.servicesToRestart),
+		ServicesRebuild:  maputil.SortedKeys(ctx.servicesToRebuild),
+		ServicesRestart:  maputil.SortedKeys(ctx.servicesToRestart),
 		SkippedServices:  nil,
 		ChangedFileCount: len(ctx.changedFiles),
 		ChangedFiles:     files,
@@ -462,6 +588,9 @@ func runUpdatePlan(ctx *updateContext) error {
 	if !updateIncludeUI && ctx.composeChanged {
 		rep.Warnings = append(rep.Warnings, "Compose files changed; admin_ui changes (if any) are excluded unless --include-ui is enabled.")
 	}
+	if updatePrerelease {
+		rep.Warnings = append(rep.Warnings, fmt.Sprintf("--prerelease: running services using :latest registry images would be switched to :%s and the backup marked as pre-release.", updatePrereleaseTag))
+	}
 	if !updateAvailable && remoteIsAncestor && strings.TrimSpace(ctx.newSHA) != strings.TrimSpace(ctx.oldSHA) {
 		rep.Warnings = append(rep.Warnings, fmt.Sprintf("Local branch is ahead of %s/%s; no fast-forward update available.", updateRemote, updateRef))
 	}
@@ -518,18 +647,9 @@ func maybeSelfUpdateAndReexec() {
 		return
 	}
 
-	current := strings.TrimSpace(version)
-	if !strings.HasPrefix(strings.ToLower(current), "v") {
-		// dev builds: best-effort hint only
-		printSelfUpdateHint()
-		return
-	}
-
-	latest, err := fetchLatestReleaseTag(context.Background(), "hkjarral/Asterisk-AI-Voice-Agent")
-	if err != nil || latest == "" {
-		return
-	}
-	if compareSemver(current, latest) >= 0 {
+	// Dev builds are never reported as outdated.
+	rel, err := latestCLIRelease()
+	if err != nil || !rel.UpdateAvailable {
 		return
 	}
 
@@ -542,13 +662,9 @@ func maybeSelfUpdateAndReexec() {
 		exePath = resolved
 	}
 
-	binName, ok := releaseBinaryName(runtime.GOOS, runtime.GOARCH)
-	if !ok {
-		printSelfUpdateHint()
-		return
-	}
-
-	if err := selfUpdateFromGitHubRelease(latest, binName, exePath); err != nil {
+	update.ProgressWriter = updateHumanWriter()
+	if err := update.SelfUpgrade(context.Background(), rel.Tag); err != nil {
+		printUpdateInfo("Self-update failed: %v", err)
 		printSelfUpdateHint()
 		return
 	}
@@ -559,248 +675,50 @@ func maybeSelfUpdateAndReexec() {
 	_ = syscall.Exec(exePath, args, env)
 }
 
-func releaseBinaryName(goos string, goarch string) (string, bool) {
-	switch goos {
-	case "linux":
-		switch goarch {
-		case "amd64":
-			return "agent-linux-amd64", true
-		case "arm64":
-			return "agent-linux-arm64", true
-		}
-	case "darwin":
-		switch goarch {
-		case "amd64":
-			return "agent-darwin-amd64", true
-		case "arm64":
-			return "agent-darwin-arm64", true
-		}
-	case "windows":
-		if goarch == "amd64" {
-			return "agent-windows-amd64.exe", true
-		}
-	}
-	return "", false
-}
-
-func selfUpdateFromGitHubRelease(tag string, binName string, installPath string) error {
-	installDir := filepath.Dir(installPath)
-	if installDir == "" {
-		return errors.New("invalid install path")
-	}
-	if err := ensureWritableDir(installDir); err != nil {
-		return err
-	}
-
-	base := fmt.Sprintf("https://github.com/hkjarral/Asterisk-AI-Voice-Agent/releases/download/%s", tag)
-	binURL := base + "/" + binName
-	sumsURL := base + "/SHA256SUMS"
-
-	ctx, cancel := context.WithTimeout(context.Background(), 25*time.Second)
-	defer cancel()
-
-	sums, err := httpGetBytes(ctx, sumsURL)
-	if err != nil {
-		return err
-	}
-	expected, err := parseSHA256SUMS(sums, binName)
-	if err != nil {
-		return err
-	}
-
-	payload, err := httpGetBytes(ctx, binURL)
-	if err != nil {
-		return err
-	}
-	actual := fmt.Sprintf("%x", sha256.Sum256(payload))
-	if !strings.EqualFold(actual, expected) {
-		return fmt.Errorf("checksum mismatch for %s", binName)
-	}
-
-	// Backup existing binary (best-effort).
-	if _, err := os.Stat(installPath); err == nil {
-		bak := filepath.Join(installDir, "agent.bak."+time.Now().UTC().Format("20060102_150405"))
-		_ = copyFile(installPath, bak)
-	}
-
-	tmp := filepath.Join(installDir, ".agent.new."+strconv.Itoa(os.Getpid()))
-	if err := os.WriteFile(tmp, payload, 0o755); err != nil {
-		return err
-	}
-	_ = os.Chmod(tmp, 0o755)
-
-	if err := os.Rename(tmp, installPath); err != nil {
-		_ = os.Remove(tmp)
-		return err
-	}
-	return nil
-}
-
-func httpGetBytes(ctx context.Context, url string) ([]byte, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	if err != nil {
-		return nil, err
+// latestCLIRelease checks the latest release like agent version --check-update, sharing its
+// cache when the repo root is known.
+func latestCLIRelease() (update.LatestRelease, error) {
+	if repoRoot, err := resolveRepoRootForFix(); err == nil {
+		update.CachePath = filepath.Join(repoRoot, filepath.FromSlash(update.CacheFile))
 	}
-	req.Header.Set("User-Agent", "aava-agent-cli")
-	client := &http.Client{Timeout: 25 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("GET %s failed: %s", url, resp.Status)
-	}
-	return io.ReadAll(resp.Body)
-}
-
-func parseSHA256SUMS(sums []byte, filename string) (string, error) {
-	for _, line := range strings.Split(string(sums), "\n") {
-		line = strings.TrimSpace(line)
-		if line == "" {
-			continue
-		}
-		parts := strings.Fields(line)
-		if len(parts) < 2 {
-			continue
-		}
-		hash := strings.TrimSpace(parts[0])
-		name := strings.TrimSpace(parts[1])
-		if name == filename {
-			if len(hash) != 64 {
-				return "", fmt.Errorf("invalid sha256 length for %s", filename)
-			}
-			return hash, nil
-		}
-	}
-	return "", fmt.Errorf("checksum for %s not found in SHA256SUMS", filename)
-}
-
-func ensureWritableDir(dir string) error {
-	testPath := filepath.Join(dir, ".agent.write-test."+strconv.Itoa(os.Getpid()))
-	if err := os.WriteFile(testPath, []byte("x"), 0o600); err != nil {
-		return err
-	}
-	_ = os.Remove(testPath)
-	return nil
+	return update.CheckLatestRelease(context.Background(), version)
 }
 
 func printSelfUpdateHint() {
-	latest, err := fetchLatestReleaseTag(context.Background(), "hkjarral/Asterisk-AI-Voice-Agent")
-	if err != nil || latest == "" {
-		return
-	}
-	current := strings.TrimSpace(version)
-	if !strings.HasPrefix(strings.ToLower(current), "v") {
-		// dev builds or unknown formats are best-effort only.
-		return
-	}
-	if compareSemver(current, latest) >= 0 {
+	rel, err := latestCLIRelease()
+	if err != nil || !rel.UpdateAvailable {
 		return
 	}
-	fmt.Printf("Notice: a newer agent CLI is available (%s -> %s). Update with:\n", current, latest)
+	fmt.Printf("Notice: a newer agent CLI is available (%s -> %s). Update with:\n", rel.CurrentVersion, rel.Tag)
 	fmt.Printf("  curl -sSL https://raw.githubusercontent.com/hkjarral/Asterisk-AI-Voice-Agent/main/scripts/install-cli.sh | bash\n")
 }
 
-func fetchLatestReleaseTag(ctx context.Context, repo string) (string, error) {
-	ctx, cancel := context.WithTimeout(ctx, 4*time.Second)
-	defer cancel()
-
-	url := fmt.Sprintf("https://api.github.com/repos/%s/releases/latest", repo)
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	if err != nil {
-		return "", err
-	}
-	req.Header.Set("Accept", "application/vnd.github+json")
-	req.Header.Set("User-Agent", "aava-agent-cli")
-
-	client := &http.Client{Timeout: 5 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		return "", err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return "", fmt.Errorf("unexpected status %s", resp.Status)
-	}
-	body, err := io.ReadAll(resp.Body)
+func createUpdateBackups(ctx *updateContext) error {
+	backupDir, err := newUpdateBackupDir(ctx.repoRoot)
 	if err != nil {
-		return "", err
-	}
-	var payload struct {
-		TagName string `json:"tag_name"`
-	}
-	if err := json.Unmarshal(body, &payload); err != nil {
-		return "", err
-	}
-	tag := strings.TrimSpace(payload.TagName)
-	if tag == "" {
-		return "", errors.New("missing tag_name in response")
+		return err
 	}
-	return tag, nil
-}
+	ctx.backupDir = backupDir
 
-func compareSemver(a string, b string) int {
-	amaj, amin, apat, okA := parseSemver(a)
-	bmaj, bmin, bpat, okB := parseSemver(b)
-	if !okA || !okB {
-		return 0
-	}
-	if amaj != bmaj {
-		if amaj < bmaj {
-			return -1
-		}
-		return 1
-	}
-	if amin != bmin {
-		if amin < bmin {
-			return -1
-		}
-		return 1
-	}
-	if apat != bpat {
-		if apat < bpat {
-			return -1
+	for _, rel := range updateBackupPaths() {
+		if err := backupPathIfExists(rel, backupDir); err != nil {
+			return err
 		}
-		return 1
-	}
-	return 0
-}
-
-func parseSemver(v string) (major int, minor int, patch int, ok bool) {
-	v = strings.TrimSpace(v)
-	v = strings.TrimPrefix(strings.ToLower(v), "v")
-	if v == "" {
-		return 0, 0, 0, false
 	}
-	if i := strings.IndexByte(v, '-'); i >= 0 {
-		v = v[:i]
-	}
-	parts := strings.Split(v, ".")
-	if len(parts) < 3 {
-		return 0, 0, 0, false
-	}
-	maj, err := strconv.Atoi(parts[0])
-	if err != nil {
-		return 0, 0, 0, false
-	}
-	min, err := strconv.Atoi(parts[1])
-	if err != nil {
-		return 0, 0, 0, false
-	}
-	pat, err := strconv.Atoi(parts[2])
-	if err != nil {
-		return 0, 0, 0, false
+	if err := backup.WriteManifest(backupDir); err != nil {
+		return fmt.Errorf("failed to write backup manifest: %w", err)
 	}
-	return maj, min, pat, true
+	return encryptNewBackup(ctx.repoRoot, backupDir)
 }
 
-func createUpdateBackups(ctx *updateContext) error {
+// newUpdateBackupDir creates the directory for a new update-backup set, named by --backup-id
+// or the current UTC time.
+func newUpdateBackupDir(repoRoot string) (string, error) {
 	id := strings.TrimSpace(updateBackupID)
 	if id != "" {
 		id = sanitizeBackupID(id)
 		if id == "" {
-			return errors.New("invalid --backup-id")
+			return "", errors.New("invalid --backup-id")
 		}
 	}
 
@@ -809,26 +727,22 @@ func createUpdateBackups(ctx *updateContext) error {
 		dirName = id
 	}
 
-	backupDir := filepath.Join(ctx.repoRoot, ".agent", "update-backups", dirName)
+	backupDir := filepath.Join(updateBackupRoot(repoRoot), dirName)
 	if err := os.MkdirAll(backupDir, 0o755); err != nil {
-		return fmt.Errorf("failed to create backup directory: %w", err)
-	}
-	ctx.backupDir = backupDir
-
-	paths := []string{
-		".env",
-		filepath.Join("config", "ai-agent.yaml"),
-		filepath.Join("config", "ai-agent.local.yaml"),
-		filepath.Join("config", "users.json"),
-		filepath.Join("config", "contexts"),
+		return "", fmt.Errorf("failed to create backup directory: %w", err)
 	}
+	return backupDir, nil
+}
 
-	for _, rel := range paths {
-		if err := backupPathIfExists(rel, backupDir); err != nil {
-			return err
-		}
+// updateBackupPaths lists the files and directories an update-backup set snapshots, relative
+// to the repo root.
+func updateBackupPaths() []string {
+	paths := operatorConfigPaths()
+	if agentEnv != environment.Default {
+		// The update rewrites the shared upstream base, whatever the environment.
+		paths = append(paths, filepath.Join("config", "ai-agent.yaml"))
 	}
-	return nil
+	return paths
 }
 
 func sanitizeBackupID(s string) string {
@@ -858,7 +772,29 @@ func sanitizeBackupID(s string) string {
 	return strings.Trim(out.String(), "._-")
 }
 
+// backupPathIfExists snapshots relPath (relative to the repo root, which must be the cwd) into
+// backupRoot, hard-linking unchanged files to the shared .agent/content-store. When backups are
+// encrypted the store is bypassed, since its objects would keep a plaintext copy.
 func backupPathIfExists(relPath string, backupRoot string) error {
+	if _, err := os.Stat(relPath); err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to stat %s: %w", relPath, err)
+	}
+	if backupEnvValue(".", backup.EncryptKeyEnv) != "" {
+		return copyPathIfExists(relPath, backupRoot)
+	}
+	store, err := backup.NewContentStore(filepath.FromSlash(backup.ContentStoreDir))
+	if err != nil {
+		return err
+	}
+	return backup.BackupWithDedup(relPath, filepath.Join(backupRoot, relPath), store)
+}
+
+// copyPathIfExists copies relPath into root without the content store (e.g. staging outside
+// the repo).
+func copyPathIfExists(relPath string, root string) error {
 	info, err := os.Stat(relPath)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -866,36 +802,24 @@ func backupPathIfExists(relPath string, backupRoot string) error {
 		}
 		return fmt.Errorf("failed to stat %s: %w", relPath, err)
 	}
-	dst := filepath.Join(backupRoot, relPath)
+	dst := filepath.Join(root, relPath)
 	if info.IsDir() {
 		return copyDir(relPath, dst)
 	}
 	return copyFile(relPath, dst)
 }
 
+// copyFile copies src to dst atomically, writing dst as a new file (temp + rename) so that
+// restoring from a hard-linked backup never writes through into the content store. It keeps
+// src's mode (0600 for .env files) and, when running as root, its owner, so a restore under
+// sudo does not leave root-owned config behind.
 func copyFile(src string, dst string) error {
 	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
-		return fmt.Errorf("failed to create backup dir for %s: %w", dst, err)
-	}
-	in, err := os.Open(src)
-	if err != nil {
-		return fmt.Errorf("failed to open %s: %w", src, err)
-	}
-	defer in.Close()
-
-	out, err := os.Create(dst)
-	if err != nil {
-		return fmt.Errorf("failed to create %s: %w", dst, err)
+		return fmt.Errorf("failed to create parent directory of %s: %w", dst, err)
 	}
-	defer func() {
-		_ = out.Close()
-	}()
-	if _, err := io.Copy(out, in); err != nil {
+	if err := agentfs.CopyFileWithMeta(src, dst, agentfs.CopyOptions{Fsync: true}); err != nil {
 		return fmt.Errorf("failed to copy %s -> %s: %w", src, dst, err)
 	}
-	if err := out.Sync(); err != nil {
-		return fmt.Errorf("failed to sync %s: %w", dst, err)
-	}
 	return nil
 }
 
@@ -1041,17 +965,22 @@ func recoverFromStashConflict(ctx *updateContext) error {
 	if ctx.backupDir == "" {
 		return errors.New("no backup directory available for recovery")
 	}
+	srcDir, cleanup, err := openBackupDir(ctx.repoRoot, ctx.backupDir)
+	if err != nil {
+		return fmt.Errorf("failed to open backup %s: %w", ctx.backupDir, err)
+	}
+	defer cleanup()
 
 	// Restore operator-owned files. Do NOT restore config/ai-agent.yaml over the updated upstream base.
 	// If the operator had edits in ai-agent.yaml, we migrate them into ai-agent.local.yaml below.
 	configFiles := []string{
-		".env",
-		filepath.Join("config", "ai-agent.local.yaml"),
-		filepath.Join("config", "users.json"),
+		envRel(".env"),
+		envRel(filepath.Join("config", "ai-agent.local.yaml")),
+		envRel(filepath.Join("config", "users.json")),
 	}
 
 	for _, rel := range configFiles {
-		src := filepath.Join(ctx.backupDir, rel)
+		src := filepath.Join(srcDir, rel)
 		if _, err := os.Stat(src); err != nil {
 			continue // backup didn't include this file (e.g. local.yaml may not exist yet)
 		}
@@ -1062,7 +991,7 @@ func recoverFromStashConflict(ctx *updateContext) error {
 	}
 
 	// Restore contexts directory if backed up.
-	ctxSrc := filepath.Join(ctx.backupDir, "config", "contexts")
+	ctxSrc := filepath.Join(srcDir, "config", "contexts")
 	if info, err := os.Stat(ctxSrc); err == nil && info.IsDir() {
 		ctxDst := filepath.Join("config", "contexts")
 		_ = os.RemoveAll(ctxDst)
@@ -1076,7 +1005,7 @@ func recoverFromStashConflict(ctx *updateContext) error {
 
 	// Best-effort: if backup included ai-agent.yaml edits, migrate them into ai-agent.local.yaml.
 	// Fall back to restoring the base file only if migration fails due to YAML parse errors.
-	backupBase := filepath.Join(ctx.backupDir, "config", "ai-agent.yaml")
+	backupBase := filepath.Join(srcDir, "config", "ai-agent.yaml")
 	if _, err := os.Stat(backupBase); err == nil {
 		if err := migrateBackupBaseConfigEditsToLocal(ctx.oldSHA, backupBase); err != nil {
 			printUpdateInfo("WARN: failed to migrate backed-up ai-agent.yaml edits into ai-agent.local.yaml: %v", err)
@@ -1145,12 +1074,12 @@ func gitFetch(remote string, ref string) error {
 func migrateBackupBaseConfigEditsToLocal(oldSHA string, backupBasePath string) error {
 	// Determine what the operator changed in ai-agent.yaml prior to the update, then carry only those
 	// edits forward into ai-agent.local.yaml. This avoids freezing upstream defaults when the base file
-	// changes between releases.
+	// changes between releases. Files are read raw so ENC[...] secrets stay encrypted in the result.
 	baseBefore, err := gitShowYAMLMap(oldSHA, filepath.Join("config", "ai-agent.yaml"))
 	if err != nil {
 		return err
 	}
-	backupBase, err := configmerge.ReadYAMLFile(backupBasePath)
+	backupBase, err := configmerge.ReadYAMLFileRaw(backupBasePath)
 	if err != nil {
 		return err
 	}
@@ -1159,7 +1088,7 @@ func migrateBackupBaseConfigEditsToLocal(oldSHA string, backupBasePath string) e
 	localPath := filepath.Join("config", "ai-agent.local.yaml")
 	local := map[string]any{}
 	if _, statErr := os.Stat(localPath); statErr == nil {
-		m, err := configmerge.ReadYAMLFile(localPath)
+		m, err := configmerge.ReadYAMLFileRaw(localPath)
 		if err != nil {
 			return fmt.Errorf("failed to parse existing %s during migration: %w", localPath, err)
 		}
@@ -1198,7 +1127,7 @@ func migrateBaseConfigEditsToLocal() error {
 		return err
 	}
 
-	baseWorking, err := configmerge.ReadYAMLFile(baseRel)
+	baseWorking, err := configmerge.ReadYAMLFileRaw(baseRel)
 	if err != nil {
 		return err
 	}
@@ -1206,7 +1135,7 @@ func migrateBaseConfigEditsToLocal() error {
 	localRel := filepath.Join("config", "ai-agent.local.yaml")
 	localExisting := map[string]any{}
 	if _, statErr := os.Stat(localRel); statErr == nil {
-		m, err := configmerge.ReadYAMLFile(localRel)
+		m, err := configmerge.ReadYAMLFileRaw(localRel)
 		if err != nil {
 			return fmt.Errorf("failed to parse existing %s during migration: %w", localRel, err)
 		}
@@ -1396,20 +1325,7 @@ func applyDockerActions(ctx *updateContext) error {
 	// implicitly (re)create services the operator never started (e.g., local_ai_server) and
 	// fail if their images aren't present. Instead, scope to services that are already running
 	// plus any services we explicitly intend to rebuild/restart.
-	runningServices := map[string]bool{}
-	out, err := runCmd("docker", "compose", "ps", "--services", "--status", "running")
-	if err != nil {
-		// Fallback for older compose versions (or environments where --status isn't supported).
-		out, err = runCmd("docker", "compose", "ps", "--services")
-	}
-	if err == nil {
-		for _, line := range strings.Split(out, "\n") {
-			svc := strings.TrimSpace(line)
-			if svc != "" {
-				runningServices[svc] = true
-			}
-		}
-	}
+	runningServices := runningComposeServices()
 
 	if ctx.composeChanged {
 		// Avoid implicit builds when Compose files change (some deployments use pull_policy: build).
@@ -1438,15 +1354,15 @@ func applyDockerActions(ctx *updateContext) error {
 
 		// Only run compose-up if we have explicit targets; otherwise, don't implicitly start services.
 		if len(targets) > 0 {
-			args = append(args, sortedKeys(targets)...)
+			args = append(args, maputil.SortedKeys(targets)...)
 			if _, err := runCmd("docker", args...); err != nil {
 				return fmt.Errorf("docker compose up (remove-orphans) failed: %w", err)
 			}
 		}
 	}
 
-	rebuildServices := sortedKeys(ctx.servicesToRebuild)
-	restartServices := sortedKeys(ctx.servicesToRestart)
+	rebuildServices := maputil.SortedKeys(ctx.servicesToRebuild)
+	restartServices := maputil.SortedKeys(ctx.servicesToRestart)
 
 	// Avoid starting services that aren't already running unless explicitly targeted by rebuild/restart.
 	if !updateIncludeUI {
@@ -1486,6 +1402,26 @@ func applyDockerActions(ctx *updateContext) error {
 	return nil
 }
 
+// runningComposeServices returns the Compose services that are running (best-effort: empty
+// when docker compose ps fails).
+func runningComposeServices() map[string]bool {
+	running := map[string]bool{}
+	out, err := runCmd("docker", "compose", "ps", "--services", "--status", "running")
+	if err != nil {
+		// Fallback for older compose versions (or environments where --status isn't supported).
+		out, err = runCmd("docker", "compose", "ps", "--services")
+	}
+	if err == nil {
+		for _, line := range strings.Split(out, "\n") {
+			svc := strings.TrimSpace(line)
+			if svc != "" {
+				running[svc] = true
+			}
+		}
+	}
+	return running
+}
+
 func filterSlice(in []string, keep func(string) bool) []string {
 	if len(in) == 0 {
 		return nil
@@ -1499,9 +1435,15 @@ func filterSlice(in []string, keep func(string) bool) []string {
 	return out
 }
 
-func runPostUpdateCheck() (report *check.Report, status string, warnCount int, failCount int, err error) {
-	runner := check.NewRunner(verbose, version, buildTime)
-	report, runErr := runner.Run()
+// runPostUpdateCheck runs agent check until it reports no failures or timeout has elapsed
+// (services may still be starting), and returns the last result.
+func runPostUpdateCheck(log *slog.Logger, timeout time.Duration) (report *check.Report, status string, warnCount int, failCount int, err error) {
+	runner := newCheckRunner()
+	runner.Logger = log
+	if timeout > 0 {
+		printUpdateInfo("Waiting up to %s for agent check to pass", timeout)
+	}
+	report, runErr := check.WaitForServicesHealthy(runner, check.DefaultTimeout, timeout, updateHealthPoll)
 	if report == nil {
 		return nil, "FAIL", 0, 1, fmt.Errorf("agent check failed: %w", runErr)
 	}
@@ -1516,6 +1458,31 @@ func runPostUpdateCheck() (report *check.Report, status string, warnCount int, f
 	return report, "PASS", 0, 0, nil
 }
 
+// rollbackFailedUpdate undoes an update whose post-update check failed: the branch goes back to
+// ctx.oldSHA (git reset --keep preserves uncommitted changes), operator config is restored from
+// ctx.backupDir, and the services touched by the update are rebuilt/restarted from the old code.
+func rollbackFailedUpdate(ctx *updateContext) error {
+	if strings.TrimSpace(ctx.oldSHA) != strings.TrimSpace(ctx.newSHA) {
+		printUpdateInfo("Resetting code to %s", shortSHA(ctx.oldSHA))
+		if _, err := runGitCmd("reset", "--keep", ctx.oldSHA); err != nil {
+			return fmt.Errorf("git reset --keep %s failed: %w", shortSHA(ctx.oldSHA), err)
+		}
+	}
+	if ctx.backupDir != "" {
+		result := restoreFromSingleBackupDir(ctx.backupDir, true, true, ".")
+		for _, w := range result.warnings {
+			printUpdateInfo("Warning: %s", w)
+		}
+		if len(result.restoredPaths) > 0 {
+			printUpdateInfo("Restored config from %s: %s", ctx.backupDir, strings.Join(result.restoredPaths, ", "))
+		}
+	}
+	if err := applyDockerActions(ctx); err != nil {
+		return err
+	}
+	return restartCoreServices()
+}
+
 func printUpdateFailureRecovery(ctx *updateContext, err error) {
 	fmt.Printf("\n==> Update failed\n")
 	printUpdateInfo("Error: %v", err)
@@ -1523,14 +1490,25 @@ func printUpdateFailureRecovery(ctx *updateContext, err error) {
 	if ctx == nil {
 		return
 	}
+	if ctx.rolledBack {
+		printUpdateInfo("Code, config and containers were rolled back to %s", shortSHA(ctx.oldSHA))
+		printUpdateInfo("Backups: %s", ctx.backupDir)
+		return
+	}
 
-	if ctx.backupDir != "" {
+	if ctx.backupDir != "" && backup.IsEncrypted(ctx.backupDir) {
+		printUpdateInfo("Backups: %s (encrypted)", ctx.backupDir)
+		fmt.Println("Recovery (restore operator-owned config):")
+		fmt.Printf("  %s=<identity file> agent rollback %s\n", backup.IdentityFileEnv, ctx.backupDir)
+	} else if ctx.backupDir != "" {
 		printUpdateInfo("Backups: %s", ctx.backupDir)
 		fmt.Println("Recovery (restore operator-owned config):")
-		fmt.Printf("  cp %s .env\n", filepath.Join(ctx.backupDir, ".env"))
+		fmt.Printf("  cp %s %s\n", filepath.Join(ctx.backupDir, envRel(".env")), envRel(".env"))
 		fmt.Printf("  cp %s %s\n", filepath.Join(ctx.backupDir, "config", "ai-agent.yaml"), filepath.Join("config", "ai-agent.yaml"))
-		fmt.Printf("  cp %s %s  # if exists\n", filepath.Join(ctx.backupDir, "config", "ai-agent.local.yaml"), filepath.Join("config", "ai-agent.local.yaml"))
-		fmt.Printf("  cp %s %s\n", filepath.Join(ctx.backupDir, "config", "users.json"), filepath.Join("config", "users.json"))
+		localRel := envRel(filepath.Join("config", "ai-agent.local.yaml"))
+		usersRel := envRel(filepath.Join("config", "users.json"))
+		fmt.Printf("  cp %s %s  # if exists\n", filepath.Join(ctx.backupDir, localRel), localRel)
+		fmt.Printf("  cp %s %s\n", filepath.Join(ctx.backupDir, usersRel), usersRel)
 		fmt.Println("  # Replace contexts directory (if needed):")
 		fmt.Printf("  rm -rf %s && cp -r %s %s\n",
 			filepath.Join("config", "contexts"),
@@ -1568,10 +1546,10 @@ func printUpdateSummary(ctx *updateContext, checkStatus string, warnCount int, f
 		}
 	}
 	if len(ctx.servicesToRebuild) > 0 {
-		fmt.Printf("Rebuilt: %s\n", strings.Join(sortedKeys(ctx.servicesToRebuild), ", "))
+		fmt.Printf("Rebuilt: %s\n", strings.Join(maputil.SortedKeys(ctx.servicesToRebuild), ", "))
 	}
 	if len(ctx.servicesToRestart) > 0 {
-		fmt.Printf("Restarted: %s\n", strings.Join(sortedKeys(ctx.servicesToRestart), ", "))
+		fmt.Printf("Restarted: %s\n", strings.Join(maputil.SortedKeys(ctx.servicesToRestart), ", "))
 	}
 	if ctx.composeChanged {
 		fmt.Printf("Compose: applied changes\n")
@@ -1606,10 +1584,10 @@ func printDockerActionsPlanned(ctx *updateContext) {
 		printUpdateInfo("Compose files changed (will run docker compose up --no-build --remove-orphans)")
 	}
 	if len(ctx.servicesToRebuild) > 0 {
-		printUpdateInfo("Will rebuild: %s", strings.Join(sortedKeys(ctx.servicesToRebuild), ", "))
+		printUpdateInfo("Will rebuild: %s", strings.Join(maputil.SortedKeys(ctx.servicesToRebuild), ", "))
 	}
 	if len(ctx.servicesToRestart) > 0 {
-		printUpdateInfo("Will restart: %s", strings.Join(sortedKeys(ctx.servicesToRestart), ", "))
+		printUpdateInfo("Will restart: %s", strings.Join(maputil.SortedKeys(ctx.servicesToRestart), ", "))
 	}
 }
 
@@ -1618,17 +1596,8 @@ func printPostUpdateCheck(report *check.Report, warnCount int, failCount int) {
 		return
 	}
 	if verbose || warnCount > 0 || failCount > 0 {
-		report.OutputText(os.Stdout)
-	}
-}
-
-func sortedKeys(m map[string]bool) []string {
-	keys := make([]string, 0, len(m))
-	for k := range m {
-		keys = append(keys, k)
+		report.OutputText(os.Stdout, check.OutputOptions{})
 	}
-	sort.Strings(keys)
-	return keys
 }
 
 func shortSHA(sha string) string {
@@ -1639,34 +1608,17 @@ func shortSHA(sha string) string {
 	return sha
 }
 
+// runCmd runs name and returns its trimmed stdout. With --verbose or --verbose-commands the
+// command line is echoed and its output streamed to the terminal as it runs.
 func runCmd(name string, args ...string) (string, error) {
-	cmd := exec.Command(name, args...)
-	cmd.Stdin = os.Stdin
-	if verbose {
+	streams := cmdexec.Streams{Stdin: os.Stdin}
+	if verbose || verboseCommands {
 		fmt.Printf(" → %s %s\n", name, strings.Join(args, " "))
-		var buf bytes.Buffer
-		cmd.Stdout = io.MultiWriter(os.Stdout, &buf)
-		cmd.Stderr = io.MultiWriter(os.Stderr, &buf)
-		err := cmd.Run()
-		text := strings.TrimSpace(buf.String())
-		if err != nil {
-			if text != "" {
-				return text, fmt.Errorf("%w", err)
-			}
-			return text, err
-		}
-		return text, nil
-	}
-
-	out, err := cmd.CombinedOutput()
-	text := strings.TrimSpace(string(out))
-	if err != nil {
-		if text != "" {
-			return text, fmt.Errorf("%w: %s", err, text)
-		}
-		return text, err
+		streams.Stdout, streams.Stderr = os.Stdout, os.Stderr
 	}
-	return text, nil
+	ctx := cmdexec.WithStreams(context.Background(), streams)
+	res, err := cmdexec.RunCmdResult(ctx, append([]string{name}, args...)...)
+	return strings.TrimSpace(res.Stdout), err
 }
 
 func runGitCmd(args ...string) (string, error) {
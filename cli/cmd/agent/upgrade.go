@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/update"
+	"github.com/spf13/cobra"
+)
+
+var upgradeTargetVersion string
+
+var upgradeCmd = &cobra.Command{
+	Use:   "upgrade",
+	Short: "Replace this agent binary with a release from GitHub",
+	Long: `Download the agent binary for this platform from a GitHub release, verify it against the
+release's SHA256SUMS, and install it over the running binary.
+
+The current binary is copied to <binary>.bak first. If the new binary cannot run "agent
+version", the backup is moved back; otherwise it is removed. Run as a user that can write to
+the binary's directory (sudo for /usr/local/bin). Not supported on Windows.
+
+Unlike agent update, this only touches the CLI binary, not the repo checkout or containers.
+
+  agent upgrade
+  agent upgrade --target-version v6.3.1`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fmt.Printf("Current version: %s\n", version)
+		_, _, _, currentOK := update.ParseSemver(version)
+		_, _, _, targetOK := update.ParseSemver(upgradeTargetVersion)
+		if currentOK && targetOK && update.CompareSemver(version, upgradeTargetVersion) == 0 {
+			fmt.Printf("Already running %s\n", version)
+			return nil
+		}
+		return update.SelfUpgrade(cmd.Context(), upgradeTargetVersion)
+	},
+}
+
+func init() {
+	upgradeCmd.Flags().StringVar(&upgradeTargetVersion, "target-version", "latest", "release tag to install (vX.Y.Z)")
+	rootCmd.AddCommand(upgradeCmd)
+}
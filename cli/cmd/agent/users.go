@@ -0,0 +1,224 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/users"
+	"github.com/spf13/cobra"
+)
+
+var (
+	usersUsername string
+	usersPassword string
+	usersRoles    []string
+
+	usersPasswordFile string
+	usersGrace        time.Duration
+)
+
+var usersCmd = &cobra.Command{
+	Use:    "users",
+	Short:  "Manage Admin UI logins in config/users.json",
+	Hidden: true, // advanced tool; the Admin UI manages its own password changes
+	Long: `Manage the Admin UI logins stored in config/users.json without hand-editing JSON.
+
+Subcommands:
+  list    Show users
+  add     Add a user (password hashed as pbkdf2_sha256, the scheme the Admin UI verifies)
+  remove  Remove a user (the last user cannot be removed)
+  passwd  Replace a user's password
+
+Writes hold config/users.json.lock and replace the file atomically. admin_ui does not need a
+restart; it reads users.json on every login.`,
+}
+
+var usersListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List Admin UI users",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := newUserStore()
+		if err != nil {
+			return err
+		}
+		list, err := store.List()
+		if err != nil {
+			return err
+		}
+		if len(list) == 0 {
+			fmt.Printf("No users in %s\n", store.Path)
+			return nil
+		}
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "USERNAME\tROLES\tDISABLED\tMUST CHANGE PASSWORD")
+		for _, u := range list {
+			roles := strings.Join(u.Roles, ",")
+			if roles == "" {
+				roles = "-"
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%t\t%t\n", u.Username, roles, u.Disabled, u.MustChangePassword)
+		}
+		return tw.Flush()
+	},
+}
+
+var usersAddCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Add an Admin UI user",
+	Long: `Add an Admin UI user. Without --password, the password is read from the first line
+of stdin (avoids leaving it in shell history and the process list):
+
+  echo "$PASSWORD" | agent users add --username ops`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := newUserStore()
+		if err != nil {
+			return err
+		}
+		password := usersPassword
+		if password == "" {
+			fmt.Fprint(os.Stderr, "Password: ")
+			line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+			if err != nil && line == "" {
+				return errors.New("no password given (use --password or pipe it on stdin)")
+			}
+			password = strings.TrimRight(line, "\r\n")
+		}
+		if err := store.Add(usersUsername, password, usersRoles); err != nil {
+			return err
+		}
+		fmt.Printf("Added user %s to %s\n", strings.TrimSpace(usersUsername), store.Path)
+		return nil
+	},
+}
+
+var usersRemoveCmd = &cobra.Command{
+	Use:   "remove",
+	Short: "Remove an Admin UI user",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := newUserStore()
+		if err != nil {
+			return err
+		}
+		if err := store.Remove(usersUsername); err != nil {
+			return err
+		}
+		fmt.Printf("Removed user %s from %s\n", strings.TrimSpace(usersUsername), store.Path)
+		return nil
+	},
+}
+
+var usersPasswdCmd = &cobra.Command{
+	Use:   "passwd",
+	Short: "Replace an Admin UI user's password",
+	Long: `Replace an Admin UI user's password hash and clear must_change_password.
+
+The new password is read from the terminal (twice, not echoed) or, for scripts, from the
+first line of --password-file. The replaced hash is dropped immediately. With --grace (e.g.
+24h) it is kept in previous_password_hash until previous_password_expires instead: until then
+the Admin UI accepts either password at login, so scripts and other operators can switch
+over. Don't use it when the old password may have leaked. The hash is removed by the next
+write to users.json after that. Sessions already logged in are unaffected either way.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if usersGrace < 0 {
+			return errors.New("--grace must not be negative")
+		}
+		store, err := newUserStore()
+		if err != nil {
+			return err
+		}
+		var password string
+		if usersPasswordFile != "" {
+			data, err := os.ReadFile(usersPasswordFile)
+			if err != nil {
+				return err
+			}
+			password, _, _ = strings.Cut(string(data), "\n")
+			password = strings.TrimRight(password, "\r")
+		} else {
+			if fi, err := os.Stdin.Stat(); err != nil || fi.Mode()&os.ModeCharDevice == 0 {
+				return errors.New("stdin is not a terminal (use --password-file)")
+			}
+			password, err = readPasswordTTY("New password: ")
+			if err != nil {
+				return err
+			}
+			again, err := readPasswordTTY("Retype new password: ")
+			if err != nil {
+				return err
+			}
+			if password != again {
+				return errors.New("passwords do not match")
+			}
+		}
+		if err := store.SetPassword(usersUsername, password, usersGrace); err != nil {
+			return err
+		}
+		fmt.Printf("Updated password for %s in %s\n", strings.TrimSpace(usersUsername), store.Path)
+		if usersGrace > 0 {
+			fmt.Fprintf(os.Stderr, "WARNING: the old password still logs in to the Admin UI until %s (--grace %s)\n",
+				time.Now().Add(usersGrace).UTC().Format(time.RFC3339), usersGrace)
+		}
+		return nil
+	},
+}
+
+func init() {
+	usersAddCmd.Flags().StringVar(&usersUsername, "username", "", "login name")
+	usersAddCmd.Flags().StringVar(&usersPassword, "password", "", "password (read from stdin when omitted)")
+	usersAddCmd.Flags().StringSliceVar(&usersRoles, "roles", nil, "comma-separated roles to record (e.g. admin)")
+	_ = usersAddCmd.MarkFlagRequired("username")
+	usersRemoveCmd.Flags().StringVar(&usersUsername, "username", "", "login name")
+	_ = usersRemoveCmd.MarkFlagRequired("username")
+	usersPasswdCmd.Flags().StringVar(&usersUsername, "username", "", "login name")
+	usersPasswdCmd.Flags().StringVar(&usersPasswordFile, "password-file", "", "read the new password from the first line of this file")
+	usersPasswdCmd.Flags().DurationVar(&usersGrace, "grace", users.DefaultPasswordGrace, "keep the previous password valid for this long (e.g. 24h; default: not at all)")
+	_ = usersPasswdCmd.MarkFlagRequired("username")
+
+	usersCmd.AddCommand(usersListCmd, usersAddCmd, usersRemoveCmd, usersPasswdCmd)
+	rootCmd.AddCommand(usersCmd)
+}
+
+func newUserStore() (*users.UserStore, error) {
+	repoRoot, err := resolveRepoRootForFix()
+	if err != nil {
+		return nil, err
+	}
+	return users.NewUserStore(filepath.Join(repoRoot, users.DefaultPath)), nil
+}
+
+// readPasswordTTY prompts on stderr and reads one line from the terminal with echo disabled
+// via stty (on Windows the input is echoed).
+func readPasswordTTY(prompt string) (string, error) {
+	fmt.Fprint(os.Stderr, prompt)
+	if runtime.GOOS != "windows" {
+		if err := stty("-echo"); err == nil {
+			defer func() {
+				_ = stty("echo")
+				fmt.Fprintln(os.Stderr)
+			}()
+		}
+	}
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", errors.New("no password entered")
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func stty(arg string) error {
+	c := exec.Command("stty", arg)
+	c.Stdin = os.Stdin
+	return c.Run()
+}
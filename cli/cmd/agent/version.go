@@ -2,22 +2,48 @@ package main
 
 import (
 	"fmt"
+	"path/filepath"
 
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/update"
 	"github.com/spf13/cobra"
 )
 
+var versionCheckUpdate bool
+
 var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "Show version information",
-	Long:  "Display the version of the agent CLI tool",
-	Run: func(cmd *cobra.Command, args []string) {
+	Long: `Display the version of the agent CLI tool.
+
+With --check-update, also ask the GitHub Releases API for the latest release. The answer is
+cached in .agent/update-check-cache.json for 24 hours to stay under GitHub's rate limit.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
 		fmt.Printf("Asterisk AI Voice Agent CLI\n")
 		fmt.Printf("Version:    %s\n", version)
 		fmt.Printf("Built:      %s\n", buildTime)
 		fmt.Printf("Repository: https://github.com/hkjarral/Asterisk-AI-Voice-Agent\n")
+		if !versionCheckUpdate {
+			return nil
+		}
+
+		if repoRoot, err := resolveRepoRootForFix(); err == nil {
+			update.CachePath = filepath.Join(repoRoot, filepath.FromSlash(update.CacheFile))
+		}
+		rel, err := update.CheckLatestRelease(cmd.Context(), version)
+		if err != nil {
+			return fmt.Errorf("update check failed: %w", err)
+		}
+		fmt.Println()
+		if !rel.UpdateAvailable {
+			fmt.Println("Up to date")
+			return nil
+		}
+		fmt.Printf("Update available: %s (release notes: %s)\n", rel.Tag, rel.URL)
+		return nil
 	},
 }
 
 func init() {
+	versionCmd.Flags().BoolVar(&versionCheckUpdate, "check-update", false, "check GitHub for a newer release")
 	rootCmd.AddCommand(versionCmd)
 }
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"os"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/logging"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/watch"
+	"github.com/spf13/cobra"
+)
+
+var watchConfigCmd = &cobra.Command{
+	Use:    "watch-config",
+	Short:  "Re-run agent check whenever config/ or .env changes",
+	Hidden: true, // advanced tool
+	Long: `Watch config/ and .env (.env.<name> with --env) and re-run the diagnostics after each
+save, without polling (inotify on Linux). The first run prints the full report; later runs
+print the files that changed and the checks whose status changed.
+
+Runs start once the files have been quiet for 300ms. From the second run with failures in a
+row the wait doubles, up to 30s, so a series of bad saves does not thrash; a clean run resets
+it.
+
+Stop with Ctrl-C (SIGINT) or SIGTERM.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		repoRoot, err := resolveRepoRootForFix()
+		if err != nil {
+			return err
+		}
+		runner := newCheckRunner()
+		runner.Logger = logging.FromContext(cmd.Context())
+		if err := os.Chdir(repoRoot); err != nil {
+			return err
+		}
+		return watch.WatchConfig(repoRoot, runner, os.Stdout)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(watchConfigCmd)
+}
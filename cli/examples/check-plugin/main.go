@@ -0,0 +1,52 @@
+// Command check-plugin is an example third-party check for "agent check". It reports whether
+// the SIP trunk host named by SIP_TRUNK_HOST resolves and accepts TCP connections on
+// SIP_TRUNK_PORT (default 5060).
+//
+// Build it as a Go plugin into the repo's plugin directory:
+//
+//	CGO_ENABLED=1 go build -buildmode=plugin -o ../.agent/checks/sip-trunk.so ./examples/check-plugin
+//
+// Go plugins only load into an agent binary built with CGO_ENABLED=1 from the same source tree
+// and Go version (release builds are static and report a warning instead). main is unused.
+package main
+
+import (
+	"context"
+	"net"
+	"os"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/check"
+)
+
+// Check is the symbol agent looks up (check.PluginSymbol).
+var Check check.CheckPlugin = sipTrunkCheck{}
+
+type sipTrunkCheck struct{}
+
+func (sipTrunkCheck) Name() string { return "SIP Trunk" }
+
+func (sipTrunkCheck) Run(ctx context.Context) check.Item {
+	host := os.Getenv("SIP_TRUNK_HOST")
+	if host == "" {
+		return check.Item{Status: check.StatusSkip, Message: "SIP_TRUNK_HOST not set"}
+	}
+	port := os.Getenv("SIP_TRUNK_PORT")
+	if port == "" {
+		port = "5060"
+	}
+	addr := net.JoinHostPort(host, port)
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return check.Item{
+			Status:      check.StatusWarn,
+			Message:     "SIP trunk unreachable over TCP",
+			Details:     err.Error(),
+			Remediation: "Check SIP_TRUNK_HOST/SIP_TRUNK_PORT and the firewall (UDP-only trunks will always warn)",
+		}
+	}
+	_ = conn.Close()
+	return check.Item{Status: check.StatusPass, Message: "reachable at " + addr}
+}
+
+func main() {}
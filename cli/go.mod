@@ -3,8 +3,11 @@ module github.com/hkjarral/asterisk-ai-voice-agent/cli
 go 1.22
 
 require (
+	filippo.io/age v1.2.1
 	github.com/fatih/color v1.16.0
+	github.com/santhosh-tekuri/jsonschema/v5 v5.3.1
 	github.com/spf13/cobra v1.8.0
+	golang.org/x/sys v0.21.0
 	gopkg.in/yaml.v3 v3.0.1
 )
 
@@ -13,5 +16,5 @@ require (
 	github.com/mattn/go-colorable v0.1.13 // indirect
 	github.com/mattn/go-isatty v0.0.20 // indirect
 	github.com/spf13/pflag v1.0.5 // indirect
-	golang.org/x/sys v0.14.0 // indirect
+	golang.org/x/crypto v0.24.0 // indirect
 )
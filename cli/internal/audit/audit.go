@@ -0,0 +1,301 @@
+// Package audit lists the operator config changes made since a backup set, for agent config audit.
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/backup"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/configmerge"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/environment"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/maputil"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/users"
+)
+
+const (
+	// Absent is the value of a key, user or file that does not exist on that side.
+	Absent = "(absent)"
+	// Present is the value of a user or file whose existence is all that is compared.
+	Present = "(present)"
+)
+
+// AuditEntry is one change between a backup set and the live config. Key is the .env
+// variable, the dot-separated YAML path or the username; it is empty when a whole file was
+// added or removed.
+type AuditEntry struct {
+	File     string `json:"file"`
+	Key      string `json:"key,omitempty"`
+	OldValue string `json:"old_value"`
+	NewValue string `json:"new_value"`
+}
+
+// AuditConfigChanges compares the operator config of environment env under root (.env,
+// config/ai-agent.yaml, config/ai-agent.local.yaml and config/users.json, named as
+// environment.Path maps them, plus the shared config/contexts) with the backup set since,
+// which must be plaintext. Secret .env values are shown as backup.RedactedValue; users.json
+// only reports added and removed usernames. Entries are sorted by file, then key.
+func AuditConfigChanges(root, env string, since backup.BackupSetInfo) ([]AuditEntry, error) {
+	files, err := auditedFiles(env, root, since.Path)
+	if err != nil {
+		return nil, err
+	}
+	envFile, usersFile := environment.Path(".env", env), environment.Path(users.DefaultPath, env)
+	var entries []AuditEntry
+	for _, rel := range files {
+		oldPath := filepath.Join(since.Path, filepath.FromSlash(rel))
+		newPath := filepath.Join(root, filepath.FromSlash(rel))
+		oldExists, newExists := exists(oldPath), exists(newPath)
+		if !oldExists || !newExists {
+			e := AuditEntry{File: rel, OldValue: Absent, NewValue: Present}
+			if oldExists {
+				e.OldValue, e.NewValue = Present, Absent
+			}
+			entries = append(entries, e)
+			continue
+		}
+
+		var diff []AuditEntry
+		switch {
+		case rel == envFile:
+			diff, err = diffEnv(oldPath, newPath)
+		case rel == usersFile:
+			diff, err = diffUsers(oldPath, newPath)
+		default:
+			diff, err = diffYAML(oldPath, newPath)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", rel, err)
+		}
+		for i := range diff {
+			diff[i].File = rel
+		}
+		entries = append(entries, diff...)
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].File != entries[j].File {
+			return entries[i].File < entries[j].File
+		}
+		return entries[i].Key < entries[j].Key
+	})
+	return entries, nil
+}
+
+// auditedFiles lists the operator config files of environment env present on either side, as
+// slash paths.
+func auditedFiles(env string, dirs ...string) ([]string, error) {
+	seen := map[string]bool{}
+	for _, dir := range dirs {
+		for _, rel := range []string{".env", "config/ai-agent.yaml", "config/ai-agent.local.yaml", users.DefaultPath} {
+			rel = environment.Path(rel, env)
+			if exists(filepath.Join(dir, filepath.FromSlash(rel))) {
+				seen[rel] = true
+			}
+		}
+		contexts := filepath.Join(dir, "config", "contexts")
+		err := filepath.WalkDir(contexts, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if os.IsNotExist(err) {
+					return nil
+				}
+				return err
+			}
+			if d.IsDir() && strings.HasPrefix(d.Name(), ".") && p != contexts {
+				return filepath.SkipDir // e.g. .deleted from agent config contexts remove
+			}
+			if ext := path.Ext(d.Name()); d.Type().IsRegular() && (ext == ".yaml" || ext == ".yml") {
+				rel, err := filepath.Rel(dir, p)
+				if err != nil {
+					return err
+				}
+				seen[filepath.ToSlash(rel)] = true
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return maputil.SortedKeys(seen), nil
+}
+
+func diffEnv(oldPath, newPath string) ([]AuditEntry, error) {
+	before, err := readEnv(oldPath)
+	if err != nil {
+		return nil, err
+	}
+	after, err := readEnv(newPath)
+	if err != nil {
+		return nil, err
+	}
+	mask := func(key, value string) string {
+		if value == Absent || value == "" || !backup.IsSecretEnvKey(key) {
+			return value
+		}
+		return backup.RedactedValue
+	}
+	var out []AuditEntry
+	for _, key := range unionKeys(before, after) {
+		ov, inOld := before[key]
+		nv, inNew := after[key]
+		if inOld && inNew && ov == nv {
+			continue
+		}
+		if !inOld {
+			ov = Absent
+		}
+		if !inNew {
+			nv = Absent
+		}
+		out = append(out, AuditEntry{Key: key, OldValue: mask(key, ov), NewValue: mask(key, nv)})
+	}
+	return out, nil
+}
+
+// readEnv parses KEY=value lines (an optional "export " prefix, comments and blank lines
+// skipped); a repeated key keeps its last value, as docker compose does.
+func readEnv(p string) (map[string]string, error) {
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return nil, err
+	}
+	values := map[string]string{}
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			continue
+		}
+		values[key] = strings.TrimSpace(value)
+	}
+	return values, nil
+}
+
+func diffYAML(oldPath, newPath string) ([]AuditEntry, error) {
+	before, err := configmerge.ReadYAMLFile(oldPath)
+	if err != nil {
+		return nil, err
+	}
+	after, err := configmerge.ReadYAMLFile(newPath)
+	if err != nil {
+		return nil, err
+	}
+	oldLeaves, newLeaves := map[string]string{}, map[string]string{}
+	flatten("", before, oldLeaves)
+	flatten("", after, newLeaves)
+
+	var out []AuditEntry
+	for _, key := range unionKeys(oldLeaves, newLeaves) {
+		ov, inOld := oldLeaves[key]
+		nv, inNew := newLeaves[key]
+		if inOld && inNew && ov == nv {
+			continue
+		}
+		if !inOld {
+			ov = Absent
+		}
+		if !inNew {
+			nv = Absent
+		}
+		out = append(out, AuditEntry{Key: key, OldValue: ov, NewValue: nv})
+	}
+	return out, nil
+}
+
+// flatten records every leaf of m under its dot-separated path, the form agent config get
+// and set take. Lists are leaves, compared as a whole; an empty map is a leaf too, so
+// adding "providers: {}" still shows up.
+func flatten(prefix string, m map[string]any, out map[string]string) {
+	if len(m) == 0 && prefix != "" {
+		out[prefix] = "{}"
+		return
+	}
+	for k, v := range m {
+		p := k
+		if prefix != "" {
+			p = prefix + "." + k
+		}
+		if child, ok := v.(map[string]any); ok {
+			flatten(p, child, out)
+			continue
+		}
+		out[p] = formatValue(v)
+	}
+}
+
+func formatValue(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return v
+	case []any:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(b)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func diffUsers(oldPath, newPath string) ([]AuditEntry, error) {
+	names := func(p string) (map[string]string, error) {
+		list, err := users.NewUserStore(p).List()
+		if err != nil {
+			return nil, err
+		}
+		out := make(map[string]string, len(list))
+		for _, u := range list {
+			out[u.Username] = Present
+		}
+		return out, nil
+	}
+	before, err := names(oldPath)
+	if err != nil {
+		return nil, err
+	}
+	after, err := names(newPath)
+	if err != nil {
+		return nil, err
+	}
+	var out []AuditEntry
+	for _, name := range unionKeys(before, after) {
+		switch {
+		case before[name] == "":
+			out = append(out, AuditEntry{Key: name, OldValue: Absent, NewValue: Present})
+		case after[name] == "":
+			out = append(out, AuditEntry{Key: name, OldValue: Present, NewValue: Absent})
+		}
+	}
+	return out, nil
+}
+
+func unionKeys(a, b map[string]string) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func exists(p string) bool {
+	info, err := os.Stat(p)
+	return err == nil && info.Mode().IsRegular()
+}
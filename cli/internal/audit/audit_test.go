@@ -0,0 +1,125 @@
+package audit
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/backup"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/environment"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/secrets"
+)
+
+func writeFile(t *testing.T, path, data string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAuditConfigChanges(t *testing.T) {
+	set, root := t.TempDir(), t.TempDir()
+
+	writeFile(t, filepath.Join(set, ".env"), "# ARI\nASTERISK_HOST=pbx.old\nOPENAI_API_KEY=sk-old\nLOG_LEVEL=info\nGOOGLE_API_KEY=g\n")
+	writeFile(t, filepath.Join(root, ".env"), "ASTERISK_HOST=pbx.new\nexport OPENAI_API_KEY=sk-new\nLOG_LEVEL=info\nDEEPGRAM_API_KEY=\n")
+
+	writeFile(t, filepath.Join(set, "config", "ai-agent.yaml"), "default_provider: openai_realtime\nproviders:\n  openai_realtime:\n    voice: alloy\n    enabled: true\nvad:\n  modes: [a, b]\n")
+	writeFile(t, filepath.Join(root, "config", "ai-agent.yaml"), "default_provider: openai_realtime\nproviders:\n  openai_realtime:\n    voice: sage\n  deepgram: {}\nvad:\n  modes: [a]\n")
+
+	writeFile(t, filepath.Join(set, "config", "users.json"), `{"admin": {"username": "admin", "hashed_password": "x"}, "bob": {"username": "bob", "hashed_password": "y"}}`)
+	writeFile(t, filepath.Join(root, "config", "users.json"), `{"admin": {"username": "admin", "hashed_password": "changed"}, "carol": {"username": "carol", "hashed_password": "z"}}`)
+
+	writeFile(t, filepath.Join(set, "config", "contexts", "sales.yaml"), "name: sales\n")
+	writeFile(t, filepath.Join(root, "config", "contexts", "support.yaml"), "name: support\n")
+	writeFile(t, filepath.Join(root, "config", "contexts", ".deleted", "old.yaml.expires-x"), "name: old\n")
+
+	got, err := AuditConfigChanges(root, environment.Default, backup.BackupSetInfo{Path: set})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := backup.RedactedValue
+	want := []AuditEntry{
+		{".env", "ASTERISK_HOST", "pbx.old", "pbx.new"},
+		{".env", "DEEPGRAM_API_KEY", Absent, ""},
+		{".env", "GOOGLE_API_KEY", r, Absent},
+		{".env", "OPENAI_API_KEY", r, r},
+		{"config/ai-agent.yaml", "providers.deepgram", Absent, "{}"},
+		{"config/ai-agent.yaml", "providers.openai_realtime.enabled", "true", Absent},
+		{"config/ai-agent.yaml", "providers.openai_realtime.voice", "alloy", "sage"},
+		{"config/ai-agent.yaml", "vad.modes", `["a","b"]`, `["a"]`},
+		{"config/contexts/sales.yaml", "", Present, Absent},
+		{"config/contexts/support.yaml", "", Absent, Present},
+		{"config/users.json", "bob", Present, Absent},
+		{"config/users.json", "carol", Absent, Present},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("entries:\n got %v\nwant %v", got, want)
+	}
+}
+
+func TestAuditConfigChangesNamedEnvironment(t *testing.T) {
+	set, root := t.TempDir(), t.TempDir()
+	writeFile(t, filepath.Join(set, ".env.staging"), "ASTERISK_HOST=pbx.old\nOPENAI_API_KEY=sk-old\n")
+	writeFile(t, filepath.Join(root, ".env.staging"), "ASTERISK_HOST=pbx.new\nOPENAI_API_KEY=sk-new\n")
+	writeFile(t, filepath.Join(set, "config", "ai-agent.local.staging.yaml"), "vad:\n  enabled: true\n")
+	writeFile(t, filepath.Join(root, "config", "ai-agent.local.staging.yaml"), "vad:\n  enabled: false\n")
+	writeFile(t, filepath.Join(set, "config", "users.staging.json"), `{"bob": {"username": "bob", "hashed_password": "y"}}`)
+	writeFile(t, filepath.Join(root, "config", "users.staging.json"), `{"bob": {"username": "bob", "hashed_password": "z"}}`)
+	// The default environment's files are not part of the staging audit.
+	writeFile(t, filepath.Join(root, ".env"), "ASTERISK_HOST=pbx.default\n")
+
+	got, err := AuditConfigChanges(root, "staging", backup.BackupSetInfo{Path: set})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []AuditEntry{
+		{".env.staging", "ASTERISK_HOST", "pbx.old", "pbx.new"},
+		{".env.staging", "OPENAI_API_KEY", backup.RedactedValue, backup.RedactedValue},
+		{"config/ai-agent.local.staging.yaml", "vad.enabled", "true", "false"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("entries:\n got %v\nwant %v", got, want)
+	}
+}
+
+func TestAuditConfigChangesReportsParseErrors(t *testing.T) {
+	set, root := t.TempDir(), t.TempDir()
+	writeFile(t, filepath.Join(set, "config", "ai-agent.local.yaml"), "a: 1\n")
+	writeFile(t, filepath.Join(root, "config", "ai-agent.local.yaml"), "a: [\n")
+	if _, err := AuditConfigChanges(root, environment.Default, backup.BackupSetInfo{Path: set}); err == nil {
+		t.Fatal("expected a parse error")
+	}
+}
+
+func TestAuditConfigChangesKeepsEncryptedValues(t *testing.T) {
+	set, root := t.TempDir(), t.TempDir()
+	keyFile := filepath.Join(root, filepath.FromSlash(secrets.KeyFile))
+	if _, err := secrets.GenerateKeyFile(keyFile); err != nil {
+		t.Fatal(err)
+	}
+	for dir, password := range map[string]string{set: "old-s3cr3t", root: "new-s3cr3t"} {
+		path := filepath.Join(dir, "config", "ai-agent.yaml")
+		writeFile(t, path, "asterisk:\n  password: "+password+"\n")
+		if err := secrets.EncryptYAMLSecrets(path, keyFile, []string{"password"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := AuditConfigChanges(root, environment.Default, backup.BackupSetInfo{Path: set})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("entries = %v", got)
+	}
+	for _, v := range []string{got[0].OldValue, got[0].NewValue} {
+		if strings.Contains(v, "s3cr3t") || !secrets.IsEncryptedValue(v) {
+			t.Fatalf("audit printed %q, want the ENC[...] value as written", v)
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package backup
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ContentStoreDir is the content-addressed object store shared by backup sets, relative to
+// the repo root.
+const ContentStoreDir = ".agent/content-store"
+
+// ContentStore maps a file's sha256 (and permission bits) to a single object on disk, at
+// <dir>/<first two hex digits>/<sha256>-<mode>. Backup sets hard-link to these objects, so an
+// unchanged file costs one inode across every backup that contains it. Objects are never
+// modified in place; the mode is part of the key because hard links share permissions.
+type ContentStore struct {
+	Dir string
+}
+
+// NewContentStore returns a store rooted at dir, creating it if needed.
+func NewContentStore(dir string) (*ContentStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create content store %s: %w", dir, err)
+	}
+	return &ContentStore{Dir: dir}, nil
+}
+
+func (s *ContentStore) objectPath(sum string, mode fs.FileMode) string {
+	return filepath.Join(s.Dir, sum[:2], fmt.Sprintf("%s-%04o", sum, mode.Perm()))
+}
+
+// add stores a copy of src (hashing it in the same pass) and returns its object path.
+func (s *ContentStore) add(src string, mode fs.FileMode) (string, error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return "", err
+	}
+	defer in.Close()
+
+	tmp, err := os.CreateTemp(s.Dir, ".incoming.*")
+	if err != nil {
+		return "", err
+	}
+	tmpName := tmp.Name()
+	defer func() {
+		_ = os.Remove(tmpName)
+	}()
+	h := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(tmp, h), in); err != nil {
+		_ = tmp.Close()
+		return "", fmt.Errorf("failed to read %s: %w", src, err)
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+
+	obj := s.objectPath(hex.EncodeToString(h.Sum(nil)), mode)
+	if _, err := os.Stat(obj); err == nil {
+		return obj, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(obj), 0o755); err != nil {
+		return "", err
+	}
+	if err := os.Chmod(tmpName, mode.Perm()); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmpName, obj); err != nil {
+		return "", err
+	}
+	return obj, nil
+}
+
+// BackupWithDedup copies src (a file or directory tree) to dst, hard-linking each regular file
+// to its object in store instead of writing a new copy. When a link can't be made (different
+// filesystem, no hard-link support) the file is copied. Symlinks are skipped, as in copyDir.
+func BackupWithDedup(src, dst string, store *ContentStore) error {
+	return filepath.WalkDir(src, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if entry.IsDir() {
+			return os.MkdirAll(target, 0o755)
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		obj, err := store.add(path, info.Mode())
+		if err != nil {
+			return fmt.Errorf("failed to store %s: %w", path, err)
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return err
+		}
+		// Never write through an existing name: it may itself be a link to a store object.
+		if err := os.Remove(target); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		if err := os.Link(obj, target); err == nil {
+			return nil
+		}
+		return CopyFile(obj, target)
+	})
+}
+
+// CopyFile copies src to dst through a temp file and rename, so dst is always a new,
+// independent file even when src or the old dst is a hard link into a ContentStore. An
+// existing dst keeps its permissions; a new one takes src's.
+func CopyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	srcInfo, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	mode := srcInfo.Mode().Perm()
+	if st, err := os.Stat(dst); err == nil {
+		mode = st.Mode().Perm()
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(dst), "."+filepath.Base(dst)+".tmp.*")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+	defer func() {
+		_ = os.Remove(tmpName)
+	}()
+	if _, err := io.Copy(tmp, in); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpName, mode); err != nil {
+		return err
+	}
+	return os.Rename(tmpName, dst)
+}
+
+// PruneContentStore deletes objects that no file under roots references (by content and
+// mode), e.g. after old backup sets were pruned. It returns the number of objects removed.
+func PruneContentStore(store *ContentStore, roots ...string) (int, error) {
+	referenced := map[string]bool{}
+	for _, root := range roots {
+		err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				if os.IsNotExist(err) {
+					return nil
+				}
+				return err
+			}
+			if !entry.Type().IsRegular() {
+				return nil
+			}
+			info, err := entry.Info()
+			if err != nil {
+				return err
+			}
+			sum, err := fileSHA256(path)
+			if err != nil {
+				return err
+			}
+			referenced[filepath.Base(store.objectPath(sum, info.Mode()))] = true
+			return nil
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	removed := 0
+	err := filepath.WalkDir(store.Dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		name := entry.Name()
+		if strings.HasPrefix(name, ".incoming.") || referenced[name] {
+			return nil
+		}
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+		removed++
+		return nil
+	})
+	return removed, err
+}
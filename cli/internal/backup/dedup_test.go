@@ -0,0 +1,100 @@
+package backup
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBackupWithDedupLinksUnchangedFiles(t *testing.T) {
+	root := t.TempDir()
+	live := filepath.Join(root, "config", "contexts")
+	if err := os.MkdirAll(live, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for name, body := range map[string]string{"a.yaml": "a: 1\n", "b.yaml": "b: 2\n"} {
+		if err := os.WriteFile(filepath.Join(live, name), []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	store, err := NewContentStore(filepath.Join(root, ".agent", "content-store"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first := filepath.Join(root, "backups", "1", "contexts")
+	second := filepath.Join(root, "backups", "2", "contexts")
+	if err := BackupWithDedup(live, first, store); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(live, "b.yaml"), []byte("b: 3\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := BackupWithDedup(live, second, store); err != nil {
+		t.Fatal(err)
+	}
+
+	same := func(a, b string) bool {
+		sa, err1 := os.Stat(a)
+		sb, err2 := os.Stat(b)
+		return err1 == nil && err2 == nil && os.SameFile(sa, sb)
+	}
+	if !same(filepath.Join(first, "a.yaml"), filepath.Join(second, "a.yaml")) {
+		t.Fatal("unchanged a.yaml should be hard-linked across backups")
+	}
+	if same(filepath.Join(first, "b.yaml"), filepath.Join(second, "b.yaml")) {
+		t.Fatal("changed b.yaml must not share an inode")
+	}
+	if got, _ := os.ReadFile(filepath.Join(first, "b.yaml")); string(got) != "b: 2\n" {
+		t.Fatalf("first backup changed: %q", got)
+	}
+
+	// Restoring writes an independent copy: editing the restored file leaves the backups alone.
+	restored := filepath.Join(live, "a.yaml")
+	if err := CopyFile(filepath.Join(first, "a.yaml"), restored); err != nil {
+		t.Fatal(err)
+	}
+	if same(restored, filepath.Join(first, "a.yaml")) {
+		t.Fatal("restored file must not be a hard link into the store")
+	}
+	if err := os.WriteFile(restored, []byte("edited\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := os.ReadFile(filepath.Join(second, "a.yaml")); string(got) != "a: 1\n" {
+		t.Fatalf("backup modified through restored file: %q", got)
+	}
+
+	if err := os.RemoveAll(filepath.Join(root, "backups", "1")); err != nil {
+		t.Fatal(err)
+	}
+	removed, err := PruneContentStore(store, filepath.Join(root, "backups"))
+	if err != nil || removed != 1 {
+		t.Fatalf("PruneContentStore removed %d, %v; want the old b.yaml object", removed, err)
+	}
+}
+
+func TestContentStoreKeysByMode(t *testing.T) {
+	root := t.TempDir()
+	store, err := NewContentStore(filepath.Join(root, "store"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	public := filepath.Join(root, "public")
+	secret := filepath.Join(root, "secret")
+	if err := os.WriteFile(public, []byte("same"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(secret, []byte("same"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := BackupWithDedup(public, filepath.Join(root, "b", "public"), store); err != nil {
+		t.Fatal(err)
+	}
+	if err := BackupWithDedup(secret, filepath.Join(root, "b", "secret"), store); err != nil {
+		t.Fatal(err)
+	}
+	st, err := os.Stat(filepath.Join(root, "b", "secret"))
+	if err != nil || st.Mode().Perm() != 0o600 {
+		t.Fatalf("secret backup mode = %v, %v; want 0600", st.Mode().Perm(), err)
+	}
+}
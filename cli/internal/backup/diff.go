@@ -0,0 +1,193 @@
+package backup
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"unicode/utf8"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/maputil"
+)
+
+// Diff statuses reported by DiffBackupDirs.
+const (
+	DiffAdded    = "added"
+	DiffRemoved  = "removed"
+	DiffModified = "modified"
+)
+
+// FileDiff describes one file that differs between two backup directories.
+type FileDiff struct {
+	Path   string // slash-separated, relative to the backup directory
+	Status string // DiffAdded, DiffRemoved or DiffModified
+
+	// Unified is a unified diff for text files (empty for binary files or when
+	// the diff tool is unavailable).
+	Unified string
+	Binary  bool
+	SizeA   int64
+	SizeB   int64
+}
+
+// DiffBackupDirs compares backup directory a (older) with b (newer) and returns
+// the files that were added, removed or modified, sorted by path. The checksum
+// manifest is ignored.
+func DiffBackupDirs(a, b string) ([]FileDiff, error) {
+	filesA, err := listFiles(a)
+	if err != nil {
+		return nil, err
+	}
+	filesB, err := listFiles(b)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make(map[string]bool, len(filesA)+len(filesB))
+	for rel := range filesA {
+		paths[rel] = true
+	}
+	for rel := range filesB {
+		paths[rel] = true
+	}
+	var diffs []FileDiff
+	for _, rel := range maputil.SortedKeys(paths) {
+		sizeA, inA := filesA[rel]
+		sizeB, inB := filesB[rel]
+		pathA := filepath.Join(a, filepath.FromSlash(rel))
+		pathB := filepath.Join(b, filepath.FromSlash(rel))
+
+		d := FileDiff{Path: rel, SizeA: sizeA, SizeB: sizeB}
+		switch {
+		case !inA:
+			d.Status = DiffAdded
+		case !inB:
+			d.Status = DiffRemoved
+		default:
+			same, err := sameContent(pathA, pathB)
+			if err != nil {
+				return nil, err
+			}
+			if same {
+				continue
+			}
+			d.Status = DiffModified
+		}
+
+		binary, err := isBinaryFile(pathA, inA, pathB, inB)
+		if err != nil {
+			return nil, err
+		}
+		d.Binary = binary
+		if !binary {
+			if !inA {
+				pathA = os.DevNull
+			}
+			if !inB {
+				pathB = os.DevNull
+			}
+			d.Unified, _ = UnifiedDiff(pathA, pathB, "a/"+rel, "b/"+rel)
+		}
+		diffs = append(diffs, d)
+	}
+	return diffs, nil
+}
+
+// listFiles maps each regular file under dir (slash-separated relative path) to its size.
+func listFiles(dir string) (map[string]int64, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backup directory: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", dir)
+	}
+	files := map[string]int64{}
+	err = filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if rel == ManifestName || rel == MetadataName {
+			return nil
+		}
+		fi, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = fi.Size()
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk %s: %w", dir, err)
+	}
+	return files, nil
+}
+
+func sameContent(a, b string) (bool, error) {
+	da, err := os.ReadFile(a)
+	if err != nil {
+		return false, err
+	}
+	db, err := os.ReadFile(b)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(da, db), nil
+}
+
+// isBinaryFile reports whether either existing side looks binary (NUL bytes or invalid UTF-8).
+func isBinaryFile(a string, inA bool, b string, inB bool) (bool, error) {
+	for _, side := range []struct {
+		path   string
+		exists bool
+	}{{a, inA}, {b, inB}} {
+		if !side.exists {
+			continue
+		}
+		data, err := os.ReadFile(side.path)
+		if err != nil {
+			return false, err
+		}
+		if len(data) > 8000 {
+			data = data[:8000]
+		}
+		if bytes.IndexByte(data, 0) >= 0 || !utf8.Valid(data) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// UnifiedDiff returns `diff -u` output between a and b ("" when they are the same), or
+// `diff -ruN` when b is a directory. For files, labelA and labelB replace the paths in the
+// header when set. The error wraps exec.ErrNotFound when there is no diff on PATH.
+func UnifiedDiff(a, b, labelA, labelB string) (string, error) {
+	if _, err := exec.LookPath("diff"); err != nil {
+		return "", err
+	}
+	args := []string{"-u"}
+	if info, err := os.Stat(b); err == nil && info.IsDir() {
+		args = []string{"-ruN"}
+	} else if labelA != "" && labelB != "" {
+		args = append(args, "--label", labelA, "--label", labelB)
+	}
+	out, err := exec.Command("diff", append(args, a, b)...).CombinedOutput()
+	if err != nil {
+		// Exit status 1 means "files differ", which is the expected case.
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) || exitErr.ExitCode() != 1 {
+			return "", fmt.Errorf("diff failed: %w", err)
+		}
+	}
+	return string(out), nil
+}
@@ -0,0 +1,78 @@
+package backup
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDiffBackupDirs(t *testing.T) {
+	a, b := t.TempDir(), t.TempDir()
+	write := func(dir, rel, content string) {
+		t.Helper()
+		p := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(a, ".env", "A=1\n")
+	write(b, ".env", "A=2\n")
+	write(a, "config/ai-agent.yaml", "x: 1\n")
+	write(b, "config/ai-agent.yaml", "x: 1\n")
+	write(a, "config/contexts/old.yaml", "k: v\n")
+	write(b, "config/contexts/new.yaml", "k: v\n")
+	write(a, "blob.bin", "\x00\x01")
+	write(b, "blob.bin", "\x00\x01\x02")
+	write(a, ManifestName, "one")
+	write(b, ManifestName, "two")
+
+	diffs, err := DiffBackupDirs(a, b)
+	if err != nil {
+		t.Fatalf("DiffBackupDirs: %v", err)
+	}
+
+	got := map[string]FileDiff{}
+	for _, d := range diffs {
+		got[d.Path] = d
+	}
+	if len(got) != 4 {
+		t.Fatalf("expected 4 diffs, got %+v", diffs)
+	}
+	if got[".env"].Status != DiffModified {
+		t.Fatalf(".env: %+v", got[".env"])
+	}
+	if got["config/contexts/new.yaml"].Status != DiffAdded || got["config/contexts/old.yaml"].Status != DiffRemoved {
+		t.Fatalf("contexts: %+v", diffs)
+	}
+	if bin := got["blob.bin"]; !bin.Binary || bin.Unified != "" || bin.SizeA != 2 || bin.SizeB != 3 {
+		t.Fatalf("blob.bin: %+v", bin)
+	}
+	if u := got[".env"].Unified; u != "" && !strings.Contains(u, "+A=2") {
+		t.Fatalf("unexpected unified diff:\n%s", u)
+	}
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	if _, err := exec.LookPath("diff"); err != nil {
+		t.Skip("diff not on PATH")
+	}
+	a, b := t.TempDir(), t.TempDir()
+	writeFile(t, filepath.Join(a, "x.yaml"), "a: 1\n")
+	writeFile(t, filepath.Join(b, "x.yaml"), "a: 2\n")
+
+	out, err := UnifiedDiff(filepath.Join(a, "x.yaml"), filepath.Join(b, "x.yaml"), "a/x.yaml", "b/x.yaml")
+	if err != nil || !strings.HasPrefix(out, "--- a/x.yaml\n+++ b/x.yaml\n") || !strings.Contains(out, "+a: 2") {
+		t.Fatalf("file diff = %q, %v", out, err)
+	}
+	if out, err := UnifiedDiff(a, b, "", ""); err != nil || !strings.Contains(out, "+a: 2") {
+		t.Fatalf("directory diff = %q, %v", out, err)
+	}
+	if out, err := UnifiedDiff(filepath.Join(a, "x.yaml"), filepath.Join(a, "x.yaml"), "", ""); err != nil || out != "" {
+		t.Fatalf("same file = %q, %v", out, err)
+	}
+}
@@ -0,0 +1,350 @@
+package backup
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"filippo.io/age"
+)
+
+const (
+	// EncryptKeyEnv holds the age recipient (public key, "age1...") new backup sets are
+	// encrypted to. Unset means backups stay plaintext.
+	EncryptKeyEnv = "AGENT_BACKUP_ENCRYPT_KEY"
+	// IdentityFileEnv points at the age identity file used to decrypt backup sets on restore.
+	IdentityFileEnv = "AGENT_BACKUP_IDENTITY_FILE"
+
+	// EncryptedSuffix is appended to each file of an encrypted backup set.
+	EncryptedSuffix = ".age"
+	// ManifestSigName holds the hex HMAC-SHA256 of the manifest of an encrypted backup set,
+	// one line per secret key of the identity file it was signed with (see SignBackupManifest).
+	ManifestSigName = ManifestName + ".sig"
+
+	secretKeyPrefix = "AGE-SECRET-KEY-"
+)
+
+// ErrManifestSignature is returned when an encrypted backup's manifest does not match its
+// signature for the given identity (tampered manifest, unsigned set, or the wrong identity file).
+var ErrManifestSignature = errors.New("backup manifest signature mismatch")
+
+// IsEncrypted reports whether dir is a backup set written by EncryptBackupDir: it has a
+// manifest signature, or every file its manifest lists is an age file.
+func IsEncrypted(dir string) bool {
+	if _, err := os.Stat(filepath.Join(dir, ManifestSigName)); err == nil {
+		return true
+	}
+	rels, err := manifestEntries(dir)
+	if err != nil || len(rels) == 0 {
+		return false
+	}
+	for _, rel := range rels {
+		if !strings.HasSuffix(rel, EncryptedSuffix) {
+			return false
+		}
+	}
+	return true
+}
+
+// EncryptBackupDir encrypts every file of the backup set in dir to recipientKey in place
+// (<file> becomes <file>.age with the same mode, and the plaintext is removed) and rewrites
+// the manifest over the encrypted files. The recipient is public, so it cannot sign anything:
+// SignBackupManifest must follow with the identity of recipientKey, or DecryptBackupDir
+// rejects the set.
+func EncryptBackupDir(dir string, recipientKey string) error {
+	recipient, err := age.ParseX25519Recipient(strings.TrimSpace(recipientKey))
+	if err != nil {
+		return fmt.Errorf("invalid age recipient key: %w", err)
+	}
+	if IsEncrypted(dir) {
+		return fmt.Errorf("%s is already encrypted", dir)
+	}
+	files, err := backupFiles(dir)
+	if err != nil {
+		return err
+	}
+	for _, path := range files {
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		dst := path + EncryptedSuffix
+		if err := encryptFile(path, dst, recipient); err != nil {
+			_ = os.Remove(dst)
+			return fmt.Errorf("failed to encrypt %s: %w", path, err)
+		}
+		if err := os.Chmod(dst, info.Mode().Perm()); err != nil {
+			return err
+		}
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+	}
+	return WriteManifest(dir)
+}
+
+// SignBackupManifest signs the manifest of an encrypted set with keys derived from the secret
+// keys in identityFile, which should be the identity the set was encrypted to (see
+// CheckIdentity).
+func SignBackupManifest(dir string, identityFile string) error {
+	if identityFile == "" {
+		return fmt.Errorf("an age identity file (%s) is required to sign the backup manifest", IdentityFileEnv)
+	}
+	if !IsEncrypted(dir) {
+		return fmt.Errorf("%s is not encrypted", dir)
+	}
+	keys, err := identityKeys(identityFile)
+	if err != nil {
+		return err
+	}
+	return signManifest(dir, keys)
+}
+
+// DecryptBackupDir reverses EncryptBackupDir in place using the age identity in identityFile.
+// The manifest signature and checksums are verified before anything is decrypted. A set that
+// is not encrypted is left alone.
+func DecryptBackupDir(dir string, identityFile string) error {
+	if !IsEncrypted(dir) {
+		return nil
+	}
+	identities, err := loadIdentities(identityFile)
+	if err != nil {
+		return err
+	}
+	keys := make([]string, 0, len(identities))
+	ids := make([]age.Identity, 0, len(identities))
+	for _, id := range identities {
+		keys = append(keys, id.String())
+		ids = append(ids, id)
+	}
+	if err := verifyManifestSig(dir, keys); err != nil {
+		return err
+	}
+	if err := VerifyManifest(dir); err != nil {
+		return err
+	}
+	rels, err := manifestEntries(dir)
+	if err != nil {
+		return err
+	}
+	for _, rel := range rels {
+		if !strings.HasSuffix(rel, EncryptedSuffix) {
+			continue
+		}
+		src := filepath.Join(dir, filepath.FromSlash(rel))
+		dst := strings.TrimSuffix(src, EncryptedSuffix)
+		info, err := os.Stat(src)
+		if err != nil {
+			return err
+		}
+		if err := decryptFile(src, dst, ids); err != nil {
+			_ = os.Remove(dst)
+			return fmt.Errorf("failed to decrypt %s: %w", rel, err)
+		}
+		if err := os.Chmod(dst, info.Mode().Perm()); err != nil {
+			return err
+		}
+		if err := os.Remove(src); err != nil {
+			return err
+		}
+	}
+	if err := os.Remove(filepath.Join(dir, ManifestSigName)); err != nil {
+		return err
+	}
+	return WriteManifest(dir)
+}
+
+// CheckIdentity returns an error unless identityFile holds an age secret key and is the
+// identity of recipientKey, so sets encrypted to recipientKey can be signed and restored.
+func CheckIdentity(identityFile, recipientKey string) error {
+	identities, err := loadIdentities(identityFile)
+	if err != nil {
+		return err
+	}
+	recipientKey = strings.TrimSpace(recipientKey)
+	for _, id := range identities {
+		if id.Recipient().String() == recipientKey {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s is not the identity of %s; backups encrypted to it could not be restored", identityFile, recipientKey)
+}
+
+// loadIdentities parses the AGE-SECRET-KEY-1... identities of an age identity file.
+func loadIdentities(identityFile string) ([]*age.X25519Identity, error) {
+	f, err := os.Open(identityFile)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	parsed, err := age.ParseIdentities(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", identityFile, err)
+	}
+	var identities []*age.X25519Identity
+	for _, id := range parsed {
+		if x, ok := id.(*age.X25519Identity); ok {
+			identities = append(identities, x)
+		}
+	}
+	if len(identities) == 0 {
+		return nil, fmt.Errorf("%s holds no %s... identity", identityFile, secretKeyPrefix)
+	}
+	return identities, nil
+}
+
+// identityKeys returns the secret keys (AGE-SECRET-KEY-1...) of an age identity file.
+func identityKeys(identityFile string) ([]string, error) {
+	identities, err := loadIdentities(identityFile)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(identities))
+	for _, id := range identities {
+		keys = append(keys, id.String())
+	}
+	return keys, nil
+}
+
+// manifestKey derives the HMAC key for the manifest signature from an age secret key
+// (HKDF-SHA256, RFC 5869, one output block).
+func manifestKey(secretKey string) []byte {
+	extract := hmac.New(sha256.New, []byte("agent-backup-manifest-v2"))
+	extract.Write([]byte(secretKey))
+	expand := hmac.New(sha256.New, extract.Sum(nil))
+	expand.Write([]byte(ManifestName))
+	expand.Write([]byte{1})
+	return expand.Sum(nil)
+}
+
+func manifestMAC(dir, secretKey string) ([]byte, error) {
+	data, err := os.ReadFile(filepath.Join(dir, ManifestName))
+	if err != nil {
+		return nil, err
+	}
+	mac := hmac.New(sha256.New, manifestKey(secretKey))
+	mac.Write(data)
+	return mac.Sum(nil), nil
+}
+
+// signManifest writes one signature line per key, so any identity file that still holds one
+// of them (after adding a new key, say) verifies the set.
+func signManifest(dir string, keys []string) error {
+	var b strings.Builder
+	for _, k := range keys {
+		sum, err := manifestMAC(dir, k)
+		if err != nil {
+			return err
+		}
+		b.WriteString(hex.EncodeToString(sum) + "\n")
+	}
+	if err := os.WriteFile(filepath.Join(dir, ManifestSigName), []byte(b.String()), 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", ManifestSigName, err)
+	}
+	return nil
+}
+
+// verifyManifestSig accepts the manifest if a signature line matches one of keys.
+func verifyManifestSig(dir string, keys []string) error {
+	raw, err := os.ReadFile(filepath.Join(dir, ManifestSigName))
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("%w: the set is not signed", ErrManifestSignature)
+	}
+	if err != nil {
+		return err
+	}
+	var sigs [][]byte
+	for _, line := range strings.Fields(string(raw)) {
+		sig, err := hex.DecodeString(line)
+		if err != nil {
+			return fmt.Errorf("%w: malformed %s", ErrManifestSignature, ManifestSigName)
+		}
+		sigs = append(sigs, sig)
+	}
+	for _, k := range keys {
+		got, err := manifestMAC(dir, k)
+		if err != nil {
+			return err
+		}
+		for _, want := range sigs {
+			if hmac.Equal(got, want) {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("%w (wrong identity file, or the manifest was modified)", ErrManifestSignature)
+}
+
+// backupFiles lists the regular files of a backup set, excluding the manifest, signature and
+// metadata.
+func backupFiles(dir string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		if rel, _ := filepath.Rel(dir, path); rel == ManifestName || rel == ManifestSigName || rel == MetadataName {
+			return nil
+		}
+		files = append(files, path)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk %s: %w", dir, err)
+	}
+	return files, nil
+}
+
+func encryptFile(src, dst string, recipient age.Recipient) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	w, err := age.Encrypt(out, recipient)
+	if err == nil {
+		_, err = io.Copy(w, in)
+		if closeErr := w.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func decryptFile(src, dst string, identities []age.Identity) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	r, err := age.Decrypt(in, identities...)
+	if err != nil {
+		return err
+	}
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(out, r)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
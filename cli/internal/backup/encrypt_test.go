@@ -0,0 +1,163 @@
+package backup
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"filippo.io/age"
+)
+
+// newIdentity writes a fresh age identity file, as age-keygen does, and returns its path and
+// recipient.
+func newIdentity(t *testing.T) (identityFile, recipient string) {
+	t.Helper()
+	id, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	identityFile = filepath.Join(t.TempDir(), "identity.txt")
+	writeFile(t, identityFile, "# public key: "+id.Recipient().String()+"\n"+id.String()+"\n")
+	return identityFile, id.Recipient().String()
+}
+
+func newEncryptedSet(t *testing.T) (dir, identity string) {
+	t.Helper()
+	dir = t.TempDir()
+	writeFile(t, filepath.Join(dir, ".env"), "ASTERISK_ARI_PASSWORD=hunter2\n")
+	writeFile(t, filepath.Join(dir, "config", "ai-agent.yaml"), "a: 1\n")
+	if err := os.Chmod(filepath.Join(dir, ".env"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteManifest(dir); err != nil {
+		t.Fatal(err)
+	}
+	identity, recipient := newIdentity(t)
+	if err := EncryptBackupDir(dir, recipient); err != nil {
+		t.Fatal(err)
+	}
+	if err := SignBackupManifest(dir, identity); err != nil {
+		t.Fatal(err)
+	}
+	return dir, identity
+}
+
+func TestEncryptBackupDirRoundTrip(t *testing.T) {
+	dir, identity := newEncryptedSet(t)
+
+	if !IsEncrypted(dir) {
+		t.Fatal("IsEncrypted = false after EncryptBackupDir")
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".env")); !os.IsNotExist(err) {
+		t.Fatalf("plaintext .env left behind: %v", err)
+	}
+	if err := VerifyManifest(dir); err != nil {
+		t.Fatalf("manifest over encrypted files: %v", err)
+	}
+
+	if err := DecryptBackupDir(dir, identity); err != nil {
+		t.Fatal(err)
+	}
+	if IsEncrypted(dir) {
+		t.Fatal("signature left after decrypt")
+	}
+	if got, _ := os.ReadFile(filepath.Join(dir, ".env")); string(got) != "ASTERISK_ARI_PASSWORD=hunter2\n" {
+		t.Fatalf(".env = %q", got)
+	}
+	if st, _ := os.Stat(filepath.Join(dir, ".env")); st.Mode().Perm() != 0o600 {
+		t.Fatalf(".env mode = %v, want 0600", st.Mode().Perm())
+	}
+	if err := VerifyManifest(dir); err != nil {
+		t.Fatalf("manifest after decrypt: %v", err)
+	}
+}
+
+func TestDecryptBackupDirRejectsTamperedManifest(t *testing.T) {
+	dir, identity := newEncryptedSet(t)
+
+	// Swap a file and fix up its checksum: VerifyManifest alone would now pass.
+	env := filepath.Join(dir, ".env"+EncryptedSuffix)
+	writeFile(t, env, "age1attacker\nxyz")
+	if err := WriteManifest(dir); err != nil {
+		t.Fatal(err)
+	}
+	err := DecryptBackupDir(dir, identity)
+	if !errors.Is(err, ErrManifestSignature) {
+		t.Fatalf("err = %v, want ErrManifestSignature", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".env")); !os.IsNotExist(err) {
+		t.Fatal("decryption started before the signature was verified")
+	}
+}
+
+func TestDecryptBackupDirWrongIdentity(t *testing.T) {
+	dir, _ := newEncryptedSet(t)
+	other, _ := newIdentity(t)
+	if err := DecryptBackupDir(dir, other); !errors.Is(err, ErrManifestSignature) {
+		t.Fatalf("err = %v, want ErrManifestSignature", err)
+	}
+}
+
+func TestDecryptBackupDirRejectsSignatureFromRecipient(t *testing.T) {
+	dir, identity := newEncryptedSet(t)
+	data, err := os.ReadFile(identity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recipient, _, _ := strings.Cut(strings.TrimPrefix(string(data), "# public key: "), "\n")
+	// Everything public about the set is known to an attacker; re-signing with it must fail.
+	env := filepath.Join(dir, ".env"+EncryptedSuffix)
+	writeFile(t, env, "xyz")
+	if err := WriteManifest(dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := signManifest(dir, []string{recipient}); err != nil {
+		t.Fatal(err)
+	}
+	if err := DecryptBackupDir(dir, identity); !errors.Is(err, ErrManifestSignature) {
+		t.Fatalf("err = %v, want ErrManifestSignature", err)
+	}
+}
+
+func TestDecryptBackupDirRejectsUnsignedSet(t *testing.T) {
+	identity, recipient := newIdentity(t)
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, ".env"), "A=1\n")
+	if err := EncryptBackupDir(dir, recipient); err != nil {
+		t.Fatal(err)
+	}
+	if !IsEncrypted(dir) {
+		t.Fatal("IsEncrypted = false for an unsigned encrypted set")
+	}
+	if err := DecryptBackupDir(dir, identity); !errors.Is(err, ErrManifestSignature) {
+		t.Fatalf("err = %v, want ErrManifestSignature", err)
+	}
+}
+
+func TestCheckIdentity(t *testing.T) {
+	identity, recipient := newIdentity(t)
+	if err := CheckIdentity(identity, recipient); err != nil {
+		t.Fatal(err)
+	}
+	_, other := newIdentity(t)
+	if err := CheckIdentity(identity, other); err == nil {
+		t.Fatal("expected an error for an identity of another recipient")
+	}
+	public := filepath.Join(t.TempDir(), "public.txt")
+	writeFile(t, public, "# public key: "+recipient+"\n")
+	if err := CheckIdentity(public, recipient); err == nil {
+		t.Fatal("expected an error for an identity file without a secret key")
+	}
+}
+
+func writeFile(t *testing.T, path, data string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,129 @@
+package backup
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ExportHeaderName is the version header written at the root of a config export archive.
+const ExportHeaderName = "agent-export.json"
+
+// ExportFormatVersion is bumped whenever the archive layout changes incompatibly.
+const ExportFormatVersion = 1
+
+// RedactedValue replaces secret values in a redacted .env export.
+const RedactedValue = "<redacted>"
+
+// ExportHeader describes a config export archive.
+type ExportHeader struct {
+	Format     int       `json:"format"`
+	CLIVersion string    `json:"cli_version"`
+	CreatedAt  time.Time `json:"created_at"`
+	Redacted   bool      `json:"redacted"`
+}
+
+// WriteExportHeader writes h to dir/ExportHeaderName.
+func WriteExportHeader(dir string, h ExportHeader) error {
+	data, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, ExportHeaderName), append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", ExportHeaderName, err)
+	}
+	return nil
+}
+
+// ReadExportHeader reads dir/ExportHeaderName and rejects archives written by a newer format.
+func ReadExportHeader(dir string) (ExportHeader, error) {
+	var h ExportHeader
+	data, err := os.ReadFile(filepath.Join(dir, ExportHeaderName))
+	if err != nil {
+		return h, fmt.Errorf("not a config export (missing %s): %w", ExportHeaderName, err)
+	}
+	if err := json.Unmarshal(data, &h); err != nil {
+		return h, fmt.Errorf("invalid %s: %w", ExportHeaderName, err)
+	}
+	if h.Format < 1 {
+		return h, fmt.Errorf("invalid %s: missing format version", ExportHeaderName)
+	}
+	if h.Format > ExportFormatVersion {
+		return h, fmt.Errorf("export format %d is newer than this CLI supports (%d); upgrade agent first", h.Format, ExportFormatVersion)
+	}
+	return h, nil
+}
+
+// IsSecretEnvKey reports whether an .env key holds a credential (API keys, passwords, tokens).
+func IsSecretEnvKey(key string) bool {
+	key = strings.ToUpper(key)
+	for _, marker := range []string{"KEY", "SECRET", "PASSWORD", "TOKEN"} {
+		if strings.Contains(key, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactEnv replaces the value of every non-empty secret key with RedactedValue, keeping
+// comments, blank lines and key order intact.
+func RedactEnv(data []byte) []byte {
+	lines := strings.Split(string(data), "\n")
+	for i, line := range lines {
+		prefix, key, value, ok := splitEnvLine(line)
+		if !ok || !IsSecretEnvKey(key) || strings.TrimSpace(value) == "" {
+			continue
+		}
+		lines[i] = prefix + key + "=" + RedactedValue
+	}
+	return []byte(strings.Join(lines, "\n"))
+}
+
+// RestoreRedactedEnv fills RedactedValue placeholders in archived from the matching lines of
+// live, so importing a redacted export keeps the host's existing secrets. Keys with no live
+// value are left empty and returned.
+func RestoreRedactedEnv(archived, live []byte) ([]byte, []string) {
+	liveLines := map[string]string{}
+	for _, line := range strings.Split(string(live), "\n") {
+		if _, key, _, ok := splitEnvLine(line); ok {
+			liveLines[key] = line
+		}
+	}
+
+	var missing []string
+	lines := strings.Split(string(archived), "\n")
+	for i, line := range lines {
+		prefix, key, value, ok := splitEnvLine(line)
+		if !ok || strings.TrimSpace(value) != RedactedValue {
+			continue
+		}
+		if liveLine, found := liveLines[key]; found {
+			lines[i] = liveLine
+			continue
+		}
+		lines[i] = prefix + key + "="
+		missing = append(missing, key)
+	}
+	return []byte(strings.Join(lines, "\n")), missing
+}
+
+// splitEnvLine splits "[export ]KEY=value" lines; comments and blank lines return ok=false.
+func splitEnvLine(line string) (prefix, key, value string, ok bool) {
+	trimmed := strings.TrimSpace(line)
+	if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+		return "", "", "", false
+	}
+	if strings.HasPrefix(trimmed, "export ") {
+		prefix = "export "
+		trimmed = strings.TrimSpace(strings.TrimPrefix(trimmed, "export "))
+	}
+	key, value, ok = strings.Cut(trimmed, "=")
+	key = strings.TrimSpace(key)
+	if !ok || key == "" {
+		return "", "", "", false
+	}
+	return prefix, key, value, true
+}
@@ -0,0 +1,53 @@
+package backup
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExportHeaderRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	want := ExportHeader{Format: ExportFormatVersion, CLIVersion: "v6.3.0", CreatedAt: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC), Redacted: true}
+	if err := WriteExportHeader(dir, want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadExportHeader(dir)
+	if err != nil {
+		t.Fatalf("ReadExportHeader: %v", err)
+	}
+	if got != want {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, ExportHeaderName), []byte(`{"format": 99}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadExportHeader(dir); err == nil || !strings.Contains(err.Error(), "newer") {
+		t.Fatalf("expected newer-format error, got %v", err)
+	}
+	if _, err := ReadExportHeader(t.TempDir()); err == nil {
+		t.Fatal("expected error for missing header")
+	}
+}
+
+func TestRedactEnvRoundTrip(t *testing.T) {
+	env := "# creds\nASTERISK_HOST=127.0.0.1\nASTERISK_ARI_PASSWORD=secret\nexport OPENAI_API_KEY=sk-1\nDEEPGRAM_API_KEY=\nGOOGLE_API_KEY=g-1\n"
+	redacted := string(RedactEnv([]byte(env)))
+	want := "# creds\nASTERISK_HOST=127.0.0.1\nASTERISK_ARI_PASSWORD=" + RedactedValue + "\nexport OPENAI_API_KEY=" + RedactedValue + "\nDEEPGRAM_API_KEY=\nGOOGLE_API_KEY=" + RedactedValue + "\n"
+	if redacted != want {
+		t.Fatalf("RedactEnv:\n%s\nwant:\n%s", redacted, want)
+	}
+
+	live := "ASTERISK_ARI_PASSWORD=other\nexport OPENAI_API_KEY=sk-live\n"
+	restored, missing := RestoreRedactedEnv([]byte(redacted), []byte(live))
+	want = "# creds\nASTERISK_HOST=127.0.0.1\nASTERISK_ARI_PASSWORD=other\nexport OPENAI_API_KEY=sk-live\nDEEPGRAM_API_KEY=\nGOOGLE_API_KEY=\n"
+	if string(restored) != want {
+		t.Fatalf("RestoreRedactedEnv:\n%s\nwant:\n%s", restored, want)
+	}
+	if len(missing) != 1 || missing[0] != "GOOGLE_API_KEY" {
+		t.Fatalf("missing = %v", missing)
+	}
+}
@@ -0,0 +1,322 @@
+package backup
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/maputil"
+)
+
+// IncrementalEnv makes agent backup create take incremental sets by default when true.
+const IncrementalEnv = "AGENT_BACKUP_INCREMENTAL"
+
+// DeltaManifestName marks an incremental backup set and records how to rebuild it.
+const DeltaManifestName = "delta-manifest.json"
+
+// MaxDeltaChain is how many incremental sets may follow a full one; callers should take a
+// full backup once ChainLength reaches it, so a restore never replays a long chain.
+const MaxDeltaChain = 10
+
+// DeltaManifest describes an incremental backup set. The set directory holds only the Added
+// and Modified files; Unchanged files are taken from Base, the sibling set it was computed
+// against (itself full or incremental). Files left out of all three lists were removed.
+type DeltaManifest struct {
+	Base      string   `json:"base"`
+	Added     []string `json:"added"`
+	Modified  []string `json:"modified"`
+	Unchanged []string `json:"unchanged"`
+	// Files maps every file of the reconstructed set to its SHA-256, so the next incremental
+	// backup and restores can compare against it without rebuilding the chain.
+	Files map[string]string `json:"files"`
+}
+
+// IsIncremental reports whether dir is an incremental backup set.
+func IsIncremental(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, DeltaManifestName))
+	return err == nil
+}
+
+// ReadDeltaManifest reads dir's DeltaManifestName. The error wraps fs.ErrNotExist for a full
+// set.
+func ReadDeltaManifest(dir string) (*DeltaManifest, error) {
+	b, err := os.ReadFile(filepath.Join(dir, DeltaManifestName))
+	if err != nil {
+		return nil, err
+	}
+	var m DeltaManifest
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Join(dir, DeltaManifestName), err)
+	}
+	if m.Base == "" || m.Base != filepath.Base(m.Base) || m.Base == "." || m.Base == ".." {
+		return nil, fmt.Errorf("%s: invalid base %q", filepath.Join(dir, DeltaManifestName), m.Base)
+	}
+	for _, rel := range append(append(append([]string(nil), m.Added...), m.Modified...), m.Unchanged...) {
+		if !localRel(rel) {
+			return nil, fmt.Errorf("%s: path %q escapes backup directory", filepath.Join(dir, DeltaManifestName), rel)
+		}
+	}
+	return &m, nil
+}
+
+// IncrementalBackup snapshots the files under root into targetDir, copying only those whose
+// SHA-256 differs from baselineDir (a full or incremental set next to targetDir), and writes a
+// DeltaManifest listing the added, modified and unchanged files. paths limits the snapshot to
+// these files or directories relative to root (all of root when empty); missing ones are
+// skipped. It returns the copied files, relative to root with forward slashes.
+func IncrementalBackup(root, baselineDir, targetDir string, paths ...string) ([]string, error) {
+	if filepath.Clean(filepath.Dir(baselineDir)) != filepath.Clean(filepath.Dir(targetDir)) {
+		return nil, fmt.Errorf("baseline %s must be in the same directory as %s", baselineDir, targetDir)
+	}
+	base, err := setHashes(baselineDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read baseline %s: %w", baselineDir, err)
+	}
+	current, err := sourceHashes(root, paths)
+	if err != nil {
+		return nil, err
+	}
+
+	m := DeltaManifest{Base: filepath.Base(baselineDir), Files: current}
+	for _, rel := range maputil.SortedKeys(current) {
+		switch old, ok := base[rel]; {
+		case !ok:
+			m.Added = append(m.Added, rel)
+		case old != current[rel]:
+			m.Modified = append(m.Modified, rel)
+		default:
+			m.Unchanged = append(m.Unchanged, rel)
+		}
+	}
+
+	copied := append(append([]string{}, m.Added...), m.Modified...)
+	slices.Sort(copied)
+	for _, rel := range copied {
+		dst := filepath.Join(targetDir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+			return nil, err
+		}
+		if err := CopyFile(filepath.Join(root, filepath.FromSlash(rel)), dst); err != nil {
+			return nil, fmt.Errorf("failed to back up %s: %w", rel, err)
+		}
+	}
+
+	if m.Added == nil {
+		m.Added = []string{}
+	}
+	if m.Modified == nil {
+		m.Modified = []string{}
+	}
+	if m.Unchanged == nil {
+		m.Unchanged = []string{}
+	}
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(targetDir, 0o755); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(targetDir, DeltaManifestName), append(b, '\n'), 0o644); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", DeltaManifestName, err)
+	}
+	return copied, nil
+}
+
+// BackupChain returns the sets needed to rebuild dir: the nearest full set first, then each
+// incremental set in the order it was taken, ending with dir. A full dir is its own chain.
+func BackupChain(dir string) ([]string, error) {
+	chain := []string{dir}
+	seen := map[string]bool{filepath.Clean(dir): true}
+	for cur := dir; ; {
+		m, err := ReadDeltaManifest(cur)
+		if errors.Is(err, fs.ErrNotExist) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		base := filepath.Join(filepath.Dir(cur), m.Base)
+		if seen[filepath.Clean(base)] {
+			return nil, fmt.Errorf("backup %s: base chain loops back to %s", dir, m.Base)
+		}
+		if info, err := os.Stat(base); err != nil || !info.IsDir() {
+			return nil, fmt.Errorf("backup %s: base set %s is missing (pruned or deleted)", cur, m.Base)
+		}
+		seen[filepath.Clean(base)] = true
+		chain = append(chain, base)
+		cur = base
+	}
+	slices.Reverse(chain)
+	return chain, nil
+}
+
+// ChainLength returns how many incremental sets dir is away from its full set (0 for a
+// full set).
+func ChainLength(dir string) (int, error) {
+	chain, err := BackupChain(dir)
+	if err != nil {
+		return 0, err
+	}
+	return len(chain) - 1, nil
+}
+
+// MaterializeBackup rebuilds the full contents of the backup set dir in dst, which must not
+// exist or be empty: it copies the nearest full set, then applies each incremental set in
+// order (keeping its unchanged files, taking its added and modified ones, dropping the rest).
+// Every rebuilt file is checked against the hashes in dir's DeltaManifest, and a checksum
+// manifest is written for the result. A full dir is simply copied.
+func MaterializeBackup(dir, dst string) error {
+	chain, err := BackupChain(dir)
+	if err != nil {
+		return err
+	}
+	// state maps each file of the set being rebuilt to the backup file holding its content.
+	state := map[string]string{}
+	full, err := setFiles(chain[0])
+	if err != nil {
+		return err
+	}
+	for _, rel := range full {
+		state[rel] = filepath.Join(chain[0], filepath.FromSlash(rel))
+	}
+	var last *DeltaManifest
+	for _, set := range chain[1:] {
+		m, err := ReadDeltaManifest(set)
+		if err != nil {
+			return err
+		}
+		next := map[string]string{}
+		for _, rel := range m.Unchanged {
+			src, ok := state[rel]
+			if !ok {
+				return fmt.Errorf("backup %s: unchanged file %s is not in base %s", set, rel, m.Base)
+			}
+			next[rel] = src
+		}
+		for _, rel := range append(append([]string(nil), m.Added...), m.Modified...) {
+			next[rel] = filepath.Join(set, filepath.FromSlash(rel))
+		}
+		state, last = next, m
+	}
+
+	for _, rel := range maputil.SortedKeys(state) {
+		out := filepath.Join(dst, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(out), 0o755); err != nil {
+			return err
+		}
+		if err := CopyFile(state[rel], out); err != nil {
+			return fmt.Errorf("failed to restore %s from %s: %w", rel, dir, err)
+		}
+		if last == nil {
+			continue
+		}
+		sum, err := fileSHA256(out)
+		if err != nil {
+			return err
+		}
+		if want := last.Files[rel]; !strings.EqualFold(sum, want) {
+			return fmt.Errorf("backup %s: rebuilt %s does not match its recorded checksum", dir, rel)
+		}
+	}
+	if err := os.MkdirAll(dst, 0o755); err != nil {
+		return err
+	}
+	return WriteManifest(dst)
+}
+
+// setHashes returns the SHA-256 of every file of the backup set in dir, from its DeltaManifest
+// for an incremental set and by hashing the files of a full one.
+func setHashes(dir string) (map[string]string, error) {
+	m, err := ReadDeltaManifest(dir)
+	if err == nil {
+		return m.Files, nil
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	if IsEncrypted(dir) {
+		return nil, errors.New("encrypted backup sets cannot be a baseline")
+	}
+	rels, err := setFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	sums := make(map[string]string, len(rels))
+	for _, rel := range rels {
+		if sums[rel], err = fileSHA256(filepath.Join(dir, filepath.FromSlash(rel))); err != nil {
+			return nil, err
+		}
+	}
+	return sums, nil
+}
+
+// setFiles lists the backed-up files of a set: every regular file except the manifests.
+func setFiles(dir string) ([]string, error) {
+	var rels []string
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		switch rel {
+		case ManifestName, ManifestSigName, DeltaManifestName, MetadataName:
+			return nil
+		}
+		rels = append(rels, filepath.ToSlash(rel))
+		return nil
+	})
+	return rels, err
+}
+
+// sourceHashes hashes the regular files under root, limited to paths when given.
+func sourceHashes(root string, paths []string) (map[string]string, error) {
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+	sums := map[string]string{}
+	for _, p := range paths {
+		start := filepath.Join(root, p)
+		if _, err := os.Stat(start); errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		err := filepath.WalkDir(start, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !entry.Type().IsRegular() {
+				return nil
+			}
+			rel, err := filepath.Rel(root, path)
+			if err != nil {
+				return err
+			}
+			sum, err := fileSHA256(path)
+			if err != nil {
+				return err
+			}
+			sums[filepath.ToSlash(rel)] = sum
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", start, err)
+		}
+	}
+	return sums, nil
+}
+
+func localRel(rel string) bool {
+	clean := filepath.Clean(filepath.FromSlash(rel))
+	return rel != "" && !filepath.IsAbs(clean) && clean != ".." && !strings.HasPrefix(clean, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,162 @@
+package backup
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// readTree returns the files under dir (manifest excluded) and their contents.
+func readTree(t *testing.T, dir string) map[string]string {
+	t.Helper()
+	rels, err := setFiles(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := map[string]string{}
+	for _, rel := range rels {
+		b, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(rel)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		out[rel] = string(b)
+	}
+	return out
+}
+
+func TestIncrementalBackupChainRestoresEachSet(t *testing.T) {
+	root := t.TempDir()
+	sets := t.TempDir()
+	writeFile(t, filepath.Join(root, ".env"), "A=1\n")
+	writeFile(t, filepath.Join(root, "config/contexts/sales.yaml"), "name: sales\n")
+	writeFile(t, filepath.Join(root, "config/contexts/support.yaml"), "name: support\n")
+	writeFile(t, filepath.Join(root, "ignored.txt"), "not backed up\n")
+	paths := []string{".env", "config/contexts", "config/users.json"}
+
+	full := filepath.Join(sets, "20250101_000000")
+	for _, rel := range []string{".env", "config/contexts/sales.yaml", "config/contexts/support.yaml"} {
+		writeFile(t, filepath.Join(full, rel), "")
+		if err := CopyFile(filepath.Join(root, rel), filepath.Join(full, rel)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := WriteManifest(full); err != nil {
+		t.Fatal(err)
+	}
+
+	// First delta: one file modified, one added, one removed.
+	writeFile(t, filepath.Join(root, "config/contexts/sales.yaml"), "name: sales\nvoice: alloy\n")
+	writeFile(t, filepath.Join(root, "config/contexts/billing.yaml"), "name: billing\n")
+	os.Remove(filepath.Join(root, "config/contexts/support.yaml"))
+	d1 := filepath.Join(sets, "20250102_000000")
+	copied, err := IncrementalBackup(root, full, d1, paths...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"config/contexts/billing.yaml", "config/contexts/sales.yaml"}; !slices.Equal(copied, want) {
+		t.Fatalf("copied = %v, want %v", copied, want)
+	}
+	m, err := ReadDeltaManifest(d1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Base != "20250101_000000" || !slices.Equal(m.Added, []string{"config/contexts/billing.yaml"}) ||
+		!slices.Equal(m.Modified, []string{"config/contexts/sales.yaml"}) || !slices.Equal(m.Unchanged, []string{".env"}) {
+		t.Fatalf("delta manifest = %+v", m)
+	}
+	if _, err := os.Stat(filepath.Join(d1, ".env")); !os.IsNotExist(err) {
+		t.Fatal("unchanged .env was copied into the delta")
+	}
+	want1 := readTree(t, root)
+	delete(want1, "ignored.txt")
+
+	// Second delta on top of the first: only .env changes.
+	writeFile(t, filepath.Join(root, ".env"), "A=2\n")
+	d2 := filepath.Join(sets, "20250103_000000")
+	if copied, err := IncrementalBackup(root, d1, d2, paths...); err != nil || !slices.Equal(copied, []string{".env"}) {
+		t.Fatalf("copied = %v, err = %v", copied, err)
+	}
+	want2 := readTree(t, root)
+	delete(want2, "ignored.txt")
+
+	chain, err := BackupChain(d2)
+	if err != nil || !slices.Equal(chain, []string{full, d1, d2}) {
+		t.Fatalf("chain = %v, err = %v", chain, err)
+	}
+	if n, _ := ChainLength(d2); n != 2 {
+		t.Fatalf("ChainLength = %d", n)
+	}
+
+	for dir, want := range map[string]map[string]string{d1: want1, d2: want2} {
+		out := filepath.Join(t.TempDir(), "restored")
+		if err := MaterializeBackup(dir, out); err != nil {
+			t.Fatal(err)
+		}
+		if got := readTree(t, out); !maps.Equal(got, want) {
+			t.Fatalf("%s restored as %v, want %v", filepath.Base(dir), got, want)
+		}
+		if err := VerifyManifest(out); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// A corrupted delta file is caught against the recorded checksum.
+	writeFile(t, filepath.Join(d1, "config/contexts/billing.yaml"), "name: tampered\n")
+	if err := MaterializeBackup(d2, filepath.Join(t.TempDir(), "x")); err == nil || !strings.Contains(err.Error(), "billing.yaml") {
+		t.Fatalf("err = %v, want checksum error for billing.yaml", err)
+	}
+
+	// Without its base the chain cannot be rebuilt.
+	os.RemoveAll(full)
+	if _, err := BackupChain(d2); err == nil || !strings.Contains(err.Error(), "missing") {
+		t.Fatalf("err = %v, want missing base", err)
+	}
+}
+
+func TestReadDeltaManifestRejectsEscapingPaths(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, DeltaManifestName), `{"base":"20250101_000000","added":["../../etc/passwd"],"modified":[],"unchanged":[],"files":{}}`)
+	if _, err := ReadDeltaManifest(dir); err == nil {
+		t.Fatal("expected an error for ../ path")
+	}
+	writeFile(t, filepath.Join(dir, DeltaManifestName), `{"base":"../other","added":[],"modified":[],"unchanged":[],"files":{}}`)
+	if _, err := ReadDeltaManifest(dir); err == nil {
+		t.Fatal("expected an error for a base outside the backup root")
+	}
+}
+
+func TestPruneKeepsBasesOfIncrementalSets(t *testing.T) {
+	root := t.TempDir()
+	old := time.Now().Add(-time.Hour)
+	for i, name := range []string{"20250101_000000", "20250102_000000", "20250103_000000"} {
+		dir := filepath.Join(root, name)
+		writeFile(t, filepath.Join(dir, ".env"), "A=1\n")
+		if i == 2 {
+			writeFile(t, filepath.Join(dir, DeltaManifestName), `{"base":"20250101_000000","added":[],"modified":[".env"],"unchanged":[],"files":{}}`)
+		}
+		mt := old.Add(time.Duration(i) * time.Minute)
+		if err := os.Chtimes(dir, mt, mt); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	removed, err := PruneUpdateBackups(root, 1)
+	if err != nil || removed != 1 {
+		t.Fatalf("removed = %d, err = %v", removed, err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "20250101_000000")); err != nil {
+		t.Fatal("base of the kept incremental set was pruned")
+	}
+	if _, err := os.Stat(filepath.Join(root, "20250102_000000")); !os.IsNotExist(err) {
+		t.Fatal("unreferenced set was kept")
+	}
+
+	sets, err := ListBackupSets(root)
+	if err != nil || sets[0].Base != "20250101_000000" || sets[0].FileCount != 1 || sets[1].Base != "" {
+		t.Fatalf("sets = %+v, err = %v", sets, err)
+	}
+}
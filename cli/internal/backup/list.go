@@ -0,0 +1,87 @@
+package backup
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"time"
+)
+
+// backupDirTimeLayout is the UTC timestamp used to name backup directories.
+const backupDirTimeLayout = "20060102_150405"
+
+// BackupSetInfo summarizes one backup directory.
+type BackupSetInfo struct {
+	Kind        string    `json:"kind"` // name of the backup root, e.g. "update-backups"
+	Path        string    `json:"path"`
+	CreatedAt   time.Time `json:"created_at"`
+	FileCount   int       `json:"file_count"`
+	TotalBytes  int64     `json:"total_bytes"`
+	HasManifest bool      `json:"has_manifest"`
+	// Base is the set an incremental set was taken against ("" for a full set).
+	Base string `json:"base,omitempty"`
+}
+
+// ListBackupSets returns every backup directory under root, newest first. CreatedAt comes
+// from the directory name when it is a backup timestamp, otherwise from its modification time.
+// FileCount excludes the checksum manifest. A missing root returns no sets.
+func ListBackupSets(root string) ([]BackupSetInfo, error) {
+	dirs, err := listBackupDirs(root)
+	if err != nil {
+		return nil, err
+	}
+	kind := filepath.Base(filepath.Clean(root))
+	sets := make([]BackupSetInfo, 0, len(dirs))
+	for _, d := range dirs {
+		info := BackupSetInfo{Kind: kind, Path: d.path, CreatedAt: d.mt}
+		err := filepath.WalkDir(d.path, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !entry.Type().IsRegular() {
+				return nil
+			}
+			fi, err := entry.Info()
+			if err != nil {
+				return err
+			}
+			info.TotalBytes += fi.Size()
+			if path == filepath.Join(d.path, ManifestName) {
+				info.HasManifest = true
+				return nil
+			}
+			if path == filepath.Join(d.path, DeltaManifestName) || path == filepath.Join(d.path, MetadataName) {
+				return nil
+			}
+			info.FileCount++
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan %s: %w", d.path, err)
+		}
+		if m, err := ReadDeltaManifest(d.path); err == nil {
+			info.Base = m.Base
+		}
+		sets = append(sets, info)
+	}
+	return sets, nil
+}
+
+// HumanBytes formats n using decimal units ("512 B", "12 KB", "3.4 MB").
+func HumanBytes(n int64) string {
+	const unit = 1000
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	value := float64(n) / float64(div)
+	suffix := "KMGTPE"[exp : exp+1]
+	if value >= 10 {
+		return fmt.Sprintf("%.0f %sB", value, suffix)
+	}
+	return fmt.Sprintf("%.1f %sB", value, suffix)
+}
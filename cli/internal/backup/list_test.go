@@ -0,0 +1,63 @@
+package backup
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestListBackupSets(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "update-backups")
+	older := filepath.Join(root, "20240101_000000")
+	newer := filepath.Join(root, "20250101_000000")
+	for _, dir := range []string{older, filepath.Join(newer, "config")} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(newer, ".env"), []byte("A=1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(newer, "config", "users.json"), []byte("{}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteManifest(newer); err != nil {
+		t.Fatal(err)
+	}
+	// Make mtime order disagree with the directory timestamps.
+	past := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(newer, past, past); err != nil {
+		t.Fatal(err)
+	}
+
+	sets, err := ListBackupSets(root)
+	if err != nil {
+		t.Fatalf("ListBackupSets: %v", err)
+	}
+	if len(sets) != 2 || sets[0].Path != newer || sets[1].Path != older {
+		t.Fatalf("unexpected order: %+v", sets)
+	}
+	got := sets[0]
+	if got.Kind != "update-backups" || got.FileCount != 2 || !got.HasManifest || got.TotalBytes <= 6 {
+		t.Fatalf("unexpected info: %+v", got)
+	}
+	if !got.CreatedAt.Equal(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("CreatedAt=%v", got.CreatedAt)
+	}
+	if sets[1].HasManifest || sets[1].FileCount != 0 {
+		t.Fatalf("unexpected info: %+v", sets[1])
+	}
+
+	if sets, err := ListBackupSets(filepath.Join(root, "missing")); err != nil || len(sets) != 0 {
+		t.Fatalf("missing root: %v %v", sets, err)
+	}
+}
+
+func TestHumanBytes(t *testing.T) {
+	for n, want := range map[int64]string{512: "512 B", 1500: "1.5 KB", 12_000_000: "12 MB"} {
+		if got := HumanBytes(n); got != want {
+			t.Fatalf("HumanBytes(%d)=%q want %q", n, got, want)
+		}
+	}
+}
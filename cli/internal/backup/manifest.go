@@ -0,0 +1,146 @@
+package backup
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ManifestName is the checksum manifest written at the root of each backup directory.
+const ManifestName = "manifest.sha256"
+
+// WriteManifest hashes every regular file under dir and writes ManifestName in
+// sha256sum format ("<hex-digest>  <relative-path>"), sorted by path.
+func WriteManifest(dir string) error {
+	var rels []string
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if rel == ManifestName || rel == ManifestSigName || rel == MetadataName {
+			return nil
+		}
+		rels = append(rels, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to walk %s: %w", dir, err)
+	}
+	sort.Strings(rels)
+
+	var b strings.Builder
+	for _, rel := range rels {
+		sum, err := fileSHA256(filepath.Join(dir, filepath.FromSlash(rel)))
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&b, "%s  %s\n", sum, rel)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ManifestName), []byte(b.String()), 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", ManifestName, err)
+	}
+	return nil
+}
+
+// VerifyManifest re-hashes every file listed in dir's manifest and returns an error naming the
+// first missing or mismatched file. It returns an error wrapping fs.ErrNotExist if dir has no
+// manifest.
+func VerifyManifest(dir string) error {
+	entries, err := readManifest(dir)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		got, err := fileSHA256(filepath.Join(dir, filepath.FromSlash(e.rel)))
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return fmt.Errorf("checksum mismatch: %s is missing", e.rel)
+			}
+			return err
+		}
+		if !strings.EqualFold(got, e.sum) {
+			return fmt.Errorf("checksum mismatch: %s (expected %s, got %s)", e.rel, e.sum[:12], got[:12])
+		}
+	}
+	return nil
+}
+
+// manifestEntries returns the relative paths listed in dir's manifest.
+func manifestEntries(dir string) ([]string, error) {
+	entries, err := readManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+	rels := make([]string, 0, len(entries))
+	for _, e := range entries {
+		rels = append(rels, e.rel)
+	}
+	return rels, nil
+}
+
+type manifestEntry struct {
+	sum string
+	rel string
+}
+
+func readManifest(dir string) ([]manifestEntry, error) {
+	f, err := os.Open(filepath.Join(dir, ManifestName))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []manifestEntry
+	scanner := bufio.NewScanner(f)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		want, rel, ok := strings.Cut(line, "  ")
+		if !ok || len(want) != 64 || strings.TrimSpace(rel) == "" {
+			return nil, fmt.Errorf("%s line %d: malformed entry", ManifestName, lineNo)
+		}
+		rel = strings.TrimSpace(rel)
+		// "..foo" is an ordinary name; only a ".." element escapes.
+		clean := filepath.Clean(filepath.FromSlash(rel))
+		if filepath.IsAbs(rel) || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+			return nil, fmt.Errorf("%s line %d: path %q escapes backup directory", ManifestName, lineNo, rel)
+		}
+		entries = append(entries, manifestEntry{sum: want, rel: rel})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", ManifestName, err)
+	}
+	return entries, nil
+}
+
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("failed to hash %s: %w", path, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
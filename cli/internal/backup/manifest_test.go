@@ -0,0 +1,59 @@
+package backup
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestManifestDetectsCorruption(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "config"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".env"), []byte("ASTERISK_HOST=127.0.0.1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "config", "ai-agent.local.yaml"), []byte("a: 1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := WriteManifest(dir); err != nil {
+		t.Fatalf("write manifest: %v", err)
+	}
+	if err := VerifyManifest(dir); err != nil {
+		t.Fatalf("verify clean backup: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "config", "ai-agent.local.yaml"), []byte("a: 2\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	err := VerifyManifest(dir)
+	if err == nil || !strings.Contains(err.Error(), "config/ai-agent.local.yaml") {
+		t.Fatalf("expected mismatch naming config/ai-agent.local.yaml, got %v", err)
+	}
+}
+
+func TestManifestPathsStayInBackupDir(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "..foo"), []byte("x\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteManifest(dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyManifest(dir); err != nil {
+		t.Fatalf("..foo is inside the backup: %v", err)
+	}
+
+	sum := strings.Repeat("0", 64)
+	for _, rel := range []string{"..", "../x", "config/../../x"} {
+		if err := os.WriteFile(filepath.Join(dir, ManifestName), []byte(sum+"  "+rel+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := VerifyManifest(dir); err == nil || !strings.Contains(err.Error(), "escapes") {
+			t.Fatalf("%s: err = %v", rel, err)
+		}
+	}
+}
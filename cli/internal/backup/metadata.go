@@ -0,0 +1,52 @@
+package backup
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// MetadataName holds facts about a backup set that are only known after it was taken, such as
+// whether the update it guarded installed pre-release images. It is written after the
+// checksum manifest and left out of it, so it can be added without invalidating the set.
+const MetadataName = "manifest.json"
+
+// Metadata is the content of MetadataName.
+type Metadata struct {
+	// Prerelease marks a set taken before an update to pre-release images (agent update
+	// --prerelease). Such a set is not used for production recovery unless asked for.
+	Prerelease bool `json:"prerelease"`
+	// ImageTag is the pre-release tag the update installed, e.g. "edge".
+	ImageTag string `json:"image_tag,omitempty"`
+}
+
+// WriteMetadata writes m as dir's MetadataName.
+func WriteMetadata(dir string, m Metadata) error {
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, MetadataName), append(b, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", MetadataName, err)
+	}
+	return nil
+}
+
+// ReadMetadata reads dir's MetadataName. A set without one has zero Metadata.
+func ReadMetadata(dir string) (Metadata, error) {
+	var m Metadata
+	b, err := os.ReadFile(filepath.Join(dir, MetadataName))
+	if errors.Is(err, fs.ErrNotExist) {
+		return m, nil
+	}
+	if err != nil {
+		return m, err
+	}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return m, fmt.Errorf("%s: %w", filepath.Join(dir, MetadataName), err)
+	}
+	return m, nil
+}
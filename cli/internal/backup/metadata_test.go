@@ -0,0 +1,48 @@
+package backup
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMetadataRoundTripOutsideManifest(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ".env"), []byte("A=1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if m, err := ReadMetadata(dir); err != nil || m.Prerelease {
+		t.Fatalf("ReadMetadata without file = %+v, %v", m, err)
+	}
+	if err := WriteManifest(dir); err != nil {
+		t.Fatal(err)
+	}
+	// Written after the manifest, as agent update does once the update succeeded.
+	if err := WriteMetadata(dir, Metadata{Prerelease: true, ImageTag: "edge"}); err != nil {
+		t.Fatal(err)
+	}
+	m, err := ReadMetadata(dir)
+	if err != nil || !m.Prerelease || m.ImageTag != "edge" {
+		t.Fatalf("ReadMetadata = %+v, %v", m, err)
+	}
+	if err := VerifyManifest(dir); err != nil {
+		t.Fatalf("VerifyManifest: %v", err)
+	}
+	if err := WriteManifest(dir); err != nil {
+		t.Fatal(err)
+	}
+	rels, err := manifestEntries(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rels) != 1 || rels[0] != ".env" {
+		t.Fatalf("manifest lists %v, want only .env", rels)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, MetadataName), []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadMetadata(dir); err == nil {
+		t.Fatal("expected an error for invalid metadata")
+	}
+}
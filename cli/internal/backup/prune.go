@@ -0,0 +1,110 @@
+package backup
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// DefaultKeep is the number of update-backup directories retained when AGENT_BACKUP_KEEP is unset.
+const DefaultKeep = 10
+
+type backupDir struct {
+	path string
+	mt   time.Time
+}
+
+// listBackupDirs returns the immediate subdirectories of root, newest first. Sets are
+// ordered by the timestamp in their name (see setTime), so writing into an old set (a manifest
+// added by backup verify --fix-manifest, say) does not make it the newest.
+// A missing root is not an error (no backups have been taken yet).
+func listBackupDirs(root string) ([]backupDir, error) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read backup root %s: %w", root, err)
+	}
+	dirs := make([]backupDir, 0, len(entries))
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		full := filepath.Join(root, e.Name())
+		info, statErr := os.Stat(full)
+		if statErr != nil {
+			continue
+		}
+		dirs = append(dirs, backupDir{path: full, mt: setTime(e.Name(), info.ModTime())})
+	}
+	sort.Slice(dirs, func(i, j int) bool { return dirs[i].mt.After(dirs[j].mt) })
+	return dirs, nil
+}
+
+// setTime is when the backup set called name was taken: the UTC timestamp it is named after,
+// or modTime for sets with another name (a custom --backup-id).
+func setTime(name string, modTime time.Time) time.Time {
+	if ts, err := time.Parse(backupDirTimeLayout, name); err == nil {
+		return ts
+	}
+	return modTime
+}
+
+// LatestBackupDir returns the newest backup directory under root, or "" if none exist.
+func LatestBackupDir(root string) (string, error) {
+	dirs, err := listBackupDirs(root)
+	if err != nil || len(dirs) == 0 {
+		return "", err
+	}
+	return dirs[0].path, nil
+}
+
+// BackupDirs returns the backup directories under root, newest first.
+func BackupDirs(root string) ([]string, error) {
+	dirs, err := listBackupDirs(root)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]string, 0, len(dirs))
+	for _, d := range dirs {
+		out = append(out, d.path)
+	}
+	return out, nil
+}
+
+// PruneUpdateBackups keeps the newest keepN backup directories under root and deletes the
+// rest, except older sets that a kept incremental set is built on (see BackupChain). It
+// returns how many directories were removed.
+func PruneUpdateBackups(root string, keepN int) (removed int, err error) {
+	if keepN < 0 {
+		return 0, fmt.Errorf("invalid keep count %d (must be >= 0)", keepN)
+	}
+	dirs, err := listBackupDirs(root)
+	if err != nil {
+		return 0, err
+	}
+	if len(dirs) <= keepN {
+		return 0, nil
+	}
+	needed := map[string]bool{}
+	for _, d := range dirs[:keepN] {
+		if chain, err := BackupChain(d.path); err == nil {
+			for _, dir := range chain {
+				needed[filepath.Clean(dir)] = true
+			}
+		}
+	}
+	for _, d := range dirs[keepN:] {
+		if needed[filepath.Clean(d.path)] {
+			continue
+		}
+		if err := os.RemoveAll(d.path); err != nil {
+			return removed, fmt.Errorf("failed to remove %s: %w", d.path, err)
+		}
+		removed++
+	}
+	return removed, nil
+}
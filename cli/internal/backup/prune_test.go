@@ -0,0 +1,46 @@
+package backup
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestPruneUpdateBackupsKeepsNewest(t *testing.T) {
+	root := t.TempDir()
+	base := time.Now().Add(-time.Hour)
+	for i, name := range []string{"20250101_000000", "20250102_000000", "20250103_000000"} {
+		dir := filepath.Join(root, name)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		mt := base.Add(time.Duration(i) * time.Minute)
+		if err := os.Chtimes(dir, mt, mt); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	removed, err := PruneUpdateBackups(root, 2)
+	if err != nil {
+		t.Fatalf("prune: %v", err)
+	}
+	if removed != 1 {
+		t.Fatalf("removed=%d want 1", removed)
+	}
+	if _, err := os.Stat(filepath.Join(root, "20250101_000000")); !os.IsNotExist(err) {
+		t.Fatalf("expected oldest backup to be removed")
+	}
+	for _, name := range []string{"20250102_000000", "20250103_000000"} {
+		if _, err := os.Stat(filepath.Join(root, name)); err != nil {
+			t.Fatalf("expected %s to be kept: %v", name, err)
+		}
+	}
+}
+
+func TestPruneUpdateBackupsMissingRoot(t *testing.T) {
+	removed, err := PruneUpdateBackups(filepath.Join(t.TempDir(), "missing"), 10)
+	if err != nil || removed != 0 {
+		t.Fatalf("removed=%d err=%v", removed, err)
+	}
+}
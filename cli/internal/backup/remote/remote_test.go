@@ -0,0 +1,52 @@
+package remote
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestArchiveRoundTrip(t *testing.T) {
+	src := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(src, "config", "contexts"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		".env":                              "ASTERISK_HOST=127.0.0.1\n",
+		"config/ai-agent.local.yaml":        "a: 1\n",
+		"config/contexts/demo-project.yaml": "name: demo\n",
+	}
+	for rel, content := range files {
+		if err := os.WriteFile(filepath.Join(src, filepath.FromSlash(rel)), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := WriteArchive(&buf, src); err != nil {
+		t.Fatalf("write archive: %v", err)
+	}
+	dst := filepath.Join(t.TempDir(), "restored")
+	if err := ExtractArchive(&buf, dst); err != nil {
+		t.Fatalf("extract archive: %v", err)
+	}
+	for rel, want := range files {
+		got, err := os.ReadFile(filepath.Join(dst, filepath.FromSlash(rel)))
+		if err != nil {
+			t.Fatalf("read %s: %v", rel, err)
+		}
+		if string(got) != want {
+			t.Fatalf("%s: got %q want %q", rel, got, want)
+		}
+	}
+}
+
+func TestURIEncode(t *testing.T) {
+	if got := uriEncode("/bucket/a b+c.tar.gz", false); got != "/bucket/a%20b%2Bc.tar.gz" {
+		t.Fatalf("got %q", got)
+	}
+	if got := uriEncode("a/b", true); got != "a%2Fb" {
+		t.Fatalf("got %q", got)
+	}
+}
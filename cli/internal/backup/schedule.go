@@ -0,0 +1,128 @@
+package backup
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/environment"
+)
+
+// Interval is how often agent backup schedule runs agent backup create.
+type Interval string
+
+const (
+	Hourly Interval = "hourly"
+	Daily  Interval = "daily"
+	Weekly Interval = "weekly"
+)
+
+// ParseInterval accepts hourly, daily or weekly.
+func ParseInterval(s string) (Interval, error) {
+	switch iv := Interval(strings.ToLower(strings.TrimSpace(s))); iv {
+	case Hourly, Daily, Weekly:
+		return iv, nil
+	}
+	return "", fmt.Errorf("invalid interval %q (must be hourly, daily or weekly)", s)
+}
+
+// ScheduleName is the systemd unit name (without suffix) and crontab marker for a schedule:
+// agent-backup, or agent-backup-<env> for a named environment.
+func ScheduleName(env string) string {
+	if env == "" || env == environment.Default {
+		return "agent-backup"
+	}
+	return "agent-backup-" + env
+}
+
+// SystemdUnits returns the .service and .timer units that run command (quoted with
+// QuoteCommand) at iv. The timer is persistent: a run missed while the host was off
+// happens at the next boot.
+func SystemdUnits(name string, command []string, iv Interval) (service, timer string) {
+	service = fmt.Sprintf(`[Unit]
+Description=Asterisk AI Voice Agent config backup (%s)
+
+[Service]
+Type=oneshot
+ExecStart=%s
+`, name, strings.Join(command, " "))
+	timer = fmt.Sprintf(`[Unit]
+Description=Run %s.service %s
+
+[Timer]
+OnCalendar=%s
+Persistent=true
+
+[Install]
+WantedBy=timers.target
+`, name, iv, iv)
+	return service, timer
+}
+
+// cronMarker tags the crontab line written for a schedule so it can be replaced or removed.
+func cronMarker(name string) string {
+	return "# " + name + " (managed by agent backup schedule)"
+}
+
+// CrontabWithSchedule returns crontab with any previous line for name replaced by one that
+// runs command at iv.
+func CrontabWithSchedule(crontab, name string, command []string, iv Interval) string {
+	rest, _ := CrontabWithoutSchedule(crontab, name)
+	line := fmt.Sprintf("@%s %s >/dev/null 2>&1 %s", iv, strings.Join(command, " "), cronMarker(name))
+	if rest != "" && !strings.HasSuffix(rest, "\n") {
+		rest += "\n"
+	}
+	return rest + line + "\n"
+}
+
+// CrontabWithoutSchedule removes the line written for name and reports whether there was one.
+func CrontabWithoutSchedule(crontab, name string) (string, bool) {
+	marker := cronMarker(name)
+	var kept []string
+	found := false
+	for _, line := range strings.SplitAfter(crontab, "\n") {
+		if strings.HasSuffix(strings.TrimRight(line, "\n"), marker) {
+			found = true
+			continue
+		}
+		kept = append(kept, line)
+	}
+	return strings.Join(kept, ""), found
+}
+
+// NextCronRun returns when cron next fires @hourly, @daily or @weekly after now (minute 0 of
+// the next hour, next midnight, next Sunday midnight; in now's location).
+func NextCronRun(iv Interval, now time.Time) time.Time {
+	switch iv {
+	case Hourly:
+		return time.Date(now.Year(), now.Month(), now.Day(), now.Hour()+1, 0, 0, 0, now.Location())
+	case Weekly:
+		midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+		days := (7 - int(now.Weekday())) % 7
+		if days == 0 {
+			days = 7
+		}
+		return midnight.AddDate(0, 0, days)
+	default:
+		midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+		return midnight.AddDate(0, 0, 1)
+	}
+}
+
+// QuoteCommand single-quotes the arguments that need it, in a form both sh (cron) and
+// systemd's ExecStart parse the same way. Arguments with a single quote, '%' (a systemd
+// specifier and a newline in crontab) or a newline have no such form and are rejected.
+func QuoteCommand(args ...string) ([]string, error) {
+	out := make([]string, len(args))
+	for i, a := range args {
+		if strings.ContainsAny(a, "'%\n") {
+			return nil, fmt.Errorf("cannot schedule a command with %q: quotes, %% and newlines are not supported", a)
+		}
+		if a != "" && !strings.ContainsAny(a, " \t\"\\$`;&|<>()*?[]#~") {
+			out[i] = a
+			continue
+		}
+		out[i] = "'" + a + "'"
+	}
+	return out, nil
+}
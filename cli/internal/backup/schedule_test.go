@@ -0,0 +1,76 @@
+package backup
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCrontabSchedule(t *testing.T) {
+	cmd, err := QuoteCommand("/usr/local/bin/agent", "--repo-root", "/srv/my agent", "backup", "create")
+	if err != nil {
+		t.Fatal(err)
+	}
+	existing := "MAILTO=ops@example.com\n0 3 * * * /usr/bin/certbot renew"
+	got := CrontabWithSchedule(existing, "agent-backup", cmd, Daily)
+	want := "MAILTO=ops@example.com\n0 3 * * * /usr/bin/certbot renew\n" +
+		"@daily /usr/local/bin/agent --repo-root '/srv/my agent' backup create >/dev/null 2>&1 # agent-backup (managed by agent backup schedule)\n"
+	if got != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	// Rescheduling replaces the line instead of adding a second one.
+	again := CrontabWithSchedule(got, "agent-backup", cmd, Hourly)
+	if strings.Count(again, "agent-backup (managed") != 1 || !strings.Contains(again, "@hourly ") {
+		t.Fatalf("reschedule:\n%s", again)
+	}
+	// Other environments keep their own line.
+	both := CrontabWithSchedule(again, ScheduleName("staging"), cmd, Weekly)
+
+	removed, found := CrontabWithoutSchedule(both, "agent-backup")
+	if !found || strings.Contains(removed, "# agent-backup (") || !strings.Contains(removed, "# agent-backup-staging (") {
+		t.Fatalf("remove (found=%v):\n%s", found, removed)
+	}
+	if _, found := CrontabWithoutSchedule(existing, "agent-backup"); found {
+		t.Error("found a schedule in a crontab without one")
+	}
+}
+
+func TestSystemdUnits(t *testing.T) {
+	service, timer := SystemdUnits("agent-backup", []string{"/usr/local/bin/agent", "backup", "create"}, Weekly)
+	if !strings.Contains(service, "ExecStart=/usr/local/bin/agent backup create\n") || !strings.Contains(service, "Type=oneshot") {
+		t.Errorf("service:\n%s", service)
+	}
+	if !strings.Contains(timer, "OnCalendar=weekly\n") || !strings.Contains(timer, "Persistent=true") || !strings.Contains(timer, "WantedBy=timers.target") {
+		t.Errorf("timer:\n%s", timer)
+	}
+}
+
+func TestNextCronRun(t *testing.T) {
+	now := time.Date(2026, 10, 16, 14, 25, 0, 0, time.UTC) // a Friday
+	for iv, want := range map[Interval]time.Time{
+		Hourly: time.Date(2026, 10, 16, 15, 0, 0, 0, time.UTC),
+		Daily:  time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC),
+		Weekly: time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC),
+	} {
+		if got := NextCronRun(iv, now); !got.Equal(want) {
+			t.Errorf("%s: got %v, want %v", iv, got, want)
+		}
+	}
+	sunday := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	if got := NextCronRun(Weekly, sunday); !got.Equal(time.Date(2026, 10, 25, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("weekly from Sunday: %v", got)
+	}
+}
+
+func TestParseIntervalAndQuote(t *testing.T) {
+	if iv, err := ParseInterval(" Daily "); err != nil || iv != Daily {
+		t.Errorf("ParseInterval = %v, %v", iv, err)
+	}
+	if _, err := ParseInterval("monthly"); err == nil {
+		t.Error("monthly should be rejected")
+	}
+	if _, err := QuoteCommand("/opt/100%/agent"); err == nil {
+		t.Error("'%' should be rejected")
+	}
+}
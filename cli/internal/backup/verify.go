@@ -0,0 +1,82 @@
+package backup
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"sort"
+)
+
+// FileValidator checks one kind of file in a backup set.
+type FileValidator struct {
+	Name string
+	// Match selects the files the validator applies to by their slash-separated path
+	// relative to the backup set; nil matches every file.
+	Match    func(rel string) bool
+	Validate func(path string) error
+}
+
+// FileResult is the outcome of validating one file of a backup set.
+type FileResult struct {
+	Path string `json:"path"`
+	// Validators lists the validators that were run, in order, up to the first failure.
+	Validators []string `json:"validators"`
+	Error      string   `json:"error,omitempty"`
+}
+
+// OK reports whether every validator accepted the file.
+func (r FileResult) OK() bool { return r.Error == "" }
+
+// ManifestStatus summarizes how a backup set's manifest compared with its files.
+type ManifestStatus string
+
+const (
+	ManifestOK       ManifestStatus = "ok"
+	ManifestMissing  ManifestStatus = "missing"
+	ManifestMismatch ManifestStatus = "mismatch"
+)
+
+// CheckManifest verifies dir against its manifest. Backups created before manifests were
+// introduced report ManifestMissing with a nil error.
+func CheckManifest(dir string) (ManifestStatus, error) {
+	err := VerifyManifest(dir)
+	switch {
+	case err == nil:
+		return ManifestOK, nil
+	case errors.Is(err, fs.ErrNotExist):
+		return ManifestMissing, nil
+	default:
+		return ManifestMismatch, err
+	}
+}
+
+// ValidateFiles runs every matching validator over each file of the backup set in dir
+// (manifest and signature excluded), stopping at a file's first failure. Results are sorted
+// by path.
+func ValidateFiles(dir string, validators []FileValidator) ([]FileResult, error) {
+	paths, err := backupFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]FileResult, 0, len(paths))
+	for _, path := range paths {
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return nil, err
+		}
+		res := FileResult{Path: filepath.ToSlash(rel), Validators: []string{}}
+		for _, v := range validators {
+			if v.Match != nil && !v.Match(res.Path) {
+				continue
+			}
+			res.Validators = append(res.Validators, v.Name)
+			if err := v.Validate(path); err != nil {
+				res.Error = err.Error()
+				break
+			}
+		}
+		results = append(results, res)
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Path < results[j].Path })
+	return results, nil
+}
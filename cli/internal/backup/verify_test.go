@@ -0,0 +1,62 @@
+package backup
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckManifest(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, ".env"), "ASTERISK_HOST=pbx\n")
+	if status, err := CheckManifest(dir); status != ManifestMissing || err != nil {
+		t.Fatalf("no manifest: %s, %v", status, err)
+	}
+	if err := WriteManifest(dir); err != nil {
+		t.Fatal(err)
+	}
+	if status, err := CheckManifest(dir); status != ManifestOK || err != nil {
+		t.Fatalf("fresh manifest: %s, %v", status, err)
+	}
+	writeFile(t, filepath.Join(dir, ".env"), "ASTERISK_HOST=other\n")
+	if status, err := CheckManifest(dir); status != ManifestMismatch || err == nil {
+		t.Fatalf("modified file: %s, %v", status, err)
+	}
+}
+
+func TestValidateFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "config", "ai-agent.yaml"), "a: 1\n")
+	writeFile(t, filepath.Join(dir, "config", "contexts", "bad.yaml"), "oops\n")
+	writeFile(t, filepath.Join(dir, ".env"), "X=1\n")
+	if err := WriteManifest(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	calls := 0
+	validators := []FileValidator{
+		{Name: "any", Validate: func(string) error { calls++; return nil }},
+		{Name: "yaml", Match: func(rel string) bool { return strings.HasSuffix(rel, ".yaml") }, Validate: func(p string) error {
+			data, _ := os.ReadFile(p)
+			if !strings.Contains(string(data), ":") {
+				return errors.New("not a mapping")
+			}
+			return nil
+		}},
+	}
+	results, err := ValidateFiles(dir, validators)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 3 || calls != 3 {
+		t.Fatalf("results = %+v (calls %d); the manifest must be skipped", results, calls)
+	}
+	if r := results[0]; r.Path != ".env" || !r.OK() || strings.Join(r.Validators, ",") != "any" {
+		t.Fatalf(".env: %+v", r)
+	}
+	if r := results[2]; r.Path != "config/contexts/bad.yaml" || r.OK() || r.Error != "not a mapping" || len(r.Validators) != 2 {
+		t.Fatalf("bad.yaml: %+v", r)
+	}
+}
@@ -0,0 +1,198 @@
+// Package bench measures ARI HTTP round-trip latency and throughput under concurrent load.
+package bench
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ARIPath is the ARI resource listing each request fetches. It is cheap for Asterisk to serve
+// and needs only ARI credentials, so it measures the HTTP/ARI stack rather than a call flow.
+const ARIPath = "/ari/api-docs/resources.json"
+
+// Defaults for BenchmarkOptions fields left at zero.
+const (
+	DefaultConcurrency    = 10
+	DefaultRequests       = 100
+	DefaultRequestTimeout = 10 * time.Second
+)
+
+// BenchmarkOptions configures RunBenchmark.
+type BenchmarkOptions struct {
+	// Concurrency is the number of requests in flight at once.
+	Concurrency int
+	// Requests is the total number of requests to send.
+	Requests int
+	// Endpoint is the ARI base URL, e.g. http://127.0.0.1:8088; ARIPath is appended.
+	Endpoint string
+	// Username and Password are sent as HTTP basic auth when Username is set.
+	Username string
+	Password string
+	// InsecureSkipVerify disables TLS certificate verification (ASTERISK_ARI_SSL_VERIFY=false).
+	InsecureSkipVerify bool
+	// RequestTimeout bounds each request (DefaultRequestTimeout when zero).
+	RequestTimeout time.Duration
+}
+
+// BenchmarkResult summarizes a run. Latency percentiles cover successful requests only.
+type BenchmarkResult struct {
+	URL       string
+	Requests  int
+	Errors    int
+	Elapsed   time.Duration
+	Min       time.Duration
+	Max       time.Duration
+	P50       time.Duration
+	P95       time.Duration
+	P99       time.Duration
+	RPS       float64
+	ErrorRate float64
+	// FirstError describes the first failed request, if any.
+	FirstError string
+	// Latencies holds the successful round-trip times in ascending order.
+	Latencies []time.Duration
+}
+
+// RunBenchmark sends opts.Requests GET requests for ARIPath to opts.Endpoint, opts.Concurrency
+// at a time, and times each round trip including reading the body. A transport error or a
+// non-2xx status counts as an error. RPS is completed requests per second of wall time. When
+// ctx is cancelled the requests not yet sent are dropped and the partial result is returned
+// with ctx's error.
+func RunBenchmark(ctx context.Context, opts BenchmarkOptions) (BenchmarkResult, error) {
+	if opts.Endpoint == "" {
+		return BenchmarkResult{}, errors.New("no ARI endpoint")
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = DefaultConcurrency
+	}
+	if opts.Requests <= 0 {
+		opts.Requests = DefaultRequests
+	}
+	if opts.Concurrency > opts.Requests {
+		opts.Concurrency = opts.Requests
+	}
+	if opts.RequestTimeout <= 0 {
+		opts.RequestTimeout = DefaultRequestTimeout
+	}
+	url := strings.TrimRight(opts.Endpoint, "/") + ARIPath
+	if _, err := http.NewRequest(http.MethodGet, url, nil); err != nil {
+		return BenchmarkResult{}, fmt.Errorf("invalid ARI endpoint: %w", err)
+	}
+
+	client := &http.Client{
+		Timeout: opts.RequestTimeout,
+		Transport: &http.Transport{
+			Proxy:               http.ProxyFromEnvironment,
+			MaxIdleConnsPerHost: opts.Concurrency,
+			TLSClientConfig:     &tls.Config{InsecureSkipVerify: opts.InsecureSkipVerify}, //nolint:gosec // operator opt-out via ASTERISK_ARI_SSL_VERIFY
+		},
+	}
+	defer client.CloseIdleConnections()
+
+	type sample struct {
+		d   time.Duration
+		err error
+	}
+	jobs := make(chan struct{})
+	samples := make(chan sample, opts.Requests)
+	var wg sync.WaitGroup
+	for i := 0; i < opts.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range jobs {
+				d, err := roundTrip(ctx, client, url, opts)
+				samples <- sample{d, err}
+			}
+		}()
+	}
+
+	start := time.Now()
+send:
+	for i := 0; i < opts.Requests; i++ {
+		select {
+		case jobs <- struct{}{}:
+		case <-ctx.Done():
+			break send
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	close(samples)
+
+	res := BenchmarkResult{URL: url, Elapsed: time.Since(start)}
+	for s := range samples {
+		res.Requests++
+		if s.err != nil {
+			res.Errors++
+			if res.FirstError == "" {
+				res.FirstError = s.err.Error()
+			}
+			continue
+		}
+		res.Latencies = append(res.Latencies, s.d)
+	}
+	summarize(&res)
+	return res, ctx.Err()
+}
+
+func roundTrip(ctx context.Context, client *http.Client, url string, opts BenchmarkOptions) (time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, err
+	}
+	if opts.Username != "" {
+		req.SetBasicAuth(opts.Username, opts.Password)
+	}
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	_, err = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	d := time.Since(start)
+	if err != nil {
+		return 0, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return 0, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return d, nil
+}
+
+// summarize fills the derived fields of res from res.Latencies and the counts.
+func summarize(res *BenchmarkResult) {
+	slices.Sort(res.Latencies)
+	if n := len(res.Latencies); n > 0 {
+		res.Min, res.Max = res.Latencies[0], res.Latencies[n-1]
+		res.P50 = Percentile(res.Latencies, 50)
+		res.P95 = Percentile(res.Latencies, 95)
+		res.P99 = Percentile(res.Latencies, 99)
+	}
+	if res.Requests > 0 {
+		res.ErrorRate = float64(res.Errors) / float64(res.Requests)
+	}
+	if secs := res.Elapsed.Seconds(); secs > 0 {
+		res.RPS = float64(res.Requests) / secs
+	}
+}
+
+// Percentile returns the nearest-rank p-th percentile of sorted, or 0 when it is empty.
+func Percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	rank = max(1, min(rank, len(sorted)))
+	return sorted[rank-1]
+}
@@ -0,0 +1,131 @@
+package bench
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRunBenchmarkCountsRequestsAndErrors(t *testing.T) {
+	var hits, inFlight, peak atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := hits.Add(1)
+		cur := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			p := peak.Load()
+			if cur <= p || peak.CompareAndSwap(p, cur) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		if r.URL.Path != ARIPath {
+			http.NotFound(w, r)
+			return
+		}
+		if u, p, _ := r.BasicAuth(); u != "asterisk" || p != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if n%5 == 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"apis":[]}`))
+	}))
+	defer srv.Close()
+
+	res, err := RunBenchmark(context.Background(), BenchmarkOptions{
+		Concurrency: 4,
+		Requests:    20,
+		Endpoint:    srv.URL + "/",
+		Username:    "asterisk",
+		Password:    "secret",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Requests != 20 || res.Errors != 4 || len(res.Latencies) != 16 || hits.Load() != 20 {
+		t.Fatalf("requests=%d errors=%d latencies=%d hits=%d", res.Requests, res.Errors, len(res.Latencies), hits.Load())
+	}
+	if res.ErrorRate != 0.2 || res.FirstError != "HTTP 503" || res.URL != srv.URL+ARIPath {
+		t.Fatalf("%+v", res)
+	}
+	if peak.Load() > 4 {
+		t.Fatalf("peak concurrency = %d, want <= 4", peak.Load())
+	}
+	if res.P50 < 5*time.Millisecond || res.P50 > res.P95 || res.P95 > res.P99 || res.P99 > res.Max || res.RPS <= 0 {
+		t.Fatalf("latencies: %+v", res)
+	}
+}
+
+func TestRunBenchmarkUnreachable(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+	res, err := RunBenchmark(context.Background(), BenchmarkOptions{Concurrency: 2, Requests: 3, Endpoint: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Errors != 3 || res.ErrorRate != 1 || res.P50 != 0 || res.FirstError == "" {
+		t.Fatalf("%+v", res)
+	}
+	if _, err := RunBenchmark(context.Background(), BenchmarkOptions{}); err == nil {
+		t.Fatal("expected an error without an endpoint")
+	}
+}
+
+func TestPercentileNearestRank(t *testing.T) {
+	var d []time.Duration
+	for i := 1; i <= 100; i++ {
+		d = append(d, time.Duration(i)*time.Millisecond)
+	}
+	for p, want := range map[float64]time.Duration{50: 50 * time.Millisecond, 95: 95 * time.Millisecond, 99: 99 * time.Millisecond, 100: 100 * time.Millisecond, 0: time.Millisecond} {
+		if got := Percentile(d, p); got != want {
+			t.Errorf("p%v = %s, want %s", p, got, want)
+		}
+	}
+	if Percentile(nil, 50) != 0 {
+		t.Error("empty percentile should be 0")
+	}
+}
+
+func TestWriteSummaryHistogram(t *testing.T) {
+	res := BenchmarkResult{URL: "http://pbx:8088" + ARIPath, Requests: 5, Errors: 1, Elapsed: time.Second, FirstError: "HTTP 503"}
+	res.Latencies = []time.Duration{time.Millisecond, time.Millisecond, 2 * time.Millisecond, 11 * time.Millisecond}
+	summarize(&res)
+
+	var b strings.Builder
+	if err := WriteSummary(&b, res); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	for _, want := range []string{
+		"Requests:    5 in 1s (5.0 req/s)",
+		"Errors:      1 (20.0%)",
+		"First error: HTTP 503",
+		"min 1ms  p50 1ms  p95 11ms  p99 11ms  max 11ms",
+		"  <= 1.271ms       2  " + strings.Repeat("█", HistogramWidth) + "\n",
+		"  <= 1.615ms       0\n",
+		"  <= 2.053ms       1  " + strings.Repeat("█", HistogramWidth/2) + "\n",
+		"  <= 11ms          1  " + strings.Repeat("█", HistogramWidth/2) + "\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("summary missing %q:\n%s", want, out)
+		}
+	}
+	if got := strings.Count(out, "  <= "); got != HistogramBuckets {
+		t.Errorf("%d histogram rows, want %d", got, HistogramBuckets)
+	}
+
+	same := BenchmarkResult{Requests: 2, Latencies: []time.Duration{time.Millisecond, time.Millisecond}}
+	summarize(&same)
+	b.Reset()
+	WriteSummary(&b, same)
+	if got := strings.Count(b.String(), "  <= "); got != 1 {
+		t.Errorf("equal latencies: %d rows, want 1:\n%s", got, b.String())
+	}
+}
@@ -0,0 +1,82 @@
+package bench
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strings"
+	"time"
+)
+
+// HistogramBuckets and HistogramWidth shape WriteSummary's latency histogram.
+const (
+	HistogramBuckets = 10
+	HistogramWidth   = 40
+)
+
+// WriteSummary writes res as text: the counts and percentiles, then a histogram of the
+// successful latencies in HistogramBuckets buckets between Min and Max. The buckets grow
+// geometrically, so a few slow outliers do not squash the bulk of the requests into one row.
+func WriteSummary(w io.Writer, res BenchmarkResult) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Target:      %s\n", res.URL)
+	fmt.Fprintf(&b, "Requests:    %d in %s (%.1f req/s)\n", res.Requests, round(res.Elapsed), res.RPS)
+	fmt.Fprintf(&b, "Errors:      %d (%.1f%%)\n", res.Errors, res.ErrorRate*100)
+	if res.FirstError != "" {
+		fmt.Fprintf(&b, "First error: %s\n", res.FirstError)
+	}
+	if len(res.Latencies) > 0 {
+		fmt.Fprintf(&b, "Latency:     min %s  p50 %s  p95 %s  p99 %s  max %s\n",
+			round(res.Min), round(res.P50), round(res.P95), round(res.P99), round(res.Max))
+		b.WriteString("\n")
+		writeHistogram(&b, res.Latencies)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeHistogram draws one bar per bucket, scaled so the fullest bucket is HistogramWidth wide.
+func writeHistogram(b *strings.Builder, sorted []time.Duration) {
+	lo, hi := max(sorted[0], 1), sorted[len(sorted)-1]
+	buckets := HistogramBuckets
+	if hi <= lo {
+		buckets = 1
+	}
+	// Bucket i ends at lo * (hi/lo)^((i+1)/buckets); the last one ends exactly at hi.
+	uppers := make([]time.Duration, buckets)
+	ratio := float64(hi) / float64(lo)
+	for i := range uppers {
+		uppers[i] = time.Duration(float64(lo) * math.Pow(ratio, float64(i+1)/float64(buckets)))
+	}
+	uppers[buckets-1] = hi
+
+	counts := make([]int, buckets)
+	i := 0
+	for _, d := range sorted {
+		for d > uppers[i] && i < buckets-1 {
+			i++
+		}
+		counts[i]++
+	}
+	peak := slices.Max(counts)
+	for i, c := range counts {
+		bar := strings.Repeat("█", c*HistogramWidth/peak)
+		if c > 0 && bar == "" {
+			bar = "▏"
+		}
+		fmt.Fprintln(b, strings.TrimRight(fmt.Sprintf("  <= %-9s %5d  %s", round(uppers[i]), c, bar), " "))
+	}
+}
+
+// round trims durations to a readable precision (1.234ms, 12.3ms, 1.2s).
+func round(d time.Duration) time.Duration {
+	switch {
+	case d >= time.Second:
+		return d.Round(10 * time.Millisecond)
+	case d >= 10*time.Millisecond:
+		return d.Round(100 * time.Microsecond)
+	default:
+		return d.Round(time.Microsecond)
+	}
+}
@@ -0,0 +1,137 @@
+package check
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/environment"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/health"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/secrets"
+)
+
+// DefaultARITimeout bounds the host-side ARI probe when Runner.ARITimeout is unset.
+const DefaultARITimeout = 5 * time.Second
+
+// CheckARI opens an HTTP connection from the host to ASTERISK_HOST:ASTERISK_ARI_PORT with the
+// .env credentials and expects 200 from /ari/api-docs/resources.json. The round-trip time is
+// stored in Item.Duration. The probe is bounded by the run context (--check-timeout) and by
+// Runner.ARITimeout.
+func (r *Runner) CheckARI() Item {
+	item := Item{Name: "ARI Connectivity"}
+
+	envMap, _, _ := secrets.LoadEnv(r.hostEnvPath()) // .env.age is decrypted in memory
+	get := func(key string) string { return EnvValue(health.GetEnv(key, envMap)) }
+
+	host := get("ASTERISK_HOST")
+	if host == "" {
+		item.Status = StatusSkip
+		item.Message = "ASTERISK_HOST not set"
+		return item
+	}
+	port := get("ASTERISK_ARI_PORT")
+	if port == "" {
+		if k, ok := DefaultEnvSchema().Lookup("ASTERISK_ARI_PORT"); ok {
+			port = k.Default
+		}
+	}
+	scheme := strings.ToLower(get("ASTERISK_ARI_SCHEME"))
+	if scheme == "" {
+		scheme = "http"
+	}
+	insecure := false
+	switch strings.ToLower(get("ASTERISK_ARI_SSL_VERIFY")) {
+	case "0", "false", "no":
+		insecure = true
+	}
+
+	timeout := r.ARITimeout
+	if timeout <= 0 {
+		timeout = DefaultARITimeout
+	}
+	ctx := r.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	url := fmt.Sprintf("%s://%s/ari/api-docs/resources.json", scheme, net.JoinHostPort(host, port))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		item.Status = StatusFail
+		item.Message = "invalid ARI URL"
+		item.Details = err.Error()
+		item.Remediation = "Set ASTERISK_HOST in .env to the IP or hostname of your Asterisk server"
+		return item
+	}
+	req.SetBasicAuth(get("ASTERISK_ARI_USERNAME"), get("ASTERISK_ARI_PASSWORD"))
+
+	client := &http.Client{Transport: &http.Transport{
+		Proxy:           http.ProxyFromEnvironment,
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: insecure}, //nolint:gosec // operator opt-out via ASTERISK_ARI_SSL_VERIFY
+	}}
+	start := time.Now()
+	resp, err := client.Do(req)
+	item.Duration = time.Since(start)
+	if err != nil {
+		item.Status = StatusFail
+		item.Message = "cannot reach ARI at " + url
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			item.Message = "ARI did not respond within " + timeout.String()
+		}
+		item.Details = err.Error()
+		item.Remediation = "Check ASTERISK_HOST/ASTERISK_ARI_PORT in .env and that Asterisk's HTTP server (http.conf) is enabled and reachable"
+		return item
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		item.Status = StatusPass
+		item.Message = fmt.Sprintf("reachable (%dms)", item.Duration.Milliseconds())
+		item.Details = "url=" + url
+	case http.StatusUnauthorized:
+		item.Status = StatusFail
+		item.Message = "ARI rejected the credentials (HTTP 401)"
+		item.Details = "url=" + url
+		item.Remediation = "Check ASTERISK_ARI_USERNAME/ASTERISK_ARI_PASSWORD in .env against ari.conf"
+	default:
+		item.Status = StatusFail
+		item.Message = fmt.Sprintf("unexpected HTTP %d from ARI", resp.StatusCode)
+		item.Details = "url=" + url
+		item.Remediation = "Check that ARI is enabled (ari.conf: enabled = yes) and the port serves ARI"
+	}
+	return item
+}
+
+// hostEnvPath locates .env on the host: Runner.EnvFile, then ./.env, then <git toplevel>/.env
+// (.env.<Runner.Env> for a named environment).
+// When only <path>.age exists, secrets.LoadEnv reads that instead.
+func (r *Runner) hostEnvPath() string {
+	if r.EnvFile != "" {
+		return r.EnvFile
+	}
+	return r.repoPath(environment.Path(".env", r.Env))
+}
+
+// repoPath resolves rel against the working directory, falling back to the git toplevel.
+func (r *Runner) repoPath(rel string) string {
+	if _, err := os.Stat(rel); err == nil {
+		return rel
+	}
+	if out, err := r.command("git", "rev-parse", "--show-toplevel").Output(); err == nil {
+		if root := strings.TrimSpace(string(out)); root != "" {
+			return filepath.Join(root, filepath.FromSlash(rel))
+		}
+	}
+	return rel
+}
@@ -0,0 +1,58 @@
+package check
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeARIEnv(t *testing.T, hostport string) string {
+	t.Helper()
+	host, port, err := net.SplitHostPort(hostport)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), ".env")
+	content := "ASTERISK_HOST=" + host + "\nASTERISK_ARI_PORT=" + port + "\nASTERISK_ARI_USERNAME=ari\nASTERISK_ARI_PASSWORD=secret\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestCheckARI(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		user, pass, ok := req.BasicAuth()
+		if req.URL.Path != "/ari/api-docs/resources.json" || !ok || user != "ari" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	r := &Runner{EnvFile: writeARIEnv(t, srv.Listener.Addr().String())}
+	item := r.CheckARI()
+	if item.Status != StatusPass || item.Duration <= 0 {
+		t.Fatalf("expected pass with duration, got %+v", item)
+	}
+}
+
+func TestCheckARIUnreachable(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	_ = ln.Close()
+
+	r := &Runner{EnvFile: writeARIEnv(t, addr), ARITimeout: time.Second}
+	item := r.CheckARI()
+	if item.Status != StatusFail || item.Details == "" {
+		t.Fatalf("expected fail with details, got %+v", item)
+	}
+}
@@ -0,0 +1,48 @@
+package check
+
+import (
+	"path/filepath"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/backup"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/health"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/secrets"
+)
+
+// checkBackupEncryption checks what encrypted backups need once AGENT_BACKUP_ENCRYPT_KEY is set:
+// a readable AGENT_BACKUP_IDENTITY_FILE for that key, which signs new sets and decrypts them
+// on restore.
+func (r *Runner) checkBackupEncryption() Item {
+	envMap, _, _ := secrets.LoadEnv(r.hostEnvPath())
+	key := EnvValue(health.GetEnv(backup.EncryptKeyEnv, envMap))
+	identity := EnvValue(health.GetEnv(backup.IdentityFileEnv, envMap))
+	if identity != "" && !filepath.IsAbs(identity) {
+		identity = r.repoPath(identity)
+	}
+	return checkBackupEncryption(key, identity)
+}
+
+func checkBackupEncryption(key, identity string) Item {
+	item := Item{Name: "Backup Encryption"}
+	if key == "" {
+		item.Status = StatusSkip
+		item.Message = backup.EncryptKeyEnv + " not set; backups are not encrypted"
+		return item
+	}
+	if identity == "" {
+		item.Status = StatusFail
+		item.Message = backup.IdentityFileEnv + " not set; new backups cannot be signed"
+		item.Remediation = "Set " + backup.IdentityFileEnv + " in .env to the age identity file of " + backup.EncryptKeyEnv
+		return item
+	}
+	if err := backup.CheckIdentity(identity, key); err != nil {
+		item.Status = StatusFail
+		item.Message = "backup identity file unusable"
+		item.Details = err.Error()
+		item.Remediation = "Point " + backup.IdentityFileEnv + " at the identity file age-keygen wrote for " + backup.EncryptKeyEnv
+		return item
+	}
+	item.Status = StatusPass
+	item.Message = "identity matches " + backup.EncryptKeyEnv
+	item.Details = "identity=" + identity
+	return item
+}
@@ -0,0 +1,23 @@
+package check
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCheckBackupEncryption(t *testing.T) {
+	if item := checkBackupEncryption("", ""); item.Status != StatusSkip {
+		t.Fatalf("no key: %+v", item)
+	}
+	if item := checkBackupEncryption("age1x", ""); item.Status != StatusFail {
+		t.Fatalf("no identity: %+v", item)
+	}
+	identity := filepath.Join(t.TempDir(), "identity.txt")
+	if err := os.WriteFile(identity, []byte("# public key: age1x\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if item := checkBackupEncryption("age1x", identity); item.Status != StatusFail {
+		t.Fatalf("identity without a secret key: %+v", item)
+	}
+}
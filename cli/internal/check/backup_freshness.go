@@ -0,0 +1,117 @@
+package check
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/backup"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/environment"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/health"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/secrets"
+)
+
+// DefaultBackupMaxAge is how old the newest update backup may get before Backup Freshness
+// warns, unless AGENT_BACKUP_MAX_AGE says otherwise. It fails at four times the limit.
+const DefaultBackupMaxAge = 7 * 24 * time.Hour
+
+// now is replaced in tests.
+var now = time.Now
+
+// CheckBackupFreshness checks the age (modification time) of the newest backup directory under
+// <root>/.agent/update-backups: older than maxAge warns and older than 4*maxAge fails, since a set that
+// old is unlikely to restore a config anyone still wants. No backups at all is a skip.
+func CheckBackupFreshness(root string, maxAge time.Duration) Item {
+	return checkBackupDir(filepath.Join(root, ".agent", "update-backups"), maxAge)
+}
+
+func (r *Runner) checkBackupFreshness() Item {
+	envMap, _, _ := secrets.LoadEnv(r.hostEnvPath()) // .env.age is decrypted in memory
+	maxAge := DefaultBackupMaxAge
+	if v := EnvValue(health.GetEnv("AGENT_BACKUP_MAX_AGE", envMap)); v != "" {
+		d, err := parseMaxAge(v)
+		if err != nil {
+			return Item{
+				Name:        "Backup Freshness",
+				Status:      StatusWarn,
+				Message:     "invalid AGENT_BACKUP_MAX_AGE " + strconv.Quote(v),
+				Details:     err.Error(),
+				Remediation: "Set AGENT_BACKUP_MAX_AGE in .env to a number of days (7d) or a duration (36h)",
+			}
+		}
+		maxAge = d
+	}
+	// Named environments keep their backups under .agent/envs/<name>, as agent backup does.
+	rel := filepath.Join(".agent", "update-backups")
+	if r.Env != "" && r.Env != environment.Default {
+		rel = filepath.Join(".agent", "envs", r.Env, "update-backups")
+	}
+	return checkBackupDir(r.repoPath(rel), maxAge)
+}
+
+// parseMaxAge parses AGENT_BACKUP_MAX_AGE: a whole number of days with an optional d suffix
+// (7, 7d) or a Go duration (36h). It must be positive.
+func parseMaxAge(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	var d time.Duration
+	if n, err := strconv.Atoi(strings.TrimSuffix(s, "d")); err == nil {
+		d = time.Duration(n) * 24 * time.Hour
+	} else if d, err = time.ParseDuration(s); err != nil {
+		return 0, fmt.Errorf("not a number of days or a duration: %q", s)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("must be positive: %q", s)
+	}
+	return d, nil
+}
+
+func checkBackupDir(dir string, maxAge time.Duration) Item {
+	item := Item{Name: "Backup Freshness"}
+	latest, err := backup.LatestBackupDir(dir)
+	if err != nil {
+		item.Status = StatusWarn
+		item.Message = "failed to list update backups"
+		item.Details = err.Error()
+		item.Remediation = "Check the permissions on " + dir
+		return item
+	}
+	if latest == "" {
+		item.Status = StatusSkip
+		item.Message = "no update backups yet (agent update or agent backup create makes one)"
+		return item
+	}
+	info, err := os.Stat(latest)
+	if err != nil {
+		item.Status = StatusWarn
+		item.Message = "failed to read the newest update backup"
+		item.Details = err.Error()
+		return item
+	}
+
+	age := now().Sub(info.ModTime())
+	item.Details = fmt.Sprintf("newest=%s modified=%s max_age=%s", filepath.Base(latest), info.ModTime().Format(time.RFC3339), formatAge(maxAge))
+	switch {
+	case age > 4*maxAge:
+		item.Status = StatusFail
+	case age > maxAge:
+		item.Status = StatusWarn
+	default:
+		item.Status = StatusPass
+		item.Message = "newest update backup is " + formatAge(age) + " old"
+		return item
+	}
+	item.Message = fmt.Sprintf("newest update backup is %s old (max %s)", formatAge(age), formatAge(maxAge))
+	item.Remediation = "Take a fresh backup with: agent backup create (or schedule one: agent backup schedule --interval daily)"
+	return item
+}
+
+// formatAge renders d in whole days when it is at least a day, else rounded to the minute.
+func formatAge(d time.Duration) string {
+	if d >= 24*time.Hour {
+		return plural(int(d/(24*time.Hour)), "day")
+	}
+	return d.Round(time.Minute).String()
+}
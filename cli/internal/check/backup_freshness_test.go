@@ -0,0 +1,61 @@
+package check
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCheckBackupFreshness(t *testing.T) {
+	fixed := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	now = func() time.Time { return fixed }
+	t.Cleanup(func() { now = time.Now })
+
+	root := t.TempDir()
+	if item := CheckBackupFreshness(root, DefaultBackupMaxAge); item.Status != StatusSkip {
+		t.Fatalf("no backups: %+v", item)
+	}
+
+	backups := filepath.Join(root, ".agent", "update-backups")
+	mkset := func(name string, age time.Duration) {
+		t.Helper()
+		dir := filepath.Join(backups, name)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		mt := fixed.Add(-age)
+		if err := os.Chtimes(dir, mt, mt); err != nil {
+			t.Fatal(err)
+		}
+	}
+	mkset("20260101-120000", 59*24*time.Hour)
+	if item := CheckBackupFreshness(root, DefaultBackupMaxAge); item.Status != StatusFail || item.Remediation == "" {
+		t.Fatalf("59 days old: %+v", item)
+	}
+	mkset("20260215-120000", 14*24*time.Hour)
+	item := CheckBackupFreshness(root, DefaultBackupMaxAge)
+	if item.Status != StatusWarn || item.Message != "newest update backup is 14 days old (max 7 days)" {
+		t.Fatalf("14 days old: %+v", item)
+	}
+	if item := CheckBackupFreshness(root, 30*24*time.Hour); item.Status != StatusPass {
+		t.Fatalf("14 days old, max 30: %+v", item)
+	}
+	mkset("20260301-090000", 3*time.Hour)
+	if item := CheckBackupFreshness(root, DefaultBackupMaxAge); item.Status != StatusPass || item.Message != "newest update backup is 3h0m0s old" {
+		t.Fatalf("3 hours old: %+v", item)
+	}
+}
+
+func TestParseMaxAge(t *testing.T) {
+	for in, want := range map[string]time.Duration{"7": 7 * 24 * time.Hour, "30d": 30 * 24 * time.Hour, "36h": 36 * time.Hour} {
+		if got, err := parseMaxAge(in); err != nil || got != want {
+			t.Errorf("parseMaxAge(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"", "0", "-1d", "week"} {
+		if _, err := parseMaxAge(in); err == nil {
+			t.Errorf("parseMaxAge(%q) succeeded", in)
+		}
+	}
+}
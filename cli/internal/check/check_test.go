@@ -0,0 +1,50 @@
+package check
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReportSummarize(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name                   string
+		pass, warn, fail, skip int
+		want                   string
+		color                  string
+	}{
+		{"all passed", 12, 0, 0, 0, "✓ all 12 checks passed", "32"},
+		{"all passed with skips", 10, 0, 0, 2, "✓ all 10 checks passed, 2 skipped", "32"},
+		{"one check", 1, 0, 0, 0, "✓ 1 check passed", "32"},
+		{"empty", 0, 0, 0, 0, "✓ no checks failed", "32"},
+		{"one warning", 11, 1, 0, 0, "⚠ 1 warning", "33"},
+		{"warnings", 10, 2, 0, 0, "⚠ 2 warnings", "33"},
+		{"one failure", 11, 0, 1, 0, "✗ 1 failure", "31"},
+		{"failures", 9, 0, 3, 0, "✗ 3 failures", "31"},
+		{"failure and warning", 10, 1, 1, 0, "✗ 1 failure, 1 warning", "31"},
+		{"failures and warning", 8, 1, 3, 0, "✗ 3 failures, 1 warning", "31"},
+		{"failures and warnings", 6, 2, 3, 1, "✗ 3 failures, 2 warnings", "31"},
+	}
+	for _, tc := range cases {
+		var items []Item
+		for status, n := range map[Status]int{StatusPass: tc.pass, StatusWarn: tc.warn, StatusFail: tc.fail, StatusSkip: tc.skip} {
+			for i := 0; i < n; i++ {
+				items = append(items, Item{Name: string(status), Status: status})
+			}
+		}
+		items = append(items, Item{Name: "demoted", Status: StatusInfo}) // never counted
+		r := &Report{Items: items}
+
+		got := r.Summarize()
+		if got != tc.want {
+			t.Errorf("%s: Summarize() = %q, want %q", tc.name, got, tc.want)
+		}
+		if strings.Contains(got, "\x1b") {
+			t.Errorf("%s: Summarize() contains ANSI escapes: %q", tc.name, got)
+		}
+		if colored, want := r.SummarizeColored(), "\x1b["+tc.color+"m"+tc.want+"\x1b[0m"; colored != want {
+			t.Errorf("%s: SummarizeColored() = %q, want %q", tc.name, colored, want)
+		}
+	}
+}
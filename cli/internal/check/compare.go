@@ -0,0 +1,143 @@
+package check
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// ReportDiff lists the items whose failing or warning state changed between two reports (see
+// DiffReports). Each item is taken from the head report with PreviousStatus set to its status
+// in the base report.
+type ReportDiff struct {
+	// NewFailures fail in head and did not in base (including items base does not have).
+	NewFailures []Item `json:"new_failures"`
+	// ResolvedFailures failed in base and no longer do in head; they may still warn.
+	ResolvedFailures []Item `json:"resolved_failures"`
+	// NewWarnings warn in head and neither warned nor failed in base.
+	NewWarnings []Item `json:"new_warnings"`
+	// ResolvedWarnings warned in base and neither warn nor fail in head.
+	ResolvedWarnings []Item `json:"resolved_warnings"`
+
+	BaseTimestamp time.Time `json:"base_timestamp"`
+	HeadTimestamp time.Time `json:"head_timestamp"`
+}
+
+// DiffReports compares head with base, matching items by name, for regression testing across
+// commits (agent check --compare-to). Items only in base are ignored, since head did not run
+// them; items only in head count as previously passing, like CompareWith. Demoted (info) and
+// skipped items count as neither warning nor failing.
+func DiffReports(base, head *Report) ReportDiff {
+	// Empty lists rather than nil so the JSON output always has arrays.
+	d := ReportDiff{NewFailures: []Item{}, ResolvedFailures: []Item{}, NewWarnings: []Item{}, ResolvedWarnings: []Item{}}
+	before := map[string]Status{}
+	if base != nil {
+		d.BaseTimestamp = base.Timestamp
+		for _, item := range base.Items {
+			before[item.Name] = item.Status
+		}
+	}
+	if head == nil {
+		return d
+	}
+	d.HeadTimestamp = head.Timestamp
+	for _, item := range head.Items {
+		was, ok := before[item.Name]
+		if !ok {
+			was = StatusPass
+		}
+		if item.Status == was {
+			continue
+		}
+		item.PreviousStatus = was
+		switch {
+		case item.Status == StatusFail:
+			d.NewFailures = append(d.NewFailures, item)
+		case was == StatusFail:
+			d.ResolvedFailures = append(d.ResolvedFailures, item)
+		case item.Status == StatusWarn:
+			d.NewWarnings = append(d.NewWarnings, item)
+		case was == StatusWarn:
+			d.ResolvedWarnings = append(d.ResolvedWarnings, item)
+		}
+	}
+	return d
+}
+
+// Empty reports whether nothing changed.
+func (d ReportDiff) Empty() bool {
+	return len(d.NewFailures)+len(d.ResolvedFailures)+len(d.NewWarnings)+len(d.ResolvedWarnings) == 0
+}
+
+// ExitCode is 2 when there are new failures, 1 when there are only new warnings and 0
+// otherwise, whatever failed in both reports.
+func (d ReportDiff) ExitCode() int {
+	switch {
+	case len(d.NewFailures) > 0:
+		return StatusFail.ExitCode()
+	case len(d.NewWarnings) > 0:
+		return StatusWarn.ExitCode()
+	default:
+		return 0
+	}
+}
+
+// OutputJSON writes d as indented JSON.
+func (d ReportDiff) OutputJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(d)
+}
+
+// OutputText prints the changed items, regressions (↑) before improvements (↓), and a
+// one-line count. baseName names the base report in the heading.
+func (d ReportDiff) OutputText(w io.Writer, baseName string) {
+	yellow := color.New(color.FgYellow, color.Bold).SprintFunc()
+	red := color.New(color.FgRed, color.Bold).SprintFunc()
+	green := color.New(color.FgGreen, color.Bold).SprintFunc()
+	gray := color.New(color.FgHiBlack).SprintFunc()
+
+	since := baseName
+	if !d.BaseTimestamp.IsZero() {
+		since += " (" + d.BaseTimestamp.Format(time.RFC3339) + ")"
+	}
+	fmt.Fprintln(w)
+	if d.Empty() {
+		fmt.Fprintf(w, "No status changes since %s\n\n", since)
+		return
+	}
+	fmt.Fprintf(w, "Status changes since %s:\n", since)
+	list := func(items []Item, label string, paint func(a ...interface{}) string, details bool) {
+		for _, item := range items {
+			fmt.Fprintf(w, "  %s %s: %s %s\n", paint(fmt.Sprintf("%-15s", label)), item.Name, item.Message, gray("(was "+string(item.PreviousStatus)+")"))
+			if details && item.Details != "" {
+				fmt.Fprintf(w, "      %s\n", gray(item.Details))
+			}
+			if details && item.Remediation != "" {
+				fmt.Fprintf(w, "      %s %s\n", yellow("Remediation:"), item.Remediation)
+			}
+		}
+	}
+	list(d.NewFailures, "↑ new failure", red, true)
+	list(d.NewWarnings, "↑ new warning", yellow, true)
+	list(d.ResolvedFailures, "↓ resolved", green, false)
+	list(d.ResolvedWarnings, "↓ resolved", green, false)
+
+	var counts []string
+	if n := len(d.NewFailures); n > 0 {
+		counts = append(counts, plural(n, "new failure"))
+	}
+	if n := len(d.NewWarnings); n > 0 {
+		counts = append(counts, plural(n, "new warning"))
+	}
+	if n := len(d.ResolvedFailures) + len(d.ResolvedWarnings); n > 0 {
+		counts = append(counts, fmt.Sprintf("%d resolved", n))
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, strings.Join(counts, ", "))
+	fmt.Fprintln(w)
+}
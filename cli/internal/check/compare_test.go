@@ -0,0 +1,73 @@
+package check
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestDiffReports(t *testing.T) {
+	base := &Report{Items: []Item{
+		{Name: "Docker CLI", Status: StatusPass},
+		{Name: "ARI", Status: StatusFail},
+		{Name: "Env", Status: StatusWarn},
+		{Name: "Config", Status: StatusWarn},
+		{Name: "Dialplan", Status: StatusFail},
+		{Name: "Internet/DNS", Status: StatusFail},
+		{Name: "Removed Check", Status: StatusFail},
+	}}
+	head := &Report{Items: []Item{
+		{Name: "Docker CLI", Status: StatusFail, Message: "docker not found"},
+		{Name: "ARI", Status: StatusPass, Message: "connected"},
+		{Name: "Env", Status: StatusPass},
+		{Name: "Config", Status: StatusFail},
+		{Name: "Dialplan", Status: StatusWarn},
+		{Name: "Internet/DNS", Status: StatusFail},
+		{Name: "New Check", Status: StatusWarn},
+	}}
+	d := DiffReports(base, head)
+	names := func(items []Item) string {
+		var out []string
+		for _, item := range items {
+			out = append(out, item.Name+"<"+string(item.PreviousStatus))
+		}
+		return strings.Join(out, ",")
+	}
+	for label, got := range map[string][2]string{
+		"NewFailures":      {names(d.NewFailures), "Docker CLI<pass,Config<warn"},
+		"ResolvedFailures": {names(d.ResolvedFailures), "ARI<fail,Dialplan<fail"},
+		"NewWarnings":      {names(d.NewWarnings), "New Check<pass"},
+		"ResolvedWarnings": {names(d.ResolvedWarnings), "Env<warn"},
+	} {
+		if got[0] != got[1] {
+			t.Errorf("%s = %q, want %q", label, got[0], got[1])
+		}
+	}
+	if d.ExitCode() != 2 {
+		t.Fatalf("ExitCode = %d, want 2", d.ExitCode())
+	}
+
+	var buf bytes.Buffer
+	d.OutputText(&buf, "base.json")
+	out := buf.String()
+	for _, want := range []string{"↑ new failure   Docker CLI: docker not found (was pass)", "↓ resolved      ARI: connected (was fail)", "2 new failures, 1 new warning, 3 resolved"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output lacks %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "Internet/DNS") {
+		t.Errorf("unchanged item listed:\n%s", out)
+	}
+}
+
+func TestReportDiffExitCode(t *testing.T) {
+	warnOnly := DiffReports(&Report{}, &Report{Items: []Item{{Name: "Env", Status: StatusWarn}}})
+	if warnOnly.ExitCode() != 1 {
+		t.Fatalf("new warnings only: ExitCode = %d, want 1", warnOnly.ExitCode())
+	}
+	stillFailing := &Report{Items: []Item{{Name: "ARI", Status: StatusFail}}}
+	same := DiffReports(stillFailing, stillFailing)
+	if !same.Empty() || same.ExitCode() != 0 {
+		t.Fatalf("unchanged failure: %+v, ExitCode = %d", same, same.ExitCode())
+	}
+}
@@ -0,0 +1,148 @@
+package check
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// composeCoreServices are the services whose images CheckImagePullability resolves; they are
+// the ones agent check --fix and agent update restart.
+var composeCoreServices = []string{"ai_engine", "admin_ui"}
+
+// composeUnavailable is the item returned when docker or its compose plugin is missing.
+func composeUnavailable(name string, err error) Item {
+	return Item{
+		Name:        name,
+		Status:      StatusWarn,
+		Message:     "docker compose unavailable",
+		Details:     errString(err),
+		Remediation: "Install Docker with the Compose v2 plugin (see docs/INSTALLATION.md)",
+	}
+}
+
+// composeAvailable runs `docker compose version` to tell a missing plugin from a bad file.
+func composeAvailable(ctx context.Context) error {
+	if _, err := exec.LookPath("docker"); err != nil {
+		return err
+	}
+	out, err := exec.CommandContext(ctx, "docker", "compose", "version", "--short").CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// CheckComposeFile validates the Compose project in root (docker-compose.yml plus any override
+// and .env interpolation) with `docker compose config --quiet`, so a broken file is caught
+// before a restore or update tries to bring services up with it.
+func CheckComposeFile(root string) Item {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	return checkComposeFile(ctx, root)
+}
+
+func checkComposeFile(ctx context.Context, root string) Item {
+	const name = "Compose File"
+	if err := composeAvailable(ctx); err != nil {
+		return composeUnavailable(name, err)
+	}
+	cmd := exec.CommandContext(ctx, "docker", "compose", "config", "--quiet")
+	cmd.Dir = root
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return Item{
+			Name:        name,
+			Status:      StatusFail,
+			Message:     "docker compose config failed",
+			Details:     truncateOutput(strings.TrimSpace(string(out))),
+			Remediation: "Fix the error reported by `docker compose config` in docker-compose.yml (run it from " + root + ")",
+		}
+	}
+	return Item{Name: name, Status: StatusPass, Message: "valid", Details: "dir=" + root}
+}
+
+// CheckImagePullability resolves the registry images of the core services with
+// `docker compose pull --dry-run` (Docker Compose v2.20+), which contacts the registry without
+// downloading layers. Images built locally are ignored. Authentication and connectivity errors
+// are warnings: the stack keeps running from the images already on the host, but a restore or
+// update that needs to pull would fail.
+func CheckImagePullability(root string) Item {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	return checkImagePullability(ctx, root)
+}
+
+func checkImagePullability(ctx context.Context, root string) Item {
+	const name = "Image Pullability"
+	if err := composeAvailable(ctx); err != nil {
+		return composeUnavailable(name, err)
+	}
+	args := append([]string{"compose", "pull", "--dry-run", "--ignore-buildable"}, composeCoreServices...)
+	cmd := exec.CommandContext(ctx, "docker", args...)
+	cmd.Dir = root
+	out, err := cmd.CombinedOutput()
+	text := strings.TrimSpace(string(out))
+	if err == nil {
+		return Item{Name: name, Status: StatusPass, Message: "core service images resolvable", Details: "services=" + strings.Join(composeCoreServices, ",")}
+	}
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return Item{Name: name, Status: StatusWarn, Message: "registry did not answer in time", Details: truncateOutput(text), Remediation: "Check outbound HTTPS access to the image registries"}
+	}
+
+	lower := strings.ToLower(text)
+	item := Item{Name: name, Status: StatusWarn, Details: truncateOutput(text)}
+	switch {
+	case strings.Contains(lower, "unknown flag") || strings.Contains(lower, "unknown shorthand flag"):
+		return Item{Name: name, Status: StatusSkip, Message: "needs Docker Compose v2.20+ (pull --dry-run)", Details: truncateOutput(text)}
+	case containsAny(lower, "unauthorized", "denied", "authentication required", "no basic auth credentials"):
+		item.Message = "registry authentication failed"
+		item.Remediation = "Run `docker login <registry>` as the user that runs docker compose"
+	case containsAny(lower, "no such host", "timeout", "connection refused", "network is unreachable", "tls handshake"):
+		item.Message = "registry unreachable"
+		item.Remediation = "Check DNS and outbound HTTPS access from this host to the image registries"
+	case containsAny(lower, "manifest unknown", "not found", "does not exist"):
+		item.Message = "image not found in registry"
+		item.Remediation = "Check the image names and tags in docker-compose.yml"
+	default:
+		item.Message = "docker compose pull --dry-run failed"
+		item.Remediation = "Run `docker compose pull --dry-run` in " + root + " for the full error"
+	}
+	return item
+}
+
+// CheckComposeFile and CheckImagePullability bound to the run's context and repo root.
+func (r *Runner) checkComposeFile() Item {
+	return checkComposeFile(r.runContext(), r.composeRoot())
+}
+
+func (r *Runner) checkImagePullability() Item {
+	return checkImagePullability(r.runContext(), r.composeRoot())
+}
+
+func (r *Runner) composeRoot() string {
+	root := filepath.Dir(r.repoPath("docker-compose.yml"))
+	if abs, err := filepath.Abs(root); err == nil {
+		return abs
+	}
+	return root
+}
+
+func (r *Runner) runContext() context.Context {
+	if r.ctx == nil {
+		return context.Background()
+	}
+	return r.ctx
+}
+
+func containsAny(s string, subs ...string) bool {
+	for _, sub := range subs {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+package check
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// fakeDocker puts a docker script on PATH that handles `docker compose version` and runs body
+// for every other invocation, logging its arguments to the returned file.
+func fakeDocker(t *testing.T, body string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake docker is a shell script")
+	}
+	dir := t.TempDir()
+	argsLog := filepath.Join(dir, "args")
+	script := "#!/bin/sh\n" +
+		`if [ "$1 $2" = "compose version" ]; then echo v2.29.1; exit 0; fi` + "\n" +
+		`echo "$@" >> "` + argsLog + `"` + "\n" + body + "\n"
+	if err := os.WriteFile(filepath.Join(dir, "docker"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir)
+	return argsLog
+}
+
+func TestComposeChecksWithoutDocker(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	for _, item := range []Item{CheckComposeFile(t.TempDir()), CheckImagePullability(t.TempDir())} {
+		if item.Status != StatusWarn || item.Message != "docker compose unavailable" {
+			t.Errorf("%s: %+v", item.Name, item)
+		}
+	}
+}
+
+func TestCheckComposeFile(t *testing.T) {
+	root := t.TempDir()
+	argsLog := fakeDocker(t, `exit 0`)
+	if item := CheckComposeFile(root); item.Status != StatusPass || item.Name != "Compose File" {
+		t.Fatalf("valid file: %+v", item)
+	}
+	if data, _ := os.ReadFile(argsLog); strings.TrimSpace(string(data)) != "compose config --quiet" {
+		t.Fatalf("args = %q", data)
+	}
+
+	fakeDocker(t, `echo "services.ai_engine Additional property imagee is not allowed" >&2; exit 15`)
+	item := CheckComposeFile(root)
+	if item.Status != StatusFail || !strings.Contains(item.Details, "imagee") {
+		t.Fatalf("invalid file: %+v", item)
+	}
+}
+
+func TestCheckImagePullability(t *testing.T) {
+	root := t.TempDir()
+	argsLog := fakeDocker(t, `exit 0`)
+	if item := CheckImagePullability(root); item.Status != StatusPass {
+		t.Fatalf("pullable: %+v", item)
+	}
+	if data, _ := os.ReadFile(argsLog); strings.TrimSpace(string(data)) != "compose pull --dry-run --ignore-buildable ai_engine admin_ui" {
+		t.Fatalf("args = %q", data)
+	}
+
+	for _, c := range []struct {
+		output  string
+		status  Status
+		message string
+	}{
+		{"Error response from daemon: Head \"https://ghcr.io/v2/x/manifests/1\": unauthorized", StatusWarn, "registry authentication failed"},
+		{"dial tcp: lookup registry-1.docker.io: no such host", StatusWarn, "registry unreachable"},
+		{"manifest unknown: manifest tagged by \"9.9\" is not found", StatusWarn, "image not found in registry"},
+		{"unknown flag: --dry-run", StatusSkip, "needs Docker Compose v2.20+ (pull --dry-run)"},
+	} {
+		fakeDocker(t, `echo '`+c.output+`' >&2; exit 1`)
+		item := CheckImagePullability(root)
+		if item.Status != c.status || item.Message != c.message || item.Details != c.output {
+			t.Errorf("%q: %+v", c.output, item)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package check
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/environment"
+)
+
+// ValidationResult is the outcome of validating one operator config file.
+type ValidationResult struct {
+	File    string `json:"file"`
+	Status  Status `json:"status"`
+	Message string `json:"message"`
+}
+
+// ValidateConfigSet runs the recovery validators against the live operator config under root
+// (.env, config/ai-agent.yaml, config/ai-agent.local.yaml, config/users.json, config/contexts/*.yaml).
+// For an environment other than environment.Default the files carry its suffix (.env.<env>);
+// the base config falls back to the shared config/ai-agent.yaml and contexts are shared.
+// It is read-only: nothing is written, moved, or fixed.
+func ValidateConfigSet(root, env string) ([]ValidationResult, error) {
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read config root %s: %w", root, err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("config root %s is not a directory", root)
+	}
+
+	var results []ValidationResult
+	add := func(rel string, st Status, msg string) {
+		results = append(results, ValidationResult{File: rel, Status: st, Message: msg})
+	}
+	validate := func(rel string, validator func(string) error, missing Status, missingMsg string) {
+		path := filepath.Join(root, rel)
+		if _, err := os.Stat(path); err != nil {
+			if os.IsNotExist(err) {
+				add(rel, missing, missingMsg)
+				return
+			}
+			add(rel, StatusFail, err.Error())
+			return
+		}
+		if HasConflictMarkers(path) {
+			add(rel, StatusFail, "contains git conflict markers")
+			return
+		}
+		if err := validator(path); err != nil {
+			add(rel, StatusFail, err.Error())
+			return
+		}
+		add(rel, StatusPass, "ok")
+	}
+
+	base := filepath.Join("config", "ai-agent.yaml")
+	if rel := environment.Path(base, env); rel != base {
+		if _, err := os.Stat(filepath.Join(root, rel)); err == nil {
+			base = rel
+		}
+	}
+	validate(environment.Path(".env", env), ValidateEnv, StatusFail, "missing (copy .env.example and run agent setup)")
+	validate(base, ValidateYAMLMapping, StatusFail, "missing (restore from git: git checkout -- config/ai-agent.yaml)")
+	validate(environment.Path(filepath.Join("config", "ai-agent.local.yaml"), env), ValidateYAMLMapping, StatusSkip, "not present (optional)")
+	validate(environment.Path(filepath.Join("config", "users.json"), env), ValidateUsersJSON, StatusWarn, "not present (Admin UI will recreate the default admin/admin login)")
+
+	ctxDir := filepath.Join(root, "config", "contexts")
+	entries, err := os.ReadDir(ctxDir)
+	if err != nil && !os.IsNotExist(err) {
+		add(filepath.Join("config", "contexts"), StatusFail, err.Error())
+	}
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		ext := strings.ToLower(filepath.Ext(e.Name()))
+		if ext == ".yaml" || ext == ".yml" {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		validate(filepath.Join("config", "contexts", name), ValidateYAMLMapping, StatusFail, "missing")
+	}
+	return results, nil
+}
@@ -0,0 +1,62 @@
+package check
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ContextsDir holds the per-context YAML files the engine merges into `contexts` at startup.
+const ContextsDir = "config/contexts"
+
+// CheckContextFiles validates every config/contexts/*.yaml (and *.yml) on the host. The engine
+// silently skips files it cannot parse, so a hand-edited context with a syntax error otherwise
+// only shows up as a missing context at call time.
+func (r *Runner) CheckContextFiles() Item {
+	return checkContextDir(r.repoPath(ContextsDir))
+}
+
+func checkContextDir(dir string) Item {
+	item := Item{Name: "Context Files"}
+
+	var files []string
+	for _, pattern := range []string{"*.yaml", "*.yml"} {
+		matches, err := filepath.Glob(filepath.Join(dir, pattern))
+		if err != nil {
+			item.Status = StatusFail
+			item.Message = "failed to list context files"
+			item.Details = err.Error()
+			item.Remediation = "Check the permissions on " + ContextsDir
+			return item
+		}
+		files = append(files, matches...)
+	}
+	sort.Strings(files)
+
+	if len(files) == 0 {
+		item.Status = StatusWarn
+		item.Message = "no context files found"
+		item.Details = "dir=" + dir
+		item.Remediation = "Add a context under " + ContextsDir + "/ (see demo-project-expert.yaml) or define contexts inline in config/ai-agent.yaml"
+		return item
+	}
+
+	var invalid []string
+	for _, path := range files {
+		if err := ValidateYAMLMapping(path); err != nil {
+			invalid = append(invalid, fmt.Sprintf("%s: %v", filepath.Base(path), err))
+		}
+	}
+	if len(invalid) > 0 {
+		item.Status = StatusFail
+		item.Message = fmt.Sprintf("%d of %d context file(s) invalid", len(invalid), len(files))
+		item.Details = strings.Join(invalid, "; ")
+		item.Remediation = "Fix the YAML syntax in the listed files; the engine skips contexts it cannot parse"
+		return item
+	}
+
+	item.Status = StatusPass
+	item.Message = fmt.Sprintf("%d context file(s) valid", len(files))
+	return item
+}
@@ -0,0 +1,30 @@
+package check
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckContextDir(t *testing.T) {
+	dir := t.TempDir()
+	if item := checkContextDir(dir); item.Status != StatusWarn {
+		t.Fatalf("expected warn for empty dir, got %+v", item)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "sales.yaml"), []byte("name: sales\nprompt: hi\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if item := checkContextDir(dir); item.Status != StatusPass {
+		t.Fatalf("expected pass, got %+v", item)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "broken.yml"), []byte("name: [unterminated\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	item := checkContextDir(dir)
+	if item.Status != StatusFail || !strings.Contains(item.Details, "broken.yml") {
+		t.Fatalf("expected fail naming broken.yml, got %+v", item)
+	}
+}
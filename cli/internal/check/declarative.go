@@ -0,0 +1,246 @@
+package check
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/health"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/secrets"
+)
+
+// DeclarativeChecksPath is the optional file of declarative checks, relative to the repo root.
+const DeclarativeChecksPath = ".agent/checks.yaml"
+
+// Declarative check types.
+const (
+	DeclFileExists      = "file-exists"
+	DeclURLReachable    = "url-reachable"
+	DeclEnvSet          = "env-set"
+	DeclCommandExitZero = "command-exit-zero"
+)
+
+// declOutputLimit caps the command output kept in a failing command-exit-zero item.
+const declOutputLimit = 2000
+
+// DeclarativeCheck is one entry of a checks file:
+//
+//	checks:
+//	  - name: Recordings volume
+//	    type: file-exists
+//	    target: /mnt/recordings
+//	    severity: warn
+//	  - name: CRM webhook
+//	    type: url-reachable
+//	    target: https://crm.example.com/health
+//	  - name: Twilio key
+//	    type: env-set
+//	    target: TWILIO_API_KEY
+//	  - name: SIP trunk registered
+//	    type: command-exit-zero
+//	    target: docker exec asterisk asterisk -rx 'pjsip show registrations' | grep -q Registered
+//
+// Relative file-exists paths and commands run from the repo root. env-set looks in the host
+// .env (then the process environment). Severity is the status when the check does not pass:
+// fail (the default) or warn.
+type DeclarativeCheck struct {
+	CheckName string `yaml:"name"`
+	Type      string `yaml:"type"`
+	Target    string `yaml:"target"`
+	Severity  string `yaml:"severity"`
+
+	// root resolves relative targets; envFile is read by env-set.
+	root    string
+	envFile string
+}
+
+type declarativeFile struct {
+	Checks []DeclarativeCheck `yaml:"checks"`
+}
+
+// LoadDeclarativeChecks reads the checks file at path. A missing file yields no checks; an
+// invalid one (unknown type or severity, missing name or target, duplicate name) is an error
+// and none of its checks are returned.
+//
+// Targets are resolved against the repo root: the directory holding .agent/ when path is
+// inside one, else the directory of path.
+func LoadDeclarativeChecks(path string) ([]CheckPlugin, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var f declarativeFile
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	root := filepath.Dir(path)
+	if filepath.Base(root) == ".agent" {
+		root = filepath.Dir(root)
+	}
+	var (
+		checks []CheckPlugin
+		errs   []error
+	)
+	seen := map[string]bool{}
+	for i, c := range f.Checks {
+		c.CheckName = strings.TrimSpace(c.CheckName)
+		c.Type = strings.ToLower(strings.TrimSpace(c.Type))
+		c.Target = strings.TrimSpace(c.Target)
+		c.Severity = strings.ToLower(strings.TrimSpace(c.Severity))
+		if c.Severity == "" {
+			c.Severity = string(StatusFail)
+		}
+		label := fmt.Sprintf("checks[%d]", i)
+		if c.CheckName != "" {
+			label += " (" + c.CheckName + ")"
+		}
+		switch {
+		case c.CheckName == "":
+			errs = append(errs, fmt.Errorf("%s: name is required", label))
+		case seen[strings.ToLower(c.CheckName)]:
+			errs = append(errs, fmt.Errorf("%s: duplicate name", label))
+		}
+		seen[strings.ToLower(c.CheckName)] = true
+		switch c.Type {
+		case DeclFileExists, DeclURLReachable, DeclEnvSet, DeclCommandExitZero:
+		default:
+			errs = append(errs, fmt.Errorf("%s: unknown type %q (must be %s, %s, %s or %s)", label, c.Type, DeclFileExists, DeclURLReachable, DeclEnvSet, DeclCommandExitZero))
+		}
+		if c.Target == "" {
+			errs = append(errs, fmt.Errorf("%s: target is required", label))
+		}
+		if c.Severity != string(StatusFail) && c.Severity != string(StatusWarn) {
+			errs = append(errs, fmt.Errorf("%s: severity must be fail or warn, not %q", label, c.Severity))
+		}
+		c.root = root
+		c.envFile = filepath.Join(root, ".env")
+		checks = append(checks, &c)
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("%s: %w", path, errors.Join(errs...))
+	}
+	return checks, nil
+}
+
+func (c *DeclarativeCheck) Name() string { return c.CheckName }
+
+// Run performs the check; it is bounded by ctx (PluginTimeout when run by a Runner).
+func (c *DeclarativeCheck) Run(ctx context.Context) Item {
+	item := Item{Name: c.CheckName, Status: StatusPass}
+	var failure string
+	switch c.Type {
+	case DeclFileExists:
+		p := c.resolve(c.Target)
+		if _, err := os.Stat(p); err != nil {
+			failure = fmt.Sprintf("%s does not exist", c.Target)
+			item.Details = err.Error()
+		} else {
+			item.Message = c.Target + " exists"
+		}
+	case DeclURLReachable:
+		item.Details = "url=" + c.Target
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.Target, nil)
+		if err != nil {
+			failure = "invalid URL: " + err.Error()
+			break
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			failure = "unreachable: " + err.Error()
+			break
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= 400 {
+			failure = fmt.Sprintf("HTTP %d", resp.StatusCode)
+		} else {
+			item.Message = fmt.Sprintf("reachable (HTTP %d)", resp.StatusCode)
+		}
+	case DeclEnvSet:
+		envMap, _, _ := secrets.LoadEnv(c.envFile) // a missing .env leaves only the process environment
+		if EnvValue(health.GetEnv(c.Target, envMap)) == "" {
+			failure = c.Target + " is not set"
+			item.Remediation = fmt.Sprintf("Set %s in %s", c.Target, filepath.Base(c.envFile))
+		} else {
+			item.Message = c.Target + " is set"
+		}
+	case DeclCommandExitZero:
+		cmd := shellCommand(ctx, c.Target)
+		cmd.Dir = c.root
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			failure = "command failed: " + err.Error()
+			item.Details = truncateOutput(strings.TrimSpace(string(out)))
+		} else {
+			item.Message = "command exited 0"
+		}
+	}
+	if failure != "" {
+		item.Status = Status(c.Severity)
+		item.Message = failure
+		if ctx.Err() != nil {
+			item.Message += " (" + ctx.Err().Error() + ")"
+		}
+		if item.Remediation == "" {
+			item.Remediation = "See the " + c.CheckName + " entry in " + DeclarativeChecksPath
+		}
+	}
+	return item
+}
+
+func (c *DeclarativeCheck) resolve(p string) string {
+	if filepath.IsAbs(p) || c.root == "" {
+		return p
+	}
+	return filepath.Join(c.root, p)
+}
+
+func shellCommand(ctx context.Context, script string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", script)
+	}
+	return exec.CommandContext(ctx, "sh", "-c", script)
+}
+
+func truncateOutput(s string) string {
+	if len(s) > declOutputLimit {
+		return s[:declOutputLimit] + "\n... (truncated)"
+	}
+	return s
+}
+
+// loadDeclarative returns the checks in Runner.ChecksFile (default DeclarativeChecksPath
+// under the repo root), reading env-set values from the runner's host .env. A file that
+// fails to load yields a warning item instead.
+func (r *Runner) loadDeclarative() ([]CheckPlugin, []Item) {
+	path := r.ChecksFile
+	if path == "" {
+		path = r.repoPath(DeclarativeChecksPath)
+	}
+	checks, err := LoadDeclarativeChecks(path)
+	if err != nil {
+		return nil, []Item{{
+			Name:        "Check Definitions",
+			Status:      StatusWarn,
+			Message:     "ignoring invalid " + filepath.Base(path),
+			Details:     err.Error(),
+			Remediation: "Fix or remove " + path,
+		}}
+	}
+	envFile := r.hostEnvPath()
+	for _, c := range checks {
+		c.(*DeclarativeCheck).envFile = envFile
+	}
+	return checks, nil
+}
@@ -0,0 +1,124 @@
+package check
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func writeChecksFile(t *testing.T, root, content string) string {
+	t.Helper()
+	path := filepath.Join(root, filepath.FromSlash(DeclarativeChecksPath))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadDeclarativeChecksRejectsInvalidEntries(t *testing.T) {
+	path := writeChecksFile(t, t.TempDir(), `checks:
+  - name: A
+    type: file-exists
+    target: x
+  - name: a
+    type: ping
+    target: y
+  - name: C
+    type: env-set
+    severity: info
+`)
+	checks, err := LoadDeclarativeChecks(path)
+	if err == nil {
+		t.Fatalf("expected an error, got %d checks", len(checks))
+	}
+	for _, want := range []string{"duplicate name", `unknown type "ping"`, "target is required", `severity must be fail or warn, not "info"`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %q", err, want)
+		}
+	}
+
+	if checks, err := LoadDeclarativeChecks(filepath.Join(t.TempDir(), "missing.yaml")); err != nil || checks != nil {
+		t.Fatalf("missing file: checks=%v err=%v", checks, err)
+	}
+}
+
+func TestDeclarativeChecksRun(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/down" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "present.txt"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, ".env"), []byte("DECL_TEST_SET=\"yes\"\nDECL_TEST_EMPTY=\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	exitOne := "exit 1"
+	if runtime.GOOS == "windows" {
+		exitOne = "exit /b 1"
+	}
+	path := writeChecksFile(t, root, `checks:
+  - {name: Present, type: file-exists, target: present.txt}
+  - {name: Missing, type: file-exists, target: missing.txt, severity: warn}
+  - {name: Up, type: url-reachable, target: `+srv.URL+`/ok}
+  - {name: Down, type: url-reachable, target: `+srv.URL+`/down}
+  - {name: Set, type: env-set, target: DECL_TEST_SET}
+  - {name: Empty, type: env-set, target: DECL_TEST_EMPTY}
+  - {name: True, type: command-exit-zero, target: "echo ok"}
+  - {name: False, type: command-exit-zero, target: "`+exitOne+`"}
+`)
+	checks, err := LoadDeclarativeChecks(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]Status{
+		"Present": StatusPass, "Missing": StatusWarn,
+		"Up": StatusPass, "Down": StatusFail,
+		"Set": StatusPass, "Empty": StatusFail,
+		"True": StatusPass, "False": StatusFail,
+	}
+	if len(checks) != len(want) {
+		t.Fatalf("loaded %d checks, want %d", len(checks), len(want))
+	}
+	for _, c := range checks {
+		item := c.Run(context.Background())
+		if item.Status != want[c.Name()] {
+			t.Errorf("%s: status %s (%s), want %s", c.Name(), item.Status, item.Message, want[c.Name()])
+		}
+	}
+}
+
+func TestRunnerReportsDeclarativeChecks(t *testing.T) {
+	resetRegistry(t)
+	root := t.TempDir()
+	path := writeChecksFile(t, root, `checks:
+  - {name: Custom File, type: file-exists, target: nope}
+`)
+	r := NewRunner(false, "test", "", WithDeclarativeChecks(path))
+	r.PluginDir = t.TempDir()
+	p := &runProgress{rep: &Report{}}
+	checks, failures := r.loadPlugins()
+	r.runPlugins(p, checks, failures)
+	p.ordered()
+	if len(p.rep.Items) != 1 || p.rep.Items[0].Name != "Custom File" || p.rep.Items[0].Status != StatusFail {
+		t.Fatalf("items = %+v", p.rep.Items)
+	}
+
+	writeChecksFile(t, root, "checks: [{name: Bad}]\n")
+	checks, failures = r.loadPlugins()
+	if len(checks) != 0 || len(failures) != 1 || failures[0].Name != "Check Definitions" || failures[0].Status != StatusWarn {
+		t.Fatalf("invalid file: checks=%v failures=%+v", checks, failures)
+	}
+}
@@ -0,0 +1,185 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/check"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/maputil"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/reporoot"
+	"gopkg.in/yaml.v3"
+)
+
+// DependenciesItemName is the report name of the depends_on item.
+const DependenciesItemName = "Compose Dependencies"
+
+// RegisterDependencies adds the depends_on check to every check.Runner. It only reads the
+// Compose file, so it reports even when the Docker daemon is down.
+func RegisterDependencies() {
+	check.Register(DependenciesItemName, func(ctx context.Context) check.Item {
+		return CheckComposeDependencies(check.ComposeRoot(ctx))
+	})
+}
+
+// CheckComposeDependencies reads the depends_on entries of docker-compose.yml in root and
+// sorts the services topologically (Kahn's algorithm). A cycle fails, since Compose never
+// starts the services in it or those waiting on them; a dependency on a service the file
+// does not define warns. A missing Compose file is skipped.
+func CheckComposeDependencies(root string) check.Item {
+	item := check.Item{Name: DependenciesItemName}
+	var path string
+	for _, name := range reporoot.ComposeFiles {
+		if p := filepath.Join(root, name); fileExists(p) {
+			path = p
+			break
+		}
+	}
+	if path == "" {
+		item.Status = check.StatusSkip
+		item.Message = "docker-compose.yml not found"
+		return item
+	}
+	deps, err := readDependsOn(path)
+	if err != nil {
+		item.Status = check.StatusFail
+		item.Message = "cannot parse " + filepath.Base(path)
+		item.Details = err.Error()
+		item.Remediation = "Fix the YAML syntax in " + filepath.Base(path)
+		return item
+	}
+
+	var undefined []string
+	for _, svc := range maputil.SortedKeys(deps) {
+		for _, dep := range deps[svc] {
+			if _, ok := deps[dep]; !ok {
+				undefined = append(undefined, svc+" -> "+dep)
+			}
+		}
+	}
+	var details []string
+	if len(undefined) > 0 {
+		details = append(details, "undefined: "+strings.Join(undefined, ", "))
+	}
+
+	if blocked := unsortable(deps); len(blocked) > 0 {
+		item.Status = check.StatusFail
+		item.Message = "depends_on cycle: " + strings.Join(findCycle(deps, blocked), " -> ")
+		item.Details = strings.Join(append([]string{"never started: " + strings.Join(maputil.SortedKeys(blocked), ", ")}, details...), "\n")
+		item.Remediation = "Remove one depends_on entry of the cycle in " + filepath.Base(path)
+		return item
+	}
+	if len(undefined) > 0 {
+		item.Status = check.StatusWarn
+		item.Message = fmt.Sprintf("depends_on names %d undefined service(s)", len(undefined))
+		item.Details = strings.Join(details, "\n")
+		item.Remediation = "Define the services in " + filepath.Base(path) + " or remove them from depends_on"
+		return item
+	}
+	item.Status = check.StatusPass
+	item.Message = fmt.Sprintf("%d service(s), no depends_on cycles", len(deps))
+	return item
+}
+
+// readDependsOn maps every service in the Compose file at path to its depends_on services,
+// in either the list or the map (service: {condition: ...}) form.
+func readDependsOn(path string) (map[string][]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var doc struct {
+		Services map[string]struct {
+			DependsOn yaml.Node `yaml:"depends_on"`
+		} `yaml:"services"`
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	deps := make(map[string][]string, len(doc.Services))
+	for name, svc := range doc.Services {
+		deps[name] = []string{}
+		n := svc.DependsOn
+		switch n.Kind {
+		case yaml.SequenceNode:
+			for _, c := range n.Content {
+				deps[name] = append(deps[name], c.Value)
+			}
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(n.Content); i += 2 {
+				deps[name] = append(deps[name], n.Content[i].Value)
+			}
+		case 0: // no depends_on
+		default:
+			return nil, fmt.Errorf("services.%s.depends_on: must be a list or a map (line %d)", name, n.Line)
+		}
+	}
+	return deps, nil
+}
+
+// unsortable runs Kahn's algorithm over deps (ignoring undefined services) and returns the
+// services it could not order: those in a cycle and those depending on one.
+func unsortable(deps map[string][]string) map[string]bool {
+	pending := map[string]int{}         // service -> defined dependencies not yet started
+	dependents := map[string][]string{} // dependency -> services waiting on it
+	for svc, ds := range deps {
+		pending[svc] += 0
+		for _, d := range ds {
+			if _, ok := deps[d]; ok {
+				pending[svc]++
+				dependents[d] = append(dependents[d], svc)
+			}
+		}
+	}
+	var ready []string
+	for svc, n := range pending {
+		if n == 0 {
+			ready = append(ready, svc)
+		}
+	}
+	for len(ready) > 0 {
+		svc := ready[len(ready)-1]
+		ready = ready[:len(ready)-1]
+		delete(pending, svc)
+		for _, d := range dependents[svc] {
+			if pending[d]--; pending[d] == 0 {
+				ready = append(ready, d)
+			}
+		}
+	}
+	blocked := make(map[string]bool, len(pending))
+	for svc := range pending {
+		blocked[svc] = true
+	}
+	return blocked
+}
+
+// findCycle follows dependencies among blocked services from the first one (by name) until a
+// service repeats, and returns the cycle closed on its first service, e.g. [a b a]. Every
+// blocked service depends on another blocked one, so the walk always finds a cycle.
+func findCycle(deps map[string][]string, blocked map[string]bool) []string {
+	var path []string
+	seen := map[string]int{}
+	svc := maputil.SortedKeys(blocked)[0]
+	for {
+		if i, ok := seen[svc]; ok {
+			return append(path[i:], svc)
+		}
+		seen[svc] = len(path)
+		path = append(path, svc)
+		next := ""
+		for _, d := range deps[svc] {
+			if blocked[d] && (next == "" || d < next) {
+				next = d
+			}
+		}
+		svc = next
+	}
+}
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
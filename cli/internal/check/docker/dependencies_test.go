@@ -0,0 +1,72 @@
+package docker
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/check"
+)
+
+func TestCheckComposeDependencies(t *testing.T) {
+	cases := []struct {
+		name, compose string
+		status        check.Status
+		message       string
+		details       string
+	}{
+		{
+			name:    "ordered",
+			compose: "services:\n  ai_engine:\n    depends_on: [local_ai_server]\n  local_ai_server: {}\n  admin_ui:\n    depends_on:\n      ai_engine:\n        condition: service_healthy\n",
+			status:  check.StatusPass,
+			message: "3 service(s), no depends_on cycles",
+		},
+		{
+			name:    "undefined",
+			compose: "services:\n  ai_engine:\n    depends_on: [redis]\n",
+			status:  check.StatusWarn,
+			details: "undefined: ai_engine -> redis",
+		},
+		{
+			name:    "cycle",
+			compose: "services:\n  a:\n    depends_on: [b]\n  b:\n    depends_on: [c, ghost]\n  c:\n    depends_on: [a]\n  d:\n    depends_on: [a]\n  e: {}\n",
+			status:  check.StatusFail,
+			message: "depends_on cycle: a -> b -> c -> a",
+			details: "never started: a, b, c, d\nundefined: b -> ghost",
+		},
+		{
+			name:    "self",
+			compose: "services:\n  a:\n    depends_on: [a]\n",
+			status:  check.StatusFail,
+			message: "depends_on cycle: a -> a",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			root := t.TempDir()
+			if err := os.WriteFile(filepath.Join(root, "docker-compose.yml"), []byte(tc.compose), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			item := CheckComposeDependencies(root)
+			if item.Status != tc.status {
+				t.Fatalf("status = %s, want %s: %+v", item.Status, tc.status, item)
+			}
+			if tc.message != "" && item.Message != tc.message {
+				t.Errorf("message = %q, want %q", item.Message, tc.message)
+			}
+			if tc.details != "" && item.Details != tc.details {
+				t.Errorf("details = %q, want %q", item.Details, tc.details)
+			}
+		})
+	}
+
+	if item := CheckComposeDependencies(t.TempDir()); item.Status != check.StatusSkip {
+		t.Fatalf("no compose file: %+v", item)
+	}
+	root := t.TempDir()
+	os.WriteFile(filepath.Join(root, "docker-compose.yaml"), []byte("services:\n  a:\n    depends_on: 3\n"), 0o644)
+	if item := CheckComposeDependencies(root); item.Status != check.StatusFail || !strings.Contains(item.Details, "services.a.depends_on") {
+		t.Fatalf("bad depends_on: %+v", item)
+	}
+}
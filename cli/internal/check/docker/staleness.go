@@ -0,0 +1,112 @@
+// Package docker holds agent check items about the Compose project: containers running stale
+// images, exited containers left behind, and the service dependencies in docker-compose.yml.
+package docker
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/check"
+)
+
+// DefaultServices are the Compose services CheckImageStaleness is registered for. Their
+// container names match the service names in docker-compose.yml.
+var DefaultServices = []string{"ai_engine", "admin_ui", "local_ai_server"}
+
+// ItemName is the report name of the staleness item for service.
+func ItemName(service string) string {
+	return "Image Staleness " + service
+}
+
+// Register adds one staleness check per service to every check.Runner. Registered checks run
+// after the built-in ones, so the report shows the ARI Connectivity and container results
+// before these.
+func Register(services []string) {
+	for _, svc := range services {
+		svc := svc
+		check.Register(ItemName(svc), func(ctx context.Context) check.Item {
+			return imageStaleness(ctx, svc)
+		})
+	}
+}
+
+// CheckImageStaleness reports, per service, whether its container still runs an older image
+// than the one now stored locally under the container's image reference (<name>:latest when
+// docker-compose.yml gives no tag), as happens after `docker compose pull` without `up -d`.
+// A stale container is a warning; a missing container or local image is skipped.
+func CheckImageStaleness(services []string) []check.Item {
+	items := make([]check.Item, 0, len(services))
+	for _, svc := range services {
+		items = append(items, imageStaleness(context.Background(), svc))
+	}
+	return items
+}
+
+func imageStaleness(ctx context.Context, service string) check.Item {
+	item := check.Item{Name: ItemName(service)}
+	out, err := exec.CommandContext(ctx, "docker", "inspect", "--format", "{{.Image}} {{.Config.Image}}", service).CombinedOutput()
+	text := strings.TrimSpace(string(out))
+	if err != nil {
+		item.Status = check.StatusSkip
+		item.Message = "container not found"
+		item.Details = text
+		if !strings.Contains(strings.ToLower(text), "no such") {
+			item.Message = "docker inspect failed"
+		}
+		return item
+	}
+	running, ref, ok := strings.Cut(text, " ")
+	if !ok || running == "" || ref == "" {
+		item.Status = check.StatusSkip
+		item.Message = "unexpected docker inspect output"
+		item.Details = text
+		return item
+	}
+	if strings.Contains(ref, "@") {
+		item.Status = check.StatusPass
+		item.Message = "image pinned by digest"
+		item.Details = "image=" + ref
+		return item
+	}
+	ref = withDefaultTag(ref)
+
+	out, err = exec.CommandContext(ctx, "docker", "image", "inspect", ref, "--format", "{{.Id}}").CombinedOutput()
+	latest := strings.TrimSpace(string(out))
+	if err != nil {
+		item.Status = check.StatusSkip
+		item.Message = "no local image " + ref
+		item.Details = latest
+		return item
+	}
+
+	item.Details = fmt.Sprintf("image=%s\nrunning=%s\nlatest=%s", ref, running, latest)
+	if running == latest {
+		item.Status = check.StatusPass
+		item.Message = "running the latest local image"
+		return item
+	}
+	item.Status = check.StatusWarn
+	item.Message = fmt.Sprintf("%s runs %s but %s is now %s", service, shortID(running), ref, shortID(latest))
+	item.Remediation = "Recreate the container on the new image: docker compose up -d " + service
+	return item
+}
+
+// withDefaultTag appends ":latest" to an image reference without a tag. A ':' before the last
+// '/' belongs to a registry port, not a tag.
+func withDefaultTag(ref string) string {
+	if strings.Contains(ref[strings.LastIndex(ref, "/")+1:], ":") {
+		return ref
+	}
+	return ref + ":latest"
+}
+
+// shortID trims an image ID to the 12 hex digits docker prints.
+func shortID(id string) string {
+	id = strings.TrimPrefix(id, "sha256:")
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
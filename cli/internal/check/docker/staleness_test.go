@@ -0,0 +1,69 @@
+package docker
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/check"
+)
+
+// fakeDocker puts a docker script on PATH that answers `docker inspect` for ai_engine (stale),
+// admin_ui (current) and nothing else, and `docker image inspect` for the ai_engine and
+// admin_ui images.
+func fakeDocker(t *testing.T) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake docker is a shell script")
+	}
+	dir := t.TempDir()
+	script := `#!/bin/sh
+case "$*" in
+  *"inspect --format {{.Image}} {{.Config.Image}} ai_engine") echo "sha256:aaaaaaaaaaaaaaaa asterisk-ai-voice-agent-ai-engine" ;;
+  *"inspect --format {{.Image}} {{.Config.Image}} admin_ui") echo "sha256:cccccccccccccccc registry.local:5000/admin-ui:v6" ;;
+  "image inspect asterisk-ai-voice-agent-ai-engine:latest --format {{.Id}}") echo "sha256:bbbbbbbbbbbbbbbb" ;;
+  "image inspect registry.local:5000/admin-ui:v6 --format {{.Id}}") echo "sha256:cccccccccccccccc" ;;
+  *) echo "Error: No such object: $4" >&2; exit 1 ;;
+esac
+`
+	if err := os.WriteFile(filepath.Join(dir, "docker"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir)
+}
+
+func TestCheckImageStaleness(t *testing.T) {
+	fakeDocker(t)
+	items := CheckImageStaleness([]string{"ai_engine", "admin_ui", "local_ai_server"})
+	want := []check.Status{check.StatusWarn, check.StatusPass, check.StatusSkip}
+	for i, it := range items {
+		if it.Status != want[i] {
+			t.Errorf("%s: %s %q, want %s", it.Name, it.Status, it.Message, want[i])
+		}
+	}
+	stale := items[0]
+	if stale.Name != "Image Staleness ai_engine" || !strings.Contains(stale.Message, "aaaaaaaaaaaa") || !strings.Contains(stale.Message, "bbbbbbbbbbbb") {
+		t.Fatalf("stale item = %+v", stale)
+	}
+	if !strings.Contains(stale.Details, "running=sha256:aaaaaaaaaaaaaaaa\nlatest=sha256:bbbbbbbbbbbbbbbb") {
+		t.Fatalf("details = %q", stale.Details)
+	}
+	if items[2].Message != "container not found" {
+		t.Fatalf("missing container = %+v", items[2])
+	}
+}
+
+func TestWithDefaultTag(t *testing.T) {
+	for in, want := range map[string]string{
+		"agent":                 "agent:latest",
+		"agent:v6":              "agent:v6",
+		"registry:5000/agent":   "registry:5000/agent:latest",
+		"registry:5000/a/b:dev": "registry:5000/a/b:dev",
+	} {
+		if got := withDefaultTag(in); got != want {
+			t.Errorf("withDefaultTag(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
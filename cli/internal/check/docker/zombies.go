@@ -0,0 +1,131 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/check"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/health"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/secrets"
+)
+
+// ZombieItemName is the report name of the zombie container item.
+const ZombieItemName = "Zombie Containers"
+
+// DefaultProject is the Compose project name set by docker-compose.yml. COMPOSE_PROJECT_NAME
+// overrides it.
+const DefaultProject = "asterisk-ai-voice-agent"
+
+// ZombieContainer is an exited container of the Compose project that was never removed.
+type ZombieContainer struct {
+	ID       string
+	Name     string
+	ExitCode int
+	Status   string
+}
+
+// exitedStatus matches docker's status column for a stopped container, e.g.
+// "Exited (137) 2 hours ago".
+var exitedStatus = regexp.MustCompile(`^Exited \((-?\d+)\)`)
+
+// RegisterZombies adds the zombie container check to every check.Runner. The project comes
+// from COMPOSE_PROJECT_NAME in the checked .env (see ProjectName).
+func RegisterZombies() {
+	check.Register(ZombieItemName, func(ctx context.Context) check.Item {
+		return zombieContainers(ctx, ProjectName(check.EnvPath(ctx)))
+	})
+}
+
+// ProjectName returns COMPOSE_PROJECT_NAME from the .env at envPath or the process
+// environment, or DefaultProject when it is unset.
+func ProjectName(envPath string) string {
+	envMap := map[string]string{}
+	if envPath != "" {
+		envMap, _, _ = secrets.LoadEnv(envPath)
+	}
+	if name := check.EnvValue(health.GetEnv("COMPOSE_PROJECT_NAME", envMap)); name != "" {
+		return name
+	}
+	return DefaultProject
+}
+
+// CheckZombieContainers warns about exited containers labelled with the Compose project,
+// as left behind by failed updates or crashes, listing each one's name and exit code. It is
+// skipped when docker is unavailable.
+func CheckZombieContainers(project string) check.Item {
+	return zombieContainers(context.Background(), project)
+}
+
+func zombieContainers(ctx context.Context, project string) check.Item {
+	item := check.Item{Name: ZombieItemName}
+	zombies, err := FindZombieContainers(ctx, project)
+	if err != nil {
+		item.Status = check.StatusSkip
+		item.Message = "docker ps failed"
+		item.Details = err.Error()
+		return item
+	}
+	if len(zombies) == 0 {
+		item.Status = check.StatusPass
+		item.Message = "no exited containers in project " + project
+		return item
+	}
+	lines := make([]string, 0, len(zombies))
+	for _, z := range zombies {
+		lines = append(lines, fmt.Sprintf("%s (exit code %d)", z.Name, z.ExitCode))
+	}
+	item.Status = check.StatusWarn
+	item.Message = fmt.Sprintf("%d exited container(s) not removed in project %s", len(zombies), project)
+	item.Details = strings.Join(lines, "\n")
+	item.Remediation = "Remove them: agent cleanup --zombies"
+	return item
+}
+
+// FindZombieContainers lists the exited containers of the Compose project via
+// docker ps --filter status=exited --filter label=com.docker.compose.project=<project>.
+func FindZombieContainers(ctx context.Context, project string) ([]ZombieContainer, error) {
+	out, err := exec.CommandContext(ctx, "docker", "ps", "--all",
+		"--filter", "status=exited",
+		"--filter", "label=com.docker.compose.project="+project,
+		"--format", "json").Output()
+	if err != nil {
+		if ee, ok := err.(*exec.ExitError); ok && len(ee.Stderr) > 0 {
+			return nil, fmt.Errorf("%w: %s", err, strings.TrimSpace(string(ee.Stderr)))
+		}
+		return nil, err
+	}
+	return parseZombies(out)
+}
+
+// parseZombies reads docker ps --format json output: one JSON object per line.
+func parseZombies(out []byte) ([]ZombieContainer, error) {
+	var zombies []ZombieContainer
+	for _, line := range strings.Split(string(out), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		var row struct {
+			ID     string `json:"ID"`
+			Names  string `json:"Names"`
+			Status string `json:"Status"`
+		}
+		if err := json.Unmarshal([]byte(line), &row); err != nil {
+			return nil, fmt.Errorf("unexpected docker ps output %q: %w", line, err)
+		}
+		z := ZombieContainer{ID: row.ID, Name: row.Names, Status: row.Status, ExitCode: -1}
+		if m := exitedStatus.FindStringSubmatch(row.Status); m != nil {
+			z.ExitCode, _ = strconv.Atoi(m[1])
+		}
+		if z.Name == "" {
+			z.Name = row.ID
+		}
+		zombies = append(zombies, z)
+	}
+	return zombies, nil
+}
@@ -0,0 +1,72 @@
+package docker
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/check"
+)
+
+// fakeDockerPS puts a docker script on PATH whose `docker ps` prints two exited containers
+// for project "voice" and nothing for any other project.
+func fakeDockerPS(t *testing.T) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake docker is a shell script")
+	}
+	dir := t.TempDir()
+	script := `#!/bin/sh
+case "$*" in
+  "ps --all --filter status=exited --filter label=com.docker.compose.project=voice --format json")
+    echo '{"ID":"0a1b2c3d4e5f","Names":"voice-ai_engine-run-1","Status":"Exited (137) 2 hours ago","State":"exited"}'
+    echo '{"ID":"1a2b3c4d5e6f","Names":"admin_ui_old","Status":"Exited (0) 3 days ago","State":"exited"}'
+    ;;
+  "ps "*) ;;
+  *) echo "unexpected: $*" >&2; exit 1 ;;
+esac
+`
+	if err := os.WriteFile(filepath.Join(dir, "docker"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir)
+}
+
+func TestCheckZombieContainers(t *testing.T) {
+	fakeDockerPS(t)
+	item := CheckZombieContainers("voice")
+	if item.Name != ZombieItemName || item.Status != check.StatusWarn || !strings.HasPrefix(item.Message, "2 exited container(s)") {
+		t.Fatalf("item = %+v", item)
+	}
+	if item.Details != "voice-ai_engine-run-1 (exit code 137)\nadmin_ui_old (exit code 0)" {
+		t.Fatalf("details = %q", item.Details)
+	}
+	if !strings.Contains(item.Remediation, "agent cleanup --zombies") {
+		t.Fatalf("remediation = %q", item.Remediation)
+	}
+
+	if clean := CheckZombieContainers("other"); clean.Status != check.StatusPass {
+		t.Fatalf("clean project = %+v", clean)
+	}
+
+	t.Setenv("PATH", t.TempDir())
+	if missing := CheckZombieContainers("voice"); missing.Status != check.StatusSkip {
+		t.Fatalf("without docker = %+v", missing)
+	}
+}
+
+func TestProjectName(t *testing.T) {
+	t.Setenv("COMPOSE_PROJECT_NAME", "")
+	envPath := filepath.Join(t.TempDir(), ".env")
+	if got := ProjectName(envPath); got != DefaultProject {
+		t.Fatalf("missing .env: %q", got)
+	}
+	if err := os.WriteFile(envPath, []byte("COMPOSE_PROJECT_NAME=\"voice\"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if got := ProjectName(envPath); got != "voice" {
+		t.Fatalf("from .env: %q", got)
+	}
+}
@@ -0,0 +1,49 @@
+package check
+
+// DocsBaseURL is the published location of the repository docs/ directory.
+const DocsBaseURL = "https://github.com/hkjarral/Asterisk-AI-Voice-Agent/blob/main/docs/"
+
+// checkDocs maps item names to the doc page that explains how to resolve them.
+var checkDocs = map[string]string{
+	"Check Config":              "CLI_TOOLS_GUIDE.md#agent-check",
+	"Context Files":             "Configuration-Reference.md",
+	"Backup Freshness":          "CLI_TOOLS_GUIDE.md",
+	"Backup Encryption":         "CLI_TOOLS_GUIDE.md",
+	"Docker CLI":                "INSTALLATION.md",
+	"Docker Daemon":             "INSTALLATION.md",
+	"Docker Compose":            "INSTALLATION.md",
+	"Container ai_engine":       "TROUBLESHOOTING_GUIDE.md#container-not-running",
+	"Network Mode":              "PRODUCTION_DEPLOYMENT.md",
+	"Mounts":                    "INSTALLATION.md",
+	"Container local_ai_server": "LOCAL_ONLY_SETUP.md",
+	"Local AI Models":           "LOCAL_ONLY_SETUP.md",
+	"In-Container Paths":        "TROUBLESHOOTING_GUIDE.md",
+	"Call History DB":           "TROUBLESHOOTING_GUIDE.md#call-history-db-if-missing-or-empty",
+	"Config":                    "Configuration-Reference.md",
+	"Env":                       "ENVIRONMENT_VARIABLES.md",
+	"Transport Compatibility":   "Transport-Mode-Compatibility.md",
+	"Advertise Hosts":           "Transport-Mode-Compatibility.md",
+	"ARI Connectivity":          "ENVIRONMENT_VARIABLES.md",
+	"ARI":                       "FreePBX-Integration-Guide.md",
+	"Dialplan":                  "TROUBLESHOOTING_GUIDE.md#dialplan-not-passing-to-stasis",
+	"Internet/DNS":              "TROUBLESHOOTING_GUIDE.md#0-docker-build-fails-apt-get--dns",
+}
+
+// DocURLFor returns the documentation link for a check name, or "" if none is registered.
+func DocURLFor(name string) string {
+	if page, ok := checkDocs[name]; ok {
+		return DocsBaseURL + page
+	}
+	return ""
+}
+
+// attachDocURLs fills DocURL on warning and failing items that did not set their own.
+func attachDocURLs(rep *Report) {
+	for i := range rep.Items {
+		item := &rep.Items[i]
+		if item.DocURL != "" || (item.Status != StatusWarn && item.Status != StatusFail) {
+			continue
+		}
+		item.DocURL = DocURLFor(item.Name)
+	}
+}
@@ -0,0 +1,26 @@
+package check
+
+import "testing"
+
+func TestAttachDocURLs(t *testing.T) {
+	rep := &Report{Items: []Item{
+		{Name: "Docker CLI", Status: StatusFail},
+		{Name: "Docker Daemon", Status: StatusPass},
+		{Name: "ARI", Status: StatusWarn, DocURL: "https://example.com/custom"},
+		{Name: "Unknown", Status: StatusFail},
+	}}
+	attachDocURLs(rep)
+
+	if got, want := rep.Items[0].DocURL, DocsBaseURL+"INSTALLATION.md"; got != want {
+		t.Fatalf("DocURL = %q, want %q", got, want)
+	}
+	if rep.Items[1].DocURL != "" {
+		t.Fatalf("passing item should not get a DocURL: %+v", rep.Items[1])
+	}
+	if rep.Items[2].DocURL != "https://example.com/custom" {
+		t.Fatalf("explicit DocURL overwritten: %+v", rep.Items[2])
+	}
+	if rep.Items[3].DocURL != "" {
+		t.Fatalf("unknown check should have no DocURL: %+v", rep.Items[3])
+	}
+}
@@ -0,0 +1,140 @@
+package check
+
+//go:generate go run gen_env_schema.go
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// EnvKey describes one known .env key.
+type EnvKey struct {
+	Name        string
+	Required    bool
+	Description string
+	Default     string
+	Validate    func(string) error
+}
+
+// EnvSchema is the set of .env keys the agent knows about (see env.schema.yaml).
+type EnvSchema struct {
+	Keys []EnvKey
+}
+
+// EnvIssue is one finding from EnvSchema.Check.
+type EnvIssue struct {
+	Key     string `json:"key"`
+	Status  Status `json:"status"`
+	Message string `json:"message"`
+}
+
+// DefaultEnvSchema returns the schema generated from env.schema.yaml.
+func DefaultEnvSchema() *EnvSchema {
+	return &EnvSchema{Keys: generatedEnvKeys}
+}
+
+// Lookup returns the schema entry for name.
+func (s *EnvSchema) Lookup(name string) (EnvKey, bool) {
+	for _, k := range s.Keys {
+		if k.Name == name {
+			return k, true
+		}
+	}
+	return EnvKey{}, false
+}
+
+// Check reports missing required keys and invalid values (fail), and keys the schema
+// does not know about (warn). Issues are sorted by key.
+func (s *EnvSchema) Check(env map[string]string) []EnvIssue {
+	var issues []EnvIssue
+	for _, k := range s.Keys {
+		raw, ok := env[k.Name]
+		value := EnvValue(raw)
+		if !ok || value == "" {
+			if k.Required {
+				issues = append(issues, EnvIssue{Key: k.Name, Status: StatusFail, Message: "required key is missing or empty (" + k.Description + ")"})
+			}
+			continue
+		}
+		if k.Validate != nil {
+			if err := k.Validate(value); err != nil {
+				issues = append(issues, EnvIssue{Key: k.Name, Status: StatusFail, Message: err.Error()})
+			}
+		}
+	}
+	for name := range env {
+		if _, ok := s.Lookup(name); !ok {
+			issues = append(issues, EnvIssue{Key: name, Status: StatusWarn, Message: "unknown key (typo, or not used by this version)"})
+		}
+	}
+	sort.SliceStable(issues, func(i, j int) bool { return issues[i].Key < issues[j].Key })
+	return issues
+}
+
+// EnvValue normalizes a raw .env value: it strips an inline "  # comment" and surrounding quotes.
+func EnvValue(raw string) string {
+	v := strings.TrimSpace(raw)
+	if len(v) >= 2 && (v[0] == '"' || v[0] == '\'') {
+		if end := strings.IndexByte(v[1:], v[0]); end >= 0 {
+			return v[1 : end+1]
+		}
+	}
+	if i := strings.Index(v, " #"); i >= 0 {
+		v = v[:i]
+	} else if i := strings.Index(v, "\t#"); i >= 0 {
+		v = v[:i]
+	}
+	return strings.TrimSpace(v)
+}
+
+func validateEnvInt(v string) error {
+	if _, err := strconv.Atoi(v); err != nil {
+		return fmt.Errorf("%q is not an integer", v)
+	}
+	return nil
+}
+
+func validateEnvNumber(v string) error {
+	if _, err := strconv.ParseFloat(v, 64); err != nil {
+		return fmt.Errorf("%q is not a number", v)
+	}
+	return nil
+}
+
+func validateEnvPort(v string) error {
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 1 || n > 65535 {
+		return fmt.Errorf("%q is not a valid port (1-65535)", v)
+	}
+	return nil
+}
+
+func validateEnvBool(v string) error {
+	switch strings.ToLower(v) {
+	case "true", "false", "1", "0", "yes", "no", "on", "off":
+		return nil
+	}
+	return fmt.Errorf("%q is not a boolean (true/false/1/0)", v)
+}
+
+func validateEnvURL(v string) error {
+	u, err := url.Parse(v)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return fmt.Errorf("%q is not a URL (expected scheme://host)", v)
+	}
+	return nil
+}
+
+func validateEnvEnum(values ...string) func(string) error {
+	return func(v string) error {
+		for _, allowed := range values {
+			if strings.EqualFold(v, allowed) {
+				return nil
+			}
+		}
+		return fmt.Errorf("%q is not one of: %s", v, strings.Join(values, ", "))
+	}
+}
@@ -0,0 +1,54 @@
+package check
+
+import "testing"
+
+func TestEnvSchemaCheck(t *testing.T) {
+	env := map[string]string{
+		"ASTERISK_HOST":         "127.0.0.1",
+		"ASTERISK_ARI_USERNAME": "asterisk",
+		"ASTERISK_ARI_PORT":     "80880",
+		"LOG_LEVEL":             "INFO   # AI Engine",
+		"ASTERISK_ARI_SCHEME":   "ftp",
+		"ASTERISK_HSOT":         "typo",
+	}
+	issues := DefaultEnvSchema().Check(env)
+
+	got := map[string]Status{}
+	for _, is := range issues {
+		got[is.Key] = is.Status
+	}
+	want := map[string]Status{
+		"ASTERISK_ARI_PASSWORD": StatusFail, // required, missing
+		"ASTERISK_ARI_PORT":     StatusFail, // out of range
+		"ASTERISK_ARI_SCHEME":   StatusFail, // not in enum
+		"ASTERISK_HSOT":         StatusWarn, // unknown
+	}
+	if len(got) != len(want) {
+		t.Fatalf("unexpected issues: %+v", issues)
+	}
+	for k, st := range want {
+		if got[k] != st {
+			t.Fatalf("%s: got %q want %q (issues=%+v)", k, got[k], st, issues)
+		}
+	}
+}
+
+func TestEnvValue(t *testing.T) {
+	for raw, want := range map[string]string{
+		`int8  # Compute type`:  "int8",
+		`"You are # helpful."`:  "You are # helpful.",
+		`'x'`:                   "x",
+		`http://host/#fragment`: "http://host/#fragment",
+	} {
+		if got := EnvValue(raw); got != want {
+			t.Fatalf("EnvValue(%q)=%q want %q", raw, got, want)
+		}
+	}
+}
+
+func TestDefaultEnvSchemaHasARIPort(t *testing.T) {
+	k, ok := DefaultEnvSchema().Lookup("ASTERISK_ARI_PORT")
+	if !ok || k.Default != "8088" || k.Validate == nil {
+		t.Fatalf("ASTERISK_ARI_PORT: %+v ok=%v", k, ok)
+	}
+}
@@ -0,0 +1,152 @@
+package check
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/maputil"
+)
+
+// ErrUnknownItem is returned by Run when Runner.FilterItems names a check that does not exist.
+var ErrUnknownItem = errors.New("unknown check item")
+
+// checkDecl registers a built-in check: its item name and the checks whose results it reads
+// (or that must pass before it can run). A Critical check's failure is never demoted (see
+// Item.Critical).
+type checkDecl struct {
+	Name     string
+	Deps     []string
+	Critical bool
+}
+
+// builtinChecks lists the checks runChecks declares, with their dependencies. Runner.FilterItems
+// is resolved against it before anything runs, so a new step in runChecks must be added here.
+var builtinChecks = []checkDecl{
+	{Name: "Host"},
+	{Name: "Context Files"},
+	{Name: "Backup Freshness"},
+	{Name: "Backup Encryption"},
+	{Name: "Docker CLI", Critical: true},
+	{Name: "Docker Daemon", Deps: []string{"Docker CLI"}, Critical: true},
+	{Name: "Docker Compose", Deps: []string{"Docker CLI"}, Critical: true},
+	{Name: "Compose File", Deps: []string{"Docker Compose"}},
+	{Name: "Image Pullability", Deps: []string{"Docker Daemon", "Docker Compose"}},
+	{Name: "Container ai_engine", Deps: []string{"Docker Daemon"}},
+	{Name: "Network Mode", Deps: []string{"Container ai_engine"}},
+	{Name: "Mounts", Deps: []string{"Container ai_engine"}},
+	{Name: "Container local_ai_server", Deps: []string{"Docker Daemon"}},
+	{Name: "Local AI Models", Deps: []string{"Container ai_engine", "Container local_ai_server"}},
+	{Name: "In-Container Paths", Deps: []string{"Container ai_engine"}},
+	{Name: "Call History DB", Deps: []string{"Container ai_engine"}},
+	{Name: "Config", Deps: []string{"Container ai_engine"}},
+	{Name: "Env", Deps: []string{"Container ai_engine"}},
+	{Name: "Transport Compatibility", Deps: []string{"Config"}},
+	{Name: "Advertise Hosts", Deps: []string{"Config", "Env"}},
+	{Name: "ARI Connectivity"},
+	{Name: "ARI", Deps: []string{"Config", "Env"}},
+	{Name: "Dialplan", Deps: []string{"ARI"}},
+	{Name: "Internet/DNS", Deps: []string{"Env"}},
+}
+
+// IsBuiltinItem reports whether name is a check compiled into the binary: a built-in step or
+// one added with Register. Declarative checks and plugins, whose names operators choose, are
+// not.
+func IsBuiltinItem(name string) bool {
+	for _, d := range builtinChecks {
+		if d.Name == name {
+			return true
+		}
+	}
+	for _, c := range registered() {
+		if c.Name() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// isCriticalItem reports whether name is a built-in check declared Critical or a check added
+// with RegisterCritical.
+func isCriticalItem(name string) bool {
+	for _, d := range builtinChecks {
+		if d.Name == name {
+			return d.Critical
+		}
+	}
+	for _, c := range registered() {
+		if fc, ok := c.(funcCheck); ok && fc.name == name {
+			return fc.critical
+		}
+	}
+	return false
+}
+
+// markCritical sets Item.Critical on the items of critical checks.
+func markCritical(rep *Report) {
+	for i := range rep.Items {
+		if isCriticalItem(rep.Items[i].Name) {
+			rep.Items[i].Critical = true
+		}
+	}
+}
+
+// ItemKey is the form --item accepts for an item name: lower case with each run of other
+// characters replaced by "-" ("ARI Connectivity" -> "ari-connectivity").
+func ItemKey(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(strings.TrimSpace(name)) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		dash = true
+	}
+	return b.String()
+}
+
+// resolveFilter returns the keys of the requested checks and everything they depend on,
+// transitively. Names are matched by ItemKey, so "ARI Connectivity" and "ari-connectivity"
+// are the same item.
+func resolveFilter(decls []checkDecl, requested []string) (map[string]bool, error) {
+	byKey := make(map[string]checkDecl, len(decls))
+	for _, d := range decls {
+		byKey[ItemKey(d.Name)] = d
+	}
+
+	selected := map[string]bool{}
+	var add func(key string)
+	add = func(key string) {
+		if selected[key] {
+			return
+		}
+		selected[key] = true
+		for _, dep := range byKey[key].Deps {
+			add(ItemKey(dep))
+		}
+	}
+	var unknown []string
+	for _, name := range requested {
+		key := ItemKey(name)
+		if _, ok := byKey[key]; !ok {
+			unknown = append(unknown, fmt.Sprintf("%q", name))
+			continue
+		}
+		add(key)
+	}
+	if len(unknown) > 0 {
+		known := maputil.SortedKeys(byKey)
+		return nil, fmt.Errorf("%w %s (known items: %s)", ErrUnknownItem, strings.Join(unknown, ", "), strings.Join(known, ", "))
+	}
+	return selected, nil
+}
+
+// selects reports whether the check named name is part of this run.
+func (r *Runner) selects(name string) bool {
+	return r.selected == nil || r.selected[ItemKey(name)]
+}
@@ -0,0 +1,73 @@
+package check
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestItemKey(t *testing.T) {
+	cases := map[string]string{
+		"ARI Connectivity":    "ari-connectivity",
+		"Internet/DNS":        "internet-dns",
+		"Container ai_engine": "container-ai-engine",
+		" ari-connectivity ":  "ari-connectivity",
+	}
+	for in, want := range cases {
+		if got := ItemKey(in); got != want {
+			t.Errorf("ItemKey(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestBuiltinChecksDepsAreKnown(t *testing.T) {
+	known := map[string]bool{}
+	for _, d := range builtinChecks {
+		for _, dep := range d.Deps {
+			if !known[dep] {
+				t.Errorf("%s depends on %q, which is not declared before it", d.Name, dep)
+			}
+		}
+		known[d.Name] = true
+	}
+}
+
+func TestResolveFilterIncludesDependencies(t *testing.T) {
+	selected, err := resolveFilter(builtinChecks, []string{"Dialplan", "host"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"Dialplan", "ARI", "Config", "Env", "Container ai_engine", "Docker Daemon", "Docker CLI", "Host"} {
+		if !selected[ItemKey(name)] {
+			t.Errorf("%s not selected", name)
+		}
+	}
+	if selected["mounts"] || selected["ari-connectivity"] || len(selected) != 8 {
+		t.Fatalf("selected = %v", selected)
+	}
+
+	_, err = resolveFilter(builtinChecks, []string{"ari-connectivity", "sip-trunk"})
+	if !errors.Is(err, ErrUnknownItem) || !strings.Contains(err.Error(), `"sip-trunk"`) || !strings.Contains(err.Error(), "internet-dns") {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestRunWithFilterItems(t *testing.T) {
+	resetRegistry(t)
+	Register("SIP Trunk", func(ctx context.Context) Item { return Item{Status: StatusPass} })
+	r := &Runner{Config: &RunnerConfig{}, PluginDir: t.TempDir(), FilterItems: []string{"sip-trunk"}}
+
+	rep, err := r.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rep.Items) != 1 || rep.Items[0].Name != "SIP Trunk" || len(rep.FilterItems) != 1 {
+		t.Fatalf("report = %+v", rep)
+	}
+
+	r.FilterItems = []string{"nope"}
+	if rep, err := r.Run(context.Background()); rep != nil || !errors.Is(err, ErrUnknownItem) {
+		t.Fatalf("unknown item: %v, %v", rep, err)
+	}
+}
@@ -0,0 +1,156 @@
+package check
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// FixHistoryPath is the append-only log of agent check --fix recoveries, relative to the repo root.
+const FixHistoryPath = ".agent/fix-history.jsonl"
+
+// FixRecord is one recovery applied by agent check --fix, with the reports around it.
+type FixRecord struct {
+	Timestamp       time.Time `json:"timestamp"`
+	RepoRoot        string    `json:"repo_root"`
+	PrefixBackup    string    `json:"prefix_backup,omitempty"`
+	SourceBackup    string    `json:"source_backup"`
+	RestoredPaths   []string  `json:"restored_paths"`
+	SkippedPaths    []string  `json:"skipped_paths,omitempty"`
+	Warnings        []string  `json:"warnings"`
+	BeforeFailCount int       `json:"before_fail_count"`
+	AfterFailCount  int       `json:"after_fail_count"`
+	// Attempts lists each restore cycle of a run with --max-retries; the top-level fields
+	// describe the run as a whole (SourceBackup is the last set used).
+	Attempts []AttemptRecord `json:"attempts,omitempty"`
+	Before   *Report         `json:"before,omitempty"`
+	After    *Report         `json:"after,omitempty"`
+}
+
+// AttemptRecord is one restore, restart and re-check cycle of agent check --fix.
+type AttemptRecord struct {
+	Attempt        int      `json:"attempt"`
+	SourceBackup   string   `json:"source_backup"`
+	RestoredPaths  []string `json:"restored_paths"`
+	AfterFailCount int      `json:"after_fail_count"`
+	Passed         bool     `json:"passed"`
+	Error          string   `json:"error,omitempty"`
+}
+
+// AppendFixRecord adds rec to the JSONL file at path as a single line, creating the file and
+// its directory if needed. The fail counts are taken from the reports when they are set.
+func AppendFixRecord(path string, rec FixRecord) error {
+	if rec.Before != nil {
+		rec.Before.finalizeCounts()
+		rec.BeforeFailCount = rec.Before.FailCount
+	}
+	if rec.After != nil {
+		rec.After.finalizeCounts()
+		rec.AfterFailCount = rec.After.FailCount
+	}
+	if rec.RestoredPaths == nil {
+		rec.RestoredPaths = []string{}
+	}
+	if rec.Warnings == nil {
+		rec.Warnings = []string{}
+	}
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	// One write per record: a crash leaves at most a truncated last line, which
+	// LoadFixHistory skips.
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to append to %s: %w", path, err)
+	}
+	return f.Close()
+}
+
+// LoadFixHistory reads the records in path, oldest first. Lines that do not parse (a write
+// cut short) are skipped and counted. A missing file has no records.
+func LoadFixHistory(path string) (records []FixRecord, skipped int, err error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	defer f.Close()
+
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64*1024), 16*1024*1024) // each line carries two full reports
+	for sc.Scan() {
+		line := sc.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var rec FixRecord
+		if err := json.Unmarshal(line, &rec); err != nil {
+			skipped++
+			continue
+		}
+		records = append(records, rec)
+	}
+	if err := sc.Err(); err != nil {
+		return records, skipped, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return records, skipped, nil
+}
+
+// FixSummary is the outcome of one agent check --fix run as written by --summary-output.
+// Unlike FixRecord it is written for failed recoveries too, with Error set, and each run
+// replaces the previous file.
+type FixSummary struct {
+	Timestamp    time.Time       `json:"timestamp"`
+	RepoRoot     string          `json:"repo_root"`
+	PrefixBackup string          `json:"prefix_backup,omitempty"`
+	SourceBackup string          `json:"source_backup"`
+	Restored     []string        `json:"restored"`
+	Skipped      []string        `json:"skipped,omitempty"`
+	Warnings     []string        `json:"warnings"`
+	DryRun       bool            `json:"dry_run"`
+	ExitCode     int             `json:"exit_code"`
+	Error        string          `json:"error,omitempty"`
+	Attempts     []AttemptRecord `json:"attempts,omitempty"`
+	BeforeReport *Report         `json:"before_report,omitempty"`
+	AfterReport  *Report         `json:"after_report,omitempty"`
+}
+
+// WriteFixSummary writes s to path as indented JSON, replacing any previous file.
+func WriteFixSummary(path string, s FixSummary) error {
+	for _, rep := range []*Report{s.BeforeReport, s.AfterReport} {
+		if rep != nil {
+			rep.finalizeCounts()
+		}
+	}
+	if s.Restored == nil {
+		s.Restored = []string{}
+	}
+	if s.Warnings == nil {
+		s.Warnings = []string{}
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return os.Rename(tmp, path)
+}
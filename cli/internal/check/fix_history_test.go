@@ -0,0 +1,100 @@
+package check
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFixHistoryAppendAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".agent", "fix-history.jsonl")
+	if recs, skipped, err := LoadFixHistory(path); err != nil || len(recs) != 0 || skipped != 0 {
+		t.Fatalf("missing file: %v %d %v", recs, skipped, err)
+	}
+
+	first := FixRecord{
+		Timestamp:     time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC),
+		SourceBackup:  ".agent/update-backups/20260930_120000",
+		RestoredPaths: []string{".env"},
+		Before:        &Report{Items: []Item{{Name: "Env", Status: StatusFail}, {Name: "ARI", Status: StatusFail}}},
+		After:         &Report{Items: []Item{{Name: "Env", Status: StatusPass}, {Name: "ARI", Status: StatusWarn}}},
+	}
+	if err := AppendFixRecord(path, first); err != nil {
+		t.Fatal(err)
+	}
+	// A write cut short by a crash must not hide the records around it.
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"timestamp": "2026-10-01T13:00:00Z", "repo_ro` + "\n")
+	f.Close()
+	second := FixRecord{SourceBackup: "second", Attempts: []AttemptRecord{
+		{Attempt: 1, SourceBackup: "newer", RestoredPaths: []string{".env"}, AfterFailCount: 1},
+		{Attempt: 2, SourceBackup: "second", RestoredPaths: []string{".env"}, Passed: true},
+	}}
+	if err := AppendFixRecord(path, second); err != nil {
+		t.Fatal(err)
+	}
+
+	recs, skipped, err := LoadFixHistory(path)
+	if err != nil || skipped != 1 || len(recs) != 2 {
+		t.Fatalf("got %d records, %d skipped, %v", len(recs), skipped, err)
+	}
+	got := recs[0]
+	if got.BeforeFailCount != 2 || got.AfterFailCount != 0 || got.After.WarnCount != 1 || got.RestoredPaths[0] != ".env" || !got.Timestamp.Equal(first.Timestamp) {
+		t.Fatalf("first record = %+v", got)
+	}
+	if recs[1].SourceBackup != "second" || recs[1].Warnings == nil || len(recs[1].Attempts) != 2 || !recs[1].Attempts[1].Passed {
+		t.Fatalf("second record = %+v", recs[1])
+	}
+	if got.Attempts != nil {
+		t.Fatalf("single-cycle record should have no attempts: %+v", got.Attempts)
+	}
+}
+
+func TestWriteFixSummaryOverwrites(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out", "fix-summary.json")
+	first := FixSummary{
+		RepoRoot:     "/srv/agent",
+		SourceBackup: ".agent/update-backups/20260930_120000",
+		Restored:     []string{".env"},
+		BeforeReport: &Report{Items: []Item{{Name: "Env", Status: StatusFail}}},
+		AfterReport:  &Report{Items: []Item{{Name: "Env", Status: StatusPass}}},
+	}
+	if err := WriteFixSummary(path, first); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteFixSummary(path, FixSummary{RepoRoot: "/srv/agent", ExitCode: 2, Error: "no usable backup found"}); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got FixSummary
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Error != "no usable backup found" || got.ExitCode != 2 || got.BeforeReport != nil {
+		t.Fatalf("second write did not replace the first: %+v", got)
+	}
+	if got.Restored == nil || got.Warnings == nil {
+		t.Errorf("empty lists should be written as []: %s", data)
+	}
+
+	if err := WriteFixSummary(path, first); err != nil {
+		t.Fatal(err)
+	}
+	data, _ = os.ReadFile(path)
+	got = FixSummary{}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.BeforeReport == nil || got.BeforeReport.FailCount != 1 || got.AfterReport.PassCount != 1 {
+		t.Errorf("reports not embedded with counts: %+v %+v", got.BeforeReport, got.AfterReport)
+	}
+}
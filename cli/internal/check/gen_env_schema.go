@@ -0,0 +1,94 @@
+//go:build ignore
+
+// gen_env_schema.go generates env.schema.go from env.schema.yaml.
+// Run via `go generate` in this directory.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+type schemaFile struct {
+	Keys []struct {
+		Name        string   `yaml:"name"`
+		Required    bool     `yaml:"required"`
+		Type        string   `yaml:"type"`
+		Values      []string `yaml:"values"`
+		Default     string   `yaml:"default"`
+		Description string   `yaml:"description"`
+	} `yaml:"keys"`
+}
+
+func main() {
+	data, err := os.ReadFile("env.schema.yaml")
+	if err != nil {
+		log.Fatal(err)
+	}
+	var schema schemaFile
+	if err := yaml.Unmarshal(data, &schema); err != nil {
+		log.Fatalf("env.schema.yaml: %v", err)
+	}
+
+	var b bytes.Buffer
+	b.WriteString("// Code generated by gen_env_schema.go from env.schema.yaml; DO NOT EDIT.\n\n")
+	b.WriteString("package check\n\n")
+	b.WriteString("var generatedEnvKeys = []EnvKey{\n")
+	seen := map[string]bool{}
+	for _, k := range schema.Keys {
+		if k.Name == "" || seen[k.Name] {
+			log.Fatalf("env.schema.yaml: empty or duplicate key %q", k.Name)
+		}
+		seen[k.Name] = true
+
+		var validate string
+		switch k.Type {
+		case "", "string":
+		case "int":
+			validate = "validateEnvInt"
+		case "number":
+			validate = "validateEnvNumber"
+		case "port":
+			validate = "validateEnvPort"
+		case "bool":
+			validate = "validateEnvBool"
+		case "url":
+			validate = "validateEnvURL"
+		case "enum":
+			if len(k.Values) == 0 {
+				log.Fatalf("env.schema.yaml: %s: enum without values", k.Name)
+			}
+			quoted := make([]string, len(k.Values))
+			for i, v := range k.Values {
+				quoted[i] = fmt.Sprintf("%q", v)
+			}
+			validate = "validateEnvEnum(" + strings.Join(quoted, ", ") + ")"
+		default:
+			log.Fatalf("env.schema.yaml: %s: unknown type %q", k.Name, k.Type)
+		}
+
+		fmt.Fprintf(&b, "\t{Name: %q, Required: %t, Description: %q", k.Name, k.Required, k.Description)
+		if k.Default != "" {
+			fmt.Fprintf(&b, ", Default: %q", k.Default)
+		}
+		if validate != "" {
+			fmt.Fprintf(&b, ", Validate: %s", validate)
+		}
+		b.WriteString("},\n")
+	}
+	b.WriteString("}\n")
+
+	out, err := format.Source(b.Bytes())
+	if err != nil {
+		log.Fatalf("format: %v", err)
+	}
+	if err := os.WriteFile("env.schema.go", out, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,167 @@
+package check
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// LastReportPath is where agent check keeps the previous report for --since, relative to the repo root.
+const LastReportPath = ".agent/last-report.json"
+
+// BaselineReportPath is the known-good report saved by agent check --baseline, relative to the repo root.
+const BaselineReportPath = ".agent/baseline-report.json"
+
+// SaveReport writes rep (without its ChangedItems and RegressionItems) as JSON to path,
+// creating the parent directory.
+func SaveReport(path string, rep *Report) error {
+	rep.finalizeCounts()
+	saved := *rep
+	saved.ChangedItems = nil
+	saved.RegressionItems = nil
+	data, err := json.MarshalIndent(&saved, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// LoadReport reads a report written by SaveReport. A missing file returns (nil, nil).
+func LoadReport(path string) (*Report, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var rep Report
+	if err := json.Unmarshal(data, &rep); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", path, err)
+	}
+	return &rep, nil
+}
+
+// CompareWith sets ChangedItems to the items whose status differs from prev, matched by name.
+// Items absent from prev count as previously passing, so a first run reports only problems.
+func (r *Report) CompareWith(prev *Report) {
+	before := map[string]Status{}
+	if prev != nil {
+		r.PreviousTimestamp = prev.Timestamp
+		for _, item := range prev.Items {
+			before[item.Name] = item.Status
+		}
+	}
+	r.ChangedItems = nil
+	for _, item := range r.Items {
+		was, ok := before[item.Name]
+		if !ok {
+			was = StatusPass
+		}
+		if item.Status == was {
+			continue
+		}
+		item.PreviousStatus = was
+		r.ChangedItems = append(r.ChangedItems, item)
+	}
+}
+
+// CompareWithBaseline sets RegressionItems to the items that passed in base and now fail,
+// matched by name; items missing from base are not regressions.
+func (r *Report) CompareWithBaseline(base *Report) {
+	r.RegressionItems = nil
+	if base == nil {
+		return
+	}
+	r.BaselineTimestamp = base.Timestamp
+	passed := map[string]bool{}
+	for _, item := range base.Items {
+		if item.Status == StatusPass {
+			passed[item.Name] = true
+		}
+	}
+	for _, item := range r.Items {
+		if item.Status == StatusFail && passed[item.Name] {
+			item.PreviousStatus = StatusPass
+			r.RegressionItems = append(r.RegressionItems, item)
+		}
+	}
+}
+
+// outputRegressions prints the baseline regressions ahead of the report.
+func (r *Report) outputRegressions(w io.Writer) {
+	if len(r.RegressionItems) == 0 {
+		return
+	}
+	red := color.New(color.FgRed, color.Bold).SprintFunc()
+	label := red(fmt.Sprintf("REGRESSION since %s:", r.BaselineTimestamp.Format(time.RFC3339)))
+	fmt.Fprintln(w)
+	for _, item := range r.RegressionItems {
+		fmt.Fprintf(w, "%s %s %s\n", label, item.Name, item.Message)
+	}
+}
+
+// ChangeLabel is "RECOVERED" for items that no longer warn or fail and "NEW" otherwise.
+func (item Item) ChangeLabel() string {
+	if item.Status.ExitCode() == 0 {
+		return "RECOVERED"
+	}
+	return "NEW"
+}
+
+func (r *Report) outputChanges(w io.Writer) {
+	yellow := color.New(color.FgYellow, color.Bold).SprintFunc()
+	red := color.New(color.FgRed, color.Bold).SprintFunc()
+	green := color.New(color.FgGreen, color.Bold).SprintFunc()
+	gray := color.New(color.FgHiBlack).SprintFunc()
+
+	since := "the first run"
+	if !r.PreviousTimestamp.IsZero() {
+		since = r.PreviousTimestamp.Format(time.RFC3339)
+	}
+
+	if len(r.ChangedItems) == 0 {
+		if r.FailCount == 0 && r.WarnCount == 0 {
+			fmt.Fprintf(w, "No status changes since %s\n\n", since)
+			return
+		}
+		fmt.Fprintf(w, "No status changes since %s (still %d failing, %d warning)\n\n", since, r.FailCount, r.WarnCount)
+		return
+	}
+
+	fmt.Fprintf(w, "Status changes since %s:\n", since)
+	for _, item := range r.ChangedItems {
+		label := item.ChangeLabel() + ":"
+		switch item.Status {
+		case StatusFail:
+			label = red(label)
+		case StatusWarn:
+			label = yellow(label)
+		default:
+			label = green(label)
+		}
+		fmt.Fprintf(w, "  %s %s [%s] %s %s\n", label, item.Name, item.Status, item.Message, gray("(was "+string(item.PreviousStatus)+")"))
+		if item.Details != "" && item.ChangeLabel() == "NEW" {
+			fmt.Fprintf(w, "      %s\n", gray(item.Details))
+		}
+		if item.Remediation != "" && item.ChangeLabel() == "NEW" {
+			fmt.Fprintf(w, "      %s %s\n", yellow("Remediation:"), item.Remediation)
+		}
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "%s %d  %s %d  %s %d  (%d total)\n",
+		green("PASS"), r.PassCount, yellow("WARN"), r.WarnCount, red("FAIL"), r.FailCount, r.Total)
+	fmt.Fprintln(w)
+}
@@ -0,0 +1,110 @@
+package check
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCompareWithAndSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".agent", "last-report.json")
+	if prev, err := LoadReport(path); err != nil || prev != nil {
+		t.Fatalf("missing report: got %v, %v", prev, err)
+	}
+
+	first := &Report{Timestamp: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), Items: []Item{
+		{Name: "ARI", Status: StatusFail},
+		{Name: "Docker CLI", Status: StatusPass},
+		{Name: "Env", Status: StatusWarn},
+	}}
+	if err := SaveReport(path, first); err != nil {
+		t.Fatal(err)
+	}
+	prev, err := LoadReport(path)
+	if err != nil || prev == nil {
+		t.Fatalf("LoadReport: %v", err)
+	}
+
+	cur := &Report{Items: []Item{
+		{Name: "ARI", Status: StatusPass},
+		{Name: "Docker CLI", Status: StatusFail},
+		{Name: "Env", Status: StatusWarn},
+		{Name: "Context Files", Status: StatusPass},
+	}}
+	cur.CompareWith(prev)
+	if len(cur.ChangedItems) != 2 {
+		t.Fatalf("expected 2 changes, got %+v", cur.ChangedItems)
+	}
+	if got := cur.ChangedItems[0]; got.Name != "ARI" || got.ChangeLabel() != "RECOVERED" || got.PreviousStatus != StatusFail {
+		t.Fatalf("unexpected first change: %+v", got)
+	}
+	if got := cur.ChangedItems[1]; got.Name != "Docker CLI" || got.ChangeLabel() != "NEW" {
+		t.Fatalf("unexpected second change: %+v", got)
+	}
+
+	cur.OnlyChanges = true
+	var buf bytes.Buffer
+	cur.OutputText(&buf, OutputOptions{})
+	out := buf.String()
+	if !strings.Contains(out, "RECOVERED: ARI") || !strings.Contains(out, "NEW: Docker CLI") || strings.Contains(out, "Env") {
+		t.Fatalf("unexpected --since output:\n%s", out)
+	}
+}
+
+func TestOutputTextNoChanges(t *testing.T) {
+	ts := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	prev := &Report{Timestamp: ts, Items: []Item{{Name: "ARI", Status: StatusPass}}}
+	cur := &Report{Items: []Item{{Name: "ARI", Status: StatusPass}}, OnlyChanges: true}
+	cur.CompareWith(prev)
+
+	var buf bytes.Buffer
+	cur.OutputText(&buf, OutputOptions{})
+	if !strings.Contains(buf.String(), "No status changes since "+ts.Format(time.RFC3339)+"\n") {
+		t.Fatalf("unexpected output:\n%s", buf.String())
+	}
+}
+
+func TestCompareWithBaseline(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".agent", "baseline-report.json")
+	base := &Report{Timestamp: time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC), Items: []Item{
+		{Name: "Docker CLI", Status: StatusPass},
+		{Name: "ARI", Status: StatusPass},
+		{Name: "Env", Status: StatusWarn},
+		{Name: "Dialplan", Status: StatusPass},
+	}}
+	if err := SaveReport(path, base); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadReport(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Different order, a new item, and fail/warn transitions.
+	cur := &Report{Timestamp: time.Now(), Items: []Item{
+		{Name: "Internet/DNS", Status: StatusFail}, // not in the baseline
+		{Name: "Env", Status: StatusFail},          // was warning, not passing
+		{Name: "Dialplan", Status: StatusWarn},     // passing -> warning is not a failure
+		{Name: "ARI", Status: StatusFail, Message: "401 Unauthorized"},
+		{Name: "Docker CLI", Status: StatusPass},
+	}}
+	cur.CompareWithBaseline(loaded)
+	if len(cur.RegressionItems) != 1 || cur.RegressionItems[0].Name != "ARI" {
+		t.Fatalf("RegressionItems = %+v, want only ARI", cur.RegressionItems)
+	}
+
+	var buf bytes.Buffer
+	cur.OutputText(&buf, OutputOptions{})
+	out := buf.String()
+	line := "REGRESSION since 2025-03-01T12:00:00Z: ARI 401 Unauthorized"
+	if i, j := strings.Index(out, line), strings.Index(out, "agent check ("); i < 0 || j < i {
+		t.Errorf("regression line missing or not before the report:\n%s", out)
+	}
+
+	cur.CompareWithBaseline(nil)
+	if cur.RegressionItems != nil {
+		t.Errorf("no baseline: RegressionItems = %+v", cur.RegressionItems)
+	}
+}
@@ -0,0 +1,130 @@
+package check
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// HTMLRefreshSeconds is the meta refresh interval of OutputHTML pages, so a browser showing a
+// file rewritten by agent check --watch --html-output follows along.
+const HTMLRefreshSeconds = 10
+
+// OutputHTML writes the report as a single self-contained HTML page (inline CSS and JS, no
+// external requests): a summary badge, the CLI version and timestamp, and one collapsible row
+// per item. Failed items start expanded.
+func (r *Report) OutputHTML(w io.Writer) error {
+	symbol, summary, _ := r.summary() // also finalizes the counts
+	version := r.Version
+	if version == "" {
+		version = "unknown"
+	}
+	badge := "pass"
+	switch {
+	case r.FailCount > 0:
+		badge = "fail"
+	case r.WarnCount > 0:
+		badge = "warn"
+	}
+	return htmlReport.Execute(w, htmlData{
+		Report:    r,
+		Refresh:   HTMLRefreshSeconds,
+		Badge:     badge,
+		Summary:   symbol + " " + summary,
+		Version:   version,
+		Timestamp: r.Timestamp.Format(time.RFC3339),
+	})
+}
+
+// WriteHTMLFile writes OutputHTML to path through a temp file and rename, so a browser
+// refreshing the page never reads a half-written report.
+func (r *Report) WriteHTMLFile(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if err := r.OutputHTML(f); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+type htmlData struct {
+	*Report
+	Refresh   int
+	Badge     string
+	Summary   string
+	Version   string
+	Timestamp string
+}
+
+var htmlReport = template.Must(template.New("report").Funcs(template.FuncMap{
+	"ms": func(d time.Duration) string { return d.Round(time.Millisecond).String() },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta http-equiv="refresh" content="{{.Refresh}}">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Summary}} - agent check</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Roboto, sans-serif; margin: 2rem auto; max-width: 60rem; padding: 0 1rem; color: #1f2328; background: #f6f8fa; }
+h1 { font-size: 1.4rem; margin-bottom: .25rem; }
+.meta { color: #57606a; font-size: .9rem; margin-bottom: 1rem; }
+.badge { display: inline-block; padding: .4rem .9rem; border-radius: 1rem; font-weight: 600; color: #fff; }
+.badge.pass { background: #1a7f37; } .badge.warn { background: #9a6700; } .badge.fail { background: #cf222e; }
+.counts { margin: .75rem 0 1rem; color: #57606a; }
+.toolbar button { font: inherit; margin-right: .5rem; padding: .2rem .6rem; cursor: pointer; }
+details { background: #fff; border: 1px solid #d0d7de; border-left: .4rem solid #8c959f; border-radius: .3rem; margin: .4rem 0; }
+details.pass { border-left-color: #1a7f37; } details.warn { border-left-color: #bf8700; } details.fail { border-left-color: #cf222e; } details.skip, details.info { border-left-color: #0969da; }
+summary { padding: .5rem .75rem; cursor: pointer; }
+summary .status { display: inline-block; width: 3.5rem; font-weight: 600; text-transform: uppercase; font-size: .8rem; }
+summary .duration { float: right; color: #57606a; font-size: .85rem; }
+.body { padding: 0 .75rem .75rem 4.25rem; }
+.body pre { white-space: pre-wrap; background: #f6f8fa; padding: .5rem; border-radius: .3rem; margin: .25rem 0; }
+.regressions { background: #ffebe9; border: 1px solid #cf222e; border-radius: .3rem; padding: .5rem .75rem; margin-bottom: 1rem; }
+</style>
+</head>
+<body>
+<h1>Asterisk AI Voice Agent - agent check</h1>
+<div class="meta">CLI version {{.Version}}{{if and .BuildTime (ne .BuildTime "unknown")}} (build {{.BuildTime}}){{end}} &middot; {{.Timestamp}}</div>
+<span class="badge {{.Badge}}">{{.Summary}}</span>
+<div class="counts">{{.PassCount}} passed, {{.WarnCount}} warnings, {{.FailCount}} failed, {{.SkipCount}} skipped, {{.InfoCount}} info</div>
+{{- if .RegressionItems}}
+<div class="regressions"><strong>Regressions since the baseline ({{.BaselineTimestamp.Format "2006-01-02 15:04"}}):</strong>
+<ul>{{range .RegressionItems}}<li>{{.Name}}: {{.Message}}</li>{{end}}</ul></div>
+{{- end}}
+<div class="toolbar"><button type="button" onclick="toggleAll(true)">Expand all</button><button type="button" onclick="toggleAll(false)">Collapse all</button></div>
+{{range .Items}}
+<details class="{{.Status}}"{{if eq .Status "fail"}} open{{end}}>
+<summary><span class="status">{{.Status}}</span> <strong>{{.Name}}</strong> &mdash; {{.Message}}{{if .Duration}}<span class="duration">{{ms .Duration}}</span>{{end}}</summary>
+<div class="body">
+{{- if .Details}}<pre>{{.Details}}</pre>{{end}}
+{{- if .Remediation}}<p><strong>Fix:</strong> {{.Remediation}}</p>{{end}}
+{{- if .DocURL}}<p><a href="{{.DocURL}}">Documentation</a></p>{{end}}
+{{- if .DemotedFrom}}<p>Demoted from {{.DemotedFrom}}.</p>{{end}}
+{{- if not (or .Details .Remediation .DocURL .DemotedFrom)}}<p>No details.</p>{{end}}
+</div>
+</details>
+{{- end}}
+<script>
+function toggleAll(open) {
+  document.querySelectorAll("details").forEach(function (d) { d.open = open; });
+}
+</script>
+</body>
+</html>
+`))
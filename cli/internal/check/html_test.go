@@ -0,0 +1,67 @@
+package check
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOutputHTML(t *testing.T) {
+	rep := &Report{
+		Version:   "v6.1.0",
+		Timestamp: time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC),
+		Items: []Item{
+			{Name: "Docker", Status: StatusPass, Message: "running", Duration: 12 * time.Millisecond},
+			{Name: "ARI Connectivity", Status: StatusFail, Message: "connection refused", Details: "<dial tcp 127.0.0.1:8088>", Remediation: "Start Asterisk"},
+			{Name: "Env", Status: StatusWarn, Message: "unknown key FOO"},
+		},
+	}
+	var buf bytes.Buffer
+	if err := rep.OutputHTML(&buf); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		`<meta http-equiv="refresh" content="10">`,
+		`<span class="badge fail">✗ 1 failure, 1 warning</span>`,
+		"CLI version v6.1.0",
+		"2026-10-01T12:00:00Z",
+		`<details class="fail" open>`,
+		`<details class="pass">`,
+		`<details class="warn">`,
+		"&lt;dial tcp 127.0.0.1:8088&gt;", // details are escaped
+		"<script>",
+		"12ms",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+	// Self-contained: nothing is loaded from elsewhere.
+	for _, banned := range []string{"<link", "src=", "http://", "https://"} {
+		if strings.Contains(out, banned) {
+			t.Errorf("page references an external resource (%q)", banned)
+		}
+	}
+}
+
+func TestWriteHTMLFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "www", "check.html")
+	rep := &Report{Items: []Item{{Name: "Docker", Status: StatusPass}}}
+	if err := rep.WriteHTMLFile(path); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `<span class="badge pass">✓ 1 check passed</span>`) {
+		t.Errorf("unexpected page:\n%s", data)
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("temp file left behind: %v", err)
+	}
+}
@@ -0,0 +1,142 @@
+// Package network probes the external AI service endpoints the engine calls, to tell a blocked
+// or intercepted network apart from a provider or configuration problem.
+package network
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/check"
+	"gopkg.in/yaml.v3"
+)
+
+// ProbeTimeout bounds each endpoint probe, including DNS, TLS and the response headers.
+const ProbeTimeout = 5 * time.Second
+
+//go:embed endpoints.json
+var defaultEndpoints []byte
+
+// httpClient is replaced in tests. Redirects are not followed so the first status is reported.
+var httpClient = &http.Client{
+	Timeout: ProbeTimeout,
+	CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
+// EndpointSpec is one endpoint to probe. ExpectedStatus is the HTTP status an unauthenticated
+// GET returns when the request reaches the provider (usually 401); 0 accepts any status.
+type EndpointSpec struct {
+	Name           string `json:"name" yaml:"name"`
+	URL            string `json:"url" yaml:"url"`
+	ExpectedStatus int    `json:"expected_status,omitempty" yaml:"expected_status,omitempty"`
+}
+
+// DefaultEndpoints returns the provider endpoints built into the binary.
+func DefaultEndpoints() ([]EndpointSpec, error) {
+	var specs []EndpointSpec
+	if err := json.Unmarshal(defaultEndpoints, &specs); err != nil {
+		return nil, fmt.Errorf("embedded endpoints.json: %w", err)
+	}
+	return specs, validate(specs)
+}
+
+// LoadEndpoints reads a list of EndpointSpec from a JSON or YAML file.
+func LoadEndpoints(path string) ([]EndpointSpec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var specs []EndpointSpec
+	if err := yaml.Unmarshal(data, &specs); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := validate(specs); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return specs, nil
+}
+
+func validate(specs []EndpointSpec) error {
+	for i, s := range specs {
+		if strings.TrimSpace(s.Name) == "" {
+			return fmt.Errorf("endpoint %d: name is required", i+1)
+		}
+		u, err := url.Parse(s.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("endpoint %q: url must be an absolute http(s) URL", s.Name)
+		}
+		if s.ExpectedStatus != 0 && (s.ExpectedStatus < 100 || s.ExpectedStatus > 599) {
+			return fmt.Errorf("endpoint %q: expected_status %d is not an HTTP status", s.Name, s.ExpectedStatus)
+		}
+	}
+	return nil
+}
+
+// CheckExternalConnectivity probes every endpoint concurrently with a GET and returns one item
+// per endpoint, in order. An endpoint that cannot be reached fails; one that answers with a
+// status other than ExpectedStatus warns, since a proxy or captive portal may be answering in
+// the provider's place.
+func CheckExternalConnectivity(endpoints []EndpointSpec) []check.Item {
+	items := make([]check.Item, len(endpoints))
+	var wg sync.WaitGroup
+	for i, spec := range endpoints {
+		wg.Add(1)
+		go func(i int, spec EndpointSpec) {
+			defer wg.Done()
+			items[i] = probe(spec)
+		}(i, spec)
+	}
+	wg.Wait()
+	return items
+}
+
+func probe(spec EndpointSpec) check.Item {
+	item := check.Item{Name: spec.Name, Details: "url=" + spec.URL}
+	ctx, cancel := context.WithTimeout(context.Background(), ProbeTimeout)
+	defer cancel()
+	start := time.Now()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, spec.URL, nil)
+	if err != nil {
+		item.Status = check.StatusFail
+		item.Message = "invalid URL"
+		item.Details += "\n" + err.Error()
+		return item
+	}
+	req.Header.Set("User-Agent", "aava-agent-cli")
+	resp, err := httpClient.Do(req)
+	item.Duration = time.Since(start)
+	if err != nil {
+		item.Status = check.StatusFail
+		item.Details += "\n" + err.Error()
+		item.Remediation = "Check DNS, firewall rules and HTTPS_PROXY for outbound access to " + req.URL.Host
+		var netErr interface{ Timeout() bool }
+		if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+			item.Message = fmt.Sprintf("no response within %s", ProbeTimeout)
+		} else {
+			item.Message = "unreachable"
+		}
+		return item
+	}
+	resp.Body.Close()
+
+	ms := item.Duration.Milliseconds()
+	if spec.ExpectedStatus != 0 && resp.StatusCode != spec.ExpectedStatus {
+		item.Status = check.StatusWarn
+		item.Message = fmt.Sprintf("unexpected HTTP %d (want %d)", resp.StatusCode, spec.ExpectedStatus)
+		item.Remediation = "A proxy or TLS-inspecting firewall may be answering for " + req.URL.Host
+		return item
+	}
+	item.Status = check.StatusPass
+	item.Message = fmt.Sprintf("reachable (HTTP %d, %dms)", resp.StatusCode, ms)
+	return item
+}
@@ -0,0 +1,94 @@
+package network
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/check"
+)
+
+func TestCheckExternalConnectivity(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/auth":
+			w.WriteHeader(http.StatusUnauthorized)
+		case "/portal":
+			http.Redirect(w, r, "/login", http.StatusFound)
+		default:
+			w.Write([]byte("ok"))
+		}
+	}))
+	defer srv.Close()
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+
+	items := CheckExternalConnectivity([]EndpointSpec{
+		{Name: "auth", URL: srv.URL + "/auth", ExpectedStatus: 401},
+		{Name: "portal", URL: srv.URL + "/portal", ExpectedStatus: 401},
+		{Name: "any", URL: srv.URL + "/"},
+		{Name: "down", URL: closed.URL, ExpectedStatus: 401},
+	})
+	want := []check.Status{check.StatusPass, check.StatusWarn, check.StatusPass, check.StatusFail}
+	for i, it := range items {
+		if it.Status != want[i] {
+			t.Errorf("%s: status %s (%s), want %s", it.Name, it.Status, it.Message, want[i])
+		}
+	}
+	if !strings.Contains(items[1].Message, "HTTP 302") {
+		t.Fatalf("portal message = %q", items[1].Message)
+	}
+}
+
+func TestProbeTimesOut(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer srv.Close()
+	defer close(release)
+	old := httpClient
+	httpClient = &http.Client{Timeout: 50 * time.Millisecond}
+	t.Cleanup(func() { httpClient = old })
+
+	it := probe(EndpointSpec{Name: "slow", URL: srv.URL})
+	if it.Status != check.StatusFail || !strings.Contains(it.Message, "no response") {
+		t.Fatalf("item = %+v", it)
+	}
+}
+
+func TestDefaultEndpoints(t *testing.T) {
+	specs, err := DefaultEndpoints()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, s := range specs {
+		names = append(names, s.Name)
+	}
+	for _, want := range []string{"OpenAI", "ElevenLabs", "Google", "Deepgram", "Azure"} {
+		if !strings.Contains(strings.Join(names, ","), want) {
+			t.Errorf("no %s endpoint in %v", want, names)
+		}
+	}
+}
+
+func TestLoadEndpoints(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "extra.yaml")
+	os.WriteFile(path, []byte("- name: Proxy\n  url: https://proxy.example.com/health\n  expected_status: 204\n"), 0o644)
+	specs, err := LoadEndpoints(path)
+	if err != nil || len(specs) != 1 || specs[0].ExpectedStatus != 204 {
+		t.Fatalf("specs = %+v, err = %v", specs, err)
+	}
+
+	path = filepath.Join(dir, "bad.json")
+	os.WriteFile(path, []byte(`[{"name":"x","url":"api.example.com"}]`), 0o644)
+	if _, err := LoadEndpoints(path); err == nil || !strings.Contains(err.Error(), "absolute") {
+		t.Fatalf("err = %v", err)
+	}
+}
@@ -0,0 +1,78 @@
+package check
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// OrderMode selects the order OutputText lists the results in.
+type OrderMode string
+
+const (
+	// OrderFailFirst lists failures, then warnings, skips and passes (the default).
+	OrderFailFirst OrderMode = "fail-first"
+	// OrderWarnFirst lists warnings, then failures, skips and passes.
+	OrderWarnFirst OrderMode = "warn-first"
+	// OrderPassFirst lists passes, then skips, warnings and failures, so the failures end up
+	// just above the summary.
+	OrderPassFirst OrderMode = "pass-first"
+	// OrderNameAsc lists items by name, ignoring case.
+	OrderNameAsc OrderMode = "name-asc"
+)
+
+// OrderModes lists the accepted modes in the order they are documented.
+var OrderModes = []OrderMode{OrderFailFirst, OrderWarnFirst, OrderPassFirst, OrderNameAsc}
+
+// statusRanks gives, per mode, the position of each status; items of equal rank keep their
+// run order.
+var statusRanks = map[OrderMode][]Status{
+	OrderFailFirst: {StatusFail, StatusWarn, StatusSkip, StatusPass},
+	OrderWarnFirst: {StatusWarn, StatusFail, StatusSkip, StatusPass},
+	OrderPassFirst: {StatusPass, StatusSkip, StatusWarn, StatusFail},
+}
+
+// OutputOptions controls OutputText. The zero value lists failures first.
+type OutputOptions struct {
+	ItemOrder OrderMode
+}
+
+// ParseOrderMode validates a mode name; "" is OrderFailFirst.
+func ParseOrderMode(name string) (OrderMode, error) {
+	if name == "" {
+		return OrderFailFirst, nil
+	}
+	m := OrderMode(strings.ToLower(name))
+	if !slices.Contains(OrderModes, m) {
+		return "", fmt.Errorf("unknown output order %q (want fail-first, warn-first, pass-first or name-asc)", name)
+	}
+	return m, nil
+}
+
+// orderItems returns a copy of items in mode's order.
+func orderItems(items []Item, mode OrderMode) []Item {
+	out := append([]Item(nil), items...)
+	if mode == "" {
+		mode = OrderFailFirst
+	}
+	switch mode {
+	case OrderNameAsc:
+		sort.SliceStable(out, func(i, j int) bool {
+			return strings.ToLower(out[i].Name) < strings.ToLower(out[j].Name)
+		})
+	default:
+		ranks, ok := statusRanks[mode]
+		if !ok {
+			return out
+		}
+		rank := func(s Status) int {
+			if i := slices.Index(ranks, s); i >= 0 {
+				return i
+			}
+			return len(ranks)
+		}
+		sort.SliceStable(out, func(i, j int) bool { return rank(out[i].Status) < rank(out[j].Status) })
+	}
+	return out
+}
@@ -0,0 +1,58 @@
+package check
+
+import (
+	"bytes"
+	"regexp"
+	"slices"
+	"testing"
+)
+
+func TestOutputTextItemOrder(t *testing.T) {
+	rep := &Report{Items: []Item{
+		{Name: "Docker CLI", Status: StatusPass, Message: "ok"},
+		{Name: "ARI", Status: StatusFail, Message: "down"},
+		{Name: "Env", Status: StatusWarn, Message: "key empty"},
+		{Name: "Call History DB", Status: StatusSkip, Message: "no container"},
+		{Name: "advertise Hosts", Status: StatusFail, Message: "mismatch"},
+	}}
+	line := regexp.MustCompile(`(?m)^\[(\d)/5\] (.+?)\.\.\.`)
+
+	for _, tc := range []struct {
+		mode OrderMode
+		want []string
+	}{
+		{"", []string{"ARI", "advertise Hosts", "Env", "Call History DB", "Docker CLI"}},
+		{OrderFailFirst, []string{"ARI", "advertise Hosts", "Env", "Call History DB", "Docker CLI"}},
+		{OrderWarnFirst, []string{"Env", "ARI", "advertise Hosts", "Call History DB", "Docker CLI"}},
+		{OrderPassFirst, []string{"Docker CLI", "Call History DB", "Env", "ARI", "advertise Hosts"}},
+		{OrderNameAsc, []string{"advertise Hosts", "ARI", "Call History DB", "Docker CLI", "Env"}},
+	} {
+		var buf bytes.Buffer
+		rep.OutputText(&buf, OutputOptions{ItemOrder: tc.mode})
+		var got []string
+		for i, m := range line.FindAllStringSubmatch(buf.String(), -1) {
+			if m[1] != string(rune('1'+i)) {
+				t.Errorf("%s: item %q numbered %s, want %d", tc.mode, m[2], m[1], i+1)
+			}
+			got = append(got, m[2])
+		}
+		if !slices.Equal(got, tc.want) {
+			t.Errorf("%q order = %v, want %v", tc.mode, got, tc.want)
+		}
+	}
+	if rep.Items[0].Name != "Docker CLI" {
+		t.Fatal("OutputText reordered Report.Items")
+	}
+}
+
+func TestParseOrderMode(t *testing.T) {
+	if m, err := ParseOrderMode("Fail-First"); err != nil || m != OrderFailFirst {
+		t.Fatalf("ParseOrderMode = %q, %v", m, err)
+	}
+	if m, _ := ParseOrderMode(""); m != OrderFailFirst {
+		t.Fatalf("empty mode = %q", m)
+	}
+	if _, err := ParseOrderMode("severity"); err == nil {
+		t.Fatal("expected an error for an unknown mode")
+	}
+}
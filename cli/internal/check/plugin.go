@@ -0,0 +1,226 @@
+package check
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"plugin"
+	"sort"
+	"sync"
+	"time"
+)
+
+// PluginDir holds compiled check plugins (*.so), relative to the repo root.
+const PluginDir = ".agent/checks"
+
+// PluginSymbol is the exported variable a plugin must define:
+//
+//	var Check check.CheckPlugin = sipTrunkCheck{}
+const PluginSymbol = "Check"
+
+// PluginTimeout bounds each plugin or registered check.
+const PluginTimeout = 5 * time.Second
+
+// CheckPlugin is a check from outside the built-in set: a Go plugin in PluginDir or a
+// function passed to Register. Extra checks run after the built-in checks and are reported
+// under Name(), so RunnerConfig demotions apply to them like any other item.
+type CheckPlugin interface {
+	Name() string
+	Run(ctx context.Context) Item
+}
+
+var (
+	registryMu sync.Mutex
+	registry   []CheckPlugin
+)
+
+// Register adds a native check that every Runner runs after the built-in checks. It is meant
+// for tests and for builds of the CLI that compile extra checks in.
+func Register(name string, fn func(ctx context.Context) Item) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry = append(registry, funcCheck{name: name, fn: fn})
+}
+
+// RegisterCritical is Register for a check whose failure must never be hidden: it is not
+// demoted by RunnerConfig.DemoteToInfo and is listed under "Critical failures" in OutputText.
+func RegisterCritical(name string, fn func(ctx context.Context) Item) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry = append(registry, funcCheck{name: name, fn: fn, critical: true})
+}
+
+type envPathKey struct{}
+
+// EnvPath returns the .env file of the run that called a plugin or registered check (it
+// follows --env-file and AGENT_ENV), or "" outside a run. Read it with secrets.LoadEnv so an
+// encrypted .env.age works too.
+func EnvPath(ctx context.Context) string {
+	path, _ := ctx.Value(envPathKey{}).(string)
+	return path
+}
+
+type composeRootKey struct{}
+
+// ComposeRoot returns the directory holding docker-compose.yml for the run that called a plugin
+// or registered check, or "" outside a run.
+func ComposeRoot(ctx context.Context) string {
+	root, _ := ctx.Value(composeRootKey{}).(string)
+	return root
+}
+
+func registered() []CheckPlugin {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	return append([]CheckPlugin(nil), registry...)
+}
+
+type funcCheck struct {
+	name     string
+	fn       func(ctx context.Context) Item
+	critical bool
+}
+
+func (c funcCheck) Name() string                 { return c.name }
+func (c funcCheck) Run(ctx context.Context) Item { return c.fn(ctx) }
+
+// LoadPlugins opens every *.so in dir (in name order) and looks up PluginSymbol. A plugin that
+// cannot be loaded yields a warning item instead; a missing dir yields nothing.
+//
+// Go plugins only load into a binary built with cgo and must be built from the same source
+// tree with the same toolchain as the agent binary. Release builds use CGO_ENABLED=0, so on
+// them every plugin is reported as unsupported (see pluginsSupported); declarative checks
+// (DeclarativeChecksPath) are the supported way to add checks to a stock binary.
+func LoadPlugins(dir string) ([]CheckPlugin, []Item) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.so"))
+	if err != nil {
+		return nil, []Item{pluginLoadItem(dir, err, pluginsSupported)}
+	}
+	sort.Strings(paths)
+
+	var (
+		plugins  []CheckPlugin
+		failures []Item
+	)
+	for _, path := range paths {
+		p, err := openPlugin(path)
+		if err != nil {
+			failures = append(failures, pluginLoadItem(path, err, pluginsSupported))
+			continue
+		}
+		plugins = append(plugins, p)
+	}
+	return plugins, failures
+}
+
+// openPlugin is replaced in tests; plugin.Open cannot load test-built objects.
+var openPlugin = func(path string) (CheckPlugin, error) {
+	p, err := plugin.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	sym, err := p.Lookup(PluginSymbol)
+	if err != nil {
+		return nil, err
+	}
+	switch c := sym.(type) {
+	case *CheckPlugin:
+		if *c == nil {
+			return nil, fmt.Errorf("%s is nil", PluginSymbol)
+		}
+		return *c, nil
+	case CheckPlugin:
+		return c, nil
+	default:
+		return nil, fmt.Errorf("%s is a %T, not a check.CheckPlugin", PluginSymbol, sym)
+	}
+}
+
+func pluginLoadItem(path string, err error, supported bool) Item {
+	if !supported {
+		return Item{
+			Name:        "Plugin " + filepath.Base(path),
+			Status:      StatusWarn,
+			Message:     ".so check plugins need a self-built cgo agent binary",
+			Details:     "this agent was built with CGO_ENABLED=0, as every release binary is, and cannot load Go plugins: " + err.Error(),
+			Remediation: "Rewrite the check as a declarative check in " + DeclarativeChecksPath + " (supported by release binaries), or build agent yourself with CGO_ENABLED=1 from the same source tree and Go version as the plugin",
+		}
+	}
+	return Item{
+		Name:        "Plugin " + filepath.Base(path),
+		Status:      StatusWarn,
+		Message:     "failed to load check plugin",
+		Details:     err.Error(),
+		Remediation: "Rebuild the plugin with go build -buildmode=plugin from the same source tree and Go version as agent (built with CGO_ENABLED=1), or remove it from " + PluginDir,
+	}
+}
+
+// loadPlugins returns the registered checks, then the declarative checks, then the plugins in
+// the plugin directory.
+func (r *Runner) loadPlugins() ([]CheckPlugin, []Item) {
+	dir := r.PluginDir
+	if dir == "" {
+		dir = r.repoPath(PluginDir)
+	}
+	declared, failures := r.loadDeclarative()
+	loaded, pluginFailures := LoadPlugins(dir)
+	checks := append(registered(), declared...)
+	return append(checks, loaded...), append(failures, pluginFailures...)
+}
+
+// runPlugins reports plugin load failures and runs checks, each bounded by PluginTimeout.
+func (r *Runner) runPlugins(p *runProgress, checks []CheckPlugin, failures []Item) {
+	for _, item := range failures {
+		p.begin(item.Name)
+		p.add(item)
+	}
+
+	ctx := context.WithValue(r.runContext(), envPathKey{}, r.hostEnvPath())
+	ctx = context.WithValue(ctx, composeRootKey{}, r.composeRoot())
+	steps := make([]checkStep, 0, len(checks))
+	for _, c := range checks {
+		c := c
+		if !r.selects(c.Name()) {
+			continue
+		}
+		steps = append(steps, checkStep{slot: p.reserve(c.Name()), run: func() Item {
+			return runSandboxed(ctx, c, PluginTimeout)
+		}})
+	}
+	r.runWave(p, steps...)
+}
+
+// runSandboxed runs c with a deadline and turns a panic or overrun into a failing item. Plugins
+// share the agent process, so this bounds how long the report waits rather than isolating them;
+// a plugin that ignores ctx keeps running in the background until agent exits.
+func runSandboxed(parent context.Context, c CheckPlugin, timeout time.Duration) Item {
+	if parent == nil {
+		parent = context.Background()
+	}
+	name := c.Name()
+	ctx, cancel := context.WithTimeout(parent, timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan Item, 1)
+	go func() {
+		defer func() {
+			if rec := recover(); rec != nil {
+				done <- Item{Name: name, Status: StatusFail, Message: "check panicked", Details: fmt.Sprint(rec)}
+			}
+		}()
+		done <- c.Run(ctx)
+	}()
+
+	select {
+	case item := <-done:
+		item.Name = name
+		if item.Status == "" {
+			item.Status = StatusFail
+			item.Message = "check returned no status"
+		}
+		return item
+	case <-ctx.Done():
+		return timeoutItem(name, ctx.Err(), time.Since(start))
+	}
+}
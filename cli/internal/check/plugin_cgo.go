@@ -0,0 +1,6 @@
+//go:build cgo && (linux || darwin || freebsd)
+
+package check
+
+// pluginsSupported reports whether this binary can load Go plugins at all.
+const pluginsSupported = true
@@ -0,0 +1,7 @@
+//go:build !cgo || !(linux || darwin || freebsd)
+
+package check
+
+// pluginsSupported reports whether this binary can load Go plugins at all. Binaries built with
+// CGO_ENABLED=0, which includes every release build, cannot.
+const pluginsSupported = false
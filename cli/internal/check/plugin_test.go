@@ -0,0 +1,139 @@
+package check
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func resetRegistry(t *testing.T) {
+	t.Helper()
+	registryMu.Lock()
+	saved := registry
+	registry = nil
+	registryMu.Unlock()
+	t.Cleanup(func() {
+		registryMu.Lock()
+		registry = saved
+		registryMu.Unlock()
+	})
+}
+
+func TestRunPluginsRunsRegisteredChecksInOrder(t *testing.T) {
+	resetRegistry(t)
+	Register("SIP Trunk", func(ctx context.Context) Item {
+		return Item{Name: "ignored", Status: StatusPass, Message: "registered"}
+	})
+	Register("Empty", func(ctx context.Context) Item { return Item{} })
+
+	p := &runProgress{rep: &Report{}}
+	p.begin("Host")
+	p.add(Item{Name: "Host", Status: StatusPass})
+	r := &Runner{PluginDir: t.TempDir()}
+	checks, failures := r.loadPlugins()
+	r.runPlugins(p, checks, failures)
+	p.ordered()
+
+	if len(p.rep.Items) != 3 {
+		t.Fatalf("items = %+v", p.rep.Items)
+	}
+	if got := p.rep.Items[1]; got.Name != "SIP Trunk" || got.Status != StatusPass {
+		t.Fatalf("plugin item should be reported under Name(): %+v", got)
+	}
+	if got := p.rep.Items[2]; got.Status != StatusFail || got.Message != "check returned no status" {
+		t.Fatalf("empty item should fail: %+v", got)
+	}
+}
+
+func TestRegisteredChecksSeeEnvPath(t *testing.T) {
+	resetRegistry(t)
+	var got string
+	Register("Env Reader", func(ctx context.Context) Item {
+		got = EnvPath(ctx)
+		return Item{Status: StatusPass}
+	})
+	p := &runProgress{rep: &Report{}}
+	r := &Runner{PluginDir: t.TempDir(), EnvFile: "/srv/aava/.env.staging"}
+	checks, failures := r.loadPlugins()
+	r.runPlugins(p, checks, failures)
+	if got != "/srv/aava/.env.staging" {
+		t.Fatalf("EnvPath = %q", got)
+	}
+	if EnvPath(context.Background()) != "" {
+		t.Fatal("EnvPath outside a run should be empty")
+	}
+}
+
+func TestRunSandboxedTimeoutAndPanic(t *testing.T) {
+	slow := funcCheck{name: "Slow", fn: func(ctx context.Context) Item {
+		<-ctx.Done()
+		time.Sleep(time.Second) // ignores cancellation for a while
+		return Item{Status: StatusPass}
+	}}
+	start := time.Now()
+	item := runSandboxed(context.Background(), slow, 20*time.Millisecond)
+	if item.Name != "Slow" || item.Status != StatusFail || item.Message != "check timed out" {
+		t.Fatalf("unexpected timeout item: %+v", item)
+	}
+	if time.Since(start) >= time.Second {
+		t.Fatal("runSandboxed waited for a check that ignores its deadline")
+	}
+
+	boom := funcCheck{name: "Boom", fn: func(ctx context.Context) Item { panic("nil map") }}
+	item = runSandboxed(context.Background(), boom, time.Second)
+	if item.Status != StatusFail || item.Message != "check panicked" || item.Details != "nil map" {
+		t.Fatalf("unexpected panic item: %+v", item)
+	}
+}
+
+func TestLoadPluginsReportsLoadErrors(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"b.so", "a.so", "notes.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("x"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	saved := openPlugin
+	t.Cleanup(func() { openPlugin = saved })
+	var opened []string
+	openPlugin = func(path string) (CheckPlugin, error) {
+		opened = append(opened, filepath.Base(path))
+		if filepath.Base(path) == "b.so" {
+			return nil, errors.New("plugin: not implemented")
+		}
+		return funcCheck{name: "A", fn: func(ctx context.Context) Item { return Item{Status: StatusPass} }}, nil
+	}
+
+	plugins, failures := LoadPlugins(dir)
+	if strings.Join(opened, ",") != "a.so,b.so" {
+		t.Fatalf("opened %v, want a.so,b.so", opened)
+	}
+	if len(plugins) != 1 || plugins[0].Name() != "A" {
+		t.Fatalf("plugins = %+v", plugins)
+	}
+	if len(failures) != 1 || failures[0].Name != "Plugin b.so" || failures[0].Status != StatusWarn {
+		t.Fatalf("failures = %+v", failures)
+	}
+
+	if plugins, failures := LoadPlugins(filepath.Join(dir, "missing")); len(plugins)+len(failures) != 0 {
+		t.Fatalf("missing dir should yield nothing: %v %v", plugins, failures)
+	}
+}
+
+func TestPluginLoadItemPointsStockBinariesAtDeclarativeChecks(t *testing.T) {
+	err := errors.New("plugin: not implemented")
+	item := pluginLoadItem("/repo/.agent/checks/sip.so", err, false)
+	if item.Name != "Plugin sip.so" || item.Status != StatusWarn {
+		t.Fatalf("%+v", item)
+	}
+	if !strings.Contains(item.Message, "self-built cgo") || !strings.Contains(item.Remediation, DeclarativeChecksPath) {
+		t.Fatalf("unsupported binary: %+v", item)
+	}
+	if item := pluginLoadItem("/repo/.agent/checks/sip.so", err, true); item.Message != "failed to load check plugin" {
+		t.Fatalf("cgo binary: %+v", item)
+	}
+}
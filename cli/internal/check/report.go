@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"strings"
 	"time"
 
 	"github.com/fatih/color"
@@ -16,14 +17,44 @@ const (
 	StatusWarn Status = "warn"
 	StatusFail Status = "fail"
 	StatusSkip Status = "skip"
+	// StatusInfo marks a warning or failure demoted via RunnerConfig.DemoteToInfo.
+	StatusInfo Status = "info"
 )
 
+// DefaultSlowThreshold is the per-item duration above which OutputText annotates
+// an item with its timing and lists it under "Slow checks".
+const DefaultSlowThreshold = 500 * time.Millisecond
+
 type Item struct {
 	Name        string `json:"name"`
 	Status      Status `json:"status"`
 	Message     string `json:"message"`
 	Details     string `json:"details,omitempty"`
 	Remediation string `json:"remediation,omitempty"`
+	DocURL      string `json:"doc_url,omitempty"`
+	ExitCode    int    `json:"exit_code"`
+
+	Duration time.Duration `json:"duration_ns,omitempty"`
+
+	// DemotedFrom records the original status of an item demoted to StatusInfo.
+	DemotedFrom Status `json:"demoted_from,omitempty"`
+	// Critical marks an item from a check that is always fatal when it fails (e.g. Docker not
+	// installed): its failure is never demoted, so it always fails the run.
+	Critical bool `json:"critical,omitempty"`
+	// PreviousStatus is the status in the last saved report, set on Report.ChangedItems.
+	PreviousStatus Status `json:"previous_status,omitempty"`
+}
+
+// ExitCode maps a status to the numeric code used by `agent check` (0=pass/skip/info, 1=warn, 2=fail).
+func (s Status) ExitCode() int {
+	switch s {
+	case StatusWarn:
+		return 1
+	case StatusFail:
+		return 2
+	default:
+		return 0
+	}
 }
 
 type Report struct {
@@ -37,12 +68,32 @@ type Report struct {
 	WarnCount int `json:"warn_count"`
 	FailCount int `json:"fail_count"`
 	SkipCount int `json:"skip_count"`
+	InfoCount int `json:"info_count"`
 	Total     int `json:"total"`
+
+	// SlowThreshold overrides DefaultSlowThreshold for text output when > 0.
+	SlowThreshold time.Duration `json:"-"`
+
+	// ChangedItems and PreviousTimestamp are set by CompareWith.
+	ChangedItems      []Item    `json:"changed_items,omitempty"`
+	PreviousTimestamp time.Time `json:"-"`
+	// FilterItems records the checks requested with agent check --item; the report then
+	// only holds those and their dependencies.
+	FilterItems []string `json:"filter_items,omitempty"`
+
+	// RegressionItems and BaselineTimestamp are set by CompareWithBaseline.
+	RegressionItems   []Item    `json:"regression_items,omitempty"`
+	BaselineTimestamp time.Time `json:"-"`
+
+	// OnlyChanges makes OutputText list ChangedItems instead of every item (agent check --since).
+	OnlyChanges bool `json:"-"`
 }
 
 func (r *Report) finalizeCounts() {
-	r.PassCount, r.WarnCount, r.FailCount, r.SkipCount = 0, 0, 0, 0
-	for _, item := range r.Items {
+	r.PassCount, r.WarnCount, r.FailCount, r.SkipCount, r.InfoCount = 0, 0, 0, 0, 0
+	for i := range r.Items {
+		item := &r.Items[i]
+		item.ExitCode = item.Status.ExitCode()
 		switch item.Status {
 		case StatusPass:
 			r.PassCount++
@@ -52,11 +103,73 @@ func (r *Report) finalizeCounts() {
 			r.FailCount++
 		case StatusSkip:
 			r.SkipCount++
+		case StatusInfo:
+			r.InfoCount++
 		}
 	}
 	r.Total = len(r.Items)
 }
 
+// Summarize returns a one-line, badge-style summary of the report for status boards:
+// "✓ all 12 checks passed", "⚠ 2 warnings" or "✗ 3 failures, 1 warning". It contains no
+// ANSI escapes; see SummarizeColored.
+func (r *Report) Summarize() string {
+	symbol, text, _ := r.summary()
+	return symbol + " " + text
+}
+
+// SummarizeColored is Summarize with the line in ANSI green, yellow or red. It does not
+// consult color.NoColor: callers that want plain output use Summarize.
+func (r *Report) SummarizeColored() string {
+	symbol, text, code := r.summary()
+	return "\x1b[" + code + "m" + symbol + " " + text + "\x1b[0m"
+}
+
+// summary returns the symbol, text and ANSI color code of the Summarize line.
+func (r *Report) summary() (symbol, text, code string) {
+	r.finalizeCounts()
+	switch {
+	case r.FailCount > 0:
+		text = plural(r.FailCount, "failure")
+		if r.WarnCount > 0 {
+			text += ", " + plural(r.WarnCount, "warning")
+		}
+		return "✗", text, "31"
+	case r.WarnCount > 0:
+		return "⚠", plural(r.WarnCount, "warning"), "33"
+	}
+	switch r.PassCount {
+	case 0:
+		text = "no checks failed"
+	case 1:
+		text = "1 check passed"
+	default:
+		text = fmt.Sprintf("all %d checks passed", r.PassCount)
+	}
+	if r.SkipCount > 0 {
+		text += fmt.Sprintf(", %d skipped", r.SkipCount)
+	}
+	return "✓", text, "32"
+}
+
+// CriticalFailures returns the failing Critical items, in run order.
+func (r *Report) CriticalFailures() []Item {
+	var out []Item
+	for _, item := range r.Items {
+		if item.Critical && item.Status == StatusFail {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
 func (r *Report) OutputJSON(w io.Writer) error {
 	r.finalizeCounts()
 	enc := json.NewEncoder(w)
@@ -64,8 +177,10 @@ func (r *Report) OutputJSON(w io.Writer) error {
 	return enc.Encode(r)
 }
 
-func (r *Report) OutputText(w io.Writer) {
+// OutputText prints the human-readable report, listing items in opts.ItemOrder.
+func (r *Report) OutputText(w io.Writer, opts OutputOptions) {
 	r.finalizeCounts()
+	r.outputRegressions(w)
 
 	green := color.New(color.FgGreen, color.Bold).SprintFunc()
 	yellow := color.New(color.FgYellow, color.Bold).SprintFunc()
@@ -88,8 +203,43 @@ func (r *Report) OutputText(w io.Writer) {
 		fmt.Fprintf(w, "%s %s\n", gray("Build:"), r.BuildTime)
 	}
 	fmt.Fprintln(w)
+	if len(r.FilterItems) > 0 {
+		deps := "the checks it depends on"
+		if len(r.FilterItems) > 1 {
+			deps = "the checks they depend on"
+		}
+		fmt.Fprintln(w, yellow("Partial report:")+" only "+strings.Join(r.FilterItems, ", ")+" and "+deps+" were run.")
+		fmt.Fprintln(w, gray("Run agent check without --item for the full report."))
+		fmt.Fprintln(w)
+	}
+	if critical := r.CriticalFailures(); len(critical) > 0 {
+		fmt.Fprintln(w, red("Critical failures (cannot be suppressed):"))
+		for _, item := range critical {
+			fmt.Fprintf(w, "  ❌ %s: %s\n", item.Name, item.Message)
+		}
+		fmt.Fprintln(w)
+	}
+
+	if r.OnlyChanges {
+		r.outputChanges(w)
+		return
+	}
 
-	for i, item := range r.Items {
+	slowThreshold := r.SlowThreshold
+	if slowThreshold <= 0 {
+		slowThreshold = DefaultSlowThreshold
+	}
+	var slow, info []Item
+
+	// Demoted items are listed under "Info:" instead of the numbered results.
+	shown := r.Total - r.InfoCount
+	n := 0
+	for _, item := range orderItems(r.Items, opts.ItemOrder) {
+		if item.Status == StatusInfo {
+			info = append(info, item)
+			continue
+		}
+		n++
 		var icon string
 		var paint func(a ...interface{}) string
 		switch item.Status {
@@ -110,25 +260,47 @@ func (r *Report) OutputText(w io.Writer) {
 			paint = blue
 		}
 
-		fmt.Fprintf(w, "[%d/%d] %-26s %s %s\n", i+1, r.Total, item.Name+"...", icon, paint(item.Message))
+		timing := ""
+		if item.Duration > slowThreshold {
+			timing = " " + gray(fmt.Sprintf("(%dms)", item.Duration.Milliseconds()))
+			slow = append(slow, item)
+		}
+		fmt.Fprintf(w, "[%d/%d] %-26s %s %s%s\n", n, shown, item.Name+"...", icon, paint(item.Message), timing)
 		if item.Details != "" {
 			fmt.Fprintf(w, "      %s\n", gray(item.Details))
 		}
 		if item.Remediation != "" && (item.Status == StatusFail || item.Status == StatusWarn) {
 			fmt.Fprintf(w, "      %s %s\n", yellow("Remediation:"), item.Remediation)
 		}
+		if item.DocURL != "" && (item.Status == StatusFail || item.Status == StatusWarn) {
+			fmt.Fprintf(w, "      %s %s\n", gray("Docs:"), item.DocURL)
+		}
+	}
+
+	if len(info) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, blue("Info:")+" "+gray("(demoted via "+RunnerConfigPath+")"))
+		for _, item := range info {
+			fmt.Fprintf(w, "  ℹ️  %s: %s %s\n", item.Name, item.Message, gray("(was "+string(item.DemotedFrom)+")"))
+			if item.Details != "" {
+				fmt.Fprintf(w, "      %s\n", gray(item.Details))
+			}
+		}
 	}
 
 	fmt.Fprintln(w)
 	fmt.Fprintln(w, gray("══════════════════════════════════════════"))
 	fmt.Fprintln(w, blue("Summary"))
-	fmt.Fprintf(w, "%s %d  %s %d  %s %d  %s %d  (%d total)\n",
+	fmt.Fprintf(w, "%s %d  %s %d  %s %d  %s %d",
 		green("PASS"), r.PassCount,
 		yellow("WARN"), r.WarnCount,
 		red("FAIL"), r.FailCount,
 		blue("SKIP"), r.SkipCount,
-		r.Total,
 	)
+	if r.InfoCount > 0 {
+		fmt.Fprintf(w, "  %s %d", blue("INFO"), r.InfoCount)
+	}
+	fmt.Fprintf(w, "  (%d total)\n", r.Total)
 
 	if r.FailCount > 0 {
 		fmt.Fprintln(w, red("Overall: FAIL (critical issues detected)"))
@@ -137,5 +309,13 @@ func (r *Report) OutputText(w io.Writer) {
 	} else {
 		fmt.Fprintln(w, green("Overall: PASS (system looks healthy)"))
 	}
+
+	if len(slow) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, yellow(fmt.Sprintf("Slow checks: (over %dms)", slowThreshold.Milliseconds())))
+		for _, item := range slow {
+			fmt.Fprintf(w, "  - %s: %dms\n", item.Name, item.Duration.Milliseconds())
+		}
+	}
 	fmt.Fprintln(w)
 }
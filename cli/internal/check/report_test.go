@@ -0,0 +1,85 @@
+package check
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOutputTextReportsSlowChecks(t *testing.T) {
+	rep := &Report{
+		Items: []Item{
+			{Name: "Fast", Status: StatusPass, Message: "ok", Duration: 10 * time.Millisecond},
+			{Name: "ARI", Status: StatusWarn, Message: "slow", Duration: 1200 * time.Millisecond},
+		},
+	}
+
+	var buf bytes.Buffer
+	rep.OutputText(&buf, OutputOptions{})
+	out := buf.String()
+	if !strings.Contains(out, "(1200ms)") {
+		t.Fatalf("expected timing on slow item, got:\n%s", out)
+	}
+	if strings.Contains(out, "(10ms)") {
+		t.Fatalf("did not expect timing on fast item, got:\n%s", out)
+	}
+	if !strings.Contains(out, "Slow checks:") || !strings.Contains(out, "- ARI: 1200ms") {
+		t.Fatalf("expected Slow checks section, got:\n%s", out)
+	}
+
+	buf.Reset()
+	rep.SlowThreshold = 2 * time.Second
+	rep.OutputText(&buf, OutputOptions{})
+	if strings.Contains(buf.String(), "Slow checks:") {
+		t.Fatalf("threshold override ignored:\n%s", buf.String())
+	}
+}
+
+func TestDemotedItemsListedUnderInfo(t *testing.T) {
+	rep := &Report{Items: []Item{
+		{Name: "Docker CLI", Status: StatusPass, Message: "docker found"},
+		{Name: "Container local_ai_server", Status: StatusWarn, Message: "not running"},
+		{Name: "Internet/DNS", Status: StatusFail, Message: "offline"},
+	}}
+	cfg := &RunnerConfig{DemoteToInfo: []string{"container LOCAL_AI_SERVER", "Internet/DNS"}}
+	cfg.applyDemotions(rep)
+
+	var buf bytes.Buffer
+	rep.OutputText(&buf, OutputOptions{})
+	out := buf.String()
+	if rep.WarnCount != 0 || rep.FailCount != 0 || rep.InfoCount != 2 {
+		t.Fatalf("counts: warn=%d fail=%d info=%d", rep.WarnCount, rep.FailCount, rep.InfoCount)
+	}
+	if rep.Items[2].DemotedFrom != StatusFail || rep.Items[2].ExitCode != 0 {
+		t.Fatalf("unexpected demoted item: %+v", rep.Items[2])
+	}
+	if !strings.Contains(out, "[1/1] Docker CLI") || !strings.Contains(out, "Info:") || !strings.Contains(out, "Internet/DNS: offline") {
+		t.Fatalf("unexpected output:\n%s", out)
+	}
+}
+
+func TestCriticalFailuresAreNotDemoted(t *testing.T) {
+	rep := &Report{Items: []Item{
+		{Name: "Docker CLI", Status: StatusFail, Message: "docker not found"},
+		{Name: "Docker Compose", Status: StatusWarn, Message: "compose v1"},
+		{Name: "Internet/DNS", Status: StatusFail, Message: "offline"},
+	}}
+	markCritical(rep)
+	cfg := &RunnerConfig{DemoteToInfo: []string{"Docker CLI", "Docker Compose", "Internet/DNS"}}
+	cfg.applyDemotions(rep)
+
+	var buf bytes.Buffer
+	rep.OutputText(&buf, OutputOptions{})
+	out := buf.String()
+	if rep.FailCount != 1 || rep.InfoCount != 2 || rep.Items[0].Status != StatusFail || rep.Items[0].ExitCode != 2 {
+		t.Fatalf("counts: fail=%d info=%d, items %+v", rep.FailCount, rep.InfoCount, rep.Items)
+	}
+	if got := rep.CriticalFailures(); len(got) != 1 || got[0].Name != "Docker CLI" {
+		t.Fatalf("CriticalFailures = %+v", got)
+	}
+	block := strings.Index(out, "Critical failures (cannot be suppressed):\n  ❌ Docker CLI: docker not found")
+	if block < 0 || block > strings.Index(out, "[1/1] Docker CLI") {
+		t.Fatalf("expected the critical block above the results:\n%s", out)
+	}
+}
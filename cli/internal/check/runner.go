@@ -2,28 +2,105 @@ package check
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"path/filepath"
+	"log/slog"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"runtime"
+	"runtime/debug"
 	"strings"
+	"sync"
 	"time"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/crash"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/logging"
 )
 
+// DefaultTimeout bounds a full diagnostics run so a hung probe (e.g. an ARI
+// connection that never answers) cannot block agent check forever.
+const DefaultTimeout = 30 * time.Second
+
 type Runner struct {
 	Verbose   bool
 	Version   string
 	BuildTime string
+
+	// EnvFile is the host .env used by host-side probes (default: ./.env or <git toplevel>/.env).
+	EnvFile string
+	// Env is the agent --env environment; unless EnvFile is set, host-side probes read
+	// .env.<Env> instead of .env.
+	Env string
+	// ARITimeout bounds the host-side ARI probe (default DefaultARITimeout).
+	ARITimeout time.Duration
+	// Config controls item demotions; when nil, RunnerConfigPath is loaded if present.
+	Config *RunnerConfig
+	// Concurrency is the number of independent checks run at once (default 1, sequential).
+	// Report order does not depend on it.
+	Concurrency int
+	// Logger receives per-check debug records and timeout warnings (default: discarded).
+	Logger *slog.Logger
+	// PluginDir holds check plugins (*.so) run after the built-in checks
+	// (default: PluginDir under the repo root).
+	PluginDir string
+	// ChecksFile holds declarative checks (see DeclarativeCheck) run alongside the plugins
+	// (default: DeclarativeChecksPath under the repo root).
+	ChecksFile string
+	// FilterItems limits the run to these checks (item names or their ItemKey form) plus the
+	// checks they depend on; the rest are not run and do not appear in the report.
+	FilterItems []string
+
+	// selected holds the ItemKeys chosen by FilterItems (nil runs everything).
+	selected map[string]bool
+	// ctx is set on the per-run copy of the Runner so probes can be cancelled.
+	ctx context.Context
 }
 
-func NewRunner(verbose bool, version, buildTime string) *Runner {
-	return &Runner{Verbose: verbose, Version: version, BuildTime: buildTime}
+// RunnerOption configures a Runner built by NewRunner.
+type RunnerOption func(*Runner)
+
+// WithDeclarativeChecks loads declarative checks from path instead of DeclarativeChecksPath.
+func WithDeclarativeChecks(path string) RunnerOption {
+	return func(r *Runner) { r.ChecksFile = path }
 }
 
-func (r *Runner) Run() (*Report, error) {
+func NewRunner(verbose bool, version, buildTime string, opts ...RunnerOption) *Runner {
+	r := &Runner{Verbose: verbose, Version: version, BuildTime: buildTime}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// ErrTimedOut is returned by Run when ctx expires before every check has finished.
+var ErrTimedOut = errors.New("agent check timed out")
+
+// ErrChecksFailed is returned by Run, with the complete report, when some check failed.
+var ErrChecksFailed = errors.New("agent check failed")
+
+// RunWithTimeout runs diagnostics with a deadline of d (no deadline when d <= 0).
+func (r *Runner) RunWithTimeout(ctx context.Context, d time.Duration) (*Report, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if d <= 0 {
+		return r.Run(ctx)
+	}
+	ctx, cancel := context.WithTimeout(ctx, d)
+	defer cancel()
+	return r.Run(ctx)
+}
+
+// Run executes all checks. If ctx is done before the checks finish, the items
+// completed so far are returned along with a failing "check timed out" item for
+// the probe that was still in flight; its subprocesses are killed.
+func (r *Runner) Run(ctx context.Context) (*Report, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
 	rep := &Report{
 		Version:   r.Version,
 		BuildTime: r.BuildTime,
@@ -31,69 +108,342 @@ func (r *Runner) Run() (*Report, error) {
 		Items:     []Item{},
 	}
 
-	// Host context (best-effort).
-	rep.Items = append(rep.Items, r.checkHost())
-
-	// Docker prerequisites.
-	if item := r.checkDockerCLI(); item.Status == StatusFail {
-		rep.Items = append(rep.Items, item)
+	cfg := r.Config
+	if cfg == nil {
+		loaded, err := LoadRunnerConfig(r.repoPath(RunnerConfigPath))
+		if err != nil {
+			rep.Items = append(rep.Items, Item{
+				Name:        "Check Config",
+				Status:      StatusWarn,
+				Message:     "ignoring invalid " + RunnerConfigPath,
+				Details:     err.Error(),
+				Remediation: "Fix or remove " + RunnerConfigPath,
+			})
+		}
+		cfg = loaded
+	}
+	log := r.Logger
+	if log == nil {
+		log = logging.Discard()
+	}
+	plugins, failures := r.loadPlugins()
+	runCopy := *r
+	runCopy.ctx = ctx
+	if len(r.FilterItems) > 0 {
+		decls := append([]checkDecl(nil), builtinChecks...)
+		for _, c := range plugins {
+			decls = append(decls, checkDecl{Name: c.Name()})
+		}
+		selected, err := resolveFilter(decls, r.FilterItems)
+		if err != nil {
+			return nil, err
+		}
+		runCopy.selected = selected
+		rep.FilterItems = append([]string(nil), r.FilterItems...)
+		failures = nil // plugins that failed to load cannot be what was asked for
+	}
+	p := &runProgress{rep: rep, base: len(rep.Items), log: log}
+
+	done := make(chan error, 1)
+	go func() {
+		// Panics here are out of reach of the recovery agent installs around the command.
+		defer func() {
+			if rec := recover(); rec != nil {
+				done <- fmt.Errorf("diagnostics crashed: %s", crashDetails(rec))
+			}
+		}()
+		err := runCopy.runChecks(p)
+		runCopy.runPlugins(p, plugins, failures)
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		p.ordered()
+		attachDocURLs(rep)
+		markCritical(rep)
+		cfg.applyDemotions(rep)
 		rep.finalizeCounts()
-		return rep, errors.New("docker not available")
-	} else {
-		rep.Items = append(rep.Items, item)
+		if err != nil {
+			return rep, err
+		}
+		if rep.FailCount > 0 {
+			return rep, ErrChecksFailed
+		}
+		return rep, nil
+	case <-ctx.Done():
+		log.Warn("diagnostics timed out", "error", ctx.Err())
+		partial := p.timedOut(ctx.Err())
+		attachDocURLs(partial)
+		markCritical(partial)
+		cfg.applyDemotions(partial)
+		partial.finalizeCounts()
+		return partial, ErrTimedOut
 	}
-	rep.Items = append(rep.Items, r.checkDockerDaemon())
-	rep.Items = append(rep.Items, r.checkCompose())
+}
 
-	// Container must exist for docker-exec probes.
-	inspect, inspectItem := r.inspectContainer("ai_engine")
-	rep.Items = append(rep.Items, inspectItem)
-	if inspectItem.Status == StatusFail {
-		rep.finalizeCounts()
-		return rep, errors.New("ai_engine container not available")
+// runProgress tracks the checks completed so far and those in flight, so a
+// timed-out run can still report partial results. Each check owns a slot,
+// reserved in report order; items are appended to rep.Items as they complete
+// and put back into slot order by ordered.
+type runProgress struct {
+	mu    sync.Mutex
+	rep   *Report
+	base  int // items already in rep.Items when the checks started
+	slots []progressSlot
+	last  int
+	log   *slog.Logger // nil in tests
+}
+
+type progressSlot struct {
+	name    string
+	started time.Time
+	done    bool
+	item    Item
+}
+
+// reserve registers a check that has not started yet and returns its slot.
+func (p *runProgress) reserve(name string) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.slots = append(p.slots, progressSlot{name: name})
+	return len(p.slots) - 1
+}
+
+func (p *runProgress) name(slot int) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.slots[slot].name
+}
+
+func (p *runProgress) start(slot int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.slots[slot].started = time.Now()
+	p.last = slot
+	if p.log != nil {
+		p.log.Debug("check started", "check", p.slots[slot].name)
 	}
+}
 
-	rep.Items = append(rep.Items, r.checkNetworkMode(inspect))
-	rep.Items = append(rep.Items, r.checkMounts(inspect))
+// begin reserves and starts a check in one step (sequential use).
+func (p *runProgress) begin(name string) {
+	p.start(p.reserve(name))
+}
 
-	// Local AI server status (always reported; WARN if not running).
-	localAIInspect, localAIItem := r.inspectOptionalContainer("local_ai_server")
-	rep.Items = append(rep.Items, localAIItem)
-	rep.Items = append(rep.Items, r.checkModelsMount(inspect, localAIInspect))
+// add completes the most recently started check.
+func (p *runProgress) add(item Item) Item {
+	p.mu.Lock()
+	slot := p.last
+	p.mu.Unlock()
+	return p.finish(slot, item)
+}
 
-	// In-container probes (python-only; no curl).
-	rep.Items = append(rep.Items, r.checkInContainerPaths())
-	rep.Items = append(rep.Items, r.checkCallHistorySQLite())
+// finish records a slot's item along with the time spent since it started, unless the
+// check already measured a more precise duration (e.g. a network round trip).
+func (p *runProgress) finish(slot int, item Item) Item {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if item.Duration == 0 {
+		item.Duration = time.Since(p.slots[slot].started)
+	}
+	p.slots[slot].done = true
+	p.slots[slot].item = item
+	p.rep.Items = append(p.rep.Items, item)
+	if p.log != nil {
+		p.log.Debug("check finished", "check", item.Name, "status", string(item.Status), "duration_ms", item.Duration.Milliseconds())
+	}
+	return item
+}
 
-	cfg, cfgItem := r.readEffectiveConfig()
-	rep.Items = append(rep.Items, cfgItem)
+// ordered rewrites rep.Items in slot order once all checks have finished.
+func (p *runProgress) ordered() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	items := append([]Item(nil), p.rep.Items[:p.base]...)
+	for _, s := range p.slots {
+		if s.done {
+			items = append(items, s.item)
+		}
+	}
+	p.rep.Items = items
+}
 
-	env, envItem := r.readEnvSummary()
-	rep.Items = append(rep.Items, envItem)
+func (p *runProgress) timedOut(cause error) *Report {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	partial := *p.rep
+	partial.Items = append([]Item(nil), p.rep.Items[:p.base]...)
+	pending := 0
+	for _, s := range p.slots {
+		switch {
+		case s.done:
+			partial.Items = append(partial.Items, s.item)
+		case !s.started.IsZero():
+			pending++
+			partial.Items = append(partial.Items, timeoutItem(s.name, cause, time.Since(s.started)))
+		}
+	}
+	if pending == 0 {
+		partial.Items = append(partial.Items, timeoutItem("agent check", cause, 0))
+	}
+	return &partial
+}
 
-	rep.Items = append(rep.Items, r.checkTransportCompatibility(cfg))
-	rep.Items = append(rep.Items, r.checkAdvertiseHosts(cfg, env, inspect))
+func timeoutItem(name string, cause error, d time.Duration) Item {
+	return Item{
+		Name:        name,
+		Status:      StatusFail,
+		Message:     "check timed out",
+		Details:     errString(cause),
+		Remediation: "Re-run with a larger --check-timeout, or investigate why this probe hangs",
+		Duration:    d,
+	}
+}
 
-	ari, ariItem := r.probeARI(cfg, env)
-	rep.Items = append(rep.Items, ariItem)
-	rep.Items = append(rep.Items, r.dialplanGuidance(cfg, env, ari))
+// checkStep is a check bound to its report slot; a negative slot marks a check left out by
+// Runner.FilterItems, which runWave skips.
+type checkStep struct {
+	slot int
+	run  func() Item
+}
 
-	rep.Items = append(rep.Items, r.bestEffortNetwork(env))
+// crashDetails writes a crash report for panic value v (see crash.WriteReport) and describes
+// it for the report. Call it from the deferred function that recovered v, so the stack is the
+// panicking goroutine's.
+func crashDetails(v any) string {
+	path, err := crash.WriteReport(v, debug.Stack())
+	if err != nil {
+		return fmt.Sprintf("%v (could not write a crash report: %v)", v, err)
+	}
+	return fmt.Sprintf("%v; crash report: %s (agent crash show %s)", v, path, filepath.Base(path))
+}
 
-	rep.finalizeCounts()
-	if rep.FailCount > 0 {
-		return rep, errors.New("agent check failed")
+// runWave runs steps with at most r.Concurrency in flight and returns their items in step
+// order. Each item is written by exactly one goroutine.
+func (r *Runner) runWave(p *runProgress, steps ...checkStep) []Item {
+	limit := r.Concurrency
+	if limit < 1 {
+		limit = 1
+	}
+	items := make([]Item, len(steps))
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for i, s := range steps {
+		if s.slot < 0 {
+			continue
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, s checkStep) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			// A panicking check fails on its own instead of taking the run down with it.
+			defer func() {
+				if rec := recover(); rec != nil {
+					items[i] = p.finish(s.slot, Item{Name: p.name(s.slot), Status: StatusFail, Message: "check panicked", Details: crashDetails(rec)})
+				}
+			}()
+			p.start(s.slot)
+			items[i] = p.finish(s.slot, s.run())
+		}(i, s)
 	}
-	if rep.WarnCount > 0 {
-		return rep, nil
+	wg.Wait()
+	return items
+}
+
+func (r *Runner) runChecks(p *runProgress) error {
+	var (
+		inspect, localAIInspect *containerInspect
+		cfg                     *configSummary
+		env                     *envSummary
+		ari                     *ariProbe
+	)
+	step := func(name string, run func() Item) checkStep {
+		if !r.selects(name) {
+			return checkStep{slot: -1}
+		}
+		return checkStep{slot: p.reserve(name), run: run}
+	}
+
+	// Report order is the declaration order below; checks then run in dependency waves.
+	// Dependencies between them are declared in builtinChecks.
+	host := step("Host", r.checkHost)
+	contexts := step("Context Files", r.CheckContextFiles)
+	backups := step("Backup Freshness", r.checkBackupFreshness)
+	backupCrypt := step("Backup Encryption", r.checkBackupEncryption)
+	dockerCLI := step("Docker CLI", r.checkDockerCLI)
+	daemon := step("Docker Daemon", r.checkDockerDaemon)
+	compose := step("Docker Compose", r.checkCompose)
+	composeFile := step("Compose File", r.checkComposeFile)
+	images := step("Image Pullability", r.checkImagePullability)
+	engine := step("Container ai_engine", func() (item Item) {
+		inspect, item = r.inspectContainer("ai_engine")
+		return item
+	})
+	network := step("Network Mode", func() Item { return r.checkNetworkMode(inspect) })
+	mounts := step("Mounts", func() Item { return r.checkMounts(inspect) })
+	// Local AI server status (always reported; WARN if not running).
+	localAI := step("Container local_ai_server", func() (item Item) {
+		localAIInspect, item = r.inspectOptionalContainer("local_ai_server")
+		return item
+	})
+	models := step("Local AI Models", func() Item { return r.checkModelsMount(inspect, localAIInspect) })
+	// In-container probes (python-only; no curl).
+	paths := step("In-Container Paths", r.checkInContainerPaths)
+	callHistory := step("Call History DB", r.checkCallHistorySQLite)
+	config := step("Config", func() (item Item) {
+		cfg, item = r.readEffectiveConfig()
+		return item
+	})
+	envStep := step("Env", func() (item Item) {
+		env, item = r.readEnvSummary()
+		return item
+	})
+	transport := step("Transport Compatibility", func() Item { return r.checkTransportCompatibility(cfg) })
+	advertise := step("Advertise Hosts", func() Item { return r.checkAdvertiseHosts(cfg, env, inspect) })
+	ariConn := step("ARI Connectivity", r.CheckARI)
+	ariStep := step("ARI", func() (item Item) {
+		ari, item = r.probeARI(cfg, env)
+		return item
+	})
+	dialplan := step("Dialplan", func() Item { return r.dialplanGuidance(cfg, env, ari) })
+	internet := step("Internet/DNS", func() Item { return r.bestEffortNetwork(env) })
+
+	// Host context (best-effort).
+	r.runWave(p, host, contexts, backups, backupCrypt)
+
+	// Docker prerequisites.
+	if r.runWave(p, dockerCLI)[0].Status == StatusFail {
+		return errors.New("docker not available")
+	}
+	r.runWave(p, daemon, compose)
+	r.runWave(p, composeFile, images)
+
+	// Container must exist for docker-exec probes.
+	if r.runWave(p, engine)[0].Status == StatusFail {
+		return errors.New("ai_engine container not available")
 	}
-	return rep, nil
+
+	r.runWave(p, network, mounts, localAI, paths, callHistory, config, envStep, ariConn)
+	r.runWave(p, models, transport, advertise, ariStep, internet)
+	r.runWave(p, dialplan)
+	return nil
+}
+
+// command builds an exec.Cmd bound to the run's context so it is killed on timeout.
+func (r *Runner) command(name string, args ...string) *exec.Cmd {
+	ctx := r.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return exec.CommandContext(ctx, name, args...)
 }
 
 func (r *Runner) checkHost() Item {
 	host, _ := os.Hostname()
 	kernel := runtime.GOOS + "/" + runtime.GOARCH
-	if out, err := exec.Command("uname", "-r").Output(); err == nil {
+	if out, err := r.command("uname", "-r").Output(); err == nil {
 		kernel = strings.TrimSpace(string(out))
 	}
 
@@ -118,7 +468,7 @@ func (r *Runner) checkDockerCLI() Item {
 }
 
 func (r *Runner) checkDockerDaemon() Item {
-	cmd := exec.Command("docker", "info")
+	cmd := r.command("docker", "info")
 	if out, err := cmd.CombinedOutput(); err != nil {
 		return Item{
 			Name:        "Docker Daemon",
@@ -129,7 +479,7 @@ func (r *Runner) checkDockerDaemon() Item {
 		}
 	}
 
-	versionOut, _ := exec.Command("docker", "version", "--format", "{{.Server.Version}}").Output()
+	versionOut, _ := r.command("docker", "version", "--format", "{{.Server.Version}}").Output()
 	version := strings.TrimSpace(string(versionOut))
 	if version == "" {
 		version = "unknown"
@@ -138,7 +488,7 @@ func (r *Runner) checkDockerDaemon() Item {
 }
 
 func (r *Runner) checkCompose() Item {
-	out, err := exec.Command("docker", "compose", "version", "--short").CombinedOutput()
+	out, err := r.command("docker", "compose", "version", "--short").CombinedOutput()
 	if err != nil {
 		return Item{
 			Name:        "Docker Compose",
@@ -182,7 +532,7 @@ type containerInspect struct {
 }
 
 func (r *Runner) inspectContainer(name string) (*containerInspect, Item) {
-	out, err := exec.Command("docker", "inspect", name).CombinedOutput()
+	out, err := r.command("docker", "inspect", name).CombinedOutput()
 	if err != nil {
 		return nil, Item{
 			Name:        "Container " + name,
@@ -194,14 +544,16 @@ func (r *Runner) inspectContainer(name string) (*containerInspect, Item) {
 	}
 	var arr []containerInspect
 	if err := json.Unmarshal(out, &arr); err != nil || len(arr) == 0 {
-		return nil, Item{Name: "Container " + name, Status: StatusFail, Message: "inspect parse failed", Details: errString(err)}
+		return nil, Item{Name: "Container " + name, Status: StatusFail, Message: "inspect parse failed", Details: errString(err), Remediation: "Check that the docker CLI and daemon versions match (docker version)"}
 	}
 	ci := arr[0]
 	msg := "running"
 	st := StatusPass
+	remediation := ""
 	if !ci.State.Running {
 		msg = "not running"
 		st = StatusFail
+		remediation = "Run: docker compose -p asterisk-ai-voice-agent up -d " + name + " (then check: docker logs " + name + ")"
 	}
 
 	health := ""
@@ -219,15 +571,16 @@ func (r *Runner) inspectContainer(name string) (*containerInspect, Item) {
 	}
 
 	return &ci, Item{
-		Name:    "Container " + name,
-		Status:  st,
-		Message: msg,
-		Details: strings.Join(details, "\n"),
+		Name:        "Container " + name,
+		Status:      st,
+		Message:     msg,
+		Details:     strings.Join(details, "\n"),
+		Remediation: remediation,
 	}
 }
 
 func (r *Runner) inspectOptionalContainer(name string) (*containerInspect, Item) {
-	out, err := exec.Command("docker", "inspect", name).CombinedOutput()
+	out, err := r.command("docker", "inspect", name).CombinedOutput()
 	if err != nil {
 		return nil, Item{
 			Name:        "Container " + name,
@@ -239,7 +592,7 @@ func (r *Runner) inspectOptionalContainer(name string) (*containerInspect, Item)
 	}
 	var arr []containerInspect
 	if err := json.Unmarshal(out, &arr); err != nil || len(arr) == 0 {
-		return nil, Item{Name: "Container " + name, Status: StatusWarn, Message: "inspect parse failed", Details: errString(err)}
+		return nil, Item{Name: "Container " + name, Status: StatusWarn, Message: "inspect parse failed", Details: errString(err), Remediation: "Check that the docker CLI and daemon versions match (docker version)"}
 	}
 	ci := arr[0]
 	msg := "running"
@@ -457,7 +810,7 @@ func dockerHostIsRemote() bool {
 }
 
 func (r *Runner) dockerExecPython(script string) ([]byte, error) {
-	cmd := exec.Command("docker", "exec", "-i", "ai_engine", "python", "-")
+	cmd := r.command("docker", "exec", "-i", "ai_engine", "python", "-")
 	cmd.Stdin = strings.NewReader(script)
 	out, err := cmd.CombinedOutput()
 	if err != nil {
@@ -492,7 +845,7 @@ print(json.dumps({"paths": [test_path(p) for p in paths]}))
 `
 	out, err := r.dockerExecPython(script)
 	if err != nil {
-		return Item{Name: "In-Container Paths", Status: StatusFail, Message: "probe failed", Details: err.Error()}
+		return Item{Name: "In-Container Paths", Status: StatusFail, Message: "probe failed", Details: err.Error(), Remediation: "Check that ai_engine is running and python is available: docker exec ai_engine python -V"}
 	}
 	var res struct {
 		Paths []struct {
@@ -503,7 +856,7 @@ print(json.dumps({"paths": [test_path(p) for p in paths]}))
 		} `json:"paths"`
 	}
 	if err := json.Unmarshal(bytes.TrimSpace(out), &res); err != nil {
-		return Item{Name: "In-Container Paths", Status: StatusFail, Message: "invalid probe output", Details: string(out)}
+		return Item{Name: "In-Container Paths", Status: StatusFail, Message: "invalid probe output", Details: string(out), Remediation: "Re-run with -v and check ai_engine logs: docker logs ai_engine"}
 	}
 	var bad []string
 	var details []string
@@ -550,7 +903,7 @@ print(json.dumps(res))
 `
 	out, err := r.dockerExecPython(script)
 	if err != nil {
-		return Item{Name: "Call History DB", Status: StatusFail, Message: "sqlite test failed", Details: err.Error()}
+		return Item{Name: "Call History DB", Status: StatusFail, Message: "sqlite test failed", Details: err.Error(), Remediation: "Ensure ./data is mounted to /app/data and writable (see preflight.sh guidance)."}
 	}
 	var res struct {
 		DBDir    string  `json:"db_dir"`
@@ -559,7 +912,7 @@ print(json.dumps(res))
 		Error    *string `json:"error"`
 	}
 	if err := json.Unmarshal(bytes.TrimSpace(out), &res); err != nil {
-		return Item{Name: "Call History DB", Status: StatusFail, Message: "invalid probe output", Details: string(out)}
+		return Item{Name: "Call History DB", Status: StatusFail, Message: "invalid probe output", Details: string(out), Remediation: "Re-run with -v and check ai_engine logs: docker logs ai_engine"}
 	}
 	if !res.OK {
 		d := fmt.Sprintf("dir=%s\ntest=%s", res.DBDir, res.TestPath)
@@ -653,7 +1006,7 @@ print(json.dumps(out))
 `
 	raw, err := r.dockerExecPython(script)
 	if err != nil {
-		return nil, Item{Name: "Config", Status: StatusFail, Message: "cannot read /app/config/ai-agent.yaml", Details: err.Error()}
+		return nil, Item{Name: "Config", Status: StatusFail, Message: "cannot read /app/config/ai-agent.yaml", Details: err.Error(), Remediation: "Ensure config/ai-agent.yaml exists and ./config is mounted to /app/config"}
 	}
 	var res struct {
 		OK      bool          `json:"ok"`
@@ -661,7 +1014,7 @@ print(json.dumps(out))
 		Summary configSummary `json:"summary"`
 	}
 	if err := json.Unmarshal(bytes.TrimSpace(raw), &res); err != nil {
-		return nil, Item{Name: "Config", Status: StatusFail, Message: "invalid probe output", Details: string(raw)}
+		return nil, Item{Name: "Config", Status: StatusFail, Message: "invalid probe output", Details: string(raw), Remediation: "Re-run with -v and check ai_engine logs: docker logs ai_engine"}
 	}
 	if !res.OK {
 		msg := "failed to parse config"
@@ -715,11 +1068,11 @@ print(json.dumps(out))
 `
 	raw, err := r.dockerExecPython(script)
 	if err != nil {
-		return nil, Item{Name: "Env", Status: StatusWarn, Message: "cannot read env from container", Details: err.Error()}
+		return nil, Item{Name: "Env", Status: StatusWarn, Message: "cannot read env from container", Details: err.Error(), Remediation: "Check that ai_engine is running and loads .env (env_file in docker-compose.yml)"}
 	}
 	var env envSummary
 	if err := json.Unmarshal(bytes.TrimSpace(raw), &env); err != nil {
-		return nil, Item{Name: "Env", Status: StatusWarn, Message: "invalid env probe output", Details: string(raw)}
+		return nil, Item{Name: "Env", Status: StatusWarn, Message: "invalid env probe output", Details: string(raw), Remediation: "Re-run with -v and check ai_engine logs: docker logs ai_engine"}
 	}
 
 	details := []string{
@@ -907,11 +1260,11 @@ print(json.dumps(out))
 
 	raw, err := r.dockerExecPython(script)
 	if err != nil {
-		return nil, Item{Name: "ARI", Status: StatusFail, Message: "probe failed", Details: err.Error()}
+		return nil, Item{Name: "ARI", Status: StatusFail, Message: "probe failed", Details: err.Error(), Remediation: "Set ASTERISK_HOST in .env to the IP of your Asterisk server and check it is reachable from ai_engine"}
 	}
 	var probe ariProbe
 	if err := json.Unmarshal(bytes.TrimSpace(raw), &probe); err != nil {
-		return nil, Item{Name: "ARI", Status: StatusFail, Message: "invalid probe output", Details: string(raw)}
+		return nil, Item{Name: "ARI", Status: StatusFail, Message: "invalid probe output", Details: string(raw), Remediation: "Re-run with -v and check ai_engine logs: docker logs ai_engine"}
 	}
 
 	if !probe.OK {
@@ -0,0 +1,67 @@
+package check
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RunnerConfigPath is the optional per-install check configuration, relative to the repo root.
+const RunnerConfigPath = ".agent/check-config.yaml"
+
+// RunnerConfig tunes how check results are reported for a given install.
+//
+// Example .agent/check-config.yaml:
+//
+//	demote_to_info:
+//	  - Container local_ai_server
+//	  - Internet/DNS
+type RunnerConfig struct {
+	// DemoteToInfo lists item names (case-insensitive) whose warnings or failures are
+	// reported as StatusInfo instead, for checks an environment cannot satisfy. Failures of
+	// Critical items (Docker CLI, Docker Daemon, Docker Compose) are not demoted.
+	DemoteToInfo []string `yaml:"demote_to_info"`
+}
+
+// LoadRunnerConfig reads path. A missing file yields an empty config.
+func LoadRunnerConfig(path string) (*RunnerConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &RunnerConfig{}, nil
+		}
+		return nil, err
+	}
+	var cfg RunnerConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+// applyDemotions rewrites warn/fail items listed in DemoteToInfo to StatusInfo. Failing
+// Critical items are left alone.
+func (c *RunnerConfig) applyDemotions(rep *Report) {
+	if c == nil || len(c.DemoteToInfo) == 0 {
+		return
+	}
+	demote := map[string]bool{}
+	for _, name := range c.DemoteToInfo {
+		demote[strings.ToLower(strings.TrimSpace(name))] = true
+	}
+	for i := range rep.Items {
+		item := &rep.Items[i]
+		if item.Status != StatusWarn && item.Status != StatusFail {
+			continue
+		}
+		if item.Critical && item.Status == StatusFail {
+			continue
+		}
+		if demote[strings.ToLower(item.Name)] {
+			item.DemotedFrom = item.Status
+			item.Status = StatusInfo
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package check
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/crash"
+)
+
+func TestRunProgressTimedOutKeepsCompletedItems(t *testing.T) {
+	p := &runProgress{rep: &Report{Version: "v1"}}
+	p.begin("Host")
+	p.add(Item{Name: "Host", Status: StatusPass, Message: "ok"})
+	p.begin("ARI")
+
+	partial := p.timedOut(context.DeadlineExceeded)
+	partial.finalizeCounts()
+
+	if len(partial.Items) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(partial.Items))
+	}
+	last := partial.Items[1]
+	if last.Name != "ARI" || last.Status != StatusFail || last.Message != "check timed out" {
+		t.Fatalf("unexpected timeout item: %+v", last)
+	}
+	if partial.FailCount != 1 || partial.Version != "v1" {
+		t.Fatalf("unexpected counts/header: fail=%d version=%q", partial.FailCount, partial.Version)
+	}
+	if len(p.rep.Items) != 1 {
+		t.Fatalf("timedOut must not mutate the live report")
+	}
+}
+
+func TestRunWaveRecoversPanickingStep(t *testing.T) {
+	root := t.TempDir()
+	orig := crash.Root
+	crash.Root = func() (string, error) { return root, nil }
+	t.Cleanup(func() { crash.Root = orig })
+
+	p := &runProgress{rep: &Report{}}
+	r := &Runner{Concurrency: 2}
+	boom := checkStep{slot: p.reserve("Boom"), run: func() Item { panic("nil map") }}
+	ok := checkStep{slot: p.reserve("OK"), run: func() Item { return Item{Name: "OK", Status: StatusPass} }}
+
+	items := r.runWave(p, boom, ok)
+	if items[0].Name != "Boom" || items[0].Status != StatusFail || !strings.Contains(items[0].Details, "nil map") {
+		t.Fatalf("panicking step = %+v", items[0])
+	}
+	if items[1].Status != StatusPass {
+		t.Fatalf("other step = %+v", items[1])
+	}
+	reports, _ := filepath.Glob(filepath.Join(root, crash.Dir, "*"))
+	if len(reports) != 1 || !strings.Contains(items[0].Details, filepath.Base(reports[0])) {
+		t.Fatalf("crash reports = %v, details = %q", reports, items[0].Details)
+	}
+}
+
+func TestRunWaveKeepsSlotOrder(t *testing.T) {
+	p := &runProgress{rep: &Report{}}
+	r := &Runner{Concurrency: 4}
+	var steps []checkStep
+	names := []string{"A", "B", "C", "D"}
+	for i, name := range names {
+		name, delay := name, time.Duration(len(names)-i)*10*time.Millisecond
+		steps = append(steps, checkStep{slot: p.reserve(name), run: func() Item {
+			time.Sleep(delay)
+			return Item{Name: name, Status: StatusPass}
+		}})
+	}
+	later := checkStep{slot: p.reserve("E"), run: func() Item { return Item{Name: "E", Status: StatusPass} }}
+
+	start := time.Now()
+	r.runWave(p, later)
+	items := r.runWave(p, steps...)
+	if elapsed := time.Since(start); elapsed >= 100*time.Millisecond {
+		t.Fatalf("wave did not run concurrently (%s)", elapsed)
+	}
+	for i, item := range items {
+		if item.Name != names[i] {
+			t.Fatalf("runWave items out of order: %+v", items)
+		}
+	}
+
+	p.ordered()
+	var got []string
+	for _, item := range p.rep.Items {
+		got = append(got, item.Name)
+	}
+	if strings.Join(got, "") != "ABCDE" {
+		t.Fatalf("report order = %v, want A B C D E", got)
+	}
+}
+
+func TestLoadRunnerConfig(t *testing.T) {
+	dir := t.TempDir()
+	cfg, err := LoadRunnerConfig(filepath.Join(dir, "missing.yaml"))
+	if err != nil || len(cfg.DemoteToInfo) != 0 {
+		t.Fatalf("missing file: %+v %v", cfg, err)
+	}
+	path := filepath.Join(dir, "check-config.yaml")
+	if err := os.WriteFile(path, []byte("demote_to_info:\n  - Internet/DNS\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err = LoadRunnerConfig(path)
+	if err != nil || len(cfg.DemoteToInfo) != 1 || cfg.DemoteToInfo[0] != "Internet/DNS" {
+		t.Fatalf("got %+v %v", cfg, err)
+	}
+}
+
+func TestRunProgressLogsCheckResults(t *testing.T) {
+	var buf bytes.Buffer
+	p := &runProgress{rep: &Report{}, log: slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))}
+	p.begin("ARI")
+	p.add(Item{Name: "ARI", Status: StatusFail, Duration: 1500 * time.Millisecond})
+
+	var finished map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var rec map[string]any
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatalf("invalid JSON log line %q: %v", line, err)
+		}
+		if rec["msg"] == "check finished" {
+			finished = rec
+		}
+	}
+	if finished == nil || finished["level"] != "DEBUG" || finished["check"] != "ARI" || finished["status"] != "fail" || finished["duration_ms"] != float64(1500) {
+		t.Fatalf("unexpected log output:\n%s", buf.String())
+	}
+}
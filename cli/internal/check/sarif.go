@@ -0,0 +1,200 @@
+package check
+
+import (
+	"encoding/json"
+	"io"
+	"path"
+	"strings"
+)
+
+const (
+	// SARIFVersion is the SARIF spec version emitted by OutputSARIF.
+	SARIFVersion = "2.1.0"
+	// SARIFSchemaURI is the published JSON schema for SARIFVersion.
+	SARIFSchemaURI = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+// checkFiles maps item names to the repo-relative file a finding most likely lives in. Checks
+// that probe the runtime (Docker, ARI, DNS) have no file and get no location.
+var checkFiles = map[string]string{
+	"Check Config":            ".agent/check-config.yaml",
+	"Context Files":           ContextsDir,
+	"Config":                  "config/ai-agent.yaml",
+	"Transport Compatibility": "config/ai-agent.yaml",
+	"Advertise Hosts":         "config/ai-agent.yaml",
+	"Env":                     ".env",
+	"ARI Connectivity":        ".env",
+	"Mounts":                  "docker-compose.yml",
+	"Network Mode":            "docker-compose.yml",
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri,omitempty"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string        `json:"id"`
+	Name             string        `json:"name"`
+	ShortDescription sarifMessage  `json:"shortDescription"`
+	FullDescription  *sarifMessage `json:"fullDescription,omitempty"`
+	HelpURI          string        `json:"helpUri,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// sarifLevel maps a status to a SARIF result level; passing and skipped checks produce no result.
+func sarifLevel(s Status) (string, bool) {
+	switch s {
+	case StatusFail:
+		return "error", true
+	case StatusWarn:
+		return "warning", true
+	case StatusInfo:
+		return "note", true
+	default:
+		return "", false
+	}
+}
+
+// OutputSARIF writes the report as a SARIF 2.1.0 log (for GitHub code scanning and other
+// SARIF consumers). Every failing, warning and demoted item becomes a result whose ruleId is the
+// slugified check name.
+func (r *Report) OutputSARIF(w io.Writer) error {
+	r.finalizeCounts()
+
+	driver := sarifDriver{
+		Name:           "agent check",
+		Version:        r.Version,
+		InformationURI: DocsBaseURL + "CLI_TOOLS_GUIDE.md#agent-check",
+		Rules:          []sarifRule{},
+	}
+	results := []sarifResult{}
+	ruleIndex := map[string]int{}
+	for _, item := range r.Items {
+		level, ok := sarifLevel(item.Status)
+		if !ok {
+			continue
+		}
+		id := slugify(item.Name)
+		idx, seen := ruleIndex[id]
+		if !seen {
+			idx = len(driver.Rules)
+			ruleIndex[id] = idx
+			helpURI := item.DocURL
+			if helpURI == "" {
+				helpURI = DocURLFor(item.Name)
+			}
+			rule := sarifRule{
+				ID:               id,
+				Name:             item.Name,
+				ShortDescription: sarifMessage{Text: item.Name + " check"},
+				HelpURI:          helpURI,
+			}
+			if item.Remediation != "" {
+				rule.FullDescription = &sarifMessage{Text: item.Remediation}
+			}
+			driver.Rules = append(driver.Rules, rule)
+		}
+
+		text := item.Message
+		if text == "" {
+			text = string(item.Status)
+		}
+		res := sarifResult{
+			RuleID:    id,
+			RuleIndex: idx,
+			Level:     level,
+			Message:   sarifMessage{Text: text},
+		}
+		if uri := itemFile(item); uri != "" {
+			res.Locations = []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: uri},
+			}}}
+		}
+		results = append(results, res)
+	}
+
+	log := sarifLog{
+		Schema:  SARIFSchemaURI,
+		Version: SARIFVersion,
+		Runs:    []sarifRun{{Tool: sarifTool{Driver: driver}, Results: results}},
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(log)
+}
+
+// itemFile returns the repo-relative file for item, or "". For Context Files it points at the
+// first invalid file named in Details ("name.yaml: error; ...") when there is one.
+func itemFile(item Item) string {
+	file := checkFiles[item.Name]
+	if item.Name == "Context Files" && item.Status == StatusFail {
+		first, _, _ := strings.Cut(item.Details, ";")
+		if name, _, ok := strings.Cut(first, ": "); ok {
+			name = strings.TrimSpace(name)
+			if ext := path.Ext(name); (ext == ".yaml" || ext == ".yml") && !strings.ContainsAny(name, `/\`) {
+				return path.Join(ContextsDir, name)
+			}
+		}
+	}
+	return file
+}
+
+// slugify lowercases s and collapses every run of non-alphanumeric characters into a single
+// dash ("Container ai_engine" -> "container-ai-engine").
+func slugify(s string) string {
+	var b strings.Builder
+	dash := false
+	for _, c := range strings.ToLower(s) {
+		if (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(c)
+			dash = false
+			continue
+		}
+		dash = true
+	}
+	return b.String()
+}
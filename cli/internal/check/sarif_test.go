@@ -0,0 +1,218 @@
+package check
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+// sarifSchemaSubset is the part of the SARIF 2.1.0 schema (json.schemastore.org/sarif-2.1.0.json)
+// that OutputSARIF populates: required properties, types and enums for the log, run, tool,
+// reportingDescriptor, result and location objects.
+const sarifSchemaSubset = `{
+  "type": "object",
+  "required": ["version", "runs"],
+  "properties": {
+    "$schema": {"type": "string"},
+    "version": {"enum": ["2.1.0"]},
+    "runs": {"type": "array", "items": {
+      "type": "object",
+      "required": ["tool"],
+      "properties": {
+        "tool": {"type": "object", "required": ["driver"], "properties": {
+          "driver": {"type": "object", "required": ["name"], "properties": {
+            "name": {"type": "string"},
+            "version": {"type": "string"},
+            "informationUri": {"type": "string"},
+            "rules": {"type": "array", "items": {
+              "type": "object",
+              "required": ["id"],
+              "properties": {
+                "id": {"type": "string"},
+                "name": {"type": "string"},
+                "shortDescription": {"type": "object", "required": ["text"], "properties": {"text": {"type": "string"}}},
+                "fullDescription": {"type": "object", "required": ["text"], "properties": {"text": {"type": "string"}}},
+                "helpUri": {"type": "string"}
+              }
+            }}
+          }}
+        }},
+        "results": {"type": "array", "items": {
+          "type": "object",
+          "required": ["message"],
+          "properties": {
+            "ruleId": {"type": "string"},
+            "ruleIndex": {"type": "integer", "minimum": 0},
+            "level": {"enum": ["none", "note", "warning", "error"]},
+            "message": {"type": "object", "required": ["text"], "properties": {"text": {"type": "string"}}},
+            "locations": {"type": "array", "items": {"type": "object", "properties": {
+              "physicalLocation": {"type": "object", "required": ["artifactLocation"], "properties": {
+                "artifactLocation": {"type": "object", "properties": {"uri": {"type": "string"}}}
+              }}
+            }}}
+          }
+        }}
+      }
+    }}
+  }
+}`
+
+type testSchema struct {
+	Type       string                 `json:"type"`
+	Required   []string               `json:"required"`
+	Properties map[string]*testSchema `json:"properties"`
+	Items      *testSchema            `json:"items"`
+	Enum       []any                  `json:"enum"`
+	Minimum    *float64               `json:"minimum"`
+}
+
+// validate checks v against the keywords used in sarifSchemaSubset.
+func (s *testSchema) validate(at string, v any) error {
+	switch s.Type {
+	case "object":
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%s: want object, got %T", at, v)
+		}
+		for _, key := range s.Required {
+			if _, ok := obj[key]; !ok {
+				return fmt.Errorf("%s: missing required property %q", at, key)
+			}
+		}
+		for key, sub := range s.Properties {
+			if val, ok := obj[key]; ok {
+				if err := sub.validate(at+"."+key, val); err != nil {
+					return err
+				}
+			}
+		}
+	case "array":
+		arr, ok := v.([]any)
+		if !ok {
+			return fmt.Errorf("%s: want array, got %T", at, v)
+		}
+		for i, el := range arr {
+			if err := s.Items.validate(fmt.Sprintf("%s[%d]", at, i), el); err != nil {
+				return err
+			}
+		}
+	case "string":
+		if _, ok := v.(string); !ok {
+			return fmt.Errorf("%s: want string, got %T", at, v)
+		}
+	case "integer":
+		n, ok := v.(float64)
+		if !ok || n != float64(int64(n)) {
+			return fmt.Errorf("%s: want integer, got %v", at, v)
+		}
+		if s.Minimum != nil && n < *s.Minimum {
+			return fmt.Errorf("%s: %v is below minimum %v", at, n, *s.Minimum)
+		}
+	}
+	if len(s.Enum) > 0 {
+		for _, allowed := range s.Enum {
+			if allowed == v {
+				return nil
+			}
+		}
+		return fmt.Errorf("%s: %v not in %v", at, v, s.Enum)
+	}
+	return nil
+}
+
+func TestOutputSARIFMatchesSchema(t *testing.T) {
+	var schema testSchema
+	if err := json.Unmarshal([]byte(sarifSchemaSubset), &schema); err != nil {
+		t.Fatalf("schema: %v", err)
+	}
+	rep := &Report{
+		Version: "v1.2.3",
+		Items: []Item{
+			{Name: "Docker CLI", Status: StatusPass, Message: "ok"},
+			{Name: "Config", Status: StatusFail, Message: "invalid YAML", Remediation: "Fix YAML syntax in config/ai-agent.yaml"},
+			{Name: "Container ai_engine", Status: StatusWarn, Message: "restarting"},
+			{Name: "Internet/DNS", Status: StatusInfo, Message: "dns slow", DemotedFrom: StatusWarn},
+			{Name: "Context Files", Status: StatusFail, Message: "1 of 2 context file(s) invalid", Details: "sales.yaml: yaml: line 3: bad; x.yml: oops"},
+			{Name: "Dialplan", Status: StatusSkip, Message: "skipped"},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := rep.OutputSARIF(&buf); err != nil {
+		t.Fatalf("OutputSARIF: %v", err)
+	}
+	var doc any
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, buf.String())
+	}
+	if err := schema.validate("$", doc); err != nil {
+		t.Fatalf("schema violation: %v\n%s", err, buf.String())
+	}
+
+	var log sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatal(err)
+	}
+	run := log.Runs[0]
+	if got := len(run.Results); got != 4 {
+		t.Fatalf("results = %d, want 4 (pass and skip omitted)", got)
+	}
+	for _, res := range run.Results {
+		if rule := run.Tool.Driver.Rules[res.RuleIndex]; rule.ID != res.RuleID {
+			t.Fatalf("ruleIndex %d points at %q, want %q", res.RuleIndex, rule.ID, res.RuleID)
+		}
+	}
+
+	want := []struct {
+		ruleID, level, uri string
+	}{
+		{"config", "error", "config/ai-agent.yaml"},
+		{"container-ai-engine", "warning", ""},
+		{"internet-dns", "note", ""},
+		{"context-files", "error", "config/contexts/sales.yaml"},
+	}
+	for i, w := range want {
+		res := run.Results[i]
+		if res.RuleID != w.ruleID || res.Level != w.level {
+			t.Fatalf("result %d = %s/%s, want %s/%s", i, res.RuleID, res.Level, w.ruleID, w.level)
+		}
+		uri := ""
+		if len(res.Locations) > 0 {
+			uri = res.Locations[0].PhysicalLocation.ArtifactLocation.URI
+		}
+		if uri != w.uri {
+			t.Fatalf("result %d uri = %q, want %q", i, uri, w.uri)
+		}
+	}
+	if run.Results[0].Message.Text != "invalid YAML" {
+		t.Fatalf("message = %q", run.Results[0].Message.Text)
+	}
+}
+
+func TestOutputSARIFEmptyReport(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (&Report{Items: []Item{{Name: "Docker CLI", Status: StatusPass}}}).OutputSARIF(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var log sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatal(err)
+	}
+	if len(log.Runs) != 1 || log.Runs[0].Results == nil || len(log.Runs[0].Results) != 0 {
+		t.Fatalf("want one run with an empty results array, got %s", buf.String())
+	}
+}
+
+func TestSlugify(t *testing.T) {
+	for in, want := range map[string]string{
+		"Container ai_engine": "container-ai-engine",
+		"Internet/DNS":        "internet-dns",
+		"ARI":                 "ari",
+		"  Call History DB ":  "call-history-db",
+	} {
+		if got := slugify(in); got != want {
+			t.Errorf("slugify(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
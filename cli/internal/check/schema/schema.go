@@ -0,0 +1,128 @@
+// Package schema checks config/ai-agent.yaml against the JSON Schema of the engine's config.
+package schema
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/check"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/configmerge"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/reporoot"
+)
+
+// ItemName is the report name of the schema check.
+const ItemName = "Config Schema"
+
+// DefaultSchema is the canonical schema for ai-agent.yaml, built into the binary. It follows
+// AppConfig in src/config.py.
+//
+//go:embed ai-agent.schema.json
+var DefaultSchema []byte
+
+// defaultSchemaURL is the $id of DefaultSchema.
+const defaultSchemaURL = "https://github.com/hkjarral/asterisk-ai-voice-agent/config/ai-agent.schema.json"
+
+// Register adds the schema check to every check.Runner. schemaFile is called on each run and
+// returns the --schema-file override, or "" for DefaultSchema.
+func Register(schemaFile func() string) {
+	check.Register(ItemName, func(ctx context.Context) check.Item {
+		path := filepath.FromSlash(reporoot.ConfigFile)
+		if root, err := reporoot.Find("."); err == nil {
+			path = filepath.Join(root, path)
+		}
+		return CheckConfigSchema(schemaFile(), path)
+	})
+}
+
+// CheckConfigSchema validates the YAML file at yamlPath (with !include resolved) against the
+// JSON Schema at schemaPath, or DefaultSchema when schemaPath is "". The YAML is converted to
+// JSON first, so the schema sees the same types a JSON document would have. Each violation is
+// one line of Details. A missing or unparsable config is skipped: the Config check reports it.
+func CheckConfigSchema(schemaPath, yamlPath string) check.Item {
+	item := check.Item{Name: ItemName}
+	source, url := "the built-in schema", defaultSchemaURL
+	raw := DefaultSchema
+	if schemaPath != "" {
+		b, err := os.ReadFile(schemaPath)
+		if err != nil {
+			item.Status = check.StatusWarn
+			item.Message = "cannot read schema file"
+			item.Details = err.Error()
+			item.Remediation = "Check the --schema-file path, or drop the flag to use the built-in schema"
+			return item
+		}
+		raw, source = b, schemaPath
+		if url, err = filepath.Abs(schemaPath); err != nil {
+			url = schemaPath
+		}
+	}
+	s, err := Compile(url, raw)
+	if err != nil {
+		item.Status = check.StatusWarn
+		item.Message = "cannot use " + source
+		item.Details = err.Error()
+		item.Remediation = "Fix the schema file (JSON Schema; draft-07 unless $schema says otherwise). $ref to local files is resolved, http(s) references are not fetched"
+		return item
+	}
+
+	if _, err := os.Stat(yamlPath); err != nil {
+		item.Status = check.StatusSkip
+		item.Message = yamlPath + " not found"
+		return item
+	}
+	cfg, err := configmerge.ReadYAMLFileDecrypted(yamlPath)
+	if err != nil {
+		item.Status = check.StatusSkip
+		item.Message = "config does not parse (see Config)"
+		item.Details = err.Error()
+		return item
+	}
+	if line, err := configmerge.FindInclude(yamlPath); err == nil && line > 0 {
+		item.Status = check.StatusFail
+		item.Message = fmt.Sprintf("%s uses %s (line %d), which the engine cannot load", filepath.Base(yamlPath), configmerge.IncludeTag, line)
+		item.Remediation = "Keep split sources outside config/ and deploy the result of agent config flatten --output " + filepath.ToSlash(reporoot.ConfigFile)
+		return item
+	}
+	doc, err := toJSON(cfg)
+	if err != nil {
+		item.Status = check.StatusFail
+		item.Message = "config cannot be represented as JSON"
+		item.Details = err.Error()
+		return item
+	}
+
+	errs := s.Validate(doc)
+	if len(errs) == 0 {
+		item.Status = check.StatusPass
+		item.Message = "matches " + source
+		return item
+	}
+	lines := make([]string, 0, len(errs))
+	for _, e := range errs {
+		lines = append(lines, e.Error())
+	}
+	item.Status = check.StatusFail
+	item.Message = fmt.Sprintf("%d schema violation(s) in %s", len(errs), filepath.Base(yamlPath))
+	item.Details = strings.Join(lines, "\n")
+	item.Remediation = "Fix the listed keys (JSON Pointer paths) in " + filepath.ToSlash(reporoot.ConfigFile)
+	return item
+}
+
+// toJSON round-trips v through encoding/json, so numbers become float64 and YAML-only types
+// such as timestamps become strings.
+func toJSON(v any) (any, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var out any
+	if err := json.Unmarshal(b, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
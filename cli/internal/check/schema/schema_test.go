@@ -0,0 +1,118 @@
+package schema
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/check"
+)
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestShippedConfigMatchesDefaultSchema(t *testing.T) {
+	item := CheckConfigSchema("", filepath.Join("..", "..", "..", "..", "config", "ai-agent.yaml"))
+	if item.Status != check.StatusPass {
+		t.Fatalf("%s: %s\n%s", item.Status, item.Message, item.Details)
+	}
+}
+
+func TestCheckConfigSchemaReportsEachViolation(t *testing.T) {
+	dir := t.TempDir()
+	cfg := writeFile(t, dir, "ai-agent.yaml", `providers:
+  openai: sk-not-a-mapping
+audio_transport: sip
+config_version: 1.5
+audiosocket:
+  port: 70000
+external_media:
+  port_range: "18080-18099"
+`)
+	item := CheckConfigSchema("", cfg)
+	if item.Status != check.StatusFail {
+		t.Fatalf("status = %s: %s", item.Status, item.Details)
+	}
+	want := []string{
+		`(root): missing properties: 'default_provider'`,
+		`/audio_transport: value must be one of "audiosocket", "externalmedia", "legacy"`,
+		`/audiosocket/port: must be <= 65535 but found 70000`,
+		`/config_version: expected integer, but got number`,
+		`/external_media/port_range: does not match pattern '^[0-9]+:[0-9]+$'`,
+		`/providers/openai: expected object, but got string`,
+	}
+	if got := strings.Split(item.Details, "\n"); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("details:\n%s\nwant:\n%s", item.Details, strings.Join(want, "\n"))
+	}
+	if !strings.HasPrefix(item.Message, "6 schema violation(s)") {
+		t.Fatalf("message = %q", item.Message)
+	}
+}
+
+func TestCheckConfigSchemaFileOverride(t *testing.T) {
+	dir := t.TempDir()
+	cfg := writeFile(t, dir, "ai-agent.yaml", "default_provider: local\nproviders: {}\nvad: {}\n")
+	custom := writeFile(t, dir, "strict.json", `{"type":"object","properties":{"default_provider":{"const":"local"},"providers":{"type":"object"}},"additionalProperties":false}`)
+
+	item := CheckConfigSchema(custom, cfg)
+	if item.Status != check.StatusFail || item.Details != "(root): additionalProperties 'vad' not allowed" {
+		t.Fatalf("%+v", item)
+	}
+
+	// $ref to a sibling file, definitions and oneOf are all resolved.
+	writeFile(t, dir, "provider.json", `{"definitions":{"name":{"oneOf":[{"const":"local"},{"const":"openai"}]}}}`)
+	refs := writeFile(t, dir, "refs.json", `{"type":"object","properties":{"default_provider":{"$ref":"provider.json#/definitions/name"}}}`)
+	if item := CheckConfigSchema(refs, cfg); item.Status != check.StatusPass {
+		t.Fatalf("$ref schema: %+v", item)
+	}
+
+	invalid := writeFile(t, dir, "invalid.json", `{"type":5}`)
+	if item := CheckConfigSchema(invalid, cfg); item.Status != check.StatusWarn {
+		t.Fatalf("invalid schema: %+v", item)
+	}
+	if item := CheckConfigSchema(filepath.Join(dir, "missing.json"), cfg); item.Status != check.StatusWarn {
+		t.Fatalf("missing schema: %+v", item)
+	}
+}
+
+func TestCheckConfigSchemaSkipsMissingOrBrokenConfig(t *testing.T) {
+	dir := t.TempDir()
+	if item := CheckConfigSchema("", filepath.Join(dir, "nope.yaml")); item.Status != check.StatusSkip {
+		t.Fatalf("missing: %+v", item)
+	}
+	broken := writeFile(t, dir, "broken.yaml", "providers: [\n")
+	if item := CheckConfigSchema("", broken); item.Status != check.StatusSkip {
+		t.Fatalf("broken: %+v", item)
+	}
+}
+
+func TestCheckConfigSchemaFailsOnInclude(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "providers.yaml", "openai:\n  enabled: true\n")
+	cfg := writeFile(t, dir, "ai-agent.yaml", "default_provider: openai\nproviders: !include providers.yaml\n")
+	item := CheckConfigSchema("", cfg)
+	if item.Status != check.StatusFail || !strings.Contains(item.Message, "!include (line 2)") {
+		t.Fatalf("%s: %s", item.Status, item.Message)
+	}
+}
+
+func TestValidateTypesAndItems(t *testing.T) {
+	s, err := Compile("items.json", []byte(`{"type":"array","minItems":1,"items":{"type":["string","null"],"maxLength":3}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	errs := s.Validate([]any{"abc", nil, "abcd", 1.0})
+	if len(errs) != 2 || errs[0].Error() != "/2: length must be <= 3, but got 4" || errs[1].Error() != "/3: expected string or null, but got number" {
+		t.Fatalf("errs = %v", errs)
+	}
+	if errs := s.Validate([]any{}); len(errs) != 1 || errs[0].Path != "" {
+		t.Fatalf("empty: %v", errs)
+	}
+}
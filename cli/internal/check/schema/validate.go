@@ -0,0 +1,81 @@
+package schema
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// ValidationError is one place where a document does not match the schema.
+type ValidationError struct {
+	// Path is a JSON Pointer to the offending value ("" for the document itself).
+	Path    string
+	Message string
+}
+
+func (e ValidationError) Error() string {
+	if e.Path == "" {
+		return "(root): " + e.Message
+	}
+	return e.Path + ": " + e.Message
+}
+
+// Schema is a compiled JSON Schema. A schema without $schema is read as draft-07; $ref to
+// other local files resolves against the schema's own location, but http(s) references are
+// not fetched.
+type Schema struct {
+	s *jsonschema.Schema
+}
+
+// Compile parses the JSON Schema document b, which was loaded from url (a file path or URL).
+func Compile(url string, b []byte) (*Schema, error) {
+	c := jsonschema.NewCompiler()
+	c.Draft = jsonschema.Draft7
+	if err := c.AddResource(url, bytes.NewReader(b)); err != nil {
+		return nil, err
+	}
+	s, err := c.Compile(url)
+	if err != nil {
+		return nil, err
+	}
+	return &Schema{s: s}, nil
+}
+
+// Validate checks v, a value decoded from JSON (map[string]any, []any, float64, string, bool
+// or nil), and returns every violation, sorted by path and message.
+func (s *Schema) Validate(v any) []ValidationError {
+	err := s.s.Validate(v)
+	if err == nil {
+		return nil
+	}
+	var ve *jsonschema.ValidationError
+	if !errors.As(err, &ve) {
+		return []ValidationError{{Message: err.Error()}}
+	}
+	var errs []ValidationError
+	seen := map[ValidationError]bool{}
+	var walk func(*jsonschema.ValidationError)
+	walk = func(e *jsonschema.ValidationError) {
+		if len(e.Causes) == 0 {
+			leaf := ValidationError{Path: e.InstanceLocation, Message: e.Message}
+			if !seen[leaf] {
+				seen[leaf] = true
+				errs = append(errs, leaf)
+			}
+			return
+		}
+		for _, c := range e.Causes {
+			walk(c)
+		}
+	}
+	walk(ve)
+	sort.Slice(errs, func(i, j int) bool {
+		if errs[i].Path != errs[j].Path {
+			return errs[i].Path < errs[j].Path
+		}
+		return errs[i].Message < errs[j].Message
+	})
+	return errs
+}
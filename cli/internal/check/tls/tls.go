@@ -0,0 +1,165 @@
+// Package tls checks the certificate served on the Asterisk HTTPS/WSS (ARI) endpoint.
+package tls
+
+import (
+	"context"
+	cryptotls "crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/check"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/health"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/secrets"
+)
+
+// ItemName is the report name of the certificate check.
+const ItemName = "TLS Certificate"
+
+// DefaultWarnDays is how many days before expiry the check warns, unless ASTERISK_TLS_WARN_DAYS
+// says otherwise.
+const DefaultWarnDays = 14
+
+// DialTimeout bounds the TCP connect and TLS handshake.
+const DialTimeout = 5 * time.Second
+
+// now is replaced in tests.
+var now = time.Now
+
+// Register adds the certificate check to every check.Runner. It reads the run's .env, so it
+// follows --env-file and AGENT_ENV like the built-in checks.
+func Register() {
+	check.Register(ItemName, func(ctx context.Context) check.Item {
+		return checkFromEnv(ctx, check.EnvPath(ctx))
+	})
+}
+
+// CheckTLSCertificate connects to host:port over TLS and checks the leaf certificate: expired
+// or not valid for host fails, expiring within DefaultWarnDays warns, anything else passes.
+// Details carry the subject CN, expiry date and issuer.
+func CheckTLSCertificate(host, port string) check.Item {
+	return checkCertificate(context.Background(), host, port, DefaultWarnDays)
+}
+
+// checkFromEnv runs the check against the ARI endpoint configured in the .env at envPath. It
+// is skipped when ASTERISK_TLS=false, or when ASTERISK_TLS is unset and ARI is plain http.
+func checkFromEnv(ctx context.Context, envPath string) check.Item {
+	envMap := map[string]string{}
+	if envPath != "" {
+		envMap, _, _ = secrets.LoadEnv(envPath)
+	}
+	get := func(key string) string { return check.EnvValue(health.GetEnv(key, envMap)) }
+
+	enabled := strings.EqualFold(get("ASTERISK_ARI_SCHEME"), "https")
+	if v := get("ASTERISK_TLS"); v != "" {
+		enabled = parseBool(v)
+	}
+	if !enabled {
+		return check.Item{Name: ItemName, Status: check.StatusSkip, Message: "TLS not enabled (ASTERISK_TLS=false or ASTERISK_ARI_SCHEME=http)"}
+	}
+	host := get("ASTERISK_HOST")
+	if host == "" {
+		return check.Item{Name: ItemName, Status: check.StatusSkip, Message: "ASTERISK_HOST not set"}
+	}
+	port := get("ASTERISK_ARI_PORT")
+	if port == "" {
+		port = "8089" // Asterisk's conventional https port (http.conf tlsbindaddr)
+	}
+	warnDays := DefaultWarnDays
+	if v := get("ASTERISK_TLS_WARN_DAYS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return check.Item{
+				Name:        ItemName,
+				Status:      check.StatusWarn,
+				Message:     "invalid ASTERISK_TLS_WARN_DAYS " + strconv.Quote(v),
+				Remediation: "Set ASTERISK_TLS_WARN_DAYS in .env to a whole number of days",
+			}
+		}
+		warnDays = n
+	}
+	return checkCertificate(ctx, host, port, warnDays)
+}
+
+func checkCertificate(ctx context.Context, host, port string, warnDays int) check.Item {
+	item := check.Item{Name: ItemName}
+	addr := net.JoinHostPort(host, port)
+	dialer := &cryptotls.Dialer{
+		NetDialer: &net.Dialer{Timeout: DialTimeout},
+		// Verification is done below, so an expired or mismatched certificate can be reported
+		// with its details instead of as a handshake error.
+		Config: &cryptotls.Config{ServerName: host, InsecureSkipVerify: true},
+	}
+	ctx, cancel := context.WithTimeout(ctx, DialTimeout)
+	defer cancel()
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		item.Status = check.StatusFail
+		item.Message = "TLS handshake with " + addr + " failed"
+		item.Details = err.Error()
+		item.Remediation = "Check that Asterisk serves TLS on this port (http.conf tlsenable/tlsbindaddr) and that ASTERISK_ARI_PORT points at it"
+		return item
+	}
+	certs := conn.(*cryptotls.Conn).ConnectionState().PeerCertificates
+	conn.Close()
+	if len(certs) == 0 {
+		item.Status = check.StatusFail
+		item.Message = "no certificate presented by " + addr
+		return item
+	}
+
+	leaf := certs[0]
+	daysLeft := int(leaf.NotAfter.Sub(now()).Hours() / 24)
+	item.Details = fmt.Sprintf("addr=%s\nsubject_cn=%s\nexpires=%s\ndays_left=%d\nissuer=%s",
+		addr, leaf.Subject.CommonName, leaf.NotAfter.UTC().Format(time.RFC3339), daysLeft, issuerName(leaf))
+
+	switch {
+	case !now().Before(leaf.NotAfter):
+		item.Status = check.StatusFail
+		item.Message = "certificate expired on " + leaf.NotAfter.UTC().Format("2006-01-02")
+		item.Remediation = "Renew the certificate in Asterisk's http.conf (tlscertfile) and reload Asterisk"
+	case leaf.VerifyHostname(host) != nil:
+		item.Status = check.StatusFail
+		item.Message = "certificate is not valid for " + host
+		item.Details += "\nnames=" + strings.Join(certNames(leaf), ",")
+		item.Remediation = "Set ASTERISK_HOST to a name the certificate covers, or reissue it with " + host + " in its subject alternative names"
+	case daysLeft < warnDays:
+		item.Status = check.StatusWarn
+		item.Message = fmt.Sprintf("certificate expires in %d day(s)", daysLeft)
+		item.Remediation = "Renew the certificate in Asterisk's http.conf (tlscertfile) before it expires"
+	default:
+		item.Status = check.StatusPass
+		item.Message = fmt.Sprintf("valid for %d more days", daysLeft)
+	}
+	return item
+}
+
+func issuerName(c *x509.Certificate) string {
+	if c.Issuer.CommonName != "" {
+		return c.Issuer.CommonName
+	}
+	return c.Issuer.String()
+}
+
+// certNames lists the DNS and IP subject alternative names, or the CN when there are none.
+func certNames(c *x509.Certificate) []string {
+	names := append([]string(nil), c.DNSNames...)
+	for _, ip := range c.IPAddresses {
+		names = append(names, ip.String())
+	}
+	if len(names) == 0 && c.Subject.CommonName != "" {
+		names = append(names, c.Subject.CommonName)
+	}
+	return names
+}
+
+func parseBool(v string) bool {
+	switch strings.ToLower(v) {
+	case "1", "true", "yes", "on":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,98 @@
+package tls
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/check"
+)
+
+// tlsServer starts an HTTPS server with httptest's certificate (valid for example.com,
+// 127.0.0.1 and ::1) and returns its port and the certificate's expiry.
+func tlsServer(t *testing.T) (string, time.Time) {
+	t.Helper()
+	srv := httptest.NewTLSServer(http.NotFoundHandler())
+	t.Cleanup(srv.Close)
+	_, port, _ := net.SplitHostPort(srv.Listener.Addr().String())
+	return port, srv.Certificate().NotAfter
+}
+
+func at(t *testing.T, ts time.Time) {
+	old := now
+	now = func() time.Time { return ts }
+	t.Cleanup(func() { now = old })
+}
+
+func TestCheckTLSCertificate(t *testing.T) {
+	port, expiry := tlsServer(t)
+
+	at(t, expiry.Add(-60*24*time.Hour))
+	item := CheckTLSCertificate("127.0.0.1", port)
+	if item.Status != check.StatusPass || !strings.Contains(item.Details, "expires="+expiry.UTC().Format(time.RFC3339)) || !strings.Contains(item.Details, "issuer=") {
+		t.Fatalf("valid: %+v", item)
+	}
+
+	at(t, expiry.Add(-5*24*time.Hour))
+	if item := CheckTLSCertificate("127.0.0.1", port); item.Status != check.StatusWarn || !strings.Contains(item.Message, "expires in 5 day") {
+		t.Fatalf("expiring: %+v", item)
+	}
+
+	at(t, expiry.Add(time.Hour))
+	if item := CheckTLSCertificate("127.0.0.1", port); item.Status != check.StatusFail || !strings.Contains(item.Message, "expired") {
+		t.Fatalf("expired: %+v", item)
+	}
+
+	at(t, expiry.Add(-60*24*time.Hour))
+	if item := CheckTLSCertificate("localhost", port); item.Status != check.StatusFail || !strings.Contains(item.Message, "not valid for localhost") {
+		t.Fatalf("mismatch: %+v", item)
+	}
+}
+
+func TestCheckTLSCertificateRefused(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, port, _ := net.SplitHostPort(ln.Addr().String())
+	ln.Close()
+	if item := CheckTLSCertificate("127.0.0.1", port); item.Status != check.StatusFail || !strings.Contains(item.Message, "handshake") {
+		t.Fatalf("refused: %+v", item)
+	}
+}
+
+func TestCheckFromEnv(t *testing.T) {
+	port, expiry := tlsServer(t)
+	at(t, expiry.Add(-20*24*time.Hour))
+	for _, key := range []string{"ASTERISK_TLS", "ASTERISK_ARI_SCHEME", "ASTERISK_HOST", "ASTERISK_ARI_PORT", "ASTERISK_TLS_WARN_DAYS"} {
+		t.Setenv(key, "")
+	}
+	envFile := func(body string) string {
+		path := filepath.Join(t.TempDir(), ".env")
+		if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	base := "ASTERISK_HOST=127.0.0.1\nASTERISK_ARI_PORT=" + port + "\n"
+	for _, tc := range []struct {
+		env  string
+		want check.Status
+	}{
+		{base, check.StatusSkip}, // plain http ARI
+		{base + "ASTERISK_ARI_SCHEME=https\nASTERISK_TLS=false\n", check.StatusSkip},
+		{base + "ASTERISK_ARI_SCHEME=https\n", check.StatusPass},
+		{base + "ASTERISK_TLS=true\nASTERISK_TLS_WARN_DAYS=30\n", check.StatusWarn},
+		{base + "ASTERISK_TLS=true\nASTERISK_TLS_WARN_DAYS=soon\n", check.StatusWarn},
+	} {
+		if item := checkFromEnv(context.Background(), envFile(tc.env)); item.Status != tc.want {
+			t.Errorf("%q: %s %q, want %s", tc.env, item.Status, item.Message, tc.want)
+		}
+	}
+}
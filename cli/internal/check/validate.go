@@ -0,0 +1,158 @@
+package check
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/configmerge"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/maputil"
+)
+
+// ValidateYAMLMapping returns an error if path contains git conflict markers, is not a YAML
+// mapping, or uses !include. Every file it validates is read by the engine, which cannot
+// resolve !include.
+func ValidateYAMLMapping(path string) error {
+	if HasConflictMarkers(path) {
+		return errors.New("contains git conflict markers")
+	}
+	if _, err := configmerge.ReadYAMLFileDecrypted(path); err != nil {
+		var detail *configmerge.ParseErrorDetail
+		if errors.As(err, &detail) {
+			return detail
+		}
+		return fmt.Errorf("invalid YAML mapping: %w", err)
+	}
+	if line, err := configmerge.FindInclude(path); err == nil && line > 0 {
+		return fmt.Errorf("line %d: %s is resolved by the agent CLI only; the engine cannot load it (deploy the output of agent config flatten)", line, configmerge.IncludeTag)
+	}
+	return nil
+}
+
+// ValidateEnv performs the lightweight .env sanity check used during recovery: the file must be
+// non-empty and define the core ARI keys.
+func ValidateEnv(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	content := string(data)
+	if strings.TrimSpace(content) == "" {
+		return errors.New("empty env file")
+	}
+	// Keep this lightweight: for recovery we only require core ARI keys.
+	if !strings.Contains(content, "ASTERISK_HOST=") || !strings.Contains(content, "ASTERISK_ARI_USERNAME=") {
+		return errors.New("missing core ARI keys")
+	}
+	return nil
+}
+
+// Git writes conflict markers at the start of a line. The open, base (diff3/zdiff3) and close
+// markers may carry a label ("<<<<<<< HEAD (Current Change)" in VS Code, "||||||| merged
+// common ancestors"); the separator never does. A longer run of the same character (a
+// "========" rule) is not a marker.
+var (
+	conflictOpen  = regexp.MustCompile(`^<{7}(?:[^<]|$)`)
+	conflictBase  = regexp.MustCompile(`^\|{7}(?:[^|]|$)`)
+	conflictSep   = regexp.MustCompile(`^={7}[ \t]*$`)
+	conflictClose = regexp.MustCompile(`^>{7}(?:[^>]|$)`)
+)
+
+// HasConflictMarkers reports whether path plausibly contains unresolved git conflict markers.
+func HasConflictMarkers(path string) bool {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	hasOpen := false
+	hasSep := false
+	hasClose := false
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		switch {
+		case conflictOpen.MatchString(line):
+			hasOpen = true
+		case conflictBase.MatchString(line), conflictSep.MatchString(line):
+			hasSep = true
+		case conflictClose.MatchString(line):
+			hasClose = true
+		}
+
+		// Only signal a conflict when the marker pattern is plausibly present.
+		// A standalone "=======" line can occur legitimately (e.g., separators in YAML),
+		// but Git conflicts require marker combinations.
+		if hasOpen && (hasSep || hasClose) {
+			return true
+		}
+		if hasSep && hasClose {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateUsersJSON checks that config/users.json is valid JSON holding at least one user record,
+// and that every record has a non-empty "username" and password hash. Both the Admin UI layout
+// (an object keyed by username) and a plain JSON array of records are accepted. Errors identify
+// the first offending record by index (object keys are visited in sorted order).
+func ValidateUsersJSON(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(string(data)) == "" {
+		return errors.New("empty users file")
+	}
+
+	type record struct {
+		label string
+		value any
+	}
+	var records []record
+
+	var raw any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+	switch top := raw.(type) {
+	case []any:
+		for _, v := range top {
+			records = append(records, record{value: v})
+		}
+	case map[string]any:
+		for _, e := range maputil.SortedEntries(top) {
+			records = append(records, record{label: e.Key, value: e.Value})
+		}
+	default:
+		return errors.New("top-level value must be an array or object of user records")
+	}
+	if len(records) == 0 {
+		return errors.New("no user records")
+	}
+
+	for i, rec := range records {
+		name := fmt.Sprintf("record %d", i)
+		if rec.label != "" {
+			name = fmt.Sprintf("record %d (%s)", i, rec.label)
+		}
+		fields, ok := rec.value.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%s: not an object", name)
+		}
+		if v, ok := fields["username"].(string); !ok || strings.TrimSpace(v) == "" {
+			return fmt.Errorf("%s: missing or empty \"username\"", name)
+		}
+		if !nonEmptyString(fields["hashed_password"]) && !nonEmptyString(fields["password"]) {
+			return fmt.Errorf("%s: missing or empty \"hashed_password\"", name)
+		}
+	}
+	return nil
+}
+
+func nonEmptyString(v any) bool {
+	s, ok := v.(string)
+	return ok && strings.TrimSpace(s) != ""
+}
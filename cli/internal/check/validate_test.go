@@ -0,0 +1,119 @@
+package check
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTemp(t *testing.T, name string, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestValidateUsersJSON(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"admin ui layout", `{"admin": {"username": "admin", "hashed_password": "$2b$12$abc"}}`, ""},
+		{"array layout", `[{"username": "admin", "password": "$2b$12$abc"}]`, ""},
+		{"empty file", ``, "empty users file"},
+		{"invalid json", `{"admin": `, "invalid JSON"},
+		{"no records", `[]`, "no user records"},
+		{"scalar", `"admin"`, "top-level value"},
+		{"missing password", `[{"username": "a", "password": "x"}, {"username": "b"}]`, "record 1"},
+		{"empty username", `{"admin": {"username": " ", "hashed_password": "x"}}`, "record 0 (admin)"},
+	}
+	for _, tc := range cases {
+		path := writeTemp(t, "users.json", tc.content)
+		err := ValidateUsersJSON(path)
+		if tc.wantErr == "" {
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", tc.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+			t.Fatalf("%s: got %v, want error containing %q", tc.name, err, tc.wantErr)
+		}
+	}
+}
+
+func TestHasConflictMarkersIgnoresLoneSeparator(t *testing.T) {
+	t.Parallel()
+
+	if HasConflictMarkers(writeTemp(t, "a.yaml", "# =======\nkey: value\n=======\n")) {
+		t.Fatalf("lone separator should not be treated as a conflict")
+	}
+	if !HasConflictMarkers(writeTemp(t, "b.yaml", "<<<<<<< HEAD\na: 1\n=======\na: 2\n>>>>>>> main\n")) {
+		t.Fatalf("expected conflict markers to be detected")
+	}
+}
+
+func TestValidateConfigSetEnvironment(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	files := map[string]string{
+		".env":                                  "ASTERISK_HOST=default\n",
+		".env.production":                       "ASTERISK_HOST=prod\nASTERISK_ARI_USERNAME=u\nASTERISK_ARI_PASSWORD=p\n",
+		"config/ai-agent.yaml":                  "providers: {}\n",
+		"config/ai-agent.local.production.yaml": "a: [\n",
+	}
+	for rel, content := range files {
+		p := filepath.Join(root, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	results, err := ValidateConfigSet(root, "production")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]Status{}
+	for _, r := range results {
+		got[filepath.ToSlash(r.File)] = r.Status
+	}
+	want := map[string]Status{
+		".env.production":                       StatusPass,
+		"config/ai-agent.yaml":                  StatusPass, // no ai-agent.production.yaml: shared base
+		"config/ai-agent.local.production.yaml": StatusFail,
+		"config/users.production.json":          StatusWarn,
+	}
+	for file, st := range want {
+		if got[file] != st {
+			t.Errorf("%s: status %q, want %q (results %v)", file, got[file], st, got)
+		}
+	}
+	if _, ok := got[".env"]; ok {
+		t.Errorf("default .env validated under --env production: %v", got)
+	}
+}
+
+func TestValidateYAMLMappingRejectsInclude(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "prompt.yaml"), []byte("text: hi\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "sales.yaml")
+	if err := os.WriteFile(path, []byte("name: sales\nprompt: !include prompt.yaml\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	err := ValidateYAMLMapping(path)
+	if err == nil || !strings.Contains(err.Error(), "line 2: !include") {
+		t.Fatalf("err = %v", err)
+	}
+}
@@ -0,0 +1,66 @@
+package check
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// DefaultWaitTimeout bounds WaitForServicesHealthy after a restart when no --wait-timeout is given.
+const DefaultWaitTimeout = 30 * time.Second
+
+// UnhealthyError is returned by WaitForServicesHealthy when failures persist past the deadline.
+type UnhealthyError struct {
+	Report   *Report
+	Attempts int
+	Waited   time.Duration
+}
+
+func (e *UnhealthyError) Error() string {
+	fails := 0
+	if e.Report != nil {
+		fails = e.Report.FailCount
+	}
+	return fmt.Sprintf("services still unhealthy after %s (%d attempt(s), %d failing check(s))",
+		e.Waited.Round(time.Second), e.Attempts, fails)
+}
+
+// WaitForServicesHealthy runs diagnostics until a report has no failures or maxWait has elapsed.
+// The first run starts immediately; the pause between runs starts at poll and grows 1.5x each
+// round. Each run is bounded by runTimeout (see Runner.RunWithTimeout; callers without a
+// --check-timeout pass DefaultTimeout). The last report is always returned; on deadline the
+// error is an *UnhealthyError carrying it.
+func WaitForServicesHealthy(runner *Runner, runTimeout, maxWait, poll time.Duration) (*Report, error) {
+	run := func() (*Report, error) {
+		return runner.RunWithTimeout(context.Background(), runTimeout)
+	}
+	return waitHealthy(run, maxWait, poll, time.Sleep)
+}
+
+func waitHealthy(run func() (*Report, error), maxWait, poll time.Duration, sleep func(time.Duration)) (*Report, error) {
+	if poll <= 0 {
+		poll = time.Second
+	}
+	start := time.Now()
+	deadline := start.Add(maxWait)
+	attempts := 0
+	for {
+		rep, err := run()
+		attempts++
+		if rep != nil && rep.FailCount == 0 && err == nil {
+			return rep, nil
+		}
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			if rep == nil {
+				return nil, fmt.Errorf("diagnostics failed after %d attempt(s): %w", attempts, err)
+			}
+			return rep, &UnhealthyError{Report: rep, Attempts: attempts, Waited: time.Since(start)}
+		}
+		if poll > remaining {
+			poll = remaining
+		}
+		sleep(poll)
+		poll = poll * 3 / 2
+	}
+}
@@ -0,0 +1,36 @@
+package check
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWaitHealthyBacksOff(t *testing.T) {
+	attempts := 0
+	run := func() (*Report, error) {
+		attempts++
+		if attempts < 3 {
+			return &Report{FailCount: 1}, errors.New("agent check failed")
+		}
+		return &Report{}, nil
+	}
+	var slept []time.Duration
+	rep, err := waitHealthy(run, time.Hour, 10*time.Millisecond, func(d time.Duration) { slept = append(slept, d) })
+	if err != nil || rep == nil {
+		t.Fatalf("expected healthy report, got %v, %v", rep, err)
+	}
+	if len(slept) != 2 || slept[0] != 10*time.Millisecond || slept[1] != 15*time.Millisecond {
+		t.Fatalf("unexpected back-off: %v", slept)
+	}
+}
+
+func TestWaitHealthyDeadline(t *testing.T) {
+	last := &Report{FailCount: 2}
+	run := func() (*Report, error) { return last, errors.New("agent check failed") }
+	rep, err := waitHealthy(run, 0, time.Second, func(time.Duration) { t.Fatal("should not sleep past the deadline") })
+	var unhealthy *UnhealthyError
+	if !errors.As(err, &unhealthy) || unhealthy.Report != last || unhealthy.Attempts != 1 || rep != last {
+		t.Fatalf("expected UnhealthyError with last report, got %v (%+v)", err, rep)
+	}
+}
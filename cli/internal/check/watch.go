@@ -0,0 +1,121 @@
+package check
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// DefaultWatchInterval is the pause between runs for agent check --watch.
+const DefaultWatchInterval = 5 * time.Second
+
+// clearScreen moves the cursor home and clears the terminal.
+const clearScreen = "\033[H\033[2J"
+
+// Watcher re-runs diagnostics on an interval and reprints the report, flagging checks whose
+// status got worse (REGRESSION:) or better (RECOVERED:) since the previous run.
+type Watcher struct {
+	Runner *Runner
+	// Timeout bounds each run (DefaultTimeout when zero).
+	Timeout time.Duration
+	// SlowThreshold is copied onto each report (see Report.SlowThreshold).
+	SlowThreshold time.Duration
+	// Clear emits an ANSI clear-screen before each report; leave it off when out is not a terminal.
+	Clear bool
+	// Output is passed to OutputText for each report.
+	Output OutputOptions
+	// HTMLOutput, when set, is rewritten with each report (see Report.WriteHTMLFile).
+	HTMLOutput string
+	// OnReport, when set, is called after each run from the second on with the previous and
+	// the new report (agent check --notify-webhook).
+	OnReport func(prev, rep *Report)
+
+	// run replaces Runner.RunWithTimeout in tests.
+	run func(ctx context.Context) (*Report, error)
+}
+
+// Run loops until ctx is cancelled, which is the normal way to stop it (Ctrl-C); it then
+// returns nil.
+func (w *Watcher) Run(ctx context.Context, interval time.Duration, out io.Writer) error {
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+	run := w.run
+	if run == nil {
+		timeout := w.Timeout
+		if timeout <= 0 {
+			timeout = DefaultTimeout
+		}
+		run = func(ctx context.Context) (*Report, error) {
+			return w.Runner.RunWithTimeout(ctx, timeout)
+		}
+	}
+
+	var prev *Report
+	for {
+		rep, err := run(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if rep == nil {
+			rep = &Report{Timestamp: time.Now(), Items: []Item{{
+				Name:    "agent check",
+				Status:  StatusFail,
+				Message: "failed to generate diagnostics report",
+				Details: errString(err),
+			}}}
+		}
+		rep.SlowThreshold = w.SlowThreshold
+
+		if w.Clear {
+			fmt.Fprint(out, clearScreen)
+		}
+		fmt.Fprintf(out, "Every %s: agent check  %s  (Ctrl-C to stop)\n\n", interval, rep.Timestamp.Format("15:04:05"))
+		if prev != nil {
+			rep.CompareWith(prev)
+			writeTransitions(out, rep.ChangedItems)
+			if w.OnReport != nil {
+				w.OnReport(prev, rep)
+			}
+		}
+		rep.OutputText(out, w.Output)
+		if w.HTMLOutput != "" {
+			if err := rep.WriteHTMLFile(w.HTMLOutput); err != nil {
+				fmt.Fprintf(out, "warning: %v\n", err)
+			}
+		}
+		prev = rep
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(interval):
+		}
+	}
+}
+
+// writeTransitions prints one line per check whose severity changed between consecutive
+// watch runs (pass/info/skip count as the same severity).
+func writeTransitions(out io.Writer, changed []Item) {
+	regression := color.New(color.FgRed, color.Bold).SprintFunc()
+	recovered := color.New(color.FgGreen, color.Bold).SprintFunc()
+	n := 0
+	for _, item := range changed {
+		was, now := item.PreviousStatus.ExitCode(), item.Status.ExitCode()
+		if was == now {
+			continue
+		}
+		label := recovered("RECOVERED:")
+		if now > was {
+			label = regression("REGRESSION:")
+		}
+		fmt.Fprintf(out, "%s %s (%s -> %s) %s\n", label, item.Name, item.PreviousStatus, item.Status, item.Message)
+		n++
+	}
+	if n > 0 {
+		fmt.Fprintln(out)
+	}
+}
@@ -0,0 +1,69 @@
+package check
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWatcherPrintsTransitions(t *testing.T) {
+	runs := [][]Item{
+		{{Name: "ARI", Status: StatusPass}, {Name: "Env", Status: StatusFail}},
+		{{Name: "ARI", Status: StatusFail, Message: "connection refused"}, {Name: "Env", Status: StatusPass}},
+		{{Name: "ARI", Status: StatusFail, Message: "connection refused"}, {Name: "Env", Status: StatusPass}},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	n := 0
+	w := &Watcher{Clear: true, run: func(context.Context) (*Report, error) {
+		rep := &Report{Timestamp: time.Now(), Items: runs[n]}
+		n++
+		if n == len(runs) {
+			cancel()
+		}
+		return rep, nil
+	}}
+
+	var out bytes.Buffer
+	if err := w.Run(ctx, time.Millisecond, &out); err != nil {
+		t.Fatal(err)
+	}
+	text := out.String()
+	if got := strings.Count(text, clearScreen); got != 2 {
+		t.Fatalf("expected 2 redraws (last run cancelled), got %d", got)
+	}
+	if !strings.Contains(text, "REGRESSION: ARI (pass -> fail) connection refused") || !strings.Contains(text, "RECOVERED: Env (fail -> pass)") {
+		t.Fatalf("missing transitions:\n%s", text)
+	}
+	if strings.Count(text, "REGRESSION:") != 1 {
+		t.Fatalf("transitions should only be printed once:\n%s", text)
+	}
+}
+
+func TestWatcherOnReportGetsConsecutiveRuns(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	n := 0
+	var pairs []string
+	w := &Watcher{
+		run: func(context.Context) (*Report, error) {
+			n++
+			if n == 4 {
+				cancel()
+			}
+			return &Report{Version: fmt.Sprint(n), Timestamp: time.Now()}, nil
+		},
+		OnReport: func(prev, rep *Report) {
+			pairs = append(pairs, prev.Version+"->"+rep.Version)
+		},
+	}
+	if err := w.Run(ctx, time.Millisecond, &bytes.Buffer{}); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(pairs, " ") != "1->2 2->3" {
+		t.Fatalf("OnReport calls = %v", pairs)
+	}
+}
@@ -0,0 +1,236 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/environment"
+)
+
+// The templates are copies of the files shipped in the repository; refresh them with
+// `go generate` in this directory.
+//
+//go:generate sh -c "cp ../../../config/ai-agent.yaml templates/ai-agent.yaml && cp ../../../.env.example templates/env.example && cp ../../../config/contexts/*.yaml templates/contexts/"
+
+//go:embed templates/ai-agent.yaml templates/env.example templates/contexts/*.yaml
+var defaults embed.FS
+
+// ResetOptions configures ResetConfig.
+type ResetOptions struct {
+	// Env is the --env environment whose files are reset ("" or environment.Default for the
+	// unsuffixed files).
+	Env string
+	// PreserveCredentials carries ASTERISK_HOST, the ARI username and password and every
+	// *_API_KEY from the old .env into the new one.
+	PreserveCredentials bool
+	// Snapshot backs up the current operator config before anything is written and returns
+	// the backup directory. It is required: a reset is only undoable through this backup.
+	Snapshot func() (string, error)
+	// Out receives one line per file written or removed (default io.Discard).
+	Out io.Writer
+}
+
+// credentialKeys are preserved by ResetOptions.PreserveCredentials, besides every *_API_KEY.
+var credentialKeys = []string{"ASTERISK_HOST", "ASTERISK_ARI_USERNAME", "ASTERISK_ARI_PASSWORD"}
+
+// ResetConfig restores the factory config under root: .env from the shipped .env.example,
+// config/ai-agent.yaml from the shipped base config, and config/contexts/ holding only the
+// shipped contexts. The local override file config/ai-agent.local.yaml is removed.
+// opts.Snapshot runs first; if it fails nothing is changed.
+//
+// For a named environment the suffixed files are reset instead. The shared base config is
+// left alone unless the environment has its own (config/ai-agent.<env>.yaml), and
+// config/contexts is not touched, since the other environments read them too.
+func ResetConfig(root string, opts ResetOptions) error {
+	if opts.Snapshot == nil {
+		return errors.New("reset requires a pre-reset snapshot")
+	}
+	if opts.Out == nil {
+		opts.Out = io.Discard
+	}
+	dir, err := opts.Snapshot()
+	if err != nil {
+		return fmt.Errorf("pre-reset backup failed: %w", err)
+	}
+	fmt.Fprintf(opts.Out, "Current config saved to %s\n", dir)
+
+	envRel := environment.Path(".env", opts.Env)
+	envData, err := defaults.ReadFile("templates/env.example")
+	if err != nil {
+		return err
+	}
+	if opts.PreserveCredentials {
+		old, err := os.ReadFile(filepath.Join(root, envRel))
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to read %s: %w", envRel, err)
+		}
+		envData = preserveCredentials(envData, old)
+	}
+	if err := writeDefault(root, envRel, envData, 0o600, opts.Out); err != nil {
+		return err
+	}
+
+	sharedBase := filepath.Join("config", "ai-agent.yaml")
+	baseRel := environment.Path(sharedBase, opts.Env)
+	if _, err := os.Stat(filepath.Join(root, baseRel)); baseRel == sharedBase || err == nil {
+		baseData, err := defaults.ReadFile("templates/ai-agent.yaml")
+		if err != nil {
+			return err
+		}
+		if err := writeDefault(root, baseRel, baseData, 0o644, opts.Out); err != nil {
+			return err
+		}
+	}
+
+	localRel := environment.Path(filepath.Join("config", "ai-agent.local.yaml"), opts.Env)
+	if err := os.Remove(filepath.Join(root, localRel)); err == nil {
+		fmt.Fprintf(opts.Out, "  removed %s\n", localRel)
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	if opts.Env != "" && opts.Env != environment.Default {
+		return nil // config/contexts is shared by every environment
+	}
+	return resetContexts(root, opts.Out)
+}
+
+// resetContexts removes the context files at the top of config/contexts (not .deleted/) and
+// writes the shipped ones.
+func resetContexts(root string, out io.Writer) error {
+	dir := filepath.Join(root, "config", "contexts")
+	entries, err := os.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	shipped, err := fs.Glob(defaults, "templates/contexts/*.yaml")
+	if err != nil {
+		return err
+	}
+	keep := map[string]bool{}
+	for _, name := range shipped {
+		keep[path.Base(name)] = true
+	}
+	for _, e := range entries {
+		ext := filepath.Ext(e.Name())
+		if e.IsDir() || (ext != ".yaml" && ext != ".yml") || keep[e.Name()] {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, e.Name())); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "  removed %s\n", filepath.Join("config", "contexts", e.Name()))
+	}
+	for _, name := range shipped {
+		data, err := defaults.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		if err := writeDefault(root, filepath.Join("config", "contexts", path.Base(name)), data, 0o644, out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeDefault replaces root/rel with data through a temp file and rename.
+func writeDefault(root, rel string, data []byte, mode os.FileMode, out io.Writer) error {
+	dst := filepath.Join(root, rel)
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+	tmp := dst + ".tmp"
+	if err := os.WriteFile(tmp, data, mode); err != nil {
+		return fmt.Errorf("failed to write %s: %w", rel, err)
+	}
+	if err := os.Chmod(tmp, mode); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, dst); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	fmt.Fprintf(out, "  wrote %s\n", rel)
+	return nil
+}
+
+// preserveCredentials copies the credential lines of old into tmpl verbatim: a KEY= line in
+// the template takes the old line's place, and keys the template only mentions in comments
+// are appended at the end.
+func preserveCredentials(tmpl, old []byte) []byte {
+	kept := map[string]string{}
+	var order []string
+	sc := bufio.NewScanner(bytes.NewReader(old))
+	for sc.Scan() {
+		key, ok := envLineKey(sc.Text())
+		if !ok || !isCredentialKey(key) {
+			continue
+		}
+		if _, seen := kept[key]; !seen {
+			order = append(order, key)
+		}
+		kept[key] = strings.TrimSpace(sc.Text()) // the last assignment wins, as in docker compose
+	}
+	if len(kept) == 0 {
+		return tmpl
+	}
+
+	var buf bytes.Buffer
+	used := map[string]bool{}
+	sc = bufio.NewScanner(bytes.NewReader(tmpl))
+	for sc.Scan() {
+		line := sc.Text()
+		if key, ok := envLineKey(line); ok && kept[key] != "" && !used[key] {
+			line = kept[key]
+			used[key] = true
+		}
+		buf.WriteString(line)
+		buf.WriteByte('\n')
+	}
+	var rest []string
+	for _, key := range order {
+		if !used[key] {
+			rest = append(rest, kept[key])
+		}
+	}
+	if len(rest) > 0 {
+		buf.WriteString("\n# Preserved by agent config reset\n")
+		buf.WriteString(strings.Join(rest, "\n"))
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes()
+}
+
+// envLineKey returns the key assigned by an uncommented KEY=value line.
+func envLineKey(line string) (string, bool) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return "", false
+	}
+	line = strings.TrimPrefix(line, "export ")
+	key, _, ok := strings.Cut(line, "=")
+	key = strings.TrimSpace(key)
+	return key, ok && key != ""
+}
+
+func isCredentialKey(key string) bool {
+	if strings.HasSuffix(key, "_API_KEY") {
+		return true
+	}
+	for _, k := range credentialKeys {
+		if key == k {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,149 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTree(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for rel, data := range files {
+		p := filepath.Join(root, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestResetConfig(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		".env":                            "ASTERISK_HOST=10.0.0.5\nASTERISK_ARI_PASSWORD='s3cr#t'\nOPENAI_API_KEY=sk-old\nKROKO_API_KEY=kr-old\nGREETING=custom\n",
+		"config/ai-agent.yaml":            "default_provider: custom\n",
+		"config/ai-agent.local.yaml":      "llm: {}\n",
+		"config/contexts/sales.yaml":      "name: sales\n",
+		"config/contexts/.deleted/x.yaml": "name: x\n",
+	})
+	snapshots := 0
+	var out bytes.Buffer
+	err := ResetConfig(root, ResetOptions{
+		PreserveCredentials: true,
+		Snapshot:            func() (string, error) { snapshots++; return "/backups/1", nil },
+		Out:                 &out,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snapshots != 1 {
+		t.Fatalf("snapshot ran %d times", snapshots)
+	}
+
+	env, _ := os.ReadFile(filepath.Join(root, ".env"))
+	for _, want := range []string{"ASTERISK_HOST=10.0.0.5\n", "ASTERISK_ARI_PASSWORD='s3cr#t'\n", "OPENAI_API_KEY=sk-old\n", "KROKO_API_KEY=kr-old\n", "ASTERISK_ARI_USERNAME=asterisk\n"} {
+		if !strings.Contains(string(env), want) {
+			t.Errorf(".env missing %q", want)
+		}
+	}
+	if strings.Contains(string(env), "GREETING=custom") || strings.Contains(string(env), "ASTERISK_HOST=127.0.0.1") {
+		t.Errorf(".env kept non-credential or template values:\n%s", env)
+	}
+	if info, _ := os.Stat(filepath.Join(root, ".env")); info.Mode().Perm() != 0o600 {
+		t.Errorf(".env mode = %v", info.Mode().Perm())
+	}
+
+	base, _ := os.ReadFile(filepath.Join(root, "config", "ai-agent.yaml"))
+	shipped, _ := defaults.ReadFile("templates/ai-agent.yaml")
+	if !bytes.Equal(base, shipped) {
+		t.Error("config/ai-agent.yaml was not reset")
+	}
+	for _, rel := range []string{"config/ai-agent.local.yaml", "config/contexts/sales.yaml"} {
+		if _, err := os.Stat(filepath.Join(root, rel)); !os.IsNotExist(err) {
+			t.Errorf("%s should be removed: %v", rel, err)
+		}
+	}
+	for _, rel := range []string{"config/contexts/demo-project-expert.yaml", "config/contexts/.deleted/x.yaml"} {
+		if _, err := os.Stat(filepath.Join(root, rel)); err != nil {
+			t.Errorf("%s: %v", rel, err)
+		}
+	}
+	if !strings.HasPrefix(out.String(), "Current config saved to /backups/1\n") {
+		t.Errorf("output:\n%s", out.String())
+	}
+}
+
+func TestResetConfigEnvironment(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		".env":                               "ASTERISK_HOST=default-host\n",
+		".env.staging":                       "ASTERISK_HOST=staging-host\n",
+		"config/ai-agent.yaml":               "shared: true\n",
+		"config/ai-agent.local.yaml":         "default: true\n",
+		"config/ai-agent.local.staging.yaml": "staging: true\n",
+		"config/contexts/sales.yaml":         "name: sales\n",
+	})
+	err := ResetConfig(root, ResetOptions{Env: "staging", Snapshot: func() (string, error) { return "b", nil }})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if env, _ := os.ReadFile(filepath.Join(root, ".env")); string(env) != "ASTERISK_HOST=default-host\n" {
+		t.Errorf("default .env changed: %q", env)
+	}
+	if env, _ := os.ReadFile(filepath.Join(root, ".env.staging")); strings.Contains(string(env), "staging-host") {
+		t.Error(".env.staging kept its host without PreserveCredentials")
+	}
+	if base, _ := os.ReadFile(filepath.Join(root, "config", "ai-agent.yaml")); string(base) != "shared: true\n" {
+		t.Errorf("shared base config changed: %q", base)
+	}
+	if _, err := os.Stat(filepath.Join(root, "config", "ai-agent.local.yaml")); err != nil {
+		t.Errorf("default local override removed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "config", "ai-agent.local.staging.yaml")); !os.IsNotExist(err) {
+		t.Errorf("staging local override kept: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "config", "contexts", "sales.yaml")); err != nil {
+		t.Errorf("shared context removed by a named-environment reset: %v", err)
+	}
+}
+
+func TestResetConfigSnapshotFailure(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{".env": "ASTERISK_HOST=h\n"})
+	err := ResetConfig(root, ResetOptions{Snapshot: func() (string, error) { return "", errors.New("disk full") }})
+	if err == nil || !strings.Contains(err.Error(), "disk full") {
+		t.Fatalf("err = %v", err)
+	}
+	if env, _ := os.ReadFile(filepath.Join(root, ".env")); string(env) != "ASTERISK_HOST=h\n" {
+		t.Errorf(".env changed after a failed snapshot: %q", env)
+	}
+	if err := ResetConfig(root, ResetOptions{}); err == nil {
+		t.Error("a reset without a snapshot must be refused")
+	}
+}
+
+// The embedded defaults must match the files shipped in the repository; run go generate.
+func TestDefaultsMatchRepository(t *testing.T) {
+	for tmpl, shipped := range map[string]string{
+		"templates/ai-agent.yaml":                     "../../../config/ai-agent.yaml",
+		"templates/env.example":                       "../../../.env.example",
+		"templates/contexts/demo-project-expert.yaml": "../../../config/contexts/demo-project-expert.yaml",
+	} {
+		want, err := os.ReadFile(shipped)
+		if os.IsNotExist(err) {
+			t.Skipf("%s not found (not running in the repository)", shipped)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, _ := defaults.ReadFile(tmpl)
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s is out of date with %s; run go generate ./internal/config", tmpl, shipped)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/configmerge"
 	"gopkg.in/yaml.v3"
 )
 
@@ -47,6 +48,9 @@ func (v *Validator) Validate() (*ValidationResult, error) {
 	}
 	
 	result.Passed = append(result.Passed, "YAML syntax valid")
+	if line, err := configmerge.FindInclude(v.configPath); err == nil && line > 0 {
+		result.Errors = append(result.Errors, fmt.Sprintf("Line %d uses %s, which the engine cannot load (deploy the output of 'agent config flatten')", line, configmerge.IncludeTag))
+	}
 	
 	// Validate structure
 	v.validateStructure(result)
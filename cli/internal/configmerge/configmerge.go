@@ -7,20 +7,86 @@ import (
 	"path/filepath"
 	"reflect"
 
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/secrets"
 	"gopkg.in/yaml.v3"
 )
 
-// ReadYAMLFile reads a YAML mapping file into map[string]any.
+// ReadYAMLFile reads a YAML mapping file into map[string]any, resolving !include directives
+// (see IncludeTag). Parse errors are returned as *ParseErrorDetail ("path:line: message")
+// naming the file that contains them when yaml reports a position.
+//
+// ENC[aes256gcm,...] values (agent config encrypt-secrets) are returned as written, so the map
+// is safe to print, export or write out; validators that need plaintext use
+// ReadYAMLFileDecrypted.
 func ReadYAMLFile(path string) (map[string]any, error) {
+	doc, b, err := loadYAMLNode(path)
+	if err != nil {
+		return nil, err
+	}
+	var raw any
+	if doc.Kind != 0 {
+		if err := doc.Decode(&raw); err != nil {
+			return nil, newParseErrorDetail(path, b, err)
+		}
+	}
+	m, ok := normalizeYAMLValue(raw).(map[string]any)
+	if !ok {
+		return nil, newParseErrorDetail(path, b, errors.New("YAML top-level must be a mapping"))
+	}
+	return m, nil
+}
+
+// ReadYAMLFileDecrypted is ReadYAMLFile with ENC[aes256gcm,...] values decrypted when a key
+// file (secrets.KeyFile) is found in the file's directory or one of its parents; without one
+// they are returned as is. Only checks and validators use it: the result holds plaintext
+// secrets and must not be printed or written anywhere.
+func ReadYAMLFileDecrypted(path string) (map[string]any, error) {
+	m, err := ReadYAMLFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if keyFile := secrets.FindKeyFile(filepath.Dir(path)); keyFile != "" {
+		if err := secrets.DecryptValues(m, keyFile); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	return m, nil
+}
+
+// ReadYAMLFileRaw reads a YAML mapping file exactly as written: unlike ReadYAMLFileDecrypted it
+// neither resolves !include nor decrypts ENC[...] values. Callers that write the map back to a file use
+// it, so encrypted secrets are never put on disk in plaintext and included files are never
+// inlined. A map cannot carry the !include tag either, so a file that uses one is rejected with
+// ErrIncludeNotWritable rather than rewritten without it.
+func ReadYAMLFileRaw(path string) (map[string]any, error) {
 	b, err := os.ReadFile(path)
 	if err != nil {
 		return nil, err
 	}
-	return ParseYAML(b)
+	var doc yaml.Node
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return nil, newParseErrorDetail(path, b, err)
+	}
+	if line := findIncludeTag(&doc); line > 0 {
+		return nil, fmt.Errorf("%s:%d: %w", path, line, ErrIncludeNotWritable)
+	}
+	m, err := parseYAML(b)
+	if err != nil {
+		return nil, newParseErrorDetail(path, b, err)
+	}
+	return m, nil
 }
 
 // ParseYAML parses YAML bytes into a map[string]any. Non-mapping documents return an error.
 func ParseYAML(b []byte) (map[string]any, error) {
+	m, err := parseYAML(b)
+	if err != nil {
+		return nil, newParseErrorDetail("", b, err)
+	}
+	return m, nil
+}
+
+func parseYAML(b []byte) (map[string]any, error) {
 	var raw any
 	if err := yaml.Unmarshal(b, &raw); err != nil {
 		return nil, err
@@ -89,6 +155,14 @@ func ComputeOverrideNoDeletes(base map[string]any, desired map[string]any) map[s
 // WriteYAMLFileAtomic writes data to path atomically (temp file + rename). If the file already
 // exists, we preserve its permissions; otherwise we default to 0644.
 func WriteYAMLFileAtomic(path string, data map[string]any) error {
+	b, err := yaml.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, b)
+}
+
+func writeFileAtomic(path string, b []byte) error {
 	dir := filepath.Dir(path)
 	if dir != "." && dir != "" {
 		if err := os.MkdirAll(dir, 0o755); err != nil {
@@ -99,10 +173,6 @@ func WriteYAMLFileAtomic(path string, data map[string]any) error {
 	if st, err := os.Stat(path); err == nil {
 		mode = st.Mode()
 	}
-	b, err := yaml.Marshal(data)
-	if err != nil {
-		return err
-	}
 	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".tmp.*")
 	if err != nil {
 		return err
@@ -1,8 +1,12 @@
 package configmerge
 
 import (
+	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/secrets"
 )
 
 func TestDeepMergeNilDeletesKey(t *testing.T) {
@@ -47,3 +51,48 @@ func TestComputeOverrideNoDeletesKeepsNewUpstreamKeys(t *testing.T) {
 	}
 }
 
+func TestReadYAMLFileDecryptedWithKeyFile(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "config", "ai-agent.yaml")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("asterisk:\n  password: s3cr3t\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	keyFile := filepath.Join(root, filepath.FromSlash(secrets.KeyFile))
+	if _, err := secrets.GenerateKeyFile(keyFile); err != nil {
+		t.Fatal(err)
+	}
+	if err := secrets.EncryptYAMLSecrets(path, keyFile, []string{"password"}); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := ReadYAMLFileDecrypted(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := m["asterisk"].(map[string]any)["password"]; got != "s3cr3t" {
+		t.Fatalf("password = %v", got)
+	}
+
+	// ReadYAMLFile never decrypts: its callers print, export or write the map.
+	m, err = ReadYAMLFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := m["asterisk"].(map[string]any)["password"].(string); !secrets.IsEncryptedValue(got) {
+		t.Fatalf("ReadYAMLFile decrypted the value: %v", got)
+	}
+
+	if err := os.Remove(keyFile); err != nil {
+		t.Fatal(err)
+	}
+	m, err = ReadYAMLFileDecrypted(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := m["asterisk"].(map[string]any)["password"].(string); !secrets.IsEncryptedValue(got) {
+		t.Fatalf("without a key file the value should stay encrypted, got %v", got)
+	}
+}
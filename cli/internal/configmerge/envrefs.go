@@ -0,0 +1,123 @@
+package configmerge
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/backup"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/maputil"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrUndefinedEnvRef is wrapped by the error ResolveEnvRefs returns when a ${KEY} reference
+// without a default names a key that env does not set.
+var ErrUndefinedEnvRef = errors.New("undefined environment variable")
+
+// RedactMask replaces secret values in RedactSecrets output.
+const RedactMask = "***"
+
+// envRefPattern matches ${VAR}, ${VAR:-default} and ${VAR:=default}, the forms the engine
+// expands (_ENV_VAR_PATTERN in src/config/loaders.py).
+var envRefPattern = regexp.MustCompile(`\$\{([^}:]+)(:-|:=)?([^}]*)\}`)
+
+// ResolveEnvRefs returns a copy of data with the ${KEY} references in string values (at any
+// depth, including list items) replaced by env[KEY], the way the engine expands them when it
+// loads the file: ${KEY:-default} and ${KEY:=default} use default when KEY is unset or empty,
+// and ${KEY} is left as written when KEY is unset.
+//
+// The copy is always returned. If some ${KEY} references were left unresolved the error wraps
+// ErrUndefinedEnvRef and names them, so callers can choose to treat them as fatal.
+func ResolveEnvRefs(data map[string]interface{}, env map[string]string) (map[string]interface{}, error) {
+	undefined := map[string]bool{}
+	out, _ := resolveEnvValue(data, env, undefined).(map[string]interface{})
+	if out == nil {
+		out = map[string]interface{}{}
+	}
+	if len(undefined) == 0 {
+		return out, nil
+	}
+	return out, fmt.Errorf("%w: %s", ErrUndefinedEnvRef, strings.Join(maputil.SortedKeys(undefined), ", "))
+}
+
+func resolveEnvValue(v interface{}, env map[string]string, undefined map[string]bool) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(t))
+		for k, child := range t {
+			out[k] = resolveEnvValue(child, env, undefined)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(t))
+		for i, child := range t {
+			out[i] = resolveEnvValue(child, env, undefined)
+		}
+		return out
+	case string:
+		resolved := envRefPattern.ReplaceAllStringFunc(t, func(ref string) string {
+			m := envRefPattern.FindStringSubmatch(ref)
+			name, op, def := m[1], m[2], m[3]
+			value, ok := env[name]
+			if op != "" {
+				if value == "" {
+					return def
+				}
+				return value
+			}
+			if !ok {
+				undefined[name] = true
+				return ref
+			}
+			return value
+		})
+		if resolved != t && envRefPattern.FindString(t) == t {
+			// The engine expands before parsing, so a value that is just a reference takes
+			// the type of what it expands to (chunk_ms: ${LOCAL_WS_CHUNK_MS:=320} is 320).
+			var typed interface{}
+			if err := yaml.Unmarshal([]byte(resolved), &typed); err == nil {
+				switch typed.(type) {
+				case bool, int, float64:
+					return typed
+				}
+			}
+		}
+		return resolved
+	default:
+		return v
+	}
+}
+
+// RedactSecrets returns a copy of data with every value under a key that looks like a
+// credential (backup.IsSecretEnvKey: KEY, SECRET, PASSWORD or TOKEN in the name) replaced by
+// RedactMask, including whole mappings and lists under such a key. Empty and null values are
+// kept so an unset credential stays visible.
+func RedactSecrets(data map[string]interface{}) map[string]interface{} {
+	out, _ := redactValue(data, false).(map[string]interface{})
+	return out
+}
+
+func redactValue(v interface{}, secret bool) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(t))
+		for k, child := range t {
+			out[k] = redactValue(child, secret || backup.IsSecretEnvKey(k))
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(t))
+		for i, child := range t {
+			out[i] = redactValue(child, secret)
+		}
+		return out
+	case nil:
+		return nil
+	default:
+		if !secret || t == "" {
+			return v
+		}
+		return RedactMask
+	}
+}
@@ -0,0 +1,71 @@
+package configmerge
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestResolveEnvRefs(t *testing.T) {
+	data := map[string]interface{}{
+		"asterisk": map[string]interface{}{"host": "${ASTERISK_HOST}", "port": 8088},
+		"providers": map[string]interface{}{
+			"local": map[string]interface{}{
+				"base_url":   "${LOCAL_WS_URL:-ws://127.0.0.1:8765}",
+				"auth_token": "${LOCAL_WS_AUTH_TOKEN:-}",
+				"chunk_ms":   "${LOCAL_WS_CHUNK_MS:=320}",
+				"enabled":    "${LOCAL_ENABLED}",
+			},
+		},
+		"greetings": []interface{}{"Hello from ${COMPANY}", "${UNSET_ONE} and ${UNSET_TWO}"},
+	}
+	env := map[string]string{"ASTERISK_HOST": "pbx.internal", "LOCAL_WS_URL": "", "COMPANY": "Acme", "LOCAL_ENABLED": "true"}
+
+	got, err := ResolveEnvRefs(data, env)
+	if !errors.Is(err, ErrUndefinedEnvRef) || !strings.HasSuffix(err.Error(), ": UNSET_ONE, UNSET_TWO") {
+		t.Fatalf("err = %v", err)
+	}
+	want := map[string]interface{}{
+		"asterisk": map[string]interface{}{"host": "pbx.internal", "port": 8088},
+		"providers": map[string]interface{}{
+			"local": map[string]interface{}{
+				"base_url":   "ws://127.0.0.1:8765",
+				"auth_token": "",
+				"chunk_ms":   320,
+				"enabled":    true,
+			},
+		},
+		"greetings": []interface{}{"Hello from Acme", "${UNSET_ONE} and ${UNSET_TWO}"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %#v\nwant %#v", got, want)
+	}
+	if data["asterisk"].(map[string]interface{})["host"] != "${ASTERISK_HOST}" {
+		t.Fatal("ResolveEnvRefs modified its input")
+	}
+
+	if _, err := ResolveEnvRefs(map[string]interface{}{"a": "${ASTERISK_HOST}"}, env); err != nil {
+		t.Fatalf("err = %v, want nil", err)
+	}
+}
+
+func TestRedactSecrets(t *testing.T) {
+	got := RedactSecrets(map[string]interface{}{
+		"providers": map[string]interface{}{
+			"openai": map[string]interface{}{"api_key": "sk-live", "model": "gpt-4o"},
+			"google": map[string]interface{}{"api_key": ""},
+		},
+		"secrets": map[string]interface{}{"list": []interface{}{"a", 1}},
+	})
+	want := map[string]interface{}{
+		"providers": map[string]interface{}{
+			"openai": map[string]interface{}{"api_key": RedactMask, "model": "gpt-4o"},
+			"google": map[string]interface{}{"api_key": ""},
+		},
+		"secrets": map[string]interface{}{"list": []interface{}{RedactMask, RedactMask}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %#v\nwant %#v", got, want)
+	}
+}
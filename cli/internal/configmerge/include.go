@@ -0,0 +1,153 @@
+package configmerge
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// IncludeTag marks a scalar whose value is the path of another YAML file, relative to the
+// directory of the file that contains it:
+//
+//	providers: !include providers.yaml
+//
+// The included document replaces the tagged node, so the result reads exactly like the
+// equivalent flat file.
+const IncludeTag = "!include"
+
+// ErrIncludeCycle is returned when a file includes itself, directly or through other files.
+var ErrIncludeCycle = errors.New("circular !include")
+
+// ErrIncludeNotWritable is returned by ReadYAMLFileRaw for a file that uses IncludeTag.
+var ErrIncludeNotWritable = errors.New(IncludeTag + " cannot be kept when the file is rewritten (inline it with agent config flatten first)")
+
+// FlattenYAMLFile reads path, resolves every !include and returns the result as a single
+// YAML document. Comments and key order from all files are kept.
+func FlattenYAMLFile(path string) ([]byte, error) {
+	doc, _, err := loadYAMLNode(path)
+	if err != nil {
+		return nil, err
+	}
+	if doc.Kind == 0 {
+		return nil, nil
+	}
+	return encodeYAMLDocument(doc)
+}
+
+// loadYAMLNode parses path into a document node with includes resolved. It also returns the
+// raw bytes of path for error reporting.
+func loadYAMLNode(path string) (*yaml.Node, []byte, error) {
+	return loadIncluded(path, nil)
+}
+
+// loadIncluded loads path as part of an include chain. chain holds the absolute paths of the
+// files currently being resolved (the seen-set); meeting one again is a cycle.
+func loadIncluded(path string, chain []string) (*yaml.Node, []byte, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	for i, p := range chain {
+		if p == abs {
+			return nil, nil, fmt.Errorf("%w: %s", ErrIncludeCycle, strings.Join(append(chain[i:], abs), " -> "))
+		}
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return nil, b, newParseErrorDetail(path, b, err)
+	}
+	// Decode errors (e.g. duplicate keys) only surface on Decode; check each file on its own so
+	// they are reported against the file that has them.
+	if doc.Kind != 0 {
+		var probe any
+		if err := doc.Decode(&probe); err != nil {
+			return nil, b, newParseErrorDetail(path, b, err)
+		}
+	}
+	chain = append(chain[:len(chain):len(chain)], abs)
+	if err := resolveIncludes(&doc, path, chain); err != nil {
+		return nil, b, err
+	}
+	return &doc, b, nil
+}
+
+// FindInclude returns the line of the first IncludeTag in path (not following includes), or
+// 0 when it has none. The engine and Admin UI load ai-agent.yaml, the local override and
+// context files with a plain YAML loader that cannot resolve the tag, so those files must not
+// use it; split sources belong elsewhere and are deployed with FlattenYAMLFile.
+func FindInclude(path string) (int, error) {
+	doc, err := ReadYAMLNode(path)
+	if err != nil {
+		return 0, err
+	}
+	return findIncludeTag(doc), nil
+}
+
+// findIncludeTag returns the line of the first IncludeTag scalar under n, or 0 when there is
+// none.
+func findIncludeTag(n *yaml.Node) int {
+	if n.Kind == yaml.ScalarNode && n.Tag == IncludeTag {
+		return n.Line
+	}
+	for _, c := range n.Content {
+		if line := findIncludeTag(c); line > 0 {
+			return line
+		}
+	}
+	return 0
+}
+
+// resolveIncludes replaces, in place, every IncludeTag scalar under n with the root node of
+// the file it names. Mapping keys are never treated as includes.
+func resolveIncludes(n *yaml.Node, file string, chain []string) error {
+	switch n.Kind {
+	case yaml.ScalarNode:
+		if n.Tag != IncludeTag {
+			return nil
+		}
+		rel := strings.TrimSpace(n.Value)
+		if rel == "" || filepath.IsAbs(rel) {
+			return fmt.Errorf("%s:%d: %s path must be relative to the including file, got %q", file, n.Line, IncludeTag, n.Value)
+		}
+		target := filepath.Join(filepath.Dir(file), filepath.FromSlash(rel))
+		doc, _, err := loadIncluded(target, chain)
+		if err != nil {
+			if errors.Is(err, ErrIncludeCycle) {
+				return err
+			}
+			var detail *ParseErrorDetail
+			if errors.As(err, &detail) {
+				return err
+			}
+			return fmt.Errorf("%s:%d: %s %s: %w", file, n.Line, IncludeTag, rel, err)
+		}
+		if doc.Kind == 0 || len(doc.Content) == 0 {
+			// An empty file is a null value, as it would be inline.
+			*n = yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null", Line: n.Line, Column: n.Column}
+			return nil
+		}
+		*n = *doc.Content[0]
+		return nil
+	case yaml.MappingNode:
+		for i := 1; i < len(n.Content); i += 2 {
+			if err := resolveIncludes(n.Content[i], file, chain); err != nil {
+				return err
+			}
+		}
+	case yaml.DocumentNode, yaml.SequenceNode:
+		for _, c := range n.Content {
+			if err := resolveIncludes(c, file, chain); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package configmerge
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, body := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestReadYAMLFileIncludesMatchFlatFile(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"ai-agent.yaml": "default_provider: openai\nproviders: !include parts/providers.yaml\ncontexts:\n  - !include parts/ctx/sales.yaml\n  - name: support\n",
+		// Nested includes resolve relative to the including file (parts/), not the root.
+		"parts/providers.yaml": "# providers\nopenai:\n  model: gpt-4o\nlocal: !include local.yaml\n",
+		"parts/local.yaml":     "enabled: false\nport: 8765\n",
+		"parts/ctx/sales.yaml": "name: sales\ngreeting: Hi\n",
+		"flat/ai-agent.yaml":   "default_provider: openai\nproviders:\n  openai:\n    model: gpt-4o\n  local:\n    enabled: false\n    port: 8765\ncontexts:\n  - name: sales\n    greeting: Hi\n  - name: support\n",
+	})
+
+	split, err := ReadYAMLFile(filepath.Join(dir, "ai-agent.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	flat, err := ReadYAMLFile(filepath.Join(dir, "flat", "ai-agent.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(split, flat) {
+		t.Fatalf("included config differs from flat file:\n got  %#v\n want %#v", split, flat)
+	}
+
+	out, err := FlattenYAMLFile(filepath.Join(dir, "ai-agent.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(out), IncludeTag) || !strings.Contains(string(out), "# providers") {
+		t.Fatalf("unexpected flattened output:\n%s", out)
+	}
+	reparsed, err := ParseYAML(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(reparsed, flat) {
+		t.Fatalf("flattened output differs from flat file:\n%s", out)
+	}
+}
+
+func TestReadYAMLFileIncludeCycle(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"a.yaml": "b: !include b.yaml\n",
+		"b.yaml": "a: !include a.yaml\n",
+	})
+	_, err := ReadYAMLFile(filepath.Join(dir, "a.yaml"))
+	if !errors.Is(err, ErrIncludeCycle) {
+		t.Fatalf("err = %v, want ErrIncludeCycle", err)
+	}
+	if !strings.Contains(err.Error(), "a.yaml -> ") || !strings.Contains(err.Error(), "b.yaml") {
+		t.Fatalf("cycle error should name the chain: %v", err)
+	}
+}
+
+func TestReadYAMLFileIncludeSameFileTwiceIsNotACycle(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"root.yaml":   "a: !include common.yaml\nb: !include common.yaml\n",
+		"common.yaml": "v: 1\n",
+	})
+	m, err := ReadYAMLFile(filepath.Join(dir, "root.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m["a"].(map[string]any)["v"] != 1 || m["b"].(map[string]any)["v"] != 1 {
+		t.Fatalf("unexpected result %#v", m)
+	}
+}
+
+func TestReadYAMLFileIncludeErrors(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"missing.yaml":  "x: !include nope.yaml\n",
+		"absolute.yaml": "x: !include /etc/passwd\n",
+		"broken.yaml":   "x: !include bad.yaml\n",
+		"bad.yaml":      "a: [1, 2\n",
+	})
+
+	if _, err := ReadYAMLFile(filepath.Join(dir, "missing.yaml")); err == nil || !strings.Contains(err.Error(), "missing.yaml:1:") {
+		t.Fatalf("missing include err = %v", err)
+	}
+	if _, err := ReadYAMLFile(filepath.Join(dir, "absolute.yaml")); err == nil || !strings.Contains(err.Error(), "must be relative") {
+		t.Fatalf("absolute include err = %v", err)
+	}
+	_, err := ReadYAMLFile(filepath.Join(dir, "broken.yaml"))
+	var detail *ParseErrorDetail
+	if !errors.As(err, &detail) || filepath.Base(detail.File) != "bad.yaml" {
+		t.Fatalf("parse error should point at the included file, got %v", err)
+	}
+}
@@ -0,0 +1,258 @@
+package configmerge
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Lint issue severities.
+const (
+	LintError   = "error"
+	LintWarning = "warning"
+)
+
+// DefaultMaxLineLength is the MaxLineLength limit of DefaultLintRules.
+const DefaultMaxLineLength = 120
+
+// LintIssue is one finding of a LintRule. Line and Column are 1-based; 0 means the issue is
+// about the file as a whole.
+type LintIssue struct {
+	Rule     string `json:"rule"`
+	File     string `json:"file"`
+	Line     int    `json:"line,omitempty"`
+	Column   int    `json:"column,omitempty"`
+	Key      string `json:"key,omitempty"` // dot path of the key involved, if any
+	Message  string `json:"message"`
+	Severity string `json:"severity"`
+	// Fixable is set when the rule implements LintFixer and Fix corrects this issue.
+	Fixable bool `json:"fixable,omitempty"`
+}
+
+func (i LintIssue) String() string {
+	loc := i.File
+	if i.Line > 0 {
+		loc += fmt.Sprintf(":%d", i.Line)
+		if i.Column > 0 {
+			loc += fmt.Sprintf(":%d", i.Column)
+		}
+	}
+	return fmt.Sprintf("%s: %s: %s [%s]", loc, i.Severity, i.Message, i.Rule)
+}
+
+// LintRule checks one YAML file. node is the parsed document and path the file it came from;
+// rules about the raw text (line length, whitespace) read path themselves.
+type LintRule interface {
+	Name() string
+	Check(node *yaml.Node, path string) []LintIssue
+}
+
+// LintFixer is implemented by rules that can correct their own issues in the raw file.
+type LintFixer interface {
+	Fix(data []byte) []byte
+}
+
+// DefaultLintRules are the built-in rules applied to every file agent config lint checks.
+// RequiredTopLevelKeys is added for base configs only, since overrides are partial.
+func DefaultLintRules() []LintRule {
+	return []LintRule{NoDuplicateKeys(), MaxLineLength(DefaultMaxLineLength), NoTrailingWhitespace()}
+}
+
+// LintFile parses path and runs rules over it. Issues are sorted by line. A file that is not
+// valid YAML is an error, except for duplicate keys, which NoDuplicateKeys reports.
+func LintFile(path string, rules []LintRule) ([]LintIssue, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	var issues []LintIssue
+	for _, r := range rules {
+		for _, is := range r.Check(&doc, path) {
+			if is.Rule == "" {
+				is.Rule = r.Name()
+			}
+			if is.File == "" {
+				is.File = path
+			}
+			if is.Severity == "" {
+				is.Severity = LintWarning
+			}
+			if _, ok := r.(LintFixer); ok {
+				is.Fixable = true
+			}
+			issues = append(issues, is)
+		}
+	}
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].Line != issues[j].Line {
+			return issues[i].Line < issues[j].Line
+		}
+		return issues[i].Column < issues[j].Column
+	})
+	return issues, nil
+}
+
+// FixFile applies the LintFixer of every rule in rules to path and rewrites it atomically
+// when anything changed. It reports whether the file was changed.
+func FixFile(path string, rules []LintRule) (bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+	fixed := data
+	for _, r := range rules {
+		if f, ok := r.(LintFixer); ok {
+			fixed = f.Fix(fixed)
+		}
+	}
+	if bytes.Equal(fixed, data) {
+		return false, nil
+	}
+	return true, writeFileAtomic(path, fixed)
+}
+
+type noDuplicateKeys struct{}
+
+// NoDuplicateKeys reports a mapping key defined twice. The engine's YAML loader keeps the last
+// value silently, so the earlier one is usually a forgotten edit.
+func NoDuplicateKeys() LintRule { return noDuplicateKeys{} }
+
+func (noDuplicateKeys) Name() string { return "no-duplicate-keys" }
+
+func (noDuplicateKeys) Check(node *yaml.Node, path string) []LintIssue {
+	var issues []LintIssue
+	walkYAML(node, "", func(n *yaml.Node, key string) {
+		if n.Kind != yaml.MappingNode {
+			return
+		}
+		first := map[string]int{}
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			k := n.Content[i]
+			if line, ok := first[k.Value]; ok {
+				issues = append(issues, LintIssue{
+					Line: k.Line, Column: k.Column, Key: joinKey(key, k.Value), Severity: LintError,
+					Message: fmt.Sprintf("duplicate key %q (first defined on line %d; the last one wins)", k.Value, line),
+				})
+				continue
+			}
+			first[k.Value] = k.Line
+		}
+	})
+	return issues
+}
+
+type maxLineLength struct{ max int }
+
+// MaxLineLength reports lines longer than max characters.
+func MaxLineLength(max int) LintRule { return maxLineLength{max: max} }
+
+func (maxLineLength) Name() string { return "max-line-length" }
+
+func (r maxLineLength) Check(_ *yaml.Node, path string) []LintIssue {
+	var issues []LintIssue
+	forEachLine(path, func(n int, line string) {
+		if l := len([]rune(line)); l > r.max {
+			issues = append(issues, LintIssue{Line: n, Column: r.max + 1, Message: fmt.Sprintf("line is %d characters long (max %d)", l, r.max)})
+		}
+	})
+	return issues
+}
+
+type noTrailingWhitespace struct{}
+
+// NoTrailingWhitespace reports spaces and tabs at the end of a line. It is fixable.
+func NoTrailingWhitespace() LintRule { return noTrailingWhitespace{} }
+
+func (noTrailingWhitespace) Name() string { return "no-trailing-whitespace" }
+
+func (noTrailingWhitespace) Check(_ *yaml.Node, path string) []LintIssue {
+	var issues []LintIssue
+	forEachLine(path, func(n int, line string) {
+		if trimmed := strings.TrimRight(line, " \t"); trimmed != line {
+			issues = append(issues, LintIssue{Line: n, Column: len([]rune(trimmed)) + 1, Message: "trailing whitespace"})
+		}
+	})
+	return issues
+}
+
+var trailingWhitespace = regexp.MustCompile(`(?m)[ \t]+(\r?)$`)
+
+func (noTrailingWhitespace) Fix(data []byte) []byte {
+	return trailingWhitespace.ReplaceAll(data, []byte("$1"))
+}
+
+type requiredTopLevelKeys struct{ keys []string }
+
+// RequiredTopLevelKeys reports each of keys that the document does not define at the top
+// level.
+func RequiredTopLevelKeys(keys []string) LintRule { return requiredTopLevelKeys{keys: keys} }
+
+func (requiredTopLevelKeys) Name() string { return "required-top-level-keys" }
+
+func (r requiredTopLevelKeys) Check(node *yaml.Node, _ string) []LintIssue {
+	root := node
+	if root.Kind == yaml.DocumentNode && len(root.Content) > 0 {
+		root = root.Content[0]
+	}
+	present := map[string]bool{}
+	if root.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(root.Content); i += 2 {
+			present[root.Content[i].Value] = true
+		}
+	}
+	var issues []LintIssue
+	for _, k := range r.keys {
+		if !present[k] {
+			issues = append(issues, LintIssue{Key: k, Severity: LintError, Message: fmt.Sprintf("required top-level key %q is missing", k)})
+		}
+	}
+	return issues
+}
+
+// walkYAML calls fn for n and every node below it with the dot path of the mapping key (or
+// list index) it sits under.
+func walkYAML(n *yaml.Node, key string, fn func(n *yaml.Node, key string)) {
+	fn(n, key)
+	switch n.Kind {
+	case yaml.DocumentNode:
+		for _, c := range n.Content {
+			walkYAML(c, key, fn)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			walkYAML(n.Content[i+1], joinKey(key, n.Content[i].Value), fn)
+		}
+	case yaml.SequenceNode:
+		for i, c := range n.Content {
+			walkYAML(c, joinKey(key, fmt.Sprint(i)), fn)
+		}
+	}
+}
+
+func joinKey(parent, key string) string {
+	if parent == "" {
+		return key
+	}
+	return parent + "." + key
+}
+
+// forEachLine calls fn with each line of path (1-based, without the line break). Read errors
+// are left to LintFile, which has read the file already.
+func forEachLine(path string, fn func(n int, line string)) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	for i, line := range strings.Split(strings.TrimSuffix(string(data), "\n"), "\n") {
+		fn(i+1, strings.TrimSuffix(line, "\r"))
+	}
+}
@@ -0,0 +1,161 @@
+package configmerge
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LintRulesDir holds the operator's declarative lint rules (*.yaml), relative to the repo root.
+const LintRulesDir = ".agent/lint-rules"
+
+// PatternRule is a declarative lint rule from a rules file:
+//
+//	rules:
+//	  - name: no-debug-logging
+//	    key: logging.level
+//	    forbid: '^debug$'
+//	    message: debug logging left on
+//	  - name: https-webhooks
+//	    key: tools.*.webhook_url
+//	    require: '^https://'
+//	    severity: error
+//
+// Key is a dot path; each segment is a path.Match pattern, so * matches any one key (list
+// items are numbered from 0). The rule reports every scalar at a matching key whose value
+// matches Forbid, or does not match Require. Severity is warning (the default) or error.
+type PatternRule struct {
+	RuleName string `yaml:"name"`
+	Key      string `yaml:"key"`
+	Forbid   string `yaml:"forbid"`
+	Require  string `yaml:"require"`
+	Message  string `yaml:"message"`
+	Severity string `yaml:"severity"`
+
+	forbid, require *regexp.Regexp
+}
+
+type lintRulesFile struct {
+	Rules []PatternRule `yaml:"rules"`
+}
+
+// LoadLintRules reads every *.yaml file in dir, in name order. A missing dir yields no rules.
+func LoadLintRules(dir string) ([]LintRule, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+	var rules []LintRule
+	for _, p := range paths {
+		r, err := LoadLintRulesFile(p)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, r...)
+	}
+	return rules, nil
+}
+
+// LoadLintRulesFile reads one rules file. An invalid rule (missing name or key, neither forbid
+// nor require, a bad regular expression or severity) is an error and no rule of the file is
+// returned.
+func LoadLintRulesFile(file string) ([]LintRule, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var f lintRulesFile
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	var (
+		rules []LintRule
+		errs  []error
+	)
+	for i, r := range f.Rules {
+		r := r
+		label := fmt.Sprintf("rules[%d]", i)
+		if r.RuleName != "" {
+			label += " (" + r.RuleName + ")"
+		}
+		r.Severity = strings.ToLower(strings.TrimSpace(r.Severity))
+		if r.Severity == "" {
+			r.Severity = LintWarning
+		}
+		switch {
+		case strings.TrimSpace(r.RuleName) == "":
+			errs = append(errs, fmt.Errorf("%s: name is required", label))
+		case strings.TrimSpace(r.Key) == "":
+			errs = append(errs, fmt.Errorf("%s: key is required", label))
+		case r.Forbid == "" && r.Require == "":
+			errs = append(errs, fmt.Errorf("%s: forbid or require is required", label))
+		case r.Severity != LintWarning && r.Severity != LintError:
+			errs = append(errs, fmt.Errorf("%s: severity must be warning or error, not %q", label, r.Severity))
+		}
+		if _, err := path.Match(r.Key, ""); err != nil {
+			errs = append(errs, fmt.Errorf("%s: invalid key pattern: %w", label, err))
+		}
+		if r.Forbid != "" {
+			if r.forbid, err = regexp.Compile(r.Forbid); err != nil {
+				errs = append(errs, fmt.Errorf("%s: invalid forbid: %w", label, err))
+			}
+		}
+		if r.Require != "" {
+			if r.require, err = regexp.Compile(r.Require); err != nil {
+				errs = append(errs, fmt.Errorf("%s: invalid require: %w", label, err))
+			}
+		}
+		rules = append(rules, &r)
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("%s: %w", file, errors.Join(errs...))
+	}
+	return rules, nil
+}
+
+func (r *PatternRule) Name() string { return r.RuleName }
+
+func (r *PatternRule) Check(node *yaml.Node, _ string) []LintIssue {
+	var issues []LintIssue
+	walkYAML(node, "", func(n *yaml.Node, key string) {
+		if n.Kind != yaml.ScalarNode || key == "" || !matchKey(r.Key, key) {
+			return
+		}
+		var problem string
+		switch {
+		case r.forbid != nil && r.forbid.MatchString(n.Value):
+			problem = fmt.Sprintf("%s: %q matches %s", key, n.Value, r.Forbid)
+		case r.require != nil && !r.require.MatchString(n.Value):
+			problem = fmt.Sprintf("%s: %q does not match %s", key, n.Value, r.Require)
+		default:
+			return
+		}
+		if r.Message != "" {
+			problem = key + ": " + r.Message
+		}
+		issues = append(issues, LintIssue{Line: n.Line, Column: n.Column, Key: key, Message: problem, Severity: r.Severity})
+	})
+	return issues
+}
+
+// matchKey matches a dot path against a pattern of path.Match segments.
+func matchKey(pattern, key string) bool {
+	ps, ks := strings.Split(pattern, "."), strings.Split(key, ".")
+	if len(ps) != len(ks) {
+		return false
+	}
+	for i := range ps {
+		if ok, _ := path.Match(ps[i], ks[i]); !ok {
+			return false
+		}
+	}
+	return true
+}
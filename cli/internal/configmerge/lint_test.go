@@ -0,0 +1,99 @@
+package configmerge
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLintFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "ai-agent.local.yaml")
+	content := "providers:\n  openai:\n    model: gpt-4o  \n    model: gpt-4o-mini\n" +
+		"greeting: \"" + strings.Repeat("x", 130) + "\"\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	rules := append(DefaultLintRules(), RequiredTopLevelKeys([]string{"default_provider", "providers"}))
+	issues, err := LintFile(path, rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, is := range issues {
+		got = append(got, fmt.Sprintf("%s@%d", is.Rule, is.Line))
+	}
+	want := []string{"required-top-level-keys@0", "no-trailing-whitespace@3", "no-duplicate-keys@4", "max-line-length@5"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Fatalf("issues = %v, want %v", got, want)
+	}
+	if issues[2].Key != "providers.openai.model" || issues[2].Severity != LintError {
+		t.Fatalf("duplicate issue = %+v", issues[2])
+	}
+	if !issues[1].Fixable || issues[2].Fixable {
+		t.Fatalf("fixable = %v, %v", issues[1].Fixable, issues[2].Fixable)
+	}
+
+	changed, err := FixFile(path, rules)
+	if err != nil || !changed {
+		t.Fatalf("FixFile = %v, %v", changed, err)
+	}
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), "gpt-4o  \n") {
+		t.Fatalf("trailing whitespace not fixed:\n%s", data)
+	}
+	if changed, _ := FixFile(path, rules); changed {
+		t.Fatal("second FixFile changed the file")
+	}
+}
+
+func TestLoadLintRules(t *testing.T) {
+	dir := t.TempDir()
+	rules := `rules:
+  - name: https-webhooks
+    key: tools.*.url
+    require: '^https://'
+    severity: error
+  - name: no-debug
+    key: logging.level
+    forbid: '^debug$'
+    message: debug logging left on
+`
+	if err := os.WriteFile(filepath.Join(dir, "site.yaml"), []byte(rules), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadLintRules(dir)
+	if err != nil || len(loaded) != 2 {
+		t.Fatalf("LoadLintRules = %v, %v", loaded, err)
+	}
+
+	cfg := filepath.Join(dir, "cfg.yaml")
+	doc := "logging:\n  level: debug\ntools:\n  - url: https://ok\n  - url: http://plain\n"
+	if err := os.WriteFile(cfg, []byte(doc), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	issues, err := LintFile(cfg, loaded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 2 {
+		t.Fatalf("issues = %+v", issues)
+	}
+	if issues[0].Rule != "no-debug" || issues[0].Message != "logging.level: debug logging left on" || issues[0].Severity != LintWarning {
+		t.Fatalf("issues[0] = %+v", issues[0])
+	}
+	if issues[1].Rule != "https-webhooks" || issues[1].Key != "tools.1.url" || issues[1].Line != 5 || issues[1].Severity != LintError {
+		t.Fatalf("issues[1] = %+v", issues[1])
+	}
+
+	bad := filepath.Join(dir, "bad.yaml")
+	if err := os.WriteFile(bad, []byte("rules:\n  - name: x\n    key: a\n  - name: y\n    key: b\n    forbid: '('\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_, err = LoadLintRulesFile(bad)
+	if err == nil || !strings.Contains(err.Error(), "forbid or require is required") || !strings.Contains(err.Error(), "invalid forbid") {
+		t.Fatalf("err = %v", err)
+	}
+}
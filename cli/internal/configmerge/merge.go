@@ -0,0 +1,163 @@
+package configmerge
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// MergeStrategy selects how MergeYAML applies an overlay to a base config.
+type MergeStrategy string
+
+const (
+	// StrategyOverlay merges mappings recursively and lets overlay lists and scalars replace
+	// the base value. It is what the engine does, and the default.
+	StrategyOverlay MergeStrategy = "overlay"
+	// StrategyDeepMerge is StrategyOverlay, except that lists are concatenated (base first)
+	// with items already present dropped.
+	StrategyDeepMerge MergeStrategy = "deep-merge"
+	// StrategyLastWins replaces each top-level key of base with the overlay's value as a whole.
+	StrategyLastWins MergeStrategy = "last-wins"
+)
+
+// MergeStrategies lists the strategies in the order they are documented.
+var MergeStrategies = []MergeStrategy{StrategyOverlay, StrategyDeepMerge, StrategyLastWins}
+
+// ParseMergeStrategy validates a strategy name; "" is StrategyOverlay.
+func ParseMergeStrategy(name string) (MergeStrategy, error) {
+	if name == "" {
+		return StrategyOverlay, nil
+	}
+	s := MergeStrategy(strings.ToLower(name))
+	if !slices.Contains(MergeStrategies, s) {
+		return "", fmt.Errorf("unknown merge strategy %q (want overlay, deep-merge or last-wins)", name)
+	}
+	return s, nil
+}
+
+// MergeYAML returns the config that results from applying overlay (ai-agent.local.yaml) on
+// top of base (ai-agent.yaml) with strategy ("" is StrategyOverlay). Under every strategy an
+// explicit null in overlay deletes the key.
+//
+// StrategyOverlay is the effective config the engine sees: mappings merge recursively, scalars
+// and lists in overlay replace the base value. This mirrors deep_merge_dicts in
+// src/config/loaders.py; the other strategies are previews only.
+func MergeYAML(base, overlay map[string]interface{}, strategy MergeStrategy) (map[string]interface{}, error) {
+	if base == nil {
+		return nil, errors.New("base config is empty")
+	}
+	if overlay == nil {
+		overlay = map[string]any{}
+	}
+	switch strategy {
+	case "", StrategyOverlay:
+		return DeepMerge(base, overlay), nil
+	case StrategyDeepMerge:
+		return deepMergeLists(base, overlay), nil
+	case StrategyLastWins:
+		out := make(map[string]any, len(base))
+		for k, v := range base {
+			out[k] = v
+		}
+		for k, v := range overlay {
+			if v == nil {
+				delete(out, k)
+				continue
+			}
+			out[k] = v
+		}
+		return out, nil
+	default:
+		return nil, fmt.Errorf("unknown merge strategy %q", strategy)
+	}
+}
+
+// deepMergeLists is DeepMerge with list values concatenated instead of replaced; overlay items
+// equal to one already in the list are skipped.
+func deepMergeLists(base, overlay map[string]any) map[string]any {
+	out := map[string]any{}
+	for k, v := range base {
+		out[k] = v
+	}
+	for k, ov := range overlay {
+		if ov == nil {
+			delete(out, k)
+			continue
+		}
+		switch bv := out[k].(type) {
+		case map[string]any:
+			if om, ok := ov.(map[string]any); ok {
+				out[k] = deepMergeLists(bv, om)
+				continue
+			}
+		case []any:
+			if ol, ok := ov.([]any); ok {
+				merged := append([]any{}, bv...)
+				for _, item := range ol {
+					if !slices.ContainsFunc(merged, func(x any) bool { return reflect.DeepEqual(x, item) }) {
+						merged = append(merged, item)
+					}
+				}
+				out[k] = merged
+				continue
+			}
+		}
+		out[k] = ov
+	}
+	return out
+}
+
+// Override kinds reported by Overrides.
+const (
+	OverrideChanged = "changed"
+	OverrideAdded   = "added"
+	OverrideRemoved = "removed"
+)
+
+// Override is one leaf key where the overlay changes the base config.
+type Override struct {
+	Path  string // dotted key path, e.g. providers.openai_realtime.model
+	Kind  string // OverrideChanged, OverrideAdded or OverrideRemoved
+	Base  any
+	Local any
+}
+
+// Overrides lists the leaf keys where overlay changes base, sorted by path. Keys the overlay
+// sets to the same value as base are not reported.
+func Overrides(base, overlay map[string]any) []Override {
+	var out []Override
+	collectOverrides("", base, overlay, &out)
+	sort.Slice(out, func(i, j int) bool { return out[i].Path < out[j].Path })
+	return out
+}
+
+func collectOverrides(prefix string, base, overlay map[string]any, out *[]Override) {
+	for k, ov := range overlay {
+		path := k
+		if prefix != "" {
+			path = prefix + "." + k
+		}
+		bv, inBase := base[k]
+		switch {
+		case ov == nil:
+			if inBase {
+				*out = append(*out, Override{Path: path, Kind: OverrideRemoved, Base: bv})
+			}
+		case !inBase:
+			*out = append(*out, Override{Path: path, Kind: OverrideAdded, Local: ov})
+		default:
+			bm, ok1 := bv.(map[string]any)
+			om, ok2 := ov.(map[string]any)
+			if ok1 && ok2 {
+				collectOverrides(path, bm, om, out)
+				continue
+			}
+			if !reflect.DeepEqual(bv, ov) {
+				*out = append(*out, Override{Path: path, Kind: OverrideChanged, Base: bv, Local: ov})
+			}
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package configmerge
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMergeYAMLReplacesListsAndDeletesNulls(t *testing.T) {
+	base := map[string]any{
+		"providers": map[string]any{"openai": map[string]any{"model": "a", "voices": []any{"x", "y"}}},
+		"keep":      1,
+		"drop":      2,
+	}
+	overlay := map[string]any{
+		"providers": map[string]any{"openai": map[string]any{"voices": []any{"z"}}},
+		"drop":      nil,
+	}
+	got, err := MergeYAML(base, overlay, StrategyOverlay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{
+		"providers": map[string]any{"openai": map[string]any{"model": "a", "voices": []any{"z"}}},
+		"keep":      1,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %#v want %#v", got, want)
+	}
+	if _, err := MergeYAML(nil, overlay, ""); err == nil {
+		t.Fatalf("expected error for nil base")
+	}
+}
+
+func TestMergeYAMLStrategies(t *testing.T) {
+	base := map[string]any{
+		"providers": map[string]any{"openai": map[string]any{"model": "a", "voices": []any{"x", "y"}}},
+		"keep":      1,
+		"drop":      2,
+	}
+	overlay := map[string]any{
+		"providers": map[string]any{"openai": map[string]any{"voices": []any{"y", "z"}}},
+		"drop":      nil,
+	}
+	for _, tc := range []struct {
+		strategy MergeStrategy
+		want     map[string]any
+	}{
+		{StrategyOverlay, map[string]any{"providers": map[string]any{"openai": map[string]any{"model": "a", "voices": []any{"y", "z"}}}, "keep": 1}},
+		{StrategyDeepMerge, map[string]any{"providers": map[string]any{"openai": map[string]any{"model": "a", "voices": []any{"x", "y", "z"}}}, "keep": 1}},
+		{StrategyLastWins, map[string]any{"providers": map[string]any{"openai": map[string]any{"voices": []any{"y", "z"}}}, "keep": 1}},
+	} {
+		got, err := MergeYAML(base, overlay, tc.strategy)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: got %#v want %#v", tc.strategy, got, tc.want)
+		}
+	}
+	if base["providers"].(map[string]any)["openai"].(map[string]any)["voices"].([]any)[1] != "y" || len(base) != 3 {
+		t.Fatalf("base was modified: %#v", base)
+	}
+
+	if s, err := ParseMergeStrategy("Deep-Merge"); err != nil || s != StrategyDeepMerge {
+		t.Fatalf("ParseMergeStrategy = %q, %v", s, err)
+	}
+	if _, err := ParseMergeStrategy("union"); err == nil {
+		t.Fatal("expected an error for an unknown strategy")
+	}
+}
+
+func TestOverrides(t *testing.T) {
+	base := map[string]any{"a": map[string]any{"b": 1, "c": 2}, "d": "x"}
+	overlay := map[string]any{"a": map[string]any{"b": 1, "c": 3, "e": true}, "d": nil}
+	got := Overrides(base, overlay)
+	want := []Override{
+		{Path: "a.c", Kind: OverrideChanged, Base: 2, Local: 3},
+		{Path: "a.e", Kind: OverrideAdded, Local: true},
+		{Path: "d", Kind: OverrideRemoved, Base: "x"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %#v want %#v", got, want)
+	}
+}
@@ -0,0 +1,70 @@
+package configmerge
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ParseErrorDetail is a YAML parse error with its position, formatted as
+// "file:line:col: message" so operators can jump straight to the problem.
+type ParseErrorDetail struct {
+	File    string
+	Line    int
+	Column  int // 0 when the parser does not report a column
+	Message string
+	Excerpt string // the offending source line, if available
+}
+
+func (e *ParseErrorDetail) Error() string {
+	var b strings.Builder
+	if e.File != "" {
+		b.WriteString(e.File)
+		b.WriteString(":")
+	}
+	if e.Line > 0 {
+		b.WriteString(strconv.Itoa(e.Line))
+		b.WriteString(":")
+		if e.Column > 0 {
+			b.WriteString(strconv.Itoa(e.Column))
+			b.WriteString(":")
+		}
+	}
+	if b.Len() > 0 {
+		b.WriteString(" ")
+	}
+	b.WriteString(e.Message)
+	return b.String()
+}
+
+// yaml.v3 reports positions as "yaml: line N: msg" (syntax errors) or "line N: msg" (type errors),
+// optionally with a column ("line N, column M").
+var yamlPositionRE = regexp.MustCompile(`^(?:yaml: )?line (\d+)(?:, column (\d+))?: (.*)$`)
+
+// newParseErrorDetail converts a yaml.v3 error into a *ParseErrorDetail. Errors without a
+// recognisable position are returned unchanged.
+func newParseErrorDetail(file string, src []byte, err error) error {
+	msg := err.Error()
+	var typeErr *yaml.TypeError
+	if errors.As(err, &typeErr) && len(typeErr.Errors) > 0 {
+		msg = typeErr.Errors[0]
+	}
+	m := yamlPositionRE.FindStringSubmatch(msg)
+	if m == nil {
+		if file != "" {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+		return err
+	}
+	line, _ := strconv.Atoi(m[1])
+	col, _ := strconv.Atoi(m[2])
+	detail := &ParseErrorDetail{File: file, Line: line, Column: col, Message: m[3]}
+	if lines := strings.Split(string(src), "\n"); line >= 1 && line <= len(lines) {
+		detail.Excerpt = strings.TrimRight(lines[line-1], "\r")
+	}
+	return detail
+}
@@ -0,0 +1,42 @@
+package configmerge
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadYAMLFileReportsPosition(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ai-agent.local.yaml")
+	src := "a: 1\nb:\n  c: [1, 2\nd: 3\n"
+	if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_, err := ReadYAMLFile(path)
+	var detail *ParseErrorDetail
+	if !errors.As(err, &detail) {
+		t.Fatalf("expected *ParseErrorDetail, got %T: %v", err, err)
+	}
+	if detail.File != path || detail.Line == 0 || detail.Message == "" {
+		t.Fatalf("unexpected detail: %+v", detail)
+	}
+	if !strings.HasPrefix(err.Error(), path+":") || strings.Contains(err.Error(), "yaml: line") {
+		t.Fatalf("unexpected message: %q", err.Error())
+	}
+	if detail.Excerpt == "" {
+		t.Fatalf("expected excerpt for line %d", detail.Line)
+	}
+}
+
+func TestParseErrorDetailFormat(t *testing.T) {
+	d := &ParseErrorDetail{File: "config/ai-agent.local.yaml", Line: 42, Column: 5, Message: "did not find expected key"}
+	if got, want := d.Error(), "config/ai-agent.local.yaml:42:5: did not find expected key"; got != want {
+		t.Fatalf("got %q want %q", got, want)
+	}
+	d.Column = 0
+	if got, want := d.Error(), "config/ai-agent.local.yaml:42: did not find expected key"; got != want {
+		t.Fatalf("got %q want %q", got, want)
+	}
+}
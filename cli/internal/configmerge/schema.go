@@ -0,0 +1,126 @@
+package configmerge
+
+import "fmt"
+
+// SchemaVersionKey is the top-level key the engine uses as its config schema marker
+// (AppConfig.config_version in src/config.py).
+const SchemaVersionKey = "config_version"
+
+// CurrentSchemaVersion is the schema version this CLI migrates configs up to.
+// A file without SchemaVersionKey is treated as current, matching the engine's default.
+const CurrentSchemaVersion = 6
+
+// SchemaHeader is the typed view of the schema marker in ai-agent.yaml.
+type SchemaHeader struct {
+	SchemaVersion int `yaml:"config_version"`
+}
+
+// Migrator upgrades a config mapping in place to the version it is registered under.
+type Migrator func(map[string]interface{}) error
+
+// Migrations maps a target schema version to the migration that produces it from
+// the previous version. Versions without an entry only bump SchemaVersionKey.
+var Migrations = map[int]Migrator{
+	6: migrateInCallToolsKey,
+}
+
+var migrationDescriptions = map[int]string{
+	6: "rename top-level in_call_http_tools to in_call_tools",
+}
+
+// SchemaVersionOf returns the schema version recorded in m (CurrentSchemaVersion when absent).
+func SchemaVersionOf(m map[string]any) (int, error) {
+	raw, ok := m[SchemaVersionKey]
+	if !ok || raw == nil {
+		return CurrentSchemaVersion, nil
+	}
+	switch v := raw.(type) {
+	case int:
+		return v, nil
+	case int64:
+		return int(v), nil
+	case float64:
+		if v == float64(int(v)) {
+			return int(v), nil
+		}
+	}
+	return 0, fmt.Errorf("%s must be an integer (got %v)", SchemaVersionKey, raw)
+}
+
+// PendingMigrations returns the target versions that must be applied, in order,
+// to bring a config at version from up to CurrentSchemaVersion.
+func PendingMigrations(from int) []int {
+	var out []int
+	for v := from + 1; v <= CurrentSchemaVersion; v++ {
+		out = append(out, v)
+	}
+	return out
+}
+
+// DescribeMigration returns a human-readable summary of the migration to version v.
+func DescribeMigration(v int) string {
+	if d, ok := migrationDescriptions[v]; ok {
+		return d
+	}
+	return "version bump only"
+}
+
+// Migrate upgrades m in place to CurrentSchemaVersion and returns the versions applied.
+// Configs newer than this CLI understands are rejected rather than downgraded.
+func Migrate(m map[string]any) ([]int, error) {
+	from, err := SchemaVersionOf(m)
+	if err != nil {
+		return nil, err
+	}
+	if from > CurrentSchemaVersion {
+		return nil, fmt.Errorf("%s %d is newer than this CLI supports (%d); update the agent CLI", SchemaVersionKey, from, CurrentSchemaVersion)
+	}
+	pending := PendingMigrations(from)
+	for _, v := range pending {
+		if fn, ok := Migrations[v]; ok {
+			if err := fn(m); err != nil {
+				return nil, fmt.Errorf("migration to %s %d failed: %w", SchemaVersionKey, v, err)
+			}
+		}
+		m[SchemaVersionKey] = v
+	}
+	return pending, nil
+}
+
+// MigrateYAMLFile reads path with ReadYAMLFileRaw, applies pending migrations and, when write
+// is true and anything changed, writes the result back atomically with WriteYAMLFile, keeping
+// the file's comments and key order. ENC[...] values are written back still encrypted. It
+// returns the versions applied.
+func MigrateYAMLFile(path string, write bool) ([]int, error) {
+	m, err := ReadYAMLFileRaw(path)
+	if err != nil {
+		return nil, err
+	}
+	applied, err := Migrate(m)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if write && len(applied) > 0 {
+		orig, err := ReadYAMLNode(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := WriteYAMLFile(path, m, WriteOptions{PreserveComments: true, Original: orig}); err != nil {
+			return nil, err
+		}
+	}
+	return applied, nil
+}
+
+func migrateInCallToolsKey(m map[string]interface{}) error {
+	legacy, ok := m["in_call_http_tools"]
+	if !ok {
+		return nil
+	}
+	if _, exists := m["in_call_tools"].(map[string]any); exists {
+		return fmt.Errorf("both in_call_http_tools and in_call_tools are set; merge them manually")
+	}
+	m["in_call_tools"] = legacy
+	delete(m, "in_call_http_tools")
+	return nil
+}
@@ -0,0 +1,123 @@
+package configmerge
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/secrets"
+)
+
+func TestMigrateChainsFromOldVersion(t *testing.T) {
+	m := map[string]any{
+		"config_version":     4,
+		"in_call_http_tools": map[string]any{"lookup": map[string]any{"url": "http://x"}},
+	}
+	applied, err := Migrate(m)
+	if err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	if !reflect.DeepEqual(applied, []int{5, 6}) {
+		t.Fatalf("applied=%v", applied)
+	}
+	if m["config_version"] != CurrentSchemaVersion {
+		t.Fatalf("config_version=%v", m["config_version"])
+	}
+	if _, ok := m["in_call_http_tools"]; ok {
+		t.Fatalf("legacy key not removed: %#v", m)
+	}
+	if _, ok := m["in_call_tools"]; !ok {
+		t.Fatalf("in_call_tools missing: %#v", m)
+	}
+}
+
+func TestMigrateLeavesCurrentAndUnversionedAlone(t *testing.T) {
+	for _, m := range []map[string]any{
+		{"config_version": CurrentSchemaVersion, "a": 1},
+		{"a": 1},
+	} {
+		before := DeepMerge(map[string]any{}, m)
+		applied, err := Migrate(m)
+		if err != nil || len(applied) != 0 || !reflect.DeepEqual(m, before) {
+			t.Fatalf("applied=%v err=%v m=%#v", applied, err, m)
+		}
+	}
+	if _, err := Migrate(map[string]any{"config_version": CurrentSchemaVersion + 1}); err == nil {
+		t.Fatalf("expected error for newer schema")
+	}
+}
+
+func TestMigrateYAMLFileDryRunDoesNotWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ai-agent.yaml")
+	orig := []byte("config_version: 5\nfoo: bar\n")
+	if err := os.WriteFile(path, orig, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	applied, err := MigrateYAMLFile(path, false)
+	if err != nil || !reflect.DeepEqual(applied, []int{6}) {
+		t.Fatalf("applied=%v err=%v", applied, err)
+	}
+	if got, _ := os.ReadFile(path); string(got) != string(orig) {
+		t.Fatalf("dry run modified file: %q", got)
+	}
+	if _, err := MigrateYAMLFile(path, true); err != nil {
+		t.Fatal(err)
+	}
+	m, err := ReadYAMLFile(path)
+	if err != nil || m["config_version"] != CurrentSchemaVersion {
+		t.Fatalf("after write: %#v err=%v", m, err)
+	}
+}
+
+func TestMigrateYAMLFileKeepsEncryptedValues(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "config", "ai-agent.yaml")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("config_version: 5\nasterisk:\n  password: s3cr3t\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	keyFile := filepath.Join(root, filepath.FromSlash(secrets.KeyFile))
+	if _, err := secrets.GenerateKeyFile(keyFile); err != nil {
+		t.Fatal(err)
+	}
+	if err := secrets.EncryptYAMLSecrets(path, keyFile, []string{"password"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := MigrateYAMLFile(path, true); err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(got), "s3cr3t") || !strings.Contains(string(got), "ENC[aes256gcm,") {
+		t.Fatalf("migrated file lost its encrypted value:\n%s", got)
+	}
+	if m, err := ReadYAMLFileDecrypted(path); err != nil || m["asterisk"].(map[string]any)["password"] != "s3cr3t" {
+		t.Fatalf("ReadYAMLFileDecrypted after migrate = %v, %v", m, err)
+	}
+}
+
+func TestMigrateYAMLFileRefusesIncludes(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "ai-agent.yaml")
+	orig := []byte("config_version: 5\nproviders: !include providers.yaml\n")
+	if err := os.WriteFile(path, orig, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "providers.yaml"), []byte("openai:\n  enabled: true\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := MigrateYAMLFile(path, true); !errors.Is(err, ErrIncludeNotWritable) {
+		t.Fatalf("err = %v, want ErrIncludeNotWritable", err)
+	}
+	if got, _ := os.ReadFile(path); string(got) != string(orig) {
+		t.Fatalf("file was rewritten: %q", got)
+	}
+}
@@ -0,0 +1,141 @@
+package configmerge
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrKeyNotFound is returned by ConfigStore.Get when a dotted key is absent.
+var ErrKeyNotFound = errors.New("key not found")
+
+// ConfigStore reads and writes single keys of a YAML mapping file (normally
+// config/ai-agent.local.yaml) addressed in dot notation, e.g. "audiosocket.port". Set edits
+// the parsed yaml.Node tree rather than a map, so comments, key order and quoting elsewhere
+// in the file survive; only the file's indentation is normalised to two spaces.
+type ConfigStore struct {
+	Path string
+}
+
+// NewConfigStore returns a store for the YAML file at path.
+func NewConfigStore(path string) *ConfigStore {
+	return &ConfigStore{Path: path}
+}
+
+// Get returns the value at key, normalised like ReadYAMLFile (mappings are map[string]any).
+// A missing file or key returns an error wrapping ErrKeyNotFound.
+func (s *ConfigStore) Get(key string) (any, error) {
+	parts, err := splitKey(key)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	node := doc.Content[0]
+	for i, part := range parts {
+		if node.Kind != yaml.MappingNode {
+			return nil, fmt.Errorf("%s is not a mapping", strings.Join(parts[:i], "."))
+		}
+		_, val := mappingEntry(node, part)
+		if val == nil {
+			return nil, fmt.Errorf("%w: %s", ErrKeyNotFound, key)
+		}
+		node = val
+	}
+	var out any
+	if err := node.Decode(&out); err != nil {
+		return nil, err
+	}
+	return normalizeYAMLValue(out), nil
+}
+
+// Set stores value at key, creating intermediate mappings as needed, and rewrites the file
+// atomically (temp file + rename). Comments attached to a replaced value are kept.
+func (s *ConfigStore) Set(key string, value any) error {
+	parts, err := splitKey(key)
+	if err != nil {
+		return err
+	}
+	doc, err := s.load()
+	if err != nil {
+		return err
+	}
+	var newVal yaml.Node
+	if err := newVal.Encode(value); err != nil {
+		return fmt.Errorf("encode %s: %w", key, err)
+	}
+
+	node := doc.Content[0]
+	for i, part := range parts {
+		if node.Kind != yaml.MappingNode {
+			return fmt.Errorf("cannot set %s: %s is not a mapping", key, strings.Join(parts[:i], "."))
+		}
+		_, val := mappingEntry(node, part)
+		last := i == len(parts)-1
+		if val == nil {
+			child := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			if last {
+				child = &newVal
+			}
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: part}, child)
+			node = child
+			continue
+		}
+		if last {
+			newVal.HeadComment, newVal.LineComment, newVal.FootComment = val.HeadComment, val.LineComment, val.FootComment
+			*val = newVal
+		}
+		node = val
+	}
+
+	b, err := encodeYAMLDocument(doc)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(s.Path, b)
+}
+
+// load parses the file as a document whose root is a mapping. A missing or empty file yields
+// an empty mapping so Set can create it.
+func (s *ConfigStore) load() (*yaml.Node, error) {
+	b, err := os.ReadFile(s.Path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return nil, newParseErrorDetail(s.Path, b, err)
+	}
+	if doc.Kind == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("%s: YAML top-level must be a mapping", s.Path)
+	}
+	return &doc, nil
+}
+
+// mappingEntry returns the key and value nodes for name in a mapping node, or nils.
+func mappingEntry(m *yaml.Node, name string) (*yaml.Node, *yaml.Node) {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == name {
+			return m.Content[i], m.Content[i+1]
+		}
+	}
+	return nil, nil
+}
+
+func splitKey(key string) ([]string, error) {
+	parts := strings.Split(strings.TrimSpace(key), ".")
+	for _, p := range parts {
+		if p == "" {
+			return nil, fmt.Errorf("invalid key %q (use dot notation, e.g. audiosocket.port)", key)
+		}
+	}
+	return parts, nil
+}
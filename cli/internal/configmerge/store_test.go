@@ -0,0 +1,66 @@
+package configmerge
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConfigStoreSetPreservesComments(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ai-agent.local.yaml")
+	src := "# operator overrides\naudiosocket:\n  # bind address\n  host: 0.0.0.0 # all interfaces\n  port: 8090\n"
+	if err := os.WriteFile(path, []byte(src), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	s := NewConfigStore(path)
+
+	if err := s.Set("audiosocket.host", "127.0.0.1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Set("providers.openai.model", "gpt-4o"); err != nil {
+		t.Fatal(err)
+	}
+	b, _ := os.ReadFile(path)
+	out := string(b)
+	for _, want := range []string{"# operator overrides", "# bind address", "host: 127.0.0.1 # all interfaces", "port: 8090", "providers:\n  openai:\n    model: gpt-4o"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("missing %q in:\n%s", want, out)
+		}
+	}
+	if st, _ := os.Stat(path); st.Mode().Perm() != 0o600 {
+		t.Fatalf("mode = %v, want 0600", st.Mode().Perm())
+	}
+
+	if v, err := s.Get("audiosocket.port"); err != nil || v != 8090 {
+		t.Fatalf("Get port = %#v, %v", v, err)
+	}
+	if v, err := s.Get("providers"); err != nil || v.(map[string]any)["openai"].(map[string]any)["model"] != "gpt-4o" {
+		t.Fatalf("Get providers = %#v, %v", v, err)
+	}
+	if _, err := s.Get("audiosocket.format"); !errors.Is(err, ErrKeyNotFound) {
+		t.Fatalf("missing key: %v", err)
+	}
+	if err := s.Set("audiosocket.port.x", 1); err == nil || !strings.Contains(err.Error(), "audiosocket.port is not a mapping") {
+		t.Fatalf("set under scalar: %v", err)
+	}
+	if _, err := s.Get("audiosocket..port"); err == nil {
+		t.Fatal("expected invalid key error")
+	}
+}
+
+func TestConfigStoreSetCreatesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ai-agent.local.yaml")
+	s := NewConfigStore(path)
+	if _, err := s.Get("a"); !errors.Is(err, ErrKeyNotFound) {
+		t.Fatalf("missing file: %v", err)
+	}
+	if err := s.Set("barge_in.enabled", false); err != nil {
+		t.Fatal(err)
+	}
+	m, err := ReadYAMLFile(path)
+	if err != nil || m["barge_in"].(map[string]any)["enabled"] != false {
+		t.Fatalf("got %#v, %v", m, err)
+	}
+}
@@ -0,0 +1,153 @@
+package configmerge
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/maputil"
+	"gopkg.in/yaml.v3"
+)
+
+// WriteOptions controls how WriteYAMLFile serializes a mapping.
+type WriteOptions struct {
+	// SortKeys emits the keys of every mapping in byte-wise lexicographic order, so the output
+	// is stable across runs and diffs only show real changes.
+	SortKeys bool
+	// PreserveComments copies the comments of Original onto the keys and values still present
+	// in the data. Without SortKeys, keys also keep their order from Original; keys new to the
+	// data follow them.
+	PreserveComments bool
+	// Original is the document the data was read from, as returned by ReadYAMLNode.
+	Original *yaml.Node
+}
+
+// ReadYAMLNode parses path into a document node, keeping comments and key order. Unlike
+// ReadYAMLFile it does not resolve !include, so the node describes the file exactly as
+// written. It is meant as WriteOptions.Original.
+func ReadYAMLNode(path string) (*yaml.Node, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return nil, newParseErrorDetail(path, b, err)
+	}
+	return &doc, nil
+}
+
+// WriteYAMLFile serializes data to path atomically (temp file + rename, keeping the mode of an
+// existing file) with a 2-space indent. See WriteOptions for key order and comments.
+func WriteYAMLFile(path string, data map[string]any, opts WriteOptions) error {
+	var root yaml.Node
+	if err := root.Encode(data); err != nil {
+		return fmt.Errorf("encode %s: %w", path, err)
+	}
+	doc := &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{&root}}
+	if opts.PreserveComments && opts.Original != nil {
+		orig := opts.Original
+		if orig.Kind == yaml.DocumentNode {
+			doc.HeadComment, doc.FootComment = orig.HeadComment, orig.FootComment
+			if len(orig.Content) > 0 {
+				orig = orig.Content[0]
+			}
+		}
+		copyComments(&root, orig, !opts.SortKeys)
+	}
+	if opts.SortKeys {
+		sortMappingKeys(&root)
+	}
+	b, err := encodeYAMLDocument(doc)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, b)
+}
+
+// encodeYAMLDocument renders doc with the 2-space indent used by every file this package
+// writes from a node.
+func encodeYAMLDocument(doc *yaml.Node) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// copyComments copies the comments of src onto dst, matching mapping entries by key and
+// sequence items by index. With keepOrder, dst's mapping entries are reordered to follow src.
+func copyComments(dst, src *yaml.Node, keepOrder bool) {
+	dst.HeadComment, dst.LineComment, dst.FootComment = src.HeadComment, src.LineComment, src.FootComment
+	switch {
+	case dst.Kind == yaml.MappingNode && src.Kind == yaml.MappingNode:
+		pos := map[string]int{}
+		for i := 0; i+1 < len(src.Content); i += 2 {
+			pos[src.Content[i].Value] = i
+		}
+		for i := 0; i+1 < len(dst.Content); i += 2 {
+			j, ok := pos[dst.Content[i].Value]
+			if !ok {
+				continue
+			}
+			k := src.Content[j]
+			dst.Content[i].HeadComment, dst.Content[i].LineComment, dst.Content[i].FootComment = k.HeadComment, k.LineComment, k.FootComment
+			copyComments(dst.Content[i+1], src.Content[j+1], keepOrder)
+		}
+		if keepOrder {
+			reorderMapping(dst, func(key string) (int, bool) {
+				j, ok := pos[key]
+				return j, ok
+			})
+		}
+	case dst.Kind == yaml.SequenceNode && src.Kind == yaml.SequenceNode:
+		for i := 0; i < len(dst.Content) && i < len(src.Content); i++ {
+			copyComments(dst.Content[i], src.Content[i], keepOrder)
+		}
+	}
+}
+
+// reorderMapping stably sorts the entries of mapping node m by rank; entries without a rank
+// keep their relative order after the ranked ones.
+func reorderMapping(m *yaml.Node, rank func(key string) (int, bool)) {
+	type entry struct{ key, val *yaml.Node }
+	entries := make([]entry, 0, len(m.Content)/2)
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		entries = append(entries, entry{m.Content[i], m.Content[i+1]})
+	}
+	sort.SliceStable(entries, func(a, b int) bool {
+		ra, oka := rank(entries[a].key.Value)
+		rb, okb := rank(entries[b].key.Value)
+		if oka != okb {
+			return oka
+		}
+		return oka && ra < rb
+	})
+	m.Content = m.Content[:0]
+	for _, e := range entries {
+		m.Content = append(m.Content, e.key, e.val)
+	}
+}
+
+// sortMappingKeys sorts the entries of every mapping under n by key.
+func sortMappingKeys(n *yaml.Node) {
+	if n.Kind == yaml.MappingNode {
+		keys := map[string]int{}
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			keys[n.Content[i].Value] = 0
+		}
+		for i, k := range maputil.SortedKeys(keys) {
+			keys[k] = i
+		}
+		reorderMapping(n, func(key string) (int, bool) { return keys[key], true })
+	}
+	for _, c := range n.Content {
+		sortMappingKeys(c)
+	}
+}
@@ -0,0 +1,100 @@
+package configmerge
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const commentedYAML = `# Operator overrides
+zeta: 1 # last letter
+providers:
+  # primary provider
+  openai:
+    model: gpt-4o # keep in sync with the dashboard
+    enabled: true
+alpha:
+  - one
+  - two # second
+`
+
+func TestWriteYAMLFileRoundTripPreservesComments(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ai-agent.local.yaml")
+	if err := os.WriteFile(path, []byte(commentedYAML), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	orig, err := ReadYAMLNode(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := ReadYAMLFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Unchanged data comes back byte for byte, in the original order.
+	if err := WriteYAMLFile(path, m, WriteOptions{PreserveComments: true, Original: orig}); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := os.ReadFile(path); string(got) != commentedYAML {
+		t.Fatalf("round trip changed the file:\n%s", got)
+	}
+	if st, _ := os.Stat(path); st.Mode().Perm() != 0o600 {
+		t.Fatalf("mode = %v, want 0600", st.Mode().Perm())
+	}
+
+	// A changed value keeps its comment; a new key follows the existing ones.
+	m["providers"].(map[string]any)["openai"].(map[string]any)["model"] = "gpt-4o-mini"
+	m["beta"] = true
+	if err := WriteYAMLFile(path, m, WriteOptions{PreserveComments: true, Original: orig}); err != nil {
+		t.Fatal(err)
+	}
+	want := `# Operator overrides
+zeta: 1 # last letter
+providers:
+  # primary provider
+  openai:
+    model: gpt-4o-mini # keep in sync with the dashboard
+    enabled: true
+alpha:
+  - one
+  - two # second
+beta: true
+`
+	if got, _ := os.ReadFile(path); string(got) != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", got, want)
+	}
+	back, err := ReadYAMLFile(path)
+	if err != nil || !reflect.DeepEqual(back, m) {
+		t.Fatalf("read back %#v, err = %v", back, err)
+	}
+}
+
+func TestWriteYAMLFileSortKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.yaml")
+	data := map[string]any{"b10": 1, "b9": 2, "A": map[string]any{"z": 1, "a": 2}}
+	if err := WriteYAMLFile(path, data, WriteOptions{SortKeys: true}); err != nil {
+		t.Fatal(err)
+	}
+	want := "A:\n  a: 2\n  z: 1\nb10: 1\nb9: 2\n"
+	if got, _ := os.ReadFile(path); string(got) != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestMigrateYAMLFileKeepsComments(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ai-agent.yaml")
+	src := "# base config\nconfig_version: 5\nin_call_http_tools: {} # legacy key\nfoo: bar # keep me\n"
+	if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := MigrateYAMLFile(path, true); err != nil {
+		t.Fatal(err)
+	}
+	// The renamed key is new to the file, so it follows the keys that kept their names.
+	want := "# base config\nconfig_version: 6\nfoo: bar # keep me\nin_call_tools: {}\n"
+	if got, _ := os.ReadFile(path); string(got) != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
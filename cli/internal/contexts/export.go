@@ -0,0 +1,139 @@
+package contexts
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/configmerge"
+	"gopkg.in/yaml.v3"
+)
+
+// ContextExport is the document agent config contexts export writes and import --format json
+// reads: every context file as a map, in file name order, e.g.
+//
+//	{"contexts": [{"name": "sales", "system_prompt": "...", ...}], "exportedAt": "2026-05-04T10:30:00Z"}
+type ContextExport struct {
+	Contexts   []map[string]any `json:"contexts" yaml:"contexts"`
+	ExportedAt string           `json:"exportedAt" yaml:"exportedAt"` // RFC 3339, UTC
+}
+
+// now is replaced in tests.
+var now = time.Now
+
+// ExportContexts reads every context file in dir. A file that is not a YAML mapping is an
+// error; files in DeletedDir are not exported.
+func ExportContexts(dir string) (ContextExport, error) {
+	list, err := NewContextStore(dir).List()
+	if err != nil {
+		return ContextExport{}, err
+	}
+	exp := ContextExport{Contexts: make([]map[string]any, 0, len(list)), ExportedAt: now().UTC().Format(time.RFC3339)}
+	for _, c := range list {
+		data, err := configmerge.ReadYAMLFile(c.Path)
+		if err != nil {
+			return ContextExport{}, fmt.Errorf("%s: %w", filepath.Base(c.Path), err)
+		}
+		exp.Contexts = append(exp.Contexts, data)
+	}
+	return exp, nil
+}
+
+// ExportContextsJSON is ExportContexts marshalled as indented JSON, for the Admin UI API.
+func ExportContextsJSON(dir string) ([]byte, error) {
+	exp, err := ExportContexts(dir)
+	if err != nil {
+		return nil, err
+	}
+	out, err := json.MarshalIndent(exp, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(out, '\n'), nil
+}
+
+// unsafeNameChars are the characters a name field may have that a context file name may not.
+var unsafeNameChars = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+
+// ImportContextsJSON imports the contexts of a ContextExport document into destDir, like
+// ImportContextsFromZip: each is written as <name>.yaml (its name field, with characters a
+// file name cannot have replaced by '-'), validated, and renamed into place under opts only if
+// all are valid. Key order is kept. A YAML export is accepted too.
+func ImportContextsJSON(data []byte, destDir string, opts ImportOptions) ([]string, error) {
+	if err := checkPolicy(opts.OnCollision); err != nil {
+		return nil, err
+	}
+	var doc struct {
+		Contexts []yaml.Node `yaml:"contexts"`
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid export document: %w", err)
+	}
+	if len(doc.Contexts) == 0 {
+		return nil, errors.New(`no contexts in the document (expected {"contexts": [...]})`)
+	}
+
+	if err := os.MkdirAll(destDir, 0o755); err != nil {
+		return nil, err
+	}
+	tmpDir, err := os.MkdirTemp(destDir, ".import-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	var entries []stagedContext
+	seen := map[string]int{} // file name -> index in contexts
+	for i := range doc.Contexts {
+		n := &doc.Contexts[i]
+		src := fmt.Sprintf("contexts[%d]", i)
+		if n.Kind != yaml.MappingNode {
+			return nil, fmt.Errorf("%s: not an object", src)
+		}
+		var fields struct {
+			Name string `yaml:"name"`
+		}
+		_ = n.Decode(&fields)
+		name, err := normalizeName(strings.Trim(unsafeNameChars.ReplaceAllString(strings.TrimSpace(fields.Name), "-"), "-."))
+		if err != nil {
+			return nil, fmt.Errorf(`%s: a non-empty "name" is required to name the file: %w`, src, err)
+		}
+		if other, dup := seen[name]; dup {
+			return nil, fmt.Errorf("contexts[%d] and %s both import as %s.yaml", other, src, name)
+		}
+		seen[name] = i
+
+		clearStyle(n)
+		var buf bytes.Buffer
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(n); err != nil {
+			return nil, fmt.Errorf("%s: %w", src, err)
+		}
+		_ = enc.Close()
+		if buf.Len() > MaxImportFileSize {
+			return nil, fmt.Errorf("%s: larger than %d bytes", src, MaxImportFileSize)
+		}
+		tmp := filepath.Join(tmpDir, name+".yaml")
+		if err := os.WriteFile(tmp, buf.Bytes(), 0o644); err != nil {
+			return nil, err
+		}
+		entries = append(entries, stagedContext{src: src + " (" + name + ")", name: name, tmp: tmp})
+	}
+	return installStaged(entries, destDir, opts)
+}
+
+// clearStyle resets the flow and quoting style of a decoded JSON tree, so it is written as
+// block YAML like a hand-written context file.
+func clearStyle(n *yaml.Node) {
+	n.Style = 0
+	for _, c := range n.Content {
+		clearStyle(c)
+	}
+}
@@ -0,0 +1,102 @@
+package contexts
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/secrets"
+)
+
+func TestExportContextsJSON(t *testing.T) {
+	now = func() time.Time { return time.Date(2026, 5, 4, 12, 30, 0, 0, time.FixedZone("X", 2*3600)) }
+	t.Cleanup(func() { now = time.Now })
+	dir := filepath.Join(t.TempDir(), "contexts")
+	writeFile(t, filepath.Join(dir, "support.yml"), ctxYAML("support"))
+	writeFile(t, filepath.Join(dir, "sales.yaml"), ctxYAML("sales")+"tools: [transfer]\n")
+	writeFile(t, filepath.Join(dir, DeletedDir, "old.yaml"), ctxYAML("old"))
+
+	out, err := ExportContextsJSON(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var exp ContextExport
+	if err := json.Unmarshal(out, &exp); err != nil {
+		t.Fatalf("%v\n%s", err, out)
+	}
+	if exp.ExportedAt != "2026-05-04T10:30:00Z" || len(exp.Contexts) != 2 {
+		t.Fatalf("export = %+v", exp)
+	}
+	if exp.Contexts[0]["name"] != "sales" || exp.Contexts[1]["name"] != "support" {
+		t.Fatalf("contexts = %+v", exp.Contexts)
+	}
+	if tools, _ := exp.Contexts[0]["tools"].([]any); len(tools) != 1 || tools[0] != "transfer" {
+		t.Fatalf("tools = %#v", exp.Contexts[0]["tools"])
+	}
+
+	// The export imports back into an empty directory unchanged.
+	dest := filepath.Join(t.TempDir(), "contexts")
+	names, err := ImportContextsJSON(out, dest, ImportOptions{})
+	if err != nil || strings.Join(names, ",") != "sales,support" {
+		t.Fatalf("import = %v, %v", names, err)
+	}
+	again, err := ExportContextsJSON(dest)
+	if err != nil || string(again) != string(out) {
+		t.Fatalf("round trip differs (%v):\n%s\n%s", err, out, again)
+	}
+	if _, err := ImportContextsJSON(out, dest, ImportOptions{}); !errors.Is(err, ErrContextExists) {
+		t.Fatalf("collision: err = %v", err)
+	}
+}
+
+func TestImportContextsJSON(t *testing.T) {
+	dest := t.TempDir()
+	doc := `{"contexts": [{"name": "Sales Team", "system_prompt": "Line one.\nLine two.", "voice": "alloy", "language": "en-US", "greeting": "true", "max_turns": 5}]}`
+	names, err := ImportContextsJSON([]byte(doc), dest, ImportOptions{})
+	if err != nil || strings.Join(names, ",") != "Sales-Team" {
+		t.Fatalf("import = %v, %v", names, err)
+	}
+	data, _ := os.ReadFile(filepath.Join(dest, "Sales-Team.yaml"))
+	want := "name: Sales Team\nsystem_prompt: |-\n  Line one.\n  Line two.\nvoice: alloy\nlanguage: en-US\ngreeting: \"true\"\nmax_turns: 5\n"
+	if string(data) != want {
+		t.Fatalf("file =\n%s\nwant\n%s", data, want)
+	}
+
+	for doc, msg := range map[string]string{
+		`{"contexts": []}`:                       "no contexts",
+		`{"contexts": ["x"]}`:                    "not an object",
+		`{"contexts": [{"voice": "alloy"}]}`:     `"name" is required`,
+		`{"contexts": [{"name": "a", "voice": 1`: "invalid export document",
+		`{"contexts": [{"name": "bad"}]}`:        "nothing imported",
+	} {
+		if _, err := ImportContextsJSON([]byte(doc), t.TempDir(), ImportOptions{}); err == nil || !strings.Contains(err.Error(), msg) {
+			t.Errorf("%s: err = %v, want %q", doc, err, msg)
+		}
+	}
+}
+
+func TestExportContextsKeepsEncryptedValues(t *testing.T) {
+	root := t.TempDir()
+	keyFile := filepath.Join(root, filepath.FromSlash(secrets.KeyFile))
+	if _, err := secrets.GenerateKeyFile(keyFile); err != nil {
+		t.Fatal(err)
+	}
+	dir := filepath.Join(root, "config", "contexts")
+	path := filepath.Join(dir, "sales.yaml")
+	writeFile(t, path, ctxYAML("sales")+"api_key: s3cr3t\n")
+	if err := secrets.EncryptYAMLSecrets(path, keyFile, []string{"api_key"}); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := ExportContextsJSON(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(out), "s3cr3t") || !strings.Contains(string(out), "ENC[aes256gcm,") {
+		t.Fatalf("export should keep the ENC[...] value:\n%s", out)
+	}
+}
@@ -0,0 +1,220 @@
+package contexts
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/check"
+)
+
+// Collision policies for ImportContextsFromZip and ImportContextsJSON when a context file of the same name exists.
+const (
+	CollisionFail      = ""          // refuse the import
+	CollisionOverwrite = "overwrite" // replace the existing file
+	CollisionSkip      = "skip"      // keep the existing file, do not import
+	CollisionRename    = "rename"    // import as <name>-1.yaml, <name>-2.yaml, ...
+)
+
+// MaxImportFileSize caps each extracted context file, so a crafted archive cannot fill the disk.
+const MaxImportFileSize = 1 << 20
+
+// ImportOptions controls ImportContextsFromZip and ImportContextsJSON.
+type ImportOptions struct {
+	// OnCollision is one of the Collision* policies.
+	OnCollision string
+}
+
+// ImportContextsFromZip imports every .yaml/.yml entry of the zip at zipPath into destDir and
+// returns the names of the context files written. Directories inside the archive are ignored
+// (pack/sales.yaml imports as sales.yaml), as are other files. The import is all or nothing up
+// to the final renames: an entry with an absolute or "../" path, a file that fails
+// ValidateContext, or two files defining the same context name aborts it before destDir is
+// touched. Files are extracted to a temporary directory in destDir and renamed into place.
+func ImportContextsFromZip(zipPath, destDir string, opts ImportOptions) ([]string, error) {
+	if err := checkPolicy(opts.OnCollision); err != nil {
+		return nil, err
+	}
+	zr, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	if err := os.MkdirAll(destDir, 0o755); err != nil {
+		return nil, err
+	}
+	tmpDir, err := os.MkdirTemp(destDir, ".import-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	var entries []stagedContext
+	seen := map[string]string{} // file name -> zip entry
+	for _, f := range zr.File {
+		if err := checkEntryPath(f.Name); err != nil {
+			return nil, err
+		}
+		base := path.Base(f.Name)
+		ext := strings.ToLower(path.Ext(base))
+		if f.FileInfo().IsDir() || (ext != ".yaml" && ext != ".yml") || strings.HasPrefix(base, ".") || strings.HasPrefix(f.Name, "__MACOSX/") {
+			continue
+		}
+		name, err := normalizeName(base)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.Name, err)
+		}
+		if other, dup := seen[name]; dup {
+			return nil, fmt.Errorf("%s and %s both import as %s.yaml", other, f.Name, name)
+		}
+		seen[name] = f.Name
+
+		tmp := filepath.Join(tmpDir, name+".yaml")
+		if err := extractEntry(f, tmp); err != nil {
+			return nil, fmt.Errorf("%s: %w", f.Name, err)
+		}
+		entries = append(entries, stagedContext{src: f.Name, name: name, tmp: tmp})
+	}
+	if len(entries) == 0 {
+		return nil, errors.New("no .yaml context files in " + zipPath)
+	}
+	return installStaged(entries, destDir, opts)
+}
+
+// stagedContext is a context file written to the import's temporary directory.
+type stagedContext struct {
+	src  string // where it came from, for messages
+	name string // file name without .yaml
+	tmp  string
+	key  string // name field
+}
+
+// installStaged validates the staged files and renames them into destDir under opts. Nothing
+// is renamed unless every file is valid and no collision stops the import.
+func installStaged(entries []stagedContext, destDir string, opts ImportOptions) ([]string, error) {
+	var invalid []string
+	for i, e := range entries {
+		issues, err := ValidateContext(e.tmp)
+		if err != nil {
+			invalid = append(invalid, fmt.Sprintf("%s: %v", e.src, err))
+			continue
+		}
+		for _, is := range issues {
+			if is.Status == check.StatusFail {
+				invalid = append(invalid, fmt.Sprintf("%s: %s %s", e.src, is.Field, is.Message))
+			}
+		}
+		entries[i].key, _ = contextKey(e.tmp)
+	}
+	if len(invalid) > 0 {
+		return nil, fmt.Errorf("invalid context files, nothing imported:\n  %s", strings.Join(invalid, "\n  "))
+	}
+
+	// Decide each target before renaming anything, so a collision error leaves destDir as is.
+	store := NewContextStore(destDir)
+	existing, err := store.List()
+	if err != nil {
+		return nil, err
+	}
+	keys := map[string]string{} // context name field -> file that defines it after the import
+	taken := map[string]bool{}
+	for _, c := range existing {
+		taken[c.Name] = true
+		if k, err := contextKey(c.Path); err == nil {
+			keys[k] = filepath.Base(c.Path)
+		}
+	}
+	type move struct{ tmp, dst, name, replaces string }
+	var moves []move
+	for _, e := range entries {
+		m := move{tmp: e.tmp, name: e.name, dst: filepath.Join(destDir, e.name+".yaml")}
+		if c, err := store.find(e.name); err == nil {
+			switch opts.OnCollision {
+			case CollisionSkip:
+				continue
+			case CollisionOverwrite:
+				m.replaces = c.Path
+				if k, err := contextKey(c.Path); err == nil && keys[k] == filepath.Base(c.Path) {
+					delete(keys, k)
+				}
+			case CollisionRename:
+				for i := 1; taken[m.name]; i++ {
+					m.name = e.name + "-" + strconv.Itoa(i)
+				}
+				m.dst = filepath.Join(destDir, m.name+".yaml")
+			default:
+				return nil, fmt.Errorf("%w: %s (pass --overwrite, --skip or --rename)", ErrContextExists, filepath.Base(c.Path))
+			}
+		}
+		if other, dup := keys[e.key]; dup {
+			return nil, fmt.Errorf("%w: %s already defines name %q (the engine would skip %s.yaml)", ErrContextExists, other, e.key, m.name)
+		}
+		keys[e.key] = m.name + ".yaml"
+		taken[m.name] = true
+		moves = append(moves, m)
+	}
+
+	var imported []string
+	for _, m := range moves {
+		if err := os.Rename(m.tmp, m.dst); err != nil {
+			return imported, err
+		}
+		if m.replaces != "" && m.replaces != m.dst {
+			_ = os.Remove(m.replaces) // sales.yml overwritten by sales.yaml
+		}
+		imported = append(imported, m.name)
+	}
+	return imported, nil
+}
+
+func checkPolicy(policy string) error {
+	switch policy {
+	case CollisionFail, CollisionOverwrite, CollisionSkip, CollisionRename:
+		return nil
+	}
+	return fmt.Errorf("unknown collision policy %q", policy)
+}
+
+// checkEntryPath rejects zip entry names that would leave the extraction directory.
+func checkEntryPath(name string) error {
+	clean := strings.ReplaceAll(name, `\`, "/")
+	if path.IsAbs(clean) || filepath.IsAbs(name) || filepath.VolumeName(name) != "" {
+		return fmt.Errorf("unsafe path in archive: %q", name)
+	}
+	for _, part := range strings.Split(clean, "/") {
+		if part == ".." {
+			return fmt.Errorf("unsafe path in archive: %q", name)
+		}
+	}
+	return nil
+}
+
+func extractEntry(f *zip.File, dst string) error {
+	if f.UncompressedSize64 > MaxImportFileSize {
+		return fmt.Errorf("larger than %d bytes", MaxImportFileSize)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return err
+	}
+	n, err := io.Copy(out, io.LimitReader(rc, MaxImportFileSize+1))
+	if err == nil && n > MaxImportFileSize {
+		err = fmt.Errorf("larger than %d bytes", MaxImportFileSize)
+	}
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
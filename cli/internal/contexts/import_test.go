@@ -0,0 +1,99 @@
+package contexts
+
+import (
+	"archive/zip"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func ctxYAML(name string) string {
+	return "name: " + name + "\nsystem_prompt: hi\nvoice: alloy\nlanguage: en-US\n"
+}
+
+// writeZip writes entries (name -> content, in order) to a zip file and returns its path.
+func writeZip(t *testing.T, entries ...[2]string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "pack.zip")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(f)
+	for _, e := range entries {
+		w, err := zw.Create(e[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(e[1]))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	return path
+}
+
+func TestImportContextsFromZip(t *testing.T) {
+	s, _ := newStore(t) // has support.yml with name "support"
+	zipPath := writeZip(t,
+		[2]string{"pack/", ""},
+		[2]string{"pack/sales.yaml", ctxYAML("sales")},
+		[2]string{"pack/README.md", "not a context"},
+		[2]string{"__MACOSX/pack/._sales.yaml", "junk"},
+		[2]string{"billing.yml", ctxYAML("billing")},
+	)
+	names, err := ImportContextsFromZip(zipPath, s.Dir, ImportOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(names, ",") != "sales,billing" {
+		t.Fatalf("imported = %v", names)
+	}
+	list, _ := s.List()
+	if len(list) != 3 || list[0].Name != "billing" || filepath.Ext(list[0].Path) != ".yaml" {
+		t.Fatalf("list = %+v", list)
+	}
+	if entries, _ := os.ReadDir(s.Dir); len(entries) != 3 {
+		t.Fatalf("temp dir left behind: %v", entries)
+	}
+
+	// A second import collides with the files just written.
+	if _, err := ImportContextsFromZip(zipPath, s.Dir, ImportOptions{}); !errors.Is(err, ErrContextExists) {
+		t.Fatalf("collision: err = %v", err)
+	}
+	if names, err := ImportContextsFromZip(zipPath, s.Dir, ImportOptions{OnCollision: CollisionSkip}); err != nil || len(names) != 0 {
+		t.Fatalf("skip: %v, %v", names, err)
+	}
+	if names, err := ImportContextsFromZip(zipPath, s.Dir, ImportOptions{OnCollision: CollisionOverwrite}); err != nil || len(names) != 2 {
+		t.Fatalf("overwrite: %v, %v", names, err)
+	}
+	// Renaming keeps both files, but the engine keys contexts by name, so a copy is refused.
+	if _, err := ImportContextsFromZip(zipPath, s.Dir, ImportOptions{OnCollision: CollisionRename}); !errors.Is(err, ErrContextExists) || !strings.Contains(err.Error(), `"sales"`) {
+		t.Fatalf("rename duplicate key: err = %v", err)
+	}
+	renamed := writeZip(t, [2]string{"support.yaml", ctxYAML("support_v2")})
+	if names, err := ImportContextsFromZip(renamed, s.Dir, ImportOptions{OnCollision: CollisionRename}); err != nil || strings.Join(names, ",") != "support-1" {
+		t.Fatalf("rename: %v, %v", names, err)
+	}
+}
+
+func TestImportContextsFromZipRejects(t *testing.T) {
+	for name, zipPath := range map[string]string{
+		"traversal": writeZip(t, [2]string{"ok.yaml", ctxYAML("ok")}, [2]string{"../../etc/evil.yaml", ctxYAML("evil")}),
+		"absolute":  writeZip(t, [2]string{"/tmp/evil.yaml", ctxYAML("evil")}),
+		"invalid":   writeZip(t, [2]string{"ok.yaml", ctxYAML("ok")}, [2]string{"bad.yaml", "name: bad\n"}),
+		"dup-name":  writeZip(t, [2]string{"a/x.yaml", ctxYAML("x")}, [2]string{"b/x.yml", ctxYAML("y")}),
+		"empty":     writeZip(t, [2]string{"notes.txt", "hi"}),
+	} {
+		s, _ := newStore(t)
+		if _, err := ImportContextsFromZip(zipPath, s.Dir, ImportOptions{}); err == nil {
+			t.Errorf("%s: want an error", name)
+		}
+		if list, _ := s.List(); len(list) != 1 {
+			t.Errorf("%s: destDir changed: %+v", name, list)
+		}
+	}
+}
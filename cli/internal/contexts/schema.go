@@ -0,0 +1,114 @@
+package contexts
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/check"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/configmerge"
+)
+
+// ContextSchema lists what a context file must define, beyond being a YAML mapping.
+type ContextSchema struct {
+	// Required keys must be present with a non-empty string value.
+	Required []string
+	// Languages is the allowlist of BCP-47 tags for the language key, compared case-insensitively.
+	Languages []string
+	// MaxPromptLength is the system_prompt length, in characters, above which a warning is raised.
+	MaxPromptLength int
+}
+
+// DefaultContextSchema is the schema agent config contexts validate applies. The engine maps
+// system_prompt to prompt, so a file that only sets prompt satisfies system_prompt.
+var DefaultContextSchema = ContextSchema{
+	Required: []string{"name", "system_prompt", "voice", "language"},
+	Languages: []string{
+		"ar", "ar-SA", "ar-AE", "bn-IN", "cs-CZ", "da-DK", "de", "de-DE", "de-AT", "de-CH",
+		"el-GR", "en", "en-US", "en-GB", "en-AU", "en-CA", "en-IN", "en-IE", "en-NZ", "en-ZA",
+		"es", "es-ES", "es-MX", "es-US", "es-419", "fi-FI", "fil-PH", "fr", "fr-FR", "fr-CA",
+		"he-IL", "hi", "hi-IN", "hu-HU", "id-ID", "it", "it-IT", "ja", "ja-JP", "kn-IN",
+		"ko", "ko-KR", "ml-IN", "mr-IN", "ms-MY", "nb-NO", "nl", "nl-NL", "nl-BE", "pl-PL",
+		"pt", "pt-BR", "pt-PT", "ro-RO", "ru", "ru-RU", "sk-SK", "sv-SE", "ta-IN", "te-IN",
+		"th-TH", "tr-TR", "uk-UA", "ur-PK", "ur-IN", "vi-VN", "zh", "zh-CN", "zh-TW", "zh-HK",
+	},
+	MaxPromptLength: 4096,
+}
+
+// ValidationIssue is one finding from ValidateContext.
+type ValidationIssue struct {
+	Field   string       `json:"field"`
+	Status  check.Status `json:"status"`
+	Message string       `json:"message"`
+}
+
+// ValidateContext checks the context file at path against DefaultContextSchema. Files that
+// are not a readable YAML mapping return an error rather than issues.
+func ValidateContext(path string) ([]ValidationIssue, error) {
+	return DefaultContextSchema.Validate(path)
+}
+
+// Validate checks the context file at path against s: missing or empty required keys and an
+// unlisted language fail, an overlong system_prompt warns.
+func (s ContextSchema) Validate(path string) ([]ValidationIssue, error) {
+	if err := check.ValidateYAMLMapping(path); err != nil {
+		return nil, err
+	}
+	data, err := configmerge.ReadYAMLFileDecrypted(path)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := data["system_prompt"]; !ok {
+		if prompt, ok := data["prompt"]; ok {
+			data["system_prompt"] = prompt
+		}
+	}
+
+	var issues []ValidationIssue
+	fail := func(field, format string, args ...any) {
+		issues = append(issues, ValidationIssue{Field: field, Status: check.StatusFail, Message: fmt.Sprintf(format, args...)})
+	}
+	for _, key := range s.Required {
+		v, ok := data[key]
+		if !ok || v == nil {
+			fail(key, "required field is missing")
+			continue
+		}
+		str, isString := v.(string)
+		if !isString {
+			fail(key, "must be a string, got %T", v)
+		} else if strings.TrimSpace(str) == "" {
+			fail(key, "required field is empty")
+		}
+	}
+
+	if lang, ok := data["language"].(string); ok && strings.TrimSpace(lang) != "" {
+		if canonical, known := s.language(lang); !known {
+			fail("language", "%q is not a supported BCP-47 language tag (e.g. en-US, es-MX, de-DE)", lang)
+		} else if canonical != strings.TrimSpace(lang) {
+			issues = append(issues, ValidationIssue{Field: "language", Status: check.StatusWarn, Message: fmt.Sprintf("write %q as %q", lang, canonical)})
+		}
+	}
+
+	if prompt, ok := data["system_prompt"].(string); ok && s.MaxPromptLength > 0 {
+		if n := len([]rune(prompt)); n > s.MaxPromptLength {
+			issues = append(issues, ValidationIssue{
+				Field:   "system_prompt",
+				Status:  check.StatusWarn,
+				Message: fmt.Sprintf("%d characters, over the recommended %d; long prompts add latency and cost to every turn", n, s.MaxPromptLength),
+			})
+		}
+	}
+	return issues, nil
+}
+
+// language returns the allowlisted spelling of tag, matching case-insensitively and
+// accepting '_' for '-'.
+func (s ContextSchema) language(tag string) (string, bool) {
+	tag = strings.ReplaceAll(strings.TrimSpace(tag), "_", "-")
+	for _, l := range s.Languages {
+		if strings.EqualFold(l, tag) {
+			return l, true
+		}
+	}
+	return "", false
+}
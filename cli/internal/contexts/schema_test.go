@@ -0,0 +1,62 @@
+package contexts
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/check"
+)
+
+func TestValidateContext(t *testing.T) {
+	dir := t.TempDir()
+	long := strings.Repeat("x", 4097)
+	for _, tc := range []struct {
+		name, body string
+		want       []string // "<status> <field>"
+	}{
+		{"good", "name: sales\nsystem_prompt: sell\nvoice: alloy\nlanguage: en-US\n", nil},
+		{"prompt-alias", "name: sales\nprompt: sell\nvoice: alloy\nlanguage: es-mx\n", []string{"warn language"}},
+		{"missing", "name: sales\nvoice: \"\"\nlanguage: 42\n", []string{"fail system_prompt", "fail voice", "fail language"}},
+		{"bad-language", "name: sales\nsystem_prompt: sell\nvoice: alloy\nlanguage: english\n", []string{"fail language"}},
+		{"long-prompt", "name: sales\nsystem_prompt: " + long + "\nvoice: alloy\nlanguage: en\n", []string{"warn system_prompt"}},
+	} {
+		path := filepath.Join(dir, tc.name+".yaml")
+		writeFile(t, path, tc.body)
+		issues, err := ValidateContext(path)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		var got []string
+		for _, is := range issues {
+			got = append(got, string(is.Status)+" "+is.Field)
+		}
+		if strings.Join(got, ",") != strings.Join(tc.want, ",") {
+			t.Errorf("%s: issues = %+v, want %v", tc.name, issues, tc.want)
+		}
+	}
+
+	path := filepath.Join(dir, "list.yaml")
+	writeFile(t, path, "- a\n")
+	if _, err := ValidateContext(path); err == nil {
+		t.Fatal("want an error for a non-mapping file")
+	}
+}
+
+func TestShippedContextsValidate(t *testing.T) {
+	matches, _ := filepath.Glob("../../../config/contexts/*.yaml")
+	if len(matches) == 0 {
+		t.Skip("repo config/contexts not found")
+	}
+	for _, path := range matches {
+		issues, err := ValidateContext(path)
+		if err != nil {
+			t.Fatalf("%s: %v", path, err)
+		}
+		for _, is := range issues {
+			if is.Status == check.StatusFail {
+				t.Errorf("%s: %s %s", path, is.Field, is.Message)
+			}
+		}
+	}
+}
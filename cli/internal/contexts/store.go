@@ -0,0 +1,246 @@
+// Package contexts manages the per-context YAML files in config/contexts.
+package contexts
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/check"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/configmerge"
+)
+
+// DefaultDir is the contexts directory relative to the repo root.
+const DefaultDir = check.ContextsDir
+
+// DeletedDir is the subdirectory removed context files are moved to. The engine only reads
+// the top level of the contexts directory, so files here are inactive.
+const DeletedDir = ".deleted"
+
+// DefaultRetention is how long a removed context file is kept in DeletedDir.
+const DefaultRetention = 7 * 24 * time.Hour
+
+// expiresMarker separates a deleted file's original name from its expiry time.
+const expiresMarker = ".expires-"
+
+const expiresLayout = "20060102T150405Z"
+
+var (
+	ErrContextExists   = errors.New("context already exists")
+	ErrContextNotFound = errors.New("context not found")
+)
+
+// validName matches a context file name without extension.
+var validName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+// Context is one context file.
+type Context struct {
+	Name    string // file name without .yaml/.yml
+	Path    string
+	Size    int64
+	ModTime time.Time
+}
+
+// ContextStore wraps a contexts directory. Removal is soft: files move to DeletedDir and are
+// deleted for good by Purge once their retention has passed.
+type ContextStore struct {
+	Dir string
+
+	now func() time.Time
+}
+
+// NewContextStore returns a store for the contexts directory dir.
+func NewContextStore(dir string) *ContextStore {
+	return &ContextStore{Dir: dir, now: time.Now}
+}
+
+// List returns the context files sorted by name. A missing directory yields none.
+func (s *ContextStore) List() ([]Context, error) {
+	var out []Context
+	for _, pattern := range []string{"*.yaml", "*.yml"} {
+		matches, err := filepath.Glob(filepath.Join(s.Dir, pattern))
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range matches {
+			info, err := os.Stat(path)
+			if err != nil {
+				return nil, err
+			}
+			if !info.Mode().IsRegular() {
+				continue
+			}
+			out = append(out, Context{
+				Name:    strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)),
+				Path:    path,
+				Size:    info.Size(),
+				ModTime: info.ModTime(),
+			})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out, nil
+}
+
+// Add validates src and copies it to <Dir>/<name>.yaml. src must be a YAML mapping with a
+// non-empty name field (the engine skips files without one) that no other context file
+// already uses.
+func (s *ContextStore) Add(name, src string) (Context, error) {
+	name, err := normalizeName(name)
+	if err != nil {
+		return Context{}, err
+	}
+	if _, err := s.find(name); err == nil {
+		return Context{}, fmt.Errorf("%w: %s", ErrContextExists, name)
+	}
+	key, err := contextKey(src)
+	if err != nil {
+		return Context{}, fmt.Errorf("%s: %w", src, err)
+	}
+	existing, err := s.List()
+	if err != nil {
+		return Context{}, err
+	}
+	for _, c := range existing {
+		if other, err := contextKey(c.Path); err == nil && other == key {
+			return Context{}, fmt.Errorf("%w: %s already defines name %q", ErrContextExists, filepath.Base(c.Path), key)
+		}
+	}
+
+	if err := os.MkdirAll(s.Dir, 0o755); err != nil {
+		return Context{}, err
+	}
+	dst := filepath.Join(s.Dir, name+".yaml")
+	if err := copyFileAtomic(src, dst); err != nil {
+		return Context{}, err
+	}
+	return s.find(name)
+}
+
+// Remove moves the context file for name into DeletedDir, to be purged after retention. A
+// retention of zero or less deletes it immediately. It returns the file's new path ("" when
+// deleted).
+func (s *ContextStore) Remove(name string, retention time.Duration) (string, error) {
+	name, err := normalizeName(name)
+	if err != nil {
+		return "", err
+	}
+	c, err := s.find(name)
+	if err != nil {
+		return "", err
+	}
+	if retention <= 0 {
+		return "", os.Remove(c.Path)
+	}
+	deleted := filepath.Join(s.Dir, DeletedDir)
+	if err := os.MkdirAll(deleted, 0o755); err != nil {
+		return "", err
+	}
+	expires := s.now().Add(retention).UTC().Format(expiresLayout)
+	dst := filepath.Join(deleted, filepath.Base(c.Path)+expiresMarker+expires)
+	if err := os.Rename(c.Path, dst); err != nil {
+		return "", err
+	}
+	return dst, nil
+}
+
+// Purge permanently deletes files in DeletedDir whose retention has passed and returns how
+// many were removed. Files not written by Remove are left alone.
+func (s *ContextStore) Purge() (int, error) {
+	entries, err := os.ReadDir(filepath.Join(s.Dir, DeletedDir))
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	now := s.now()
+	removed := 0
+	for _, entry := range entries {
+		i := strings.LastIndex(entry.Name(), expiresMarker)
+		if i < 0 || !entry.Type().IsRegular() {
+			continue
+		}
+		expires, err := time.Parse(expiresLayout, entry.Name()[i+len(expiresMarker):])
+		if err != nil || now.Before(expires) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(s.Dir, DeletedDir, entry.Name())); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+func (s *ContextStore) find(name string) (Context, error) {
+	list, err := s.List()
+	if err != nil {
+		return Context{}, err
+	}
+	for _, c := range list {
+		if c.Name == name {
+			return c, nil
+		}
+	}
+	return Context{}, fmt.Errorf("%w: %s", ErrContextNotFound, name)
+}
+
+// normalizeName accepts "sales" or "sales.yaml" and rejects anything that could leave Dir.
+func normalizeName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if ext := filepath.Ext(name); ext == ".yaml" || ext == ".yml" {
+		name = strings.TrimSuffix(name, ext)
+	}
+	if !validName.MatchString(name) {
+		return "", fmt.Errorf("invalid context name %q (use letters, digits, '_', '-' and '.')", name)
+	}
+	return name, nil
+}
+
+// contextKey validates path the way agent check does and returns its name field.
+func contextKey(path string) (string, error) {
+	if err := check.ValidateYAMLMapping(path); err != nil {
+		return "", err
+	}
+	data, err := configmerge.ReadYAMLFileDecrypted(path)
+	if err != nil {
+		return "", err
+	}
+	key, _ := data["name"].(string)
+	if strings.TrimSpace(key) == "" {
+		return "", errors.New(`missing "name" field (the engine skips context files without one)`)
+	}
+	return strings.TrimSpace(key), nil
+}
+
+func copyFileAtomic(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	tmp, err := os.CreateTemp(filepath.Dir(dst), "."+filepath.Base(dst)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, in); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(0o644); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), dst)
+}
@@ -0,0 +1,113 @@
+package contexts
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, path, data string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newStore(t *testing.T) (*ContextStore, string) {
+	t.Helper()
+	dir := filepath.Join(t.TempDir(), "contexts")
+	writeFile(t, filepath.Join(dir, "support.yml"), "name: support\nprompt: hi\n")
+	return NewContextStore(dir), t.TempDir()
+}
+
+func TestAddValidatesAndCopies(t *testing.T) {
+	s, src := newStore(t)
+	good := filepath.Join(src, "sales.yaml")
+	writeFile(t, good, "name: sales\nsystem_prompt: sell\n")
+
+	c, err := s.Add("sales.yaml", good)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Name != "sales" || c.Path != filepath.Join(s.Dir, "sales.yaml") || c.Size == 0 {
+		t.Fatalf("unexpected context: %+v", c)
+	}
+	list, err := s.List()
+	if err != nil || len(list) != 2 || list[0].Name != "sales" || list[1].Name != "support" {
+		t.Fatalf("List = %+v, %v", list, err)
+	}
+
+	if _, err := s.Add("sales", good); !errors.Is(err, ErrContextExists) {
+		t.Fatalf("same file name: err = %v", err)
+	}
+	if _, err := s.Add("other", good); !errors.Is(err, ErrContextExists) || !strings.Contains(err.Error(), `"sales"`) {
+		t.Fatalf("same context name: err = %v", err)
+	}
+
+	for name, body := range map[string]string{
+		"noname.yaml": "prompt: hi\n",
+		"list.yaml":   "- a\n- b\n",
+		"broken.yaml": "name: [x\n",
+	} {
+		path := filepath.Join(src, name)
+		writeFile(t, path, body)
+		if _, err := s.Add(strings.TrimSuffix(name, ".yaml"), path); err == nil {
+			t.Errorf("%s: expected a validation error", name)
+		}
+	}
+	for _, name := range []string{"../escape", "a/b", "", ".hidden"} {
+		if _, err := s.Add(name, good); err == nil || !strings.Contains(err.Error(), "invalid context name") {
+			t.Errorf("name %q: err = %v", name, err)
+		}
+	}
+	if list, _ := s.List(); len(list) != 2 {
+		t.Fatalf("failed adds left files behind: %+v", list)
+	}
+}
+
+func TestRemoveAndPurge(t *testing.T) {
+	s, _ := newStore(t)
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+
+	moved, err := s.Remove("support", 48*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Dir(moved) != filepath.Join(s.Dir, DeletedDir) || !strings.HasPrefix(filepath.Base(moved), "support.yml") {
+		t.Fatalf("moved to %s", moved)
+	}
+	if list, _ := s.List(); len(list) != 0 {
+		t.Fatalf("removed context still listed: %+v", list)
+	}
+	if _, err := s.Remove("support", time.Hour); !errors.Is(err, ErrContextNotFound) {
+		t.Fatalf("err = %v, want ErrContextNotFound", err)
+	}
+
+	writeFile(t, filepath.Join(s.Dir, DeletedDir, "notes.txt"), "keep")
+	now = now.Add(47 * time.Hour)
+	if n, err := s.Purge(); err != nil || n != 0 {
+		t.Fatalf("purged %d before expiry (%v)", n, err)
+	}
+	now = now.Add(time.Hour)
+	if n, err := s.Purge(); err != nil || n != 1 {
+		t.Fatalf("purged %d at expiry (%v)", n, err)
+	}
+	if _, err := os.Stat(filepath.Join(s.Dir, DeletedDir, "notes.txt")); err != nil {
+		t.Fatalf("foreign file purged: %v", err)
+	}
+
+	writeFile(t, filepath.Join(s.Dir, "gone.yaml"), "name: gone\n")
+	if moved, err := s.Remove("gone", 0); err != nil || moved != "" {
+		t.Fatalf("immediate delete: %q %v", moved, err)
+	}
+	if _, err := os.Stat(filepath.Join(s.Dir, "gone.yaml")); !os.IsNotExist(err) {
+		t.Fatalf("file not deleted: %v", err)
+	}
+}
@@ -0,0 +1,185 @@
+// Package crash turns a panic in an agent command into a short message and a crash report
+// (.agent/crash-<timestamp>.txt) instead of a bare stack trace.
+package crash
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/backup"
+)
+
+// Dir holds crash reports, relative to the repo root.
+const Dir = ".agent"
+
+const (
+	filePrefix = "crash-"
+	fileSuffix = ".txt"
+	timeLayout = "20060102-150405"
+)
+
+var (
+	// Version is recorded in each report; main sets it.
+	Version = "unknown"
+	// Root returns the directory Dir is created under; main points it at the repo root. When it
+	// fails, reports go to the system temp directory.
+	Root = os.Getwd
+	// now is replaced in tests.
+	now = time.Now
+)
+
+// Report is a crash report file.
+type Report struct {
+	Name    string    `json:"name"`
+	Path    string    `json:"path"`
+	Time    time.Time `json:"time"`
+	Summary string    `json:"summary"` // the panic value
+}
+
+// WithPanicRecovery runs fn and returns its error. If fn panics, the panic value and stack are
+// written to a crash report and an error naming the report is returned in place of the crash.
+// Panics in goroutines fn starts are not caught.
+func WithPanicRecovery(fn func() error) (err error) {
+	defer func() {
+		v := recover()
+		if v == nil {
+			return
+		}
+		stack := debug.Stack()
+		path, werr := WriteReport(v, stack)
+		if werr != nil {
+			// Keep the stack somewhere when the report cannot be written.
+			os.Stderr.Write(stack)
+			err = fmt.Errorf("agent crashed: %v\n(could not write a crash report: %v)", v, werr)
+			return
+		}
+		err = fmt.Errorf("agent crashed: %v\nA crash report was written to %s; please attach it when reporting the problem (agent crash show %s)", v, path, filepath.Base(path))
+	}()
+	return fn()
+}
+
+// WriteReport writes a crash report for panic value v and its stack under Root()/Dir and
+// returns its path. The report records the agent version, Go runtime, command line and the
+// names (not values) of the environment variables set.
+func WriteReport(v interface{}, stack []byte) (string, error) {
+	root, err := Root()
+	if err != nil || root == "" {
+		root = os.TempDir()
+	}
+	dir := filepath.Join(root, Dir)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+
+	t := now()
+	var b strings.Builder
+	fmt.Fprintf(&b, "panic: %v\n\n", v)
+	fmt.Fprintf(&b, "time:    %s\n", t.Format(time.RFC3339))
+	fmt.Fprintf(&b, "version: %s\n", Version)
+	fmt.Fprintf(&b, "go:      %s %s/%s\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	fmt.Fprintf(&b, "args:    %s\n", strings.Join(redactArgs(os.Args), " "))
+	fmt.Fprintf(&b, "env:     %s\n\n", strings.Join(envKeys(os.Environ()), " "))
+	b.Write(stack)
+
+	name := filePrefix + t.Format(timeLayout)
+	for i := 1; ; i++ {
+		path := filepath.Join(dir, name+fileSuffix)
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+		if errors.Is(err, os.ErrExist) && i < 100 {
+			name = fmt.Sprintf("%s%s-%d", filePrefix, t.Format(timeLayout), i)
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		if _, err := f.WriteString(b.String()); err != nil {
+			f.Close()
+			return "", err
+		}
+		return path, f.Close()
+	}
+}
+
+// List returns the crash reports under root/Dir, newest first.
+func List(root string) ([]Report, error) {
+	paths, err := filepath.Glob(filepath.Join(root, Dir, filePrefix+"*"+fileSuffix))
+	if err != nil {
+		return nil, err
+	}
+	reports := make([]Report, 0, len(paths))
+	for _, p := range paths {
+		r := Report{Name: filepath.Base(p), Path: p}
+		stamp := strings.TrimSuffix(strings.TrimPrefix(r.Name, filePrefix), fileSuffix)
+		if len(stamp) > len(timeLayout) {
+			stamp = stamp[:len(timeLayout)]
+		}
+		if t, err := time.ParseInLocation(timeLayout, stamp, time.Local); err == nil {
+			r.Time = t
+		} else if info, err := os.Stat(p); err == nil {
+			r.Time = info.ModTime()
+		}
+		if data, err := os.ReadFile(p); err == nil {
+			first, _, _ := strings.Cut(string(data), "\n")
+			r.Summary = strings.TrimPrefix(first, "panic: ")
+		}
+		reports = append(reports, r)
+	}
+	sort.SliceStable(reports, func(i, j int) bool { return reports[i].Time.After(reports[j].Time) })
+	return reports, nil
+}
+
+// Resolve finds the report name refers to: a path to an existing file, or a report name (with
+// or without .txt) under root/Dir.
+func Resolve(root, name string) (string, error) {
+	if info, err := os.Stat(name); err == nil && !info.IsDir() {
+		return name, nil
+	}
+	base := filepath.Base(name)
+	if !strings.HasSuffix(base, fileSuffix) {
+		base += fileSuffix
+	}
+	path := filepath.Join(root, Dir, base)
+	if _, err := os.Stat(path); err != nil {
+		return "", fmt.Errorf("crash report not found: %s (see agent crash list)", name)
+	}
+	return path, nil
+}
+
+// redactArgs masks the value of KEY=VALUE arguments whose key looks like a credential, as in
+// agent env generate --set OPENAI_API_KEY=..., and the argument after such a flag given
+// without "=", as in --password hunter2.
+func redactArgs(args []string) []string {
+	out := make([]string, len(args))
+	secretNext := false
+	for i, a := range args {
+		switch k, _, ok := strings.Cut(a, "="); {
+		case secretNext:
+			a = backup.RedactedValue
+			secretNext = false
+		case ok && backup.IsSecretEnvKey(strings.TrimLeft(k, "-")):
+			a = k + "=" + backup.RedactedValue
+		case strings.HasPrefix(a, "-") && backup.IsSecretEnvKey(strings.TrimLeft(a, "-")):
+			secretNext = true
+		}
+		out[i] = a
+	}
+	return out
+}
+
+func envKeys(environ []string) []string {
+	keys := make([]string, 0, len(environ))
+	for _, kv := range environ {
+		if k, _, _ := strings.Cut(kv, "="); k != "" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
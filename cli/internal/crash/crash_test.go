@@ -0,0 +1,75 @@
+package crash
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithPanicRecovery(t *testing.T) {
+	root := t.TempDir()
+	Root = func() (string, error) { return root, nil }
+	Version = "1.2.3"
+	now = func() time.Time { return time.Date(2026, 5, 4, 10, 30, 0, 0, time.Local) }
+	t.Cleanup(func() { Root, Version, now = os.Getwd, "unknown", time.Now })
+	t.Setenv("OPENAI_API_KEY", "sk-secret-value")
+
+	want := errors.New("plain failure")
+	if err := WithPanicRecovery(func() error { return want }); err != want {
+		t.Fatalf("err = %v, want %v", err, want)
+	}
+
+	for i := 0; i < 2; i++ {
+		err := WithPanicRecovery(func() error { panic("boom") })
+		if err == nil || !strings.Contains(err.Error(), "agent crashed: boom") {
+			t.Fatalf("err = %v", err)
+		}
+	}
+
+	reports, err := List(root)
+	if err != nil || len(reports) != 2 {
+		t.Fatalf("List = %+v, %v", reports, err)
+	}
+	if reports[0].Summary != "boom" || !reports[0].Time.Equal(now()) {
+		t.Fatalf("report = %+v", reports[0])
+	}
+	names := reports[0].Name + " " + reports[1].Name
+	if !strings.Contains(names, "crash-20260504-103000.txt") || !strings.Contains(names, "crash-20260504-103000-1.txt") {
+		t.Fatalf("names = %s", names)
+	}
+
+	path, err := Resolve(root, "crash-20260504-103000")
+	if err != nil || path != filepath.Join(root, Dir, "crash-20260504-103000.txt") {
+		t.Fatalf("Resolve = %q, %v", path, err)
+	}
+	data, _ := os.ReadFile(path)
+	text := string(data)
+	for _, s := range []string{"panic: boom", "version: 1.2.3", "OPENAI_API_KEY", "crash_test.go"} {
+		if !strings.Contains(text, s) {
+			t.Errorf("report lacks %q:\n%s", s, text)
+		}
+	}
+	if strings.Contains(text, "sk-secret-value") {
+		t.Error("report contains an environment value")
+	}
+	if _, err := Resolve(root, "crash-19990101-000000"); err == nil {
+		t.Error("Resolve found a missing report")
+	}
+}
+
+func TestRedactArgs(t *testing.T) {
+	got := strings.Join(redactArgs([]string{"agent", "env", "generate", "--set", "OPENAI_API_KEY=sk-x", "--set", "ASTERISK_HOST=pbx", "--password=hunter2"}), " ")
+	want := "agent env generate --set OPENAI_API_KEY=<redacted> --set ASTERISK_HOST=pbx --password=<redacted>"
+	if got != want {
+		t.Fatalf("got %q\nwant %q", got, want)
+	}
+
+	got = strings.Join(redactArgs([]string{"agent", "users", "add", "--password", "hunter2", "--username", "bob"}), " ")
+	want = "agent users add --password <redacted> --username bob"
+	if got != want {
+		t.Fatalf("got %q\nwant %q", got, want)
+	}
+}
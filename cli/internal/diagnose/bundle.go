@@ -0,0 +1,195 @@
+// Package diagnose builds the anonymized support bundle written by agent diagnose and posts
+// it to a support endpoint.
+package diagnose
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/check"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/maputil"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/status"
+)
+
+// DefaultLogLines is how many trailing log lines are collected per Compose service.
+const DefaultLogLines = 100
+
+// BundleIndexName is the file at the root of a bundle listing its contents and any
+// collection errors.
+const BundleIndexName = "bundle.json"
+
+// fallbackServices are logged when docker compose ps returns nothing usable.
+var fallbackServices = []string{"ai_engine", "admin_ui", "local_ai_server"}
+
+// BundleOptions controls what BuildDiagnosticBundle collects.
+type BundleOptions struct {
+	// RepoRoot is the install directory: the Compose project and the home of config/.
+	RepoRoot string
+	// LogLines is the number of log lines per service (DefaultLogLines when zero).
+	LogLines int
+	// Timeout bounds the diagnostics run (check.DefaultTimeout when zero).
+	Timeout time.Duration
+
+	// run and command replace the check run and docker calls in tests.
+	run     func(ctx context.Context) (*check.Report, error)
+	command func(ctx context.Context, dir, name string, args ...string) ([]byte, error)
+}
+
+// BundleIndex is written to BundleIndexName.
+type BundleIndex struct {
+	CreatedAt time.Time `json:"created_at"`
+	Version   string    `json:"cli_version,omitempty"`
+	Files     []string  `json:"files"`
+	// Errors records what could not be collected; the bundle is still useful without it.
+	Errors []string `json:"errors,omitempty"`
+}
+
+// BuildDiagnosticBundle runs diagnostics and returns a tar.gz holding the check report
+// (report.json), docker compose ps --format json (compose-ps.json), the last LogLines lines
+// of each service's logs (logs/<service>.log) and config/ai-agent.yaml plus
+// config/ai-agent.local.yaml with secrets redacted. Collection is best-effort: a missing
+// file or failing docker call is recorded in bundle.json instead of aborting. Logs are
+// passed through RedactText as well.
+func BuildDiagnosticBundle(runner *check.Runner, opts BundleOptions) (io.Reader, error) {
+	if opts.LogLines <= 0 {
+		opts.LogLines = DefaultLogLines
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = check.DefaultTimeout
+	}
+	run := opts.run
+	if run == nil {
+		run = func(ctx context.Context) (*check.Report, error) {
+			return runner.RunWithTimeout(ctx, opts.Timeout)
+		}
+	}
+	command := opts.command
+	if command == nil {
+		command = runCommand
+	}
+	ctx := context.Background()
+
+	files := map[string][]byte{}
+	index := BundleIndex{CreatedAt: time.Now().UTC().Truncate(time.Second)}
+	fail := func(what string, err error) {
+		index.Errors = append(index.Errors, fmt.Sprintf("%s: %v", what, err))
+	}
+
+	report, err := run(ctx)
+	if err != nil {
+		fail("agent check", err)
+	}
+	if report != nil {
+		index.Version = report.Version
+		var buf bytes.Buffer
+		if err := report.OutputJSON(&buf); err != nil {
+			fail("agent check", err)
+		} else {
+			files["report.json"] = buf.Bytes()
+		}
+	}
+
+	services := fallbackServices
+	ps, err := command(ctx, opts.RepoRoot, "docker", "compose", "ps", "--all", "--format", "json")
+	if err != nil {
+		fail("docker compose ps", err)
+	} else {
+		files["compose-ps.json"] = ps
+		if names := composeServices(ps); len(names) > 0 {
+			services = names
+		}
+	}
+	for _, svc := range services {
+		out, err := command(ctx, opts.RepoRoot, "docker", "compose", "logs", "--no-color", "--tail", fmt.Sprint(opts.LogLines), svc)
+		if err != nil {
+			fail("logs "+svc, err)
+			continue
+		}
+		files["logs/"+svc+".log"] = RedactText(out)
+	}
+
+	for _, rel := range []string{"config/ai-agent.yaml", "config/ai-agent.local.yaml"} {
+		data, err := os.ReadFile(filepath.Join(opts.RepoRoot, filepath.FromSlash(rel)))
+		if err != nil {
+			if !os.IsNotExist(err) || rel == "config/ai-agent.yaml" {
+				fail(rel, err)
+			}
+			continue
+		}
+		files[rel] = RedactYAML(data)
+	}
+
+	index.Files = maputil.SortedKeys(files)
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	files[BundleIndexName] = append(data, '\n')
+
+	var buf bytes.Buffer
+	if err := writeTarGz(&buf, files, index.CreatedAt); err != nil {
+		return nil, fmt.Errorf("failed to write bundle: %w", err)
+	}
+	return &buf, nil
+}
+
+// composeServices extracts the sorted service names from docker compose ps --format json.
+func composeServices(out []byte) []string {
+	services, _ := status.ParseComposePS(out)
+	seen := map[string]bool{}
+	var names []string
+	for _, s := range services {
+		if s.Name != "" && !seen[s.Name] {
+			seen[s.Name] = true
+			names = append(names, s.Name)
+		}
+	}
+	return names
+}
+
+func writeTarGz(w io.Writer, files map[string][]byte, modTime time.Time) error {
+	names := maputil.SortedKeys(files)
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	for _, name := range names {
+		data := files[name]
+		hdr := &tar.Header{Name: name, Mode: 0o600, Size: int64(len(data)), ModTime: modTime, Typeflag: tar.TypeReg}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := tw.Write(data); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// runCommand runs name in dir and returns stdout; stderr is folded into the error.
+func runCommand(ctx context.Context, dir, name string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%w: %s", err, msg)
+		}
+		return nil, err
+	}
+	return out, nil
+}
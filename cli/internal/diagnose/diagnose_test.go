@@ -0,0 +1,211 @@
+package diagnose
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/check"
+)
+
+func TestRedactYAML(t *testing.T) {
+	in := `# ARI credentials
+asterisk:
+  host: 127.0.0.1
+  password: hunter2 # keep this comment
+  username: asterisk
+providers:
+  openai:
+    api_key: sk-live-123
+    model: gpt-4o
+  deepgram:
+    secrets:
+      - a
+      - b
+    tokens_per_minute: 60
+empty_token: ""
+`
+	out := string(RedactYAML([]byte(in)))
+	for _, leaked := range []string{"hunter2", "sk-live-123", "- a", "- b", ": 60"} {
+		if strings.Contains(out, leaked) {
+			t.Fatalf("secret %q not redacted:\n%s", leaked, out)
+		}
+	}
+	for _, kept := range []string{"# ARI credentials", "# keep this comment", "host: 127.0.0.1", "username: asterisk", "model: gpt-4o", `empty_token: ""`} {
+		if !strings.Contains(out, kept) {
+			t.Fatalf("expected %q to survive:\n%s", kept, out)
+		}
+	}
+}
+
+func TestRedactYAMLFallsBackForInvalidYAML(t *testing.T) {
+	out := string(RedactYAML([]byte("asterisk:\n  password: hunter2\n bad: [\n")))
+	if strings.Contains(out, "hunter2") {
+		t.Fatalf("secret leaked from invalid YAML:\n%s", out)
+	}
+}
+
+func TestRedactText(t *testing.T) {
+	in := `connecting ASTERISK_ARI_PASSWORD=hunter2 host=pbx
+openai request {"api_key": "sk-1", "model": "x"} Authorization token: abc123`
+	out := string(RedactText([]byte(in)))
+	for _, leaked := range []string{"hunter2", "sk-1", "abc123"} {
+		if strings.Contains(out, leaked) {
+			t.Fatalf("%q leaked:\n%s", leaked, out)
+		}
+	}
+	if !strings.Contains(out, "host=pbx") || !strings.Contains(out, `"model": "x"`) {
+		t.Fatalf("non-secret values changed:\n%s", out)
+	}
+}
+
+func TestComposeServices(t *testing.T) {
+	array := `[{"Service":"ai_engine","State":"running"},{"Service":"admin_ui"}]`
+	lines := "{\"Service\":\"ai_engine\"}\n{\"Service\":\"local_ai_server\"}\n"
+	if got := composeServices([]byte(array)); strings.Join(got, ",") != "admin_ui,ai_engine" {
+		t.Fatalf("array form: %v", got)
+	}
+	if got := composeServices([]byte(lines)); strings.Join(got, ",") != "ai_engine,local_ai_server" {
+		t.Fatalf("line form: %v", got)
+	}
+}
+
+func TestBuildDiagnosticBundle(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "config"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "config", "ai-agent.yaml"), []byte("asterisk:\n  password: hunter2\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var logTails []string
+	opts := BundleOptions{
+		RepoRoot: root,
+		run: func(ctx context.Context) (*check.Report, error) {
+			return &check.Report{Version: "v9", Items: []check.Item{{Name: "Docker CLI", Status: check.StatusPass}}}, nil
+		},
+		command: func(ctx context.Context, dir, name string, args ...string) ([]byte, error) {
+			if dir != root {
+				t.Fatalf("command ran in %s, want %s", dir, root)
+			}
+			switch args[1] {
+			case "ps":
+				return []byte(`{"Service":"ai_engine"}` + "\n" + `{"Service":"admin_ui"}`), nil
+			case "logs":
+				svc := args[len(args)-1]
+				logTails = append(logTails, args[len(args)-2])
+				if svc == "admin_ui" {
+					return nil, errors.New("no such service")
+				}
+				return []byte("started with OPENAI_API_KEY=sk-abc\n"), nil
+			}
+			return nil, errors.New("unexpected command")
+		},
+	}
+	r, err := BuildDiagnosticBundle(nil, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := readBundle(t, r)
+
+	for _, name := range []string{BundleIndexName, "report.json", "compose-ps.json", "logs/ai_engine.log", "config/ai-agent.yaml"} {
+		if _, ok := files[name]; !ok {
+			t.Fatalf("bundle missing %s (have %v)", name, keys(files))
+		}
+	}
+	if strings.Contains(files["config/ai-agent.yaml"], "hunter2") || strings.Contains(files["logs/ai_engine.log"], "sk-abc") {
+		t.Fatalf("secret leaked into bundle: %v", files)
+	}
+	if strings.Join(logTails, ",") != "100,100" {
+		t.Fatalf("log tails = %v, want DefaultLogLines per service", logTails)
+	}
+
+	var index BundleIndex
+	if err := json.Unmarshal([]byte(files[BundleIndexName]), &index); err != nil {
+		t.Fatal(err)
+	}
+	if index.Version != "v9" || len(index.Errors) != 1 || !strings.Contains(index.Errors[0], "logs admin_ui") {
+		t.Fatalf("unexpected index: %+v", index)
+	}
+}
+
+func TestUploadReturnsTicketID(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f, hdr, err := r.FormFile(UploadFieldName)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		data, _ := io.ReadAll(f)
+		if hdr.Filename != "diag.tar.gz" || string(data) != "payload" {
+			http.Error(w, "bad upload", http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(`{"ticket_id":"SUP-42"}`))
+	}))
+	defer srv.Close()
+
+	ticket, err := Upload(context.Background(), srv.Client(), srv.URL, "diag.tar.gz", strings.NewReader("payload"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ticket != "SUP-42" {
+		t.Fatalf("ticket = %q", ticket)
+	}
+}
+
+func TestUploadErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "quota exceeded", http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	if _, err := Upload(context.Background(), srv.Client(), srv.URL, "b.tar.gz", strings.NewReader("x")); err == nil || !strings.Contains(err.Error(), "quota exceeded") {
+		t.Fatalf("err = %v, want rejection with server message", err)
+	}
+	if _, err := Upload(context.Background(), nil, "ftp://example.com", "b.tar.gz", strings.NewReader("x")); err == nil {
+		t.Fatal("expected invalid URL error")
+	}
+}
+
+func readBundle(t *testing.T, r io.Reader) map[string]string {
+	t.Helper()
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gz)
+	files := map[string]string{}
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return files
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[hdr.Name] = string(data)
+	}
+}
+
+func keys(m map[string]string) []string {
+	var out []string
+	for k := range m {
+		out = append(out, k)
+	}
+	return out
+}
@@ -0,0 +1,68 @@
+package diagnose
+
+import (
+	"bytes"
+	"regexp"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/backup"
+	"gopkg.in/yaml.v3"
+)
+
+// RedactedValue replaces every secret value in a diagnostic bundle.
+const RedactedValue = backup.RedactedValue
+
+// secretAssignment matches "name=value" and "name: value" pairs whose name is a secret key,
+// as they appear in logs and in YAML that failed to parse.
+var secretAssignment = regexp.MustCompile(`(?i)([\w.-]*(?:PASSWORD|SECRET|KEY|TOKEN)[\w.-]*["']?)(\s*[=:]\s*)("[^"]*"|'[^']*'|[^\s,;&]+)`)
+
+// RedactText replaces the value of every secret "name=value" / "name: value" pair in data.
+func RedactText(data []byte) []byte {
+	return secretAssignment.ReplaceAll(data, []byte("${1}${2}"+RedactedValue))
+}
+
+// RedactYAML replaces every scalar under a mapping key that backup.IsSecretEnvKey matches
+// (PASSWORD, SECRET, KEY or TOKEN in the name), including whole nested mappings and lists
+// under such a key. Comments and key order are kept. Input that does not parse as YAML falls
+// back to RedactText so a broken file is still scrubbed.
+func RedactYAML(data []byte) []byte {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil || len(doc.Content) == 0 {
+		return RedactText(data)
+	}
+	redactNode(&doc, false)
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return RedactText(data)
+	}
+	if err := enc.Close(); err != nil {
+		return RedactText(data)
+	}
+	return buf.Bytes()
+}
+
+func redactNode(n *yaml.Node, secret bool) {
+	switch n.Kind {
+	case yaml.ScalarNode:
+		if secret && n.Value != "" && n.Tag != "!!null" {
+			n.Value = RedactedValue
+			n.Tag = "!!str"
+			n.Style = 0
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			redactNode(n.Content[i+1], secret || backup.IsSecretEnvKey(n.Content[i].Value))
+		}
+	case yaml.AliasNode:
+		// The alias prints as *name, so the secret would leak at its anchor instead.
+		if secret && n.Alias != nil {
+			redactNode(n.Alias, true)
+		}
+	default:
+		for _, c := range n.Content {
+			redactNode(c, secret)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package diagnose
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultUploadTimeout bounds a bundle upload.
+const DefaultUploadTimeout = 60 * time.Second
+
+// UploadFieldName is the multipart form field carrying the bundle.
+const UploadFieldName = "bundle"
+
+// Upload POSTs bundle as multipart/form-data (field UploadFieldName, file name filename) to
+// endpoint and returns the ticket ID from the response: a JSON object with ticket_id,
+// ticket or id, or otherwise a plain-text body.
+func Upload(ctx context.Context, client *http.Client, endpoint, filename string, bundle io.Reader) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return "", fmt.Errorf("invalid upload URL %q (must be http:// or https://)", endpoint)
+	}
+	if client == nil {
+		client = &http.Client{Timeout: DefaultUploadTimeout}
+	}
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	part, err := mw.CreateFormFile(UploadFieldName, filename)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(part, bundle); err != nil {
+		return "", err
+	}
+	if err := mw.Close(); err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, &body)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	req.Header.Set("Accept", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("upload failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if err != nil {
+		return "", fmt.Errorf("failed to read upload response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return "", fmt.Errorf("upload rejected: %s: %s", resp.Status, firstLine(raw))
+	}
+	ticket := ticketID(raw)
+	if ticket == "" {
+		return "", fmt.Errorf("upload succeeded but the response has no ticket ID: %s", firstLine(raw))
+	}
+	return ticket, nil
+}
+
+// ticketID reads {"ticket_id": ...}, {"ticket": ...} or {"id": ...}; any other body is used
+// verbatim when it is a single short line.
+func ticketID(raw []byte) string {
+	var obj map[string]any
+	if json.Unmarshal(raw, &obj) == nil {
+		for _, key := range []string{"ticket_id", "ticket", "id"} {
+			switch v := obj[key].(type) {
+			case string:
+				return strings.TrimSpace(v)
+			case float64:
+				return fmt.Sprintf("%.0f", v)
+			}
+		}
+		return ""
+	}
+	text := strings.TrimSpace(string(raw))
+	if text == "" || len(text) > 128 || strings.ContainsAny(text, "\n<") {
+		return ""
+	}
+	return text
+}
+
+func firstLine(raw []byte) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(string(raw)), "\n")
+	if len(line) > 200 {
+		line = line[:200] + "..."
+	}
+	return line
+}
@@ -0,0 +1,62 @@
+// Package diff renders backup.DiffBackupDirs results as a patch for `patch -p1`.
+package diff
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/backup"
+)
+
+// FileDiff is one changed file, as returned by backup.DiffBackupDirs.
+type FileDiff = backup.FileDiff
+
+// DiffToPatch writes entries to w as a unified diff that `patch -p1` applies from the repo
+// root. Each file gets "--- a/<path>" and "+++ b/<path>" headers, with /dev/null on the missing
+// side of an added or removed file so patch creates or deletes it. Files without a textual
+// diff (binary files, added empty files, or no diff tool on the host) are written as "#"
+// comment lines, which patch skips.
+func DiffToPatch(entries []FileDiff, w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	for _, d := range entries {
+		if d.Binary {
+			fmt.Fprintf(bw, "# Binary file %s %s (%d -> %d bytes); it cannot be patched, copy it instead\n", d.Path, d.Status, d.SizeA, d.SizeB)
+			continue
+		}
+		hunks := hunksOf(d.Unified)
+		if hunks == "" {
+			fmt.Fprintf(bw, "# %s %s: no textual diff available, copy it instead\n", d.Path, d.Status)
+			continue
+		}
+		oldName, newName := "a/"+d.Path, "b/"+d.Path
+		switch d.Status {
+		case backup.DiffAdded:
+			oldName = "/dev/null"
+		case backup.DiffRemoved:
+			newName = "/dev/null"
+		}
+		fmt.Fprintf(bw, "--- %s\n+++ %s\n%s", oldName, newName, hunks)
+	}
+	return bw.Flush()
+}
+
+// hunksOf returns unified from its first "@@" line on, dropping the headers diff wrote.
+func hunksOf(unified string) string {
+	if strings.HasPrefix(unified, "@@") {
+		return withNewline(unified)
+	}
+	i := strings.Index(unified, "\n@@")
+	if i < 0 {
+		return ""
+	}
+	return withNewline(unified[i+1:])
+}
+
+func withNewline(s string) string {
+	if strings.HasSuffix(s, "\n") {
+		return s
+	}
+	return s + "\n"
+}
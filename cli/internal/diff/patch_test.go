@@ -0,0 +1,121 @@
+package diff
+
+import (
+	"bytes"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/backup"
+)
+
+func writeTree(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for rel, data := range files {
+		path := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// readTree maps each file under dir to its content.
+func readTree(t *testing.T, dir string) map[string]string {
+	t.Helper()
+	files := map[string]string{}
+	filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || !entry.Type().IsRegular() {
+			return err
+		}
+		rel, _ := filepath.Rel(dir, path)
+		data, _ := os.ReadFile(path)
+		files[filepath.ToSlash(rel)] = string(data)
+		return nil
+	})
+	return files
+}
+
+// apply runs `patch -p1` in a copy of dir and returns the resulting tree.
+func apply(t *testing.T, patch []byte, dir string) map[string]string {
+	t.Helper()
+	work := writeTree(t, readTree(t, dir))
+	cmd := exec.Command("patch", "-p1", "--batch", "--forward", "--no-backup-if-mismatch")
+	cmd.Dir = work
+	cmd.Stdin = bytes.NewReader(patch)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("patch failed: %v\n%s\npatch:\n%s", err, out, patch)
+	}
+	return readTree(t, work)
+}
+
+func TestDiffToPatchRoundTrip(t *testing.T) {
+	for _, tool := range []string{"diff", "patch"} {
+		if _, err := exec.LookPath(tool); err != nil {
+			t.Skipf("%s not installed", tool)
+		}
+	}
+	a := writeTree(t, map[string]string{
+		".env":                        "ASTERISK_HOST=pbx\nLOG_LEVEL=info\n",
+		"config/ai-agent.local.yaml":  "llm:\n  model: a\n",
+		"config/contexts/old.yaml":    "name: old\n",
+		"config/contexts/no-eol.yaml": "name: x\nvoice: a",
+		"config/users.json":           "{}\n",
+	})
+	b := writeTree(t, map[string]string{
+		".env":                        "ASTERISK_HOST=pbx2\nLOG_LEVEL=info\nTZ=UTC\n",
+		"config/ai-agent.local.yaml":  "llm:\n  model: a\n",
+		"config/contexts/new.yaml":    "name: new\nlanguage: en-US\n",
+		"config/contexts/no-eol.yaml": "name: x\nvoice: b",
+		"config/users.json":           "{}\n",
+	})
+
+	for _, dirs := range [][2]string{{a, b}, {b, a}} { // forward, then --reverse
+		from, to := dirs[0], dirs[1]
+		diffs, err := backup.DiffBackupDirs(from, to)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var patch bytes.Buffer
+		if err := DiffToPatch(diffs, &patch); err != nil {
+			t.Fatal(err)
+		}
+		got, want := apply(t, patch.Bytes(), from), readTree(t, to)
+		if len(got) != len(want) {
+			t.Fatalf("files after patch = %v, want %v\npatch:\n%s", got, want, patch.String())
+		}
+		for rel, data := range want {
+			if got[rel] != data {
+				t.Errorf("%s = %q, want %q", rel, got[rel], data)
+			}
+		}
+	}
+}
+
+func TestDiffToPatchHeadersAndBinary(t *testing.T) {
+	entries := []FileDiff{
+		{Path: "config/contexts/new.yaml", Status: backup.DiffAdded, Unified: "--- a/config/contexts/new.yaml\n+++ b/config/contexts/new.yaml\n@@ -0,0 +1 @@\n+name: new\n"},
+		{Path: ".env", Status: backup.DiffModified, Unified: "--- a/.env\n+++ b/.env\n@@ -1 +1 @@\n-A=1\n+A=2"},
+		{Path: "data/model.bin", Status: backup.DiffModified, Binary: true, SizeA: 3, SizeB: 4},
+	}
+	var out bytes.Buffer
+	if err := DiffToPatch(entries, &out); err != nil {
+		t.Fatal(err)
+	}
+	want := "--- /dev/null\n+++ b/config/contexts/new.yaml\n@@ -0,0 +1 @@\n+name: new\n" +
+		"--- a/.env\n+++ b/.env\n@@ -1 +1 @@\n-A=1\n+A=2\n" +
+		"# Binary file data/model.bin modified (3 -> 4 bytes); it cannot be patched, copy it instead\n"
+	if out.String() != want {
+		t.Fatalf("patch =\n%s\nwant\n%s", out.String(), want)
+	}
+	if strings.Count(out.String(), "---") != 2 {
+		t.Fatal("binary entry must not get headers")
+	}
+}
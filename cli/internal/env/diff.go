@@ -0,0 +1,177 @@
+package env
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/backup"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/check"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/health"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/maputil"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/secrets"
+)
+
+// DiffItemName is the report name of the .env / .env.example comparison.
+const DiffItemName = "Env Example"
+
+// ExampleFile is the example env file shipped in the repo root.
+const ExampleFile = ".env.example"
+
+// PlaceholderPattern matches values left at a template placeholder, such as CHANGE_ME,
+// your_api_key_here or <token>.
+var PlaceholderPattern = regexp.MustCompile(`(?i)^(change[_-]?me.*|.*[_-]change[_-]?me|replace[_-]?me.*|your[_-].*|<.*>|x{4,}|todo|placeholder)$`)
+
+// EnvDiff compares an env file A (usually .env) with B (usually .env.example). Keys are
+// sorted; commented-out keys do not count as present.
+type EnvDiff struct {
+	// MissingInA are the keys B sets that A does not: new keys the operator has not added.
+	MissingInA []string
+	// MissingInB are the keys only A sets.
+	MissingInB []string
+	// Mismatched are the keys set in both whose value in A is still a placeholder (see
+	// PlaceholderPattern).
+	Mismatched []string
+	// A and B hold the normalized values (see check.EnvValue) of each file.
+	A, B map[string]string
+}
+
+// DiffEnvFiles compares the env files a and b. a may be an encrypted .env.age or be found as
+// one (see secrets.LoadEnv).
+func DiffEnvFiles(a, b string) (EnvDiff, error) {
+	av, _, err := secrets.LoadEnv(a)
+	if err != nil {
+		return EnvDiff{}, fmt.Errorf("failed to read %s: %w", a, err)
+	}
+	bv, err := health.LoadEnvFile(b)
+	if err != nil {
+		return EnvDiff{}, fmt.Errorf("failed to read %s: %w", b, err)
+	}
+	return diffEnv(av, bv), nil
+}
+
+func diffEnv(a, b map[string]string) EnvDiff {
+	d := EnvDiff{A: normalizeEnv(a), B: normalizeEnv(b)}
+	for _, key := range maputil.SortedKeys(d.B) {
+		v, ok := d.A[key]
+		switch {
+		case !ok:
+			d.MissingInA = append(d.MissingInA, key)
+		case PlaceholderPattern.MatchString(v):
+			d.Mismatched = append(d.Mismatched, key)
+		}
+	}
+	for _, key := range maputil.SortedKeys(d.A) {
+		if _, ok := d.B[key]; !ok {
+			d.MissingInB = append(d.MissingInB, key)
+		}
+	}
+	return d
+}
+
+func normalizeEnv(m map[string]string) map[string]string {
+	out := make(map[string]string, len(m))
+	for k, v := range m {
+		out[k] = check.EnvValue(v)
+	}
+	return out
+}
+
+// MaskValue returns value for display, redacted when key holds a credential.
+func MaskValue(key, value string) string {
+	if value == "" || !backup.IsSecretEnvKey(key) {
+		return value
+	}
+	return backup.RedactedValue
+}
+
+// RegisterDiffCheck adds the DiffItemName check to every check.Runner. It compares the run's
+// .env with the .env.example next to it, or with the template built into the binary when
+// there is none.
+func RegisterDiffCheck() {
+	check.Register(DiffItemName, func(ctx context.Context) check.Item {
+		return diffCheck(check.EnvPath(ctx))
+	})
+}
+
+// CheckEnvDiff reports the keys .env (envPath) lacks compared with example, and the keys it
+// leaves at a placeholder value, as a warning. An empty example uses the built-in template.
+func CheckEnvDiff(envPath, example string) check.Item {
+	item := check.Item{Name: DiffItemName}
+	if envPath == "" {
+		item.Status = check.StatusSkip
+		item.Message = ".env not found"
+		return item
+	}
+	var (
+		d   EnvDiff
+		err error
+	)
+	if example != "" {
+		d, err = DiffEnvFiles(envPath, example)
+	} else {
+		example = "the built-in template"
+		var av, bv map[string]string
+		if av, _, err = secrets.LoadEnv(envPath); err == nil {
+			bv, err = health.ParseEnv(bytes.NewReader(defaultTemplate))
+		}
+		d = diffEnv(av, bv)
+	}
+	if err != nil {
+		item.Status = check.StatusSkip
+		item.Message = "could not compare with " + filepath.Base(example)
+		item.Details = err.Error()
+		return item
+	}
+
+	var details []string
+	for _, key := range d.MissingInA {
+		details = append(details, "missing: "+key)
+	}
+	for _, key := range d.Mismatched {
+		details = append(details, "placeholder: "+key)
+	}
+	item.Details = strings.Join(details, "\n")
+	if len(d.MissingInA) == 0 && len(d.Mismatched) == 0 {
+		item.Status = check.StatusPass
+		item.Message = "every key in " + filepath.Base(example) + " is set"
+		return item
+	}
+	item.Status = check.StatusWarn
+	var parts []string
+	if n := len(d.MissingInA); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d key(s) from %s missing", n, filepath.Base(example)))
+	}
+	if n := len(d.Mismatched); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d placeholder value(s)", n))
+	}
+	item.Message = strings.Join(parts, ", ")
+	item.Remediation = "Review with agent env diff and add the new keys to .env (agent env generate --merge appends them)"
+	return item
+}
+
+func diffCheck(envPath string) check.Item {
+	if envPath != "" && !fileExists(envPath) && !fileExists(envPath+".age") {
+		envPath = ""
+	}
+	example := ""
+	if envPath != "" {
+		candidate := filepath.Join(filepath.Dir(envPath), ExampleFile)
+		if _, err := os.Stat(candidate); err == nil {
+			example = candidate
+		} else if !errors.Is(err, os.ErrNotExist) {
+			example = candidate // let DiffEnvFiles report it
+		}
+	}
+	return CheckEnvDiff(envPath, example)
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,78 @@
+package env
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/check"
+)
+
+func TestDiffEnvFiles(t *testing.T) {
+	dir := t.TempDir()
+	current := filepath.Join(dir, ".env")
+	example := filepath.Join(dir, ExampleFile)
+	os.WriteFile(example, []byte(`# ARI
+ASTERISK_HOST=127.0.0.1
+ASTERISK_ARI_PASSWORD=your_password_here
+OPENAI_API_KEY=
+# DEEPGRAM_API_KEY=
+LOG_LEVEL=info
+NEW_FEATURE_FLAG=false
+`), 0o644)
+	os.WriteFile(current, []byte(`ASTERISK_HOST=pbx.internal
+ASTERISK_ARI_PASSWORD="CHANGE_ME"
+OPENAI_API_KEY=sk-live   # prod key
+LOCAL_SETTING=1
+`), 0o644)
+
+	d, err := DiffEnvFiles(current, example)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"LOG_LEVEL", "NEW_FEATURE_FLAG"}; !slices.Equal(d.MissingInA, want) {
+		t.Errorf("MissingInA = %v, want %v", d.MissingInA, want)
+	}
+	if want := []string{"LOCAL_SETTING"}; !slices.Equal(d.MissingInB, want) {
+		t.Errorf("MissingInB = %v, want %v", d.MissingInB, want)
+	}
+	if want := []string{"ASTERISK_ARI_PASSWORD"}; !slices.Equal(d.Mismatched, want) {
+		t.Errorf("Mismatched = %v, want %v", d.Mismatched, want)
+	}
+	if d.A["OPENAI_API_KEY"] != "sk-live" {
+		t.Errorf("value not normalized: %q", d.A["OPENAI_API_KEY"])
+	}
+
+	if _, err := DiffEnvFiles(filepath.Join(dir, "missing.env"), example); err == nil {
+		t.Fatal("expected an error for a missing .env")
+	}
+}
+
+func TestMaskValue(t *testing.T) {
+	if got := MaskValue("OPENAI_API_KEY", "sk-live"); got == "sk-live" {
+		t.Errorf("secret not masked: %q", got)
+	}
+	if got := MaskValue("LOG_LEVEL", "info"); got != "info" {
+		t.Errorf("MaskValue(LOG_LEVEL) = %q", got)
+	}
+}
+
+func TestCheckEnvDiff(t *testing.T) {
+	dir := t.TempDir()
+	current := filepath.Join(dir, ".env")
+	os.WriteFile(current, []byte("ASTERISK_HOST=127.0.0.1\n"), 0o644)
+	os.WriteFile(filepath.Join(dir, ExampleFile), []byte("ASTERISK_HOST=\nLOG_LEVEL=info\n"), 0o644)
+
+	item := diffCheck(current)
+	if item.Status != check.StatusWarn || item.Details != "missing: LOG_LEVEL" {
+		t.Fatalf("item = %+v", item)
+	}
+	os.WriteFile(current, []byte("ASTERISK_HOST=127.0.0.1\nLOG_LEVEL=debug\n"), 0o644)
+	if item := diffCheck(current); item.Status != check.StatusPass {
+		t.Fatalf("item = %+v", item)
+	}
+	if item := diffCheck(filepath.Join(dir, "missing.env")); item.Status != check.StatusSkip {
+		t.Fatalf("no .env: item = %+v", item)
+	}
+}
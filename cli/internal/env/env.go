@@ -0,0 +1,375 @@
+// Package env writes .env files from the template built into the binary.
+package env
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	_ "embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/check"
+)
+
+//go:embed env.template
+var defaultTemplate []byte
+
+// ErrExists is returned by GenerateEnvFile when the output file is already there.
+var ErrExists = errors.New("file already exists")
+
+// Prompts for keys without a value read from PromptIn and write to PromptOut. ReadPassword,
+// when set, reads "#@ secret" keys without echo.
+var (
+	PromptIn     io.Reader = os.Stdin
+	PromptOut    io.Writer = os.Stdout
+	ReadPassword func(prompt string) (string, error)
+)
+
+// now stamps generated files; replaced in tests.
+var now = time.Now
+
+// TemplateKey is one KEY=value entry of an EnvTemplate.
+type TemplateKey struct {
+	Name string
+	// Default is the value in the template ("" when the key is to be prompted for).
+	Default     string
+	Required    bool
+	Secret      bool
+	Generate    bool
+	Description string
+}
+
+// EnvTemplate is a parsed env.template: the .env lines in order, with the entries indexed.
+type EnvTemplate struct {
+	Keys  []TemplateKey
+	lines []templateLine
+}
+
+type templateLine struct {
+	text string
+	key  int // index into Keys, or -1 for comments and blank lines
+}
+
+// DefaultTemplate returns the env.template embedded in the binary.
+func DefaultTemplate() (EnvTemplate, error) {
+	return ParseTemplate(defaultTemplate)
+}
+
+// ParseTemplate parses .env-style text where a "#@ required|secret|generate ..." line sets
+// options for the entry that follows it. Other #@ lines are template comments. Neither kind is
+// copied to the output.
+func ParseTemplate(data []byte) (EnvTemplate, error) {
+	var t EnvTemplate
+	schema := check.DefaultEnvSchema()
+	var pending []string
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; sc.Scan(); n++ {
+		line := sc.Text()
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "#@") {
+			pending = strings.Fields(strings.TrimPrefix(trimmed, "#@"))
+			continue
+		}
+		name, raw, ok := strings.Cut(trimmed, "=")
+		if !ok || strings.HasPrefix(trimmed, "#") {
+			pending = nil
+			t.lines = append(t.lines, templateLine{text: line, key: -1})
+			continue
+		}
+		k := TemplateKey{Name: strings.TrimSpace(name), Default: check.EnvValue(raw)}
+		if strings.HasPrefix(k.Default, "#") {
+			k.Default = "" // "KEY=   # comment" has no value
+		}
+		for _, d := range pending {
+			switch d {
+			case "required":
+				k.Required = true
+			case "secret":
+				k.Secret = true
+			case "generate":
+				k.Generate = true
+			default:
+				return EnvTemplate{}, fmt.Errorf("line %d: unknown directive %q for %s", n, d, k.Name)
+			}
+		}
+		pending = nil
+		if sk, ok := schema.Lookup(k.Name); ok {
+			k.Description = sk.Description
+		}
+		t.lines = append(t.lines, templateLine{text: line, key: len(t.Keys)})
+		t.Keys = append(t.Keys, k)
+	}
+	return t, sc.Err()
+}
+
+// GenerateEnvFile writes a .env to output from template. Each key takes its value from
+// answers, then the template default, then (for "#@ generate" keys) 32 bytes from crypto/rand;
+// keys with none of these are prompted for. Answers for keys the template does not list are
+// appended at the end. The file is written atomically with mode 0600; an existing output
+// returns ErrExists.
+func GenerateEnvFile(template EnvTemplate, answers map[string]string, output string) error {
+	if _, err := os.Stat(output); err == nil {
+		return fmt.Errorf("%s: %w", output, ErrExists)
+	}
+	values, err := resolveValues(template.Keys, answers, newPrompter())
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# Generated by agent env generate on %s\n", now().UTC().Format(time.RFC3339))
+	for _, l := range template.lines {
+		if l.key < 0 {
+			buf.WriteString(l.text + "\n")
+			continue
+		}
+		k := template.Keys[l.key]
+		if v, ok := values[k.Name]; ok {
+			buf.WriteString(k.Name + "=" + quote(v) + "\n")
+		} else {
+			buf.WriteString(l.text + "\n")
+		}
+	}
+	if extra := extraKeys(template, answers, nil); len(extra) > 0 {
+		buf.WriteString("\n# Additional keys\n")
+		for _, name := range extra {
+			buf.WriteString(name + "=" + quote(answers[name]) + "\n")
+		}
+	}
+	return writeAtomic(output, buf.Bytes(), 0o600)
+}
+
+// MergeEnvFile appends the template keys (and answers) that the existing .env at output does
+// not set, resolving their values as GenerateEnvFile does, and returns the names added. Keys
+// already in the file are left alone even when answers has a value for them.
+func MergeEnvFile(template EnvTemplate, answers map[string]string, output string) ([]string, error) {
+	data, err := os.ReadFile(output)
+	if err != nil {
+		return nil, err
+	}
+	existing := setKeys(data)
+	var missing []TemplateKey
+	for _, k := range template.Keys {
+		if !existing[k.Name] {
+			missing = append(missing, k)
+		}
+	}
+	extra := extraKeys(template, answers, existing)
+	if len(missing) == 0 && len(extra) == 0 {
+		return nil, nil
+	}
+	values, err := resolveValues(missing, answers, newPrompter())
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.Write(data)
+	if len(data) > 0 && !bytes.HasSuffix(data, []byte("\n")) {
+		buf.WriteString("\n")
+	}
+	fmt.Fprintf(&buf, "\n# Added by agent env generate --merge on %s\n", now().UTC().Format(time.RFC3339))
+	var added []string
+	for _, k := range missing {
+		if k.Description != "" {
+			buf.WriteString("# " + k.Description + "\n")
+		}
+		v, ok := values[k.Name]
+		if !ok {
+			v = k.Default
+		}
+		buf.WriteString(k.Name + "=" + quote(v) + "\n")
+		added = append(added, k.Name)
+	}
+	for _, name := range extra {
+		buf.WriteString(name + "=" + quote(answers[name]) + "\n")
+		added = append(added, name)
+	}
+
+	mode := os.FileMode(0o600)
+	if fi, err := os.Stat(output); err == nil {
+		mode = fi.Mode().Perm()
+	}
+	return added, writeAtomic(output, buf.Bytes(), mode)
+}
+
+// resolveValues returns the value of each key that does not keep its template line: answered,
+// generated or prompted keys. Keys with a template default and no answer are omitted.
+func resolveValues(keys []TemplateKey, answers map[string]string, p *prompter) (map[string]string, error) {
+	schema := check.DefaultEnvSchema()
+	values := map[string]string{}
+	for _, k := range keys {
+		validate := func(v string) error {
+			if v == "" {
+				if k.Required {
+					return errors.New("a value is required")
+				}
+				return nil
+			}
+			if sk, ok := schema.Lookup(k.Name); ok && sk.Validate != nil {
+				return sk.Validate(v)
+			}
+			return nil
+		}
+		if v, ok := answers[k.Name]; ok {
+			if err := validate(v); err != nil {
+				return nil, fmt.Errorf("%s: %w", k.Name, err)
+			}
+			values[k.Name] = v
+			continue
+		}
+		if k.Default != "" {
+			continue
+		}
+		if k.Generate {
+			v, err := randomHex(32)
+			if err != nil {
+				return nil, err
+			}
+			values[k.Name] = v
+			continue
+		}
+		v, err := p.ask(k, validate)
+		if err != nil {
+			return nil, err
+		}
+		values[k.Name] = v
+	}
+	return values, nil
+}
+
+// extraKeys returns the answered keys that neither the template nor skip has, sorted.
+func extraKeys(t EnvTemplate, answers map[string]string, skip map[string]bool) []string {
+	known := map[string]bool{}
+	for _, k := range t.Keys {
+		known[k.Name] = true
+	}
+	var names []string
+	for name := range answers {
+		if !known[name] && !skip[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// setKeys returns the keys assigned (not commented out) in .env content.
+func setKeys(data []byte) map[string]bool {
+	keys := map[string]bool{}
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+		if name, _, ok := strings.Cut(line, "="); ok {
+			keys[strings.TrimSpace(name)] = true
+		}
+	}
+	return keys
+}
+
+// prompter reads every answer from one buffered reader, so piped input is not lost between
+// prompts.
+type prompter struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+func newPrompter() *prompter {
+	return &prompter{in: bufio.NewReader(PromptIn), out: PromptOut}
+}
+
+func (p *prompter) ask(k TemplateKey, validate func(string) error) (string, error) {
+	label := k.Name
+	if k.Description != "" {
+		label += " (" + k.Description + ")"
+	}
+	if !k.Required {
+		label += " [optional, Enter to skip]"
+	}
+	for {
+		var v string
+		var err error
+		if k.Secret && ReadPassword != nil {
+			v, err = ReadPassword(label + ": ")
+		} else {
+			fmt.Fprintf(p.out, "%s: ", label)
+			v, err = p.line()
+		}
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", k.Name, err)
+		}
+		v = strings.TrimSpace(v)
+		if err := validate(v); err != nil {
+			fmt.Fprintf(p.out, "  ❌ %v\n", err)
+			continue
+		}
+		return v, nil
+	}
+}
+
+// line reads one line; running out of input is an error rather than an endless loop.
+func (p *prompter) line() (string, error) {
+	s, err := p.in.ReadString('\n')
+	if err != nil && s == "" {
+		if errors.Is(err, io.EOF) {
+			return "", errors.New("input ended before a value was given (pass it with --set KEY=VALUE)")
+		}
+		return "", err
+	}
+	return strings.TrimRight(s, "\r\n"), nil
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// quote quotes v where docker compose would otherwise cut it short or interpolate it.
+func quote(v string) string {
+	if v == "" || !strings.ContainsAny(v, " \t#$'\"") {
+		return v
+	}
+	if !strings.Contains(v, "'") {
+		return "'" + v + "'"
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `$$`).Replace(v) + `"`
+}
+
+func writeAtomic(path string, data []byte, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), mode); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+package env
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/check"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/health"
+)
+
+const testTemplate = `#@ template-only comment
+
+# ARI
+#@ required
+ASTERISK_HOST=
+ASTERISK_ARI_PORT=8088
+#@ required secret
+ASTERISK_ARI_PASSWORD=
+#@ secret
+OPENAI_API_KEY=
+#@ generate
+JWT_SECRET=    # openssl rand -hex 32
+LOG_LEVEL=info   # debug|info
+`
+
+// withInput feeds input to the prompts and returns what they printed.
+func withInput(t *testing.T, input string) *strings.Builder {
+	t.Helper()
+	out := &strings.Builder{}
+	oldIn, oldOut, oldPw, oldNow := PromptIn, PromptOut, ReadPassword, now
+	PromptIn, PromptOut, ReadPassword = strings.NewReader(input), out, nil
+	now = func() time.Time { return time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC) }
+	t.Cleanup(func() { PromptIn, PromptOut, ReadPassword, now = oldIn, oldOut, oldPw, oldNow })
+	return out
+}
+
+func parse(t *testing.T, text string) EnvTemplate {
+	t.Helper()
+	tmpl, err := ParseTemplate([]byte(text))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tmpl
+}
+
+func TestParseTemplate(t *testing.T) {
+	tmpl := parse(t, testTemplate)
+	if len(tmpl.Keys) != 6 {
+		t.Fatalf("keys = %+v", tmpl.Keys)
+	}
+	host, pw, jwt, level := tmpl.Keys[0], tmpl.Keys[2], tmpl.Keys[4], tmpl.Keys[5]
+	if !host.Required || host.Secret || host.Description == "" {
+		t.Fatalf("ASTERISK_HOST = %+v", host)
+	}
+	if !pw.Required || !pw.Secret || !jwt.Generate || jwt.Default != "" || level.Default != "info" {
+		t.Fatalf("keys = %+v", tmpl.Keys)
+	}
+	if _, err := ParseTemplate([]byte("#@ optional\nFOO=\n")); err == nil {
+		t.Fatal("want an error for an unknown directive")
+	}
+}
+
+func TestGenerateEnvFile(t *testing.T) {
+	// The empty host is rejected and asked again; the optional API key is skipped.
+	out := withInput(t, "\n10.0.0.5\ns3cret pw\n\n")
+	path := filepath.Join(t.TempDir(), ".env")
+	answers := map[string]string{"ASTERISK_ARI_PORT": "20071", "TZ": "UTC"}
+	if err := GenerateEnvFile(parse(t, testTemplate), answers, path); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "a value is required") {
+		t.Fatalf("prompts:\n%s", out)
+	}
+
+	data, _ := os.ReadFile(path)
+	got := string(data)
+	for _, want := range []string{
+		"# Generated by agent env generate on 2026-05-01T09:00:00Z\n",
+		"# ARI\nASTERISK_HOST=10.0.0.5\nASTERISK_ARI_PORT=20071\nASTERISK_ARI_PASSWORD='s3cret pw'\n",
+		"OPENAI_API_KEY=\n",
+		"LOG_LEVEL=info   # debug|info\n",
+		"# Additional keys\nTZ=UTC\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("missing %q in:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "#@") {
+		t.Fatalf("directives copied:\n%s", got)
+	}
+	envMap, _ := health.ParseEnv(strings.NewReader(got))
+	if jwt := envMap["JWT_SECRET"]; len(jwt) != 64 {
+		t.Fatalf("JWT_SECRET = %q", jwt)
+	}
+	if fi, _ := os.Stat(path); fi.Mode().Perm() != 0o600 {
+		t.Fatalf("mode = %v", fi.Mode().Perm())
+	}
+
+	if err := GenerateEnvFile(parse(t, testTemplate), answers, path); !errors.Is(err, ErrExists) {
+		t.Fatalf("want ErrExists, got %v", err)
+	}
+}
+
+func TestGenerateEnvFileRejectsInvalidAnswer(t *testing.T) {
+	withInput(t, "")
+	answers := map[string]string{"ASTERISK_HOST": "h", "ASTERISK_ARI_PORT": "http", "ASTERISK_ARI_PASSWORD": "p", "OPENAI_API_KEY": ""}
+	err := GenerateEnvFile(parse(t, testTemplate), answers, filepath.Join(t.TempDir(), ".env"))
+	if err == nil || !strings.Contains(err.Error(), "ASTERISK_ARI_PORT") {
+		t.Fatalf("err = %v", err)
+	}
+
+	// Running out of input for a required key fails instead of writing a broken .env.
+	delete(answers, "ASTERISK_ARI_PORT")
+	delete(answers, "ASTERISK_HOST")
+	err = GenerateEnvFile(parse(t, testTemplate), answers, filepath.Join(t.TempDir(), ".env"))
+	if err == nil || !strings.Contains(err.Error(), "--set") {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestMergeEnvFile(t *testing.T) {
+	withInput(t, "sk-test\n")
+	path := filepath.Join(t.TempDir(), ".env")
+	existing := "ASTERISK_HOST=pbx\n# ASTERISK_ARI_PORT=9000\nASTERISK_ARI_PASSWORD=old\nJWT_SECRET=keep"
+	if err := os.WriteFile(path, []byte(existing), 0o640); err != nil {
+		t.Fatal(err)
+	}
+	added, err := MergeEnvFile(parse(t, testTemplate), map[string]string{"ASTERISK_HOST": "ignored", "EXTRA": "a b"}, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(added, ",") != "ASTERISK_ARI_PORT,OPENAI_API_KEY,LOG_LEVEL,EXTRA" {
+		t.Fatalf("added = %v", added)
+	}
+	data, _ := os.ReadFile(path)
+	got := string(data)
+	if !strings.HasPrefix(got, existing+"\n\n# Added by agent env generate --merge on 2026-05-01T09:00:00Z\n") {
+		t.Fatalf("existing content changed:\n%s", got)
+	}
+	for _, want := range []string{"ASTERISK_ARI_PORT=8088\n", "OPENAI_API_KEY=sk-test\n", "LOG_LEVEL=info\n", "EXTRA='a b'\n"} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("missing %q in:\n%s", want, got)
+		}
+	}
+	if fi, _ := os.Stat(path); fi.Mode().Perm() != 0o640 {
+		t.Fatalf("mode = %v", fi.Mode().Perm())
+	}
+
+	// A second merge has nothing to add.
+	if added, err := MergeEnvFile(parse(t, testTemplate), nil, path); err != nil || len(added) != 0 {
+		t.Fatalf("second merge: %v, %v", added, err)
+	}
+}
+
+func TestDefaultTemplatePassesEnvCheck(t *testing.T) {
+	withInput(t, "")
+	PromptOut = io.Discard
+	tmpl, err := DefaultTemplate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	answers := map[string]string{"ASTERISK_HOST": "127.0.0.1", "ASTERISK_ARI_USERNAME": "ai", "ASTERISK_ARI_PASSWORD": "pw"}
+	for _, k := range tmpl.Keys {
+		if k.Default == "" && !k.Required && !k.Generate {
+			answers[k.Name] = ""
+		}
+	}
+	path := filepath.Join(t.TempDir(), ".env")
+	if err := GenerateEnvFile(tmpl, answers, path); err != nil {
+		t.Fatal(err)
+	}
+	envMap, err := health.LoadEnvFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, is := range check.DefaultEnvSchema().Check(envMap) {
+		t.Errorf("%s: %s %s", is.Key, is.Status, is.Message)
+	}
+}
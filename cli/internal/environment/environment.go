@@ -0,0 +1,113 @@
+// Package environment resolves named deployment environments (agent --env production), each
+// with its own suffixed operator config files in one checkout.
+package environment
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	// Default is the environment whose files carry no suffix (.env, config/ai-agent.yaml).
+	Default = "default"
+	// EnvVar selects the environment when --env is not given.
+	EnvVar = "AGENT_ENV"
+)
+
+// ErrInvalidName is returned for environment names that cannot be used as a file suffix.
+var ErrInvalidName = errors.New("invalid environment name")
+
+var validName = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// reserved are suffixes the repo already uses for other files (.env.example, .env.age,
+// ai-agent.local.yaml, <file>.bak.<timestamp>).
+var reserved = map[string]bool{"local": true, "example": true, "age": true, "bak": true}
+
+// Validate reports whether name can be used as an environment name.
+func Validate(name string) error {
+	if name == Default {
+		return nil
+	}
+	if !validName.MatchString(name) || reserved[name] {
+		return fmt.Errorf("%w %q (use lower-case letters, digits, - and _; local, example, age and bak are reserved)", ErrInvalidName, name)
+	}
+	return nil
+}
+
+// Resolve picks the environment: flag if set, then $AGENT_ENV, then Default.
+func Resolve(flag string) (string, error) {
+	name := strings.TrimSpace(flag)
+	if name == "" {
+		name = strings.TrimSpace(os.Getenv(EnvVar))
+	}
+	if name == "" {
+		name = Default
+	}
+	if err := Validate(name); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// Path returns rel (a path relative to the repo root) for environment name: unchanged for
+// Default, otherwise with the name inserted before the extension (config/ai-agent.yaml ->
+// config/ai-agent.production.yaml) or appended to a dotfile (.env -> .env.production).
+func Path(rel, name string) string {
+	if name == "" || name == Default {
+		return rel
+	}
+	dir, base := filepath.Split(rel)
+	ext := path.Ext(base)
+	if ext == "" || ext == base {
+		return dir + base + "." + name
+	}
+	return dir + strings.TrimSuffix(base, ext) + "." + name + ext
+}
+
+// List returns the environments that have files under root: Default when .env or
+// config/ai-agent.local.yaml exists, plus every name found as a suffix of .env.NAME,
+// config/ai-agent.NAME.yaml or config/ai-agent.local.NAME.yaml. Default comes first, the
+// rest are sorted.
+func List(root string) ([]string, error) {
+	found := map[string]bool{}
+	for _, rel := range []string{".env", filepath.Join("config", "ai-agent.local.yaml")} {
+		if _, err := os.Stat(filepath.Join(root, rel)); err == nil {
+			found[Default] = true
+		}
+	}
+	patterns := []struct{ glob, prefix, suffix string }{
+		{".env.*", ".env.", ""},
+		{filepath.Join("config", "ai-agent.*.yaml"), "ai-agent.", ".yaml"},
+		{filepath.Join("config", "ai-agent.local.*.yaml"), "ai-agent.local.", ".yaml"},
+	}
+	for _, p := range patterns {
+		matches, err := filepath.Glob(filepath.Join(root, p.glob))
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range matches {
+			name := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(m), p.prefix), p.suffix)
+			if name != Default && Validate(name) == nil {
+				found[name] = true
+			}
+		}
+	}
+
+	var names []string
+	for name := range found {
+		if name != Default {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	if found[Default] {
+		names = append([]string{Default}, names...)
+	}
+	return names, nil
+}
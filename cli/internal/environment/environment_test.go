@@ -0,0 +1,76 @@
+package environment
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestPath(t *testing.T) {
+	cases := []struct {
+		rel, name, want string
+	}{
+		{".env", Default, ".env"},
+		{".env", "", ".env"},
+		{".env", "production", ".env.production"},
+		{filepath.Join("config", "ai-agent.yaml"), "staging", filepath.Join("config", "ai-agent.staging.yaml")},
+		{filepath.Join("config", "ai-agent.local.yaml"), "staging", filepath.Join("config", "ai-agent.local.staging.yaml")},
+		{filepath.Join("config", "users.json"), "prod-2", filepath.Join("config", "users.prod-2.json")},
+	}
+	for _, c := range cases {
+		if got := Path(c.rel, c.name); got != c.want {
+			t.Errorf("Path(%q, %q) = %q, want %q", c.rel, c.name, got, c.want)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	t.Setenv(EnvVar, "")
+	if got, err := Resolve(""); err != nil || got != Default {
+		t.Errorf("Resolve(\"\") = %q, %v; want %q", got, err, Default)
+	}
+	t.Setenv(EnvVar, "staging")
+	if got, _ := Resolve(""); got != "staging" {
+		t.Errorf("Resolve with %s=staging = %q", EnvVar, got)
+	}
+	if got, _ := Resolve("production"); got != "production" {
+		t.Errorf("flag should win over %s, got %q", EnvVar, got)
+	}
+	for _, bad := range []string{"Prod", "a.b", "../x", "local", "bak"} {
+		if _, err := Resolve(bad); !errors.Is(err, ErrInvalidName) {
+			t.Errorf("Resolve(%q) err = %v, want ErrInvalidName", bad, err)
+		}
+	}
+}
+
+func TestList(t *testing.T) {
+	root := t.TempDir()
+	for _, rel := range []string{
+		".env", ".env.production", ".env.example", ".env.age", ".env.bak.20260101_000000",
+		"config/ai-agent.yaml", "config/ai-agent.local.yaml", "config/ai-agent.staging.yaml",
+		"config/ai-agent.local.qa.yaml", "config/ai-agent.yaml.bak.20260101_000000",
+	} {
+		p := filepath.Join(root, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got, err := List(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{Default, "production", "qa", "staging"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("List = %v, want %v", got, want)
+	}
+
+	empty, err := List(t.TempDir())
+	if err != nil || len(empty) != 0 {
+		t.Errorf("List(empty) = %v, %v", empty, err)
+	}
+}
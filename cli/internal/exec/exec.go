@@ -0,0 +1,108 @@
+// Package exec runs external commands (git, docker) for the CLI with stdout and stderr captured
+// separately, optionally echoing both live to the terminal.
+package exec
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	osexec "os/exec"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// MaxErrorStderr caps how much of a failed command's stderr is quoted in its error.
+const MaxErrorStderr = 2048
+
+// CmdResult is the outcome of RunCmdResult. ExitCode is -1 when the command did not start or
+// was killed by a signal.
+type CmdResult struct {
+	Stdout   string
+	Stderr   string
+	ExitCode int
+	Duration time.Duration
+}
+
+// Streams are where RunCmdResult echoes a command's output while capturing it (agent
+// --verbose-commands), and what it reads as stdin. Nil fields are not connected.
+type Streams struct {
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+type streamsKey struct{}
+
+// WithStreams returns a context whose commands use s.
+func WithStreams(ctx context.Context, s Streams) context.Context {
+	return context.WithValue(ctx, streamsKey{}, s)
+}
+
+func streamsFromContext(ctx context.Context) Streams {
+	s, _ := ctx.Value(streamsKey{}).(Streams)
+	return s
+}
+
+// RunCmdResult runs args[0] with args[1:] and returns its captured output. A failed command
+// returns the result together with an error that quotes the first MaxErrorStderr bytes of
+// stderr (or of stdout, for tools like git that report some failures there).
+func RunCmdResult(ctx context.Context, args ...string) (CmdResult, error) {
+	if len(args) == 0 {
+		return CmdResult{ExitCode: -1}, errors.New("no command given")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	streams := streamsFromContext(ctx)
+
+	cmd := osexec.CommandContext(ctx, args[0], args[1:]...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdin = streams.Stdin
+	cmd.Stdout = tee(&stdout, streams.Stdout)
+	cmd.Stderr = tee(&stderr, streams.Stderr)
+
+	start := time.Now()
+	err := cmd.Run()
+	res := CmdResult{
+		Stdout:   stdout.String(),
+		Stderr:   stderr.String(),
+		ExitCode: -1,
+		Duration: time.Since(start),
+	}
+	if cmd.ProcessState != nil {
+		res.ExitCode = cmd.ProcessState.ExitCode()
+	}
+	if err != nil {
+		detail := strings.TrimSpace(res.Stderr)
+		if detail == "" {
+			detail = strings.TrimSpace(res.Stdout)
+		}
+		if detail != "" {
+			return res, fmt.Errorf("%w: %s", err, truncate(detail, MaxErrorStderr))
+		}
+		return res, err
+	}
+	return res, nil
+}
+
+func tee(buf *bytes.Buffer, w io.Writer) io.Writer {
+	if w == nil {
+		return buf
+	}
+	return io.MultiWriter(buf, w)
+}
+
+// truncate cuts s to at most n bytes without splitting a UTF-8 sequence.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	cut := n
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + " ... (truncated)"
+}
@@ -0,0 +1,61 @@
+package exec
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestRunCmdResultCapturesStreamsSeparately(t *testing.T) {
+	res, err := RunCmdResult(context.Background(), "sh", "-c", "echo out; echo err >&2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Stdout != "out\n" || res.Stderr != "err\n" || res.ExitCode != 0 || res.Duration <= 0 {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+}
+
+func TestRunCmdResultFailureQuotesStderr(t *testing.T) {
+	res, err := RunCmdResult(context.Background(), "sh", "-c", "echo partial; printf 'x%.0s' $(seq 3000) >&2; exit 3")
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if res.ExitCode != 3 || res.Stdout != "partial\n" || len(res.Stderr) != 3000 {
+		t.Fatalf("unexpected result: exit=%d stdout=%q stderr=%d bytes", res.ExitCode, res.Stdout, len(res.Stderr))
+	}
+	quoted, ok := strings.CutPrefix(err.Error(), "exit status 3: ")
+	if !ok || !strings.HasSuffix(quoted, " ... (truncated)") || strings.Count(quoted, "x") != MaxErrorStderr {
+		t.Fatalf("error should quote the first %d bytes of stderr: %.60q...", MaxErrorStderr, err)
+	}
+
+	// Without stderr, the error falls back to stdout (git prints merge conflicts there).
+	_, err = RunCmdResult(context.Background(), "sh", "-c", "echo CONFLICT; exit 1")
+	if err == nil || !strings.HasSuffix(err.Error(), ": CONFLICT") {
+		t.Fatalf("err = %v", err)
+	}
+
+	res, err = RunCmdResult(context.Background(), "definitely-not-a-command-xyz")
+	if err == nil || res.ExitCode != -1 {
+		t.Fatalf("missing binary: %+v %v", res, err)
+	}
+}
+
+func TestRunCmdResultStreams(t *testing.T) {
+	var out, errOut bytes.Buffer
+	ctx := WithStreams(context.Background(), Streams{Stdin: strings.NewReader("in\n"), Stdout: &out, Stderr: &errOut})
+	res, err := RunCmdResult(ctx, "sh", "-c", "cat; echo warn >&2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Stdout != "in\n" || out.String() != "in\n" || errOut.String() != "warn\n" || res.Stderr != "warn\n" {
+		t.Fatalf("streamed %q/%q, captured %+v", out.String(), errOut.String(), res)
+	}
+}
+
+func TestTruncateKeepsRunes(t *testing.T) {
+	if got := truncate("aé", 2); got != "a ... (truncated)" {
+		t.Fatalf("truncate = %q", got)
+	}
+}
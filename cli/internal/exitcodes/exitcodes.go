@@ -0,0 +1,26 @@
+// Package exitcodes defines the process exit codes shared by agent commands so scripts
+// can tell failure classes apart without parsing output.
+//
+//	0  ExitOK            all checks passed (or nothing needed fixing)
+//	1  ExitWarn          non-critical warnings remain
+//	2  ExitFail          critical checks are failing (before or after a fix attempt)
+//	3  ExitRecoveryError agent check --fix found backups but restoring them or
+//	                     restarting services failed, or the operator aborted
+//	4  ExitNoBackup      agent check --fix found no restorable backup
+//	5  ExitPreFlight     the command could not start: invalid flags, repo root not
+//	                     found, or the pre-fix snapshot could not be written
+//
+// ExitError (1) is the generic code for any other command error; it keeps the historic
+// value and therefore overlaps ExitWarn.
+package exitcodes
+
+const (
+	ExitOK            = 0
+	ExitWarn          = 1
+	ExitFail          = 2
+	ExitRecoveryError = 3
+	ExitNoBackup      = 4
+	ExitPreFlight     = 5
+
+	ExitError = 1
+)
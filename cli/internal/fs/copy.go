@@ -0,0 +1,99 @@
+// Package fs holds file helpers shared by the backup, restore and config commands.
+package fs
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// EnvFileMode is the mode every copied .env file gets, whatever the source's mode: the file
+// holds the ARI password and provider API keys.
+const EnvFileMode os.FileMode = 0o600
+
+// CopyOptions configures CopyFileWithMeta.
+type CopyOptions struct {
+	// Fsync flushes the new file to disk before it is renamed into place, so a crash right
+	// after a restore cannot leave an empty dst.
+	Fsync bool
+}
+
+// CopyFileWithMeta copies src to dst through a temp file in dst's directory and a rename, so
+// readers never see a partial dst and dst is a new file even when the old one is a hard link
+// (into the backup content store, for instance). dst gets src's permission bits, except that
+// .env files (see IsEnvFile) always get EnvFileMode. Where src has a different owner than the
+// new file, the owner is copied too when the process is allowed to (root); otherwise the file
+// stays owned by the caller.
+func CopyFileWithMeta(src, dst string, opts CopyOptions) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("%s is not a regular file", src)
+	}
+	mode := info.Mode().Perm()
+	if IsEnvFile(dst) {
+		mode = EnvFileMode
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(dst), "."+filepath.Base(dst)+".tmp.*")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+	defer os.Remove(tmpName)
+	// Set the mode before writing so a .env copy is never readable by others, even briefly.
+	if err := tmp.Chmod(mode); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := io.Copy(tmp, in); err != nil {
+		tmp.Close()
+		return err
+	}
+	if opts.Fsync {
+		if err := tmp.Sync(); err != nil {
+			tmp.Close()
+			return err
+		}
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := copyOwner(tmpName, info); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpName, dst); err != nil {
+		return err
+	}
+	if opts.Fsync {
+		syncDir(filepath.Dir(dst))
+	}
+	return nil
+}
+
+// IsEnvFile reports whether path names a .env file: .env itself, an environment's .env.<name>,
+// or a backup such as .env.bak.<timestamp>. The .env.example template is not one.
+func IsEnvFile(path string) bool {
+	name := filepath.Base(path)
+	return name == ".env" || (strings.HasPrefix(name, ".env.") && name != ".env.example")
+}
+
+// syncDir makes a rename in dir durable. It is best-effort: not every platform can open a
+// directory for syncing.
+func syncDir(dir string) {
+	d, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+	_ = d.Sync()
+	_ = d.Close()
+}
@@ -0,0 +1,80 @@
+package fs
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, data string, mode os.FileMode) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(data), mode); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(path, mode); err != nil { // not narrowed by the umask
+		t.Fatal(err)
+	}
+}
+
+func TestCopyFileWithMetaModes(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Windows has no Unix permission bits")
+	}
+	dir := t.TempDir()
+	cases := []struct {
+		src, dst string
+		srcMode  os.FileMode
+		want     os.FileMode
+	}{
+		{"ai-agent.yaml", "ai-agent.copy.yaml", 0o640, 0o640},
+		{"hook.sh", "hook.copy.sh", 0o755, 0o755},
+		{"env-src", ".env", 0o644, 0o600},
+		{"env-src2", ".env.production", 0o666, 0o600},
+		{".env", ".env.bak.20260101_000000", 0o640, 0o600},
+		{"example", ".env.example", 0o644, 0o644},
+	}
+	for _, c := range cases {
+		src, dst := filepath.Join(dir, c.src), filepath.Join(dir, c.dst)
+		writeFile(t, src, "key: value\n", c.srcMode)
+		if err := CopyFileWithMeta(src, dst, CopyOptions{Fsync: true}); err != nil {
+			t.Fatalf("%s: %v", c.dst, err)
+		}
+		fi, err := os.Stat(dst)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fi.Mode().Perm() != c.want {
+			t.Errorf("%s: mode %v, want %v", c.dst, fi.Mode().Perm(), c.want)
+		}
+		if data, _ := os.ReadFile(dst); string(data) != "key: value\n" {
+			t.Errorf("%s: content %q", c.dst, data)
+		}
+	}
+}
+
+func TestCopyFileWithMetaReplacesHardLink(t *testing.T) {
+	dir := t.TempDir()
+	src, dst, linked := filepath.Join(dir, "new.yaml"), filepath.Join(dir, "dst.yaml"), filepath.Join(dir, "store-object")
+	writeFile(t, src, "new\n", 0o644)
+	writeFile(t, linked, "old\n", 0o644)
+	if err := os.Link(linked, dst); err != nil {
+		t.Skip("hard links not supported:", err)
+	}
+	if err := CopyFileWithMeta(src, dst, CopyOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(linked); string(data) != "old\n" {
+		t.Fatalf("copy wrote through the hard link: %q", data)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 3 {
+		t.Fatalf("temp file left behind: %v", entries)
+	}
+}
+
+func TestCopyFileWithMetaRejectsDirectory(t *testing.T) {
+	dir := t.TempDir()
+	if err := CopyFileWithMeta(dir, filepath.Join(dir, "x"), CopyOptions{}); err == nil {
+		t.Fatal("want an error for a directory source")
+	}
+}
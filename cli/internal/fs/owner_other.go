@@ -0,0 +1,30 @@
+//go:build !windows
+
+package fs
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// copyOwner gives path the uid/gid of src when they differ. Only root may do that, so a
+// permission error leaves path owned by the caller rather than failing the copy.
+func copyOwner(path string, src os.FileInfo) error {
+	want, ok := src.Sys().(*syscall.Stat_t)
+	if !ok {
+		return nil
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	got, ok := info.Sys().(*syscall.Stat_t)
+	if !ok || (got.Uid == want.Uid && got.Gid == want.Gid) {
+		return nil
+	}
+	if err := os.Chown(path, int(want.Uid), int(want.Gid)); err != nil && !errors.Is(err, os.ErrPermission) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+//go:build !windows
+
+package fs
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+func TestCopyFileWithMetaKeepsOwner(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("changing a file's owner needs root")
+	}
+	dir := t.TempDir()
+	src, dst := filepath.Join(dir, ".env.src"), filepath.Join(dir, ".env")
+	writeFile(t, src, "A=1\n", 0o600)
+	if err := os.Chown(src, 1234, 5678); err != nil {
+		t.Fatal(err)
+	}
+	if err := CopyFileWithMeta(src, dst, CopyOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	fi, _ := os.Stat(dst)
+	st := fi.Sys().(*syscall.Stat_t)
+	if st.Uid != 1234 || st.Gid != 5678 {
+		t.Fatalf("owner %d:%d, want 1234:5678", st.Uid, st.Gid)
+	}
+}
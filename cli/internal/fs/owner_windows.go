@@ -0,0 +1,8 @@
+package fs
+
+import "os"
+
+// copyOwner is a no-op on Windows, where files carry ACLs instead of a uid/gid.
+func copyOwner(path string, src os.FileInfo) error {
+	return nil
+}
@@ -3,8 +3,10 @@ package health
 import (
 	"context"
 	"fmt"
-	"os"
+	"log/slog"
 	"time"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/logging"
 )
 
 type CheckStatus string
@@ -35,13 +37,18 @@ type HealthResult struct {
 }
 
 type Checker struct {
-	verbose bool
+	log     *slog.Logger
 	ctx     context.Context
 	envMap  map[string]string
 	platform *PlatformContext
 }
 
-func NewChecker(verbose bool) *Checker {
+// NewChecker returns a checker that logs per-check progress to logger at debug level (nil
+// discards it).
+func NewChecker(logger *slog.Logger) *Checker {
+	if logger == nil {
+		logger = logging.Discard()
+	}
 	// Try to load .env file
 	envMap, err := LoadEnvFile(".env")
 	if err != nil {
@@ -50,7 +57,7 @@ func NewChecker(verbose bool) *Checker {
 	}
 	
 	return &Checker{
-		verbose: verbose,
+		log:     logger,
 		ctx:     context.Background(),
 		envMap:  envMap,
 		platform: DetectPlatformContext(),
@@ -80,9 +87,7 @@ func (c *Checker) RunAll() (*HealthResult, error) {
 	}
 	
 	for i, checkFn := range checks {
-		if c.verbose {
-			fmt.Fprintf(os.Stderr, "[%d/%d] Running check...\n", i+1, len(checks))
-		}
+		c.log.Debug("running health check", "index", i+1, "total", len(checks))
 		check := checkFn()
 		result.Checks = append(result.Checks, check)
 		
@@ -2,6 +2,7 @@ package health
 
 import (
 	"bufio"
+	"io"
 	"os"
 	"strings"
 )
@@ -17,7 +18,15 @@ func LoadEnvFile(path string) (map[string]string, error) {
 	}
 	defer file.Close()
 	
-	scanner := bufio.NewScanner(file)
+	return ParseEnv(file)
+}
+
+// ParseEnv parses KEY=VALUE lines the way LoadEnvFile does, for .env content that is not
+// read from a plain file (e.g. decrypted in memory)
+func ParseEnv(r io.Reader) (map[string]string, error) {
+	envMap := make(map[string]string)
+	
+	scanner := bufio.NewScanner(r)
 	for scanner.Scan() {
 		line := strings.TrimSpace(scanner.Text())
 		
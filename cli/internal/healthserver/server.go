@@ -0,0 +1,151 @@
+// Package healthserver exposes `agent check` results over HTTP for orchestrators
+// (Kubernetes/ECS probes) and Prometheus scrapes.
+package healthserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/check"
+)
+
+// server caches the most recent report produced by the background runner loop.
+type server struct {
+	mu      sync.RWMutex
+	report  *check.Report
+	runErr  error
+	lastRun time.Time
+	// staleAfter is how old the last run may get before the probes fail (0: never).
+	staleAfter time.Duration
+	failTotal  int
+	warnTotal  int
+	runs       int
+}
+
+// ServeHealth runs runner every interval, caches the last report, and serves:
+//
+//	GET /healthz  200 when the last report has no failures, else 503
+//	GET /readyz   200 when the last report has no failures or warnings, else 503
+//	GET /metrics  Prometheus text exposition
+//
+// Both probes also return 503 when the last run did not complete (it timed out or errored)
+// or finished longer ago than two intervals plus check.DefaultTimeout, i.e. the loop is stuck.
+// It blocks until the HTTP server stops.
+func ServeHealth(port int, runner *check.Runner, interval time.Duration) error {
+	if port <= 0 || port > 65535 {
+		return fmt.Errorf("invalid health port %d", port)
+	}
+	if runner == nil {
+		return fmt.Errorf("runner is required")
+	}
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+
+	s := &server{staleAfter: 2*interval + check.DefaultTimeout}
+	go s.loop(runner, interval)
+
+	srv := &http.Server{
+		Addr:              fmt.Sprintf(":%d", port),
+		Handler:           s.handler(),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	return srv.ListenAndServe()
+}
+
+func (s *server) loop(runner *check.Runner, interval time.Duration) {
+	for {
+		s.record(runner.RunWithTimeout(context.Background(), check.DefaultTimeout))
+		time.Sleep(interval)
+	}
+}
+
+func (s *server) record(rep *check.Report, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.report = rep
+	s.runErr = err
+	s.lastRun = time.Now()
+	s.runs++
+	if rep != nil {
+		s.failTotal += rep.FailCount
+		s.warnTotal += rep.WarnCount
+	}
+}
+
+func (s *server) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		s.probe(w, func(rep *check.Report) bool { return rep.FailCount == 0 })
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		s.probe(w, func(rep *check.Report) bool { return rep.FailCount+rep.WarnCount == 0 })
+	})
+	mux.HandleFunc("/metrics", s.metrics)
+	return mux
+}
+
+func (s *server) probe(w http.ResponseWriter, ok func(*check.Report) bool) {
+	s.mu.RLock()
+	rep := s.report
+	runErr := s.runErr
+	lastRun := s.lastRun
+	staleAfter := s.staleAfter
+	s.mu.RUnlock()
+
+	body := map[string]any{"status": "pending"}
+	code := http.StatusServiceUnavailable
+	if rep != nil {
+		body = map[string]any{
+			"status":     "fail",
+			"last_run":   lastRun.UTC().Format(time.RFC3339),
+			"pass_count": rep.PassCount,
+			"warn_count": rep.WarnCount,
+			"fail_count": rep.FailCount,
+		}
+		switch {
+		case runErr != nil && !errors.Is(runErr, check.ErrChecksFailed):
+			body["status"] = "error"
+			body["error"] = runErr.Error()
+		case staleAfter > 0 && time.Since(lastRun) > staleAfter:
+			body["status"] = "stale"
+		case ok(rep):
+			body["status"] = "ok"
+			code = http.StatusOK
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func (s *server) metrics(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	failing, warning := 0, 0
+	if s.report != nil {
+		failing, warning = s.report.FailCount, s.report.WarnCount
+	}
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	fmt.Fprintln(w, "# HELP agent_check_fail_total Failed check items observed across all runs.")
+	fmt.Fprintln(w, "# TYPE agent_check_fail_total counter")
+	fmt.Fprintf(w, "agent_check_fail_total %d\n", s.failTotal)
+	fmt.Fprintln(w, "# HELP agent_check_warn_total Warning check items observed across all runs.")
+	fmt.Fprintln(w, "# TYPE agent_check_warn_total counter")
+	fmt.Fprintf(w, "agent_check_warn_total %d\n", s.warnTotal)
+	fmt.Fprintln(w, "# HELP agent_check_failing Failed check items in the most recent run.")
+	fmt.Fprintln(w, "# TYPE agent_check_failing gauge")
+	fmt.Fprintf(w, "agent_check_failing %d\n", failing)
+	fmt.Fprintln(w, "# HELP agent_check_warning Warning check items in the most recent run.")
+	fmt.Fprintln(w, "# TYPE agent_check_warning gauge")
+	fmt.Fprintf(w, "agent_check_warning %d\n", warning)
+	fmt.Fprintln(w, "# HELP agent_check_runs_total Completed check runs.")
+	fmt.Fprintln(w, "# TYPE agent_check_runs_total counter")
+	fmt.Fprintf(w, "agent_check_runs_total %d\n", s.runs)
+}
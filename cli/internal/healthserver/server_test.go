@@ -0,0 +1,75 @@
+package healthserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/check"
+)
+
+func TestProbesFollowLastReport(t *testing.T) {
+	t.Parallel()
+
+	s := &server{}
+	h := s.handler()
+	get := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+
+	if code := get("/healthz").Code; code != http.StatusServiceUnavailable {
+		t.Fatalf("healthz before first run: %d", code)
+	}
+
+	s.record(&check.Report{WarnCount: 1}, nil)
+	if code := get("/healthz").Code; code != http.StatusOK {
+		t.Fatalf("healthz with warnings only: %d", code)
+	}
+	if code := get("/readyz").Code; code != http.StatusServiceUnavailable {
+		t.Fatalf("readyz with warnings: %d", code)
+	}
+
+	s.record(&check.Report{FailCount: 2}, nil)
+	if code := get("/healthz").Code; code != http.StatusServiceUnavailable {
+		t.Fatalf("healthz with failures: %d", code)
+	}
+	body := get("/metrics").Body.String()
+	if !strings.Contains(body, "agent_check_fail_total 2") || !strings.Contains(body, "agent_check_warn_total 1") {
+		t.Fatalf("unexpected metrics:\n%s", body)
+	}
+}
+
+func TestProbesFailOnRunErrorOrStaleReport(t *testing.T) {
+	t.Parallel()
+
+	s := &server{staleAfter: time.Minute}
+	h := s.handler()
+	get := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+
+	// A timed-out run leaves a partial report that may have no failures.
+	s.record(&check.Report{PassCount: 3}, check.ErrTimedOut)
+	for _, path := range []string{"/healthz", "/readyz"} {
+		if rec := get(path); rec.Code != http.StatusServiceUnavailable || !strings.Contains(rec.Body.String(), `"status":"error"`) {
+			t.Fatalf("%s after a timed-out run: %d %s", path, rec.Code, rec.Body)
+		}
+	}
+
+	s.record(&check.Report{PassCount: 3}, nil)
+	if code := get("/healthz").Code; code != http.StatusOK {
+		t.Fatalf("healthz after a clean run: %d", code)
+	}
+	s.mu.Lock()
+	s.lastRun = time.Now().Add(-2 * time.Minute)
+	s.mu.Unlock()
+	if rec := get("/healthz"); rec.Code != http.StatusServiceUnavailable || !strings.Contains(rec.Body.String(), `"status":"stale"`) {
+		t.Fatalf("healthz with a stale report: %d %s", rec.Code, rec.Body)
+	}
+}
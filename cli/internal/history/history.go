@@ -0,0 +1,105 @@
+// Package history queries the agent check --fix audit log (.agent/fix-history.jsonl).
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/check"
+)
+
+// FixRecord is one line of the fix history.
+type FixRecord = check.FixRecord
+
+// Query selects fix records. Zero fields do not filter; set fields must all match.
+type Query struct {
+	// Since keeps records at or after this time.
+	Since time.Time
+	// Before keeps records strictly before this time.
+	Before time.Time
+	// FailedOnly keeps records whose fix left failing checks (AfterFailCount > 0).
+	FailedOnly bool
+}
+
+// Match reports whether rec satisfies every filter in q.
+func (q Query) Match(rec FixRecord) bool {
+	if !q.Since.IsZero() && rec.Timestamp.Before(q.Since) {
+		return false
+	}
+	if !q.Before.IsZero() && !rec.Timestamp.Before(q.Before) {
+		return false
+	}
+	if q.FailedOnly && rec.AfterFailCount <= 0 {
+		return false
+	}
+	return true
+}
+
+// FilterHistory returns the records in the JSONL file at path whose timestamp lies in
+// [since, before), oldest first. A zero since or before leaves that end open.
+func FilterHistory(path string, since, before time.Time) ([]FixRecord, error) {
+	records, _, err := Load(path, Query{Since: since, Before: before})
+	return records, err
+}
+
+// Load reads path line by line and returns the records matching q, oldest first. Only the
+// timestamp is decoded for lines outside q's time range, since each record carries two full
+// check reports. Lines that do not parse are skipped and counted; a missing file has no
+// records.
+func Load(path string, q Query) (records []FixRecord, skipped int, err error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	defer f.Close()
+
+	timeOnly := Query{Since: q.Since, Before: q.Before}
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for sc.Scan() {
+		line := sc.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var stamp struct {
+			Timestamp time.Time `json:"timestamp"`
+		}
+		if err := json.Unmarshal(line, &stamp); err != nil {
+			skipped++
+			continue
+		}
+		if !timeOnly.Match(FixRecord{Timestamp: stamp.Timestamp}) {
+			continue
+		}
+		var rec FixRecord
+		if err := json.Unmarshal(line, &rec); err != nil {
+			skipped++
+			continue
+		}
+		if q.Match(rec) {
+			records = append(records, rec)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return records, skipped, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return records, skipped, nil
+}
+
+// ParseDate parses a --since/--before value: RFC3339, or a 2006-01-02 date meaning midnight
+// UTC, the zone agent fix history prints times in.
+func ParseDate(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q (use 2006-01-02 or RFC3339, e.g. 2006-01-02T15:04:05Z)", s)
+}
@@ -0,0 +1,87 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/check"
+)
+
+func writeHistory(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "fix-history.jsonl")
+	for i, after := range []int{0, 2, 0, 1} {
+		rec := check.FixRecord{
+			Timestamp:      time.Date(2026, 10, 1+i, 12, 0, 0, 0, time.UTC),
+			SourceBackup:   string(rune('a' + i)),
+			AfterFailCount: after,
+		}
+		if err := check.AppendFixRecord(path, rec); err != nil {
+			t.Fatal(err)
+		}
+	}
+	f, _ := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	f.WriteString(`{"timestamp": "2026-10-09T00:00:00Z", "repo_ro` + "\n")
+	f.Close()
+	return path
+}
+
+func sources(recs []FixRecord) string {
+	s := ""
+	for _, r := range recs {
+		s += r.SourceBackup
+	}
+	return s
+}
+
+func TestFilterHistory(t *testing.T) {
+	path := writeHistory(t)
+	day := func(d int) time.Time { return time.Date(2026, 10, d, 0, 0, 0, 0, time.UTC) }
+	for _, tc := range []struct {
+		since, before time.Time
+		want          string
+	}{
+		{time.Time{}, time.Time{}, "abcd"},
+		{day(2), time.Time{}, "bcd"},
+		{time.Time{}, day(3), "ab"},
+		{day(2), day(4), "bc"},
+		{day(2).Add(12 * time.Hour), day(3).Add(12 * time.Hour), "b"}, // since inclusive, before exclusive
+	} {
+		recs, err := FilterHistory(path, tc.since, tc.before)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := sources(recs); got != tc.want {
+			t.Errorf("since=%v before=%v: got %q, want %q", tc.since, tc.before, got, tc.want)
+		}
+	}
+}
+
+func TestLoadFailedOnly(t *testing.T) {
+	path := writeHistory(t)
+	recs, skipped, err := Load(path, Query{FailedOnly: true})
+	if err != nil || skipped != 1 || sources(recs) != "bd" {
+		t.Fatalf("got %q, %d skipped, %v", sources(recs), skipped, err)
+	}
+	since, _ := ParseDate("2026-10-03")
+	if recs, _, _ := Load(path, Query{Since: since, FailedOnly: true}); sources(recs) != "d" {
+		t.Fatalf("since + failed-only = %q", sources(recs))
+	}
+	if recs, _, err := Load(filepath.Join(t.TempDir(), "none.jsonl"), Query{}); err != nil || recs != nil {
+		t.Fatalf("missing file: %v %v", recs, err)
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	if got, err := ParseDate("2026-10-03"); err != nil || !got.Equal(time.Date(2026, 10, 3, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("date: %v %v", got, err)
+	}
+	if got, err := ParseDate("2026-10-03T10:00:00+02:00"); err != nil || !got.Equal(time.Date(2026, 10, 3, 8, 0, 0, 0, time.UTC)) {
+		t.Fatalf("rfc3339: %v %v", got, err)
+	}
+	if _, err := ParseDate("yesterday"); err == nil {
+		t.Fatal("want an error")
+	}
+}
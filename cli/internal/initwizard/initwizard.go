@@ -0,0 +1,277 @@
+// Package initwizard implements agent init: an interactive first-run wizard that writes .env,
+// config/ai-agent.local.yaml and a starter context from embedded templates. (The package
+// cannot be called init; Go reserves that identifier.)
+package initwizard
+
+import (
+	"bufio"
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/wizard"
+)
+
+//go:embed templates/*.tmpl
+var templates embed.FS
+
+// ContextName is the name of the starter context written to config/contexts.
+const ContextName = "assistant"
+
+// DefaultARIPort is the port written to .env and probed while validating ARI credentials.
+const DefaultARIPort = "8088"
+
+// Provider is a monolithic AI provider agent init can configure.
+type Provider struct {
+	Name         string // key under providers: in ai-agent.yaml
+	Label        string
+	APIKeyEnv    string
+	VoiceKey     string
+	DefaultVoice string
+	// LanguageKey is the provider's STT/agent language setting ("" when it detects the language).
+	LanguageKey string
+	validateKey func(key string) error
+	validVoice  func(voice string) error
+}
+
+// Providers lists the choices offered by RunInit, in menu order.
+var Providers = []Provider{
+	{
+		Name: "openai_realtime", Label: "OpenAI Realtime", APIKeyEnv: "OPENAI_API_KEY",
+		VoiceKey: "voice", DefaultVoice: "alloy",
+		validateKey: wizard.TestOpenAIKey,
+		validVoice:  oneOf("alloy", "ash", "ballad", "cedar", "coral", "echo", "marin", "sage", "shimmer", "verse"),
+	},
+	{
+		Name: "deepgram", Label: "Deepgram Voice Agent", APIKeyEnv: "DEEPGRAM_API_KEY",
+		VoiceKey: "tts_model", DefaultVoice: "aura-2-thalia-en", LanguageKey: "agent_language",
+		validateKey: wizard.TestDeepgramKey,
+		validVoice: func(v string) error {
+			if !strings.HasPrefix(v, "aura-") {
+				return errors.New(`Deepgram voices are Aura models, e.g. "aura-2-thalia-en"`)
+			}
+			return nil
+		},
+	},
+	{
+		Name: "google_live", Label: "Google Gemini Live", APIKeyEnv: "GOOGLE_API_KEY",
+		VoiceKey: "tts_voice_name", DefaultVoice: "Aoede", LanguageKey: "stt_language_code",
+		// No cheap validation endpoint; the key is checked when ai_engine connects.
+		validateKey: func(key string) error { return nil },
+		validVoice:  func(v string) error { return nil },
+	},
+}
+
+// InitOptions configures RunInit. Zero values read from os.Stdin and write to os.Stdout.
+type InitOptions struct {
+	In  io.Reader
+	Out io.Writer
+	// Force overwrites existing files, keeping each as <file>.bak.<timestamp>.
+	Force bool
+	// ReadPassword reads a secret without echo; nil reads a plain line from In.
+	ReadPassword func(prompt string) (string, error)
+	// TestARI checks ARI credentials (default wizard.TestARIConnectivity on DefaultARIPort).
+	TestARI func(host, username, password string) error
+	// Now stamps generated files (default time.Now).
+	Now func() time.Time
+}
+
+// Answers are the values RunInit collects; they fill the templates.
+type Answers struct {
+	AsteriskHost string
+	ARIPort      string
+	ARIUsername  string
+	ARIPassword  string
+	Provider     Provider
+	APIKey       string
+	Voice        string
+	Language     string
+}
+
+var languageCode = regexp.MustCompile(`^[a-z]{2,3}(-[A-Z]{2})?$`)
+
+// ErrExists is returned when a file RunInit would write already exists and Force is not set.
+var ErrExists = errors.New("already initialized")
+
+// RunInit prompts for the Asterisk ARI connection, an AI provider with its API key, a TTS
+// voice and an STT language, validating each answer as it is given, then writes .env,
+// config/ai-agent.local.yaml and config/contexts/<ContextName>.yaml under root.
+//
+// The base config/ai-agent.yaml is tracked and refreshed by agent update, so provider
+// settings go into the local override file the engine deep-merges over it.
+func RunInit(root string, opts InitOptions) error {
+	if opts.In == nil {
+		opts.In = os.Stdin
+	}
+	if opts.Out == nil {
+		opts.Out = os.Stdout
+	}
+	if opts.TestARI == nil {
+		opts.TestARI = wizard.TestARIConnectivity
+	}
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+	files := outputFiles(root)
+	if !opts.Force {
+		var existing []string
+		for _, f := range files {
+			if _, err := os.Stat(f.path); err == nil {
+				existing = append(existing, f.rel)
+			}
+		}
+		if len(existing) > 0 {
+			return fmt.Errorf("%w: %s exist (use agent setup to reconfigure, or --force to overwrite)", ErrExists, strings.Join(existing, ", "))
+		}
+	}
+
+	p := &prompter{in: bufio.NewReader(opts.In), out: opts.Out, readPassword: opts.ReadPassword}
+	answers, err := p.ask(opts.TestARI)
+	if err != nil {
+		return err
+	}
+
+	data := newTemplateData(answers, opts.Now())
+	stamp := opts.Now().UTC().Format("20060102_150405")
+	fmt.Fprintln(p.out)
+	for _, f := range files {
+		out, err := render(f.template, data)
+		if err != nil {
+			return err
+		}
+		if err := writeFile(f.path, out, f.mode, stamp); err != nil {
+			return err
+		}
+		fmt.Fprintf(p.out, "  ✅ Wrote %s\n", f.rel)
+	}
+	fmt.Fprintf(p.out, "\nRoute calls to the new context with Set(AI_CONTEXT=%s) in your dialplan (agent dialplan prints a snippet).\n", ContextName)
+	return nil
+}
+
+type outputFile struct {
+	rel, path, template string
+	mode                os.FileMode
+}
+
+func outputFiles(root string) []outputFile {
+	files := []outputFile{
+		{rel: ".env", template: "env.tmpl", mode: 0o600},
+		{rel: "config/ai-agent.local.yaml", template: "ai-agent.local.yaml.tmpl", mode: 0o644},
+		{rel: "config/contexts/" + ContextName + ".yaml", template: "context.yaml.tmpl", mode: 0o644},
+	}
+	for i := range files {
+		files[i].path = filepath.Join(root, filepath.FromSlash(files[i].rel))
+	}
+	return files
+}
+
+// templateData is what the templates see. .env values are quoted where docker compose would
+// otherwise cut them short or interpolate them (spaces, '#', '$').
+type templateData struct {
+	Generated    string
+	AsteriskHost string
+	ARIPort      string
+	ARIUsername  string
+	ARIPassword  string
+	Provider     string
+	APIKeyEnv    string
+	APIKey       string
+	VoiceKey     string
+	Voice        string
+	LanguageKey  string
+	Language     string
+	LanguageName string
+	ContextName  string
+}
+
+func newTemplateData(a Answers, now time.Time) templateData {
+	return templateData{
+		Generated:    now.UTC().Format(time.RFC3339),
+		AsteriskHost: envValue(a.AsteriskHost),
+		ARIPort:      envValue(a.ARIPort),
+		ARIUsername:  envValue(a.ARIUsername),
+		ARIPassword:  envValue(a.ARIPassword),
+		Provider:     a.Provider.Name,
+		APIKeyEnv:    a.Provider.APIKeyEnv,
+		APIKey:       envValue(a.APIKey),
+		VoiceKey:     a.Provider.VoiceKey,
+		Voice:        a.Voice,
+		LanguageKey:  a.Provider.LanguageKey,
+		Language:     a.Language,
+		LanguageName: languageName(a.Language),
+		ContextName:  ContextName,
+	}
+}
+
+func envValue(v string) string {
+	if v == "" || !strings.ContainsAny(v, " \t#$'\"") {
+		return v
+	}
+	if !strings.Contains(v, "'") {
+		return "'" + v + "'"
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `$$`).Replace(v) + `"`
+}
+
+func render(name string, data templateData) ([]byte, error) {
+	tmpl, err := template.ParseFS(templates, "templates/"+name)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("failed to render %s: %w", name, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// writeFile keeps an existing file as path.bak.<stamp>, the pattern agent check --fix
+// restores from.
+func writeFile(path string, data []byte, mode os.FileMode, stamp string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	if _, err := os.Stat(path); err == nil {
+		if err := os.Rename(path, path+".bak."+stamp); err != nil {
+			return fmt.Errorf("failed to back up %s: %w", path, err)
+		}
+	}
+	if err := os.WriteFile(path, data, mode); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+func oneOf(values ...string) func(string) error {
+	return func(v string) error {
+		for _, want := range values {
+			if v == want {
+				return nil
+			}
+		}
+		return fmt.Errorf("must be one of %s", strings.Join(values, ", "))
+	}
+}
+
+var languageNames = map[string]string{
+	"en": "English", "es": "Spanish", "fr": "French", "de": "German", "it": "Italian",
+	"pt": "Portuguese", "nl": "Dutch", "ja": "Japanese", "zh": "Chinese", "hi": "Hindi",
+	"ar": "Arabic", "ru": "Russian", "tr": "Turkish", "ko": "Korean", "pl": "Polish",
+}
+
+// languageName names a language code for the context prompt ("en-US" -> "English").
+func languageName(code string) string {
+	base, _, _ := strings.Cut(code, "-")
+	if name, ok := languageNames[base]; ok {
+		return name
+	}
+	return "the language with code " + code
+}
@@ -0,0 +1,131 @@
+package initwizard
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/configmerge"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/contexts"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/health"
+)
+
+var fixedNow = func() time.Time { return time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC) }
+
+func runInit(t *testing.T, root, input string, force bool) (string, error) {
+	t.Helper()
+	var out bytes.Buffer
+	err := RunInit(root, InitOptions{
+		In:    strings.NewReader(input),
+		Out:   &out,
+		Force: force,
+		Now:   fixedNow,
+		TestARI: func(host, username, password string) error {
+			if password != "s3cret #1" {
+				return errors.New("HTTP 401 (expected 200)")
+			}
+			return nil
+		},
+	})
+	return out.String(), err
+}
+
+func TestRunInitWritesFiles(t *testing.T) {
+	root := t.TempDir()
+	input := strings.Join([]string{
+		"pbx.local", "", "wrong", "n", // bad password, re-enter
+		"", "", "s3cret #1",
+		"3",             // google_live
+		"", "AIza-test", // empty key is refused
+		"",      // default voice
+		"EN_us", // invalid language, re-asked
+		"es-ES",
+	}, "\n") + "\n"
+	out, err := runInit(t, root, input, false)
+	if err != nil {
+		t.Fatalf("RunInit: %v\n%s", err, out)
+	}
+	if strings.Count(out, "ARI test failed") != 1 || !strings.Contains(out, "an API key is required") || !strings.Contains(out, "language code") {
+		t.Fatalf("validation messages missing:\n%s", out)
+	}
+
+	env, err := health.LoadEnvFile(filepath.Join(root, ".env"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The ARI host was re-asked with the previous answer as default.
+	if env["ASTERISK_HOST"] != "pbx.local" || env["ASTERISK_ARI_USERNAME"] != "asterisk" || env["GOOGLE_API_KEY"] != "AIza-test" {
+		t.Fatalf(".env = %v", env)
+	}
+	if got := strings.Trim(env["ASTERISK_ARI_PASSWORD"], `'"`); got != "s3cret #1" {
+		t.Fatalf("password = %q", env["ASTERISK_ARI_PASSWORD"])
+	}
+	if st, _ := os.Stat(filepath.Join(root, ".env")); st.Mode().Perm() != 0o600 {
+		t.Fatalf(".env mode = %v", st.Mode().Perm())
+	}
+
+	local, err := configmerge.ReadYAMLFile(filepath.Join(root, "config", "ai-agent.local.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	prov := local["providers"].(map[string]any)["google_live"].(map[string]any)
+	if local["default_provider"] != "google_live" || prov["tts_voice_name"] != "Aoede" || prov["stt_language_code"] != "es-ES" {
+		t.Fatalf("ai-agent.local.yaml = %#v", local)
+	}
+
+	ctx, err := configmerge.ReadYAMLFile(filepath.Join(root, "config", "contexts", ContextName+".yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ctx["name"] != ContextName || ctx["provider"] != "google_live" || ctx["voice"] != "Aoede" || ctx["language"] != "es-ES" || !strings.Contains(ctx["prompt"].(string), "Reply in Spanish") {
+		t.Fatalf("context = %#v", ctx)
+	}
+	// agent config contexts validate must accept what agent init writes.
+	issues, err := contexts.ValidateContext(filepath.Join(root, "config", "contexts", ContextName+".yaml"))
+	if err != nil || len(issues) != 0 {
+		t.Fatalf("ValidateContext = %+v, %v", issues, err)
+	}
+}
+
+func TestRunInitRefusesExistingFiles(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, ".env"), []byte("ASTERISK_HOST=old\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runInit(t, root, "", false); !errors.Is(err, ErrExists) {
+		t.Fatalf("err = %v, want ErrExists", err)
+	}
+
+	input := "\n\ns3cret #1\n3\nkey\n\n\n"
+	if out, err := runInit(t, root, input, true); err != nil {
+		t.Fatalf("--force: %v\n%s", err, out)
+	}
+	old, err := os.ReadFile(filepath.Join(root, ".env.bak.20261001_120000"))
+	if err != nil || string(old) != "ASTERISK_HOST=old\n" {
+		t.Fatalf("backup = %q, %v", old, err)
+	}
+}
+
+func TestRunInitStopsAtEndOfInput(t *testing.T) {
+	if _, err := runInit(t, t.TempDir(), "pbx\n", false); err == nil || !strings.Contains(err.Error(), "input ended") {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestEnvValue(t *testing.T) {
+	cases := map[string]string{
+		"plain":    "plain",
+		"a b#c":    "'a b#c'",
+		"pa$$":     "'pa$$'",
+		`it's "x"`: `"it's \"x\""`,
+	}
+	for in, want := range cases {
+		if got := envValue(in); got != want {
+			t.Errorf("envValue(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package initwizard
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// prompter asks questions on out and reads answers from one buffered reader, so piped input
+// (tests, scripted installs) is not lost between prompts.
+type prompter struct {
+	in           *bufio.Reader
+	out          io.Writer
+	readPassword func(prompt string) (string, error)
+}
+
+func (p *prompter) ask(testARI func(host, username, password string) error) (Answers, error) {
+	a := Answers{ARIPort: DefaultARIPort}
+	fmt.Fprintln(p.out, "🚀 Asterisk AI Voice Agent - First-run setup")
+	fmt.Fprintln(p.out, "══════════════════════════════════════════")
+
+	fmt.Fprintln(p.out, "\nStep 1/3: Asterisk ARI")
+	for {
+		var err error
+		if a.AsteriskHost, err = p.text("Asterisk host", orDefault(a.AsteriskHost, "127.0.0.1"), nonEmpty); err != nil {
+			return a, err
+		}
+		if a.ARIUsername, err = p.text("ARI username", orDefault(a.ARIUsername, "asterisk"), nonEmpty); err != nil {
+			return a, err
+		}
+		if a.ARIPassword, err = p.password("ARI password"); err != nil {
+			return a, err
+		}
+		fmt.Fprintf(p.out, "  Testing ARI at %s:%s...\n", a.AsteriskHost, a.ARIPort)
+		err = testARI(a.AsteriskHost, a.ARIUsername, a.ARIPassword)
+		if err == nil {
+			fmt.Fprintln(p.out, "  ✅ ARI reachable, credentials accepted")
+			break
+		}
+		fmt.Fprintf(p.out, "  ❌ ARI test failed: %v\n", err)
+		keep, err := p.confirm("Use these values anyway (e.g. Asterisk is not running yet)?", false)
+		if err != nil {
+			return a, err
+		}
+		if keep {
+			break
+		}
+	}
+
+	fmt.Fprintln(p.out, "\nStep 2/3: AI provider")
+	choice, err := p.choose("AI provider", Providers)
+	if err != nil {
+		return a, err
+	}
+	a.Provider = Providers[choice]
+	for {
+		key, err := p.password(a.Provider.APIKeyEnv)
+		if err != nil {
+			return a, err
+		}
+		if key == "" {
+			fmt.Fprintln(p.out, "  ❌ an API key is required")
+			continue
+		}
+		fmt.Fprintf(p.out, "  Checking %s...\n", a.Provider.APIKeyEnv)
+		if err := a.Provider.validateKey(key); err != nil {
+			fmt.Fprintf(p.out, "  ❌ %v\n", err)
+			continue
+		}
+		a.APIKey = key
+		break
+	}
+
+	fmt.Fprintln(p.out, "\nStep 3/3: Voice and language")
+	if a.Voice, err = p.text("TTS voice", a.Provider.DefaultVoice, a.Provider.validVoice); err != nil {
+		return a, err
+	}
+	validLanguage := func(v string) error {
+		if !languageCode.MatchString(v) {
+			return errors.New(`use a language code such as "en" or "en-US"`)
+		}
+		return nil
+	}
+	if a.Language, err = p.text("STT language", "en-US", validLanguage); err != nil {
+		return a, err
+	}
+	return a, nil
+}
+
+// text re-asks until validate accepts the answer; an empty answer takes def.
+func (p *prompter) text(label, def string, validate func(string) error) (string, error) {
+	for {
+		if def != "" {
+			fmt.Fprintf(p.out, "  %s [%s]: ", label, def)
+		} else {
+			fmt.Fprintf(p.out, "  %s: ", label)
+		}
+		v, err := p.line()
+		if err != nil {
+			return "", err
+		}
+		if v == "" {
+			v = def
+		}
+		if err := validate(v); err != nil {
+			fmt.Fprintf(p.out, "  ❌ %v\n", err)
+			continue
+		}
+		return v, nil
+	}
+}
+
+func (p *prompter) password(label string) (string, error) {
+	if p.readPassword != nil {
+		v, err := p.readPassword("  " + label + ": ")
+		return strings.TrimSpace(v), err
+	}
+	fmt.Fprintf(p.out, "  %s: ", label)
+	return p.line()
+}
+
+func (p *prompter) choose(label string, providers []Provider) (int, error) {
+	for i, prov := range providers {
+		fmt.Fprintf(p.out, "  %d) %s\n", i+1, prov.Label)
+	}
+	for {
+		fmt.Fprintf(p.out, "  %s [1]: ", label)
+		v, err := p.line()
+		if err != nil {
+			return 0, err
+		}
+		if v == "" {
+			return 0, nil
+		}
+		n, err := strconv.Atoi(v)
+		if err == nil && n >= 1 && n <= len(providers) {
+			return n - 1, nil
+		}
+		fmt.Fprintf(p.out, "  ❌ enter a number from 1 to %d\n", len(providers))
+	}
+}
+
+func (p *prompter) confirm(label string, defaultYes bool) (bool, error) {
+	hint := "[y/N]"
+	if defaultYes {
+		hint = "[Y/n]"
+	}
+	fmt.Fprintf(p.out, "  %s %s: ", label, hint)
+	v, err := p.line()
+	if err != nil {
+		return false, err
+	}
+	if v == "" {
+		return defaultYes, nil
+	}
+	v = strings.ToLower(v)
+	return v == "y" || v == "yes", nil
+}
+
+// line reads one trimmed line; running out of input aborts the wizard instead of looping.
+func (p *prompter) line() (string, error) {
+	s, err := p.in.ReadString('\n')
+	if err != nil && s == "" {
+		if errors.Is(err, io.EOF) {
+			return "", errors.New("agent init: input ended before setup was complete")
+		}
+		return "", err
+	}
+	return strings.TrimSpace(s), nil
+}
+
+func nonEmpty(v string) error {
+	if strings.TrimSpace(v) == "" {
+		return errors.New("a value is required")
+	}
+	return nil
+}
+
+func orDefault(v, def string) string {
+	if v != "" {
+		return v
+	}
+	return def
+}
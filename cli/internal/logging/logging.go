@@ -0,0 +1,88 @@
+// Package logging builds the CLI's structured diagnostic logger. User-facing output (reports,
+// prompts, progress) stays on stdout via fmt; slog records go to stderr so they can be
+// collected separately.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// DefaultLevel keeps routine runs quiet: only warnings and errors are logged unless
+// --log-level or --verbose asks for more.
+const DefaultLevel = "warn"
+
+// Options are the root command's logging flags.
+type Options struct {
+	Level   string // debug|info|warn|error
+	Format  string // text|json
+	Verbose bool   // --verbose; forces debug
+}
+
+// New returns a logger writing to w. Verbose lowers the level to debug regardless of Level.
+func New(w io.Writer, opts Options) (*slog.Logger, error) {
+	level, err := ParseLevel(opts.Level)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Verbose {
+		level = slog.LevelDebug
+	}
+	hopts := &slog.HandlerOptions{Level: level}
+	switch strings.ToLower(strings.TrimSpace(opts.Format)) {
+	case "", "text":
+		return slog.New(slog.NewTextHandler(w, hopts)), nil
+	case "json":
+		return slog.New(slog.NewJSONHandler(w, hopts)), nil
+	default:
+		return nil, fmt.Errorf("invalid --log-format %q (must be text or json)", opts.Format)
+	}
+}
+
+// ParseLevel maps a --log-level value to a slog.Level; empty means DefaultLevel.
+func ParseLevel(s string) (slog.Level, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" {
+		s = DefaultLevel
+	}
+	switch s {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return 0, fmt.Errorf("invalid --log-level %q (must be debug, info, warn or error)", s)
+	}
+}
+
+// Discard returns a logger that drops every record; used when no logger was supplied.
+func Discard() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelError + 1}))
+}
+
+type ctxKey struct{}
+
+// NewContext returns a copy of ctx carrying l.
+func NewContext(ctx context.Context, l *slog.Logger) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, ctxKey{}, l)
+}
+
+// FromContext returns the logger stored by NewContext, or Discard when there is none.
+func FromContext(ctx context.Context) *slog.Logger {
+	if ctx != nil {
+		if l, ok := ctx.Value(ctxKey{}).(*slog.Logger); ok && l != nil {
+			return l
+		}
+	}
+	return Discard()
+}
@@ -0,0 +1,57 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestJSONLoggerFields(t *testing.T) {
+	var buf bytes.Buffer
+	l, err := New(&buf, Options{Level: "info", Format: "json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Debug("hidden")
+	l.Info("check finished", "check", "ARI", "status", "fail", "duration_ms", 42)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("expected one record (debug filtered), got:\n%s", buf.String())
+	}
+	var rec map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &rec); err != nil {
+		t.Fatal(err)
+	}
+	if rec["level"] != "INFO" || rec["msg"] != "check finished" || rec["check"] != "ARI" || rec["status"] != "fail" || rec["duration_ms"] != float64(42) {
+		t.Fatalf("unexpected record: %v", rec)
+	}
+}
+
+func TestVerboseForcesDebug(t *testing.T) {
+	var buf bytes.Buffer
+	l, err := New(&buf, Options{Level: "error", Format: "text", Verbose: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Debug("probe", "cmd", "docker")
+	if !strings.Contains(buf.String(), "level=DEBUG msg=probe cmd=docker") {
+		t.Fatalf("unexpected text output: %q", buf.String())
+	}
+
+	ctx := NewContext(context.Background(), l)
+	if FromContext(ctx) != l || FromContext(context.Background()) == nil {
+		t.Fatal("FromContext did not return the stored logger or a fallback")
+	}
+}
+
+func TestInvalidOptions(t *testing.T) {
+	if _, err := New(&bytes.Buffer{}, Options{Level: "trace"}); err == nil {
+		t.Fatal("expected invalid level error")
+	}
+	if _, err := New(&bytes.Buffer{}, Options{Format: "xml"}); err == nil {
+		t.Fatal("expected invalid format error")
+	}
+}
@@ -0,0 +1,168 @@
+// Package logs streams docker compose logs for agent logs, filtered by pattern and level.
+package logs
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// LogOptions selects and filters the Compose service logs StreamLogs writes.
+type LogOptions struct {
+	// Dir is the Compose project directory (the repo root); "" uses the working directory.
+	Dir string
+	// Services limits output to these Compose services; empty means all of them.
+	Services []string
+	// Since only shows entries newer than this (0 shows the whole log).
+	Since time.Duration
+	// Follow keeps streaming new entries until ctx is cancelled.
+	Follow bool
+	// Grep keeps lines matching this regular expression or containing it as plain text.
+	Grep string
+	// Level keeps JSON log entries at or above this level (debug, info, warning, error,
+	// critical). Lines that are not JSON carry no parseable level and are kept.
+	Level string
+}
+
+var levelRank = map[string]int{
+	"debug": 10, "info": 20, "warn": 30, "warning": 30,
+	"error": 40, "exception": 40, "critical": 50, "fatal": 50,
+}
+
+// composeLogs starts docker compose logs and returns its stdout; tests replace it.
+var composeLogs = func(ctx context.Context, dir string, args []string) (io.ReadCloser, func() error, error) {
+	cmd := exec.CommandContext(ctx, "docker", append([]string{"compose", "logs"}, args...)...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, nil, fmt.Errorf("docker compose logs: %w", err)
+	}
+	wait := func() error {
+		if err := cmd.Wait(); err != nil {
+			if ctx.Err() != nil {
+				return nil // interrupted while following
+			}
+			if msg := strings.TrimSpace(stderr.String()); msg != "" {
+				return fmt.Errorf("docker compose logs: %w: %s", err, msg)
+			}
+			return fmt.Errorf("docker compose logs: %w", err)
+		}
+		return nil
+	}
+	return out, wait, nil
+}
+
+// StreamLogs runs docker compose logs for opts.Services and copies the lines that pass the
+// Grep and Level filters to w. With Follow it returns once ctx is cancelled.
+func StreamLogs(ctx context.Context, opts LogOptions, w io.Writer) error {
+	f, err := newFilter(opts)
+	if err != nil {
+		return err
+	}
+	args := []string{"--no-color"}
+	if opts.Since > 0 {
+		args = append(args, "--since", opts.Since.String())
+	}
+	if opts.Follow {
+		args = append(args, "--follow")
+	}
+	args = append(args, opts.Services...)
+
+	out, wait, err := composeLogs(ctx, opts.Dir, args)
+	if err != nil {
+		return err
+	}
+	copyErr := f.copy(out, w)
+	if copyErr != nil {
+		_ = out.Close()
+	}
+	if err := wait(); err != nil {
+		return err
+	}
+	if ctx.Err() != nil {
+		return nil
+	}
+	return copyErr
+}
+
+type filter struct {
+	grep     *regexp.Regexp // nil when Grep is not a valid regular expression
+	text     string
+	minLevel int
+}
+
+func newFilter(opts LogOptions) (*filter, error) {
+	f := &filter{text: opts.Grep}
+	if opts.Grep != "" {
+		// A pattern such as "call(" is still useful as plain text.
+		f.grep, _ = regexp.Compile(opts.Grep)
+	}
+	if opts.Level != "" {
+		rank, ok := levelRank[strings.ToLower(strings.TrimSpace(opts.Level))]
+		if !ok {
+			return nil, fmt.Errorf("unknown log level %q (use debug, info, warning, error or critical)", opts.Level)
+		}
+		f.minLevel = rank
+	}
+	return f, nil
+}
+
+func (f *filter) copy(r io.Reader, w io.Writer) error {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 4*1024*1024) // tracebacks and transcripts make long lines
+	for sc.Scan() {
+		line := sc.Text()
+		if !f.match(line) {
+			continue
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	if err := sc.Err(); err != nil && !errors.Is(err, io.ErrClosedPipe) {
+		return err
+	}
+	return nil
+}
+
+func (f *filter) match(line string) bool {
+	if f.text != "" && !strings.Contains(line, f.text) && (f.grep == nil || !f.grep.MatchString(line)) {
+		return false
+	}
+	if f.minLevel > 0 {
+		if level, ok := jsonLevel(line); ok {
+			rank, known := levelRank[level]
+			return !known || rank >= f.minLevel
+		}
+	}
+	return true
+}
+
+// jsonLevel returns the "level" field of a JSON log entry, which may follow the
+// "service  | " prefix docker compose adds.
+func jsonLevel(line string) (string, bool) {
+	i := strings.IndexByte(line, '{')
+	if i < 0 {
+		return "", false
+	}
+	var entry struct {
+		Level string `json:"level"`
+	}
+	if err := json.Unmarshal([]byte(line[i:]), &entry); err != nil || entry.Level == "" {
+		return "", false
+	}
+	return strings.ToLower(entry.Level), true
+}
@@ -0,0 +1,79 @@
+package logs
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const sample = `ai_engine  | {"event": "call started", "level": "info", "call_id": "c1"}
+ai_engine  | {"event": "provider timeout", "level": "error", "call_id": "c1"}
+ai_engine  | {"event": "stt chunk", "level": "debug"}
+ai_engine  | {"event": "engine crashed", "level": "critical"}
+admin_ui   | INFO:     127.0.0.1:51234 - "GET /api/health HTTP/1.1" 200 OK
+local_ai_server  | Loaded model (vosk) for call c1
+`
+
+func stream(t *testing.T, opts LogOptions) (string, []string) {
+	t.Helper()
+	var gotArgs []string
+	old := composeLogs
+	composeLogs = func(ctx context.Context, dir string, args []string) (io.ReadCloser, func() error, error) {
+		gotArgs = args
+		return io.NopCloser(strings.NewReader(sample)), func() error { return nil }, nil
+	}
+	t.Cleanup(func() { composeLogs = old })
+	var buf bytes.Buffer
+	if err := StreamLogs(context.Background(), opts, &buf); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String(), gotArgs
+}
+
+func TestStreamLogsArgs(t *testing.T) {
+	_, args := stream(t, LogOptions{Services: []string{"ai_engine", "admin_ui"}, Since: time.Hour, Follow: true})
+	want := []string{"--no-color", "--since", "1h0m0s", "--follow", "ai_engine", "admin_ui"}
+	if !reflect.DeepEqual(args, want) {
+		t.Fatalf("args = %q, want %q", args, want)
+	}
+}
+
+func TestStreamLogsLevel(t *testing.T) {
+	out, _ := stream(t, LogOptions{Level: "ERROR"})
+	for _, want := range []string{"provider timeout", "engine crashed", "GET /api/health", "Loaded model"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+	for _, unwanted := range []string{"call started", "stt chunk"} {
+		if strings.Contains(out, unwanted) {
+			t.Errorf("%q should be filtered:\n%s", unwanted, out)
+		}
+	}
+}
+
+func TestStreamLogsGrep(t *testing.T) {
+	out, _ := stream(t, LogOptions{Grep: `call_id": "c\d`})
+	if strings.Count(out, "\n") != 2 {
+		t.Fatalf("regex grep:\n%s", out)
+	}
+	// Not a valid regex, but still matched as text in non-JSON lines.
+	out, _ = stream(t, LogOptions{Grep: "model (vosk"})
+	if !strings.Contains(out, "Loaded model") || strings.Count(out, "\n") != 1 {
+		t.Fatalf("substring grep:\n%s", out)
+	}
+	out, _ = stream(t, LogOptions{Grep: "c1", Level: "error"})
+	if strings.TrimSpace(out) != strings.Split(sample, "\n")[1]+"\n"+strings.Split(sample, "\n")[5] {
+		t.Fatalf("grep + level:\n%s", out)
+	}
+}
+
+func TestStreamLogsRejectsUnknownLevel(t *testing.T) {
+	if err := StreamLogs(context.Background(), LogOptions{Level: "loud"}, io.Discard); err == nil {
+		t.Fatal("expected an error")
+	}
+}
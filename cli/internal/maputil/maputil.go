@@ -0,0 +1,42 @@
+// Package maputil turns maps into slices in key order, for output that must not depend on Go's
+// randomized map iteration.
+package maputil
+
+import (
+	"cmp"
+	"slices"
+)
+
+// Entry is one key/value pair of a map.
+type Entry[K cmp.Ordered, V any] struct {
+	Key   K
+	Value V
+}
+
+// SortedKeys returns the keys of m in ascending order. The result is never nil.
+func SortedKeys[K cmp.Ordered, V any](m map[K]V) []K {
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// SortedValues returns the values of m ordered by their keys.
+func SortedValues[K cmp.Ordered, V any](m map[K]V) []V {
+	values := make([]V, 0, len(m))
+	for _, k := range SortedKeys(m) {
+		values = append(values, m[k])
+	}
+	return values
+}
+
+// SortedEntries returns the key/value pairs of m ordered by key.
+func SortedEntries[K cmp.Ordered, V any](m map[K]V) []Entry[K, V] {
+	entries := make([]Entry[K, V], 0, len(m))
+	for _, k := range SortedKeys(m) {
+		entries = append(entries, Entry[K, V]{Key: k, Value: m[k]})
+	}
+	return entries
+}
@@ -0,0 +1,73 @@
+package maputil
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestSorted(t *testing.T) {
+	m := map[string]int{"b": 2, "c": 3, "a": 1}
+	if got := SortedKeys(m); !reflect.DeepEqual(got, []string{"a", "b", "c"}) {
+		t.Fatalf("SortedKeys = %v", got)
+	}
+	if got := SortedValues(m); !reflect.DeepEqual(got, []int{1, 2, 3}) {
+		t.Fatalf("SortedValues = %v", got)
+	}
+	want := []Entry[string, int]{{"a", 1}, {"b", 2}, {"c", 3}}
+	if got := SortedEntries(m); !reflect.DeepEqual(got, want) {
+		t.Fatalf("SortedEntries = %v", got)
+	}
+	if got := SortedKeys(map[int]bool{3: true, -1: true}); !reflect.DeepEqual(got, []int{-1, 3}) {
+		t.Fatalf("int keys = %v", got)
+	}
+	if got := SortedKeys(map[string]bool(nil)); got == nil || len(got) != 0 {
+		t.Fatalf("nil map = %#v", got)
+	}
+}
+
+// handRolled is the loop SortedKeys replaced.
+func handRolled(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func benchMap(n int) map[string]bool {
+	m := make(map[string]bool, n)
+	for i := 0; i < n; i++ {
+		m[fmt.Sprintf("config/contexts/ctx-%04d.yaml", i)] = true
+	}
+	return m
+}
+
+func TestSortedKeysAllocatesLikeHandRolled(t *testing.T) {
+	m := benchMap(100)
+	generic := testing.AllocsPerRun(100, func() { SortedKeys(m) })
+	manual := testing.AllocsPerRun(100, func() { handRolled(m) })
+	if generic > manual {
+		t.Fatalf("SortedKeys: %v allocs/op, hand-rolled: %v", generic, manual)
+	}
+}
+
+func BenchmarkSortedKeys(b *testing.B) {
+	for _, n := range []int{10, 1000} {
+		m := benchMap(n)
+		b.Run(fmt.Sprintf("generic/%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				SortedKeys(m)
+			}
+		})
+		b.Run(fmt.Sprintf("hand-rolled/%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				handRolled(m)
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package metrics
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultOutput is where agent metrics collect appends samples, relative to the repo root.
+const DefaultOutput = ".agent/metrics.jsonl"
+
+// DefaultInterval is how often agent metrics collect polls docker stats.
+const DefaultInterval = 10 * time.Second
+
+// Sample is one container's resource usage at one poll, stored as a JSON line. Network
+// counters are cumulative since the container started, as docker reports them.
+type Sample struct {
+	Time       time.Time `json:"time"`
+	Container  string    `json:"container"`
+	CPUPercent float64   `json:"cpu_percent"`
+	MemBytes   int64     `json:"mem_bytes"`
+	MemLimit   int64     `json:"mem_limit_bytes"`
+	MemPercent float64   `json:"mem_percent"`
+	NetRxBytes int64     `json:"net_rx_bytes"`
+	NetTxBytes int64     `json:"net_tx_bytes"`
+}
+
+// statsCommand runs docker stats; replaced in tests.
+var statsCommand = func(ctx context.Context) ([]byte, error) {
+	out, err := exec.CommandContext(ctx, "docker", "stats", "--no-stream", "--format", "json").Output()
+	if ee, ok := err.(*exec.ExitError); ok && len(ee.Stderr) > 0 {
+		return nil, fmt.Errorf("docker stats: %w: %s", err, strings.TrimSpace(string(ee.Stderr)))
+	}
+	return out, err
+}
+
+// CollectMetrics polls docker stats --no-stream every interval and appends one JSON line per
+// running container in services (every container when services is empty) to output, creating
+// it and its directory as needed. It returns nil once ctx is cancelled. A failing first poll
+// is returned, as docker is then most likely unavailable; later failures are logged and the
+// poll is retried at the next tick.
+func CollectMetrics(ctx context.Context, services []string, interval time.Duration, output string) error {
+	if interval <= 0 {
+		return errors.New("interval must be positive")
+	}
+	if err := os.MkdirAll(filepath.Dir(output), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(output, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for first := true; ; first = false {
+		if err := collectOnce(ctx, services, f); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			if first {
+				return err
+			}
+			slog.Warn("metrics sample failed", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func collectOnce(ctx context.Context, services []string, w io.Writer) error {
+	out, err := statsCommand(ctx)
+	if err != nil {
+		return err
+	}
+	samples, err := parseStats(out, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	for _, s := range samples {
+		if len(services) > 0 && !slices.Contains(services, s.Container) {
+			continue
+		}
+		if err := enc.Encode(s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseStats reads docker stats --format json output, one JSON object per container, e.g.
+// {"Name":"ai_engine","CPUPerc":"1.25%","MemUsage":"50MiB / 7.6GiB","MemPerc":"0.64%",
+// "NetIO":"1.2kB / 648B",...}.
+func parseStats(out []byte, at time.Time) ([]Sample, error) {
+	var samples []Sample
+	for _, line := range strings.Split(string(out), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		var row struct {
+			Name     string
+			CPUPerc  string
+			MemUsage string
+			MemPerc  string
+			NetIO    string
+		}
+		if err := json.Unmarshal([]byte(line), &row); err != nil {
+			return nil, fmt.Errorf("unexpected docker stats output %q: %w", line, err)
+		}
+		s := Sample{Time: at, Container: row.Name}
+		s.CPUPercent = parsePercent(row.CPUPerc)
+		s.MemPercent = parsePercent(row.MemPerc)
+		s.MemBytes, s.MemLimit = parseSizePair(row.MemUsage)
+		s.NetRxBytes, s.NetTxBytes = parseSizePair(row.NetIO)
+		samples = append(samples, s)
+	}
+	return samples, nil
+}
+
+func parsePercent(v string) float64 {
+	f, _ := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(v), "%"), 64)
+	return f
+}
+
+// parseSizePair parses docker's "used / total" columns; unparsable halves are 0.
+func parseSizePair(v string) (int64, int64) {
+	a, b, _ := strings.Cut(v, "/")
+	return parseSize(a), parseSize(b)
+}
+
+var sizeRE = regexp.MustCompile(`^([0-9.]+)\s*([a-zA-Z]*)$`)
+
+// sizeUnits covers both the decimal units docker uses for network and block I/O and the
+// binary ones it uses for memory.
+var sizeUnits = map[string]float64{
+	"": 1, "b": 1,
+	"kb": 1e3, "mb": 1e6, "gb": 1e9, "tb": 1e12,
+	"kib": 1 << 10, "mib": 1 << 20, "gib": 1 << 30, "tib": 1 << 40,
+}
+
+func parseSize(v string) int64 {
+	m := sizeRE.FindStringSubmatch(strings.TrimSpace(v))
+	if m == nil {
+		return 0
+	}
+	n, err := strconv.ParseFloat(m[1], 64)
+	mult, ok := sizeUnits[strings.ToLower(m[2])]
+	if err != nil || !ok {
+		return 0
+	}
+	return int64(n * mult)
+}
+
+// ReadSamples reads the samples in a file written by CollectMetrics taken at or after since
+// (all of them for a zero since). Lines that do not parse, such as one cut short by a crash,
+// are skipped.
+func ReadSamples(path string, since time.Time) ([]Sample, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var samples []Sample
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		var s Sample
+		if json.Unmarshal(sc.Bytes(), &s) != nil || s.Container == "" || s.Time.Before(since) {
+			continue
+		}
+		samples = append(samples, s)
+	}
+	return samples, sc.Err()
+}
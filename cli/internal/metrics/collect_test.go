@@ -0,0 +1,102 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const statsOutput = `{"BlockIO":"0B / 0B","CPUPerc":"12.50%","Container":"ai_engine","ID":"0a1b","MemPerc":"1.30%","MemUsage":"100MiB / 7.5GiB","Name":"ai_engine","NetIO":"1.5kB / 648B","PIDs":"12"}
+{"BlockIO":"0B / 0B","CPUPerc":"0.10%","Container":"postgres","ID":"9f8e","MemPerc":"0.40%","MemUsage":"30MiB / 7.5GiB","Name":"postgres","NetIO":"0B / 0B","PIDs":"5"}
+`
+
+func TestCollectMetricsAppendsSamplesUntilCancelled(t *testing.T) {
+	var polls atomic.Int32
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	old := statsCommand
+	statsCommand = func(context.Context) ([]byte, error) {
+		if polls.Add(1) == 3 {
+			cancel()
+		}
+		return []byte(statsOutput), nil
+	}
+	defer func() { statsCommand = old }()
+
+	out := filepath.Join(t.TempDir(), ".agent", "metrics.jsonl")
+	if err := CollectMetrics(ctx, []string{"ai_engine"}, time.Millisecond, out); err != nil {
+		t.Fatal(err)
+	}
+	samples, err := ReadSamples(out, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) < 2 || len(samples) > 3 {
+		t.Fatalf("got %d samples after 3 polls", len(samples))
+	}
+	s := samples[0]
+	if s.Container != "ai_engine" || s.CPUPercent != 12.5 || s.MemBytes != 100<<20 || s.MemLimit != int64(7.5*(1<<30)) || s.NetRxBytes != 1500 || s.NetTxBytes != 648 {
+		t.Fatalf("sample = %+v", s)
+	}
+
+	// A torn last line is skipped; so are samples older than since.
+	f, _ := os.OpenFile(out, os.O_APPEND|os.O_WRONLY, 0)
+	f.WriteString(`{"time":"2099-01-01T00:00:00Z","contai`)
+	f.Close()
+	if again, err := ReadSamples(out, time.Time{}); err != nil || len(again) != len(samples) {
+		t.Fatalf("with torn line: %d samples, err = %v", len(again), err)
+	}
+	if recent, _ := ReadSamples(out, time.Now().Add(time.Hour)); len(recent) != 0 {
+		t.Fatalf("since filter kept %d samples", len(recent))
+	}
+}
+
+func TestCollectMetricsFailsWhenFirstPollFails(t *testing.T) {
+	old := statsCommand
+	statsCommand = func(context.Context) ([]byte, error) { return nil, errors.New("docker: not found") }
+	defer func() { statsCommand = old }()
+	err := CollectMetrics(context.Background(), nil, time.Second, filepath.Join(t.TempDir(), "m.jsonl"))
+	if err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestSummarizeAndWriteReport(t *testing.T) {
+	t0 := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	var samples []Sample
+	for i, cpu := range []float64{10, 20, 30, 40} {
+		samples = append(samples, Sample{
+			Time: t0.Add(time.Duration(i) * time.Minute), Container: "ai_engine", CPUPercent: cpu,
+			MemBytes: int64(100+i*10) * 1e6, NetRxBytes: []int64{1000, 3000, 500, 1500}[i], NetTxBytes: int64(i) * 100,
+		})
+	}
+	samples = append(samples, Sample{Time: t0, Container: "admin_ui", CPUPercent: 1, MemBytes: 50e6})
+
+	got := Summarize(samples)
+	if len(got) != 2 || got[0].Container != "admin_ui" || got[1].Container != "ai_engine" {
+		t.Fatalf("summaries = %+v", got)
+	}
+	ai := got[1]
+	// The rx counter dropped to 500 (a restart), so the window saw 2000 + 500 + 1000 bytes.
+	if ai.Samples != 4 || ai.CPUAvg != 25 || ai.CPUMax != 40 || ai.CPUTrend != 20 ||
+		ai.MemLast != 130e6 || ai.MemTrend != 30e6 || ai.MemMax != 130e6 || ai.NetRx != 3500 || ai.NetTx != 300 {
+		t.Fatalf("ai_engine = %+v", ai)
+	}
+
+	var b strings.Builder
+	if err := WriteReport(&b, got); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "CONTAINER") {
+		t.Fatalf("report:\n%s", b.String())
+	}
+	if f := strings.Fields(lines[2]); strings.Join(f, " ") != "ai_engine 4 25.0 40.0 +20.0 130 MB +30 MB 130 MB 3.5 KB 300 B" {
+		t.Fatalf("ai_engine row = %q", lines[2])
+	}
+}
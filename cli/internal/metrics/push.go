@@ -0,0 +1,122 @@
+// Package metrics pushes agent check results to a Prometheus Pushgateway in the text
+// exposition format, for batch jobs that have no endpoint to scrape, and records container
+// resource usage from docker stats to a local JSON Lines file for hosts without Prometheus.
+package metrics
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/check"
+)
+
+// DefaultJob is the Pushgateway job label agent check pushes under.
+const DefaultJob = "agent_check"
+
+// statuses are the values of the status label, one series each per item.
+var statuses = []check.Status{check.StatusPass, check.StatusWarn, check.StatusFail, check.StatusSkip, check.StatusInfo}
+
+// httpClient is replaced in tests.
+var httpClient = &http.Client{Timeout: 10 * time.Second}
+
+// PushMetrics POSTs report to <gatewayURL>/metrics/job/<jobName>/instance/<instance>, replacing
+// the metrics of the same names in that group. Per item it sends agent_check_item_status
+// (1 for the item's status, 0 for the others) and agent_check_duration_seconds, plus the
+// failing/warning counts and the run's timestamp.
+func PushMetrics(report *check.Report, gatewayURL, jobName, instance string) error {
+	if report == nil {
+		return errors.New("no report to push")
+	}
+	if jobName == "" {
+		return errors.New("job name is required")
+	}
+	target := strings.TrimRight(gatewayURL, "/") + "/metrics" + groupPath("job", jobName) + groupPath("instance", instance)
+
+	var body bytes.Buffer
+	Encode(&body, report)
+	req, err := http.NewRequest(http.MethodPost, target, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/plain; version=0.0.4")
+	req.Header.Set("User-Agent", "aava-agent-cli")
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to push metrics: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("pushgateway returned %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// Encode writes report in the Prometheus text format. Items that repeat an earlier item's
+// name are skipped, since a duplicate series makes the gateway reject the whole push.
+func Encode(w io.Writer, report *check.Report) {
+	failing, warning := 0, 0
+	var items []check.Item
+	seen := map[string]bool{}
+	for _, it := range report.Items {
+		switch it.Status {
+		case check.StatusFail:
+			failing++
+		case check.StatusWarn:
+			warning++
+		}
+		if !seen[it.Name] {
+			seen[it.Name] = true
+			items = append(items, it)
+		}
+	}
+
+	fmt.Fprintln(w, "# HELP agent_check_item_status Check item status: 1 for the item's current status, 0 for the others.")
+	fmt.Fprintln(w, "# TYPE agent_check_item_status gauge")
+	for _, it := range items {
+		for _, s := range statuses {
+			v := 0
+			if it.Status == s {
+				v = 1
+			}
+			fmt.Fprintf(w, "agent_check_item_status{name=\"%s\",status=\"%s\"} %d\n", escapeLabel(it.Name), s, v)
+		}
+	}
+	fmt.Fprintln(w, "# HELP agent_check_duration_seconds How long the check item took to run.")
+	fmt.Fprintln(w, "# TYPE agent_check_duration_seconds gauge")
+	for _, it := range items {
+		fmt.Fprintf(w, "agent_check_duration_seconds{name=\"%s\"} %g\n", escapeLabel(it.Name), it.Duration.Seconds())
+	}
+	fmt.Fprintln(w, "# HELP agent_check_failing Failed check items in the pushed run.")
+	fmt.Fprintln(w, "# TYPE agent_check_failing gauge")
+	fmt.Fprintf(w, "agent_check_failing %d\n", failing)
+	fmt.Fprintln(w, "# HELP agent_check_warning Warning check items in the pushed run.")
+	fmt.Fprintln(w, "# TYPE agent_check_warning gauge")
+	fmt.Fprintf(w, "agent_check_warning %d\n", warning)
+	fmt.Fprintln(w, "# HELP agent_check_last_run_timestamp_seconds When the pushed run started, in Unix time.")
+	fmt.Fprintln(w, "# TYPE agent_check_last_run_timestamp_seconds gauge")
+	fmt.Fprintf(w, "agent_check_last_run_timestamp_seconds %d\n", report.Timestamp.Unix())
+}
+
+func escapeLabel(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v)
+}
+
+// groupPath returns the "/label/value" URL segment of a grouping key. Values a path segment
+// cannot carry (empty, or containing '/') use the gateway's "/label@base64/<base64url>" form.
+func groupPath(label, v string) string {
+	switch {
+	case v == "":
+		return "/" + label + "@base64/="
+	case strings.Contains(v, "/"):
+		return "/" + label + "@base64/" + base64.URLEncoding.EncodeToString([]byte(v))
+	}
+	return "/" + label + "/" + url.PathEscape(v)
+}
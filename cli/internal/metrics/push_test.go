@@ -0,0 +1,88 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/check"
+)
+
+func testReport() *check.Report {
+	return &check.Report{
+		Timestamp: time.Unix(1767225600, 0),
+		Items: []check.Item{
+			{Name: "Docker", Status: check.StatusPass, Duration: 250 * time.Millisecond},
+			{Name: `ARI "remote"`, Status: check.StatusFail, Duration: 2 * time.Second},
+			{Name: "Docker", Status: check.StatusWarn},
+		},
+	}
+}
+
+func TestEncode(t *testing.T) {
+	var b strings.Builder
+	Encode(&b, testReport())
+	got := b.String()
+	for _, want := range []string{
+		"# TYPE agent_check_item_status gauge\n",
+		`agent_check_item_status{name="Docker",status="pass"} 1` + "\n",
+		`agent_check_item_status{name="Docker",status="fail"} 0` + "\n",
+		`agent_check_item_status{name="ARI \"remote\"",status="fail"} 1` + "\n",
+		`agent_check_duration_seconds{name="Docker"} 0.25` + "\n",
+		`agent_check_duration_seconds{name="ARI \"remote\""} 2` + "\n",
+		"agent_check_failing 1\n",
+		"agent_check_warning 1\n",
+		"agent_check_last_run_timestamp_seconds 1767225600\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("missing %q in:\n%s", want, got)
+		}
+	}
+	if n := strings.Count(got, `name="Docker",status="pass"`); n != 1 {
+		t.Fatalf("duplicate item pushed %d times:\n%s", n, got)
+	}
+}
+
+func TestPushMetrics(t *testing.T) {
+	var gotPath, gotMethod, gotType, gotBody string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		gotPath, gotMethod, gotType, gotBody = r.URL.EscapedPath(), r.Method, r.Header.Get("Content-Type"), string(data)
+	}))
+	defer srv.Close()
+
+	if err := PushMetrics(testReport(), srv.URL+"/", DefaultJob, "pbx-1"); err != nil {
+		t.Fatal(err)
+	}
+	if gotPath != "/metrics/job/agent_check/instance/pbx-1" || gotMethod != http.MethodPost {
+		t.Fatalf("%s %s", gotMethod, gotPath)
+	}
+	if !strings.HasPrefix(gotType, "text/plain; version=0.0.4") || !strings.Contains(gotBody, "agent_check_failing 1") {
+		t.Fatalf("content type %q, body:\n%s", gotType, gotBody)
+	}
+
+	if err := PushMetrics(testReport(), srv.URL, "nightly", "10.0.0.5:8088/ari"); err != nil {
+		t.Fatal(err)
+	}
+	if gotPath != "/metrics/job/nightly/instance@base64/MTAuMC4wLjU6ODA4OC9hcmk=" {
+		t.Fatalf("path = %s", gotPath)
+	}
+	if err := PushMetrics(testReport(), srv.URL, "nightly", ""); err != nil || gotPath != "/metrics/job/nightly/instance@base64/=" {
+		t.Fatalf("empty instance: %v, path = %s", err, gotPath)
+	}
+}
+
+func TestPushMetricsError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "text format parsing error in line 3", http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	err := PushMetrics(testReport(), srv.URL, DefaultJob, "pbx-1")
+	if err == nil || !strings.Contains(err.Error(), "400") || !strings.Contains(err.Error(), "parsing error") {
+		t.Fatalf("err = %v", err)
+	}
+}
@@ -0,0 +1,108 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/backup"
+)
+
+// ContainerSummary condenses one container's samples over a report window.
+type ContainerSummary struct {
+	Container   string
+	Samples     int
+	First, Last time.Time
+	CPUAvg      float64
+	CPUMax      float64
+	// CPUTrend is the average CPU% of the second half of the window minus that of the first.
+	CPUTrend float64
+	MemLast  int64
+	MemMax   int64
+	// MemTrend is the last memory sample minus the first.
+	MemTrend int64
+	// NetRx and NetTx are the bytes received and sent during the window.
+	NetRx, NetTx int64
+}
+
+// Summarize groups samples by container, sorted by name. Samples must be in time order, as
+// ReadSamples returns them.
+func Summarize(samples []Sample) []ContainerSummary {
+	byContainer := map[string][]Sample{}
+	for _, s := range samples {
+		byContainer[s.Container] = append(byContainer[s.Container], s)
+	}
+	out := make([]ContainerSummary, 0, len(byContainer))
+	for name, ss := range byContainer {
+		first, last := ss[0], ss[len(ss)-1]
+		sum := ContainerSummary{
+			Container: name,
+			Samples:   len(ss),
+			First:     first.Time,
+			Last:      last.Time,
+			MemLast:   last.MemBytes,
+			MemTrend:  last.MemBytes - first.MemBytes,
+			NetRx:     counterDelta(ss, func(s Sample) int64 { return s.NetRxBytes }),
+			NetTx:     counterDelta(ss, func(s Sample) int64 { return s.NetTxBytes }),
+		}
+		var total float64
+		for _, s := range ss {
+			total += s.CPUPercent
+			sum.CPUMax = max(sum.CPUMax, s.CPUPercent)
+			sum.MemMax = max(sum.MemMax, s.MemBytes)
+		}
+		sum.CPUAvg = total / float64(len(ss))
+		if half := len(ss) / 2; half > 0 {
+			sum.CPUTrend = avgCPU(ss[len(ss)-half:]) - avgCPU(ss[:half])
+		}
+		out = append(out, sum)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Container < out[j].Container })
+	return out
+}
+
+func avgCPU(ss []Sample) float64 {
+	var total float64
+	for _, s := range ss {
+		total += s.CPUPercent
+	}
+	return total / float64(len(ss))
+}
+
+// counterDelta sums the growth of a cumulative counter. A drop means the container restarted
+// and the counter began again from 0, so the new value counts in full.
+func counterDelta(ss []Sample, value func(Sample) int64) int64 {
+	var total int64
+	for i := 1; i < len(ss); i++ {
+		prev, cur := value(ss[i-1]), value(ss[i])
+		if cur >= prev {
+			total += cur - prev
+		} else {
+			total += cur
+		}
+	}
+	return total
+}
+
+// WriteReport prints summaries as a table: CPU% average, maximum and trend, memory at the
+// last sample with its change and peak, and network I/O during the window.
+func WriteReport(w io.Writer, summaries []ContainerSummary) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "CONTAINER\tSAMPLES\tCPU% AVG\tCPU% MAX\tCPU% TREND\tMEM\tMEM CHANGE\tMEM MAX\tNET RX\tNET TX")
+	for _, s := range summaries {
+		fmt.Fprintf(tw, "%s\t%d\t%.1f\t%.1f\t%+.1f\t%s\t%s\t%s\t%s\t%s\n",
+			s.Container, s.Samples, s.CPUAvg, s.CPUMax, s.CPUTrend,
+			backup.HumanBytes(s.MemLast), signedBytes(s.MemTrend), backup.HumanBytes(s.MemMax),
+			backup.HumanBytes(s.NetRx), backup.HumanBytes(s.NetTx))
+	}
+	return tw.Flush()
+}
+
+func signedBytes(n int64) string {
+	if n < 0 {
+		return "-" + backup.HumanBytes(-n)
+	}
+	return "+" + backup.HumanBytes(n)
+}
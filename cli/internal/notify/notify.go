@@ -0,0 +1,253 @@
+// Package notify posts agent check status transitions (all passing to failing and back) to a
+// Slack incoming webhook or a generic JSON webhook.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/check"
+)
+
+// Payload formats.
+const (
+	FormatSlack   = "slack"
+	FormatGeneric = "generic"
+)
+
+// Transition events.
+const (
+	EventAlert    = "alert"
+	EventRecovery = "recovery"
+)
+
+// Timeout bounds each webhook request.
+const Timeout = 10 * time.Second
+
+// responseLimit caps how much of a webhook response is read.
+const responseLimit = 4096
+
+// httpClient is replaced in tests.
+var httpClient = &http.Client{Timeout: Timeout}
+
+// formats caches DetectFormat per webhook URL, so a watch loop probes each webhook once.
+var formats sync.Map
+
+// ChangedItem is a check whose status differs from the previous report.
+type ChangedItem struct {
+	Name           string       `json:"name"`
+	Status         check.Status `json:"status"`
+	PreviousStatus check.Status `json:"previous_status"`
+	Message        string       `json:"message,omitempty"`
+}
+
+// GenericPayload is the body posted to webhooks that are not Slack-compatible.
+type GenericPayload struct {
+	Text         string        `json:"text"`
+	Timestamp    string        `json:"timestamp"`
+	Event        string        `json:"event"`
+	Summary      string        `json:"summary"`
+	FailCount    int           `json:"fail_count"`
+	WarnCount    int           `json:"warn_count"`
+	ChangedItems []ChangedItem `json:"changed_items"`
+}
+
+type slackPayload struct {
+	Text        string            `json:"text"`
+	Attachments []slackAttachment `json:"attachments,omitempty"`
+}
+
+type slackAttachment struct {
+	Color    string       `json:"color"`
+	Fallback string       `json:"fallback"`
+	Fields   []slackField `json:"fields,omitempty"`
+	Ts       int64        `json:"ts"`
+}
+
+type slackField struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+	Short bool   `json:"short"`
+}
+
+// Transition reports whether going from last to current is an alert (no failures before,
+// some now) or a recovery (the reverse). A nil last report is no transition.
+func Transition(last, current *check.Report) (event string, ok bool) {
+	if last == nil || current == nil {
+		return "", false
+	}
+	before, now := failures(last), failures(current)
+	switch {
+	case before == 0 && now > 0:
+		return EventAlert, true
+	case before > 0 && now == 0:
+		return EventRecovery, true
+	}
+	return "", false
+}
+
+// failures counts the failing items; FailCount is only set once a report is finalized.
+func failures(r *check.Report) int {
+	n := 0
+	for _, item := range r.Items {
+		if item.Status == check.StatusFail {
+			n++
+		}
+	}
+	return n
+}
+
+// NotifyOnTransition posts to webhook when the failure count goes from 0 to more than 0
+// (alert) or back to 0 (recovery); otherwise it does nothing. The payload carries the check
+// summary, the items whose status changed and the report timestamp, in Slack
+// incoming-webhook form or as GenericPayload (see DetectFormat).
+func NotifyOnTransition(webhook string, lastReport, currentReport *check.Report) error {
+	event, ok := Transition(lastReport, currentReport)
+	if !ok {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*Timeout)
+	defer cancel()
+	format, err := DetectFormat(ctx, webhook)
+	if err != nil {
+		return err
+	}
+	body, err := buildPayload(format, event, lastReport, currentReport)
+	if err != nil {
+		return err
+	}
+	return post(ctx, webhook, body)
+}
+
+// DetectFormat returns FormatSlack for hooks.slack.com URLs. For other URLs it POSTs an empty
+// JSON object once and returns FormatSlack when the response mentions the channel field, or
+// carries Slack's no_text/invalid_payload errors, as Slack-compatible receivers (Mattermost,
+// Rocket.Chat) do; otherwise FormatGeneric. The result is cached per URL.
+func DetectFormat(ctx context.Context, webhook string) (string, error) {
+	if f, ok := formats.Load(webhook); ok {
+		return f.(string), nil
+	}
+	u, err := url.Parse(webhook)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("invalid webhook URL %q", redact(webhook))
+	}
+	format := FormatGeneric
+	if strings.EqualFold(u.Hostname(), "hooks.slack.com") {
+		format = FormatSlack
+	} else {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook, strings.NewReader("{}"))
+		if err != nil {
+			return "", err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return "", requestError(webhook, err)
+		}
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, responseLimit))
+		resp.Body.Close()
+		reply := strings.ToLower(string(data))
+		if strings.Contains(reply, "channel") || strings.Contains(reply, "no_text") || strings.Contains(reply, "invalid_payload") {
+			format = FormatSlack
+		}
+	}
+	formats.Store(webhook, format)
+	return format, nil
+}
+
+func buildPayload(format, event string, last, current *check.Report) ([]byte, error) {
+	changed := changedItems(last, current)
+	summary := current.Summarize()
+	text := "agent check: " + summary
+	if event == EventAlert {
+		text = "🔴 " + text
+	} else {
+		text = "✅ " + text + " (recovered)"
+	}
+
+	if format == FormatGeneric {
+		return json.Marshal(GenericPayload{
+			Text:         text,
+			Timestamp:    current.Timestamp.Format(time.RFC3339),
+			Event:        event,
+			Summary:      summary,
+			FailCount:    current.FailCount,
+			WarnCount:    current.WarnCount,
+			ChangedItems: changed,
+		})
+	}
+
+	color := "danger"
+	if event == EventRecovery {
+		color = "good"
+	}
+	att := slackAttachment{Color: color, Fallback: text, Ts: current.Timestamp.Unix()}
+	for _, item := range changed {
+		att.Fields = append(att.Fields, slackField{
+			Title: fmt.Sprintf("%s: %s → %s", item.Name, item.PreviousStatus, item.Status),
+			Value: item.Message,
+		})
+	}
+	return json.Marshal(slackPayload{Text: text, Attachments: []slackAttachment{att}})
+}
+
+// changedItems lists the items of current whose status differs from last (see
+// check.Report.CompareWith), without modifying current.
+func changedItems(last, current *check.Report) []ChangedItem {
+	cmp := *current
+	cmp.CompareWith(last)
+	out := make([]ChangedItem, 0, len(cmp.ChangedItems))
+	for _, item := range cmp.ChangedItems {
+		out = append(out, ChangedItem{Name: item.Name, Status: item.Status, PreviousStatus: item.PreviousStatus, Message: item.Message})
+	}
+	return out
+}
+
+func post(ctx context.Context, webhook string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return requestError(webhook, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, responseLimit))
+		msg := strings.TrimSpace(string(data))
+		if msg != "" {
+			return fmt.Errorf("webhook %s returned HTTP %d: %s", redact(webhook), resp.StatusCode, msg)
+		}
+		return fmt.Errorf("webhook %s returned HTTP %d", redact(webhook), resp.StatusCode)
+	}
+	return nil
+}
+
+// requestError drops the URL from transport errors: webhook URLs embed their secret token.
+func requestError(webhook string, err error) error {
+	var ue *url.Error
+	if errors.As(err, &ue) {
+		err = ue.Err
+	}
+	return fmt.Errorf("webhook %s: %w", redact(webhook), err)
+}
+
+// redact keeps the scheme and host of a webhook URL.
+func redact(webhook string) string {
+	u, err := url.Parse(webhook)
+	if err != nil || u.Host == "" {
+		return "(invalid URL)"
+	}
+	return u.Scheme + "://" + u.Host + "/…"
+}
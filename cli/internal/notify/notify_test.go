@@ -0,0 +1,146 @@
+package notify
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/check"
+)
+
+func report(statuses ...check.Status) *check.Report {
+	rep := &check.Report{Timestamp: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)}
+	names := []string{"Docker", "ARI", "Config"}
+	for i, s := range statuses {
+		rep.Items = append(rep.Items, check.Item{Name: names[i], Status: s, Message: "msg " + names[i]})
+	}
+	return rep
+}
+
+// recorder is a webhook that records every body and answers with reply.
+type recorder struct {
+	mu     sync.Mutex
+	bodies []string
+	reply  string
+}
+
+func (rec *recorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	data, _ := io.ReadAll(r.Body)
+	rec.mu.Lock()
+	rec.bodies = append(rec.bodies, string(data))
+	rec.mu.Unlock()
+	if string(data) == "{}" && rec.reply != "" {
+		w.WriteHeader(http.StatusBadRequest)
+		io.WriteString(w, rec.reply)
+	}
+}
+
+func TestTransition(t *testing.T) {
+	pass := report(check.StatusPass, check.StatusWarn)
+	fail := report(check.StatusPass, check.StatusFail)
+	for _, tc := range []struct {
+		last, cur *check.Report
+		want      string
+	}{
+		{nil, fail, ""},
+		{pass, pass, ""},
+		{fail, fail, ""},
+		{pass, fail, EventAlert},
+		{fail, pass, EventRecovery},
+	} {
+		got, _ := Transition(tc.last, tc.cur)
+		if got != tc.want {
+			t.Errorf("Transition = %q, want %q", got, tc.want)
+		}
+	}
+}
+
+func TestNotifyOnTransitionGeneric(t *testing.T) {
+	rec := &recorder{}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	last := report(check.StatusPass, check.StatusPass)
+	if err := NotifyOnTransition(srv.URL, last, last); err != nil {
+		t.Fatal(err)
+	}
+	if len(rec.bodies) != 0 {
+		t.Fatalf("posted without a transition: %q", rec.bodies)
+	}
+
+	cur := report(check.StatusPass, check.StatusFail)
+	if err := NotifyOnTransition(srv.URL, last, cur); err != nil {
+		t.Fatal(err)
+	}
+	if len(rec.bodies) != 2 || rec.bodies[0] != "{}" {
+		t.Fatalf("want probe then payload, got %q", rec.bodies)
+	}
+	var got GenericPayload
+	if err := json.Unmarshal([]byte(rec.bodies[1]), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Event != EventAlert || got.Timestamp != "2026-03-01T12:00:00Z" || got.FailCount != 1 || !strings.Contains(got.Text, "1 failure") {
+		t.Fatalf("payload = %+v", got)
+	}
+	if len(got.ChangedItems) != 1 || got.ChangedItems[0].Name != "ARI" || got.ChangedItems[0].PreviousStatus != check.StatusPass {
+		t.Fatalf("changed items = %+v", got.ChangedItems)
+	}
+	if cur.ChangedItems != nil {
+		t.Fatal("NotifyOnTransition modified the current report")
+	}
+
+	// The format is cached: recovery posts without probing again.
+	if err := NotifyOnTransition(srv.URL, cur, last); err != nil {
+		t.Fatal(err)
+	}
+	if len(rec.bodies) != 3 || !strings.Contains(rec.bodies[2], `"event":"recovery"`) {
+		t.Fatalf("bodies = %q", rec.bodies)
+	}
+}
+
+func TestNotifyOnTransitionSlackCompatible(t *testing.T) {
+	rec := &recorder{reply: `{"message":"Unable to find channel"}`}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	if err := NotifyOnTransition(srv.URL, report(check.StatusPass), report(check.StatusFail)); err != nil {
+		t.Fatal(err)
+	}
+	var got slackPayload
+	if err := json.Unmarshal([]byte(rec.bodies[len(rec.bodies)-1]), &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Attachments) != 1 || got.Attachments[0].Color != "danger" || len(got.Attachments[0].Fields) != 1 {
+		t.Fatalf("slack payload = %+v", got)
+	}
+	if f := got.Attachments[0].Fields[0]; f.Title != "Docker: pass → fail" || f.Value != "msg Docker" {
+		t.Fatalf("field = %+v", f)
+	}
+}
+
+func TestNotifyErrorsHideWebhookToken(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		if string(data) != "{}" {
+			http.Error(w, "bad token", http.StatusForbidden)
+		}
+	}))
+	defer srv.Close()
+
+	webhook := srv.URL + "/hooks/secret-token"
+	err := NotifyOnTransition(webhook, report(check.StatusPass), report(check.StatusFail))
+	if err == nil || !strings.Contains(err.Error(), "HTTP 403") || strings.Contains(err.Error(), "secret-token") {
+		t.Fatalf("err = %v", err)
+	}
+
+	srv.Close()
+	err = NotifyOnTransition(srv.URL+"/other/secret-token", report(check.StatusPass), report(check.StatusFail))
+	if err == nil || strings.Contains(err.Error(), "secret-token") {
+		t.Fatalf("err = %v", err)
+	}
+}
@@ -0,0 +1,287 @@
+// Package reload signals ai_engine to reload its configuration after each valid config change,
+// for agent config watch-reload.
+package reload
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/check"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/environment"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/maputil"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/watch"
+)
+
+const (
+	// DefaultService is the Compose service that reloads its config on SIGHUP.
+	DefaultService = "ai_engine"
+	// LogWindow is how long the engine log is polled for the reload result after the signal.
+	LogWindow = 5 * time.Second
+	// logPoll is the pause between docker compose logs calls within LogWindow.
+	logPoll = 500 * time.Millisecond
+)
+
+// Log messages the engine writes when a reload ends (src/engine.py, _reload_config).
+const (
+	reloadCompleted = "Configuration reload completed"
+	reloadFailed    = "Configuration reload failed"
+)
+
+// signalNames maps the signals the reloader can send to the names docker compose kill takes.
+// Names rather than numbers, since numbers differ between the host OS and the container.
+var signalNames = map[os.Signal]string{
+	syscall.SIGHUP:  "SIGHUP",
+	syscall.SIGINT:  "SIGINT",
+	syscall.SIGQUIT: "SIGQUIT",
+	syscall.SIGTERM: "SIGTERM",
+	syscall.SIGKILL: "SIGKILL",
+}
+
+// Reloader sends Signal to the Compose service Service after each burst of writes to the YAML
+// files under Root/config, once every changed file passes validation.
+type Reloader struct {
+	Root    string
+	Service string
+	Signal  os.Signal
+	// Env selects the environment (see the environment package); only used to find the
+	// event source's .env, which is not itself watched for reloads.
+	Env string
+	// NoValidate signals even when a changed file fails validation.
+	NoValidate bool
+	// Debounce is how long the config must stay quiet before a reload (watch.DefaultDebounce
+	// when zero).
+	Debounce time.Duration
+	Out      io.Writer
+
+	// kill and logs run docker compose; tests replace them (and shorten logWindow).
+	kill      func(ctx context.Context, dir, service, signal string) error
+	logs      func(ctx context.Context, dir, service string, since time.Time) (string, error)
+	logWindow time.Duration
+}
+
+// WatchAndReload watches root/config and sends sig to containerName (a Compose service) after
+// each valid change, reporting the engine's reload result on stdout. It returns nil on SIGINT
+// or SIGTERM.
+func WatchAndReload(root string, containerName string, sig os.Signal) error {
+	r := &Reloader{Root: root, Service: containerName, Signal: sig, Out: os.Stdout}
+	return r.Run()
+}
+
+// Run watches until SIGINT or SIGTERM.
+func (r *Reloader) Run() error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if _, err := r.signalName(); err != nil {
+		return err
+	}
+	src, err := watch.NewEventSource(r.Root, environment.Path(".env", r.Env))
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	return r.loop(ctx, src)
+}
+
+// ParseSignal returns the signal named name ("SIGHUP", "hup" or "HUP").
+func ParseSignal(name string) (os.Signal, error) {
+	want := strings.ToUpper(strings.TrimSpace(name))
+	if !strings.HasPrefix(want, "SIG") {
+		want = "SIG" + want
+	}
+	for sig, n := range signalNames {
+		if n == want {
+			return sig, nil
+		}
+	}
+	return nil, fmt.Errorf("unsupported reload signal %q", name)
+}
+
+func (r *Reloader) signalName() (string, error) {
+	name, ok := signalNames[r.Signal]
+	if !ok {
+		return "", fmt.Errorf("unsupported reload signal %v", r.Signal)
+	}
+	return name, nil
+}
+
+func (r *Reloader) loop(ctx context.Context, src watch.EventSource) error {
+	if r.Out == nil {
+		r.Out = io.Discard
+	}
+	if r.Service == "" {
+		r.Service = DefaultService
+	}
+	debounce := r.Debounce
+	if debounce <= 0 {
+		debounce = watch.DefaultDebounce
+	}
+	sigName, err := r.signalName()
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(r.Out, "Watching config/ for changes; %s gets %s after each valid save (Ctrl-C to stop)\n", r.Service, sigName)
+	pending := map[string]bool{}
+	var timer <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err, open := <-src.Errors():
+			if !open {
+				return nil
+			}
+			fmt.Fprintf(r.Out, "watch error: %v\n", err)
+		case rel, open := <-src.Events():
+			if !open {
+				return nil
+			}
+			if !reloadable(rel) {
+				continue
+			}
+			pending[rel] = true
+			timer = time.After(debounce)
+		case <-timer:
+			timer = nil
+			changed := maputil.SortedKeys(pending)
+			pending = map[string]bool{}
+			r.handle(ctx, changed, sigName)
+		}
+	}
+}
+
+// reloadable reports whether a change to rel (slash-separated, relative to the repo root)
+// affects the engine: YAML under config/, except deleted contexts and temp files.
+func reloadable(rel string) bool {
+	ext := path.Ext(rel)
+	return strings.HasPrefix(rel, "config/") &&
+		(ext == ".yaml" || ext == ".yml") &&
+		!strings.HasPrefix(rel, "config/contexts/.deleted/")
+}
+
+// handle validates the changed files, sends the signal and reports the engine's reload result.
+func (r *Reloader) handle(ctx context.Context, changed []string, sigName string) {
+	stamp := time.Now().Format("15:04:05")
+	fmt.Fprintf(r.Out, "%s changed: %s\n", stamp, strings.Join(changed, ", "))
+	if !r.NoValidate {
+		if bad := invalidFiles(r.Root, changed); len(bad) > 0 {
+			for _, msg := range bad {
+				fmt.Fprintf(r.Out, "  ✗ %s\n", msg)
+			}
+			fmt.Fprintf(r.Out, "  not reloading: fix the file(s) above, or pass --no-validate\n")
+			return
+		}
+	}
+
+	kill, logs := r.kill, r.logs
+	if kill == nil {
+		kill = composeKill
+	}
+	if logs == nil {
+		logs = composeLogs
+	}
+	window := r.logWindow
+	if window <= 0 {
+		window = LogWindow
+	}
+	since := time.Now()
+	if err := kill(ctx, r.Root, r.Service, sigName); err != nil {
+		fmt.Fprintf(r.Out, "  ✗ %v\n", err)
+		return
+	}
+	deadline := time.Now().Add(window)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(logPoll):
+		}
+		out, err := logs(ctx, r.Root, r.Service, since)
+		if err == nil {
+			if line, ok := findLine(out, reloadFailed); ok {
+				fmt.Fprintf(r.Out, "  ✗ reload failed: %s\n", line)
+				return
+			}
+			if line, ok := findLine(out, reloadCompleted); ok {
+				fmt.Fprintf(r.Out, "  ✓ reloaded: %s\n", line)
+				return
+			}
+		}
+		if time.Now().After(deadline) {
+			msg := fmt.Sprintf("no reload confirmation in the %s log within %s", r.Service, window)
+			if err != nil {
+				msg += fmt.Sprintf(" (%v)", err)
+			}
+			fmt.Fprintf(r.Out, "  ? %s; check agent logs %s\n", msg, r.Service)
+			return
+		}
+	}
+}
+
+// invalidFiles validates each changed file that still exists as check --fix would and
+// returns one message per failure.
+func invalidFiles(root string, changed []string) []string {
+	var bad []string
+	for _, rel := range changed {
+		p := filepath.Join(root, filepath.FromSlash(rel))
+		if _, err := os.Stat(p); err != nil {
+			continue // renamed or removed since the event
+		}
+		if check.HasConflictMarkers(p) {
+			bad = append(bad, rel+": contains git conflict markers")
+			continue
+		}
+		if err := check.ValidateYAMLMapping(p); err != nil {
+			bad = append(bad, fmt.Sprintf("%s: %v", rel, err))
+		}
+	}
+	return bad
+}
+
+// findLine returns the last line of out containing msg.
+func findLine(out, msg string) (string, bool) {
+	lines := strings.Split(strings.TrimRight(out, "\n"), "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		if strings.Contains(lines[i], msg) {
+			return strings.TrimSpace(lines[i]), true
+		}
+	}
+	return "", false
+}
+
+func composeKill(ctx context.Context, dir, service, sig string) error {
+	cmd := exec.CommandContext(ctx, "docker", "compose", "kill", "--signal", sig, service)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("docker compose kill: %w: %s", err, msg)
+		}
+		return fmt.Errorf("docker compose kill: %w", err)
+	}
+	return nil
+}
+
+func composeLogs(ctx context.Context, dir, service string, since time.Time) (string, error) {
+	cmd := exec.CommandContext(ctx, "docker", "compose", "logs", "--no-color", "--no-log-prefix", "--since", since.UTC().Format(time.RFC3339Nano), service)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("docker compose logs: %w: %s", err, msg)
+		}
+		return "", fmt.Errorf("docker compose logs: %w", err)
+	}
+	return string(out), nil
+}
@@ -0,0 +1,180 @@
+package reload
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+)
+
+type fakeSource struct {
+	events chan string
+	errs   chan error
+}
+
+func (f *fakeSource) Events() <-chan string { return f.events }
+func (f *fakeSource) Errors() <-chan error  { return f.errs }
+func (f *fakeSource) Close() error          { return nil }
+
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf strings.Builder
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// start runs r.loop with a fake source and returns the source and a stop func.
+func start(t *testing.T, r *Reloader) (*fakeSource, func()) {
+	t.Helper()
+	src := &fakeSource{events: make(chan string), errs: make(chan error)}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- r.loop(ctx, src) }()
+	return src, func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func waitFor(t *testing.T, out *syncBuffer, want string) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !strings.Contains(out.String(), want) {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %q in:\n%s", want, out.String())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestReloadAfterValidChange(t *testing.T) {
+	root := t.TempDir()
+	os.MkdirAll(filepath.Join(root, "config"), 0o755)
+	os.WriteFile(filepath.Join(root, "config", "ai-agent.local.yaml"), []byte("llm:\n  model: x\n"), 0o644)
+
+	kills := make(chan string, 5)
+	out := &syncBuffer{}
+	r := &Reloader{
+		Root: root, Service: "ai_engine", Signal: syscall.SIGHUP, Out: out,
+		Debounce: 10 * time.Millisecond,
+		kill: func(ctx context.Context, dir, service, sig string) error {
+			kills <- service + " " + sig
+			return nil
+		},
+		logs: func(ctx context.Context, dir, service string, since time.Time) (string, error) {
+			return "{\"event\": \"🔄 Configuration reload requested\"}\n{\"event\": \"✅ Configuration reload completed\"}\n", nil
+		},
+	}
+	src, stop := start(t, r)
+	defer stop()
+
+	src.events <- ".env" // not reloadable
+	src.events <- "config/ai-agent.local.yaml"
+	src.events <- "config/ai-agent.local.yaml"
+	waitFor(t, out, "✓ reloaded")
+	if got := <-kills; got != "ai_engine SIGHUP" {
+		t.Errorf("kill = %q", got)
+	}
+	if len(kills) != 0 {
+		t.Errorf("a burst of writes should signal once, got %d more", len(kills))
+	}
+	if strings.Contains(out.String(), ".env") {
+		t.Errorf(".env changes must not trigger a reload:\n%s", out.String())
+	}
+}
+
+func TestInvalidChangeIsNotReloaded(t *testing.T) {
+	root := t.TempDir()
+	os.MkdirAll(filepath.Join(root, "config", "contexts"), 0o755)
+	os.WriteFile(filepath.Join(root, "config", "contexts", "sales.yaml"), []byte("name: [unterminated\n"), 0o644)
+
+	var killed int
+	var mu sync.Mutex
+	out := &syncBuffer{}
+	r := &Reloader{
+		Root: root, Signal: syscall.SIGHUP, Out: out, Debounce: 10 * time.Millisecond,
+		kill: func(ctx context.Context, dir, service, sig string) error {
+			mu.Lock()
+			killed++
+			mu.Unlock()
+			return nil
+		},
+		logs: func(ctx context.Context, dir, service string, since time.Time) (string, error) {
+			return "Configuration reload failed error=boom\n", nil
+		},
+	}
+	src, stop := start(t, r)
+	src.events <- "config/contexts/sales.yaml"
+	waitFor(t, out, "not reloading")
+
+	r.NoValidate = true // only read by the loop goroutine after the next event
+	src.events <- "config/contexts/sales.yaml"
+	waitFor(t, out, "✗ reload failed: Configuration reload failed error=boom")
+	stop()
+	if killed != 1 {
+		t.Errorf("killed %d times, want 1 (only with --no-validate)", killed)
+	}
+}
+
+func TestReloadWithoutConfirmation(t *testing.T) {
+	root := t.TempDir()
+	out := &syncBuffer{}
+	r := &Reloader{
+		Root: root, Signal: syscall.SIGHUP, Out: out, Debounce: 10 * time.Millisecond, logWindow: 50 * time.Millisecond,
+		kill: func(ctx context.Context, dir, service, sig string) error { return nil },
+		logs: func(ctx context.Context, dir, service string, since time.Time) (string, error) { return "", nil },
+	}
+	src, stop := start(t, r)
+	defer stop()
+	src.events <- "config/ai-agent.yaml" // removed before validation: nothing to validate
+	waitFor(t, out, "? no reload confirmation in the ai_engine log")
+}
+
+func TestParseSignal(t *testing.T) {
+	for _, name := range []string{"SIGHUP", "hup", " HUP "} {
+		if sig, err := ParseSignal(name); err != nil || sig != syscall.SIGHUP {
+			t.Errorf("ParseSignal(%q) = %v, %v", name, sig, err)
+		}
+	}
+	if _, err := ParseSignal("SIGUSR9"); err == nil {
+		t.Error("expected an error for an unknown signal name")
+	}
+}
+
+func TestUnsupportedSignal(t *testing.T) {
+	r := &Reloader{Signal: os.Signal(nil)}
+	if _, err := r.signalName(); err == nil {
+		t.Fatal("expected an error for an unknown signal")
+	}
+}
+
+func TestReloadable(t *testing.T) {
+	for rel, want := range map[string]bool{
+		"config/ai-agent.yaml":              true,
+		"config/contexts/sales.yml":         true,
+		"config/contexts/.deleted/old.yaml": false,
+		"config/ai-agent.yaml.tmp":          false,
+		"config/users.json":                 false,
+		".env":                              false,
+	} {
+		if got := reloadable(rel); got != want {
+			t.Errorf("reloadable(%q) = %v, want %v", rel, got, want)
+		}
+	}
+}
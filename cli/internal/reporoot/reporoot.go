@@ -0,0 +1,55 @@
+// Package reporoot locates the Asterisk AI Voice Agent checkout without relying on git.
+package reporoot
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ComposeFiles are the Compose file names that, next to ConfigFile, mark a repo root.
+var ComposeFiles = []string{"docker-compose.yml", "docker-compose.yaml"}
+
+// ConfigFile is the base engine config every checkout ships, relative to the repo root.
+const ConfigFile = "config/ai-agent.yaml"
+
+// ErrNotFound is returned by Find when no directory up to the filesystem root has the markers.
+var ErrNotFound = errors.New("repository root not found")
+
+// IsRepoRoot reports whether dir holds a Compose file and config/ai-agent.yaml.
+func IsRepoRoot(dir string) bool {
+	if !isFile(filepath.Join(dir, filepath.FromSlash(ConfigFile))) {
+		return false
+	}
+	for _, name := range ComposeFiles {
+		if isFile(filepath.Join(dir, name)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Find walks up from start to the filesystem root and returns the first directory for which
+// IsRepoRoot is true, as an absolute path.
+func Find(start string) (string, error) {
+	dir, err := filepath.Abs(start)
+	if err != nil {
+		return "", err
+	}
+	for {
+		if IsRepoRoot(dir) {
+			return dir, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", fmt.Errorf("%w: no %s with %s in %s or any parent directory", ErrNotFound, ComposeFiles[0], ConfigFile, start)
+		}
+		dir = parent
+	}
+}
+
+func isFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular()
+}
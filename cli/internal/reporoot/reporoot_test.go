@@ -0,0 +1,48 @@
+package reporoot
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func touch(t *testing.T, path string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFindWalksUpToMarkers(t *testing.T) {
+	root := t.TempDir()
+	touch(t, filepath.Join(root, "docker-compose.yaml"))
+	touch(t, filepath.Join(root, "config", "ai-agent.yaml"))
+	deep := filepath.Join(root, "config", "contexts", "nested")
+	if err := os.MkdirAll(deep, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	// A compose file alone (e.g. a sub-project) is not a match.
+	touch(t, filepath.Join(root, "config", "contexts", "docker-compose.yml"))
+
+	for _, start := range []string{root, deep} {
+		got, err := Find(start)
+		if err != nil || got != root {
+			t.Fatalf("Find(%s) = %q, %v; want %s", start, got, err, root)
+		}
+	}
+}
+
+func TestFindStopsAtFilesystemRoot(t *testing.T) {
+	dir := t.TempDir()
+	touch(t, filepath.Join(dir, "config", "ai-agent.yaml")) // no compose file
+	if _, err := Find(dir); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("err = %v, want ErrNotFound", err)
+	}
+	if IsRepoRoot(filepath.Join(dir, "missing")) {
+		t.Fatal("missing directory reported as repo root")
+	}
+}
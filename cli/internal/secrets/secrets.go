@@ -0,0 +1,121 @@
+// Package secrets encrypts the repo .env with age, for agent env encrypt and decrypt, and
+// reads the encrypted form back into memory without writing plaintext to disk. It also
+// encrypts individual YAML values in place (ENC[aes256gcm,...], agent config encrypt-secrets).
+package secrets
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/health"
+)
+
+const (
+	// EncryptedEnvFile is the encrypted .env, relative to the repo root.
+	EncryptedEnvFile = ".env.age"
+	// RecipientEnv holds the age recipient (public key, "age1...") agent env encrypt uses
+	// when --recipient is not given.
+	RecipientEnv = "AGENT_ENV_RECIPIENT"
+	// IdentityFileEnv points at the age identity file that decrypts .env.age.
+	IdentityFileEnv = "AGENT_ENV_IDENTITY_FILE"
+)
+
+// ErrNoIdentity is returned when .env.age has to be decrypted and no identity file is set.
+var ErrNoIdentity = errors.New("no age identity file (set " + IdentityFileEnv + ")")
+
+// runAge runs the age CLI and returns its stdout; it writes the same format as
+// filippo.io/age. Tests replace it.
+var runAge = func(args ...string) ([]byte, error) {
+	cmd := exec.Command("age", args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := bytes.TrimSpace(stderr.Bytes()); len(msg) > 0 {
+			return nil, fmt.Errorf("age: %w: %s", err, msg)
+		}
+		return nil, fmt.Errorf("age: %w", err)
+	}
+	return stdout.Bytes(), nil
+}
+
+// EncryptEnv encrypts plainPath to recipientKey and writes the result to encPath (mode
+// 0600). encPath is replaced only once encryption succeeded; plainPath is left alone.
+func EncryptEnv(plainPath, encPath, recipientKey string) error {
+	recipientKey = strings.TrimSpace(recipientKey)
+	if recipientKey == "" {
+		return errors.New("no age recipient key")
+	}
+	return writeVia(encPath, func(tmp string) error {
+		_, err := runAge("-r", recipientKey, "-o", tmp, plainPath)
+		return err
+	})
+}
+
+// DecryptEnv decrypts encPath with the age identity in identityFile and writes the plaintext
+// to plainPath (mode 0600). encPath is left alone.
+func DecryptEnv(encPath, plainPath, identityFile string) error {
+	if identityFile == "" {
+		return ErrNoIdentity
+	}
+	return writeVia(plainPath, func(tmp string) error {
+		_, err := runAge("-d", "-i", identityFile, "-o", tmp, encPath)
+		return err
+	})
+}
+
+// LoadEncryptedEnv decrypts encPath with identityFile and parses it like a .env file. The
+// plaintext only exists in memory.
+func LoadEncryptedEnv(encPath, identityFile string) (map[string]string, error) {
+	if identityFile == "" {
+		return nil, ErrNoIdentity
+	}
+	out, err := runAge("-d", "-i", identityFile, encPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt %s: %w", encPath, err)
+	}
+	return health.ParseEnv(bytes.NewReader(out))
+}
+
+// LoadEnv reads the .env at envPath, or, when it does not exist and envPath+".age" does,
+// decrypts that in memory with the identity file named by AGENT_ENV_IDENTITY_FILE. It
+// reports which file was read.
+func LoadEnv(envPath string) (map[string]string, string, error) {
+	if _, err := os.Stat(envPath); err != nil && os.IsNotExist(err) {
+		encPath := envPath + ".age"
+		if _, err := os.Stat(encPath); err == nil {
+			values, err := LoadEncryptedEnv(encPath, os.Getenv(IdentityFileEnv))
+			return values, encPath, err
+		}
+	}
+	values, err := health.LoadEnvFile(envPath)
+	return values, envPath, err
+}
+
+// writeVia has write produce a temp file next to dst and renames it over dst, so a failed
+// run never leaves dst truncated.
+func writeVia(dst string, write func(tmp string) error) error {
+	f, err := os.CreateTemp(filepath.Dir(dst), "."+filepath.Base(dst)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	f.Close()
+	defer os.Remove(tmp)
+
+	if err := write(tmp); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp, 0o600); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, dst); err != nil {
+		return fmt.Errorf("failed to write %s: %w", dst, err)
+	}
+	return nil
+}
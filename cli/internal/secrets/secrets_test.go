@@ -0,0 +1,153 @@
+package secrets
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeAge stands in for the age CLI: "encryption" prefixes the recipient line, decryption
+// strips it. Anything decrypted to stdout is recorded in stdoutReads.
+func fakeAge(t *testing.T) *int {
+	t.Helper()
+	orig := runAge
+	t.Cleanup(func() { runAge = orig })
+	stdoutReads := 0
+	runAge = func(args ...string) ([]byte, error) {
+		var out string
+		decrypt := false
+		for i := 0; i < len(args)-1; i++ {
+			switch args[i] {
+			case "-d":
+				decrypt = true
+			case "-o":
+				out = args[i+1]
+				i++
+			case "-r", "-i":
+				i++
+			}
+		}
+		data, err := os.ReadFile(args[len(args)-1])
+		if err != nil {
+			return nil, err
+		}
+		if decrypt {
+			header, body, ok := strings.Cut(string(data), "\n")
+			if !ok || !strings.HasPrefix(header, "age1") {
+				return nil, errors.New("no identity matched any of the recipients")
+			}
+			data = []byte(body)
+		} else {
+			data = append([]byte(args[1]+"\n"), data...)
+		}
+		if out == "" {
+			stdoutReads++
+			return data, nil
+		}
+		return nil, os.WriteFile(out, data, 0o644)
+	}
+	return &stdoutReads
+}
+
+func TestEncryptDecryptEnvRoundTrip(t *testing.T) {
+	fakeAge(t)
+	dir := t.TempDir()
+	plain := filepath.Join(dir, ".env")
+	enc := filepath.Join(dir, EncryptedEnvFile)
+	if err := os.WriteFile(plain, []byte("ASTERISK_HOST=10.0.0.5\nOPENAI_API_KEY=sk-test\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := EncryptEnv(plain, enc, " age1recipient "); err != nil {
+		t.Fatalf("EncryptEnv: %v", err)
+	}
+	info, err := os.Stat(enc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	out := filepath.Join(dir, "decrypted.env")
+	if err := DecryptEnv(enc, out, "identity.txt"); err != nil {
+		t.Fatalf("DecryptEnv: %v", err)
+	}
+	got, _ := os.ReadFile(out)
+	if string(got) != "ASTERISK_HOST=10.0.0.5\nOPENAI_API_KEY=sk-test\n" {
+		t.Errorf("decrypted = %q", got)
+	}
+}
+
+func TestEncryptEnvRequiresRecipient(t *testing.T) {
+	fakeAge(t)
+	dir := t.TempDir()
+	if err := EncryptEnv(filepath.Join(dir, ".env"), filepath.Join(dir, EncryptedEnvFile), "  "); err == nil {
+		t.Fatal("expected an error without a recipient")
+	}
+}
+
+func TestDecryptEnvFailureKeepsExistingFile(t *testing.T) {
+	fakeAge(t)
+	dir := t.TempDir()
+	enc := filepath.Join(dir, EncryptedEnvFile)
+	plain := filepath.Join(dir, ".env")
+	os.WriteFile(enc, []byte("garbage"), 0o600)
+	os.WriteFile(plain, []byte("KEEP=1\n"), 0o600)
+
+	if err := DecryptEnv(enc, plain, "identity.txt"); err == nil {
+		t.Fatal("expected a decryption error")
+	}
+	if got, _ := os.ReadFile(plain); string(got) != "KEEP=1\n" {
+		t.Errorf(".env = %q, want it untouched", got)
+	}
+	if err := DecryptEnv(enc, plain, ""); !errors.Is(err, ErrNoIdentity) {
+		t.Errorf("err = %v, want ErrNoIdentity", err)
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 2 {
+		t.Errorf("temp files left behind: %v", entries)
+	}
+}
+
+func TestLoadEnvPrefersPlainFile(t *testing.T) {
+	reads := fakeAge(t)
+	dir := t.TempDir()
+	plain := filepath.Join(dir, ".env")
+	os.WriteFile(plain, []byte("ASTERISK_HOST=plain\n"), 0o600)
+	os.WriteFile(plain+".age", []byte("age1x\nASTERISK_HOST=encrypted\n"), 0o600)
+
+	values, from, err := LoadEnv(plain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if from != plain || values["ASTERISK_HOST"] != "plain" || *reads != 0 {
+		t.Errorf("LoadEnv = %v from %s (%d decrypts), want the plain file", values, from, *reads)
+	}
+}
+
+func TestLoadEnvDecryptsInMemory(t *testing.T) {
+	reads := fakeAge(t)
+	dir := t.TempDir()
+	plain := filepath.Join(dir, ".env")
+	os.WriteFile(plain+".age", []byte("age1x\n# comment\nASTERISK_HOST=encrypted\n"), 0o600)
+	t.Setenv(IdentityFileEnv, filepath.Join(dir, "identity.txt"))
+
+	values, from, err := LoadEnv(plain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if from != plain+".age" || values["ASTERISK_HOST"] != "encrypted" || *reads != 1 {
+		t.Errorf("LoadEnv = %v from %s (%d decrypts)", values, from, *reads)
+	}
+	if _, err := os.Stat(plain); !os.IsNotExist(err) {
+		t.Errorf("plaintext .env written to disk: %v", err)
+	}
+
+	t.Setenv(IdentityFileEnv, "")
+	if _, _, err := LoadEnv(plain); !errors.Is(err, ErrNoIdentity) {
+		t.Errorf("err = %v, want ErrNoIdentity", err)
+	}
+}
@@ -0,0 +1,312 @@
+package secrets
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// KeyFile holds the key for inline YAML secrets, relative to the repo root.
+const KeyFile = ".agent/keyfile"
+
+// DefaultSecretAnnotations are the key names agent config encrypt-secrets encrypts by default.
+var DefaultSecretAnnotations = []string{"password", "api_key", "secret", "token"}
+
+const (
+	encPrefix = "ENC[aes256gcm,"
+	encSuffix = "]"
+	// keyContext separates the inline-secret key from any other use of the key file contents.
+	keyContext = "asterisk-ai-voice-agent inline yaml secrets v1\x00"
+)
+
+// ErrNoKeyFile is returned when an ENC[...] value has to be decrypted and there is no key file.
+var ErrNoKeyFile = errors.New("no key file for ENC[...] values (" + KeyFile + ")")
+
+// GenerateKeyFile writes 32 random bytes, base64-encoded, to path (mode 0600). An existing
+// file is kept; created reports whether one was written.
+func GenerateKeyFile(path string) (created bool, err error) {
+	if _, err := os.Stat(path); err == nil {
+		return false, nil
+	}
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return false, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return false, err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return false, err
+	}
+	if _, err := f.WriteString(base64.StdEncoding.EncodeToString(raw) + "\n"); err != nil {
+		f.Close()
+		return false, err
+	}
+	return true, f.Close()
+}
+
+// FindKeyFile returns KeyFile under the nearest directory at or above dir that has one,
+// or "" when there is none.
+func FindKeyFile(dir string) string {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	for {
+		p := filepath.Join(dir, filepath.FromSlash(KeyFile))
+		if fi, err := os.Stat(p); err == nil && !fi.IsDir() {
+			return p
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// deriveKey returns the AES-256 key for keyFile: SHA-256 over a fixed context string and the
+// file contents (surrounding whitespace ignored).
+func deriveKey(keyFile string) ([]byte, error) {
+	data, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, fmt.Errorf("%s is empty", keyFile)
+	}
+	sum := sha256.Sum256(append([]byte(keyContext), data...))
+	return sum[:], nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// IsEncryptedValue reports whether s is an ENC[aes256gcm,...] value.
+func IsEncryptedValue(s string) bool {
+	return strings.HasPrefix(s, encPrefix) && strings.HasSuffix(s, encSuffix)
+}
+
+func encryptValue(aead cipher.AEAD, plain string) (string, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(plain), nil)
+	return encPrefix + base64.StdEncoding.EncodeToString(sealed) + encSuffix, nil
+}
+
+func decryptValue(aead cipher.AEAD, s string) (string, error) {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSuffix(strings.TrimPrefix(s, encPrefix), encSuffix))
+	if err != nil || len(raw) < aead.NonceSize() {
+		return "", errors.New("malformed ENC[...] value")
+	}
+	plain, err := aead.Open(nil, raw[:aead.NonceSize()], raw[aead.NonceSize():], nil)
+	if err != nil {
+		return "", errors.New("cannot decrypt ENC[...] value (wrong key file?)")
+	}
+	return string(plain), nil
+}
+
+// matchesAnnotation reports whether the YAML key name is one of annotations, or ends in
+// _<annotation> (so "token" matches auth_token but not max_tokens). Case is ignored.
+func matchesAnnotation(name string, annotations []string) bool {
+	name = strings.ToLower(name)
+	for _, a := range annotations {
+		a = strings.ToLower(strings.TrimSpace(a))
+		if a != "" && (name == a || strings.HasSuffix(name, "_"+a)) {
+			return true
+		}
+	}
+	return false
+}
+
+// EncryptYAMLSecrets rewrites path with the string value of every key matching annotations
+// (see matchesAnnotation) replaced by ENC[aes256gcm,<base64 nonce+ciphertext>], encrypted
+// with AES-256-GCM under a key derived from keyFile. Comments and layout are kept. Values
+// that are already encrypted, empty, or ${VAR} references are left alone, and the file is
+// not rewritten when nothing matched.
+func EncryptYAMLSecrets(path string, keyFile string, annotations []string) error {
+	key, err := deriveKey(keyFile)
+	if err != nil {
+		return err
+	}
+	aead, err := newGCM(key)
+	if err != nil {
+		return err
+	}
+	return rewriteYAMLScalars(path, func(name string, n *yaml.Node) (bool, error) {
+		if !matchesAnnotation(name, annotations) || n.Value == "" || IsEncryptedValue(n.Value) || strings.HasPrefix(n.Value, "${") {
+			return false, nil
+		}
+		enc, err := encryptValue(aead, n.Value)
+		if err != nil {
+			return false, err
+		}
+		n.Value, n.Tag, n.Style = enc, "!!str", 0
+		return true, nil
+	})
+}
+
+// DecryptYAMLSecrets rewrites path with every ENC[...] value decrypted with keyFile; the
+// inverse of EncryptYAMLSecrets.
+func DecryptYAMLSecrets(path string, keyFile string) error {
+	key, err := deriveKey(keyFile)
+	if err != nil {
+		return err
+	}
+	aead, err := newGCM(key)
+	if err != nil {
+		return err
+	}
+	return rewriteYAMLScalars(path, func(name string, n *yaml.Node) (bool, error) {
+		if !IsEncryptedValue(n.Value) {
+			return false, nil
+		}
+		plain, err := decryptValue(aead, n.Value)
+		if err != nil {
+			return false, fmt.Errorf("%s (line %d): %w", name, n.Line, err)
+		}
+		n.Value, n.Tag, n.Style = plain, "!!str", 0
+		var asPlain any
+		if err := yaml.Unmarshal([]byte(plain), &asPlain); err != nil || asPlain != plain {
+			// Quote it so it stays a string (a password 1234 must not become a number).
+			n.Style = yaml.DoubleQuotedStyle
+		}
+		return true, nil
+	})
+}
+
+// DecryptValues replaces the ENC[...] strings anywhere in v (as decoded from YAML) with their
+// plaintext, in place. Without any ENC[...] value keyFile is not read; with one and no
+// keyFile it returns ErrNoKeyFile.
+func DecryptValues(v any, keyFile string) error {
+	var aead cipher.AEAD
+	var walk func(v any) (any, error)
+	walk = func(v any) (any, error) {
+		switch t := v.(type) {
+		case map[string]any:
+			for k, child := range t {
+				nv, err := walk(child)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", k, err)
+				}
+				t[k] = nv
+			}
+		case []any:
+			for i, child := range t {
+				nv, err := walk(child)
+				if err != nil {
+					return nil, err
+				}
+				t[i] = nv
+			}
+		case string:
+			if !IsEncryptedValue(t) {
+				return t, nil
+			}
+			if aead == nil {
+				if keyFile == "" {
+					return nil, ErrNoKeyFile
+				}
+				key, err := deriveKey(keyFile)
+				if err != nil {
+					return nil, err
+				}
+				if aead, err = newGCM(key); err != nil {
+					return nil, err
+				}
+			}
+			return decryptValue(aead, t)
+		}
+		return v, nil
+	}
+	_, err := walk(v)
+	return err
+}
+
+// rewriteYAMLScalars calls fn for each scalar mapping value in path, with its key name, and
+// writes the file back (same mode) when fn changed any.
+func rewriteYAMLScalars(path string, fn func(name string, n *yaml.Node) (bool, error)) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	changed := false
+	var walk func(n *yaml.Node) error
+	walk = func(n *yaml.Node) error {
+		if n.Kind == yaml.MappingNode {
+			for i := 0; i+1 < len(n.Content); i += 2 {
+				k, v := n.Content[i], n.Content[i+1]
+				if v.Kind == yaml.ScalarNode && (v.Tag == "!!str" || v.Tag == "!!int" || v.Tag == "!!float") {
+					ok, err := fn(k.Value, v)
+					if err != nil {
+						return err
+					}
+					changed = changed || ok
+					continue
+				}
+				if err := walk(v); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		for _, c := range n.Content {
+			if err := walk(c); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := walk(&doc); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if !changed {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return err
+	}
+	if err := enc.Close(); err != nil {
+		return err
+	}
+	mode := os.FileMode(0o644)
+	if fi, err := os.Stat(path); err == nil {
+		mode = fi.Mode().Perm()
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), mode); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package secrets
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const secretsYAML = `# providers
+providers:
+  openai:
+    api_key: ${OPENAI_API_KEY}
+    max_tokens: 200
+  custom:
+    api_key: sk-live-123 # prod key
+    auth_token: abc
+    pin_password: 1234
+asterisk:
+  password: s3cr3t
+  username: asterisk
+`
+
+func writeSecretsFixture(t *testing.T) (path, keyFile string) {
+	t.Helper()
+	dir := t.TempDir()
+	keyFile = filepath.Join(dir, filepath.FromSlash(KeyFile))
+	if created, err := GenerateKeyFile(keyFile); err != nil || !created {
+		t.Fatalf("GenerateKeyFile: created=%v err=%v", created, err)
+	}
+	path = filepath.Join(dir, "ai-agent.yaml")
+	if err := os.WriteFile(path, []byte(secretsYAML), 0o640); err != nil {
+		t.Fatal(err)
+	}
+	return path, keyFile
+}
+
+func TestEncryptDecryptYAMLSecretsRoundTrip(t *testing.T) {
+	path, keyFile := writeSecretsFixture(t)
+	if err := EncryptYAMLSecrets(path, keyFile, DefaultSecretAnnotations); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	enc := string(data)
+	if n := strings.Count(enc, "ENC[aes256gcm,"); n != 4 {
+		t.Fatalf("want 4 encrypted values, got %d:\n%s", n, enc)
+	}
+	for _, plain := range []string{"sk-live-123", "s3cr3t", ": abc", "1234"} {
+		if strings.Contains(enc, plain) {
+			t.Fatalf("%q left in plaintext:\n%s", plain, enc)
+		}
+	}
+	for _, kept := range []string{"# providers", "# prod key", "${OPENAI_API_KEY}", "max_tokens: 200", "username: asterisk"} {
+		if !strings.Contains(enc, kept) {
+			t.Fatalf("%q lost:\n%s", kept, enc)
+		}
+	}
+	if fi, _ := os.Stat(path); fi.Mode().Perm() != 0o640 {
+		t.Fatalf("mode changed to %v", fi.Mode().Perm())
+	}
+
+	// Encrypting again changes nothing.
+	if err := EncryptYAMLSecrets(path, keyFile, DefaultSecretAnnotations); err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := os.ReadFile(path); string(again) != enc {
+		t.Fatal("already encrypted values were encrypted again")
+	}
+
+	if err := DecryptYAMLSecrets(path, keyFile); err != nil {
+		t.Fatal(err)
+	}
+	data, _ = os.ReadFile(path)
+	for _, want := range []string{"api_key: sk-live-123 # prod key", "auth_token: abc", `pin_password: "1234"`, "password: s3cr3t"} {
+		if !strings.Contains(string(data), want) {
+			t.Fatalf("missing %q after decrypt:\n%s", want, data)
+		}
+	}
+}
+
+func TestDecryptValues(t *testing.T) {
+	path, keyFile := writeSecretsFixture(t)
+	if err := EncryptYAMLSecrets(path, keyFile, []string{"password"}); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	var encrypted string
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.Contains(line, "  password: ") {
+			encrypted = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "password:"))
+		}
+	}
+	if !IsEncryptedValue(encrypted) {
+		t.Fatalf("password not encrypted:\n%s", data)
+	}
+
+	tree := map[string]any{"asterisk": map[string]any{"password": encrypted}, "list": []any{encrypted, 7}}
+	if err := DecryptValues(tree, keyFile); err != nil {
+		t.Fatal(err)
+	}
+	if tree["asterisk"].(map[string]any)["password"] != "s3cr3t" || tree["list"].([]any)[0] != "s3cr3t" {
+		t.Fatalf("tree = %#v", tree)
+	}
+
+	if err := DecryptValues(map[string]any{"p": encrypted}, ""); !errors.Is(err, ErrNoKeyFile) {
+		t.Fatalf("want ErrNoKeyFile, got %v", err)
+	}
+	otherKey := filepath.Join(t.TempDir(), "keyfile")
+	if _, err := GenerateKeyFile(otherKey); err != nil {
+		t.Fatal(err)
+	}
+	if err := DecryptValues(map[string]any{"p": encrypted}, otherKey); err == nil || !strings.Contains(err.Error(), "wrong key file") {
+		t.Fatalf("want a wrong-key error, got %v", err)
+	}
+}
+
+func TestMatchesAnnotation(t *testing.T) {
+	for name, want := range map[string]bool{
+		"password": true, "ARI_PASSWORD": true, "auth_token": true, "api_key": true,
+		"max_tokens": false, "passwords": false, "keyword": false,
+	} {
+		if got := matchesAnnotation(name, DefaultSecretAnnotations); got != want {
+			t.Errorf("matchesAnnotation(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
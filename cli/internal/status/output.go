@@ -0,0 +1,106 @@
+package status
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/fatih/color"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/check"
+)
+
+// ExitCode follows agent check (0 pass, 1 warn, 2 fail); a degraded service counts as a
+// warning, since optional services such as local_ai_server may be stopped on purpose.
+func (r *StatusReport) ExitCode() int {
+	code := 0
+	for _, item := range r.Items {
+		if c := item.Status.ExitCode(); c > code {
+			code = c
+		}
+	}
+	if code == 0 && len(r.DegradedServices()) > 0 {
+		code = 1
+	}
+	return code
+}
+
+// OutputJSON writes the check report fields plus services as one JSON object.
+func (r *StatusReport) OutputJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// OutputText writes services and check items as a single table. Degraded services and
+// failing checks are shown in red, warnings in yellow.
+func (r *StatusReport) OutputText(w io.Writer, showServices, showChecks bool) {
+	red := color.New(color.FgRed, color.Bold)
+	yellow := color.New(color.FgYellow)
+
+	// Colour whole rows after tabwriter has aligned them: escape codes in a cell would skew
+	// its width.
+	var buf bytes.Buffer
+	tw := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "KIND\tNAME\tSTATUS\tDETAILS")
+	rowColors := []*color.Color{nil}
+	if showServices {
+		for _, s := range r.Services {
+			state := s.State
+			if s.Health != "" {
+				state += " (" + s.Health + ")"
+			}
+			details := s.Image
+			if s.Ports != "" {
+				details += "  " + s.Ports
+			}
+			fmt.Fprintf(tw, "service\t%s\t%s\t%s\n", s.Name, state, details)
+			if s.Degraded() {
+				rowColors = append(rowColors, red)
+			} else {
+				rowColors = append(rowColors, nil)
+			}
+		}
+	}
+	if showChecks {
+		for _, item := range r.Items {
+			fmt.Fprintf(tw, "check\t%s\t%s\t%s\n", item.Name, strings.ToUpper(string(item.Status)), firstLine(item.Message))
+			switch item.Status {
+			case check.StatusFail:
+				rowColors = append(rowColors, red)
+			case check.StatusWarn:
+				rowColors = append(rowColors, yellow)
+			default:
+				rowColors = append(rowColors, nil)
+			}
+		}
+	}
+	_ = tw.Flush()
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	for i, line := range lines {
+		if i < len(rowColors) && rowColors[i] != nil {
+			line = rowColors[i].Sprint(line)
+		}
+		fmt.Fprintln(w, line)
+	}
+
+	fmt.Fprintln(w)
+	if showServices {
+		if r.ServicesError != "" {
+			fmt.Fprintf(w, "Services: unavailable (%s)\n", r.ServicesError)
+		} else {
+			fmt.Fprintf(w, "Services: %d, %d unhealthy or exited\n", len(r.Services), len(r.DegradedServices()))
+		}
+	}
+	if showChecks {
+		fmt.Fprintf(w, "Checks: %d pass, %d warn, %d fail\n", r.PassCount, r.WarnCount, r.FailCount)
+	}
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
@@ -0,0 +1,178 @@
+// Package status combines docker compose ps with the agent check report for agent status.
+package status
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/check"
+)
+
+// ServiceStatus is one Compose service container as reported by docker compose ps.
+type ServiceStatus struct {
+	Name   string `json:"name"`
+	Image  string `json:"image"`
+	State  string `json:"state"`
+	Health string `json:"health,omitempty"`
+	Ports  string `json:"ports,omitempty"`
+}
+
+// Degraded reports whether the service is exited, dead or failing its healthcheck.
+func (s ServiceStatus) Degraded() bool {
+	switch strings.ToLower(s.State) {
+	case "exited", "dead":
+		return true
+	}
+	return strings.EqualFold(s.Health, "unhealthy")
+}
+
+// StatusReport is the agent check report plus the state of each Compose service.
+type StatusReport struct {
+	check.Report
+	Services []ServiceStatus `json:"services"`
+	// ServicesError is set when docker compose ps could not be run.
+	ServicesError string `json:"services_error,omitempty"`
+}
+
+// DegradedServices returns the services for which Degraded is true.
+func (r *StatusReport) DegradedServices() []ServiceStatus {
+	var out []ServiceStatus
+	for _, s := range r.Services {
+		if s.Degraded() {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// composePS runs docker compose ps in root; tests replace it.
+var composePS = func(ctx context.Context, root string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "docker", "compose", "ps", "--all", "--format", "json")
+	cmd.Dir = root
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("docker compose ps: %w: %s", err, msg)
+		}
+		return nil, fmt.Errorf("docker compose ps: %w", err)
+	}
+	return out, nil
+}
+
+// RunStatusReport runs runner's diagnostics and lists the Compose services of the project in
+// root. With a nil runner only the services are collected. A docker compose ps failure is
+// recorded in ServicesError (Docker problems already show up as failing checks) unless there
+// are no checks to report it, in which case it is returned. As with check.Runner.Run, the
+// report is returned alongside a timeout or "agent check failed" error.
+func RunStatusReport(root string, runner *check.Runner) (*StatusReport, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), check.DefaultTimeout)
+	defer cancel()
+
+	rep := &StatusReport{Report: check.Report{Items: []check.Item{}}, Services: []ServiceStatus{}}
+	var runErr error
+	if runner != nil {
+		checks, err := runner.Run(ctx)
+		if checks == nil {
+			return nil, err
+		}
+		rep.Report = *checks
+		runErr = err
+	}
+
+	out, err := composePS(ctx, root)
+	if err == nil {
+		rep.Services, err = ParseComposePS(out)
+	}
+	if err != nil {
+		if runner == nil {
+			return nil, err
+		}
+		rep.ServicesError = err.Error()
+	}
+	return rep, runErr
+}
+
+type composeEntry struct {
+	Name       string `json:"Name"`
+	Service    string `json:"Service"`
+	Image      string `json:"Image"`
+	State      string `json:"State"`
+	Health     string `json:"Health"`
+	Ports      string `json:"Ports"`
+	Publishers []struct {
+		URL           string `json:"URL"`
+		TargetPort    int    `json:"TargetPort"`
+		PublishedPort int    `json:"PublishedPort"`
+		Protocol      string `json:"Protocol"`
+	} `json:"Publishers"`
+}
+
+// ParseComposePS parses docker compose ps --format json, which prints a JSON array on
+// Compose < 2.21 and one object per line after that. Services are sorted by name.
+func ParseComposePS(out []byte) ([]ServiceStatus, error) {
+	var entries []composeEntry
+	trimmed := bytes.TrimSpace(out)
+	if bytes.HasPrefix(trimmed, []byte("[")) {
+		if err := json.Unmarshal(trimmed, &entries); err != nil {
+			return nil, fmt.Errorf("failed to parse docker compose ps output: %w", err)
+		}
+	} else {
+		for _, line := range strings.Split(string(trimmed), "\n") {
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+			var e composeEntry
+			if err := json.Unmarshal([]byte(line), &e); err != nil {
+				return nil, fmt.Errorf("failed to parse docker compose ps output: %w", err)
+			}
+			entries = append(entries, e)
+		}
+	}
+
+	services := make([]ServiceStatus, 0, len(entries))
+	for _, e := range entries {
+		name := e.Service
+		if name == "" {
+			name = e.Name
+		}
+		services = append(services, ServiceStatus{
+			Name:   name,
+			Image:  e.Image,
+			State:  e.State,
+			Health: e.Health,
+			Ports:  e.ports(),
+		})
+	}
+	sort.SliceStable(services, func(i, j int) bool { return services[i].Name < services[j].Name })
+	return services, nil
+}
+
+// ports formats published ports like docker ps ("0.0.0.0:8088->8088/tcp"); host-network
+// services (the usual ai_engine setup) have none.
+func (e composeEntry) ports() string {
+	if len(e.Publishers) == 0 {
+		return e.Ports
+	}
+	seen := map[string]bool{}
+	var ports []string
+	for _, p := range e.Publishers {
+		var s string
+		if p.PublishedPort == 0 {
+			s = fmt.Sprintf("%d/%s", p.TargetPort, p.Protocol)
+		} else {
+			s = fmt.Sprintf("%s:%d->%d/%s", p.URL, p.PublishedPort, p.TargetPort, p.Protocol)
+		}
+		if !seen[s] {
+			seen[s] = true
+			ports = append(ports, s)
+		}
+	}
+	return strings.Join(ports, ", ")
+}
@@ -0,0 +1,119 @@
+package status
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/check"
+)
+
+const psLines = `{"Name":"ai_engine","Service":"ai_engine","Image":"asterisk-ai-voice-agent-ai_engine","State":"running","Health":"healthy","Publishers":null}
+{"Name":"admin_ui","Service":"admin_ui","Image":"admin-ui:latest","State":"running","Health":"unhealthy","Publishers":[{"URL":"0.0.0.0","TargetPort":3003,"PublishedPort":3003,"Protocol":"tcp"},{"URL":"::","TargetPort":3003,"PublishedPort":3003,"Protocol":"tcp"}]}
+{"Name":"local_ai_server","Service":"local_ai_server","Image":"local-ai:latest","State":"exited","Health":""}
+`
+
+func TestParseComposePS(t *testing.T) {
+	services, err := ParseComposePS([]byte(psLines))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, s := range services {
+		names = append(names, s.Name)
+	}
+	if strings.Join(names, ",") != "admin_ui,ai_engine,local_ai_server" {
+		t.Fatalf("names = %v", names)
+	}
+	if got := services[0].Ports; got != "0.0.0.0:3003->3003/tcp, :::3003->3003/tcp" {
+		t.Fatalf("ports = %q", got)
+	}
+
+	array := `[{"Service":"ai_engine","State":"running","Ports":"8088/tcp"}]`
+	services, err = ParseComposePS([]byte(array))
+	if err != nil || len(services) != 1 || services[0].Ports != "8088/tcp" {
+		t.Fatalf("array form: %+v %v", services, err)
+	}
+	if _, err := ParseComposePS([]byte("{not json")); err == nil {
+		t.Fatal("expected a parse error")
+	}
+}
+
+func TestDegraded(t *testing.T) {
+	cases := []struct {
+		s    ServiceStatus
+		want bool
+	}{
+		{ServiceStatus{State: "running", Health: "healthy"}, false},
+		{ServiceStatus{State: "running"}, false},
+		{ServiceStatus{State: "running", Health: "unhealthy"}, true},
+		{ServiceStatus{State: "exited"}, true},
+		{ServiceStatus{State: "restarting"}, false},
+	}
+	for _, c := range cases {
+		if got := c.s.Degraded(); got != c.want {
+			t.Errorf("%+v Degraded() = %v, want %v", c.s, got, c.want)
+		}
+	}
+}
+
+func fakeComposePS(t *testing.T, out string, err error) {
+	t.Helper()
+	saved := composePS
+	t.Cleanup(func() { composePS = saved })
+	composePS = func(ctx context.Context, root string) ([]byte, error) {
+		return []byte(out), err
+	}
+}
+
+func TestRunStatusReportServicesOnly(t *testing.T) {
+	fakeComposePS(t, psLines, nil)
+	rep, err := RunStatusReport(t.TempDir(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rep.Services) != 3 || len(rep.Items) != 0 {
+		t.Fatalf("unexpected report: %+v", rep)
+	}
+	if len(rep.DegradedServices()) != 2 || rep.ExitCode() != 1 {
+		t.Fatalf("degraded = %+v, exit = %d", rep.DegradedServices(), rep.ExitCode())
+	}
+
+	fakeComposePS(t, "", errors.New("docker: not found"))
+	if _, err := RunStatusReport(t.TempDir(), nil); err == nil {
+		t.Fatal("expected docker compose ps error without checks")
+	}
+}
+
+func TestOutputTextHighlightsDegradedServices(t *testing.T) {
+	services, err := ParseComposePS([]byte(psLines))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rep := &StatusReport{
+		Report:   check.Report{Items: []check.Item{{Name: "Docker Daemon", Status: check.StatusPass, Message: "Docker running"}}, PassCount: 1},
+		Services: services,
+	}
+	var buf bytes.Buffer
+	rep.OutputText(&buf, true, false)
+	out := buf.String()
+	if !strings.Contains(out, "local_ai_server") || strings.Contains(out, "Docker Daemon") {
+		t.Fatalf("services-only table:\n%s", out)
+	}
+	if !strings.Contains(out, "2 unhealthy or exited") {
+		t.Fatalf("missing summary:\n%s", out)
+	}
+
+	buf.Reset()
+	rep.OutputText(&buf, true, true)
+	lines := strings.Split(buf.String(), "\n")
+	if !strings.HasPrefix(lines[0], "KIND") || !strings.HasPrefix(lines[4], "check") {
+		t.Fatalf("services should precede checks in one table:\n%s", buf.String())
+	}
+	// Columns are aligned across services and checks.
+	if strings.Index(lines[1], "running") != strings.Index(lines[4], "PASS") {
+		t.Fatalf("columns not aligned:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,77 @@
+package status
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrNotHealthy is wrapped by WaitForDockerServiceHealthy when the services did not become
+// healthy in time.
+var ErrNotHealthy = errors.New("services not healthy")
+
+// MaxHealthWait caps WaitForDockerServiceHealthy when ctx has no earlier deadline.
+const MaxHealthWait = 60 * time.Second
+
+// WaitForDockerServiceHealthy polls docker compose ps in the current directory every poll
+// until each of services is running and healthy (or has no healthcheck, so Health is empty).
+// It gives up at ctx's deadline or after MaxHealthWait, whichever comes first, and the error
+// then lists the state last seen for every service that was not ready.
+func WaitForDockerServiceHealthy(ctx context.Context, services []string, poll time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, MaxHealthWait)
+	defer cancel()
+	if poll <= 0 {
+		poll = time.Second
+	}
+	ticker := time.NewTicker(poll)
+	defer ticker.Stop()
+
+	var last string
+	for {
+		out, err := composePS(ctx, "")
+		if err == nil {
+			var svcs []ServiceStatus
+			if svcs, err = ParseComposePS(out); err == nil {
+				pending := notReady(svcs, services)
+				if len(pending) == 0 {
+					return nil
+				}
+				last = strings.Join(pending, ", ")
+			}
+		}
+		if err != nil && ctx.Err() == nil {
+			last = err.Error()
+		}
+		select {
+		case <-ctx.Done():
+			if last == "" {
+				last = "no state observed"
+			}
+			return fmt.Errorf("%w: %w (last seen: %s)", ErrNotHealthy, ctx.Err(), last)
+		case <-ticker.C:
+		}
+	}
+}
+
+// notReady describes each of services that is missing from svcs, not running, or not healthy.
+func notReady(svcs []ServiceStatus, services []string) []string {
+	byName := make(map[string]ServiceStatus, len(svcs))
+	for _, s := range svcs {
+		byName[s.Name] = s
+	}
+	var pending []string
+	for _, name := range services {
+		s, ok := byName[name]
+		switch {
+		case !ok:
+			pending = append(pending, name+" not created")
+		case !strings.EqualFold(s.State, "running"):
+			pending = append(pending, name+" "+s.State)
+		case s.Health != "" && !strings.EqualFold(s.Health, "healthy"):
+			pending = append(pending, name+" running ("+s.Health+")")
+		}
+	}
+	return pending
+}
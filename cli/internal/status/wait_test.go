@@ -0,0 +1,53 @@
+package status
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWaitForDockerServiceHealthyPollsUntilHealthy(t *testing.T) {
+	steps := []string{
+		`{"Service":"ai_engine","State":"created","Health":""}`,
+		`{"Service":"ai_engine","State":"running","Health":"starting"}
+{"Service":"admin_ui","State":"running","Health":""}`,
+		`{"Service":"ai_engine","State":"running","Health":"healthy"}
+{"Service":"admin_ui","State":"running","Health":""}`,
+	}
+	calls := 0
+	saved := composePS
+	t.Cleanup(func() { composePS = saved })
+	composePS = func(ctx context.Context, root string) ([]byte, error) {
+		out := steps[min(calls, len(steps)-1)]
+		calls++
+		return []byte(out), nil
+	}
+
+	if err := WaitForDockerServiceHealthy(context.Background(), []string{"ai_engine", "admin_ui"}, time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 3 {
+		t.Fatalf("docker compose ps calls = %d, want 3", calls)
+	}
+}
+
+func TestWaitForDockerServiceHealthyReportsLastState(t *testing.T) {
+	fakeComposePS(t, psLines, nil)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := WaitForDockerServiceHealthy(ctx, []string{"ai_engine", "admin_ui", "local_ai_server", "tts"}, time.Millisecond)
+	if !errors.Is(err, ErrNotHealthy) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want ErrNotHealthy wrapping the deadline", err)
+	}
+	for _, want := range []string{"admin_ui running (unhealthy)", "local_ai_server exited", "tts not created"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %q", err, want)
+		}
+	}
+	if strings.Contains(err.Error(), "ai_engine") {
+		t.Errorf("healthy ai_engine reported as pending: %v", err)
+	}
+}
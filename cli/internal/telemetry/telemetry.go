@@ -0,0 +1,199 @@
+// Package telemetry sends opt-in, anonymized agent check statistics: how many checks passed,
+// warned and failed, which built-in checks did, and the OS, architecture and CLI version. It
+// never sends messages, details, .env values or host names.
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/check"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/reporoot"
+)
+
+const (
+	// Env enables telemetry when set to 1/true/yes/on in .env. It is off by default.
+	Env = "AGENT_TELEMETRY"
+	// EndpointEnv is the URL payloads are POSTed to.
+	EndpointEnv = "AGENT_TELEMETRY_ENDPOINT"
+	// InstallIDPath holds the random installation ID, relative to the repo root.
+	InstallIDPath = ".agent/install-id"
+	// SchemaVersion is bumped whenever Payload changes shape.
+	SchemaVersion = 1
+)
+
+// httpClient is replaced in tests.
+var httpClient = &http.Client{Timeout: 10 * time.Second}
+
+// ItemStatus is the outcome of one built-in check, without its message or details.
+type ItemStatus struct {
+	Name   string       `json:"name"`
+	Status check.Status `json:"status"`
+}
+
+// Payload is everything telemetry sends for one agent check run.
+type Payload struct {
+	SchemaVersion int    `json:"schema_version"`
+	InstallID     string `json:"install_id"`
+	Version       string `json:"version"`
+	OS            string `json:"os"`
+	Arch          string `json:"arch"`
+	PassCount     int    `json:"pass_count"`
+	WarnCount     int    `json:"warn_count"`
+	FailCount     int    `json:"fail_count"`
+	SkipCount     int    `json:"skip_count"`
+	InfoCount     int    `json:"info_count"`
+	// CustomCount is the number of declarative and plugin checks in the run. Operators name
+	// those, so they are counted above but left out of Items.
+	CustomCount int          `json:"custom_count"`
+	Items       []ItemStatus `json:"items"`
+}
+
+// Enabled reports whether Env turns telemetry on in envMap.
+func Enabled(envMap map[string]string) bool {
+	switch strings.ToLower(check.EnvValue(envMap[Env])) {
+	case "1", "true", "yes", "on":
+		return true
+	}
+	return false
+}
+
+// Endpoint returns EndpointEnv from envMap, or "" when it is unset.
+func Endpoint(envMap map[string]string) string {
+	return check.EnvValue(envMap[EndpointEnv])
+}
+
+type repoRootKey struct{}
+
+// WithRepoRoot returns a context telling SendTelemetry which repo root holds InstallIDPath.
+// Without it, the repo root is searched for from the working directory.
+func WithRepoRoot(ctx context.Context, dir string) context.Context {
+	return context.WithValue(ctx, repoRootKey{}, dir)
+}
+
+func repoRoot(ctx context.Context) (string, error) {
+	if dir, _ := ctx.Value(repoRootKey{}).(string); dir != "" {
+		return dir, nil
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	return reporoot.Find(wd)
+}
+
+// InstallID returns the installation ID in repoRoot's InstallIDPath, creating it with 16 random
+// bytes on first use. The ID is not derived from anything about the host.
+func InstallID(repoRoot string) (string, error) {
+	path := filepath.Join(repoRoot, filepath.FromSlash(InstallIDPath))
+	if b, err := os.ReadFile(path); err == nil {
+		if id := strings.TrimSpace(string(b)); validID(id) {
+			return id, nil
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return "", err
+	}
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	id := hex.EncodeToString(buf)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, []byte(id+"\n"), 0o644); err != nil {
+		return "", fmt.Errorf("failed to save installation ID: %w", err)
+	}
+	return id, nil
+}
+
+func validID(id string) bool {
+	_, err := hex.DecodeString(id)
+	return err == nil && len(id) == 32
+}
+
+// NewPayload condenses report into what telemetry sends.
+func NewPayload(report *check.Report, installID string) Payload {
+	p := Payload{
+		SchemaVersion: SchemaVersion,
+		InstallID:     installID,
+		Version:       report.Version,
+		OS:            runtime.GOOS,
+		Arch:          runtime.GOARCH,
+		Items:         []ItemStatus{},
+	}
+	for _, it := range report.Items {
+		switch it.Status {
+		case check.StatusPass:
+			p.PassCount++
+		case check.StatusWarn:
+			p.WarnCount++
+		case check.StatusFail:
+			p.FailCount++
+		case check.StatusSkip:
+			p.SkipCount++
+		case check.StatusInfo:
+			p.InfoCount++
+		}
+		if !check.IsBuiltinItem(it.Name) {
+			p.CustomCount++
+			continue
+		}
+		p.Items = append(p.Items, ItemStatus{Name: it.Name, Status: it.Status})
+	}
+	return p
+}
+
+// SendTelemetry POSTs the Payload for report to endpoint as JSON. The installation ID comes from
+// the repo root (see WithRepoRoot) and is created if needed. Callers decide whether telemetry is
+// enabled; see Enabled.
+func SendTelemetry(ctx context.Context, report *check.Report, endpoint string) error {
+	if report == nil {
+		return errors.New("no report to send")
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid telemetry endpoint %q (set %s to an http(s) URL)", endpoint, EndpointEnv)
+	}
+	root, err := repoRoot(ctx)
+	if err != nil {
+		return err
+	}
+	id, err := InstallID(root)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(NewPayload(report, id))
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "aava-agent-cli")
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send telemetry: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("telemetry endpoint returned %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
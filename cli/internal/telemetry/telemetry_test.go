@@ -0,0 +1,101 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/check"
+)
+
+func testReport() *check.Report {
+	return &check.Report{
+		Version: "6.2.0",
+		Items: []check.Item{
+			{Name: "Docker CLI", Status: check.StatusPass, Message: "docker 27.1"},
+			{Name: "ARI Connectivity", Status: check.StatusFail, Message: "dial tcp pbx.internal:8088: refused", Details: "ASTERISK_ARI_PASSWORD=hunter2"},
+			{Name: "Env", Status: check.StatusWarn, Message: "OPENAI_API_KEY is empty"},
+			{Name: "ping pbx.internal", Status: check.StatusPass, Message: "declarative check"},
+		},
+	}
+}
+
+func TestNewPayloadOmitsValuesAndCustomNames(t *testing.T) {
+	p := NewPayload(testReport(), "0123456789abcdef0123456789abcdef")
+	if p.PassCount != 2 || p.FailCount != 1 || p.WarnCount != 1 || p.CustomCount != 1 || len(p.Items) != 3 {
+		t.Fatalf("payload = %+v", p)
+	}
+	if p.OS != runtime.GOOS || p.Arch != runtime.GOARCH || p.Version != "6.2.0" || p.SchemaVersion != SchemaVersion {
+		t.Fatalf("payload = %+v", p)
+	}
+	b, _ := json.Marshal(p)
+	for _, leak := range []string{"pbx.internal", "hunter2", "OPENAI_API_KEY", "docker 27.1"} {
+		if strings.Contains(string(b), leak) {
+			t.Errorf("payload contains %q: %s", leak, b)
+		}
+	}
+}
+
+func TestInstallIDIsCreatedOnceAndReused(t *testing.T) {
+	root := t.TempDir()
+	id, err := InstallID(root)
+	if err != nil || !validID(id) {
+		t.Fatalf("id = %q, err = %v", id, err)
+	}
+	if again, _ := InstallID(root); again != id {
+		t.Fatalf("second call = %q, want %q", again, id)
+	}
+	// A corrupted file is replaced rather than sent.
+	os.WriteFile(filepath.Join(root, filepath.FromSlash(InstallIDPath)), []byte("my-hostname\n"), 0o644)
+	if fresh, _ := InstallID(root); fresh == id || !validID(fresh) {
+		t.Fatalf("after corruption = %q", fresh)
+	}
+}
+
+func TestSendTelemetry(t *testing.T) {
+	var got Payload
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("%s %s", r.Method, r.Header.Get("Content-Type"))
+		}
+		b, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(b, &got); err != nil {
+			t.Error(err)
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	root := t.TempDir()
+	ctx := WithRepoRoot(context.Background(), root)
+	if err := SendTelemetry(ctx, testReport(), srv.URL+"/v1/agent-check"); err != nil {
+		t.Fatal(err)
+	}
+	id, _ := InstallID(root)
+	if got.InstallID != id || got.FailCount != 1 {
+		t.Fatalf("received %+v", got)
+	}
+
+	if err := SendTelemetry(ctx, testReport(), "ftp://example.com"); err == nil || !strings.Contains(err.Error(), EndpointEnv) {
+		t.Fatalf("err = %v, want invalid endpoint", err)
+	}
+	srv.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { http.Error(w, "nope", http.StatusForbidden) })
+	if err := SendTelemetry(ctx, testReport(), srv.URL); err == nil || !strings.Contains(err.Error(), "403") {
+		t.Fatalf("err = %v, want 403", err)
+	}
+}
+
+func TestEnabled(t *testing.T) {
+	for v, want := range map[string]bool{"": false, "0": false, "1": true, "true": true, `"yes"`: true, "off": false} {
+		if got := Enabled(map[string]string{Env: v}); got != want {
+			t.Errorf("Enabled(%q) = %v, want %v", v, got, want)
+		}
+	}
+}
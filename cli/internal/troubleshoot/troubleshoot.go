@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"os"
 	"os/exec"
 	"regexp"
@@ -12,6 +13,7 @@ import (
 	"time"
 
 	"github.com/fatih/color"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/logging"
 )
 
 var (
@@ -33,6 +35,7 @@ type Call struct {
 // Runner orchestrates troubleshooting
 type Runner struct {
 	verbose     bool
+	log         *slog.Logger
 	ctx         context.Context
 	callID      string
 	symptom     string
@@ -44,10 +47,15 @@ type Runner struct {
 	jsonOutput  bool
 }
 
-// NewRunner creates a new troubleshoot runner
-func NewRunner(callID, symptom string, interactive, collectOnly, noLLM, forceLLM, list, jsonOutput, verbose bool) *Runner {
+// NewRunner creates a new troubleshoot runner. Log-parsing diagnostics go to logger at debug
+// level (nil discards them).
+func NewRunner(callID, symptom string, interactive, collectOnly, noLLM, forceLLM, list, jsonOutput, verbose bool, logger *slog.Logger) *Runner {
+	if logger == nil {
+		logger = logging.Discard()
+	}
 	return &Runner{
 		verbose:     verbose,
+		log:         logger,
 		ctx:         context.Background(),
 		callID:      callID,
 		symptom:     symptom,
@@ -366,31 +374,23 @@ func (r *Runner) getRecentCalls(limit int) ([]Call, error) {
 	pendingExternalMediaPattern := regexp.MustCompile(`(?i)(?:"pending_external_media_id"\s*:\s*"([0-9]+\.[0-9]+)"|pending_external_media_id=([0-9]+\.[0-9]+))`)
 	lines := strings.Split(cleanOutput, "\n")
 
-	if r.verbose {
-		fmt.Fprintf(os.Stderr, "[DEBUG] Read %d lines from Docker logs\n", len(lines))
-	}
+	r.log.Debug("read docker logs", "lines", len(lines))
 
 	for _, line := range lines {
 		matches := audioSocketPattern.FindStringSubmatch(line)
 		if id := firstNonEmpty(matches, 1, 2); id != "" {
 			excludedChannels[id] = true
-			if r.verbose {
-				fmt.Fprintf(os.Stderr, "[DEBUG] Found AudioSocket channel: %s\n", id)
-			}
+			r.log.Debug("found AudioSocket channel", "channel_id", id)
 		}
 		matches = externalMediaPattern.FindStringSubmatch(line)
 		if id := firstNonEmpty(matches, 1, 2); id != "" {
 			excludedChannels[id] = true
-			if r.verbose {
-				fmt.Fprintf(os.Stderr, "[DEBUG] Found ExternalMedia channel: %s\n", id)
-			}
+			r.log.Debug("found ExternalMedia channel", "channel_id", id)
 		}
 		matches = pendingExternalMediaPattern.FindStringSubmatch(line)
 		if id := firstNonEmpty(matches, 1, 2); id != "" {
 			excludedChannels[id] = true
-			if r.verbose {
-				fmt.Fprintf(os.Stderr, "[DEBUG] Found pending ExternalMedia channel: %s\n", id)
-			}
+			r.log.Debug("found pending ExternalMedia channel", "channel_id", id)
 		}
 	}
 
@@ -411,9 +411,7 @@ func (r *Runner) getRecentCalls(limit int) ([]Call, error) {
 				callID := matches[1]
 				// Skip non-caller channels (AudioSocket / ExternalMedia helper channels)
 				if excludedChannels[callID] {
-					if r.verbose {
-						fmt.Fprintf(os.Stderr, "[DEBUG] Skipping non-caller channel: %s\n", callID)
-					}
+					r.log.Debug("skipping non-caller channel", "channel_id", callID)
 					continue
 				}
 				if _, exists := callMap[callID]; !exists {
@@ -421,18 +419,14 @@ func (r *Runner) getRecentCalls(limit int) ([]Call, error) {
 						ID:        callID,
 						Timestamp: time.Now(), // Will be refined from log timestamp
 					}
-					if r.verbose {
-						fmt.Fprintf(os.Stderr, "[DEBUG] Found call ID: %s\n", callID)
-					}
+					r.log.Debug("found call", "call_id", callID)
 				}
 				break // Found a match, no need to try other patterns
 			}
 		}
 	}
 
-	if r.verbose {
-		fmt.Fprintf(os.Stderr, "[DEBUG] Total pattern matches: %d, Unique calls: %d\n", matchCount, len(callMap))
-	}
+	r.log.Debug("call ID scan complete", "pattern_matches", matchCount, "unique_calls", len(callMap))
 
 	// Convert to slice and sort by ID (descending, newer first)
 	calls := make([]Call, 0, len(callMap))
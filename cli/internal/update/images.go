@@ -0,0 +1,207 @@
+package update
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	cmdexec "github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/exec"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/maputil"
+	"gopkg.in/yaml.v3"
+)
+
+// Pre-release image tags UpdateImages can switch services to.
+const (
+	TagEdge    = "edge"
+	TagNightly = "nightly"
+)
+
+// ComposeOverrideFile is the operator's Compose override, which docker compose reads next to
+// docker-compose.yml by default. UpdateImages passes it explicitly when it exists, since
+// naming any -f file turns the default lookup off.
+const ComposeOverrideFile = "docker-compose.override.yml"
+
+// UpdateOptions controls UpdateImages.
+type UpdateOptions struct {
+	// RepoRoot holds docker-compose.yml.
+	RepoRoot string
+	// Services limits the update to these Compose services; empty means every service whose
+	// image can be retagged.
+	Services []string
+	// Prerelease switches the services from their latest images to Tag.
+	Prerelease bool
+	// Tag is TagEdge (the default) or TagNightly.
+	Tag string
+}
+
+// runCompose runs docker compose with args; it is replaced in tests.
+var runCompose = func(ctx context.Context, args ...string) error {
+	_, err := cmdexec.RunCmdResult(ctx, append([]string{"docker", "compose"}, args...)...)
+	return err
+}
+
+// UpdateImages pulls and starts pre-release images when opts.Prerelease is set; otherwise it
+// does nothing, as agent update rebuilds the images from the checked-out code. Every service
+// whose image is untagged or tagged latest is switched to opts.Tag through a temporary Compose
+// override file passed with -f after docker-compose.yml, then pulled and recreated with
+// docker compose up -d --no-build. Images pinned to another tag or a digest, and services
+// built locally (pull_policy: build), are left alone. The override is removed afterwards, so
+// a later plain docker compose up returns the services to latest.
+func UpdateImages(ctx context.Context, opts UpdateOptions) error {
+	if !opts.Prerelease {
+		return nil
+	}
+	tag := opts.Tag
+	if tag == "" {
+		tag = TagEdge
+	}
+	if tag != TagEdge && tag != TagNightly {
+		return fmt.Errorf("unknown pre-release tag %q (use %s or %s)", tag, TagEdge, TagNightly)
+	}
+
+	files, images, err := SelectPrereleaseImages(opts.RepoRoot, opts.Services, tag)
+	if err != nil {
+		return err
+	}
+
+	override, err := writeImageOverride(opts.RepoRoot, images)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(override)
+
+	var args []string
+	for _, f := range append(files, override) {
+		args = append(args, "-f", f)
+	}
+	services := maputil.SortedKeys(images)
+	progress("Pulling %s images for %s", tag, strings.Join(services, ", "))
+	if err := runCompose(ctx, append(append(args, "pull"), services...)...); err != nil {
+		return fmt.Errorf("docker compose pull failed: %w", err)
+	}
+	progress("Starting %s", strings.Join(services, ", "))
+	if err := runCompose(ctx, append(append(args, "up", "-d", "--no-build"), services...)...); err != nil {
+		return fmt.Errorf("docker compose up failed: %w", err)
+	}
+	return nil
+}
+
+// SelectPrereleaseImages returns the Compose files under repoRoot and the images UpdateImages
+// would switch services (all of them when empty) to for tag. It fails when there is no such
+// service, so agent update can refuse --prerelease before it changes anything.
+func SelectPrereleaseImages(repoRoot string, services []string, tag string) ([]string, map[string]string, error) {
+	files := []string{filepath.Join(repoRoot, "docker-compose.yml")}
+	if _, err := os.Stat(filepath.Join(repoRoot, ComposeOverrideFile)); err == nil {
+		files = append(files, filepath.Join(repoRoot, ComposeOverrideFile))
+	}
+	images, err := PrereleaseImages(files, tag)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(services) > 0 {
+		selected := map[string]string{}
+		for _, svc := range services {
+			if img, ok := images[svc]; ok {
+				selected[svc] = img
+			}
+		}
+		images = selected
+	}
+	if len(images) == 0 {
+		return nil, nil, fmt.Errorf("no service to switch to :%s: pre-release tags need registry images tagged latest, and the selected services are built locally (pull_policy: build) or pinned", tag)
+	}
+	return files, images, nil
+}
+
+// PrereleaseImages reads the Compose files (later files override the image and pull_policy of
+// earlier ones) and maps every service that UpdateImages would switch to its image retagged
+// as tag.
+func PrereleaseImages(files []string, tag string) (map[string]string, error) {
+	type service struct{ image, pullPolicy string }
+	services := map[string]service{}
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var doc struct {
+			Services map[string]struct {
+				Image      string `yaml:"image"`
+				PullPolicy string `yaml:"pull_policy"`
+			} `yaml:"services"`
+		}
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+		}
+		for name, s := range doc.Services {
+			cur := services[name]
+			if s.Image != "" {
+				cur.image = s.Image
+			}
+			if s.PullPolicy != "" {
+				cur.pullPolicy = s.PullPolicy
+			}
+			services[name] = cur
+		}
+	}
+	images := map[string]string{}
+	for name, s := range services {
+		if s.pullPolicy == "build" {
+			continue
+		}
+		if img, ok := retag(s.image, tag); ok {
+			images[name] = img
+		}
+	}
+	return images, nil
+}
+
+// retag returns image with its tag replaced by tag, if it is untagged or tagged latest.
+func retag(image, tag string) (string, bool) {
+	if image == "" || strings.Contains(image, "@") || strings.Contains(image, "${") {
+		return "", false
+	}
+	repo, cur := image, ""
+	// A ':' before the last '/' is a registry port, not a tag.
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		repo, cur = image[:i], image[i+1:]
+	}
+	if cur != "" && cur != "latest" {
+		return "", false
+	}
+	return repo + ":" + tag, true
+}
+
+// writeImageOverride writes a Compose override setting each service's image, and a pull
+// policy that lets docker compose pull it, to a temporary file in root.
+func writeImageOverride(root string, images map[string]string) (string, error) {
+	type service struct {
+		Image      string `yaml:"image"`
+		PullPolicy string `yaml:"pull_policy"`
+	}
+	doc := struct {
+		Services map[string]service `yaml:"services"`
+	}{Services: map[string]service{}}
+	for name, img := range images {
+		doc.Services[name] = service{Image: img, PullPolicy: "missing"}
+	}
+	data, err := yaml.Marshal(doc)
+	if err != nil {
+		return "", err
+	}
+	f, err := os.CreateTemp(root, ".agent-prerelease-*.yml")
+	if err != nil {
+		return "", err
+	}
+	_, err = f.Write(append([]byte("# Written by agent update --prerelease; removed when the update finishes.\n"), data...))
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return "", fmt.Errorf("failed to write the Compose override: %w", err)
+	}
+	return f.Name(), nil
+}
@@ -0,0 +1,116 @@
+package update
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testCompose = `services:
+  ai_engine:
+    image: ghcr.io/example/ai-engine:latest
+  admin_ui:
+    image: registry.local:5000/admin-ui
+  local_ai_server:
+    image: asterisk-ai-voice-agent-local-ai-server:latest
+    pull_policy: build
+  pinned:
+    image: ghcr.io/example/pinned:1.2.3
+  digest:
+    image: ghcr.io/example/digest@sha256:abc
+`
+
+func TestPrereleaseImages(t *testing.T) {
+	dir := t.TempDir()
+	compose := filepath.Join(dir, "docker-compose.yml")
+	override := filepath.Join(dir, ComposeOverrideFile)
+	os.WriteFile(compose, []byte(testCompose), 0o644)
+	// The override pins admin_ui and turns local_ai_server into a registry image.
+	os.WriteFile(override, []byte("services:\n  admin_ui:\n    image: registry.local:5000/admin-ui:2.0\n  local_ai_server:\n    image: ghcr.io/example/local-ai\n    pull_policy: always\n"), 0o644)
+
+	got, err := PrereleaseImages([]string{compose, override}, TagNightly)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"ai_engine":       "ghcr.io/example/ai-engine:nightly",
+		"local_ai_server": "ghcr.io/example/local-ai:nightly",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("PrereleaseImages = %v, want %v", got, want)
+	}
+
+	got, err = PrereleaseImages([]string{compose}, TagEdge)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got["admin_ui"] != "registry.local:5000/admin-ui:edge" || len(got) != 2 {
+		t.Fatalf("PrereleaseImages without override = %v", got)
+	}
+}
+
+func TestUpdateImagesWritesTemporaryOverride(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "docker-compose.yml"), []byte(testCompose), 0o644)
+	ProgressWriter = io.Discard
+	t.Cleanup(func() { ProgressWriter = os.Stdout })
+
+	var calls [][]string
+	var overrideContent string
+	orig := runCompose
+	t.Cleanup(func() { runCompose = orig })
+	runCompose = func(ctx context.Context, args ...string) error {
+		calls = append(calls, args)
+		if data, err := os.ReadFile(args[3]); err == nil {
+			overrideContent = string(data)
+		}
+		return nil
+	}
+
+	if err := UpdateImages(context.Background(), UpdateOptions{RepoRoot: dir}); err != nil || len(calls) != 0 {
+		t.Fatalf("without Prerelease: err=%v calls=%v", err, calls)
+	}
+	if err := UpdateImages(context.Background(), UpdateOptions{RepoRoot: dir, Prerelease: true, Services: []string{"ai_engine", "local_ai_server"}}); err != nil {
+		t.Fatal(err)
+	}
+	if len(calls) != 2 {
+		t.Fatalf("calls = %v", calls)
+	}
+	pull := strings.Join(calls[0], " ")
+	if !strings.HasPrefix(pull, "-f "+filepath.Join(dir, "docker-compose.yml")+" -f ") || !strings.HasSuffix(pull, " pull ai_engine") {
+		t.Fatalf("pull args = %q", pull)
+	}
+	if up := strings.Join(calls[1][4:], " "); up != "up -d --no-build ai_engine" {
+		t.Fatalf("up args = %q", up)
+	}
+	if !strings.Contains(overrideContent, "image: ghcr.io/example/ai-engine:edge") || !strings.Contains(overrideContent, "pull_policy: missing") {
+		t.Fatalf("override = %q", overrideContent)
+	}
+	if _, err := os.Stat(calls[0][3]); !os.IsNotExist(err) {
+		t.Fatalf("override %s not removed: %v", calls[0][3], err)
+	}
+
+	// Only locally built or pinned services selected: nothing to switch.
+	err := UpdateImages(context.Background(), UpdateOptions{RepoRoot: dir, Prerelease: true, Services: []string{"local_ai_server", "pinned"}})
+	if err == nil || !strings.Contains(err.Error(), "pull_policy: build") {
+		t.Fatalf("err = %v", err)
+	}
+	if err := UpdateImages(context.Background(), UpdateOptions{RepoRoot: dir, Prerelease: true, Tag: "beta"}); err == nil {
+		t.Fatal("expected an error for an unknown tag")
+	}
+}
+
+func TestSelectPrereleaseImagesRefusesStockCompose(t *testing.T) {
+	// The shipped docker-compose.yml builds every service locally (pull_policy: build).
+	repoRoot := filepath.Join("..", "..", "..")
+	for _, services := range [][]string{nil, {"ai_engine", "local_ai_server", "admin_ui"}} {
+		_, _, err := SelectPrereleaseImages(repoRoot, services, TagEdge)
+		if err == nil || !strings.Contains(err.Error(), "no service to switch") {
+			t.Fatalf("services %v: err = %v", services, err)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+// Package update checks GitHub for newer agent CLI releases, upgrades the agent binary and
+// switches Compose services to pre-release images.
+package update
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ReleaseURL is the GitHub Releases API endpoint for the latest published release.
+const ReleaseURL = "https://api.github.com/repos/hkjarral/asterisk-ai-voice-agent/releases/latest"
+
+// CacheFile is where CheckLatestRelease keeps the last API response, relative to the repo root.
+const CacheFile = ".agent/update-check-cache.json"
+
+// CacheTTL is how long a cached response is reused. Unauthenticated API calls are limited
+// to 60 an hour per IP, which scripted version checks on a shared NAT can exhaust.
+const CacheTTL = 24 * time.Hour
+
+// CachePath is the cache file CheckLatestRelease reads and writes ("" disables caching).
+// Callers that know the repo root should point it there; the default is relative to the
+// working directory.
+var CachePath = CacheFile
+
+// These are replaced in tests.
+var (
+	releaseURL = ReleaseURL
+	httpClient = &http.Client{Timeout: 5 * time.Second}
+	now        = time.Now
+)
+
+// LatestRelease is the latest published release compared with the running version.
+type LatestRelease struct {
+	Tag             string    `json:"tag"`
+	URL             string    `json:"url"`
+	CurrentVersion  string    `json:"current_version"`
+	UpdateAvailable bool      `json:"update_available"`
+	CheckedAt       time.Time `json:"checked_at"`
+	// Cached is true when the release came from CachePath instead of the API.
+	Cached bool `json:"-"`
+}
+
+type cacheEntry struct {
+	Tag       string    `json:"tag"`
+	URL       string    `json:"url"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// CheckLatestRelease fetches the latest release's tag_name and html_url from the GitHub
+// Releases API (or CachePath when the last check is under CacheTTL old) and reports whether
+// it is newer than currentVersion. A currentVersion that is not semver (dev builds) is never
+// reported as outdated.
+func CheckLatestRelease(ctx context.Context, currentVersion string) (LatestRelease, error) {
+	entry, ok := readCache()
+	if !ok {
+		var err error
+		entry, err = fetchLatest(ctx)
+		if err != nil {
+			return LatestRelease{}, err
+		}
+		writeCache(entry)
+	}
+	rel := LatestRelease{
+		Tag:            entry.Tag,
+		URL:            entry.URL,
+		CurrentVersion: strings.TrimSpace(currentVersion),
+		CheckedAt:      entry.CheckedAt,
+		Cached:         ok,
+	}
+	if _, _, _, valid := ParseSemver(rel.CurrentVersion); valid {
+		rel.UpdateAvailable = CompareSemver(rel.CurrentVersion, rel.Tag) < 0
+	}
+	return rel, nil
+}
+
+func fetchLatest(ctx context.Context) (cacheEntry, error) {
+	ctx, cancel := context.WithTimeout(ctx, 4*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, releaseURL, nil)
+	if err != nil {
+		return cacheEntry{}, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("User-Agent", "aava-agent-cli")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return cacheEntry{}, fmt.Errorf("failed to query GitHub releases: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return cacheEntry{}, fmt.Errorf("GitHub releases API returned %s", resp.Status)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return cacheEntry{}, err
+	}
+	var payload struct {
+		TagName string `json:"tag_name"`
+		HTMLURL string `json:"html_url"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return cacheEntry{}, fmt.Errorf("failed to parse GitHub release: %w", err)
+	}
+	tag := strings.TrimSpace(payload.TagName)
+	if tag == "" {
+		return cacheEntry{}, errors.New("missing tag_name in GitHub release")
+	}
+	if _, _, _, ok := ParseSemver(tag); !ok {
+		return cacheEntry{}, fmt.Errorf("latest release tag %q is not semver", tag)
+	}
+	return cacheEntry{Tag: tag, URL: strings.TrimSpace(payload.HTMLURL), CheckedAt: now().UTC()}, nil
+}
+
+// readCache returns the cached release when it is present, parseable and fresh.
+func readCache() (cacheEntry, bool) {
+	if CachePath == "" {
+		return cacheEntry{}, false
+	}
+	data, err := os.ReadFile(CachePath)
+	if err != nil {
+		return cacheEntry{}, false
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.Tag == "" {
+		return cacheEntry{}, false
+	}
+	age := now().Sub(entry.CheckedAt)
+	if age < 0 || age >= CacheTTL {
+		return cacheEntry{}, false
+	}
+	return entry, true
+}
+
+// writeCache is best-effort: a read-only checkout only loses the rate-limit protection.
+func writeCache(entry cacheEntry) {
+	if CachePath == "" {
+		return
+	}
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(CachePath), 0o755); err != nil {
+		return
+	}
+	tmp := CachePath + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return
+	}
+	if err := os.Rename(tmp, CachePath); err != nil {
+		_ = os.Remove(tmp)
+	}
+}
+
+// CompareSemver returns -1, 0 or 1 as a is older than, equal to or newer than b. A leading
+// "v" and any pre-release suffix are ignored; if either side does not parse, it returns 0.
+func CompareSemver(a, b string) int {
+	amaj, amin, apat, okA := ParseSemver(a)
+	bmaj, bmin, bpat, okB := ParseSemver(b)
+	if !okA || !okB {
+		return 0
+	}
+	for _, d := range [][2]int{{amaj, bmaj}, {amin, bmin}, {apat, bpat}} {
+		if d[0] < d[1] {
+			return -1
+		}
+		if d[0] > d[1] {
+			return 1
+		}
+	}
+	return 0
+}
+
+// ParseSemver parses "vMAJOR.MINOR.PATCH[-pre]" (the "v" is optional).
+func ParseSemver(v string) (major int, minor int, patch int, ok bool) {
+	v = strings.TrimSpace(v)
+	v = strings.TrimPrefix(strings.ToLower(v), "v")
+	if v == "" {
+		return 0, 0, 0, false
+	}
+	if i := strings.IndexByte(v, '-'); i >= 0 {
+		v = v[:i]
+	}
+	parts := strings.Split(v, ".")
+	if len(parts) < 3 {
+		return 0, 0, 0, false
+	}
+	maj, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, 0, 0, false
+	}
+	min, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, 0, 0, false
+	}
+	pat, err := strconv.Atoi(parts[2])
+	if err != nil {
+		return 0, 0, 0, false
+	}
+	return maj, min, pat, true
+}
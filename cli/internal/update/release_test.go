@@ -0,0 +1,87 @@
+package update
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func setup(t *testing.T, tag string) *int {
+	t.Helper()
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.Header.Get("User-Agent") == "" {
+			t.Errorf("missing User-Agent")
+		}
+		w.Write([]byte(`{"tag_name":"` + tag + `","html_url":"https://example.test/releases/` + tag + `"}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	clock := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	oldURL, oldClient, oldNow, oldPath := releaseURL, httpClient, now, CachePath
+	releaseURL, httpClient, CachePath = srv.URL, srv.Client(), filepath.Join(t.TempDir(), ".agent", "update-check-cache.json")
+	now = func() time.Time { return clock }
+	t.Cleanup(func() { releaseURL, httpClient, now, CachePath = oldURL, oldClient, oldNow, oldPath })
+	return &calls
+}
+
+func TestCheckLatestReleaseCachesFor24Hours(t *testing.T) {
+	calls := setup(t, "v6.3.0")
+
+	rel, err := CheckLatestRelease(context.Background(), "v6.2.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !rel.UpdateAvailable || rel.Tag != "v6.3.0" || rel.URL != "https://example.test/releases/v6.3.0" || rel.Cached {
+		t.Fatalf("rel = %+v", rel)
+	}
+
+	start := now()
+	now = func() time.Time { return start.Add(CacheTTL - time.Minute) }
+	rel, err = CheckLatestRelease(context.Background(), "6.3.0")
+	if err != nil || !rel.Cached || rel.UpdateAvailable || *calls != 1 {
+		t.Fatalf("cached check: %+v, %v, calls=%d", rel, err, *calls)
+	}
+
+	now = func() time.Time { return start.Add(CacheTTL) }
+	if _, err := CheckLatestRelease(context.Background(), "v6.3.0"); err != nil || *calls != 2 {
+		t.Fatalf("expired cache: %v, calls=%d", err, *calls)
+	}
+}
+
+func TestCheckLatestReleaseDevBuild(t *testing.T) {
+	setup(t, "v6.3.0")
+	rel, err := CheckLatestRelease(context.Background(), "dev")
+	if err != nil || rel.UpdateAvailable {
+		t.Fatalf("dev build: %+v, %v", rel, err)
+	}
+}
+
+func TestCheckLatestReleaseRejectsBadTag(t *testing.T) {
+	setup(t, "nightly")
+	if _, err := CheckLatestRelease(context.Background(), "v6.2.0"); err == nil {
+		t.Fatal("expected an error for a non-semver tag")
+	}
+}
+
+func TestCompareSemver(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"v6.2.0", "v6.3.0", -1},
+		{"6.10.0", "v6.9.9", 1},
+		{"v6.2.0-rc1", "6.2.0", 0},
+		{"v7.0.0", "v6.99.99", 1},
+		{"dev", "v6.2.0", 0},
+	}
+	for _, c := range cases {
+		if got := CompareSemver(c.a, c.b); got != c.want {
+			t.Errorf("CompareSemver(%q, %q) = %d, want %d", c.a, c.b, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package update
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DownloadURL is the base URL of release assets; the tag and asset name are appended.
+const DownloadURL = "https://github.com/hkjarral/Asterisk-AI-Voice-Agent/releases/download"
+
+// ChecksumsAsset is the release asset listing the SHA-256 of every binary (sha256sum format).
+const ChecksumsAsset = "SHA256SUMS"
+
+// ProgressWriter receives the steps SelfUpgrade prints.
+var ProgressWriter io.Writer = os.Stdout
+
+// These are replaced in tests.
+var (
+	downloadURL    = DownloadURL
+	downloadClient = &http.Client{Timeout: 2 * time.Minute}
+	executable     = os.Executable
+	goos, goarch   = runtime.GOOS, runtime.GOARCH
+)
+
+// ReleaseAssetName returns the name of the release binary for goos/goarch, as built by
+// make cli-build-all.
+func ReleaseAssetName(goos string, goarch string) (string, bool) {
+	switch goos {
+	case "linux", "darwin":
+		if goarch == "amd64" || goarch == "arm64" {
+			return "agent-" + goos + "-" + goarch, true
+		}
+	case "windows":
+		if goarch == "amd64" {
+			return "agent-windows-amd64.exe", true
+		}
+	}
+	return "", false
+}
+
+// ParseChecksums returns the hex SHA-256 for filename from a SHA256SUMS file.
+func ParseChecksums(sums []byte, filename string) (string, error) {
+	for _, line := range strings.Split(string(sums), "\n") {
+		parts := strings.Fields(line)
+		if len(parts) < 2 {
+			continue
+		}
+		// sha256sum marks binary-mode entries with a leading '*'.
+		if strings.TrimPrefix(parts[1], "*") != filename {
+			continue
+		}
+		if len(parts[0]) != 64 {
+			return "", fmt.Errorf("invalid sha256 length for %s", filename)
+		}
+		return parts[0], nil
+	}
+	return "", fmt.Errorf("checksum for %s not found in %s", filename, ChecksumsAsset)
+}
+
+// SelfUpgrade replaces the running agent binary with the release targetVersion ("" or
+// "latest" for the latest release). The binary for this GOOS/GOARCH is downloaded and checked
+// against the release's SHA256SUMS, the current binary is copied to <binary>.bak, the new one
+// is moved into place and must run "version" successfully, and then the .bak is removed. If
+// anything fails after the replacement, the .bak is moved back. Steps are printed to
+// ProgressWriter.
+func SelfUpgrade(ctx context.Context, targetVersion string) error {
+	if goos == "windows" {
+		// A running .exe cannot be replaced in place.
+		return errors.New("self-upgrade is not supported on Windows; download the release binary instead")
+	}
+	asset, ok := ReleaseAssetName(goos, goarch)
+	if !ok {
+		return fmt.Errorf("no release binary for %s/%s", goos, goarch)
+	}
+	binPath, err := executable()
+	if err != nil {
+		return fmt.Errorf("cannot locate the running binary: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(binPath); err == nil {
+		binPath = resolved
+	}
+
+	tag := strings.TrimSpace(targetVersion)
+	if tag == "" || strings.EqualFold(tag, "latest") {
+		progress("Resolving latest release")
+		entry, err := fetchLatest(ctx)
+		if err != nil {
+			return err
+		}
+		tag = entry.Tag
+	} else {
+		if _, _, _, ok := ParseSemver(tag); !ok {
+			return fmt.Errorf("target version %q is not semver (want vX.Y.Z)", tag)
+		}
+		if !strings.HasPrefix(tag, "v") {
+			tag = "v" + tag
+		}
+	}
+	base := downloadURL + "/" + tag
+
+	progress("Downloading %s %s", asset, tag)
+	sums, err := download(ctx, base+"/"+ChecksumsAsset)
+	if err != nil {
+		return err
+	}
+	expected, err := ParseChecksums(sums, asset)
+	if err != nil {
+		return err
+	}
+	payload, err := download(ctx, base+"/"+asset)
+	if err != nil {
+		return err
+	}
+
+	progress("Verifying SHA-256")
+	if actual := fmt.Sprintf("%x", sha256.Sum256(payload)); !strings.EqualFold(actual, expected) {
+		return fmt.Errorf("checksum mismatch for %s: got %s, want %s", asset, actual, expected)
+	}
+
+	// Stage the new binary next to the old one so the final rename stays on one filesystem.
+	dir := filepath.Dir(binPath)
+	tmp := filepath.Join(dir, ".agent.new."+strconv.Itoa(os.Getpid()))
+	if err := os.WriteFile(tmp, payload, 0o755); err != nil {
+		return fmt.Errorf("cannot write to %s: %w", dir, err)
+	}
+	defer os.Remove(tmp)
+
+	bak := binPath + ".bak"
+	progress("Backing up %s to %s", binPath, bak)
+	if err := copyExecutable(binPath, bak); err != nil {
+		return fmt.Errorf("failed to back up %s: %w", binPath, err)
+	}
+
+	progress("Replacing %s", binPath)
+	if err := os.Rename(tmp, binPath); err != nil {
+		_ = os.Remove(bak)
+		return fmt.Errorf("failed to replace %s: %w", binPath, err)
+	}
+
+	progress("Checking that the new binary starts")
+	if out, err := runVersion(ctx, binPath); err != nil {
+		if rerr := os.Rename(bak, binPath); rerr != nil {
+			return fmt.Errorf("new binary failed to start (%v) and restoring %s failed: %w", err, bak, rerr)
+		}
+		return fmt.Errorf("new binary failed to start, previous binary restored: %w\n%s", err, out)
+	}
+
+	if err := os.Remove(bak); err != nil {
+		progress("Warning: could not remove %s: %v", bak, err)
+	}
+	progress("Upgraded %s to %s", binPath, tag)
+	return nil
+}
+
+func progress(format string, args ...any) {
+	fmt.Fprintf(ProgressWriter, "==> "+format+"\n", args...)
+}
+
+func download(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "aava-agent-cli")
+	resp, err := downloadClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("GET %s failed: %s", url, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// copyExecutable copies src to dst with src's permissions.
+func copyExecutable(src string, dst string) error {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	fi, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(dst, data, fi.Mode().Perm()); err != nil {
+		return err
+	}
+	return os.Chmod(dst, fi.Mode().Perm())
+}
+
+func runVersion(ctx context.Context, binPath string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, binPath, "version")
+	// Keep a freshly installed agent update from trying to update itself again.
+	cmd.Env = append(os.Environ(), "AAVA_AGENT_SKIP_SELF_UPDATE=1")
+	return cmd.CombinedOutput()
+}
@@ -0,0 +1,135 @@
+package update
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+const oldBinary = "#!/bin/sh\necho old\n"
+
+// setupUpgrade serves release assets for tag from a fake download server, with the checksum
+// of sumsFor (normally the binary itself) in SHA256SUMS, and points executable at a temp
+// "agent" script. It returns the installed binary's path.
+func setupUpgrade(t *testing.T, tag, binary, sumsFor string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake release binaries are shell scripts")
+	}
+	asset, _ := ReleaseAssetName("linux", "amd64")
+	sums := fmt.Sprintf("%x  %s\n%x  agent-linux-arm64\n", sha256.Sum256([]byte(sumsFor)), asset, sha256.Sum256([]byte("other")))
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/latest":
+			w.Write([]byte(`{"tag_name":"` + tag + `"}`))
+		case "/" + tag + "/" + ChecksumsAsset:
+			w.Write([]byte(sums))
+		case "/" + tag + "/" + asset:
+			w.Write([]byte(binary))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	binPath := filepath.Join(t.TempDir(), "agent")
+	if err := os.WriteFile(binPath, []byte(oldBinary), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	oldDownload, oldDL, oldRelease, oldClient, oldExe, oldOS, oldArch, oldOut :=
+		downloadURL, downloadClient, releaseURL, httpClient, executable, goos, goarch, ProgressWriter
+	downloadURL, downloadClient, releaseURL, httpClient = srv.URL, srv.Client(), srv.URL+"/latest", srv.Client()
+	executable = func() (string, error) { return binPath, nil }
+	goos, goarch, ProgressWriter = "linux", "amd64", io.Discard
+	t.Cleanup(func() {
+		downloadURL, downloadClient, releaseURL, httpClient, executable, goos, goarch, ProgressWriter =
+			oldDownload, oldDL, oldRelease, oldClient, oldExe, oldOS, oldArch, oldOut
+	})
+	return binPath
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestSelfUpgradeLatest(t *testing.T) {
+	newBinary := "#!/bin/sh\necho v6.4.0\n"
+	binPath := setupUpgrade(t, "v6.4.0", newBinary, newBinary)
+
+	if err := SelfUpgrade(context.Background(), "latest"); err != nil {
+		t.Fatal(err)
+	}
+	if got := readFile(t, binPath); got != newBinary {
+		t.Fatalf("binary = %q", got)
+	}
+	if _, err := os.Stat(binPath + ".bak"); !os.IsNotExist(err) {
+		t.Fatalf(".bak left behind: %v", err)
+	}
+	if fi, _ := os.Stat(binPath); fi.Mode().Perm()&0o100 == 0 {
+		t.Fatalf("new binary not executable: %v", fi.Mode())
+	}
+}
+
+func TestSelfUpgradeChecksumMismatchLeavesBinary(t *testing.T) {
+	binPath := setupUpgrade(t, "v6.4.0", "#!/bin/sh\necho tampered\n", "#!/bin/sh\necho v6.4.0\n")
+
+	err := SelfUpgrade(context.Background(), "6.4.0")
+	if err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Fatalf("err = %v", err)
+	}
+	if got := readFile(t, binPath); got != oldBinary {
+		t.Fatalf("binary replaced despite mismatch: %q", got)
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(binPath)); len(entries) != 1 {
+		t.Fatalf("stray files: %v", entries)
+	}
+}
+
+func TestSelfUpgradeRestoresBackupWhenNewBinaryFails(t *testing.T) {
+	broken := "#!/bin/sh\nexit 3\n"
+	binPath := setupUpgrade(t, "v6.4.0", broken, broken)
+
+	err := SelfUpgrade(context.Background(), "v6.4.0")
+	if err == nil || !strings.Contains(err.Error(), "previous binary restored") {
+		t.Fatalf("err = %v", err)
+	}
+	if got := readFile(t, binPath); got != oldBinary {
+		t.Fatalf("binary not restored: %q", got)
+	}
+	if _, err := os.Stat(binPath + ".bak"); !os.IsNotExist(err) {
+		t.Fatalf(".bak left behind: %v", err)
+	}
+}
+
+func TestSelfUpgradeRejectsBadTarget(t *testing.T) {
+	setupUpgrade(t, "v6.4.0", "x", "x")
+	if err := SelfUpgrade(context.Background(), "main"); err == nil || !strings.Contains(err.Error(), "not semver") {
+		t.Fatalf("err = %v", err)
+	}
+	if err := SelfUpgrade(context.Background(), "v9.9.9"); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Fatalf("missing release: err = %v", err)
+	}
+}
+
+func TestParseChecksums(t *testing.T) {
+	sum := strings.Repeat("a", 64)
+	if got, err := ParseChecksums([]byte(sum+" *agent-linux-amd64\n"), "agent-linux-amd64"); err != nil || got != sum {
+		t.Fatalf("got %q, %v", got, err)
+	}
+	if _, err := ParseChecksums([]byte(sum+"  agent-linux-arm64\n"), "agent-linux-amd64"); err == nil {
+		t.Fatal("want an error for a missing entry")
+	}
+}
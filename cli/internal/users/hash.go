@@ -0,0 +1,99 @@
+package users
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// The Admin UI verifies logins with passlib's CryptContext(schemes=["pbkdf2_sha256"]), so
+// hashes are written in passlib's format: $pbkdf2-sha256$<rounds>$<salt>$<checksum>, with salt
+// and checksum in passlib's "adapted base64" (no padding, '.' instead of '+').
+const (
+	hashPrefix = "$pbkdf2-sha256$"
+	hashRounds = 29000 // passlib's default for pbkdf2_sha256
+	saltLen    = 16
+	keyLen     = 32
+)
+
+var ab64 = base64.RawStdEncoding
+
+// HashPassword returns a passlib-compatible pbkdf2_sha256 hash of password.
+func HashPassword(password string) (string, error) {
+	salt := make([]byte, saltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	return formatHash(hashRounds, salt, pbkdf2SHA256([]byte(password), salt, hashRounds, keyLen)), nil
+}
+
+// VerifyPassword reports whether password matches a pbkdf2_sha256 hash written by
+// HashPassword or by the Admin UI.
+func VerifyPassword(password string, hash string) bool {
+	rounds, salt, sum, err := parseHash(hash)
+	if err != nil {
+		return false
+	}
+	got := pbkdf2SHA256([]byte(password), salt, rounds, len(sum))
+	return subtle.ConstantTimeCompare(got, sum) == 1
+}
+
+func formatHash(rounds int, salt []byte, sum []byte) string {
+	enc := func(b []byte) string { return strings.ReplaceAll(ab64.EncodeToString(b), "+", ".") }
+	return fmt.Sprintf("%s%d$%s$%s", hashPrefix, rounds, enc(salt), enc(sum))
+}
+
+func parseHash(hash string) (int, []byte, []byte, error) {
+	if !strings.HasPrefix(hash, hashPrefix) {
+		return 0, nil, nil, fmt.Errorf("unsupported hash scheme")
+	}
+	parts := strings.Split(strings.TrimPrefix(hash, hashPrefix), "$")
+	if len(parts) != 3 {
+		return 0, nil, nil, fmt.Errorf("malformed pbkdf2_sha256 hash")
+	}
+	rounds, err := strconv.Atoi(parts[0])
+	if err != nil || rounds < 1 {
+		return 0, nil, nil, fmt.Errorf("invalid rounds %q", parts[0])
+	}
+	dec := func(s string) ([]byte, error) { return ab64.DecodeString(strings.ReplaceAll(s, ".", "+")) }
+	salt, err := dec(parts[1])
+	if err != nil {
+		return 0, nil, nil, fmt.Errorf("invalid salt: %w", err)
+	}
+	sum, err := dec(parts[2])
+	if err != nil || len(sum) == 0 {
+		return 0, nil, nil, fmt.Errorf("invalid checksum")
+	}
+	return rounds, salt, sum, nil
+}
+
+// pbkdf2SHA256 implements PBKDF2 (RFC 8018) with HMAC-SHA256.
+func pbkdf2SHA256(password, salt []byte, rounds, length int) []byte {
+	prf := hmac.New(sha256.New, password)
+	var out []byte
+	for block := uint32(1); len(out) < length; block++ {
+		prf.Reset()
+		prf.Write(salt)
+		var idx [4]byte
+		binary.BigEndian.PutUint32(idx[:], block)
+		prf.Write(idx[:])
+		u := prf.Sum(nil)
+		t := append([]byte(nil), u...)
+		for i := 1; i < rounds; i++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+			for j := range t {
+				t[j] ^= u[j]
+			}
+		}
+		out = append(out, t...)
+	}
+	return out[:length]
+}
@@ -0,0 +1,290 @@
+// Package users manages the Admin UI login records in config/users.json.
+package users
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/maputil"
+)
+
+// DefaultPath is the users file relative to the repo root.
+const DefaultPath = "config/users.json"
+
+// DefaultLockTimeout bounds how long a mutation waits for another writer's lock file.
+const DefaultLockTimeout = 5 * time.Second
+
+var (
+	ErrUserExists   = errors.New("user already exists")
+	ErrUserNotFound = errors.New("user not found")
+	ErrLastUser     = errors.New("refusing to remove the last user (the Admin UI would have no login)")
+)
+
+// DefaultPasswordGrace is how long agent users passwd keeps the replaced hash when no grace
+// is given: none, since a password is often changed because the old one leaked.
+const DefaultPasswordGrace = time.Duration(0)
+
+// User is one record in users.json, in the layout the Admin UI reads (an object keyed by
+// username). Roles and the previous_password_* fields are optional; the Admin UI accepts
+// previous_password_hash at login until previous_password_expires and ignores fields it
+// doesn't know.
+type User struct {
+	Username                string     `json:"username"`
+	HashedPassword          string     `json:"hashed_password"`
+	Disabled                bool       `json:"disabled"`
+	MustChangePassword      bool       `json:"must_change_password"`
+	Roles                   []string   `json:"roles,omitempty"`
+	PreviousPasswordHash    string     `json:"previous_password_hash,omitempty"`
+	PreviousPasswordExpires *time.Time `json:"previous_password_expires,omitempty"`
+}
+
+// UserStore loads and saves users.json. Mutations hold <path>.lock for their whole
+// read-modify-write and replace the file via a temp file and rename, so a crash never leaves
+// a partially written users.json. Records are kept as raw JSON so fields this CLI doesn't
+// know about survive a rewrite.
+type UserStore struct {
+	Path        string
+	LockTimeout time.Duration
+}
+
+// NewUserStore returns a store for the users file at path.
+func NewUserStore(path string) *UserStore {
+	return &UserStore{Path: path, LockTimeout: DefaultLockTimeout}
+}
+
+// List returns every user sorted by username. A missing file yields no users.
+func (s *UserStore) List() ([]User, error) {
+	records, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	names := maputil.SortedKeys(records)
+	out := make([]User, 0, len(names))
+	for _, name := range names {
+		var u User
+		if err := json.Unmarshal(records[name], &u); err != nil {
+			return nil, fmt.Errorf("%s: user %q: %w", s.Path, name, err)
+		}
+		if u.Username == "" {
+			u.Username = name
+		}
+		out = append(out, u)
+	}
+	return out, nil
+}
+
+// Add hashes password and stores a new user. It fails with ErrUserExists if the name is taken.
+func (s *UserStore) Add(username string, password string, roles []string) error {
+	username = strings.TrimSpace(username)
+	if username == "" {
+		return errors.New("username is required")
+	}
+	if password == "" {
+		return errors.New("password is required")
+	}
+	hash, err := HashPassword(password)
+	if err != nil {
+		return fmt.Errorf("hash password: %w", err)
+	}
+	rec, err := json.Marshal(User{Username: username, HashedPassword: hash, Roles: roles})
+	if err != nil {
+		return err
+	}
+	return s.update(func(records map[string]json.RawMessage) error {
+		if _, ok := records[username]; ok {
+			return fmt.Errorf("%w: %s", ErrUserExists, username)
+		}
+		records[username] = rec
+		return nil
+	})
+}
+
+// Remove deletes a user. It fails with ErrUserNotFound for unknown names and ErrLastUser
+// when the user is the only one left.
+func (s *UserStore) Remove(username string) error {
+	username = strings.TrimSpace(username)
+	return s.update(func(records map[string]json.RawMessage) error {
+		if _, ok := records[username]; !ok {
+			return fmt.Errorf("%w: %s", ErrUserNotFound, username)
+		}
+		if len(records) == 1 {
+			return ErrLastUser
+		}
+		delete(records, username)
+		return nil
+	})
+}
+
+// SetPassword replaces a user's hash and clears must_change_password. With grace > 0 the old
+// hash is kept in previous_password_hash until previous_password_expires; expired previous
+// hashes are dropped by the next mutation of the file.
+func (s *UserStore) SetPassword(username string, password string, grace time.Duration) error {
+	username = strings.TrimSpace(username)
+	if password == "" {
+		return errors.New("password is required")
+	}
+	hash, err := HashPassword(password)
+	if err != nil {
+		return fmt.Errorf("hash password: %w", err)
+	}
+	return s.update(func(records map[string]json.RawMessage) error {
+		raw, ok := records[username]
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrUserNotFound, username)
+		}
+		var fields map[string]any
+		if err := json.Unmarshal(raw, &fields); err != nil {
+			return fmt.Errorf("user %q: %w", username, err)
+		}
+		old, _ := fields["hashed_password"].(string)
+		delete(fields, "previous_password_hash")
+		delete(fields, "previous_password_expires")
+		if grace > 0 && old != "" {
+			fields["previous_password_hash"] = old
+			fields["previous_password_expires"] = time.Now().Add(grace).UTC().Format(time.RFC3339)
+		}
+		fields["hashed_password"] = hash
+		fields["must_change_password"] = false
+		rec, err := json.Marshal(fields)
+		if err != nil {
+			return err
+		}
+		records[username] = rec
+		return nil
+	})
+}
+
+// pruneExpiredPrevious drops previous_password_* fields whose grace period has passed.
+func pruneExpiredPrevious(records map[string]json.RawMessage, now time.Time) error {
+	for name, raw := range records {
+		var u User
+		if err := json.Unmarshal(raw, &u); err != nil {
+			return fmt.Errorf("user %q: %w", name, err)
+		}
+		if u.PreviousPasswordExpires == nil || u.PreviousPasswordExpires.After(now) {
+			continue
+		}
+		var fields map[string]any
+		if err := json.Unmarshal(raw, &fields); err != nil {
+			return fmt.Errorf("user %q: %w", name, err)
+		}
+		delete(fields, "previous_password_hash")
+		delete(fields, "previous_password_expires")
+		rec, err := json.Marshal(fields)
+		if err != nil {
+			return err
+		}
+		records[name] = rec
+	}
+	return nil
+}
+
+func (s *UserStore) load() (map[string]json.RawMessage, error) {
+	data, err := os.ReadFile(s.Path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string]json.RawMessage{}, nil
+		}
+		return nil, err
+	}
+	records := map[string]json.RawMessage{}
+	if strings.TrimSpace(string(data)) == "" {
+		return records, nil
+	}
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("%s: expected an object keyed by username: %w", s.Path, err)
+	}
+	return records, nil
+}
+
+func (s *UserStore) update(fn func(records map[string]json.RawMessage) error) error {
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	records, err := s.load()
+	if err != nil {
+		return err
+	}
+	if err := fn(records); err != nil {
+		return err
+	}
+	if err := pruneExpiredPrevious(records, time.Now()); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(s.Path, append(data, '\n'))
+}
+
+// lock takes <path>.lock with O_EXCL, retrying until LockTimeout. The lock file records the
+// holder's PID so a stale lock left by a killed process can be identified and removed.
+func (s *UserStore) lock() (func(), error) {
+	lockPath := s.Path + ".lock"
+	if err := os.MkdirAll(filepath.Dir(lockPath), 0o755); err != nil {
+		return nil, err
+	}
+	deadline := time.Now().Add(s.LockTimeout)
+	for {
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+		if err == nil {
+			fmt.Fprintf(f, "%d\n", os.Getpid())
+			_ = f.Close()
+			return func() { _ = os.Remove(lockPath) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, err
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("%s is locked by another process (remove %s if it is stale)", s.Path, lockPath)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// beforeRename lets tests stop a write after the temp file is complete but before it replaces
+// the users file.
+var beforeRename = func() {}
+
+func writeFileAtomic(path string, data []byte) error {
+	mode := os.FileMode(0o644)
+	if st, err := os.Stat(path); err == nil {
+		mode = st.Mode()
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp.*")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+	defer func() {
+		_ = os.Remove(tmpName)
+	}()
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpName, mode); err != nil {
+		return err
+	}
+	beforeRename()
+	if err := os.Rename(tmpName, path); err != nil {
+		return fmt.Errorf("rename temp file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+package users
+
+import (
+	"bufio"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPBKDF2SHA256Vector(t *testing.T) {
+	// RFC 7914 section 11.
+	got := hex.EncodeToString(pbkdf2SHA256([]byte("passwd"), []byte("salt"), 1, 64))
+	want := "55ac046e56e3089fec1691c22544b605f94185216dde0465e68b9d57c20dacbc49ca9cccf179b645991664b39d77ef317c71b845b1e30bd509112041d3a19783"
+	if got != want {
+		t.Fatalf("pbkdf2 = %s", got)
+	}
+}
+
+func TestVerifyPasswordPasslibHash(t *testing.T) {
+	// Salt 00..0f, 1000 rounds, in passlib's pbkdf2_sha256 format.
+	hash := "$pbkdf2-sha256$1000$AAECAwQFBgcICQoLDA0ODw$l0lDjSrSwvrY.FedN5oD1fOd7zMSrJzjPCNFYXlS/Zs"
+	if !VerifyPassword("admin", hash) || VerifyPassword("wrong", hash) {
+		t.Fatal("passlib hash did not verify as expected")
+	}
+	own, err := HashPassword("s3cret")
+	if err != nil || !strings.HasPrefix(own, "$pbkdf2-sha256$29000$") || !VerifyPassword("s3cret", own) {
+		t.Fatalf("HashPassword round trip failed: %q %v", own, err)
+	}
+}
+
+func TestUserStoreAddRemoveList(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config", "users.json")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	existing := `{"admin": {"username": "admin", "hashed_password": "$pbkdf2-sha256$1$AA$AA", "disabled": false, "must_change_password": true, "theme": "dark"}}`
+	if err := os.WriteFile(path, []byte(existing), 0o640); err != nil {
+		t.Fatal(err)
+	}
+	s := NewUserStore(path)
+
+	if err := s.Add("ops", "pw", []string{"admin"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Add("ops", "pw", nil); !errors.Is(err, ErrUserExists) {
+		t.Fatalf("duplicate add: %v", err)
+	}
+	list, err := s.List()
+	if err != nil || len(list) != 2 || list[0].Username != "admin" || list[1].Username != "ops" {
+		t.Fatalf("List = %+v, %v", list, err)
+	}
+	if !list[0].MustChangePassword || len(list[1].Roles) != 1 || !VerifyPassword("pw", list[1].HashedPassword) {
+		t.Fatalf("unexpected records: %+v", list)
+	}
+
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), `"theme": "dark"`) {
+		t.Fatalf("unknown fields were dropped:\n%s", data)
+	}
+	if st, _ := os.Stat(path); st.Mode().Perm() != 0o640 {
+		t.Fatalf("mode = %v, want 0640", st.Mode().Perm())
+	}
+	if _, err := os.Stat(path + ".lock"); !os.IsNotExist(err) {
+		t.Fatal("lock file left behind")
+	}
+
+	if err := s.Remove("nobody"); !errors.Is(err, ErrUserNotFound) {
+		t.Fatalf("remove unknown: %v", err)
+	}
+	if err := s.Remove("admin"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Remove("ops"); !errors.Is(err, ErrLastUser) {
+		t.Fatalf("remove last: %v", err)
+	}
+}
+
+func TestUserStoreLockTimeout(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "users.json")
+	if err := os.WriteFile(path+".lock", []byte("1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	s := &UserStore{Path: path}
+	if err := s.Add("ops", "pw", nil); err == nil || !strings.Contains(err.Error(), "locked by another process") {
+		t.Fatalf("expected lock error, got %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatal("users.json written without holding the lock")
+	}
+}
+
+func TestSetPasswordKeepsPreviousHash(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "users.json")
+	s := NewUserStore(path)
+	if err := s.Add("ops", "old", nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetPassword("ops", "new", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	list, err := s.List()
+	if err != nil || len(list) != 1 {
+		t.Fatalf("List = %+v, %v", list, err)
+	}
+	u := list[0]
+	if !VerifyPassword("new", u.HashedPassword) || !VerifyPassword("old", u.PreviousPasswordHash) {
+		t.Fatalf("unexpected hashes: %+v", u)
+	}
+	if u.PreviousPasswordExpires == nil || time.Until(*u.PreviousPasswordExpires) <= 0 {
+		t.Fatalf("previous hash should expire in the future: %+v", u.PreviousPasswordExpires)
+	}
+	if err := s.SetPassword("nobody", "x", 0); !errors.Is(err, ErrUserNotFound) {
+		t.Fatalf("unknown user: %v", err)
+	}
+
+	// An expired previous hash is dropped on the next write.
+	records, err := s.load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pruneExpiredPrevious(records, time.Now().Add(2*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(records["ops"]), "previous_password") {
+		t.Fatalf("expired previous hash kept: %s", records["ops"])
+	}
+}
+
+// TestHelperKilledMidWrite is the child process for TestSetPasswordKilledMidWrite: it blocks
+// after the temp file is written but before the rename, and waits to be killed.
+func TestHelperKilledMidWrite(t *testing.T) {
+	path := os.Getenv("USERS_KILL_HELPER_PATH")
+	if path == "" {
+		t.Skip("helper process only")
+	}
+	beforeRename = func() {
+		fmt.Println("ready")
+		select {}
+	}
+	_ = NewUserStore(path).SetPassword("ops", "new", time.Hour)
+}
+
+func TestSetPasswordKilledMidWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "users.json")
+	s := NewUserStore(path)
+	if err := s.Add("ops", "old", nil); err != nil {
+		t.Fatal(err)
+	}
+	before, _ := os.ReadFile(path)
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestHelperKilledMidWrite$")
+	cmd.Env = append(os.Environ(), "USERS_KILL_HELPER_PATH="+path)
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	line, _ := bufio.NewReader(out).ReadString('\n')
+	if strings.TrimSpace(line) != "ready" {
+		_ = cmd.Process.Kill()
+		t.Fatalf("helper did not reach the rename: %q", line)
+	}
+	_ = cmd.Process.Kill()
+	_ = cmd.Wait()
+
+	after, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !json.Valid(after) || string(after) != string(before) {
+		t.Fatalf("users.json changed or corrupted by a killed writer:\n%s", after)
+	}
+	if _, err := os.Stat(path + ".lock"); err != nil {
+		t.Fatalf("expected the killed writer's stale lock to remain: %v", err)
+	}
+}
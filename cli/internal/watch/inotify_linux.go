@@ -0,0 +1,164 @@
+package watch
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+const inotifyMask = unix.IN_CREATE | unix.IN_MODIFY | unix.IN_MOVED_TO
+
+// inotifySource watches the repo root (for the .env file) and every directory under config/.
+// Directories created under config/ later are added as they appear.
+type inotifySource struct {
+	fd      int // not file.Fd(), which would switch the fd back to blocking mode
+	file    *os.File
+	root    string
+	envFile string
+
+	mu   sync.Mutex
+	dirs map[int]string // watch descriptor -> directory, relative to root ("" for root)
+
+	events    chan string
+	errs      chan error
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewEventSource watches envFile and everything under config/ in root. Close it when done.
+func NewEventSource(root, envFile string) (EventSource, error) {
+	fd, err := unix.InotifyInit1(unix.IN_CLOEXEC | unix.IN_NONBLOCK)
+	if err != nil {
+		return nil, fmt.Errorf("inotify: %w", err)
+	}
+	s := &inotifySource{
+		// A non-blocking fd goes through the runtime poller, so Close unblocks Read.
+		fd:      fd,
+		file:    os.NewFile(uintptr(fd), "inotify"),
+		root:    root,
+		envFile: envFile,
+		dirs:    map[int]string{},
+		events:  make(chan string),
+		errs:    make(chan error),
+		done:    make(chan struct{}),
+	}
+	if err := s.add(""); err != nil {
+		s.file.Close()
+		return nil, err
+	}
+	if _, err := s.addTree("config"); err != nil {
+		s.file.Close()
+		return nil, err
+	}
+	go s.read()
+	return s, nil
+}
+
+func (s *inotifySource) Events() <-chan string { return s.events }
+func (s *inotifySource) Errors() <-chan error  { return s.errs }
+
+func (s *inotifySource) Close() error {
+	err := os.ErrClosed
+	s.closeOnce.Do(func() {
+		close(s.done)
+		err = s.file.Close()
+	})
+	return err
+}
+
+// send delivers v on ch unless the source has been closed.
+func send[T any](s *inotifySource, ch chan T, v T) bool {
+	select {
+	case ch <- v:
+		return true
+	case <-s.done:
+		return false
+	}
+}
+
+func (s *inotifySource) add(rel string) error {
+	wd, err := unix.InotifyAddWatch(s.fd, filepath.Join(s.root, rel), inotifyMask)
+	if err != nil {
+		return fmt.Errorf("inotify: watch %s: %w", filepath.Join(s.root, rel), err)
+	}
+	s.mu.Lock()
+	s.dirs[wd] = rel
+	s.mu.Unlock()
+	return nil
+}
+
+// addTree watches rel and the directories below it and returns the files already in them
+// (written before the watch existed); a missing rel is not an error.
+func (s *inotifySource) addTree(rel string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(filepath.Join(s.root, rel), func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		sub, err := filepath.Rel(s.root, p)
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			files = append(files, filepath.ToSlash(sub))
+			return nil
+		}
+		return s.add(sub)
+	})
+	return files, err
+}
+
+func (s *inotifySource) read() {
+	defer close(s.events)
+	buf := make([]byte, 64*(unix.SizeofInotifyEvent+unix.NAME_MAX+1))
+	for {
+		n, err := s.file.Read(buf)
+		if err != nil {
+			if !errors.Is(err, os.ErrClosed) {
+				send(s, s.errs, err)
+			}
+			return
+		}
+		for off := 0; off+unix.SizeofInotifyEvent <= n; {
+			ev := (*unix.InotifyEvent)(unsafe.Pointer(&buf[off]))
+			nameBytes := buf[off+unix.SizeofInotifyEvent : off+unix.SizeofInotifyEvent+int(ev.Len)]
+			off += unix.SizeofInotifyEvent + int(ev.Len)
+
+			s.mu.Lock()
+			dir, ok := s.dirs[int(ev.Wd)]
+			s.mu.Unlock()
+			if !ok || ev.Len == 0 {
+				continue
+			}
+			rel := filepath.Join(dir, string(bytes.TrimRight(nameBytes, "\x00")))
+			if ev.Mask&unix.IN_ISDIR != 0 {
+				if dir == "" && rel != "config" {
+					continue
+				}
+				files, err := s.addTree(rel)
+				if err != nil && !send(s, s.errs, err) {
+					return
+				}
+				for _, f := range files {
+					if !send(s, s.events, f) {
+						return
+					}
+				}
+				continue
+			}
+			if watched(filepath.ToSlash(rel), s.envFile) && !send(s, s.events, filepath.ToSlash(rel)) {
+				return
+			}
+		}
+	}
+}
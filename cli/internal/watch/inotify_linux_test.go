@@ -0,0 +1,51 @@
+package watch
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestInotifySourceReportsWrites(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "config"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	src, err := NewEventSource(root, ".env")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer src.Close()
+
+	// waitFor drains events until want arrives; a write can produce several (IN_CREATE, IN_MODIFY).
+	waitFor := func(want string) {
+		t.Helper()
+		timeout := time.After(2 * time.Second)
+		for {
+			select {
+			case rel := <-src.Events():
+				if rel == want {
+					return
+				}
+				if rel != ".env" && !watched(rel, ".env") {
+					t.Fatalf("unexpected event %q", rel)
+				}
+			case err := <-src.Errors():
+				t.Fatal(err)
+			case <-timeout:
+				t.Fatalf("no event for %s", want)
+			}
+		}
+	}
+
+	os.WriteFile(filepath.Join(root, "README.md"), []byte("x"), 0o644) // not watched
+	os.WriteFile(filepath.Join(root, ".env"), []byte("A=1\n"), 0o644)
+	waitFor(".env")
+
+	// A directory created after the watch started is picked up.
+	os.MkdirAll(filepath.Join(root, "config", "contexts"), 0o755)
+	time.Sleep(50 * time.Millisecond)
+	os.WriteFile(filepath.Join(root, "config", "contexts", "sales.yaml"), []byte("name: sales\n"), 0o644)
+	waitFor("config/contexts/sales.yaml")
+}
@@ -0,0 +1,98 @@
+//go:build !linux
+
+package watch
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// pollInterval is how often the fallback source stats the watched files.
+const pollInterval = time.Second
+
+// pollSource compares modification times and sizes on an interval. It stands in for kqueue
+// and ReadDirectoryChangesW on platforms without the inotify source; the agent itself only
+// runs on Linux hosts.
+type pollSource struct {
+	root, envFile string
+	events        chan string
+	errs          chan error
+	done          chan struct{}
+	closeOnce     sync.Once
+}
+
+type fileStamp struct {
+	mod  time.Time
+	size int64
+}
+
+// NewEventSource watches envFile and everything under config/ in root. Close it when done.
+func NewEventSource(root, envFile string) (EventSource, error) {
+	s := &pollSource{
+		root:    root,
+		envFile: envFile,
+		events:  make(chan string),
+		errs:    make(chan error),
+		done:    make(chan struct{}),
+	}
+	go s.poll(s.scan())
+	return s, nil
+}
+
+func (s *pollSource) Events() <-chan string { return s.events }
+func (s *pollSource) Errors() <-chan error  { return s.errs }
+
+func (s *pollSource) Close() error {
+	s.closeOnce.Do(func() { close(s.done) })
+	return nil
+}
+
+func (s *pollSource) poll(last map[string]fileStamp) {
+	defer close(s.events)
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+		}
+		cur := s.scan()
+		for rel, st := range cur {
+			if prev, ok := last[rel]; ok && prev == st {
+				continue
+			}
+			select {
+			case s.events <- rel:
+			case <-s.done:
+				return
+			}
+		}
+		last = cur
+	}
+}
+
+// scan stats envFile and every file under config/, keyed by slash-separated relative path.
+func (s *pollSource) scan() map[string]fileStamp {
+	stamps := map[string]fileStamp{}
+	if info, err := os.Stat(filepath.Join(s.root, s.envFile)); err == nil {
+		stamps[filepath.ToSlash(s.envFile)] = fileStamp{info.ModTime(), info.Size()}
+	}
+	_ = filepath.WalkDir(filepath.Join(s.root, "config"), func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		if rel, err := filepath.Rel(s.root, p); err == nil {
+			stamps[filepath.ToSlash(rel)] = fileStamp{info.ModTime(), info.Size()}
+		}
+		return nil
+	})
+	return stamps
+}
@@ -0,0 +1,161 @@
+// Package watch re-runs diagnostics when the operator config changes, for agent watch-config.
+package watch
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/check"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/environment"
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/maputil"
+)
+
+const (
+	// DefaultDebounce is how long the config must stay quiet after a change before a run.
+	DefaultDebounce = 300 * time.Millisecond
+	// MaxDebounce caps the debounce as it doubles after consecutive failing runs.
+	MaxDebounce = 30 * time.Second
+)
+
+// EventSource delivers the paths (relative to the repo root, slash-separated) of files that
+// were written or created. The inotify source is used on Linux; see NewEventSource.
+type EventSource interface {
+	Events() <-chan string
+	Errors() <-chan error
+	Close() error
+}
+
+// WatchConfig watches config/ and the .env of runner.Env under root and, after each burst of
+// writes, runs the checks and prints what changed since the previous run. From the second
+// failing report in a row the debounce doubles for the next run (up to MaxDebounce) so
+// repeated bad saves do not thrash; a clean run resets it. It returns nil on SIGINT or SIGTERM.
+func WatchConfig(root string, runner *check.Runner, out io.Writer) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	src, err := NewEventSource(root, environment.Path(".env", runner.Env))
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	w := &configWatcher{
+		src: src,
+		run: func(ctx context.Context) (*check.Report, error) {
+			return runner.RunWithTimeout(ctx, check.DefaultTimeout)
+		},
+		out:         out,
+		debounce:    DefaultDebounce,
+		maxDebounce: MaxDebounce,
+	}
+	return w.loop(ctx)
+}
+
+type configWatcher struct {
+	src                   EventSource
+	run                   func(ctx context.Context) (*check.Report, error)
+	out                   io.Writer
+	debounce, maxDebounce time.Duration
+	failStreak            int // consecutive runs with failures
+}
+
+func (w *configWatcher) loop(ctx context.Context) error {
+	fmt.Fprintln(w.out, "Watching config/ and .env for changes (Ctrl-C to stop)")
+	prev, ok := w.check(ctx, nil, nil)
+	if !ok {
+		return nil
+	}
+
+	delay := w.nextDelay(w.debounce, prev)
+	pending := map[string]bool{}
+	var timer <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err, open := <-w.src.Errors():
+			if !open {
+				return nil
+			}
+			fmt.Fprintf(w.out, "watch error: %v\n", err)
+		case rel, open := <-w.src.Events():
+			if !open {
+				return nil
+			}
+			pending[rel] = true
+			timer = time.After(delay)
+		case <-timer:
+			timer = nil
+			changed := maputil.SortedKeys(pending)
+			pending = map[string]bool{}
+
+			rep, ok := w.check(ctx, prev, changed)
+			if !ok {
+				return nil
+			}
+			delay = w.nextDelay(delay, rep)
+			prev = rep
+		}
+	}
+}
+
+// check runs the checks once and prints the full report (first run) or the status changes
+// since prev. It reports false when ctx was cancelled mid-run.
+func (w *configWatcher) check(ctx context.Context, prev *check.Report, changed []string) (*check.Report, bool) {
+	rep, err := w.run(ctx)
+	if ctx.Err() != nil {
+		return nil, false
+	}
+	if rep == nil {
+		msg := "unknown error"
+		if err != nil {
+			msg = err.Error()
+		}
+		rep = &check.Report{Timestamp: time.Now(), Items: []check.Item{{
+			Name:    "agent check",
+			Status:  check.StatusFail,
+			Message: "failed to generate diagnostics report",
+			Details: msg,
+		}}}
+	}
+	if prev == nil {
+		rep.OutputText(w.out, check.OutputOptions{})
+		return rep, true
+	}
+	fmt.Fprintf(w.out, "%s changed: %s\n", rep.Timestamp.Format("15:04:05"), strings.Join(changed, ", "))
+	rep.CompareWith(prev)
+	rep.OnlyChanges = true
+	rep.OutputText(w.out, check.OutputOptions{})
+	return rep, true
+}
+
+// nextDelay doubles the debounce from the second failing run in a row, up to maxDebounce, and
+// resets it after a run without failures. A single bad save keeps the normal debounce.
+func (w *configWatcher) nextDelay(delay time.Duration, rep *check.Report) time.Duration {
+	if rep.FailCount == 0 {
+		w.failStreak = 0
+		return w.debounce
+	}
+	w.failStreak++
+	if w.failStreak < 2 {
+		return w.debounce
+	}
+	delay *= 2
+	if delay > w.maxDebounce {
+		delay = w.maxDebounce
+	}
+	return delay
+}
+
+// watched reports whether rel (slash-separated, relative to the repo root) is a file whose
+// changes trigger a run: anything under config/, or envFile.
+func watched(rel, envFile string) bool {
+	return rel == filepath.ToSlash(envFile) || strings.HasPrefix(rel, "config/")
+}
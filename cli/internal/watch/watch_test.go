@@ -0,0 +1,137 @@
+package watch
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hkjarral/asterisk-ai-voice-agent/cli/internal/check"
+)
+
+type fakeSource struct {
+	events chan string
+	errs   chan error
+}
+
+func (f *fakeSource) Events() <-chan string { return f.events }
+func (f *fakeSource) Errors() <-chan error  { return f.errs }
+func (f *fakeSource) Close() error          { return nil }
+
+// syncBuffer lets the test read output while the watcher goroutine writes it.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestWatcherDebouncesBursts(t *testing.T) {
+	src := &fakeSource{events: make(chan string), errs: make(chan error)}
+	runs := make(chan struct{}, 10)
+	statuses := []check.Status{check.StatusPass, check.StatusFail}
+	n := 0
+	out := &syncBuffer{}
+	w := &configWatcher{
+		src: src,
+		run: func(ctx context.Context) (*check.Report, error) {
+			st := statuses[n%len(statuses)]
+			n++
+			runs <- struct{}{}
+			return &check.Report{Timestamp: time.Now(), Items: []check.Item{{Name: "Config", Status: st, Message: string(st)}}}, nil
+		},
+		out:         out,
+		debounce:    20 * time.Millisecond,
+		maxDebounce: time.Second,
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- w.loop(ctx) }()
+
+	<-runs // initial run
+	for _, rel := range []string{"config/ai-agent.local.yaml", ".env", "config/ai-agent.local.yaml"} {
+		src.events <- rel
+	}
+	select {
+	case <-runs:
+	case <-time.After(2 * time.Second):
+		t.Fatal("no run after the burst")
+	}
+	select {
+	case <-runs:
+		t.Fatal("burst of writes triggered more than one run")
+	case <-time.After(100 * time.Millisecond):
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("loop returned %v", err)
+	}
+
+	got := out.String()
+	if !strings.Contains(got, "changed: .env, config/ai-agent.local.yaml") {
+		t.Errorf("output does not name the changed files:\n%s", got)
+	}
+	if !strings.Contains(got, "NEW:") || !strings.Contains(got, "Config") {
+		t.Errorf("output does not show the new failure:\n%s", got)
+	}
+}
+
+func TestNextDelayBacksOffOnFailures(t *testing.T) {
+	w := &configWatcher{debounce: DefaultDebounce, maxDebounce: MaxDebounce}
+	failing := &check.Report{FailCount: 1}
+	passing := &check.Report{}
+
+	delay := DefaultDebounce
+	want := []time.Duration{DefaultDebounce, 600 * time.Millisecond, 1200 * time.Millisecond, 2400 * time.Millisecond}
+	for i, w2 := range want {
+		delay = w.nextDelay(delay, failing)
+		if delay != w2 {
+			t.Fatalf("failure %d: delay %v, want %v", i+1, delay, w2)
+		}
+	}
+	for i := 0; i < 10; i++ {
+		delay = w.nextDelay(delay, failing)
+	}
+	if delay != MaxDebounce {
+		t.Errorf("delay %v, want the %v cap", delay, MaxDebounce)
+	}
+	if delay = w.nextDelay(delay, passing); delay != DefaultDebounce {
+		t.Errorf("delay after a clean run %v, want %v", delay, DefaultDebounce)
+	}
+	// The streak starts over: one failure after a clean run does not back off.
+	if delay = w.nextDelay(delay, failing); delay != DefaultDebounce {
+		t.Errorf("delay after a single failure %v, want %v", delay, DefaultDebounce)
+	}
+}
+
+func TestWatched(t *testing.T) {
+	cases := map[string]bool{
+		".env":                        true,
+		"config/ai-agent.local.yaml":  true,
+		"config/contexts/sales.yaml":  true,
+		".env.production":             false,
+		"docker-compose.yml":          false,
+		".agent/last-report.json":     false,
+		"configuration/ai-agent.yaml": false,
+	}
+	for rel, want := range cases {
+		if got := watched(rel, ".env"); got != want {
+			t.Errorf("watched(%q) = %v, want %v", rel, got, want)
+		}
+	}
+	if !watched(".env.production", ".env.production") {
+		t.Error(".env.production not watched for --env production")
+	}
+}
@@ -12412,11 +12412,8 @@ class Engine:
     async def _reload_handler(self, request):
         """Hot-reload configuration without restarting the engine.
         
-        Reloads ai-agent.yaml and reinitializes providers with new settings.
-        Active calls continue uninterrupted - changes apply to new calls only.
-        
         POST /reload
-        Returns JSON with reload status and what changed.
+        Returns JSON with reload status and what changed (see _reload_config).
         
         SECURITY: Requires localhost or HEALTH_API_TOKEN.
         """
@@ -12426,7 +12423,16 @@ class Engine:
                 {"success": False, "error": "Forbidden: requires localhost or valid HEALTH_API_TOKEN"},
                 status=403
             )
+        payload, status = await self._reload_config()
+        return web.json_response(payload, status=status)
+
+    async def _reload_config(self) -> Tuple[Dict[str, Any], int]:
+        """Reload ai-agent.yaml and reinitialize providers with new settings.
         
+        Active calls continue uninterrupted - changes apply to new calls only.
+        Used by POST /reload and by SIGHUP (agent config watch-reload).
+        Returns (payload, HTTP status).
+        """
         try:
             logger.info("🔄 Configuration reload requested")
             changes = []
@@ -12440,11 +12446,11 @@ class Engine:
             except Exception as e:
                 logger.debug("Failed to load config on reload", error=str(e), exc_info=True)
                 errors.append("Failed to load config (see server logs)")
-                return web.json_response({
+                return {
                     "success": False,
                     "message": "Failed to reload configuration",
                     "errors": errors
-                }, status=500)
+                }, 500
             
             # Step 2: Compare and update provider configurations
             old_providers = set(self.providers.keys()) if self.providers else set()
@@ -12552,21 +12558,21 @@ class Engine:
             
             logger.info("✅ Configuration reload completed", changes=changes, errors=errors)
             
-            return web.json_response({
+            return {
                 "success": len(errors) == 0,
                 "message": "Configuration reloaded" if not errors else "Reload completed with errors",
                 "changes": changes,
                 "errors": errors,
                 "note": "Changes apply to new calls. Active calls use previous config."
-            })
+            }, 200
             
         except Exception as exc:
             logger.error("Configuration reload failed", error=str(exc), exc_info=True)
-            return web.json_response({
+            return {
                 "success": False,
                 "message": f"Reload failed: {str(exc)}",
                 "errors": [str(exc)]
-            }, status=500)
+            }, 500
 
 
 async def main():
@@ -12599,6 +12605,20 @@ async def main():
     loop = asyncio.get_event_loop()
     for sig in (signal.SIGINT, signal.SIGTERM):
         loop.add_signal_handler(sig, shutdown_event.set)
+    # SIGHUP reloads the configuration like POST /reload (agent config watch-reload);
+    # without a handler Python would exit on it. The task is referenced until it finishes
+    # (the loop only keeps a weak reference), and a SIGHUP during a reload is dropped.
+    reload_tasks: Set[asyncio.Task] = set()
+
+    def _on_sighup() -> None:
+        if reload_tasks:
+            logger.info("SIGHUP ignored: a configuration reload is already running")
+            return
+        task = loop.create_task(engine._reload_config())
+        reload_tasks.add(task)
+        task.add_done_callback(reload_tasks.discard)
+
+    loop.add_signal_handler(signal.SIGHUP, _on_sighup)
 
     service_task = loop.create_task(engine.start())
     await shutdown_event.wait()